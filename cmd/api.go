@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/dillonlara115/barracuda/internal/api"
+	"github.com/dillonlara115/barracuda/internal/entitlements"
+	"github.com/dillonlara115/barracuda/internal/store"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -21,6 +23,10 @@ var (
 	apiSupabaseURL        string
 	apiSupabaseServiceKey string
 	apiSupabaseAnonKey    string
+	apiStore              string
+	apiToken              string
+	apiRequireVerify      bool
+	apiEntitlements       string
 )
 
 var apiCmd = &cobra.Command{
@@ -28,7 +34,30 @@ var apiCmd = &cobra.Command{
 	Short: "Start the Cloud Run API server",
 	Long: `Start the REST API server for Cloud Run deployment.
 This server handles crawl ingestion, project management, and provides
-authenticated endpoints for the dashboard.`,
+authenticated endpoints for the dashboard.
+
+By default data is stored in Supabase. To self-host without Supabase, pass
+--store with a SQLite file or PostgreSQL DSN and an --api-token that clients
+send as a bearer token. Search Console and billing endpoints need Supabase.
+
+With Supabase, crawls started from the web only run once the project's
+domain is verified with a DNS TXT record or a meta tag on its home page
+(GET /api/v1/projects/{id}/verification has the instructions). Self-hosted
+servers skip this unless --require-domain-verification is set.
+
+What each subscription tier may do (pages per crawl, projects, scheduled
+crawls, Search Console sync, rendering, exports) comes from built-in
+defaults. --entitlements names a JSON file that overrides them per tier, so
+plans change without a new release:
+
+  {"free": {"max_pages": 250}, "team": {"max_projects": 0}}
+
+A limit of 0 is unlimited. Self-hosted servers without Supabase have no tiers.
+
+Examples:
+  barracuda api
+  barracuda api --store sqlite://barracuda.db --api-token "$TOKEN"
+  barracuda api --store postgres://barracuda@localhost/barracuda?sslmode=disable --api-token "$TOKEN"`,
 	RunE: runAPI,
 }
 
@@ -37,6 +66,11 @@ func init() {
 	apiCmd.Flags().StringVar(&apiSupabaseURL, "supabase-url", "", "Supabase project URL (or set PUBLIC_SUPABASE_URL env var)")
 	apiCmd.Flags().StringVar(&apiSupabaseServiceKey, "supabase-service-key", "", "Supabase service role key (or set SUPABASE_SERVICE_ROLE_KEY env var)")
 	apiCmd.Flags().StringVar(&apiSupabaseAnonKey, "supabase-anon-key", "", "Supabase anon key (or set PUBLIC_SUPABASE_ANON_KEY env var)")
+	apiCmd.Flags().StringVar(&apiStore, "store", "", "Storage backend: supabase, memory, sqlite://<path>, or postgres://<dsn> (or set BARRACUDA_STORE env var; default: supabase)")
+	apiCmd.Flags().StringVar(&apiToken, "api-token", "", "Bearer token clients must send when running without Supabase (or set BARRACUDA_API_TOKEN env var)")
+	apiCmd.Flags().BoolVar(&apiRequireVerify, "require-domain-verification", false, "Only crawl a project's domain from the server once it is verified (always on with Supabase)")
+	apiCmd.Flags().StringVar(&apiEntitlements, "entitlements", "", "JSON file overriding what each subscription tier may do (or set BARRACUDA_ENTITLEMENTS env var)")
+	addPprofFlag(apiCmd)
 
 	rootCmd.AddCommand(apiCmd)
 }
@@ -55,29 +89,67 @@ func runAPI(cmd *cobra.Command, args []string) error {
 	}
 	defer logger.Sync()
 
+	if err := startPprof(); err != nil {
+		return err
+	}
+
 	// Get configuration from flags or environment
 	supabaseURL := apiSupabaseURL
 	if supabaseURL == "" {
 		supabaseURL = os.Getenv("PUBLIC_SUPABASE_URL")
 	}
-	if supabaseURL == "" {
-		return fmt.Errorf("PUBLIC_SUPABASE_URL is required (flag or environment variable)")
-	}
-
 	supabaseServiceKey := apiSupabaseServiceKey
 	if supabaseServiceKey == "" {
 		supabaseServiceKey = os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
 	}
-	if supabaseServiceKey == "" {
-		return fmt.Errorf("SUPABASE_SERVICE_ROLE_KEY is required (flag or environment variable)")
-	}
-
 	supabaseAnonKey := apiSupabaseAnonKey
 	if supabaseAnonKey == "" {
 		supabaseAnonKey = os.Getenv("PUBLIC_SUPABASE_ANON_KEY")
 	}
-	if supabaseAnonKey == "" {
-		return fmt.Errorf("PUBLIC_SUPABASE_ANON_KEY is required (flag or environment variable)")
+	storeDSN := apiStore
+	if storeDSN == "" {
+		storeDSN = os.Getenv("BARRACUDA_STORE")
+	}
+	token := apiToken
+	if token == "" {
+		token = os.Getenv("BARRACUDA_API_TOKEN")
+	}
+
+	// A self-hosted store can still use Supabase for auth, Search Console, and
+	// billing when it is configured; otherwise requests use the API token
+	selfHosted := storeDSN != "" && storeDSN != "supabase"
+	if !selfHosted || supabaseURL != "" {
+		if supabaseURL == "" {
+			return fmt.Errorf("PUBLIC_SUPABASE_URL is required (flag or environment variable)")
+		}
+		if supabaseServiceKey == "" {
+			return fmt.Errorf("SUPABASE_SERVICE_ROLE_KEY is required (flag or environment variable)")
+		}
+		if supabaseAnonKey == "" {
+			return fmt.Errorf("PUBLIC_SUPABASE_ANON_KEY is required (flag or environment variable)")
+		}
+	} else if token == "" {
+		return fmt.Errorf("BARRACUDA_API_TOKEN is required when running without Supabase (flag or environment variable)")
+	}
+
+	entitlementsPath := apiEntitlements
+	if entitlementsPath == "" {
+		entitlementsPath = os.Getenv("BARRACUDA_ENTITLEMENTS")
+	}
+	var tiers entitlements.Table
+	if entitlementsPath != "" {
+		if tiers, err = entitlements.Load(entitlementsPath); err != nil {
+			return err
+		}
+	}
+
+	var dataStore store.Store
+	if selfHosted {
+		dataStore, err = store.Open(storeDSN)
+		if err != nil {
+			return fmt.Errorf("failed to open store: %w", err)
+		}
+		defer dataStore.Close()
 	}
 
 	// Check if PORT is set (Cloud Run sets this)
@@ -90,16 +162,21 @@ func runAPI(cmd *cobra.Command, args []string) error {
 	// Log configuration (without sensitive data)
 	logger.Info("Initializing API server",
 		zap.String("supabase_url", supabaseURL),
+		zap.Bool("self_hosted_store", selfHosted),
 		zap.Bool("has_service_key", supabaseServiceKey != ""),
 		zap.Bool("has_anon_key", supabaseAnonKey != ""))
 
 	// Initialize API server
 	server, err := api.NewServer(api.Config{
-		SupabaseURL:        supabaseURL,
-		SupabaseServiceKey: supabaseServiceKey,
-		SupabaseAnonKey:    supabaseAnonKey,
-		CronSyncSecret:     os.Getenv("GSC_SYNC_SECRET"),
-		Logger:             logger,
+		SupabaseURL:               supabaseURL,
+		SupabaseServiceKey:        supabaseServiceKey,
+		SupabaseAnonKey:           supabaseAnonKey,
+		CronSyncSecret:            os.Getenv("GSC_SYNC_SECRET"),
+		Logger:                    logger,
+		Store:                     dataStore,
+		AuthToken:                 token,
+		RequireDomainVerification: apiRequireVerify || supabaseURL != "",
+		Entitlements:              tiers,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize API server: %w", err)
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// loadFileConfig loads the file given by --config or --profile, falling back
+// to barracuda.yaml in the working directory. It returns nil when none exists.
+func loadFileConfig() (*utils.FileConfig, string, error) {
+	path := configFile
+	if path == "" && profileName != "" {
+		profilePath, err := utils.ProfilePath(profileName)
+		if err != nil {
+			return nil, "", err
+		}
+		path = profilePath
+	}
+	if path == "" {
+		if _, err := os.Stat(utils.DefaultConfigFile); err != nil {
+			return nil, "", nil
+		}
+		path = utils.DefaultConfigFile
+	}
+
+	fc, err := utils.LoadFileConfig(path)
+	if err != nil {
+		return nil, path, err
+	}
+	return fc, path, nil
+}
+
+// firstStartURL returns the first start URL given, or "" when there is none
+func firstStartURL() string {
+	if len(startURLs) == 0 {
+		return ""
+	}
+	return startURLs[0]
+}
+
+// extraStartURLs returns the start URLs given after the first
+func extraStartURLs() []string {
+	if len(startURLs) < 2 {
+		return nil
+	}
+	return startURLs[1:]
+}
+
+// crawlConfigFromFlags builds a Config from the crawl command's flag variables
+func crawlConfigFromFlags() *utils.Config {
+	return &utils.Config{
+		StartURL:        firstStartURL(),
+		ExtraStartURLs:  extraStartURLs(),
+		MaxDepth:        maxDepth,
+		MaxPages:        maxPages,
+		Workers:         workers,
+		Delay:           delay,
+		MaxRPS:          maxRPS,
+		AutoThrottle:    autoThrottle,
+		Timeout:         timeout,
+		UserAgent:       userAgent,
+		RespectRobots:   respectRobots,
+		ParseSitemap:    parseSitemap,
+		SitemapSince:    sitemapSince,
+		ExportFormat:    exportFormat,
+		ExportPath:      exportPath,
+		DomainFilter:    domainFilter,
+		Include:         includeURLs,
+		Exclude:         excludeURLs,
+		ScopePath:       scopePath,
+		RespectNofollow: respectNofollow,
+		SkipImageCheck:  skipImages,
+		SaveHTMLDir:     saveHTMLDir,
+		Render:          renderPages,
+		VisitedLimit:    visitedLimit,
+		VisitedFPRate:   visitedFPRate,
+		LowMemory:       lowMemory,
+		Strategy:        strategy,
+		StreamResults:   streamResults,
+		HostRewrites:    hostRewrites,
+		MaxBodySize:     maxBodySize,
+		Headers:         requestHeaders,
+		Cookies:         requestCookies,
+		BasicAuth:       basicAuth,
+		LoginURL:        loginURL,
+		LoginFields:     loginFields,
+		LoginSuccess:    loginSuccess,
+		ActiveHours:     activeHours,
+		HTTPCacheDir:    httpCacheDir,
+		MobileParity:    mobileParity,
+
+		MaxConnsPerHost:   maxConnsPerHost,
+		DisableHTTP2:      !http2,
+		DisableKeepAlives: !keepAlive,
+	}
+}
+
+// applyFileConfigToFlags fills crawl flag variables from the config file.
+// Precedence is: explicit flags > config file > flag defaults.
+func applyFileConfigToFlags(cmd *cobra.Command, fc *utils.FileConfig) error {
+	if fc == nil {
+		return nil
+	}
+
+	fromFile := crawlConfigFromFlags()
+	if err := fc.Crawl.ApplyTo(fromFile); err != nil {
+		return fmt.Errorf("invalid config file: %w", err)
+	}
+
+	flags := cmd.Flags()
+	if !flags.Changed("url") && len(startURLs) == 0 {
+		startURLs = fromFile.StartURLs()
+	}
+	if !flags.Changed("max-depth") {
+		maxDepth = fromFile.MaxDepth
+	}
+	if !flags.Changed("max-pages") {
+		maxPages = fromFile.MaxPages
+	}
+	if !flags.Changed("workers") {
+		workers = fromFile.Workers
+	}
+	if !flags.Changed("delay") {
+		delay = fromFile.Delay
+	}
+	if !flags.Changed("max-rps") {
+		maxRPS = fromFile.MaxRPS
+	}
+	if !flags.Changed("auto-throttle") {
+		autoThrottle = fromFile.AutoThrottle
+	}
+	if !flags.Changed("timeout") {
+		timeout = fromFile.Timeout
+	}
+	if !flags.Changed("max-conns-per-host") {
+		maxConnsPerHost = fromFile.MaxConnsPerHost
+	}
+	if !flags.Changed("http2") {
+		http2 = !fromFile.DisableHTTP2
+	}
+	if !flags.Changed("keep-alive") {
+		keepAlive = !fromFile.DisableKeepAlives
+	}
+	if !flags.Changed("user-agent") {
+		userAgent = fromFile.UserAgent
+	}
+	if !flags.Changed("respect-robots") {
+		respectRobots = fromFile.RespectRobots
+	}
+	if !flags.Changed("parse-sitemap") {
+		parseSitemap = fromFile.ParseSitemap
+	}
+	if !flags.Changed("sitemap-since") {
+		sitemapSince = fromFile.SitemapSince
+	}
+	if !flags.Changed("domain-filter") {
+		domainFilter = fromFile.DomainFilter
+	}
+	if !flags.Changed("format") {
+		exportFormat = fromFile.ExportFormat
+	}
+	if !flags.Changed("include") {
+		includeURLs = fromFile.Include
+	}
+	if !flags.Changed("exclude") {
+		excludeURLs = fromFile.Exclude
+	}
+	if !flags.Changed("scope-path") {
+		scopePath = fromFile.ScopePath
+	}
+	if !flags.Changed("respect-nofollow") {
+		respectNofollow = fromFile.RespectNofollow
+	}
+	if !flags.Changed("skip-image-check") {
+		skipImages = fromFile.SkipImageCheck
+	}
+	if !flags.Changed("save-html") {
+		saveHTMLDir = fromFile.SaveHTMLDir
+	}
+	if !flags.Changed("render") {
+		renderPages = fromFile.Render
+	}
+	if !flags.Changed("visited-limit") {
+		visitedLimit = fromFile.VisitedLimit
+	}
+	if !flags.Changed("visited-fp-rate") {
+		visitedFPRate = fromFile.VisitedFPRate
+	}
+	if !flags.Changed("low-memory") {
+		lowMemory = fromFile.LowMemory
+	}
+	if !flags.Changed("strategy") {
+		strategy = fromFile.Strategy
+	}
+	if !flags.Changed("stream") {
+		streamResults = fromFile.StreamResults
+	}
+	if !flags.Changed("host-rewrite") {
+		hostRewrites = fromFile.HostRewrites
+	}
+	if !flags.Changed("max-body-size") {
+		maxBodySize = fromFile.MaxBodySize
+	}
+	if !flags.Changed("active-hours") {
+		activeHours = fromFile.ActiveHours
+	}
+	if !flags.Changed("http-cache") {
+		httpCacheDir = fromFile.HTTPCacheDir
+	}
+	if !flags.Changed("mobile-parity") {
+		mobileParity = fromFile.MobileParity
+	}
+
+	return nil
+}
@@ -1,101 +1,276 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
 	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/encrypt"
 	"github.com/dillonlara115/barracuda/internal/exporter"
 	"github.com/dillonlara115/barracuda/internal/graph"
+	"github.com/dillonlara115/barracuda/internal/notify"
+	"github.com/dillonlara115/barracuda/internal/suggest"
 	"github.com/dillonlara115/barracuda/internal/utils"
 	"github.com/dillonlara115/barracuda/pkg/models"
 	"github.com/spf13/cobra"
 )
 
 var (
-	startURL      string
-	maxDepth      int
-	maxPages      int
-	workers       int
-	delay         time.Duration
-	timeout       time.Duration
-	userAgent     string
-	respectRobots bool
-	parseSitemap  bool
-	exportFormat  string
-	exportPath    string
-	domainFilter  string
-	graphExport   string
-	interactive   bool
-	openBrowser   bool
+	startURLs          []string
+	maxDepth           int
+	maxPages           int
+	workers            int
+	delay              time.Duration
+	maxRPS             float64
+	autoThrottle       bool
+	maxConnsPerHost    int
+	http2              bool
+	keepAlive          bool
+	timeout            time.Duration
+	userAgent          string
+	userAgentPreset    string
+	respectRobots      bool
+	parseSitemap       bool
+	sitemapSince       string
+	exportFormat       string
+	exportPath         string
+	domainFilter       string
+	includeURLs        []string
+	excludeURLs        []string
+	scopePath          string
+	respectNofollow    bool
+	dryRun             bool
+	skipImages         bool
+	saveHTMLDir        string
+	renderPages        bool
+	visitedLimit       int
+	visitedFPRate      float64
+	lowMemory          bool
+	strategy           string
+	streamResults      bool
+	hostRewrites       []string
+	maxBodySize        string
+	activeHours        string
+	httpCacheDir       string
+	mobile             bool
+	mobileParity       bool
+	requestHeaders     []string
+	requestCookies     []string
+	basicAuth          string
+	loginURL           string
+	loginFields        []string
+	loginSuccess       string
+	outputDir          string
+	resumeFrom         string
+	checkpointInterval time.Duration
+	readStdin          bool
+	seedsFile          string
+	seedURLs           []string // Start URLs read from --seeds
+	graphExport        string
+	anchorExport       string
+	encryptOutput      bool
+	encryptKey         string
+	interactive        bool
+	openBrowser        bool
+	suggestEdits       bool
+	suggestLimit       int
+	sendNotifications  bool
+	preset             string
 )
 
+// crawlPresets are the check bundles --preset accepts
+var crawlPresets = []string{"prelaunch"}
+
+// presetFlags records the flags a preset changed, for dry-run output
+var presetFlags = make(map[string]bool)
+
 // crawlCmd represents the crawl command
 var crawlCmd = &cobra.Command{
-	Use:   "crawl [URL]",
+	Use:   "crawl [URL...]",
 	Short: "Crawl a website and extract SEO data",
 	Long: `Crawl a website recursively and extract SEO data including titles, meta descriptions,
-headings (H1-H6), canonical tags, and internal/external links. Results are exported to CSV or JSON.`,
-	Args: cobra.MaximumNArgs(1),
+headings (H1-H6), canonical tags, and internal/external links. Results are exported to CSV or JSON.
+
+Several start URLs, such as a marketing site and its docs, are crawled together: they share
+one visited set, domain scope, and output.`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runCrawl,
 }
 
 func init() {
 	rootCmd.AddCommand(crawlCmd)
 
-	// URL flag (optional - can also be provided as positional argument)
-	crawlCmd.Flags().StringVarP(&startURL, "url", "u", "", "Starting URL to crawl")
+	// URL flag (optional - can also be provided as positional arguments)
+	crawlCmd.Flags().StringSliceVarP(&startURLs, "url", "u", nil, "Starting URL to crawl (repeatable or comma-separated; all start URLs share one crawl)")
+	crawlCmd.Flags().BoolVar(&readStdin, "stdin", false, "Read newline-delimited URLs from stdin and crawl only those (list mode)")
+	crawlCmd.Flags().StringVar(&seedsFile, "seeds", "", "Read more start URLs from this file, one per line (# comments allowed); links are followed from all of them")
 
 	// Crawl options
 	crawlCmd.Flags().IntVarP(&maxDepth, "max-depth", "d", 3, "Maximum crawl depth")
 	crawlCmd.Flags().IntVarP(&maxPages, "max-pages", "p", 1000, "Maximum number of pages to crawl")
 	crawlCmd.Flags().IntVarP(&workers, "workers", "w", 10, "Number of concurrent workers")
-	crawlCmd.Flags().DurationVar(&delay, "delay", 0, "Delay between requests (e.g., 100ms)")
+	crawlCmd.Flags().DurationVar(&delay, "delay", 0, "Minimum delay between requests to the same host (e.g., 100ms)")
+	crawlCmd.Flags().Float64Var(&maxRPS, "max-rps", 0, "Maximum requests per second to each host (0: no limit)")
+	crawlCmd.Flags().BoolVar(&autoThrottle, "auto-throttle", true, "Slow down for hosts that answer 429 or 503, honoring Retry-After")
 	crawlCmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "HTTP request timeout")
+	crawlCmd.Flags().IntVar(&maxConnsPerHost, "max-conns-per-host", 0, "Most connections open to one host at a time, shared by all workers (0: no limit)")
+	crawlCmd.Flags().BoolVar(&http2, "http2", true, "Use HTTP/2 with servers that support it; --http2=false speaks HTTP/1.1 only")
+	crawlCmd.Flags().BoolVar(&keepAlive, "keep-alive", true, "Reuse connections between requests; --keep-alive=false opens one per request")
 	crawlCmd.Flags().StringVar(&userAgent, "user-agent", "barracuda/1.0.0", "User agent string")
+	crawlCmd.Flags().StringVar(&userAgentPreset, "user-agent-preset", "", "Crawl as a known user agent: 'googlebot', 'googlebot-smartphone', 'bingbot', 'mobile' (an iPhone browser), or 'default'")
+	crawlCmd.Flags().BoolVar(&mobile, "mobile", false, "Crawl as Googlebot Smartphone, the crawler of mobile-first indexing (same as --user-agent-preset googlebot-smartphone)")
+	crawlCmd.Flags().BoolVar(&mobileParity, "mobile-parity", false, "Also fetch each page as Googlebot Smartphone and flag pages whose mobile version differs or lacks a viewport")
 	crawlCmd.Flags().BoolVar(&respectRobots, "respect-robots", true, "Respect robots.txt")
+	crawlCmd.Flags().BoolVar(&respectNofollow, "respect-nofollow", false, "Don't follow rel=nofollow, sponsored, or ugc links or links on meta robots nofollow pages, like search engines")
 	crawlCmd.Flags().BoolVar(&parseSitemap, "parse-sitemap", false, "Parse sitemap.xml for seed URLs")
+	crawlCmd.Flags().StringVar(&sitemapSince, "sitemap-since", "", "With --parse-sitemap, only seed sitemap URLs whose <lastmod> is on or after this date (YYYY-MM-DD or RFC 3339)")
 	crawlCmd.Flags().StringVar(&domainFilter, "domain-filter", "same", "Domain filter: 'same' or 'all'")
+	crawlCmd.Flags().StringSliceVar(&includeURLs, "include", nil, "Only crawl URLs matching these regular expressions (repeatable)")
+	crawlCmd.Flags().StringSliceVar(&excludeURLs, "exclude", nil, "Skip URLs matching these regular expressions (repeatable)")
+	crawlCmd.Flags().StringVar(&scopePath, "scope-path", "", "Only crawl URLs whose path starts with this prefix, e.g. /docs/ (links outside it are recorded but not followed)")
+	crawlCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the effective crawl plan without crawling")
+	crawlCmd.Flags().BoolVar(&skipImages, "skip-image-check", false, "Skip checking image file sizes during analysis")
+	crawlCmd.Flags().StringVar(&saveHTMLDir, "save-html", "", "Save each fetched page's HTML to this directory, with an index.json manifest")
+	crawlCmd.Flags().StringVar(&httpCacheDir, "http-cache", "", "Keep fetched pages in this directory and revalidate them on later crawls, reusing those the server reports unchanged (304 Not Modified)")
+	crawlCmd.Flags().BoolVar(&renderPages, "render", false, "Render pages in headless Chrome before parsing, for sites built with JavaScript (slower)")
+	crawlCmd.Flags().StringVar(&preset, "preset", "", "Run an extra check bundle: 'prelaunch' (staging leftovers such as noindex, robots.txt Disallow: /, staging hostnames, and placeholder text)")
+	crawlCmd.Flags().IntVar(&visitedLimit, "visited-limit", 0, "Track at most this many visited URLs exactly, then use a bloom filter to bound memory (0: no limit)")
+	crawlCmd.Flags().Float64Var(&visitedFPRate, "visited-fp-rate", crawler.DefaultVisitedFPRate, "Target false-positive rate of the visited bloom filter")
+	crawlCmd.Flags().BoolVar(&lowMemory, "low-memory", false, "Keep visited URLs as 64-bit hashes and fewer queued URLs in memory, for very large crawls")
+	crawlCmd.Flags().StringVar(&strategy, "strategy", utils.StrategyBFS, "Crawl order: 'bfs' (breadth-first), 'dfs' (depth-first), or 'priority' (shallow, homepage-linked, and high sitemap priority URLs first)")
+	crawlCmd.Flags().BoolVar(&streamResults, "stream", false, "Write each page to the export file as it is crawled instead of holding results in memory (csv and jsonl formats)")
+	crawlCmd.Flags().StringVar(&maxBodySize, "max-body-size", "10MB", "Largest response body to read, e.g. 512KB or 10MB (0: no limit); longer bodies are cut off")
+	crawlCmd.Flags().StringVar(&activeHours, "active-hours", "", "Only crawl during this daily window of local time, e.g. 01:00-06:00; the crawl pauses outside it and resumes when it reopens")
+
+	// Authentication, sent only to the start URLs' domains
+	crawlCmd.Flags().StringArrayVar(&hostRewrites, "host-rewrite", nil, "Fetch URLs on one host from another as \"from=to[:port]\", e.g. prod.example.com=staging.internal, keeping the Host header (repeatable)")
+	crawlCmd.Flags().StringArrayVar(&requestHeaders, "header", nil, "Extra request header as \"Name: Value\" (repeatable)")
+	crawlCmd.Flags().StringArrayVar(&requestCookies, "cookie", nil, "Cookie to send as \"name=value\" (repeatable)")
+	crawlCmd.Flags().StringVar(&basicAuth, "basic-auth", "", "HTTP basic auth credentials as \"user:password\"")
+	crawlCmd.Flags().StringVar(&loginURL, "login-url", "", "Sign in through the form on this page before crawling, and crawl with its session")
+	crawlCmd.Flags().StringArrayVar(&loginFields, "login-field", nil, "Login form field to fill in as \"name=value\" (repeatable)")
+	crawlCmd.Flags().StringVar(&loginSuccess, "login-success", "", "CSS selector of an element only shown after a successful login")
 
 	// Export options
-	crawlCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "Export format: 'csv' or 'json'")
-	crawlCmd.Flags().StringVarP(&exportPath, "export", "e", "", "Export file path (default: stdout or results.csv/json)")
+	crawlCmd.Flags().StringVarP(&exportFormat, "format", "f", "csv", "Export format: "+strings.Join(exporter.Formats(), ", "))
+	crawlCmd.Flags().StringVarP(&exportPath, "export", "e", "", "Export file path, or '-' for stdout (default: results.csv/json)")
 	crawlCmd.Flags().StringVar(&graphExport, "graph-export", "", "Export link graph to JSON file")
-	
+	crawlCmd.Flags().StringVar(&anchorExport, "anchor-export", "", "Export the anchor text of the internal links to each page to a CSV file")
+	crawlCmd.Flags().StringVar(&outputDir, "output-dir", "", "Save results, graph, anchor text, summary, issues, log, and metadata to a new crawl directory under this path")
+	crawlCmd.Flags().StringVar(&resumeFrom, "resume", "", "Continue an interrupted crawl: its crawl directory, a directory name or unique prefix under --output-dir (default crawls), \"latest\", or a state.json file")
+	crawlCmd.Flags().BoolVar(&encryptOutput, "encrypt-output", false, "Encrypt the results, graph, anchor text, saved HTML, and the crawl directory's summary, issues, metadata, and logs with age once the crawl ends, using --encrypt-key or the "+encrypt.PassphraseEnv+" passphrase")
+	crawlCmd.Flags().StringVar(&encryptKey, "encrypt-key", "", "With --encrypt-output, encrypt to the age public keys in this file, or to the public key of an age-keygen identity file")
+	crawlCmd.Flags().DurationVar(&checkpointInterval, "checkpoint-interval", crawler.DefaultCheckpointInterval, "How often a crawl saving to a crawl directory checkpoints its state for --resume (0: only when interrupted)")
+
+	// Rewrite suggestions
+	crawlCmd.Flags().BoolVar(&suggestEdits, "suggest", false, "Ask the LLM in the config file's llm section to suggest rewrites for titles and meta descriptions with issues")
+	crawlCmd.Flags().IntVar(&suggestLimit, "suggest-limit", 50, "Suggest rewrites for at most this many pages (0: no limit)")
+
+	// Notifications
+	crawlCmd.Flags().BoolVar(&sendNotifications, "notify", true, "Send the config file's notifications when the crawl finishes, as notifications.on_crawl allows")
+
 	// Interactive mode
 	crawlCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Run in interactive mode with prompts")
-	
+
 	// Browser options
 	crawlCmd.Flags().BoolVarP(&openBrowser, "open", "o", true, "Automatically open web dashboard in browser after crawl")
+	addPprofFlag(crawlCmd)
 }
 
 func runCrawl(cmd *cobra.Command, args []string) error {
+	// Apply config file values for any flags not set explicitly
+	fileConfig, fileConfigPath, err := loadFileConfig()
+	if err != nil {
+		return err
+	}
+	if err := applyFileConfigToFlags(cmd, fileConfig); err != nil {
+		return err
+	}
+	if err := applyPreset(cmd, fileConfig); err != nil {
+		return err
+	}
+
+	var crawlDir string
+	var urlList []string
+
+	// A resumed crawl runs with its saved settings, under any flags given now
+	var resumeState *crawler.CrawlState
+	var statePath string
+	if resumeFrom != "" {
+		if readStdin || interactive || seedsFile != "" {
+			return fmt.Errorf("--resume cannot be combined with --stdin, --seeds, or interactive mode")
+		}
+		resumeState, statePath, err = loadCrawlState(resumeFrom)
+		if err != nil {
+			return err
+		}
+		if err := applyFileConfigToFlags(cmd, &utils.FileConfig{Crawl: resumeState.Config}); err != nil {
+			return err
+		}
+		resumeURLs := (&utils.Config{StartURL: resumeState.Config.URL, ExtraStartURLs: resumeState.Config.ExtraURLs}).StartURLs()
+		if (len(args) > 0 && !slices.Equal(args, resumeURLs)) || (cmd.Flags().Changed("url") && !slices.Equal(startURLs, resumeURLs)) {
+			return fmt.Errorf("--resume continues the crawl of %s; leave out the URL", strings.Join(resumeURLs, ", "))
+		}
+		startURLs = resumeURLs
+		urlList = resumeState.URLList
+		if filepath.Base(statePath) == crawldir.StateFile {
+			if _, err := crawldir.ReadMetadata(filepath.Dir(statePath)); err == nil {
+				crawlDir = filepath.Dir(statePath)
+			}
+		}
+	}
+
 	// Check if we should run in interactive mode
 	// Interactive if: flag is set, OR no URL provided and no flags set
 	shouldRunInteractive := interactive
-	if !shouldRunInteractive && startURL == "" && len(args) == 0 {
+	if !shouldRunInteractive && !readStdin && seedsFile == "" && len(startURLs) == 0 && len(args) == 0 {
 		// Check if any flags were provided
-		hasFlags := maxDepth != 3 || maxPages != 1000 || workers != 10 || exportFormat != "csv" || 
+		hasFlags := maxDepth != 3 || maxPages != 1000 || workers != 10 || exportFormat != "csv" ||
 			exportPath != "" || graphExport != "" || respectRobots != true || parseSitemap != false
 		if !hasFlags {
 			shouldRunInteractive = true
 		}
 	}
-	
-	var crawlDir string
-	
-	if shouldRunInteractive {
+
+	if readStdin && shouldRunInteractive {
+		return fmt.Errorf("--stdin cannot be combined with interactive mode")
+	}
+	if seedsFile != "" && (readStdin || shouldRunInteractive) {
+		return fmt.Errorf("--seeds cannot be combined with --stdin or interactive mode")
+	}
+
+	if readStdin {
+		urlList, err = readURLList(os.Stdin, "stdin")
+		if err != nil {
+			return err
+		}
+		if len(startURLs) == 0 {
+			startURLs = urlList[:1]
+		}
+		if !cmd.Flags().Changed("max-pages") && len(urlList) > maxPages {
+			maxPages = len(urlList)
+		}
+	} else if shouldRunInteractive {
 		// Run interactive prompts
 		config, graphExportPath, dir, shouldOpen, err := utils.PromptInteractive()
 		if err != nil {
 			return fmt.Errorf("interactive setup failed: %w", err)
 		}
-		
+
 		// Use config from prompts
-		startURL = config.StartURL
+		startURLs = config.StartURLs()
 		maxDepth = config.MaxDepth
 		maxPages = config.MaxPages
 		workers = config.Workers
@@ -103,77 +278,369 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 		exportPath = config.ExportPath
 		respectRobots = config.RespectRobots
 		parseSitemap = config.ParseSitemap
+		delay = config.Delay
+		timeout = config.Timeout
+		userAgent = config.UserAgent
+		domainFilter = config.DomainFilter
+		includeURLs = config.Include
+		excludeURLs = config.Exclude
+		skipImages = config.SkipImageCheck
+		renderPages = config.Render
 		graphExport = graphExportPath
 		crawlDir = dir
 		openBrowser = shouldOpen // Use interactive preference
 	} else {
-		// Get URL from positional argument or flag
+		// Get URLs from positional arguments or flag, then the seeds file
 		if len(args) > 0 {
-			startURL = args[0]
+			startURLs = args
+		}
+		if seedsFile != "" {
+			seedURLs, err = readSeedsFile(seedsFile, startURLs)
+			if err != nil {
+				return err
+			}
+			startURLs = append(startURLs, seedURLs...)
+			if !cmd.Flags().Changed("max-pages") && len(startURLs) > maxPages {
+				maxPages = len(startURLs)
+			}
 		}
-		
+
 		// Validate that URL is provided
-		if startURL == "" {
+		if len(startURLs) == 0 {
 			return fmt.Errorf("starting URL is required. Provide it as an argument, use --url flag, or run with --interactive")
 		}
 	}
+	if err := applyUserAgentPreset(cmd); err != nil {
+		return err
+	}
 
 	// Initialize logger
-	if err := utils.InitLogger(debug); err != nil {
-		return fmt.Errorf("failed to initialize logger: %w", err)
+	if err := initLogging(); err != nil {
+		return err
 	}
 	defer utils.Sync()
 
 	// Create config
 	config := &utils.Config{
-		StartURL:      startURL,
-		MaxDepth:      maxDepth,
-		MaxPages:      maxPages,
-		Workers:       workers,
-		Delay:         delay,
-		Timeout:       timeout,
-		UserAgent:     userAgent,
-		RespectRobots: respectRobots,
-		ParseSitemap:  parseSitemap,
-		ExportFormat:  exportFormat,
-		ExportPath:    exportPath,
-		DomainFilter:  domainFilter,
+		StartURL:        firstStartURL(),
+		ExtraStartURLs:  extraStartURLs(),
+		MaxDepth:        maxDepth,
+		MaxPages:        maxPages,
+		Workers:         workers,
+		Delay:           delay,
+		MaxRPS:          maxRPS,
+		AutoThrottle:    autoThrottle,
+		Timeout:         timeout,
+		UserAgent:       userAgent,
+		RespectRobots:   respectRobots,
+		ParseSitemap:    parseSitemap,
+		SitemapSince:    sitemapSince,
+		ExportFormat:    exportFormat,
+		ExportPath:      exportPath,
+		DomainFilter:    domainFilter,
+		Include:         includeURLs,
+		Exclude:         excludeURLs,
+		ScopePath:       scopePath,
+		RespectNofollow: respectNofollow,
+		SkipImageCheck:  skipImages,
+		SaveHTMLDir:     saveHTMLDir,
+		Render:          renderPages,
+		VisitedLimit:    visitedLimit,
+		VisitedFPRate:   visitedFPRate,
+		LowMemory:       lowMemory,
+		Strategy:        strategy,
+		StreamResults:   streamResults,
+		HostRewrites:    hostRewrites,
+		MaxBodySize:     maxBodySize,
+		URLList:         urlList,
+		Headers:         requestHeaders,
+		Cookies:         requestCookies,
+		BasicAuth:       basicAuth,
+		LoginURL:        loginURL,
+		LoginFields:     loginFields,
+		LoginSuccess:    loginSuccess,
+		ActiveHours:     activeHours,
+		HTTPCacheDir:    httpCacheDir,
+		MobileParity:    mobileParity,
+
+		MaxConnsPerHost:   maxConnsPerHost,
+		DisableHTTP2:      !http2,
+		DisableKeepAlives: !keepAlive,
 	}
 
 	// Validate config
 	if err := config.Validate(); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
+	if _, err := exporter.Lookup(config.ExportFormat); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := checkStreaming(config, resumeState); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	if dryRun {
+		return runDryRun(cmd, config, fileConfig, fileConfigPath)
+	}
+
+	// Check the LLM settings before crawling rather than failing at the end
+	var suggester suggest.Provider
+	if suggestEdits {
+		if fileConfig == nil || fileConfig.LLM.Provider == "" {
+			return fmt.Errorf("--suggest needs an llm section in the config file")
+		}
+		suggester, err = suggest.New(fileConfig.LLM)
+		if err != nil {
+			return fmt.Errorf("invalid llm config in %s: %w", fileConfigPath, err)
+		}
+		if config.StreamResults {
+			return fmt.Errorf("--suggest adds rewrites to the results after the crawl, so it can't be combined with --stream")
+		}
+	}
+
+	// Likewise the encryption key
+	var encryptor *encrypt.Encryptor
+	if encryptOutput {
+		if exportPath == exporter.StdoutPath {
+			return fmt.Errorf("--encrypt-output can't encrypt results written to stdout")
+		}
+		// Both are read back in plaintext by a later crawl
+		if resumeFrom != "" {
+			return fmt.Errorf("--encrypt-output can't be combined with --resume: an encrypted crawl keeps no resumable state")
+		}
+		if httpCacheDir != "" {
+			return fmt.Errorf("--encrypt-output can't be combined with --http-cache, whose pages are kept unencrypted for later crawls")
+		}
+		encryptor, err = encrypt.NewEncryptor(encryptKey, os.Getenv(encrypt.PassphraseEnv))
+		if err != nil {
+			return fmt.Errorf("--encrypt-output: %w", err)
+		}
+	} else if encryptKey != "" {
+		return fmt.Errorf("--encrypt-key needs --encrypt-output")
+	}
+
+	// Create a standard crawl directory when requested
+	if crawlDir == "" && outputDir != "" {
+		domain := hostnameOf(config.StartURL)
+		if domain == "" {
+			domain = "unknown"
+		}
+		crawlDir, err = crawldir.Create(outputDir, domain, time.Now())
+		if err != nil {
+			return err
+		}
+		if config.ExportPath == "" {
+			config.ExportPath = filepath.Join(crawlDir, crawldir.ResultsFile(config.ExportFormat))
+		}
+		if graphExport == "" {
+			graphExport = filepath.Join(crawlDir, crawldir.GraphFile)
+		}
+		if anchorExport == "" {
+			anchorExport = filepath.Join(crawlDir, crawldir.AnchorsFile)
+		}
+	}
+
+	// A resumed crawl appends to the file it was streaming results to, and
+	// writes into its own directory again
+	if resumeState != nil && resumeState.Stream != nil && config.ExportPath == "" {
+		config.ExportPath = resumeState.Stream.Path
+	}
+	if resumeState != nil && crawlDir != "" {
+		if config.ExportPath == "" {
+			config.ExportPath = filepath.Join(crawlDir, crawldir.ResultsFile(config.ExportFormat))
+		}
+		if graphExport == "" {
+			graphExport = filepath.Join(crawlDir, crawldir.GraphFile)
+		}
+		if anchorExport == "" {
+			anchorExport = filepath.Join(crawlDir, crawldir.AnchorsFile)
+		}
+	}
+	// An encrypted crawl isn't checkpointed, since the state file would hold
+	// its pages in plaintext
+	if statePath == "" && crawlDir != "" && encryptor == nil {
+		statePath = filepath.Join(crawlDir, crawldir.StateFile)
+	}
+
+	// Record metadata and logs in the crawl directory. The logs are closed
+	// early when they are encrypted.
+	var meta *crawldir.Metadata
+	closeLog, closeEvents := func() {}, func() {}
+	if crawlDir != "" {
+		stopLog, err := utils.LogToFile(filepath.Join(crawlDir, crawldir.LogFile))
+		if err != nil {
+			return err
+		}
+		closeLog = sync.OnceFunc(stopLog)
+		defer closeLog()
+
+		meta = newCrawlMetadata(config)
+		if resumeState != nil {
+			if previous, err := crawldir.ReadMetadata(crawlDir); err == nil {
+				meta.StartedAt = previous.StartedAt
+			}
+		}
+		if err := crawldir.WriteMetadata(crawlDir, meta); err != nil {
+			return err
+		}
+	}
 
 	// Set default export path if not provided
 	if config.ExportPath == "" {
-		ext := "csv"
-		if config.ExportFormat == "json" {
-			ext = "json"
-		}
-		config.ExportPath = fmt.Sprintf("results.%s", ext)
+		config.ExportPath = crawldir.ResultsFile(config.ExportFormat)
+	}
+
+	// Keep stdout clean for piping when results are written there
+	out := io.Writer(os.Stdout)
+	if config.ExportPath == exporter.StdoutPath {
+		out = os.Stderr
+		openBrowser = false
+	}
+
+	// --quiet prints nothing but errors; --summary-only prints just the analysis.
+	// Neither opens the dashboard, since both are meant for unattended runs.
+	if quiet {
+		out = io.Discard
+	}
+	if quiet || summaryOnly {
+		openBrowser = false
+	}
+	status := out
+	if summaryOnly {
+		status = io.Discard
+	}
+
+	if err := startPprof(); err != nil {
+		return err
 	}
 
 	utils.Info("Starting crawl", utils.NewField("url", config.StartURL))
 
 	// Create crawler manager
 	manager := crawler.NewManager(config)
+	if statePath != "" {
+		manager.SetStateFile(statePath, checkpointInterval)
+	}
+	if resumeState != nil {
+		manager.ResumeFrom(resumeState)
+		fmt.Fprintf(status, "↩️  Resuming crawl of %s: %d pages crawled, %d URLs queued\n",
+			config.StartURL, resumeState.PageCount(), len(resumeState.Pending))
+	}
+
+	// Write pages to the export file as they are crawled, when streaming
+	var stream *exporter.Stream
+	if config.StreamResults {
+		stream, err = exporter.OpenStream(config.ExportFormat, config.ExportPath, resumeState != nil && resumeState.Stream != nil)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+		manager.SetResultSink(stream)
+	}
+
+	// Record why each URL was crawled or skipped in the crawl directory
+	if crawlDir != "" {
+		events, err := crawldir.OpenEventLog(crawlDir)
+		if err != nil {
+			return err
+		}
+		closeEvents = sync.OnceFunc(func() {
+			if err := events.Close(); err != nil {
+				utils.Warn("Failed to save crawl events", utils.NewField("error", err.Error()))
+			}
+		})
+		defer closeEvents()
+		manager.OnEvent(events.Write)
+	}
+
+	// Logs are going to a file, so show a progress line on the terminal instead
+	progress := newProgressPrinter(os.Stderr, (logFile != "" || crawlDir != "") && !quiet && !summaryOnly)
+
+	// Analyze pages as they arrive so the summary is ready when the crawl ends
+	analysis := newAnalysis(config)
+	manager.SetAnalyzer(analysis)
+	manager.OnPageCrawled(progress.Update)
+	manager.OnCrawlComplete(progress.Done)
+	manager.OnPause(func(resumeAt time.Time) {
+		progress.Pause(status, resumeAt)
+	})
+	var hosts crawldir.HostTally
+	manager.OnPageCrawled(func(page *models.PageResult, _ int) { hosts.Add(page) })
 
 	// Start crawling
+	report := &notify.CrawlReport{
+		URL:       config.StartURL,
+		CrawlDir:  crawlDir,
+		Status:    "succeeded",
+		StartedAt: time.Now(),
+	}
 	results, err := manager.Crawl()
 	if err != nil {
+		if meta != nil {
+			finishCrawlMetadata(crawlDir, meta, nil, 0, nil, err)
+		}
+		if encryptor != nil {
+			closeEvents()
+			closeLog()
+			if err := encryptCrawlOutput(status, encryptor, crawlDir, config); err != nil {
+				utils.Warn("Failed to encrypt crawl output", utils.NewField("error", err.Error()))
+			}
+		}
+		report.Status = "failed"
+		report.Error = err.Error()
+		report.CompletedAt = time.Now()
+		notifyCrawl(fileConfig, report)
 		return fmt.Errorf("crawl failed: %w", err)
 	}
+	pageCount := manager.PageCount()
 
-	utils.Info("Crawl completed", utils.NewField("pages_crawled", len(results)))
+	utils.Info("Crawl completed", utils.NewField("pages_crawled", pageCount))
 
-	// Analyze results and print summary (including image size checking)
-	summary := analyzer.AnalyzeWithImages(results, config.Timeout)
-	analyzer.PrintSummary(summary)
+	// Print the summary (including image size checking)
+	summary := analysis.Summary()
+	skipped := manager.SkipCounts()
+	summary.Skipped = &skipped
+	siteFiles := fetchSiteFiles(manager, "/robots.txt", "/llms.txt")
+	summary.AIVisibility = analyzer.AuditAIVisibility(config.StartURL, siteFiles["/robots.txt"], siteFiles["/llms.txt"])
+	summary.AddIssues(probeNotFound(manager))
+	if preset == "prelaunch" {
+		// Streamed results are read back for the site-wide checks
+		pages := results
+		if stream != nil {
+			if pages, err = stream.ReadAll(); err != nil {
+				return fmt.Errorf("failed to read back streamed results: %w", err)
+			}
+		}
+		summary.AddIssues(analyzer.PrelaunchIssues(config.StartURL, pages, siteFiles["/robots.txt"], sitemapURLs(config)))
+	}
+	analyzer.FprintSummary(out, summary)
+
+	if suggester != nil {
+		fmt.Fprintf(status, "💡 Suggesting title and meta description rewrites via %s...\n", suggester.Name())
+		suggested, failed := suggest.Enrich(cmd.Context(), suggester, results, summary.Issues, suggestLimit)
+		fmt.Fprintf(status, "✓ Suggested rewrites for %d pages\n", suggested)
+		if failed > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  %d suggestion requests failed; see the log for details\n", failed)
+		}
+	}
+
+	// Save summary, issues, and final metadata alongside the results
+	if crawlDir != "" {
+		if err := saveCrawlArtifacts(crawlDir, summary); err != nil {
+			return err
+		}
+		if manager.Interrupted() {
+			meta.Status = "interrupted"
+		}
+		meta.RobotsTxt = manager.RobotsTxtFile()
+		finishCrawlMetadata(crawlDir, meta, summary, pageCount, hosts.Stats(), nil)
+	}
 
-	// Export results
-	if err := exportResults(results, config); err != nil {
-		return fmt.Errorf("export failed: %w", err)
+	// Export results, unless they were streamed as they came
+	if stream == nil {
+		if err := exportResults(results, summary, config); err != nil {
+			return fmt.Errorf("export failed: %w", err)
+		}
 	}
 
 	// Export link graph if requested
@@ -181,16 +648,58 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 		if err := exportLinkGraph(manager.GetLinkGraph(), graphExport); err != nil {
 			return fmt.Errorf("graph export failed: %w", err)
 		}
-		fmt.Fprintf(os.Stdout, "✓ Link graph exported to %s\n", graphExport)
+		fmt.Fprintf(status, "✓ Link graph exported to %s\n", graphExport)
+	}
+
+	// Export anchor text report if requested
+	if anchorExport != "" {
+		if err := exporter.ExportAnchors(analysis.AnchorTexts(), anchorExport); err != nil {
+			return fmt.Errorf("anchor text export failed: %w", err)
+		}
+		fmt.Fprintf(status, "✓ Anchor text exported to %s\n", anchorExport)
+	}
+
+	if encryptor != nil {
+		// Nothing more is logged to the crawl directory, so its logs can be
+		// encrypted too
+		closeEvents()
+		closeLog()
+		if err := encryptCrawlOutput(status, encryptor, crawlDir, config); err != nil {
+			return fmt.Errorf("encryption failed: %w", err)
+		}
+		// The dashboard can't read encrypted results
+		openBrowser = false
+	}
+
+	fmt.Fprintf(status, "\n✓ Crawled %d pages\n", pageCount)
+	if hits := manager.CacheHits(); hits > 0 {
+		fmt.Fprintf(status, "♻️  %d pages unchanged since the last crawl, reused from %s\n", hits, config.HTTPCacheDir)
+	}
+	if stats := manager.VisitedStats(); stats.BloomInUse {
+		fmt.Fprintf(status, "⚠️  Visited set reached its limit of %d URLs and switched to a bloom filter: %d URLs skipped by the filter, about %.1f of them possibly false positives\n",
+			stats.Exact, stats.BloomSkips, stats.EstimatedFalsePositives)
+	}
+	if config.ExportPath != exporter.StdoutPath {
+		fmt.Fprintf(status, "✓ Results exported to %s\n", config.ExportPath)
 	}
 
-	fmt.Fprintf(os.Stdout, "\n✓ Crawled %d pages\n", len(results))
-	fmt.Fprintf(os.Stdout, "✓ Results exported to %s\n", config.ExportPath)
-	
 	if crawlDir != "" {
-		fmt.Fprintf(os.Stdout, "📁 All files saved to: %s\n", crawlDir)
+		fmt.Fprintf(status, "📁 All files saved to: %s\n", crawlDir)
+	}
+	if manager.Interrupted() && statePath != "" {
+		resumeRef := crawlDir
+		if resumeRef == "" {
+			resumeRef = statePath
+		}
+		fmt.Fprintf(status, "⏸️  Crawl interrupted; continue it with: barracuda crawl --resume %s\n", resumeRef)
 	}
 
+	report.CompletedAt = time.Now()
+	report.TotalPages = pageCount
+	report.TotalIssues = summary.TotalIssues
+	report.BySeverity = summary.GetIssueCountBySeverity()
+	notifyCrawl(fileConfig, report)
+
 	// Optionally open browser with dashboard
 	if openBrowser {
 		fmt.Fprintf(os.Stdout, "\n")
@@ -207,6 +716,45 @@ func runCrawl(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// encryptCrawlOutput encrypts the files a crawl wrote: its export, link
+// graph, anchor text report, saved HTML, and the summary, issues, metadata,
+// and logs in its crawl directory, which must be closed first. Files a
+// failed crawl didn't get to write are skipped. The export, graph, and
+// anchor text paths are updated to the encrypted files.
+func encryptCrawlOutput(status io.Writer, encryptor *encrypt.Encryptor, crawlDir string, config *utils.Config) error {
+	files := []*string{&config.ExportPath, &graphExport, &anchorExport}
+	if crawlDir != "" {
+		for _, name := range []string{crawldir.SummaryFile, crawldir.IssuesFile, crawldir.MetadataFile, crawldir.LogFile, crawldir.EventsFile} {
+			path := filepath.Join(crawlDir, name)
+			files = append(files, &path)
+		}
+	}
+	for _, path := range files {
+		if *path == "" {
+			continue
+		}
+		// A failed crawl exports nothing
+		if _, err := os.Stat(*path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		encrypted, err := encryptor.File(*path)
+		if err != nil {
+			return err
+		}
+		*path = encrypted
+		fmt.Fprintf(status, "🔒 Encrypted %s\n", encrypted)
+	}
+
+	if _, err := os.Stat(config.SaveHTMLDir); config.SaveHTMLDir != "" && err == nil {
+		encrypted, err := encryptor.Dir(config.SaveHTMLDir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(status, "🔒 Encrypted saved HTML to %s\n", encrypted)
+	}
+	return nil
+}
+
 func exportLinkGraph(graph *graph.Graph, filePath string) error {
 	file, err := os.Create(filePath)
 	if err != nil {
@@ -217,7 +765,7 @@ func exportLinkGraph(graph *graph.Graph, filePath string) error {
 	edges := graph.GetAllEdges()
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
-	
+
 	if err := encoder.Encode(edges); err != nil {
 		return fmt.Errorf("failed to encode graph JSON: %w", err)
 	}
@@ -225,14 +773,216 @@ func exportLinkGraph(graph *graph.Graph, filePath string) error {
 	return nil
 }
 
-func exportResults(results []*models.PageResult, config *utils.Config) error {
-	switch config.ExportFormat {
-	case "csv":
-		return exporter.ExportCSV(results, config.ExportPath)
-	case "json":
-		return exporter.ExportJSON(results, config.ExportPath, true)
+// readURLList reads newline-delimited URLs, skipping blank lines and # comments.
+// source names where they come from in error messages.
+func readURLList(r io.Reader, source string) ([]string, error) {
+	var urls []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URLs from %s: %w", source, err)
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no URLs found in %s", source)
+	}
+	return urls, nil
+}
+
+// notifyCrawl sends the crawl report to the notification channels in the
+// config file, unless --notify=false or notifications.on_crawl says not to
+func notifyCrawl(fc *utils.FileConfig, report *notify.CrawlReport) {
+	if !sendNotifications || fc == nil || !fc.Notifications.NotifyCrawl(report.Status == "failed") {
+		return
+	}
+	if err := notify.Send(fc.Notifications, report); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+}
+
+// readSeedsFile reads the start URLs listed in path, leaving out those
+// already in startURLs and repeats
+func readSeedsFile(path string, startURLs []string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open seeds file: %w", err)
+	}
+	defer file.Close()
+
+	urls, err := readURLList(file, path)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(startURLs)+len(urls))
+	for _, startURL := range startURLs {
+		seen[startURL] = true
+	}
+	var seeds []string
+	for _, u := range urls {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%s: %w: %q", path, utils.ErrInvalidURL, u)
+		}
+		if !seen[u] {
+			seen[u] = true
+			seeds = append(seeds, u)
+		}
+	}
+	return seeds, nil
+}
+
+// fetchSiteFiles fetches files such as robots.txt from the start site, keyed
+// by path. Files that can't be fetched are nil, as if missing.
+func fetchSiteFiles(manager *crawler.Manager, paths ...string) map[string][]byte {
+	files := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		content, err := manager.SiteFile(path)
+		if err != nil {
+			utils.Warn("Could not fetch site file", utils.NewField("path", path), utils.NewField("error", err.Error()))
+		}
+		files[path] = content
+	}
+	return files
+}
+
+// probeNotFound requests a URL that doesn't exist on the site and returns the
+// issues with how it was answered. Failures are logged rather than returned,
+// like those of fetchSiteFiles.
+func probeNotFound(manager *crawler.Manager) []analyzer.Issue {
+	page, err := manager.ProbeNotFound()
+	if err != nil {
+		utils.Warn("Could not check the 404 page", utils.NewField("error", err.Error()))
+		return nil
+	}
+	return analyzer.NotFoundIssues(page)
+}
+
+// applyPreset validates --preset and adjusts flags for it. prelaunch ignores
+// robots.txt unless it was set explicitly, since a staging robots.txt usually
+// blocks the whole site.
+func applyPreset(cmd *cobra.Command, fc *utils.FileConfig) error {
+	switch preset {
+	case "":
+		return nil
+	case "prelaunch":
+		if !cmd.Flags().Changed("respect-robots") && (fc == nil || fc.Crawl.RespectRobots == nil) {
+			respectRobots = false
+			presetFlags["respect-robots"] = true
+		}
+		return nil
 	default:
-		return fmt.Errorf("unsupported export format: %s", config.ExportFormat)
+		return fmt.Errorf("unknown --preset %q (valid: %s)", preset, strings.Join(crawlPresets, ", "))
+	}
+}
+
+// applyUserAgentPreset sets the user agent from --user-agent-preset or
+// --mobile, which override a user agent from the config file but not
+// --user-agent
+func applyUserAgentPreset(cmd *cobra.Command) error {
+	if mobile {
+		if cmd.Flags().Changed("user-agent-preset") {
+			return fmt.Errorf("--mobile and --user-agent-preset cannot be combined")
+		}
+		userAgentPreset = utils.MobilePreset
+	}
+	if userAgentPreset == "" {
+		return nil
 	}
+	if cmd.Flags().Changed("user-agent") {
+		return fmt.Errorf("--user-agent cannot be combined with --user-agent-preset or --mobile")
+	}
+	preset, err := utils.PresetUserAgent(userAgentPreset)
+	if err != nil {
+		return err
+	}
+	userAgent = preset
+	return nil
 }
 
+// loadCrawlState reads the saved state of an interrupted crawl from a state
+// file or a crawl directory reference, and returns the state file's path
+func loadCrawlState(ref string) (*crawler.CrawlState, string, error) {
+	path := ref
+	if info, err := os.Stat(ref); err != nil || info.IsDir() {
+		parent := outputDir
+		if parent == "" {
+			parent = crawldir.DefaultParent
+		}
+		dir, err := resolveCrawlDir(parent, ref)
+		if err != nil {
+			return nil, "", err
+		}
+		path = filepath.Join(dir, crawldir.StateFile)
+	}
+	state, err := crawler.LoadCrawlState(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return state, path, nil
+}
+
+// sitemapURLs returns the URLs listed in the start site's sitemap, or nil
+// when it has none
+func sitemapURLs(config *utils.Config) []string {
+	fetcher := crawler.NewFetcher(config.Timeout, config.UserAgent)
+	if header, err := config.RequestHeader(); err == nil && header != nil {
+		fetcher.SetHeaders(header, config.StartURLs()...)
+	}
+	if rewrites, err := config.HostRewriteMap(); err == nil {
+		fetcher.SetHostRewrites(rewrites)
+	}
+	parser := crawler.NewSitemapParser(fetcher)
+	robots := crawler.NewRobotsChecker(fetcher, config.UserAgent, config.RespectRobots)
+	var urls []string
+	for _, sitemapURL := range parser.DiscoverSitemapURLs(config.StartURL, robots) {
+		listed, err := parser.ParseSitemap(sitemapURL)
+		if err != nil {
+			utils.Debug("No sitemap for pre-launch checks", utils.NewField("url", sitemapURL), utils.NewField("error", err.Error()))
+			continue
+		}
+		urls = append(urls, listed...)
+	}
+	return urls
+}
+
+// newAnalysis creates an incremental analyzer for a crawl, checking image
+// sizes unless disabled
+func newAnalysis(config *utils.Config) *analyzer.Incremental {
+	if config.SkipImageCheck {
+		return analyzer.NewIncremental()
+	}
+	return analyzer.NewIncrementalWithImages(config.Timeout)
+}
+
+// checkStreaming rejects --stream with a format that can't be written a page
+// at a time, or with the options that need every result after the crawl. A
+// resumed crawl keeps streaming, or not, as it did before.
+func checkStreaming(config *utils.Config, resumeState *crawler.CrawlState) error {
+	if resumeState != nil && resumeState.PageCount() > 0 && config.StreamResults != (resumeState.Stream != nil) {
+		return fmt.Errorf("--stream can't be changed when resuming a crawl")
+	}
+	if !config.StreamResults {
+		return nil
+	}
+	if !slices.Contains(exporter.StreamFormats(), config.ExportFormat) {
+		return fmt.Errorf("--stream needs a format that can be written a page at a time (%s)", strings.Join(exporter.StreamFormats(), ", "))
+	}
+	if preset == "prelaunch" && config.ExportPath == exporter.StdoutPath {
+		return fmt.Errorf("--preset prelaunch reads streamed results back after the crawl, so --stream needs an export file")
+	}
+	return nil
+}
+
+// exportResults writes the results with the exporter registered for the
+// configured format
+func exportResults(results []*models.PageResult, summary *analyzer.Summary, config *utils.Config) error {
+	e, err := exporter.Lookup(config.ExportFormat)
+	if err != nil {
+		return err
+	}
+	return e.Export(results, summary, config.ExportPath)
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/dillonlara115/barracuda/internal/encrypt"
+)
+
+// TestCrawlEncryptOutput runs the crawl command with --encrypt-output into a
+// crawl directory, and checks that nothing it wrote there is left in
+// plaintext
+func TestCrawlEncryptOutput(t *testing.T) {
+	site := newFixtureSite(t)
+	dir := t.TempDir()
+	crawls := filepath.Join(dir, "crawls")
+
+	// A public key rather than a passphrase, whose key derivation is slow
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "key.txt")
+	if err := os.WriteFile(keyFile, []byte(identity.Recipient().String()+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"crawl", site.URL + "/",
+		"--output-dir", crawls, "--save-html", filepath.Join(crawls, "html"), "--format", "json",
+		"--encrypt-output", "--encrypt-key", keyFile, "--workers", "2", "--skip-image-check", "--open=false", "--notify=false", "--quiet"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	crawlDirs, err := filepath.Glob(filepath.Join(crawls, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var encrypted int
+	err = filepath.WalkDir(crawls, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if strings.HasSuffix(path, encrypt.Ext) {
+			encrypted++
+			return nil
+		}
+		t.Errorf("%s left unencrypted", strings.TrimPrefix(path, dir))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypted == 0 {
+		t.Errorf("no encrypted files in %v", crawlDirs)
+	}
+	if _, err := os.Stat(filepath.Join(crawls, "html"+encrypt.TarExt)); err != nil {
+		t.Errorf("saved HTML wasn't archived: %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/exporter"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// fixturePages are the HTML pages of the fixture site, by path
+var fixturePages = map[string]string{
+	"/": `<p id="intro">Welcome</p> <a href="/about">About</a> <a href="/products">Products</a> <a href="/old">Old page</a>
+		<a href="/missing">Missing page</a> <a href="/private/secret">Private page</a>`,
+	"/about":          `<a href="/">Home</a>`,
+	"/products":       `<a href="/">Home</a>`,
+	"/new":            `<a href="/#intro">Home</a> <a href="/#pricing">Pricing</a>`,
+	"/orphan":         `<p>Only listed in the sitemap</p>`,
+	"/private/secret": `<p>Disallowed by robots.txt</p>`,
+}
+
+// fixtureTitles are the fixture pages' titles; /about and /products are the
+// same page
+var fixtureTitles = map[string]string{
+	"/":               "Fixture Site Home Page for Integration Tests",
+	"/about":          "About the Fixture Site and the People Behind It",
+	"/products":       "About the Fixture Site and the People Behind It",
+	"/new":            "The New Home of the Page That Used to Be Old",
+	"/orphan":         "An Orphan Page Only the Sitemap Links To Here",
+	"/private/secret": "A Private Page Search Engines Should Not Crawl",
+}
+
+// fixtureSite serves a small site with a robots.txt rule, a sitemap, a
+// redirect chain, a broken link, a link to a missing anchor, and two duplicate
+// pages. It records every path requested.
+type fixtureSite struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	requested map[string]int
+}
+
+func newFixtureSite(t *testing.T) *fixtureSite {
+	site := &fixtureSite{requested: make(map[string]int)}
+	site.Server = httptest.NewServer(http.HandlerFunc(site.serve))
+	t.Cleanup(site.Close)
+	return site
+}
+
+func (s *fixtureSite) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requested[r.URL.Path]++
+	s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/robots.txt":
+		fmt.Fprintf(w, "User-agent: *\nDisallow: /private/\n\nSitemap: %s/sitemap.xml\n", s.URL)
+		return
+	case "/sitemap.xml":
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>%[1]s/</loc></url>
+	<url><loc>%[1]s/orphan</loc></url>
+</urlset>`, s.URL)
+		return
+	case "/old":
+		http.Redirect(w, r, "/older", http.StatusMovedPermanently)
+		return
+	case "/older":
+		http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		return
+	}
+
+	body, ok := fixturePages[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprintf(w, `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<title>%s</title>
+	<meta name="description" content="A page of the fixture site that the end-to-end crawl test runs against, long enough to pass.">
+	<link rel="canonical" href="%s%s">
+</head>
+<body><h1>%s</h1>%s</body>
+</html>`, fixtureTitles[r.URL.Path], s.URL, r.URL.Path, fixtureTitles[r.URL.Path], body)
+}
+
+func (s *fixtureSite) requestCount(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requested[path]
+}
+
+// TestCrawlPipeline crawls the fixture site, analyzes and exports the pages,
+// and reads them back through the serve API, checking the output of each step
+func TestCrawlPipeline(t *testing.T) {
+	site := newFixtureSite(t)
+
+	config := utils.DefaultConfig()
+	config.StartURL = site.URL + "/"
+	config.Workers = 2
+	config.ParseSitemap = true
+	config.SkipImageCheck = true
+	if err := config.Validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+
+	// Crawl and analyze
+	manager := crawler.NewManager(config)
+	analysis := newAnalysis(config)
+	manager.SetAnalyzer(analysis)
+	results, err := manager.Crawl()
+	if err != nil {
+		t.Fatalf("crawl failed: %v", err)
+	}
+	summary := analysis.Summary()
+
+	pages := make(map[string]*models.PageResult, len(results))
+	for _, result := range results {
+		pages[strings.TrimPrefix(result.URL, site.URL)] = result
+	}
+	var crawled []string
+	for path := range pages {
+		crawled = append(crawled, path)
+	}
+	sort.Strings(crawled)
+	if want := []string{"/", "/about", "/missing", "/old", "/orphan", "/products"}; strings.Join(crawled, " ") != strings.Join(want, " ") {
+		t.Errorf("crawled %v, want %v", crawled, want)
+	}
+
+	if n := site.requestCount("/private/secret"); n != 0 {
+		t.Errorf("robots.txt disallowed /private/secret, but it was requested %d times", n)
+	}
+	if skipped := manager.SkipCounts(); skipped.Robots != 1 {
+		t.Errorf("skipped %d URLs for robots.txt, want 1", skipped.Robots)
+	}
+
+	if orphan := pages["/orphan"]; orphan == nil || !orphan.InSitemap {
+		t.Errorf("/orphan should be crawled from the sitemap and marked in_sitemap")
+	}
+	if old := pages["/old"]; old == nil || old.StatusCode != http.StatusOK || len(old.RedirectChain) != 2 {
+		t.Errorf("/old should follow two redirects to a 200, got %+v", old)
+	}
+	if missing := pages["/missing"]; missing == nil || missing.StatusCode != http.StatusNotFound {
+		t.Errorf("/missing should be crawled as a 404, got %+v", missing)
+	}
+	if about, products := pages["/about"], pages["/products"]; about == nil || products == nil ||
+		about.Title != products.Title || about.TextHash == "" || about.TextHash != products.TextHash {
+		t.Errorf("/about and /products should be crawled with the same title and text hash")
+	}
+	if home := pages["/"]; home == nil || home.Depth != 0 || len(home.InternalLinks) != 5 {
+		t.Errorf("/ should be crawled at depth 0 with 5 internal links, got %+v", home)
+	}
+
+	if summary.TotalPages != len(results) {
+		t.Errorf("summary counts %d pages, crawled %d", summary.TotalPages, len(results))
+	}
+	for _, want := range []struct {
+		issueType analyzer.IssueType
+		path      string
+	}{
+		{analyzer.IssueBrokenLink, "/missing"},
+		{analyzer.IssueRedirectChain, "/old"},
+		{analyzer.IssueDuplicateContent, "/about"},
+		{analyzer.IssueDuplicateContent, "/products"},
+		{analyzer.IssueBrokenAnchor, "/old"},
+	} {
+		if !hasIssue(summary.Issues, want.issueType, site.URL+want.path) {
+			t.Errorf("expected a %s issue for %s", want.issueType, want.path)
+		}
+	}
+	for _, issue := range summary.Issues {
+		if issue.Type == analyzer.IssueBrokenAnchor && issue.Value != site.URL+"/#pricing" {
+			t.Errorf("%s issue for %s lists %q, want only /#pricing", issue.Type, issue.URL, issue.Value)
+		}
+	}
+	// The fixture answers missing pages with a bare "404 page not found"
+	if notFound := probeNotFound(manager); len(notFound) != 1 || notFound[0].Type != analyzer.IssueUnhelpful404 {
+		t.Errorf("404 check found %v, want one %s issue", notFound, analyzer.IssueUnhelpful404)
+	}
+	if summary.PagesWithRedirects != 1 {
+		t.Errorf("summary counts %d pages with redirects, want 1", summary.PagesWithRedirects)
+	}
+
+	// Export, and import each format again
+	dir := t.TempDir()
+	for _, format := range []string{"json", "csv", "jsonl"} {
+		exportConfig := *config
+		exportConfig.ExportFormat = format
+		exportConfig.ExportPath = filepath.Join(dir, "results."+format)
+		if err := exportResults(results, summary, &exportConfig); err != nil {
+			t.Fatalf("%s export failed: %v", format, err)
+		}
+		imported, err := exporter.ImportResults(exportConfig.ExportPath)
+		if err != nil {
+			t.Fatalf("%s import failed: %v", format, err)
+		}
+		checkSamePages(t, format, results, imported)
+	}
+
+	// Serve the exported results
+	previousResults := serveResults
+	serveResults = filepath.Join(dir, "results.json")
+	t.Cleanup(func() { serveResults = previousResults })
+	served, manifest, err := loadServeResults(nil)
+	if err != nil {
+		t.Fatalf("failed to load served results: %v", err)
+	}
+	apiMux := http.NewServeMux()
+	registerServeDataRoutes(apiMux, served, analyzer.Analyze(served), manifest, nil, manager.GetLinkGraph().GetAllEdges())
+	api := httptest.NewServer(apiMux)
+	t.Cleanup(api.Close)
+
+	var apiResults []*models.PageResult
+	getJSON(t, api.URL+"/api/results", &apiResults)
+	checkSamePages(t, "/api/results", results, apiResults)
+
+	var apiSummary analyzer.Summary
+	getJSON(t, api.URL+"/api/summary", &apiSummary)
+	if apiSummary.TotalPages != summary.TotalPages {
+		t.Errorf("/api/summary counts %d pages, want %d", apiSummary.TotalPages, summary.TotalPages)
+	}
+	for issueType, count := range summary.IssuesByType {
+		if apiSummary.IssuesByType[issueType] != count {
+			t.Errorf("/api/summary counts %d %s issues, want %d", apiSummary.IssuesByType[issueType], issueType, count)
+		}
+	}
+
+	var apiGraph map[string][]string
+	getJSON(t, api.URL+"/api/graph", &apiGraph)
+	if links := apiGraph[site.URL+"/"]; len(links) == 0 {
+		t.Errorf("/api/graph has no links from the home page")
+	}
+
+	// Each page's canonical is itself, and /old's is /new, which the crawl
+	// reached by redirect without crawling /new itself
+	var apiClusters []analyzer.CanonicalCluster
+	getJSON(t, api.URL+"/api/canonicals", &apiClusters)
+	if len(apiClusters) != 1 || apiClusters[0].Canonical != site.URL+"/new" || apiClusters[0].MemberCount != 1 ||
+		apiClusters[0].Members[0].URL != site.URL+"/old" {
+		t.Errorf("/api/canonicals should have one cluster of /old pointing to /new, got %+v", apiClusters)
+	}
+}
+
+// hasIssue reports whether issues include one of issueType for url
+func hasIssue(issues []analyzer.Issue, issueType analyzer.IssueType, url string) bool {
+	for _, issue := range issues {
+		if issue.Type == issueType && issue.URL == url {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSamePages compares the fields every export format keeps
+func checkSamePages(t *testing.T, source string, want, got []*models.PageResult) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %d pages, want %d", source, len(got), len(want))
+		return
+	}
+	byURL := make(map[string]*models.PageResult, len(got))
+	for _, page := range got {
+		byURL[page.URL] = page
+	}
+	for _, page := range want {
+		other := byURL[page.URL]
+		switch {
+		case other == nil:
+			t.Errorf("%s: missing %s", source, page.URL)
+		case other.StatusCode != page.StatusCode || other.Title != page.Title || other.Canonical != page.Canonical:
+			t.Errorf("%s: %s is %d %q %q, want %d %q %q", source, page.URL,
+				other.StatusCode, other.Title, other.Canonical, page.StatusCode, page.Title, page.Canonical)
+		case len(other.RedirectChain) != len(page.RedirectChain) || len(other.InternalLinks) != len(page.InternalLinks):
+			t.Errorf("%s: %s has %d redirects and %d internal links, want %d and %d", source, page.URL,
+				len(other.RedirectChain), len(other.InternalLinks), len(page.RedirectChain), len(page.InternalLinks))
+		}
+	}
+}
+
+// getJSON decodes the JSON response of a GET request into v
+func getJSON(t *testing.T, url string, v any) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+}
@@ -0,0 +1,504 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawldiff"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/internal/wayback"
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	crawlsDir       string
+	crawlsDomain    string
+	crawlsKeep      int
+	crawlsOlderThan time.Duration
+	crawlsDryRun    bool
+	crawlsWayback   bool
+	crawlsFormat    string
+)
+
+// crawlsCmd represents the crawls command
+var crawlsCmd = &cobra.Command{
+	Use:          "crawls",
+	Short:        "Browse and prune saved crawl directories",
+	SilenceUsage: true,
+	Long: `Manage the crawl directories created by interactive mode, 'crawl --output-dir', and 'schedule'.
+
+Each crawl directory is named <domain>_<timestamp> and contains:
+  results.<csv|json>  page results
+  graph.json          link graph
+  anchors.csv         anchor text of the internal links to each page
+  summary.json        analysis summary
+  issues.json         detected issues
+  crawl.log           JSON log of the run
+  metadata.json       status, timing, totals, host stats, and the crawl config used`,
+}
+
+var crawlsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved crawls, newest first",
+	Args:  cobra.NoArgs,
+	RunE:  runCrawlsList,
+}
+
+var crawlsShowCmd = &cobra.Command{
+	Use:   "show <crawl>",
+	Short: "Show details and the analysis summary of a saved crawl",
+	Long:  `Show a saved crawl. <crawl> is the directory name, a unique prefix of it, or "latest".`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCrawlsShow,
+}
+
+var crawlsCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Delete old crawl directories",
+	Long: `Delete saved crawls that are beyond --keep per domain or older than --older-than.
+At least one of --keep or --older-than is required. Use --dry-run to preview.`,
+	Args: cobra.NoArgs,
+	RunE: runCrawlsClean,
+}
+
+var crawlsDiffCmd = &cobra.Command{
+	Use:   "diff [old] <new>",
+	Short: "Compare two saved crawls of a site",
+	Long: `Compare two saved crawls: pages added and removed, status code changes, and
+issues introduced or fixed. With one argument, <new> is compared with the
+crawl of the same domain just before it.
+
+Pages that returned 404 or 410 in the new crawl are looked up in the Wayback
+Machine, and the newest archived copy is linked so lost content can be recovered.
+
+When the crawls were made as different user agents, such as one with
+--user-agent-preset googlebot and one without, pages served to them with a
+different status code, title, description, canonical, robots directives, or
+amount of text are listed, to catch cloaking.`,
+	Example: `  barracuda crawls diff latest
+  barracuda crawls diff example.com_2025-01-01 example.com_2025-02-01 --format json`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runCrawlsDiff,
+}
+
+func init() {
+	crawlsCmd.PersistentFlags().StringVar(&crawlsDir, "dir", crawldir.DefaultParent, "Directory containing crawl runs")
+	crawlsCmd.PersistentFlags().StringVar(&crawlsDomain, "domain", "", "Only include crawls of this domain")
+
+	crawlsCleanCmd.Flags().IntVar(&crawlsKeep, "keep", 0, "Number of most recent crawls to keep per domain")
+	crawlsCleanCmd.Flags().DurationVar(&crawlsOlderThan, "older-than", 0, "Delete crawls older than this (e.g. 720h)")
+	crawlsCleanCmd.Flags().BoolVar(&crawlsDryRun, "dry-run", false, "Show what would be deleted without deleting")
+
+	crawlsCmd.AddCommand(crawlsListCmd)
+	crawlsCmd.AddCommand(crawlsShowCmd)
+	crawlsDiffCmd.Flags().BoolVar(&crawlsWayback, "wayback", true, "Look up archived copies of removed pages in the Wayback Machine")
+	crawlsDiffCmd.Flags().StringVar(&crawlsFormat, "format", "text", "Output format: text or json")
+
+	crawlsCmd.AddCommand(crawlsCleanCmd)
+	crawlsCmd.AddCommand(crawlsDiffCmd)
+	rootCmd.AddCommand(crawlsCmd)
+}
+
+// filteredRuns lists runs, applying --domain
+func filteredRuns() ([]crawldir.Run, error) {
+	runs, err := crawldir.List(crawlsDir)
+	if err != nil {
+		return nil, err
+	}
+	if crawlsDomain == "" {
+		return runs, nil
+	}
+
+	filtered := runs[:0]
+	for _, run := range runs {
+		if run.Domain == crawlsDomain {
+			filtered = append(filtered, run)
+		}
+	}
+	return filtered, nil
+}
+
+// findRun resolves a crawl reference, where "latest" is the newest matching run
+func findRun(ref string) (*crawldir.Run, error) {
+	if ref != "latest" {
+		return crawldir.Find(crawlsDir, ref)
+	}
+
+	runs, err := filteredRuns()
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no crawls found in %s/", crawlsDir)
+	}
+	return &runs[0], nil
+}
+
+func runCrawlsList(cmd *cobra.Command, args []string) error {
+	runs, err := filteredRuns()
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Fprintf(os.Stdout, "No crawls found in %s/\n", crawlsDir)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CRAWL\tDOMAIN\tSTARTED\tSTATUS\tPAGES\tISSUES")
+	for _, run := range runs {
+		status, pages, issues := "unknown", "-", "-"
+		if run.Metadata != nil {
+			status = run.Metadata.Status
+			pages = fmt.Sprint(run.Metadata.TotalPages)
+			issues = fmt.Sprint(run.Metadata.TotalIssues)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			run.Name, run.Domain, run.Time.Format("2006-01-02 15:04"), status, pages, issues)
+	}
+	return w.Flush()
+}
+
+func runCrawlsShow(cmd *cobra.Command, args []string) error {
+	run, err := findRun(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "📁 %s\n\n", run.Path)
+	if meta := run.Metadata; meta != nil {
+		fmt.Fprintf(os.Stdout, "URL:       %s\n", meta.URL)
+		fmt.Fprintf(os.Stdout, "Status:    %s\n", meta.Status)
+		if meta.Error != "" {
+			fmt.Fprintf(os.Stdout, "Error:     %s\n", meta.Error)
+		}
+		fmt.Fprintf(os.Stdout, "Started:   %s\n", meta.StartedAt.Format(time.RFC1123))
+		if !meta.CompletedAt.IsZero() {
+			fmt.Fprintf(os.Stdout, "Duration:  %s\n", meta.CompletedAt.Sub(meta.StartedAt).Round(time.Second))
+		}
+		fmt.Fprintf(os.Stdout, "Pages:     %d\n", meta.TotalPages)
+		fmt.Fprintf(os.Stdout, "Issues:    %d\n", meta.TotalIssues)
+		fmt.Fprintf(os.Stdout, "Version:   %s\n", meta.Version)
+		if len(meta.Hosts) > 0 {
+			fmt.Fprintf(os.Stdout, "\nHosts:\n")
+			for _, host := range meta.Hosts {
+				fmt.Fprintf(os.Stdout, "  %-30s %d pages, %d errors, avg %d ms\n", host.Host, host.Pages, host.Errors, host.AvgResponseTime)
+			}
+		}
+	} else {
+		fmt.Fprintf(os.Stdout, "(no %s)\n", crawldir.MetadataFile)
+	}
+
+	fmt.Fprintf(os.Stdout, "\nFiles:\n")
+	entries, err := os.ReadDir(run.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read crawl directory: %w", err)
+	}
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !entry.IsDir() {
+			fmt.Fprintf(os.Stdout, "  %-20s %s\n", entry.Name(), formatBytes(info.Size()))
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(run.Path, crawldir.SummaryFile))
+	if err != nil {
+		return nil
+	}
+	var summary analyzer.Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", crawldir.SummaryFile, err)
+	}
+	analyzer.PrintSummary(&summary)
+	return nil
+}
+
+func runCrawlsClean(cmd *cobra.Command, args []string) error {
+	if crawlsKeep <= 0 && crawlsOlderThan <= 0 {
+		return fmt.Errorf("specify --keep and/or --older-than")
+	}
+
+	runs, err := filteredRuns()
+	if err != nil {
+		return err
+	}
+
+	// Runs are newest first, so count per domain to apply --keep
+	seen := make(map[string]int)
+	var doomed []crawldir.Run
+	for _, run := range runs {
+		seen[run.Domain]++
+		tooMany := crawlsKeep > 0 && seen[run.Domain] > crawlsKeep
+		tooOld := crawlsOlderThan > 0 && time.Since(run.Time) > crawlsOlderThan
+		if tooMany || tooOld {
+			doomed = append(doomed, run)
+		}
+	}
+
+	if len(doomed) == 0 {
+		fmt.Fprintf(os.Stdout, "✓ Nothing to clean\n")
+		return nil
+	}
+
+	for _, run := range doomed {
+		if crawlsDryRun {
+			fmt.Fprintf(os.Stdout, "Would delete %s\n", run.Path)
+			continue
+		}
+		if err := os.RemoveAll(run.Path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", run.Path, err)
+		}
+		fmt.Fprintf(os.Stdout, "🗑  Deleted %s\n", run.Path)
+	}
+
+	if !crawlsDryRun {
+		fmt.Fprintf(os.Stdout, "✓ Removed %d crawl(s)\n", len(doomed))
+	}
+	return nil
+}
+
+func runCrawlsDiff(cmd *cobra.Command, args []string) error {
+	if crawlsFormat != "text" && crawlsFormat != "json" {
+		return fmt.Errorf("invalid --format %q: use text or json", crawlsFormat)
+	}
+
+	newRun, err := findRun(args[len(args)-1])
+	if err != nil {
+		return err
+	}
+	var oldRun *crawldir.Run
+	if len(args) == 2 {
+		if oldRun, err = findRun(args[0]); err != nil {
+			return err
+		}
+	} else if oldRun, err = previousRun(crawlsDir, newRun); err != nil {
+		return err
+	}
+
+	before, err := loadDiffCrawl(oldRun)
+	if err != nil {
+		return err
+	}
+	after, err := loadDiffCrawl(newRun)
+	if err != nil {
+		return err
+	}
+
+	diff := crawldiff.Compare(before, after)
+	if crawlsWayback {
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if failed := diff.FindArchived(ctx, wayback.NewClient(10*time.Second, "barracuda/"+Version)); failed > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  Wayback Machine lookup failed for %d page(s)\n", failed)
+		}
+	}
+
+	if crawlsFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+	printCrawlDiff(os.Stdout, diff)
+	return nil
+}
+
+// previousRun returns the crawl of the same domain under parent made just
+// before run, skipping crawls that failed or are still running
+func previousRun(parent string, run *crawldir.Run) (*crawldir.Run, error) {
+	runs, err := crawldir.List(parent)
+	if err != nil {
+		return nil, err
+	}
+	// Runs are newest first
+	for i := range runs {
+		if runs[i].Domain != run.Domain || !runs[i].Time.Before(run.Time) {
+			continue
+		}
+		if meta := runs[i].Metadata; meta != nil && (meta.Status == "failed" || meta.Status == "running") {
+			continue
+		}
+		return &runs[i], nil
+	}
+	return nil, fmt.Errorf("no earlier crawl of %s in %s/ to compare with", run.Domain, parent)
+}
+
+// loadDiffCrawl reads a crawl's results and issues for comparison
+func loadDiffCrawl(run *crawldir.Run) (crawldiff.Crawl, error) {
+	_, _, summary, results, err := loadCrawl(run.Path)
+	if err != nil {
+		return crawldiff.Crawl{}, err
+	}
+	crawl := crawldiff.Crawl{Name: run.Name, Results: results, Issues: summary.Issues}
+	if run.Metadata != nil {
+		crawl.UserAgent = run.Metadata.Config.UserAgent
+		crawl.RobotsTxt = run.Metadata.RobotsTxt
+	}
+	return crawl, nil
+}
+
+// printCrawlDiff writes a diff as a readable report
+func printCrawlDiff(out io.Writer, diff *crawldiff.Diff) {
+	fmt.Fprintf(out, "Comparing %s → %s\n\n", diff.Old, diff.New)
+	fmt.Fprintf(out, "Pages:  %d → %d (%d new, %d removed, %d status changes)\n",
+		diff.OldPages, diff.NewPages, len(diff.AddedPages), len(diff.RemovedPages), len(diff.StatusChanges))
+	fmt.Fprintf(out, "Indexable: %d → %d\n", diff.OldIndexable, diff.NewIndexable)
+	fmt.Fprintf(out, "Issues: %d → %d (%d new, %d fixed)\n",
+		diff.OldIssues, diff.NewIssues, len(diff.AddedIssues), len(diff.FixedIssues))
+
+	if len(diff.AddedPages) > 0 {
+		fmt.Fprintf(out, "\nNew pages:\n")
+		for _, url := range diff.AddedPages {
+			fmt.Fprintf(out, "  + %s\n", url)
+		}
+	}
+
+	if len(diff.RemovedPages) > 0 {
+		fmt.Fprintf(out, "\nRemoved pages:\n")
+		for _, page := range diff.RemovedPages {
+			if page.NewStatus == 0 {
+				fmt.Fprintf(out, "  - %s (%d → not reached)\n", page.URL, page.OldStatus)
+			} else {
+				fmt.Fprintf(out, "  - %s (%d → %d)\n", page.URL, page.OldStatus, page.NewStatus)
+			}
+			if page.Archived != nil {
+				fmt.Fprintf(out, "    📦 Archived %s: %s\n", page.Archived.Timestamp.Format("2006-01-02"), page.Archived.URL)
+			}
+		}
+	}
+
+	if len(diff.StatusChanges) > 0 {
+		fmt.Fprintf(out, "\nStatus changes:\n")
+		for _, change := range diff.StatusChanges {
+			fmt.Fprintf(out, "  ~ %s (%d → %d)\n", change.URL, change.OldStatus, change.NewStatus)
+		}
+	}
+
+	if change := diff.RobotsTxt; change != nil {
+		fmt.Fprintf(out, "\n⚠️  robots.txt changed: %s\n", change.Summary())
+		for _, rule := range change.Added {
+			fmt.Fprintf(out, "  + %s\n", rule)
+		}
+		for _, rule := range change.Removed {
+			fmt.Fprintf(out, "  - %s\n", rule)
+		}
+	}
+
+	printDiffIssues(out, "New issues", "+", diff.AddedIssues)
+	printDiffIssues(out, "Fixed issues", "-", diff.FixedIssues)
+
+	if diff.OldUserAgent != "" {
+		fmt.Fprintf(out, "\nUser agents: %s → %s\n", diff.OldUserAgent, diff.NewUserAgent)
+		if len(diff.Cloaking) == 0 {
+			fmt.Fprintf(out, "  ✅ Pages reached by both crawls were served the same way\n")
+		}
+		for _, page := range diff.Cloaking {
+			fmt.Fprintf(out, "  ⚠️  %s\n", page.URL)
+			for _, change := range page.Changes {
+				fmt.Fprintf(out, "     %s: %q → %q\n", change.Field, change.Old, change.New)
+			}
+		}
+	}
+}
+
+// printDiffIssues lists issues under a heading
+func printDiffIssues(out io.Writer, heading, marker string, issues []models.Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "\n%s:\n", heading)
+	for _, issue := range issues {
+		fmt.Fprintf(out, "  %s [%s] %s: %s\n", marker, issue.Severity, analyzer.IssueTypeLabel(issue.Type), issue.URL)
+	}
+}
+
+// newCrawlMetadata returns metadata for a crawl that is about to start
+func newCrawlMetadata(config *utils.Config) *crawldir.Metadata {
+	return &crawldir.Metadata{
+		Version:   Version,
+		URL:       config.StartURL,
+		Status:    "running",
+		StartedAt: time.Now(),
+		Config:    utils.CrawlFileConfigFrom(config),
+	}
+}
+
+// finishCrawlMetadata records the outcome of a crawl in metadata.json,
+// keeping a status the caller already set, such as "interrupted". Failures
+// are logged rather than returned so they never mask the crawl result.
+func finishCrawlMetadata(dir string, meta *crawldir.Metadata, summary *analyzer.Summary, pages int, hosts []crawldir.HostStats, crawlErr error) {
+	meta.CompletedAt = time.Now()
+	meta.TotalPages = pages
+	meta.Hosts = hosts
+	if meta.Status == "running" {
+		meta.Status = "succeeded"
+	}
+	if crawlErr != nil {
+		meta.Status = "failed"
+		meta.Error = crawlErr.Error()
+	}
+	if summary != nil {
+		meta.TotalIssues = summary.TotalIssues
+	}
+
+	if err := crawldir.WriteMetadata(dir, meta); err != nil {
+		utils.Warn("Failed to write crawl metadata", utils.NewField("error", err.Error()))
+	}
+}
+
+// saveCrawlArtifacts writes summary.json and issues.json into a crawl directory
+func saveCrawlArtifacts(dir string, summary *analyzer.Summary) error {
+	if err := exportSummary(summary, filepath.Join(dir, crawldir.SummaryFile)); err != nil {
+		return fmt.Errorf("summary export failed: %w", err)
+	}
+	if err := writeJSONFile(filepath.Join(dir, crawldir.IssuesFile), summary.Issues); err != nil {
+		return fmt.Errorf("issues export failed: %w", err)
+	}
+	return nil
+}
+
+// exportSummary writes the analysis summary as indented JSON
+func exportSummary(summary *analyzer.Summary, filePath string) error {
+	return writeJSONFile(filePath, summary)
+}
+
+// writeJSONFile writes v to filePath as indented JSON
+func writeJSONFile(filePath string, v interface{}) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(filePath), err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", filepath.Base(filePath), err)
+	}
+
+	return nil
+}
+
+// formatBytes renders a file size for display
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), strings.ToUpper("kmgtpe")[exp])
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dillonlara115/barracuda/internal/encrypt"
+	"github.com/spf13/cobra"
+)
+
+var decryptKey string
+
+// decryptCmd represents the decrypt command
+var decryptCmd = &cobra.Command{
+	Use:          "decrypt <file.age>...",
+	Short:        "Decrypt files written by crawl --encrypt-output",
+	SilenceUsage: true,
+	Long: `Decrypt crawl output encrypted with crawl --encrypt-output. Each file is
+decrypted next to itself without the .age extension, and encrypted saved HTML
+(.tar.age) is extracted into a directory. The encrypted files are kept.
+
+Files encrypted with a passphrase are decrypted with the passphrase in the
+` + encrypt.PassphraseEnv + ` environment variable. Files encrypted to age
+public keys need --key with a matching identity file, as made by age-keygen.
+The files are standard age files, so "age -d" decrypts them too.`,
+	Example: `  BARRACUDA_PASSPHRASE=… barracuda decrypt crawls/example.com_2025-01-01_10-00-00/*.age
+  barracuda decrypt results.json.age --key client-key.txt`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDecrypt,
+}
+
+func init() {
+	decryptCmd.Flags().StringVar(&decryptKey, "key", "", "age identity file to decrypt with, instead of the "+encrypt.PassphraseEnv+" passphrase")
+
+	rootCmd.AddCommand(decryptCmd)
+}
+
+func runDecrypt(cmd *cobra.Command, args []string) error {
+	decryptor, err := encrypt.NewDecryptor(decryptKey, os.Getenv(encrypt.PassphraseEnv))
+	if err != nil {
+		return err
+	}
+	for _, path := range args {
+		decrypted, err := decryptor.File(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "🔓 Decrypted %s\n", decrypted)
+	}
+	return nil
+}
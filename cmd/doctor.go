@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/render"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/spf13/cobra"
+	"github.com/temoto/robotstxt"
+)
+
+var (
+	doctorTimeout   time.Duration
+	doctorUserAgent string
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [URL]",
+	Short: "Check the environment and target site for common problems",
+	Long: `Run diagnostics for everything barracuda depends on and print a fix for each problem:
+DNS and HTTP connectivity to the target site, robots.txt access, Google Search Console
+credentials, Supabase/API environment variables, headless browser availability, and the
+embedded web dashboard.
+
+The target URL is taken from the argument, --url, or crawl.url in the config file.
+Site checks are skipped when no URL is available.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().StringP("url", "u", "", "Target URL to check")
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 10*time.Second, "Timeout for network checks")
+	doctorCmd.Flags().StringVar(&doctorUserAgent, "user-agent", "barracuda/1.0.0", "User agent used for HTTP and robots.txt checks")
+
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// checkStatus is the outcome of a single diagnostic
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+	checkSkip
+)
+
+// doctorReport prints check results and tallies failures
+type doctorReport struct {
+	warnings int
+	failures int
+}
+
+// add prints one check result with an optional fix
+func (r *doctorReport) add(status checkStatus, name, detail, fix string) {
+	icon := "✓"
+	switch status {
+	case checkWarn:
+		icon = "⚠️ "
+		r.warnings++
+	case checkFail:
+		icon = "❌"
+		r.failures++
+	case checkSkip:
+		icon = "–"
+	}
+
+	fmt.Fprintf(os.Stdout, "  %s %-22s %s\n", icon, name, detail)
+	if fix != "" && (status == checkWarn || status == checkFail) {
+		fmt.Fprintf(os.Stdout, "      → %s\n", fix)
+	}
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	report := &doctorReport{}
+
+	target, _ := cmd.Flags().GetString("url")
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	fmt.Fprintf(os.Stdout, "🩺 barracuda doctor\n\n")
+
+	// Config file
+	fmt.Fprintf(os.Stdout, "Configuration:\n")
+	fc, path, err := loadFileConfig()
+	switch {
+	case err != nil:
+		report.add(checkFail, "config file", err.Error(), "Fix the YAML syntax or pass a different file with --config")
+	case fc == nil:
+		report.add(checkSkip, "config file", fmt.Sprintf("none (%s not found)", utils.DefaultConfigFile), "")
+	default:
+		cfg := utils.DefaultConfig()
+		if err := fc.Crawl.ApplyTo(cfg); err != nil {
+			report.add(checkFail, "config file", err.Error(), "Correct the crawl section of "+path)
+		} else {
+			report.add(checkOK, "config file", path, "")
+		}
+		if target == "" {
+			target = fc.Crawl.URL
+		}
+	}
+
+	// Target site
+	fmt.Fprintf(os.Stdout, "\nTarget site:\n")
+	if target == "" {
+		report.add(checkSkip, "site checks", "no URL given", "")
+	} else {
+		checkSite(report, target)
+	}
+
+	// Integrations
+	fmt.Fprintf(os.Stdout, "\nIntegrations:\n")
+	checkGSCCredentials(report)
+	checkSupabaseEnv(report)
+
+	// Local tooling
+	fmt.Fprintf(os.Stdout, "\nLocal environment:\n")
+	if path, err := render.FindBrowser(); err != nil {
+		report.add(checkWarn, "headless browser", err.Error(),
+			fmt.Sprintf("Install Chrome/Chromium or set %s to its path (needed for render mode only)", render.BrowserEnvVar))
+	} else {
+		report.add(checkOK, "headless browser", path, "")
+	}
+	if hasFrontendAssets() {
+		report.add(checkOK, "web dashboard", "frontend assets available", "")
+	} else {
+		report.add(checkWarn, "web dashboard", "frontend assets not embedded",
+			"Run 'make frontend-build' and rebuild barracuda so 'serve' and '--open' can show the dashboard")
+	}
+
+	fmt.Fprintf(os.Stdout, "\n")
+	if report.failures > 0 {
+		return fmt.Errorf("%d check(s) failed, %d warning(s)", report.failures, report.warnings)
+	}
+	if report.warnings > 0 {
+		fmt.Fprintf(os.Stdout, "✓ No failures (%d warning(s))\n", report.warnings)
+	} else {
+		fmt.Fprintf(os.Stdout, "✓ All checks passed\n")
+	}
+	return nil
+}
+
+// checkSite verifies DNS, HTTP connectivity, and robots.txt for the target URL
+func checkSite(report *doctorReport, target string) {
+	normalized, err := utils.NormalizeURL(target)
+	if err != nil {
+		report.add(checkFail, "url", err.Error(), "Use a full URL such as https://example.com")
+		return
+	}
+	u, err := url.Parse(normalized)
+	if err != nil || u.Hostname() == "" {
+		report.add(checkFail, "url", "could not determine host", "Use a full URL such as https://example.com")
+		return
+	}
+
+	// DNS
+	addrs, err := net.LookupHost(u.Hostname())
+	if err != nil {
+		report.add(checkFail, "dns", err.Error(), "Check the hostname spelling and your network/DNS settings")
+		return
+	}
+	report.add(checkOK, "dns", fmt.Sprintf("%s → %s", u.Hostname(), addrs[0]), "")
+
+	// HTTP connectivity
+	fetcher := crawler.NewFetcher(doctorTimeout, doctorUserAgent)
+	result := fetcher.Fetch(normalized)
+	switch {
+	case result.Error != nil:
+		report.add(checkFail, "http", result.Error.Error(),
+			"Confirm the site is reachable from this machine (firewall, VPN, TLS certificate) or raise --timeout")
+		return
+	case result.PageResult.StatusCode >= 400:
+		report.add(checkWarn, "http", fmt.Sprintf("HTTP %d in %dms", result.PageResult.StatusCode, result.PageResult.ResponseTime),
+			"The start URL returns an error; the crawl will find no links. Check the URL or any bot protection")
+	default:
+		report.add(checkOK, "http", fmt.Sprintf("HTTP %d in %dms", result.PageResult.StatusCode, result.PageResult.ResponseTime), "")
+	}
+
+	// robots.txt
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+	robotsResult := fetcher.Fetch(robotsURL)
+	switch {
+	case robotsResult.Error != nil:
+		report.add(checkWarn, "robots.txt", robotsResult.Error.Error(), "robots.txt is unreachable; barracuda will treat every URL as allowed")
+	case robotsResult.PageResult.StatusCode != 200:
+		report.add(checkOK, "robots.txt", fmt.Sprintf("HTTP %d (all URLs allowed)", robotsResult.PageResult.StatusCode), "")
+	default:
+		path := u.EscapedPath()
+		if path == "" {
+			path = "/"
+		}
+		robots, err := robotstxt.FromBytes(robotsResult.Body)
+		if err != nil {
+			report.add(checkWarn, "robots.txt", "could not be parsed", "Fix the robots.txt syntax; barracuda will treat every URL as allowed")
+		} else if !robots.TestAgent(path, doctorUserAgent) {
+			report.add(checkWarn, "robots.txt", "start URL is disallowed for "+doctorUserAgent,
+				"Allow the barracuda user agent in robots.txt, change --user-agent, or crawl with --respect-robots=false if you own the site")
+		} else {
+			report.add(checkOK, "robots.txt", "start URL allowed", "")
+		}
+	}
+}
+
+// checkGSCCredentials reports whether Google Search Console OAuth is configured
+func checkGSCCredentials(report *doctorReport) {
+	clientID := os.Getenv("GSC_CLIENT_ID")
+	clientSecret := os.Getenv("GSC_CLIENT_SECRET")
+	credentialsJSON := os.Getenv("GSC_CREDENTIALS_JSON")
+
+	switch {
+	case clientID != "" && clientSecret != "":
+		report.add(checkOK, "gsc credentials", "GSC_CLIENT_ID and GSC_CLIENT_SECRET set", "")
+	case credentialsJSON != "":
+		var parsed map[string]interface{}
+		if err := json.Unmarshal([]byte(credentialsJSON), &parsed); err != nil {
+			report.add(checkFail, "gsc credentials", "GSC_CREDENTIALS_JSON is not valid JSON",
+				"Paste the OAuth client JSON downloaded from Google Cloud Console")
+		} else {
+			report.add(checkOK, "gsc credentials", "GSC_CREDENTIALS_JSON set", "")
+		}
+	case clientID != "" || clientSecret != "":
+		report.add(checkFail, "gsc credentials", "only one of GSC_CLIENT_ID / GSC_CLIENT_SECRET is set",
+			"Set both GSC_CLIENT_ID and GSC_CLIENT_SECRET")
+	default:
+		report.add(checkWarn, "gsc credentials", "not configured",
+			"Set GSC_CLIENT_ID and GSC_CLIENT_SECRET (or GSC_CREDENTIALS_JSON) to enable Search Console integration")
+	}
+}
+
+// checkSupabaseEnv reports whether the environment variables used by 'barracuda api' are set
+func checkSupabaseEnv(report *doctorReport) {
+	vars := []string{"PUBLIC_SUPABASE_URL", "SUPABASE_SERVICE_ROLE_KEY", "PUBLIC_SUPABASE_ANON_KEY"}
+
+	var missing []string
+	for _, name := range vars {
+		if os.Getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	switch {
+	case len(missing) == 0:
+		if _, err := url.ParseRequestURI(os.Getenv("PUBLIC_SUPABASE_URL")); err != nil {
+			report.add(checkFail, "supabase/api env", "PUBLIC_SUPABASE_URL is not a valid URL",
+				"Use the project URL from Supabase settings, e.g. https://xyz.supabase.co")
+		} else {
+			report.add(checkOK, "supabase/api env", "all variables set", "")
+		}
+	case len(missing) == len(vars):
+		report.add(checkSkip, "supabase/api env", "not configured (only needed for 'barracuda api')", "")
+	default:
+		report.add(checkFail, "supabase/api env", fmt.Sprintf("missing %v", missing),
+			"Set the missing variables or pass --supabase-url/--supabase-service-key/--supabase-anon-key to 'barracuda api'")
+	}
+
+	if os.Getenv("STRIPE_SECRET_KEY") == "" && len(missing) == 0 {
+		report.add(checkWarn, "stripe", "STRIPE_SECRET_KEY not set", "Set STRIPE_SECRET_KEY to enable billing endpoints")
+	}
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+// dryRunExamples is how many example URLs are printed per skip reason
+const dryRunExamples = 5
+
+// largeCrawlPages is the max-pages value above which dry-run prints a warning
+const largeCrawlPages = 10000
+
+// runDryRun prints the effective crawl plan: resolved settings and where each
+// came from, robots.txt status, seed URLs, and how include/exclude rules apply
+// to them. Only robots.txt and the sitemap are fetched.
+func runDryRun(cmd *cobra.Command, config *utils.Config, fc *utils.FileConfig, fcPath string) error {
+	var file utils.CrawlFileConfig
+	if fc != nil {
+		file = fc.Crawl
+	}
+	source := func(flag string, inFile bool) string {
+		switch {
+		case interactive:
+			return "interactive"
+		case cmd.Flags().Changed(flag):
+			return "flag"
+		case inFile:
+			return "config file"
+		case presetFlags[flag]:
+			return "preset " + preset
+		default:
+			return "default"
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "🧪 Dry run — no pages will be crawled\n\n")
+	if fcPath != "" {
+		fmt.Fprintf(os.Stdout, "Config file: %s\n\n", fcPath)
+	}
+
+	fmt.Fprintf(os.Stdout, "Effective settings:\n")
+	setting := func(name, value, src string) {
+		fmt.Fprintf(os.Stdout, "  %-16s %-40s (%s)\n", name, value, src)
+	}
+	urlSource := "argument"
+	if interactive {
+		urlSource = "interactive"
+	} else if cmd.Flags().Changed("url") {
+		urlSource = "flag"
+	} else if file.URL != "" && file.URL == config.StartURL {
+		urlSource = "config file"
+	}
+	fromSeeds := make(map[string]bool, len(seedURLs))
+	for _, seed := range seedURLs {
+		fromSeeds[seed] = true
+	}
+	startURLs := config.StartURLs()
+	for i, startURL := range startURLs {
+		if fromSeeds[startURL] && i >= dryRunExamples && len(startURLs) > i+1 {
+			setting("url", fmt.Sprintf("… and %d more", len(startURLs)-i), "seeds file")
+			break
+		}
+		if fromSeeds[startURL] {
+			setting("url", startURL, "seeds file")
+		} else {
+			setting("url", startURL, urlSource)
+		}
+	}
+	setting("max-depth", fmt.Sprint(config.MaxDepth), source("max-depth", file.MaxDepth != nil))
+	setting("max-pages", fmt.Sprint(config.MaxPages), source("max-pages", file.MaxPages != nil))
+	setting("workers", fmt.Sprint(config.Workers), source("workers", file.Workers != nil))
+	setting("delay", config.Delay.String(), source("delay", file.Delay != ""))
+	maxRPS := "(no limit)"
+	if config.MaxRPS > 0 {
+		maxRPS = fmt.Sprintf("%g per host", config.MaxRPS)
+	}
+	setting("max-rps", maxRPS, source("max-rps", file.MaxRPS != nil))
+	setting("auto-throttle", fmt.Sprint(config.AutoThrottle), source("auto-throttle", file.AutoThrottle != nil))
+	setting("timeout", config.Timeout.String(), source("timeout", file.Timeout != ""))
+	maxConns := "(no limit)"
+	if config.MaxConnsPerHost > 0 {
+		maxConns = fmt.Sprint(config.MaxConnsPerHost)
+	}
+	setting("max-conns-per-host", maxConns, source("max-conns-per-host", file.MaxConnsPerHost != nil))
+	setting("http2", fmt.Sprint(!config.DisableHTTP2), source("http2", file.HTTP2 != nil))
+	setting("keep-alive", fmt.Sprint(!config.DisableKeepAlives), source("keep-alive", file.KeepAlive != nil))
+	userAgentSource := source("user-agent", file.UserAgent != "" || file.UserAgentPreset != "")
+	if cmd.Flags().Changed("mobile") {
+		userAgentSource = "mobile"
+	} else if cmd.Flags().Changed("user-agent-preset") {
+		userAgentSource = "user-agent-preset " + userAgentPreset
+	}
+	setting("user-agent", config.UserAgent, userAgentSource)
+	setting("respect-robots", fmt.Sprint(config.RespectRobots), source("respect-robots", file.RespectRobots != nil))
+	setting("respect-nofollow", fmt.Sprint(config.RespectNofollow), source("respect-nofollow", file.RespectNofollow != nil))
+	setting("parse-sitemap", fmt.Sprint(config.ParseSitemap), source("parse-sitemap", file.ParseSitemap != nil))
+	if config.SitemapSince != "" {
+		setting("sitemap-since", config.SitemapSince, source("sitemap-since", file.SitemapSince != ""))
+	}
+	setting("domain-filter", config.DomainFilter, source("domain-filter", file.DomainFilter != ""))
+	setting("format", config.ExportFormat, source("format", file.ExportFormat != ""))
+	setting("include", formatPatterns(config.Include), source("include", len(file.Include) > 0))
+	setting("exclude", formatPatterns(config.Exclude), source("exclude", len(file.Exclude) > 0))
+	scope := config.ScopePath
+	if scope == "" {
+		scope = "(none)"
+	}
+	setting("scope-path", scope, source("scope-path", file.ScopePath != ""))
+	saveHTML := config.SaveHTMLDir
+	if saveHTML == "" {
+		saveHTML = "(off)"
+	}
+	setting("save-html", saveHTML, source("save-html", file.SaveHTML != ""))
+	httpCache := config.HTTPCacheDir
+	if httpCache == "" {
+		httpCache = "(off)"
+	}
+	setting("http-cache", httpCache, source("http-cache", file.HTTPCache != ""))
+	setting("mobile-parity", fmt.Sprint(config.MobileParity), source("mobile-parity", file.MobileParity != nil))
+	setting("render", fmt.Sprint(config.Render), source("render", file.Render != nil))
+	visited := "(no limit)"
+	if config.VisitedLimit > 0 {
+		visited = fmt.Sprintf("%d, then bloom filter at %g", config.VisitedLimit, config.VisitedFPRate)
+	}
+	setting("visited-limit", visited, source("visited-limit", file.VisitedLimit != nil))
+	setting("low-memory", fmt.Sprint(config.LowMemory), source("low-memory", file.LowMemory != nil))
+	setting("strategy", config.Strategy, source("strategy", file.Strategy != ""))
+	setting("stream", fmt.Sprint(config.StreamResults), source("stream", file.StreamResults != nil))
+	maxBody := config.MaxBodySize
+	if size, err := config.MaxBodyBytes(); err == nil && size == 0 {
+		maxBody = "(no limit)"
+	}
+	setting("max-body-size", maxBody, source("max-body-size", file.MaxBodySize != ""))
+	active := config.ActiveHours
+	if active == "" {
+		active = "(any time)"
+	}
+	setting("active-hours", active, source("active-hours", file.ActiveHours != ""))
+	for _, rewrite := range config.HostRewrites {
+		setting("host-rewrite", rewrite, source("host-rewrite", len(file.HostRewrites) > 0))
+	}
+	if len(config.Headers) > 0 || len(config.Cookies) > 0 || config.BasicAuth != "" {
+		setting("auth", formatAuth(config), "flag")
+	}
+	if config.LoginURL != "" {
+		setting("login", formatLogin(config), "flag")
+	}
+	if preset != "" {
+		setting("preset", preset, "flag")
+	}
+
+	manager := crawler.NewManager(config)
+
+	seeds, err := manager.SeedURLs()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "\nrobots.txt:\n")
+	robotsURL, err := manager.RobotsTxt()
+	switch {
+	case !config.RespectRobots:
+		fmt.Fprintf(os.Stdout, "  Ignored (--respect-robots=false)\n")
+	case err != nil:
+		fmt.Fprintf(os.Stdout, "  %s could not be fetched (%v) — all URLs allowed\n", robotsURL, err)
+	default:
+		fmt.Fprintf(os.Stdout, "  ✓ %s fetched\n", robotsURL)
+	}
+	crawlDelay, robotsSitemaps := manager.RobotsDirectives()
+	if crawlDelay > 0 {
+		fmt.Fprintf(os.Stdout, "  Crawl-delay: %s between requests\n", crawlDelay)
+	}
+	for _, sitemapURL := range robotsSitemaps {
+		fmt.Fprintf(os.Stdout, "  Sitemap: %s\n", sitemapURL)
+	}
+
+	fmt.Fprintf(os.Stdout, "\nSeed URLs:\n")
+	starts := make(map[string]bool)
+	for _, startURL := range config.StartURLs() {
+		if normalized, err := utils.NormalizeURL(startURL); err == nil {
+			starts[normalized] = true
+		}
+	}
+	startOnly := true
+	for _, seed := range seeds {
+		startOnly = startOnly && starts[seed]
+	}
+	startLabel := "start URL"
+	if len(seeds) > 1 {
+		startLabel = "start URLs"
+	}
+	if config.ParseSitemap && !startOnly {
+		fmt.Fprintf(os.Stdout, "  %d from sitemap\n", len(seeds))
+	} else if config.ParseSitemap {
+		fmt.Fprintf(os.Stdout, "  %d (%s; sitemap missing or empty)\n", len(seeds), startLabel)
+	} else {
+		fmt.Fprintf(os.Stdout, "  %d (%s; sitemap parsing disabled)\n", len(seeds), startLabel)
+	}
+
+	// Evaluate every seed against domain, scope path, include/exclude, and robots rules
+	filter, _ := utils.NewURLFilter(config.Include, config.Exclude)
+	counts := make(map[crawler.SkipReason]int)
+	examples := make(map[crawler.SkipReason][]string)
+	for _, seed := range seeds {
+		normalized, err := utils.NormalizeURL(seed)
+		if err != nil {
+			continue
+		}
+		reason := manager.Evaluate(normalized)
+		counts[reason]++
+		if len(examples[reason]) >= dryRunExamples {
+			continue
+		}
+		example := normalized
+		if allowed, rule := filter.Match(normalized); rule != "" && (allowed || reason == crawler.SkipFilter) {
+			example = fmt.Sprintf("%s  [%s]", normalized, rule)
+		}
+		examples[reason] = append(examples[reason], example)
+	}
+
+	printGroup := func(label string, reason crawler.SkipReason) {
+		fmt.Fprintf(os.Stdout, "  %-28s %d\n", label, counts[reason])
+		for _, example := range examples[reason] {
+			fmt.Fprintf(os.Stdout, "      %s\n", example)
+		}
+		if counts[reason] > len(examples[reason]) {
+			fmt.Fprintf(os.Stdout, "      … and %d more\n", counts[reason]-len(examples[reason]))
+		}
+	}
+	printGroup("Allowed:", crawler.SkipNone)
+	printGroup("Filtered by include/exclude:", crawler.SkipFilter)
+	printGroup("Outside domain:", crawler.SkipDomain)
+	printGroup("Outside scope path:", crawler.SkipScope)
+	printGroup("Blocked by robots.txt:", crawler.SkipRobots)
+
+	fmt.Fprintf(os.Stdout, "\n")
+	if config.MaxPages >= largeCrawlPages {
+		fmt.Fprintf(os.Stdout, "⚠️  max-pages is %d — this crawl may take a long time and generate heavy traffic\n", config.MaxPages)
+	}
+	if counts[crawler.SkipNone] > config.MaxPages {
+		fmt.Fprintf(os.Stdout, "⚠️  %d allowed seed URLs exceed max-pages (%d); only the first %d will be crawled\n",
+			counts[crawler.SkipNone], config.MaxPages, config.MaxPages)
+	}
+	fmt.Fprintf(os.Stdout, "✓ Crawl would stop after at most %d pages at depth %d\n", config.MaxPages, config.MaxDepth)
+
+	return nil
+}
+
+// formatPatterns joins regex patterns for display
+func formatPatterns(patterns []string) string {
+	if len(patterns) == 0 {
+		return "(none)"
+	}
+	return strings.Join(patterns, ", ")
+}
+
+// formatLogin describes the login step with its field names but not their
+// values
+func formatLogin(config *utils.Config) string {
+	names := make([]string, 0, len(config.LoginFields))
+	for _, field := range config.LoginFields {
+		name, _, _ := strings.Cut(field, "=")
+		names = append(names, strings.TrimSpace(name))
+	}
+	return fmt.Sprintf("%s (fields %s, success %s)", config.LoginURL, strings.Join(names, ", "), config.LoginSuccess)
+}
+
+// formatAuth describes the extra headers, cookies, and basic auth without
+// their secret values
+func formatAuth(config *utils.Config) string {
+	var parts []string
+	for _, line := range config.Headers {
+		name, _, _ := strings.Cut(line, ":")
+		parts = append(parts, "header "+strings.TrimSpace(name))
+	}
+	switch len(config.Cookies) {
+	case 0:
+	case 1:
+		parts = append(parts, "1 cookie")
+	default:
+		parts = append(parts, fmt.Sprintf("%d cookies", len(config.Cookies)))
+	}
+	if user, _, ok := strings.Cut(config.BasicAuth, ":"); ok {
+		parts = append(parts, "basic auth as "+user)
+	}
+	return strings.Join(parts, ", ")
+}
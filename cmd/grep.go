@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/spf13/cobra"
+)
+
+var (
+	grepCrawlDir   string
+	grepDir        string
+	grepRegexp     bool
+	grepIgnoreCase bool
+	grepMissing    bool
+	grepFormat     string
+)
+
+// grepCmd represents the grep command
+var grepCmd = &cobra.Command{
+	Use:          "grep <pattern>",
+	Short:        "Search the HTML saved by a crawl for pages containing or missing a string",
+	SilenceUsage: true,
+	Long: `Search the page bodies a crawl saved with --save-html, for instance to check
+that an analytics tag or a consent banner script is on every page.
+
+--crawl-dir is a crawl directory path, a directory name or unique prefix under
+--dir, or "latest", whose html/ snapshots are searched; it can also be a
+--save-html directory itself. Only HTML pages are searched. With --missing,
+the pages answering 200 that don't contain the pattern are listed instead.
+
+Examples:
+  barracuda grep "G-ABC123XYZ" --missing
+  barracuda grep "gtag\('config', 'G-[A-Z0-9]+'\)" --regexp --crawl-dir example.com_2025-05-01
+  barracuda grep "noindex" -i --crawl-dir html --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGrep,
+}
+
+func init() {
+	grepCmd.Flags().StringVar(&grepCrawlDir, "crawl-dir", "latest", "Crawl to search: a crawl directory, a name or unique prefix under --dir, latest, or a --save-html directory")
+	grepCmd.Flags().StringVar(&grepDir, "dir", crawldir.DefaultParent, "Directory containing crawl runs")
+	grepCmd.Flags().BoolVarP(&grepRegexp, "regexp", "E", false, "Treat the pattern as a regular expression (RE2 syntax)")
+	grepCmd.Flags().BoolVarP(&grepIgnoreCase, "ignore-case", "i", false, "Match regardless of letter case")
+	grepCmd.Flags().BoolVar(&grepMissing, "missing", false, "List the pages that don't contain the pattern")
+	grepCmd.Flags().StringVarP(&grepFormat, "format", "f", "text", "Output format: text or json")
+
+	rootCmd.AddCommand(grepCmd)
+}
+
+func runGrep(cmd *cobra.Command, args []string) error {
+	if grepFormat != "text" && grepFormat != "json" {
+		return fmt.Errorf("invalid format %q: use 'text' or 'json'", grepFormat)
+	}
+	dir, err := resolveSnapshotDir(grepDir, grepCrawlDir)
+	if err != nil {
+		return err
+	}
+
+	search, err := crawler.SearchSnapshots(dir, crawler.SnapshotQuery{
+		Pattern:    args[0],
+		Regexp:     grepRegexp,
+		IgnoreCase: grepIgnoreCase,
+		Missing:    grepMissing,
+	})
+	if err != nil {
+		return err
+	}
+
+	if grepFormat == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(search)
+	}
+
+	for _, match := range search.Matches {
+		if grepMissing {
+			fmt.Fprintln(os.Stdout, match.URL)
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "%s (%d)\n", match.URL, match.Count)
+		for _, snippet := range match.Snippets {
+			fmt.Fprintf(os.Stdout, "    %s\n", snippet)
+		}
+	}
+	verb := "Found in"
+	if grepMissing {
+		verb = "Missing from"
+	}
+	fmt.Fprintf(os.Stderr, "%s %d of %d pages\n", verb, len(search.Matches), search.Searched)
+	return nil
+}
+
+// resolveSnapshotDir accepts a directory of HTML snapshots, or a crawl
+// directory reference (see resolveCrawlDir) whose snapshots are in html/
+func resolveSnapshotDir(parent, ref string) (string, error) {
+	if _, err := os.Stat(filepath.Join(ref, crawler.SnapshotIndexFile)); err == nil {
+		return ref, nil
+	}
+	dir, err := resolveCrawlDir(parent, ref)
+	if err != nil {
+		return "", err
+	}
+	htmlDir := filepath.Join(dir, crawldir.HTMLDir)
+	if _, err := os.Stat(filepath.Join(htmlDir, crawler.SnapshotIndexFile)); err == nil {
+		return htmlDir, nil
+	}
+	if _, err := os.Stat(htmlDir + ".tar.age"); err == nil {
+		return "", fmt.Errorf("the HTML saved in %s is encrypted; run barracuda decrypt %s.tar.age first", dir, htmlDir)
+	}
+	return "", fmt.Errorf("no saved HTML in %s; crawl with --save-html to search page bodies", dir)
+}
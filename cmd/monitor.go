@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/notify"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// monitorConcurrency limits how many URLs are checked at once
+const monitorConcurrency = 4
+
+var (
+	monitorURLsFile  string
+	monitorInterval  time.Duration
+	monitorSlow      time.Duration
+	monitorTimeout   time.Duration
+	monitorUserAgent string
+	monitorRetries   int
+	monitorWebhook   string
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Repeatedly check a small set of critical URLs and alert on changes",
+	Long: `Check a list of critical URLs on an interval and alert when one changes:
+its status code, a failed request, a missing title, a slow response, or a new
+error from the SEO checks.
+
+The first check sets the baseline; only URLs that are already down, untitled,
+or slow alert on it. Alerts go to --webhook, or to the notifications section
+of the config file (--config or ./barracuda.yaml) when --webhook is not set.
+
+The URLs file lists one URL per line; blank lines and # comments are skipped.
+
+Example:
+  barracuda monitor --urls-file critical.txt --interval 5m --webhook https://hooks.example.com/barracuda`,
+	RunE: runMonitor,
+}
+
+func init() {
+	monitorCmd.Flags().StringVar(&monitorURLsFile, "urls-file", "", "File of URLs to monitor, one per line (required)")
+	monitorCmd.Flags().DurationVar(&monitorInterval, "interval", 5*time.Minute, "Time between checks")
+	monitorCmd.Flags().DurationVar(&monitorSlow, "slow", 2*time.Second, "Alert when a response takes longer than this")
+	monitorCmd.Flags().DurationVar(&monitorTimeout, "timeout", 30*time.Second, "HTTP request timeout")
+	monitorCmd.Flags().StringVar(&monitorUserAgent, "user-agent", "barracuda/1.0.0", "User agent string")
+	monitorCmd.Flags().IntVar(&monitorRetries, "retries", 1, "Retry a failed request this many times before reporting it")
+	monitorCmd.Flags().StringVar(&monitorWebhook, "webhook", "", "Webhook URL for alerts (default: notifications.webhook_url from the config file)")
+	_ = monitorCmd.MarkFlagRequired("urls-file")
+
+	rootCmd.AddCommand(monitorCmd)
+}
+
+// monitorState is what a check found for one URL, kept to compare with the
+// next check
+type monitorState struct {
+	statusCode int
+	err        string
+	hasTitle   bool
+	slow       bool
+	// errors holds error-severity issues from the SEO checks by fingerprint
+	errors map[string]models.Issue
+}
+
+// monitorCoveredIssues are issue types that already have their own change
+// (status code and title), so they aren't reported twice
+var monitorCoveredIssues = map[models.IssueType]bool{
+	models.IssueBrokenLink:   true,
+	models.IssueMissingTitle: true,
+}
+
+func runMonitor(cmd *cobra.Command, args []string) error {
+	if monitorInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	f, err := os.Open(monitorURLsFile)
+	if err != nil {
+		return fmt.Errorf("failed to open URLs file: %w", err)
+	}
+	urls, err := readURLList(f, monitorURLsFile)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid URL %q in %s", u, monitorURLsFile)
+		}
+	}
+
+	var notifications utils.NotificationFileConfig
+	if monitorWebhook != "" {
+		notifications.WebhookURL = monitorWebhook
+	} else {
+		fc, _, err := loadFileConfig()
+		if err != nil {
+			return err
+		}
+		if fc != nil {
+			notifications = fc.Notifications
+		}
+	}
+
+	if err := initLogging(); err != nil {
+		return err
+	}
+	defer utils.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// --quiet leaves only errors on the terminal
+	out := io.Writer(os.Stdout)
+	if quiet {
+		out = io.Discard
+	}
+
+	fmt.Fprintf(out, "👀 Monitoring %d URLs every %s\n", len(urls), monitorInterval)
+	if len(notify.Notifiers(notifications)) == 0 {
+		fmt.Fprintf(out, "💡 No --webhook or notifications configured; changes are only printed\n")
+	}
+
+	fetcher := crawler.NewFetcher(monitorTimeout, monitorUserAgent)
+	states := make(map[string]*monitorState, len(urls))
+	for {
+		changed := runMonitorCheck(ctx, out, fetcher, urls, states)
+		if len(changed) > 0 {
+			if err := notify.Send(notifications, notify.NewMonitorAlert(changed)); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+			}
+		}
+
+		timer := time.NewTimer(monitorInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			fmt.Fprintf(out, "\n👋 Monitor stopped\n")
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// runMonitorCheck checks every URL once, prints the outcome, and returns the
+// URLs that changed since the previous check. states is updated in place.
+func runMonitorCheck(ctx context.Context, out io.Writer, fetcher *crawler.Fetcher, urls []string, states map[string]*monitorState) []notify.URLCheck {
+	pages := make([]*models.PageResult, len(urls))
+	var checks errgroup.Group
+	checks.SetLimit(monitorConcurrency)
+	for i, u := range urls {
+		i, u := i, u
+		checks.Go(func() error {
+			if ctx.Err() == nil {
+				pages[i] = fetcher.FetchPage(u, monitorRetries)
+			}
+			return nil
+		})
+	}
+	_ = checks.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	fmt.Fprintf(out, "🔍 Check at %s\n", time.Now().Format("15:04:05"))
+	var changed []notify.URLCheck
+	for i, u := range urls {
+		page := pages[i]
+		current := newMonitorState(page)
+		previous, seen := states[u]
+		if !seen {
+			// Compare the first check against a titled page with the same
+			// SEO errors, so only outages, missing titles, and slow responses
+			// alert at startup
+			previous = &monitorState{hasTitle: true, errors: current.errors}
+		}
+		states[u] = current
+
+		if current.err != "" {
+			fmt.Fprintf(out, "  ❌ %s: %s\n", u, current.err)
+		} else {
+			icon := "✓"
+			switch {
+			case current.statusCode >= 400:
+				icon = "❌"
+			case !current.hasTitle || current.slow:
+				icon = "⚠️ "
+			}
+			fmt.Fprintf(out, "  %s %d %5d ms  %s\n", icon, current.statusCode, page.ResponseTime, u)
+		}
+
+		changes := monitorChanges(previous, current, page.ResponseTime)
+		for _, change := range changes {
+			fmt.Fprintf(out, "     → %s\n", change)
+		}
+		if len(changes) > 0 {
+			changed = append(changed, notify.URLCheck{
+				URL:          u,
+				StatusCode:   current.statusCode,
+				ResponseTime: page.ResponseTime,
+				HasTitle:     current.hasTitle,
+				Error:        current.err,
+				Changes:      changes,
+			})
+		}
+	}
+	return changed
+}
+
+// newMonitorState summarizes a fetched page, running the analyzer's per-page
+// checks on it
+func newMonitorState(page *models.PageResult) *monitorState {
+	state := &monitorState{
+		statusCode: page.StatusCode,
+		hasTitle:   page.Title != "",
+		slow:       time.Duration(page.ResponseTime)*time.Millisecond > monitorSlow,
+		errors:     make(map[string]models.Issue),
+	}
+	// The fetcher also sets Error for HTTP error statuses; only keep it when
+	// there was no response at all
+	if page.StatusCode == 0 {
+		state.err = page.Error
+	}
+	if page.Error != "" || page.StatusCode >= 400 {
+		// Not a page; the status or error says it all
+		return state
+	}
+	for _, issue := range analyzer.PageIssues(page) {
+		if issue.Severity == models.SeverityError && !monitorCoveredIssues[issue.Type] {
+			state.errors[issue.Fingerprint()] = issue
+		}
+	}
+	return state
+}
+
+// monitorChanges describes how a URL changed between two checks. A previous
+// state with no status code and no error is the baseline for a first check.
+func monitorChanges(previous, current *monitorState, responseTime int64) []string {
+	var changes []string
+
+	firstCheck := previous.statusCode == 0 && previous.err == ""
+	wasDown := previous.err != "" || previous.statusCode >= 400
+	switch {
+	case current.err != "" && previous.err == "":
+		// Nothing else is known about the page
+		return []string{"request failing"}
+	case current.err != "":
+		return nil
+	case previous.err != "":
+		changes = append(changes, fmt.Sprintf("request succeeding again (status %d)", current.statusCode))
+	case firstCheck:
+		if current.statusCode >= 400 {
+			changes = append(changes, fmt.Sprintf("status %d", current.statusCode))
+		}
+	case current.statusCode != previous.statusCode:
+		changes = append(changes, fmt.Sprintf("status %d → %d", previous.statusCode, current.statusCode))
+	}
+
+	if current.statusCode >= 400 {
+		return changes
+	}
+
+	if current.slow && !previous.slow {
+		changes = append(changes, fmt.Sprintf("slow response: %d ms (over %s)", responseTime, monitorSlow))
+	} else if !current.slow && previous.slow {
+		changes = append(changes, fmt.Sprintf("response time back to normal: %d ms", responseTime))
+	}
+
+	// Title and SEO checks only ran on a working page, so after an outage
+	// this check becomes the new baseline for them
+	if wasDown {
+		return changes
+	}
+
+	if !current.hasTitle && previous.hasTitle {
+		changes = append(changes, "title missing")
+	} else if current.hasTitle && !previous.hasTitle {
+		changes = append(changes, "title restored")
+	}
+
+	var added, fixed []string
+	for fingerprint, issue := range current.errors {
+		if _, ok := previous.errors[fingerprint]; !ok {
+			added = append(added, "new error: "+issue.Message)
+		}
+	}
+	for fingerprint, issue := range previous.errors {
+		if _, ok := current.errors[fingerprint]; !ok {
+			fixed = append(fixed, "resolved: "+issue.Message)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(fixed)
+	changes = append(changes, added...)
+	return append(changes, fixed...)
+}
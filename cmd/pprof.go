@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var pprofAddr string
+
+// addPprofFlag registers --pprof on a long-running command
+func addPprofFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&pprofAddr, "pprof", "", "Serve net/http/pprof profiles on this address (e.g. localhost:6060)")
+}
+
+// startPprof serves runtime profiles on --pprof, if set, until the process
+// exits. The handlers get their own mux so they are never exposed on the
+// dashboard or API port.
+func startPprof() error {
+	if pprofAddr == "" {
+		return nil
+	}
+
+	// Listen up front so a bad or busy address fails the command
+	listener, err := net.Listen("tcp", pprofAddr)
+	if err != nil {
+		return fmt.Errorf("failed to start pprof server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  pprof server stopped: %v\n", err)
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "🔬 Profiling at http://%s/debug/pprof/\n", listener.Addr())
+	return nil
+}
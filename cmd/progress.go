@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// progressPrinter shows a single updating progress line while a crawl runs.
+// It is used when log output goes to a file, so the terminal stays readable.
+type progressPrinter struct {
+	mu      sync.Mutex
+	out     io.Writer
+	enabled bool
+	lastLen int
+}
+
+// newProgressPrinter returns a printer writing to out. It only prints when
+// enabled is true and out is a terminal.
+func newProgressPrinter(out *os.File, enabled bool) *progressPrinter {
+	return &progressPrinter{
+		out:     out,
+		enabled: enabled && isTerminal(out),
+	}
+}
+
+// Update matches crawler.PageHook and is safe for concurrent use
+func (p *progressPrinter) Update(page *models.PageResult, totalPages int) {
+	if !p.enabled {
+		return
+	}
+
+	line := fmt.Sprintf("🔍 Crawled %d pages", totalPages)
+	if page != nil {
+		line = fmt.Sprintf("%s · %s", line, truncate(page.URL, 60))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.write(line)
+}
+
+// Done clears the progress line. It matches crawler.CompleteHook.
+func (p *progressPrinter) Done([]*models.PageResult, error) {
+	if !p.enabled {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.write("")
+}
+
+// Pause reports that the crawl paused outside its active hours, on the
+// progress line or, when that is off, as a line of its own on fallback
+func (p *progressPrinter) Pause(fallback io.Writer, resumeAt time.Time) {
+	line := fmt.Sprintf("⏸️  Outside active hours; resuming at %s", resumeAt.Format("Mon 15:04"))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.enabled {
+		p.write(line)
+		return
+	}
+	fmt.Fprintln(fallback, line)
+}
+
+// write replaces the current line, padding over any leftover characters
+func (p *progressPrinter) write(line string) {
+	pad := p.lastLen - len(line)
+	if pad < 0 {
+		pad = 0
+	}
+	fmt.Fprintf(p.out, "\r%s%*s\r%s", line, pad, "", line)
+	p.lastLen = len(line)
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// truncate shortens s to at most n characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
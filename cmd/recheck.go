@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// recheckConcurrency limits how many URLs are fetched at once
+const recheckConcurrency = 8
+
+var (
+	recheckIssues       string
+	recheckDir          string
+	recheckTypes        []string
+	recheckOutput       string
+	recheckTimeout      time.Duration
+	recheckUserAgent    string
+	recheckRetries      int
+	recheckImageTimeout time.Duration
+)
+
+// recheckSkippedTypes can't be verified by fetching a single page: they
+// depend on the crawl's link structure, on rendering, on the page's mobile
+// version, or on the whole site
+var recheckSkippedTypes = map[models.IssueType]bool{
+	models.IssueDeepPage:         true,
+	models.IssueJSErrors:         true,
+	models.IssueSiteNoindex:      true,
+	models.IssueRobotsDisallow:   true,
+	models.IssueStagingURL:       true,
+	models.IssuePlaceholderText:  true,
+	models.IssueNoindexConflict:  true,
+	models.IssueExactAnchor:      true,
+	models.IssueCanonicalChain:   true,
+	models.IssuePagination:       true,
+	models.IssueDuplicateContent: true,
+	models.IssueNearDuplicate:    true,
+	models.IssueBrokenAnchor:     true,
+	models.IssueSoft404:          true,
+	models.IssueUnhelpful404:     true,
+	models.IssueMobileParity:     true,
+	models.IssueMissingViewport:  true,
+}
+
+// recheckImageTypes need the image checker, which requests every image
+var recheckImageTypes = map[models.IssueType]bool{
+	models.IssueLargeImage:      true,
+	models.IssueMissingImageAlt: true,
+	models.IssueShortCacheTTL:   true,
+	models.IssueImageSavings:    true,
+}
+
+var recheckCmd = &cobra.Command{
+	Use:          "recheck",
+	Short:        "Re-fetch the pages behind saved issues and mark which are fixed",
+	SilenceUsage: true,
+	Long: `Re-fetch only the URLs behind the selected issues of a saved issues file and
+run the page checks on them again, without a full crawl. Each selected issue is
+marked "fixed" when the page no longer has it, or "open" when it still does.
+Issues of the selected types that the recheck finds for the first time are
+added as open. Issues on URLs that can't be fetched are left unmarked.
+
+--issues is an issues.json file, or a crawl directory, a directory name or
+unique prefix under --dir, or "latest". The updated issues are written to
+--output, by default issues-rechecked.json next to the input.
+
+Click depth, JavaScript errors, and the pre-launch checks need a full crawl and
+are never rechecked.`,
+	Example: `  barracuda recheck --issues latest --types broken_link,redirect_chain
+  barracuda recheck --issues crawls/example.com_2025-01-01_09-00-00/issues.json -o issues.json`,
+	Args: cobra.NoArgs,
+	RunE: runRecheck,
+}
+
+func init() {
+	recheckCmd.Flags().StringVar(&recheckIssues, "issues", "", "Issues file or crawl directory to recheck (required)")
+	recheckCmd.Flags().StringVar(&recheckDir, "dir", crawldir.DefaultParent, "Directory containing crawl runs")
+	recheckCmd.Flags().StringSliceVar(&recheckTypes, "types", nil, "Issue types to recheck, e.g. broken_link,redirect_chain (default: every type that can be rechecked)")
+	recheckCmd.Flags().StringVarP(&recheckOutput, "output", "o", "", "Updated issues file (default: issues-rechecked.json next to the input)")
+	recheckCmd.Flags().DurationVar(&recheckTimeout, "timeout", 30*time.Second, "HTTP request timeout")
+	recheckCmd.Flags().StringVar(&recheckUserAgent, "user-agent", "barracuda/1.0.0", "User agent string")
+	recheckCmd.Flags().IntVar(&recheckRetries, "retries", 1, "Retry a failed request this many times before giving up on the URL")
+	recheckCmd.Flags().DurationVar(&recheckImageTimeout, "image-timeout", 10*time.Second, "Timeout for each image size request")
+	_ = recheckCmd.MarkFlagRequired("issues")
+
+	rootCmd.AddCommand(recheckCmd)
+}
+
+func runRecheck(cmd *cobra.Command, args []string) error {
+	issuesPath, err := resolveIssuesFile(recheckIssues)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(issuesPath)
+	if err != nil {
+		return fmt.Errorf("failed to read issues file: %w", err)
+	}
+	var issues []models.Issue
+	if err := json.Unmarshal(data, &issues); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", issuesPath, err)
+	}
+
+	types, err := recheckTypeFilter(recheckTypes)
+	if err != nil {
+		return err
+	}
+
+	// The URLs behind the selected issues, in first-seen order
+	var urls []string
+	seenURLs := make(map[string]bool)
+	selected := make(map[string]bool) // Fingerprints of the rechecked issues
+	for _, issue := range issues {
+		if !types(issue.Type) {
+			continue
+		}
+		if !seenURLs[issue.URL] {
+			seenURLs[issue.URL] = true
+			urls = append(urls, issue.URL)
+		}
+		selected[issue.Fingerprint()] = true
+	}
+
+	out := io.Writer(os.Stdout)
+	if quiet {
+		out = io.Discard
+	}
+	if len(urls) == 0 {
+		fmt.Fprintf(out, "✓ No issues of the selected types in %s\n", issuesPath)
+		return nil
+	}
+	fmt.Fprintf(out, "🔁 Rechecking %d issues on %d URLs\n", len(selected), len(urls))
+
+	// Fetch concurrently, then analyze in order so image and asset issues,
+	// which are reported once per asset, land on the same page every run
+	fetcher := crawler.NewFetcher(recheckTimeout, recheckUserAgent)
+	pages := make([]*models.PageResult, len(urls))
+	var fetches errgroup.Group
+	fetches.SetLimit(recheckConcurrency)
+	for i, u := range urls {
+		i, u := i, u
+		fetches.Go(func() error {
+			pages[i] = fetcher.FetchPage(u, recheckRetries)
+			return nil
+		})
+	}
+	_ = fetches.Wait()
+
+	analysis := analyzer.NewIncremental()
+	for issueType := range recheckImageTypes {
+		if types(issueType) {
+			analysis = analyzer.NewIncrementalWithImages(recheckImageTimeout)
+			break
+		}
+	}
+
+	now := time.Now().UTC()
+	checked := make(map[string]bool) // URLs that were fetched
+	found := make(map[string]bool)   // Fingerprints of issues found again
+	var added []models.Issue
+	unreachable := 0
+	for i, page := range pages {
+		if page.StatusCode == 0 {
+			fmt.Fprintf(out, "  ⚠️  %s: %s\n", urls[i], page.Error)
+			unreachable++
+			continue
+		}
+		checked[urls[i]] = true
+		for _, issue := range analysis.Add(page) {
+			if !types(issue.Type) {
+				continue
+			}
+			fingerprint := issue.Fingerprint()
+			found[fingerprint] = true
+			if !selected[fingerprint] {
+				issue.Status = models.IssueStatusOpen
+				issue.RecheckedAt = &now
+				added = append(added, issue)
+			}
+		}
+	}
+
+	fixed, open := 0, 0
+	for i := range issues {
+		issue := &issues[i]
+		if !types(issue.Type) || !checked[issue.URL] {
+			continue
+		}
+		issue.RecheckedAt = &now
+		if found[issue.Fingerprint()] {
+			issue.Status = models.IssueStatusOpen
+			open++
+			continue
+		}
+		issue.Status = models.IssueStatusFixed
+		fixed++
+		fmt.Fprintf(out, "  ✅ %s  %s\n", analyzer.IssueTypeLabel(issue.Type), issue.URL)
+	}
+	for _, issue := range added {
+		fmt.Fprintf(out, "  🆕 %s  %s\n", analyzer.IssueTypeLabel(issue.Type), issue.URL)
+	}
+	issues = append(issues, added...)
+	analyzer.SortIssues(issues)
+
+	outputPath := recheckOutput
+	if outputPath == "" {
+		outputPath = filepath.Join(filepath.Dir(issuesPath), "issues-rechecked.json")
+	}
+	if err := writeJSONFile(outputPath, issues); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\n✅ %d fixed, ❌ %d still open", fixed, open)
+	if len(added) > 0 {
+		fmt.Fprintf(out, ", 🆕 %d new", len(added))
+	}
+	if unreachable > 0 {
+		fmt.Fprintf(out, ", ⚠️  %d URLs unreachable", unreachable)
+	}
+	fmt.Fprintf(out, "\n📁 Updated issues written to %s\n", outputPath)
+	return nil
+}
+
+// resolveIssuesFile returns the issues file of an issues file path or a crawl
+// directory reference
+func resolveIssuesFile(ref string) (string, error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		return ref, nil
+	}
+	dir, err := resolveCrawlDir(recheckDir, ref)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, crawldir.IssuesFile), nil
+}
+
+// recheckTypeFilter validates the --types names and returns whether an issue
+// type is selected. With no names, every type that can be rechecked is.
+func recheckTypeFilter(names []string) (func(models.IssueType) bool, error) {
+	types := make(map[models.IssueType]bool)
+	for _, name := range names {
+		issueType := models.IssueType(strings.TrimSpace(name))
+		if issueType == "" {
+			continue
+		}
+		if recheckSkippedTypes[issueType] {
+			return nil, fmt.Errorf("%s issues can't be rechecked without a full crawl", issueType)
+		}
+		types[issueType] = true
+	}
+	if len(types) > 0 {
+		return func(issueType models.IssueType) bool { return types[issueType] }, nil
+	}
+	return func(issueType models.IssueType) bool { return !recheckSkippedTypes[issueType] }, nil
+}
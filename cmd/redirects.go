@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/exporter"
+	"github.com/dillonlara115/barracuda/internal/redirectmap"
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	redirectsDir      string
+	redirectsOld      string
+	redirectsNew      string
+	redirectsMapping  string
+	redirectsFormat   string
+	redirectsOutput   string
+	redirectsStatus   int
+	redirectsMinScore float64
+)
+
+// redirectsCmd represents the redirects command
+var redirectsCmd = &cobra.Command{
+	Use:          "redirects",
+	Short:        "Generate a redirect map for a site migration",
+	SilenceUsage: true,
+	Long: `Match every page of an old crawl that the new crawl no longer serves to the
+best page of the new crawl, and write the result as a redirect map.
+
+Pages are matched, in order, by an entry in --mapping, the same path on the new
+site, an identical response body, a unique matching slug (the last path
+segment), and finally by the similarity of their slugs, titles, and headings and
+meta descriptions. Matches scoring below --min-score are left unmatched for
+review.
+
+--old and --new are crawl directories, directory names or unique prefixes under
+--dir, "latest", or results files (JSON or CSV). A mapping file of "old,new"
+CSV rows can be used with or instead of --new; its old entries are URLs or paths.
+
+Formats:
+  csv         Old URL, New URL, Status, Match, and Score, for review
+  nginx       location blocks for the old site's server block
+  apache      RedirectMatch rules for the old site's config or .htaccess
+  cloudflare  A Bulk Redirects list to import in the Cloudflare dashboard`,
+	Example: `  barracuda redirects --old example.com_2025-01-01 --new staging.example.com_2025-02-01
+  barracuda redirects --old old-results.json --new latest --format nginx -o redirects.conf
+  barracuda redirects --mapping moved.csv --format apache`,
+	Args: cobra.NoArgs,
+	RunE: runRedirects,
+}
+
+func init() {
+	redirectsCmd.Flags().StringVar(&redirectsDir, "dir", crawldir.DefaultParent, "Directory containing crawl runs")
+	redirectsCmd.Flags().StringVar(&redirectsOld, "old", "", "Crawl of the old site")
+	redirectsCmd.Flags().StringVar(&redirectsNew, "new", "", "Crawl of the new site")
+	redirectsCmd.Flags().StringVar(&redirectsMapping, "mapping", "", "CSV file of old,new URL pairs that take precedence over matching")
+	redirectsCmd.Flags().StringVarP(&redirectsFormat, "format", "f", "csv", "Output format: "+strings.Join(redirectmap.Formats, ", "))
+	redirectsCmd.Flags().StringVarP(&redirectsOutput, "output", "o", "", "Output file path (default: stdout)")
+	redirectsCmd.Flags().IntVar(&redirectsStatus, "status", 301, "HTTP status of the redirects: 301, 302, 307, or 308")
+	redirectsCmd.Flags().Float64Var(&redirectsMinScore, "min-score", redirectmap.DefaultMinScore, "Lowest similarity (0-1) accepted as a match")
+
+	rootCmd.AddCommand(redirectsCmd)
+}
+
+func runRedirects(cmd *cobra.Command, args []string) error {
+	switch redirectsStatus {
+	case 301, 302, 307, 308:
+	default:
+		return fmt.Errorf("invalid --status %d: use 301, 302, 307, or 308", redirectsStatus)
+	}
+	if redirectsMinScore < 0 || redirectsMinScore > 1 {
+		return fmt.Errorf("--min-score must be between 0 and 1")
+	}
+	if redirectsMapping == "" && (redirectsOld == "" || redirectsNew == "") {
+		return fmt.Errorf("--old and --new are required unless --mapping is given")
+	}
+	if !redirectmap.ValidFormat(redirectsFormat) {
+		return fmt.Errorf("invalid --format %q: use %s", redirectsFormat, strings.Join(redirectmap.Formats, ", "))
+	}
+
+	opts := redirectmap.Options{MinScore: redirectsMinScore}
+	if redirectsMapping != "" {
+		f, err := os.Open(redirectsMapping)
+		if err != nil {
+			return fmt.Errorf("failed to open mapping file: %w", err)
+		}
+		opts.Mapping, err = redirectmap.ReadMapping(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", redirectsMapping, err)
+		}
+	}
+
+	var oldResults, newResults []*models.PageResult
+	var err error
+	if redirectsOld != "" {
+		if oldResults, err = loadRedirectResults(redirectsOld); err != nil {
+			return err
+		}
+	}
+	if redirectsNew != "" {
+		if newResults, err = loadRedirectResults(redirectsNew); err != nil {
+			return err
+		}
+	}
+
+	redirects := redirectmap.Build(oldResults, newResults, opts)
+
+	out := io.Writer(os.Stdout)
+	if redirectsOutput != "" {
+		f, err := os.Create(redirectsOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := redirectmap.Write(out, redirects, redirectsFormat, redirectsStatus); err != nil {
+		return fmt.Errorf("failed to write redirect map: %w", err)
+	}
+
+	unmatched := 0
+	for _, r := range redirects {
+		if !r.Matched() {
+			unmatched++
+		}
+	}
+	// Keep stdout clean for the map itself
+	fmt.Fprintf(os.Stderr, "✓ %d redirects", len(redirects)-unmatched)
+	if unmatched > 0 {
+		fmt.Fprintf(os.Stderr, ", ⚠️  %d old URLs without a match (see the csv format)", unmatched)
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+	if redirectsOutput != "" {
+		fmt.Fprintf(os.Stderr, "📁 Redirect map written to %s\n", redirectsOutput)
+	}
+	return nil
+}
+
+// loadRedirectResults reads page results from a results file or a crawl
+// directory reference
+func loadRedirectResults(ref string) ([]*models.PageResult, error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		return exporter.ImportResults(ref)
+	}
+	dir, err := resolveCrawlDir(redirectsDir, ref)
+	if err != nil {
+		return nil, err
+	}
+	_, _, _, results, err := loadCrawl(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%s has no page results", dir)
+	}
+	return results, nil
+}
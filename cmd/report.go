@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/exporter"
+	"github.com/dillonlara115/barracuda/internal/render"
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportDir         string
+	reportFormat      string
+	reportOutput      string
+	reportSections    []string
+	reportTitle       string
+	reportBrandName   string
+	reportLogo        string
+	reportBrandColor  string
+	reportMaxExamples int
+	reportMaxPages    int
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:          "report <crawl>",
+	Short:        "Generate a client-ready HTML, PDF, or Markdown report from a crawl",
+	SilenceUsage: true,
+	Long: `Generate a report from a saved crawl directory.
+
+<crawl> is a crawl directory path, a directory name or unique prefix under --dir,
+or "latest". The report includes an executive summary with a health score, issues
+grouped by type with recommendations, a pages table, and performance figures.
+
+PDF output prints the HTML report with a local Chrome or Chromium browser
+(set BARRACUDA_CHROME to its path if it is not found automatically).
+
+Examples:
+  barracuda report latest
+  barracuda report latest --format pdf --brand-name "Acme SEO" --logo logo.png
+  barracuda report crawls/example.com_2024-05-01_09-00-00 --format md --sections executive,issues`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportDir, "dir", crawldir.DefaultParent, "Directory containing crawl runs")
+	reportCmd.Flags().StringVarP(&reportFormat, "format", "f", "html", "Report format (html, pdf, or md)")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Output file path (default: report.<format> in the crawl directory)")
+	reportCmd.Flags().StringSliceVar(&reportSections, "sections", nil, "Sections to include: executive, issues, pages, performance (default: all)")
+	reportCmd.Flags().StringVar(&reportTitle, "title", "", "Report title (default: \"SEO Audit: <site>\")")
+	reportCmd.Flags().StringVar(&reportBrandName, "brand-name", "", "Agency or company name shown on the report")
+	reportCmd.Flags().StringVar(&reportLogo, "logo", "", "Logo image file or URL shown in the report header")
+	reportCmd.Flags().StringVar(&reportBrandColor, "brand-color", "", "Accent color for headings, e.g. \"#ff6600\"")
+	reportCmd.Flags().IntVar(&reportMaxExamples, "max-examples", 10, "Example URLs listed per issue type")
+	reportCmd.Flags().IntVar(&reportMaxPages, "max-pages", 100, "Rows in the pages table")
+
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	format := strings.ToLower(reportFormat)
+	if format == "markdown" {
+		format = "md"
+	}
+	if format != "html" && format != "pdf" && format != "md" {
+		return fmt.Errorf("invalid report format %q: use 'html', 'pdf', or 'md'", reportFormat)
+	}
+
+	sections, err := exporter.ParseSections(reportSections)
+	if err != nil {
+		return err
+	}
+
+	dir, err := resolveCrawlDir(reportDir, args[0])
+	if err != nil {
+		return err
+	}
+
+	site, crawledAt, summary, results, err := loadCrawl(dir)
+	if err != nil {
+		return err
+	}
+
+	logo, err := logoURI(reportLogo)
+	if err != nil {
+		return err
+	}
+
+	report := exporter.NewReport(site, crawledAt, summary, results, exporter.ReportOptions{
+		Title:       reportTitle,
+		Sections:    sections,
+		MaxExamples: reportMaxExamples,
+		MaxPages:    reportMaxPages,
+		Branding: exporter.Branding{
+			Name:    reportBrandName,
+			LogoURI: logo,
+			Color:   reportBrandColor,
+		},
+	})
+
+	output := reportOutput
+	if output == "" {
+		output = filepath.Join(dir, "report."+format)
+	}
+
+	switch format {
+	case "md":
+		err = writeReportFile(output, func(f *os.File) error { return exporter.WriteMarkdownReport(f, report) })
+	case "html":
+		err = writeReportFile(output, func(f *os.File) error { return exporter.WriteHTMLReport(f, report) })
+	case "pdf":
+		err = writePDFReport(cmd.Context(), output, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "✓ Report written to %s\n", output)
+	return nil
+}
+
+// resolveCrawlDir accepts a crawl directory path, a run name or prefix under
+// parent, or "latest"
+func resolveCrawlDir(parent, ref string) (string, error) {
+	if info, err := os.Stat(ref); err == nil && info.IsDir() {
+		return ref, nil
+	}
+
+	if ref == "latest" {
+		runs, err := crawldir.List(parent)
+		if err != nil {
+			return "", err
+		}
+		if len(runs) == 0 {
+			return "", fmt.Errorf("no crawls found in %s/", parent)
+		}
+		return runs[0].Path, nil
+	}
+
+	run, err := crawldir.Find(parent, ref)
+	if err != nil {
+		return "", err
+	}
+	return run.Path, nil
+}
+
+// loadCrawl reads the site URL, crawl time, summary, and page results from a
+// crawl directory. summary.json is only used on its own when no results file exists.
+func loadCrawl(dir string) (string, time.Time, *analyzer.Summary, []*models.PageResult, error) {
+	var site string
+	var crawledAt time.Time
+	if meta, err := crawldir.ReadMetadata(dir); err == nil {
+		site = meta.URL
+		crawledAt = meta.StartedAt
+	}
+
+	var results []*models.PageResult
+	for _, format := range []string{"json", "jsonl", "csv"} {
+		path := filepath.Join(dir, crawldir.ResultsFile(format))
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		loaded, err := exporter.ImportResults(path)
+		if err != nil {
+			return "", time.Time{}, nil, nil, err
+		}
+		results = loaded
+		break
+	}
+
+	var stored *analyzer.Summary
+	if data, err := os.ReadFile(filepath.Join(dir, crawldir.SummaryFile)); err == nil {
+		stored = &analyzer.Summary{}
+		if err := json.Unmarshal(data, stored); err != nil {
+			return "", time.Time{}, nil, nil, fmt.Errorf("failed to parse %s: %w", crawldir.SummaryFile, err)
+		}
+	}
+
+	// Re-analyze results so issue messages follow --lang, keeping any image
+	// issues from the stored summary since checking image sizes needs network access
+	var summary *analyzer.Summary
+	switch {
+	case results != nil:
+		summary = analyzer.Analyze(results)
+		if stored != nil {
+			for _, issue := range stored.Issues {
+				if issue.Type == analyzer.IssueLargeImage || issue.Type == analyzer.IssueMissingImageAlt {
+					summary.Issues = append(summary.Issues, issue)
+					summary.IssuesByType[issue.Type]++
+				}
+			}
+			analyzer.SortIssues(summary.Issues)
+			summary.TotalIssues = len(summary.Issues)
+		}
+	case stored != nil:
+		summary = stored
+	default:
+		return "", time.Time{}, nil, nil, fmt.Errorf("%s has no %s or results file", dir, crawldir.SummaryFile)
+	}
+
+	if site == "" && len(results) > 0 {
+		site = results[0].URL
+	}
+	if host := hostnameOf(site); host != "" {
+		site = host
+	}
+
+	return site, crawledAt, summary, results, nil
+}
+
+// logoURI returns a URL logo unchanged and embeds a local image as a data URI
+// so the report stays self-contained
+func logoURI(logo string) (string, error) {
+	if logo == "" || strings.HasPrefix(logo, "http://") || strings.HasPrefix(logo, "https://") || strings.HasPrefix(logo, "data:") {
+		return logo, nil
+	}
+
+	data, err := os.ReadFile(logo)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logo: %w", err)
+	}
+
+	mimeType := http.DetectContentType(data)
+	if strings.HasSuffix(strings.ToLower(logo), ".svg") {
+		mimeType = "image/svg+xml"
+	}
+	if !strings.HasPrefix(mimeType, "image/") {
+		return "", fmt.Errorf("logo %s is not an image (%s)", logo, mimeType)
+	}
+
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// writeReportFile creates path and renders into it with write
+func writeReportFile(path string, write func(*os.File) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer file.Close()
+
+	return write(file)
+}
+
+// writePDFReport renders the HTML report to a temporary file and prints it to PDF
+func writePDFReport(ctx context.Context, path string, report *exporter.Report) error {
+	tmp, err := os.CreateTemp("", "barracuda-report-*.html")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := exporter.WriteHTMLReport(tmp, report); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	return render.PrintPDF(ctx, tmp.Name(), path)
+}
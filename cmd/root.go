@@ -4,11 +4,25 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/internal/update"
+	"github.com/dillonlara115/barracuda/internal/utils"
 	"github.com/spf13/cobra"
 )
 
+// Version is the barracuda release version. Release builds override it with
+// -ldflags "-X github.com/dillonlara115/barracuda/cmd.Version=<version>".
+var Version = "1.0.0"
+
 var (
-	debug bool
+	debug       bool
+	configFile  string
+	profileName string
+	logFormat   string
+	logFile     string
+	quiet       bool
+	summaryOnly bool
+	lang        string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -20,7 +34,10 @@ It recursively crawls websites, extracts key SEO elements like titles, meta desc
 headings, and links, and exports the data to CSV or JSON format.
 
 When run without arguments, barracuda starts in interactive mode.`,
-	Version: "1.0.0",
+	Version: Version,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return i18n.SetLocale(lang)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// When barracuda is run without subcommands, start interactive crawl
 		displayBanner()
@@ -37,7 +54,25 @@ When run without arguments, barracuda starts in interactive mode.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
+	// Check for a newer release in the background while the command runs.
+	// self-update checks for itself, and the notice would only end up in the
+	// logs of the long-running server commands.
+	newerVersion := func() string { return "" }
+	switch target, _, _ := rootCmd.Find(os.Args[1:]); target {
+	case selfUpdateCmd, serveCmd, apiCmd, scheduleCmd:
+	default:
+		newerVersion = update.Notice(Version)
+	}
+
 	err := rootCmd.Execute()
+
+	// --quiet only prints errors, so the notice stays out of piped output
+	if !quiet {
+		if latest := newerVersion(); latest != "" {
+			fmt.Fprintf(os.Stderr, "\n💡 barracuda %s is available (you have %s). Run 'barracuda self-update' to upgrade.\n", latest, Version)
+		}
+	}
+
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -46,5 +81,28 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "enable debug logging")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Path to config file (default: ./barracuda.yaml if present)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Load a saved interactive-mode profile as the config file")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "", "Log format: console or json (default: console on the terminal, json in log files)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "Write logs to this file; the terminal then only shows warnings, errors, and progress")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print errors")
+	rootCmd.PersistentFlags().BoolVar(&summaryOnly, "summary-only", false, "Skip per-page logging and progress; print only the final analysis")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "summary-only")
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "", "Language for issue messages and the summary (default: $BARRACUDA_LANG or the system locale)")
 }
 
+// initLogging configures the global logger from --debug, --log-format, and --log-file
+func initLogging() error {
+	err := utils.ConfigureLogger(utils.LogOptions{
+		Debug:  debug,
+		Format: logFormat,
+		File:   logFile,
+
+		Quiet:       quiet,
+		SummaryOnly: summaryOnly,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	return nil
+}
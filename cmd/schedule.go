@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawldiff"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/exporter"
+	"github.com/dillonlara115/barracuda/internal/notify"
+	"github.com/dillonlara115/barracuda/internal/scheduler"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleRunNow bool
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run crawls on a cron schedule from the config file",
+	Long: `Run barracuda as a long-lived scheduler. Crawl settings, the cron expression,
+and notification targets are read from the config file (--config or ./barracuda.yaml).
+
+Each run writes results, graph, anchor text, and summary files to a new
+timestamped directory under schedule.output_dir, keeping only the most recent
+schedule.keep runs.
+
+Each run is compared with the previous one. When it has new error-severity
+issues or noticeably fewer indexable pages, the regressions are written to
+alerts.json in its directory and sent to the notification targets as a
+crawl_regression alert. schedule.alerts sets the thresholds.
+
+Example barracuda.yaml:
+
+  crawl:
+    url: https://example.com
+    max_pages: 500
+  schedule:
+    cron: "0 3 * * *"   # every day at 03:00
+    output_dir: crawls
+    keep: 7
+    alerts:
+      new_errors: 1        # alert on this many new error issues (0 disables)
+      indexable_drop: 10   # alert when indexable pages fall this many percent (0 disables)
+  notifications:
+    webhook_url: https://hooks.example.com/barracuda
+    email:
+      smtp_host: smtp.example.com
+      from: barracuda@example.com
+      to: [seo@example.com]`,
+	RunE: runSchedule,
+}
+
+func init() {
+	scheduleCmd.Flags().BoolVar(&scheduleRunNow, "run-now", false, "Run a crawl immediately before waiting for the first scheduled time")
+
+	rootCmd.AddCommand(scheduleCmd)
+}
+
+func runSchedule(cmd *cobra.Command, args []string) error {
+	fc, path, err := loadFileConfig()
+	if err != nil {
+		return err
+	}
+	if fc == nil {
+		return fmt.Errorf("no config file found. Create %s or pass --config", utils.DefaultConfigFile)
+	}
+	if fc.Schedule.Cron == "" {
+		return fmt.Errorf("schedule.cron is required in %s", path)
+	}
+
+	sched, err := scheduler.ParseCron(fc.Schedule.Cron)
+	if err != nil {
+		return fmt.Errorf("invalid schedule.cron: %w", err)
+	}
+
+	config := utils.DefaultConfig()
+	if err := fc.Crawl.ApplyTo(config); err != nil {
+		return fmt.Errorf("invalid config file: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+	if _, err := exporter.Lookup(config.ExportFormat); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	outputDir := fc.Schedule.OutputDir
+	if outputDir == "" {
+		outputDir = crawldir.DefaultParent
+	}
+	keep := fc.Schedule.Keep
+	if keep == 0 {
+		keep = 10
+	}
+
+	if err := initLogging(); err != nil {
+		return err
+	}
+	defer utils.Sync()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// --quiet leaves only errors on the terminal
+	out := io.Writer(os.Stdout)
+	if quiet {
+		out = io.Discard
+	}
+
+	fmt.Fprintf(out, "🕒 Scheduler started for %s (cron: %s)\n", config.StartURL, fc.Schedule.Cron)
+	fmt.Fprintf(out, "📁 Results will be saved under %s/ (keeping last %d runs)\n", outputDir, keep)
+
+	if scheduleRunNow {
+		runScheduledCrawl(out, config, fc, outputDir, keep)
+	}
+
+	for {
+		next := sched.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("cron expression %q never fires", fc.Schedule.Cron)
+		}
+		fmt.Fprintf(out, "⏭  Next crawl at %s\n", next.Format(time.RFC1123))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			fmt.Fprintf(out, "\n👋 Scheduler stopped\n")
+			return nil
+		case <-timer.C:
+		}
+
+		runScheduledCrawl(out, config, fc, outputDir, keep)
+	}
+}
+
+// runScheduledCrawl performs one crawl into a fresh directory, prunes old runs,
+// and sends notifications. Errors are reported but never stop the scheduler.
+func runScheduledCrawl(out io.Writer, base *utils.Config, fc *utils.FileConfig, outputDir string, keep int) {
+	report := &notify.CrawlReport{
+		URL:       base.StartURL,
+		Status:    "succeeded",
+		StartedAt: time.Now(),
+	}
+
+	summary, dir, pages, err := crawlToDir(base, outputDir)
+	report.CompletedAt = time.Now()
+	report.CrawlDir = dir
+	report.TotalPages = pages
+
+	// Compared before pruning, which may remove the previous run
+	var alert *notify.RegressionAlert
+	if err != nil {
+		report.Status = "failed"
+		report.Error = err.Error()
+		fmt.Fprintf(os.Stderr, "❌ Scheduled crawl failed: %v\n", err)
+	} else {
+		report.TotalIssues = summary.TotalIssues
+		report.BySeverity = summary.GetIssueCountBySeverity()
+		fmt.Fprintf(out, "✓ Crawled %d pages, found %d issues → %s\n", pages, summary.TotalIssues, dir)
+		alert = checkRegressions(out, base.StartURL, outputDir, dir, fc.Schedule.Alerts)
+	}
+
+	if domain := hostnameOf(base.StartURL); domain != "" {
+		removed, err := crawldir.Prune(outputDir, domain, keep)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to prune old crawls: %v\n", err)
+		}
+		for _, path := range removed {
+			utils.Info("Removed old crawl directory", utils.NewField("path", path))
+		}
+	}
+
+	if err := notify.Send(fc.Notifications, report); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+	if alert != nil {
+		if err := notify.Send(fc.Notifications, alert); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+	}
+}
+
+// checkRegressions compares the crawl in dir with the previous run of the
+// same site and records any regressions in its alerts.json. It returns the
+// alert to send, or nil when there is nothing to report.
+func checkRegressions(out io.Writer, url, parent, dir string, cfg utils.AlertsFileConfig) *notify.RegressionAlert {
+	thresholds := crawldiff.Thresholds{NewErrors: 1, IndexableDrop: 10, RobotsTxt: true}
+	if cfg.NewErrors != nil {
+		thresholds.NewErrors = *cfg.NewErrors
+	}
+	if cfg.IndexableDrop != nil {
+		thresholds.IndexableDrop = *cfg.IndexableDrop
+	}
+	if cfg.RobotsTxt != nil {
+		thresholds.RobotsTxt = *cfg.RobotsTxt
+	}
+	if thresholds.NewErrors <= 0 && thresholds.IndexableDrop <= 0 && !thresholds.RobotsTxt {
+		return nil
+	}
+
+	run, err := crawldir.Find(parent, filepath.Base(dir))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to compare with the previous crawl: %v\n", err)
+		return nil
+	}
+	prevRun, err := previousRun(parent, run)
+	if err != nil {
+		// The first scheduled run has nothing to compare with
+		utils.Debug("No previous crawl to compare with", utils.NewField("error", err.Error()))
+		return nil
+	}
+	before, err := loadDiffCrawl(prevRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to compare with the previous crawl: %v\n", err)
+		return nil
+	}
+	after, err := loadDiffCrawl(run)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to compare with the previous crawl: %v\n", err)
+		return nil
+	}
+
+	regressions := crawldiff.Compare(before, after).Regressions(thresholds)
+	if len(regressions) == 0 {
+		fmt.Fprintf(out, "✓ No regressions since %s\n", prevRun.Name)
+		return nil
+	}
+
+	alert := notify.NewRegressionAlert(url, dir, prevRun.Name, regressions)
+	for _, regression := range regressions {
+		fmt.Fprintf(out, "🚨 %s since %s\n", regression.Message, prevRun.Name)
+	}
+	if err := writeJSONFile(filepath.Join(dir, crawldir.AlertsFile), alert); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+	}
+	return alert
+}
+
+// crawlToDir runs a crawl and writes results, graph, anchor text, summary,
+// issues, log, and metadata into a new crawl directory under parent
+func crawlToDir(base *utils.Config, parent string) (*analyzer.Summary, string, int, error) {
+	domain := hostnameOf(base.StartURL)
+	if domain == "" {
+		domain = "unknown"
+	}
+
+	dir, err := crawldir.Create(parent, domain, time.Now())
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	config := *base
+	config.ExportPath = filepath.Join(dir, crawldir.ResultsFile(config.ExportFormat))
+	// Keep each run's snapshots with the run instead of overwriting one directory
+	if config.SaveHTMLDir != "" {
+		config.SaveHTMLDir = filepath.Join(dir, crawldir.HTMLDir)
+	}
+
+	closeLog, err := utils.LogToFile(filepath.Join(dir, crawldir.LogFile))
+	if err != nil {
+		return nil, dir, 0, err
+	}
+	defer closeLog()
+
+	meta := newCrawlMetadata(&config)
+	if err := crawldir.WriteMetadata(dir, meta); err != nil {
+		return nil, dir, 0, err
+	}
+
+	manager := crawler.NewManager(&config)
+	progress := newProgressPrinter(os.Stderr, !quiet && !summaryOnly)
+	analysis := newAnalysis(&config)
+	manager.SetAnalyzer(analysis)
+	manager.OnPageCrawled(progress.Update)
+	manager.OnCrawlComplete(progress.Done)
+	results, err := manager.Crawl()
+	if err != nil {
+		finishCrawlMetadata(dir, meta, nil, 0, nil, err)
+		return nil, dir, 0, fmt.Errorf("crawl failed: %w", err)
+	}
+
+	summary := analysis.Summary()
+	skipped := manager.SkipCounts()
+	summary.Skipped = &skipped
+	siteFiles := fetchSiteFiles(manager, "/robots.txt", "/llms.txt")
+	summary.AIVisibility = analyzer.AuditAIVisibility(config.StartURL, siteFiles["/robots.txt"], siteFiles["/llms.txt"])
+	summary.AddIssues(probeNotFound(manager))
+
+	if err := exportResults(results, summary, &config); err != nil {
+		return nil, dir, len(results), fmt.Errorf("export failed: %w", err)
+	}
+	if err := exportLinkGraph(manager.GetLinkGraph(), filepath.Join(dir, crawldir.GraphFile)); err != nil {
+		return nil, dir, len(results), fmt.Errorf("graph export failed: %w", err)
+	}
+	if err := exporter.ExportAnchors(analysis.AnchorTexts(), filepath.Join(dir, crawldir.AnchorsFile)); err != nil {
+		return nil, dir, len(results), fmt.Errorf("anchor text export failed: %w", err)
+	}
+	if err := saveCrawlArtifacts(dir, summary); err != nil {
+		return nil, dir, len(results), err
+	}
+	meta.RobotsTxt = manager.RobotsTxtFile()
+	finishCrawlMetadata(dir, meta, summary, len(results), crawldir.HostStatsFor(results), nil)
+
+	return summary, dir, len(results), nil
+}
+
+// hostnameOf returns the hostname of a URL, or "" if it cannot be parsed
+func hostnameOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
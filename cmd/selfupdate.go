@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/update"
+	"github.com/spf13/cobra"
+)
+
+var (
+	selfUpdateCheckOnly bool
+	selfUpdateForce     bool
+)
+
+// selfUpdateCmd represents the self-update command
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update barracuda to the latest release",
+	Long: `Check GitHub for the latest barracuda release, download the build for this platform,
+verify it against the release's SHA-256 checksums, and replace the running binary.
+
+Set BARRACUDA_NO_UPDATE_CHECK=1 to disable the daily "new version available" notice.`,
+	SilenceUsage: true,
+	RunE:         runSelfUpdate,
+}
+
+func init() {
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheckOnly, "check", false, "Only report whether a newer version is available")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateForce, "force", false, "Reinstall even if already on the latest version")
+
+	rootCmd.AddCommand(selfUpdateCmd)
+}
+
+func runSelfUpdate(cmd *cobra.Command, args []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	fmt.Fprintf(os.Stdout, "🔍 Checking for updates (current version %s)...\n", Version)
+	release, err := update.LatestRelease(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check for updates: %w", err)
+	}
+
+	newer := update.IsNewer(release.Version(), Version)
+	if !newer && !selfUpdateForce {
+		fmt.Fprintf(os.Stdout, "✓ barracuda %s is the latest version\n", Version)
+		return nil
+	}
+
+	if selfUpdateCheckOnly {
+		if newer {
+			fmt.Fprintf(os.Stdout, "💡 barracuda %s is available: %s\n", release.Version(), release.HTMLURL)
+			fmt.Fprintf(os.Stdout, "   Run 'barracuda self-update' to install it\n")
+		}
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate current executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	fmt.Fprintf(os.Stdout, "⬇️  Downloading %s from %s...\n", update.ArchiveName(), release.TagName)
+	if err := update.Apply(ctx, release, executable); err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("permission denied replacing %s; re-run with sudo or reinstall manually: %w", executable, err)
+		}
+		return fmt.Errorf("update failed: %w", err)
+	}
+
+	fmt.Fprintf(os.Stdout, "✓ Checksum verified\n")
+	fmt.Fprintf(os.Stdout, "✓ Updated barracuda %s → %s (%s)\n", Version, release.Version(), executable)
+	return nil
+}
@@ -1,18 +1,25 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/backlinks"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/crawler"
 	"github.com/dillonlara115/barracuda/internal/exporter"
 	"github.com/dillonlara115/barracuda/internal/gsc"
+	"github.com/dillonlara115/barracuda/internal/store"
 	"github.com/dillonlara115/barracuda/pkg/models"
 	"github.com/spf13/cobra"
 )
@@ -25,13 +32,25 @@ var (
 	serveResults string
 	serveGraph   string
 	serveSummary string
+	serveStore   string
+	serveCrawl   string
+	serveHTML    string
+
+	serveBacklinks bool
 )
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Start web server to view crawl results",
 	Long: `Start a local web server to view crawl results in a web interface.
-The server will serve the Svelte frontend and provide API endpoints for results data.`,
+The server will serve the Svelte frontend and provide API endpoints for results data.
+
+Results are read from --results, or from a crawl saved by a self-hosted API
+server when --store is set (the newest crawl unless --crawl names one).
+
+Examples:
+  barracuda serve --results results.json
+  barracuda serve --store sqlite://barracuda.db --crawl 3f2a9c1e-...`,
 	RunE: runServe,
 }
 
@@ -40,31 +59,23 @@ func init() {
 	serveCmd.Flags().StringVar(&serveResults, "results", "results.json", "Path to JSON results file")
 	serveCmd.Flags().StringVar(&serveGraph, "graph", "", "Path to link graph JSON file")
 	serveCmd.Flags().StringVar(&serveSummary, "summary", "", "Path to summary JSON file (optional, will be generated from results if not provided)")
+	serveCmd.Flags().StringVar(&serveStore, "store", "", "Read results from a store instead: sqlite://<path> or postgres://<dsn>")
+	serveCmd.Flags().StringVar(&serveCrawl, "crawl", "", "Crawl ID to view from --store (default: newest crawl)")
+	serveCmd.Flags().StringVar(&serveHTML, "html", "", "HTML snapshots searched at /api/search (default: html/ beside --results)")
+	serveCmd.Flags().BoolVar(&serveBacklinks, "backlinks", false, "Look up referring domains for pages with issues using the backlinks provider in the config file, and weigh them into issue priority")
+	addPprofFlag(serveCmd)
 
 	rootCmd.AddCommand(serveCmd)
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
-	// Determine file type and load results
-	var results []*models.PageResult
-	var err error
-
-	if strings.HasSuffix(strings.ToLower(serveResults), ".csv") {
-		// Load from CSV
-		results, err = exporter.ImportCSV(serveResults)
-		if err != nil {
-			return fmt.Errorf("failed to import CSV: %w", err)
-		}
-	} else {
-		// Load from JSON
-		resultsData, err := os.ReadFile(serveResults)
-		if err != nil {
-			return fmt.Errorf("failed to read results file: %w", err)
-		}
+	if err := startPprof(); err != nil {
+		return err
+	}
 
-		if err := json.Unmarshal(resultsData, &results); err != nil {
-			return fmt.Errorf("failed to parse results JSON: %w", err)
-		}
+	results, manifest, err := loadServeResults(cmd.Context())
+	if err != nil {
+		return err
 	}
 
 	// Generate or load summary
@@ -84,6 +95,14 @@ func runServe(cmd *cobra.Command, args []string) error {
 		summary = analyzer.AnalyzeWithImages(results, 30*1000*1000*1000) // 30s timeout
 	}
 
+	var referringDomains map[string]int
+	if serveBacklinks {
+		referringDomains, err = lookupReferringDomains(cmd.Context(), summary.Issues)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Backlink lookup failed: %v\n", err)
+		}
+	}
+
 	// Load graph if provided
 	var graphData map[string][]string
 	if serveGraph != "" {
@@ -95,27 +114,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 	// Setup API routes first (must be before catch-all handler)
 	apiMux := http.NewServeMux()
-	apiMux.HandleFunc("/api/results", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		json.NewEncoder(w).Encode(results)
-	})
-
-	apiMux.HandleFunc("/api/summary", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		json.NewEncoder(w).Encode(summary)
-	})
-
-	apiMux.HandleFunc("/api/graph", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		if graphData == nil {
-			json.NewEncoder(w).Encode(map[string][]string{})
-		} else {
-			json.NewEncoder(w).Encode(graphData)
-		}
-	})
+	registerServeDataRoutes(apiMux, results, summary, manifest, referringDomains, graphData)
 
 	// Initialize GSC OAuth (non-blocking - will fail gracefully if credentials not set)
 	gscRedirectURL := fmt.Sprintf("http://localhost:%d/api/gsc/callback", servePort)
@@ -133,10 +132,9 @@ func runServe(cmd *cobra.Command, args []string) error {
 			http.Error(w, fmt.Sprintf("Failed to generate auth URL: %v", err), http.StatusInternalServerError)
 			return
 		}
-		// Return auth URL and state
-		json.NewEncoder(w).Encode(map[string]string{
-			"auth_url": authURL,
-			"state":    state,
+		json.NewEncoder(w).Encode(gsc.AuthURLResponse{
+			AuthURL: authURL,
+			State:   state,
 		})
 	})
 
@@ -257,11 +255,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 			return
 		}
 
-		var req struct {
-			UserID  string `json:"user_id"`
-			SiteURL string `json:"site_url"`
-			Days    int    `json:"days"` // Number of days to fetch (default 30)
-		}
+		var req gsc.PerformanceRequest
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			w.WriteHeader(http.StatusBadRequest)
@@ -279,7 +273,7 @@ func runServe(cmd *cobra.Command, args []string) error {
 		endDate := time.Now()
 		startDate := endDate.AddDate(0, 0, -req.Days)
 
-		performanceMap, err := gsc.FetchPerformanceData(req.UserID, req.SiteURL, startDate, endDate)
+		performanceMap, err := gsc.FetchPerformanceData(req.UserID, req.SiteURL, startDate, endDate, nil)
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			json.NewEncoder(w).Encode(map[string]string{
@@ -291,21 +285,36 @@ func runServe(cmd *cobra.Command, args []string) error {
 		json.NewEncoder(w).Encode(performanceMap)
 	})
 
+	// Issues are enriched in the background. A served crawl directory keeps
+	// the enriched issues for the next time it is served.
+	enrichedIssuesPath := ""
+	if serveStore == "" && manifest != nil {
+		enrichedIssuesPath = filepath.Join(filepath.Dir(serveResults), crawldir.EnrichedIssuesFile)
+	}
+	enricher := gsc.NewEnricher(summary.Issues, referringDomains, enrichedIssuesPath)
+
 	apiMux.HandleFunc("/api/gsc/enrich-issues", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 
-		if r.Method != http.MethodPost {
+		switch r.Method {
+		case http.MethodGet:
+			job, ok := enricher.Job()
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{"error": "No issue enrichment has been started"})
+				return
+			}
+			json.NewEncoder(w).Encode(job)
+			return
+		case http.MethodPost:
+		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 			json.NewEncoder(w).Encode(map[string]string{"error": "Method not allowed"})
 			return
 		}
 
-		var req struct {
-			UserID  string `json:"user_id"`
-			SiteURL string `json:"site_url"`
-			Days    int    `json:"days"`
-		}
+		var req gsc.PerformanceRequest
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			w.WriteHeader(http.StatusBadRequest)
@@ -320,21 +329,11 @@ func runServe(cmd *cobra.Command, args []string) error {
 			req.Days = 30
 		}
 
-		// Fetch performance data
-		endDate := time.Now()
-		startDate := endDate.AddDate(0, 0, -req.Days)
-		performanceMap, err := gsc.FetchPerformanceData(req.UserID, req.SiteURL, startDate, endDate)
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]string{
-				"error": fmt.Sprintf("Failed to fetch performance data: %v", err),
-			})
-			return
+		job := enricher.Start(req, r.URL.Query().Get("refresh") == "true")
+		if job.Status == gsc.EnrichRunning {
+			w.WriteHeader(http.StatusAccepted)
 		}
-
-		// Enrich issues
-		enrichedIssues := gsc.EnrichIssues(summary.Issues, performanceMap)
-		json.NewEncoder(w).Encode(enrichedIssues)
+		json.NewEncoder(w).Encode(job)
 	})
 
 	// Set cache headers based on file type
@@ -455,6 +454,9 @@ func runServe(cmd *cobra.Command, args []string) error {
 
 	fmt.Fprintf(os.Stdout, "🚀 Starting Barracuda web server on http://localhost:%d\n", servePort)
 	fmt.Fprintf(os.Stdout, "📊 Serving %d pages from %s\n", len(results), serveResults)
+	if manifest != nil {
+		fmt.Fprintf(os.Stdout, "📁 Crawl of %s started %s (barracuda %s)\n", manifest.URL, manifest.StartedAt.Format(time.RFC1123), manifest.Version)
+	}
 	fmt.Fprintf(os.Stdout, "🌐 Open http://localhost:%d in your browser\n", servePort)
 
 	if err := http.ListenAndServe(fmt.Sprintf(":%d", servePort), nil); err != nil {
@@ -464,7 +466,228 @@ func runServe(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// registerServeDataRoutes adds the API routes that read the served crawl: its
+// results, summary, metadata, referring domains, link graph, and event log
+func registerServeDataRoutes(apiMux *http.ServeMux, results []*models.PageResult, summary *analyzer.Summary, manifest *crawldir.Metadata, referringDomains map[string]int, graphData map[string][]string) {
+	apiMux.HandleFunc("/api/results", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(results)
+	})
+
+	apiMux.HandleFunc("/api/summary", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(summary)
+	})
+
+	apiMux.HandleFunc("/api/metadata", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if manifest == nil {
+			w.Write([]byte("{}\n"))
+			return
+		}
+		json.NewEncoder(w).Encode(manifest)
+	})
+
+	apiMux.HandleFunc("/api/backlinks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if referringDomains == nil {
+			json.NewEncoder(w).Encode(map[string]int{})
+			return
+		}
+		json.NewEncoder(w).Encode(referringDomains)
+	})
+
+	apiMux.HandleFunc("/api/graph", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if graphData == nil {
+			json.NewEncoder(w).Encode(map[string][]string{})
+		} else {
+			json.NewEncoder(w).Encode(graphData)
+		}
+	})
+
+	apiMux.HandleFunc("/api/logs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		events, err := loadServeEvents(r.Context(), serveEventFilter(r))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(events)
+	})
+
+	apiMux.HandleFunc("/api/canonicals", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		json.NewEncoder(w).Encode(analyzer.CanonicalClusters(results))
+	})
+
+	apiMux.HandleFunc("/api/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		query := r.URL.Query()
+		search, err := crawler.SearchSnapshots(serveSnapshotDir(), crawler.SnapshotQuery{
+			Pattern:    query.Get("q"),
+			Regexp:     query.Get("regexp") == "true",
+			IgnoreCase: query.Get("ignore_case") == "true",
+			Missing:    query.Get("missing") == "true",
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(search)
+	})
+}
+
+// serveSnapshotDir returns the HTML snapshots /api/search reads: --html, or
+// those saved in the crawl directory of --results
+func serveSnapshotDir() string {
+	if serveHTML != "" {
+		return serveHTML
+	}
+	return filepath.Join(filepath.Dir(serveResults), crawldir.HTMLDir)
+}
+
+// loadServeResults reads page results from --results, or from a crawl in
+// --store, along with the crawl's metadata manifest when there is one
+func loadServeResults(ctx context.Context) ([]*models.PageResult, *crawldir.Metadata, error) {
+	if serveStore == "" {
+		// Load results from CSV or JSON
+		results, err := exporter.ImportResults(serveResults)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Results saved in a crawl directory have metadata.json beside them
+		manifest, _ := crawldir.ReadMetadata(filepath.Dir(serveResults))
+		return results, manifest, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	dataStore, err := store.Open(serveStore)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	defer dataStore.Close()
+
+	crawl, err := serveStoreCrawl(ctx, dataStore)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pages, err := dataStore.ListPages(ctx, crawl.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := make([]*models.PageResult, 0, len(pages))
+	for _, page := range pages {
+		results = append(results, page.Result())
+	}
+	fmt.Fprintf(os.Stderr, "📁 Loaded %d pages from crawl %s\n", len(results), crawl.ID)
+	return results, crawl.Manifest(), nil
+}
+
+// serveStoreCrawl returns the crawl --crawl names, or the newest one
+func serveStoreCrawl(ctx context.Context, dataStore store.Store) (*store.Crawl, error) {
+	if serveCrawl != "" {
+		crawl, err := dataStore.GetCrawl(ctx, serveCrawl)
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, fmt.Errorf("crawl %s not found in %s", serveCrawl, serveStore)
+		}
+		return crawl, err
+	}
+	crawls, err := dataStore.ListCrawls(ctx, store.CrawlFilter{})
+	if err != nil {
+		return nil, err
+	}
+	if len(crawls) == 0 {
+		return nil, fmt.Errorf("no crawls found in %s", serveStore)
+	}
+	return crawls[0], nil
+}
+
+// serveEventFilter reads the /api/logs query parameters
+func serveEventFilter(r *http.Request) models.CrawlEventFilter {
+	query := r.URL.Query()
+	filter := models.CrawlEventFilter{
+		Event:  query.Get("event"),
+		Reason: query.Get("reason"),
+		URL:    query.Get("url"),
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 {
+		filter.Limit = limit
+	}
+	return filter
+}
+
+// loadServeEvents reads the served crawl's log: the events file beside
+// --results, or the crawl's logs in --store
+func loadServeEvents(ctx context.Context, filter models.CrawlEventFilter) ([]models.CrawlEvent, error) {
+	if serveStore == "" {
+		return crawldir.ReadEvents(filepath.Dir(serveResults), filter)
+	}
+
+	dataStore, err := store.Open(serveStore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	defer dataStore.Close()
+
+	crawl, err := serveStoreCrawl(ctx, dataStore)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := dataStore.ListCrawlLogs(ctx, crawl.ID, filter)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]models.CrawlEvent, 0, len(logs))
+	for _, log := range logs {
+		events = append(events, log.CrawlEvent)
+	}
+	return events, nil
+}
+
 // SetFrontendFiles sets the embedded frontend filesystem
 func SetFrontendFiles(fs fs.FS) {
 	frontendFiles = fs
 }
+
+// lookupReferringDomains fetches referring-domain counts for every page with
+// an issue from the backlinks provider in the config file. Counts fetched
+// before a failure are returned along with the error.
+func lookupReferringDomains(ctx context.Context, issues []analyzer.Issue) (map[string]int, error) {
+	fc, path, err := loadFileConfig()
+	if err != nil {
+		return nil, err
+	}
+	if fc == nil || fc.Backlinks.Provider == "" {
+		return nil, fmt.Errorf("--backlinks needs a backlinks section in the config file")
+	}
+	provider, err := backlinks.New(fc.Backlinks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backlinks config in %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	var urls []string
+	for _, issue := range issues {
+		if !seen[issue.URL] {
+			seen[issue.URL] = true
+			urls = append(urls, issue.URL)
+		}
+	}
+
+	fmt.Fprintf(os.Stdout, "🔗 Looking up referring domains for %d pages via %s...\n", len(urls), provider.Name())
+	return backlinks.Lookup(ctx, provider, urls)
+}
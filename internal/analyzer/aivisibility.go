@@ -0,0 +1,158 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/temoto/robotstxt"
+)
+
+// AICrawlers are the user agents of the AI crawlers checked against robots.txt
+var AICrawlers = []string{
+	"GPTBot",
+	"ChatGPT-User",
+	"OAI-SearchBot",
+	"ClaudeBot",
+	"CCBot",
+	"PerplexityBot",
+	"Google-Extended",
+}
+
+// AIVisibility reports how visible a site is to AI crawlers and LLM tools
+type AIVisibility struct {
+	RobotsTxtFound bool              `json:"robots_txt_found"`
+	Crawlers       []AICrawlerAccess `json:"crawlers"`
+	LLMsTxt        LLMsTxtCheck      `json:"llms_txt"`
+}
+
+// AICrawlerAccess is whether robots.txt lets one AI crawler fetch the site
+type AICrawlerAccess struct {
+	UserAgent string `json:"user_agent"`
+	Allowed   bool   `json:"allowed"`
+	// Group is the robots.txt user-agent group that applies: the crawler's
+	// own name, "*", or empty when no group matches
+	Group string `json:"group,omitempty"`
+}
+
+// LLMsTxtCheck is the result of validating /llms.txt against the format at
+// https://llmstxt.org
+type LLMsTxtCheck struct {
+	Found    bool     `json:"found"`
+	URL      string   `json:"url"`
+	Title    string   `json:"title,omitempty"`
+	Sections int      `json:"sections"`
+	Links    int      `json:"links"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Valid reports whether llms.txt exists and has no problems
+func (c LLMsTxtCheck) Valid() bool {
+	return c.Found && len(c.Problems) == 0
+}
+
+// llmsLinkPattern matches an llms.txt list entry: "- [name](url)" with
+// optional ": notes"
+var llmsLinkPattern = regexp.MustCompile(`^[-*]\s+\[([^\]]+)\]\(([^)\s]+)\)(:.*)?$`)
+
+// AuditAIVisibility checks which AI crawlers robots.txt allows on siteURL and
+// validates llms.txt. robotsTxt and llmsTxt are the files' contents, or nil
+// when the site does not serve them.
+func AuditAIVisibility(siteURL string, robotsTxt, llmsTxt []byte) *AIVisibility {
+	visibility := &AIVisibility{
+		RobotsTxtFound: robotsTxt != nil,
+		Crawlers:       make([]AICrawlerAccess, 0, len(AICrawlers)),
+	}
+
+	path := "/"
+	llmsURL := "/llms.txt"
+	if u, err := url.Parse(siteURL); err == nil {
+		if u.Path != "" {
+			path = u.Path
+		}
+		llmsURL = fmt.Sprintf("%s://%s/llms.txt", u.Scheme, u.Host)
+	}
+
+	var robots *robotstxt.RobotsData
+	if robotsTxt != nil {
+		// An unparseable robots.txt is treated like a missing one, as crawlers do
+		robots, _ = robotstxt.FromBytes(robotsTxt)
+	}
+	for _, agent := range AICrawlers {
+		access := AICrawlerAccess{UserAgent: agent, Allowed: true}
+		if robots != nil {
+			if group := robots.FindGroup(agent); group != nil {
+				access.Allowed = group.Test(path)
+				// The parser doesn't record group names, but the "*" group
+				// is the only one "*" itself can match
+				access.Group = agent
+				if group == robots.FindGroup("*") {
+					access.Group = "*"
+				}
+			}
+		}
+		visibility.Crawlers = append(visibility.Crawlers, access)
+	}
+
+	visibility.LLMsTxt = checkLLMsTxt(llmsTxt)
+	visibility.LLMsTxt.URL = llmsURL
+	return visibility
+}
+
+// checkLLMsTxt validates the structure of an llms.txt file: an H1 title
+// first, then optional summary and text, then H2 sections of link lists
+func checkLLMsTxt(content []byte) LLMsTxtCheck {
+	check := LLMsTxtCheck{Found: content != nil}
+	if content == nil {
+		return check
+	}
+
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		check.Problems = append(check.Problems, "file is empty")
+		return check
+	}
+	if trimmed[0] == '<' {
+		// Usually a catch-all route serving an HTML page with status 200
+		check.Problems = append(check.Problems, "file is HTML, not Markdown")
+		return check
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	lineNum := 0
+	inSection := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "# "):
+			if check.Title != "" {
+				check.Problems = append(check.Problems, fmt.Sprintf("line %d: only one H1 title is allowed", lineNum))
+			} else if lineNum != 1 {
+				check.Problems = append(check.Problems, fmt.Sprintf("line %d: the H1 title must be the first line", lineNum))
+			}
+			check.Title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+		case strings.HasPrefix(line, "## "):
+			check.Sections++
+			inSection = true
+		case inSection && (strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ")):
+			if llmsLinkPattern.MatchString(line) {
+				check.Links++
+			} else {
+				check.Problems = append(check.Problems, fmt.Sprintf("line %d: list entries must be \"- [name](url): notes\"", lineNum))
+			}
+		}
+	}
+
+	if check.Title == "" {
+		check.Problems = append(check.Problems, "missing H1 title")
+	}
+	if check.Links == 0 {
+		check.Problems = append(check.Problems, "no links in H2 sections")
+	}
+	return check
+}
@@ -6,52 +6,118 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/internal/kb"
 	"github.com/dillonlara115/barracuda/pkg/models"
 )
 
+// MaxClickDepth is the deepest a page can sit below the start URL before it
+// is reported as hard to reach
+const MaxClickDepth = 3
+
+// Recommended title and meta description lengths, in bytes. Outside these
+// ranges a page gets a short or long title/description issue.
+const (
+	MinTitleLength    = 30
+	MaxTitleLength    = 60
+	MinMetaDescLength = 120
+	MaxMetaDescLength = 160
+)
+
 // IssueType represents the type of SEO issue detected
-type IssueType string
+type IssueType = models.IssueType
 
+// Issue types, re-exported from models so existing callers keep working
 const (
-	IssueMissingH1          IssueType = "missing_h1"
-	IssueMissingMetaDesc    IssueType = "missing_meta_description"
-	IssueMissingTitle       IssueType = "missing_title"
-	IssueLongTitle          IssueType = "long_title"
-	IssueLongMetaDesc       IssueType = "long_meta_description"
-	IssueShortTitle         IssueType = "short_title"
-	IssueShortMetaDesc      IssueType = "short_meta_description"
-	IssueLargeImage         IssueType = "large_image"
-	IssueMissingImageAlt    IssueType = "missing_image_alt"
-	IssueSlowResponse       IssueType = "slow_response"
-	IssueRedirectChain      IssueType = "redirect_chain"
-	IssueNoCanonical        IssueType = "no_canonical"
-	IssueBrokenLink         IssueType = "broken_link"
-	IssueMultipleH1         IssueType = "multiple_h1"
-	IssueEmptyH1            IssueType = "empty_h1"
+	IssueMissingH1        = models.IssueMissingH1
+	IssueMissingMetaDesc  = models.IssueMissingMetaDesc
+	IssueMissingTitle     = models.IssueMissingTitle
+	IssueLongTitle        = models.IssueLongTitle
+	IssueLongMetaDesc     = models.IssueLongMetaDesc
+	IssueShortTitle       = models.IssueShortTitle
+	IssueShortMetaDesc    = models.IssueShortMetaDesc
+	IssueLargeImage       = models.IssueLargeImage
+	IssueMissingImageAlt  = models.IssueMissingImageAlt
+	IssueSlowResponse     = models.IssueSlowResponse
+	IssueRedirectChain    = models.IssueRedirectChain
+	IssueNoCanonical      = models.IssueNoCanonical
+	IssueBrokenLink       = models.IssueBrokenLink
+	IssueMultipleH1       = models.IssueMultipleH1
+	IssueEmptyH1          = models.IssueEmptyH1
+	IssueDeepPage         = models.IssueDeepPage
+	IssueJSErrors         = models.IssueJSErrors
+	IssueUncacheablePage  = models.IssueUncacheablePage
+	IssueShortCacheTTL    = models.IssueShortCacheTTL
+	IssueInsecureAsset    = models.IssueInsecureAsset
+	IssueMissingSRI       = models.IssueMissingSRI
+	IssueImageSavings     = models.IssueImageSavings
+	IssueNoindexConflict  = models.IssueNoindexConflict
+	IssueNofollowLink     = models.IssueNofollowLink
+	IssueEmptyAnchor      = models.IssueEmptyAnchor
+	IssueGenericAnchor    = models.IssueGenericAnchor
+	IssueExactAnchor      = models.IssueExactAnchor
+	IssueInvalidSchema    = models.IssueInvalidSchema
+	IssueMissingSchema    = models.IssueMissingSchema
+	IssueCanonicalChain   = models.IssueCanonicalChain
+	IssuePagination       = models.IssuePagination
+	IssueDuplicateContent = models.IssueDuplicateContent
+	IssueNearDuplicate    = models.IssueNearDuplicate
+	IssueBrokenAnchor     = models.IssueBrokenAnchor
+	IssueSoft404          = models.IssueSoft404
+	IssueUnhelpful404     = models.IssueUnhelpful404
+	IssueMobileParity     = models.IssueMobileParity
+	IssueMissingViewport  = models.IssueMissingViewport
+	IssueSiteNoindex      = models.IssueSiteNoindex
+	IssueRobotsDisallow   = models.IssueRobotsDisallow
+	IssueStagingURL       = models.IssueStagingURL
+	IssuePlaceholderText  = models.IssuePlaceholderText
 )
 
 // Issue represents a detected SEO issue
-type Issue struct {
-	Type           IssueType `json:"type"`
-	Severity       string    `json:"severity"` // "error", "warning", "info"
-	URL            string    `json:"url"`
-	Message        string    `json:"message"`
-	Value          string    `json:"value,omitempty"`
-	Recommendation string    `json:"recommendation,omitempty"`
-}
+type Issue = models.Issue
 
 // Summary contains analysis results and statistics
 type Summary struct {
-	TotalPages           int                `json:"total_pages"`
-	TotalIssues          int                `json:"total_issues"`
-	IssuesByType         map[IssueType]int   `json:"issues_by_type"`
-	Issues               []Issue            `json:"issues"`
-	AverageResponseTime  int64              `json:"average_response_time_ms"`
-	PagesWithErrors      int                `json:"pages_with_errors"`
-	PagesWithRedirects   int                `json:"pages_with_redirects"`
-	TotalInternalLinks   int                `json:"total_internal_links"`
-	TotalExternalLinks   int                `json:"total_external_links"`
-	SlowestPages         []PagePerformance  `json:"slowest_pages,omitempty"`
+	TotalPages          int                `json:"total_pages"`
+	TotalIssues         int                `json:"total_issues"`
+	IssuesByType        map[IssueType]int  `json:"issues_by_type"`
+	Issues              []Issue            `json:"issues"`
+	AverageResponseTime int64              `json:"average_response_time_ms"`
+	ResponseTimes       LatencyStats       `json:"response_times"`
+	HostResponseTimes   []HostLatency      `json:"host_response_times,omitempty"`
+	PagesWithErrors     int                `json:"pages_with_errors"`
+	ErrorsByCode        map[string]int     `json:"errors_by_code,omitempty"` // Pages with errors by models.PageResult.FailureCode
+	PagesWithRedirects  int                `json:"pages_with_redirects"`
+	TotalInternalLinks  int                `json:"total_internal_links"`
+	TotalExternalLinks  int                `json:"total_external_links"`
+	SlowestPages        []PagePerformance  `json:"slowest_pages,omitempty"`
+	PagesByDepth        map[int]int        `json:"pages_by_depth,omitempty"`
+	Freshness           *Freshness         `json:"freshness,omitempty"`
+	AIVisibility        *AIVisibility      `json:"ai_visibility,omitempty"`
+	ThirdPartyOrigins   []ThirdPartyOrigin `json:"third_party_origins,omitempty"`
+	ImageSavings        *ImageSavings      `json:"image_savings,omitempty"`
+	Skipped             *models.SkipCounts `json:"skipped,omitempty"` // Discovered URLs the crawl didn't fetch; set by the crawl, not by Analyze
+}
+
+// ErrorCount is how many pages failed with one error code
+type ErrorCount struct {
+	Code  string
+	Count int
+}
+
+// ErrorBreakdown lists ErrorsByCode, most common first
+func (s *Summary) ErrorBreakdown() []ErrorCount {
+	breakdown := make([]ErrorCount, 0, len(s.ErrorsByCode))
+	for code, count := range s.ErrorsByCode {
+		breakdown = append(breakdown, ErrorCount{Code: code, Count: count})
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Count != breakdown[j].Count {
+			return breakdown[i].Count > breakdown[j].Count
+		}
+		return breakdown[i].Code < breakdown[j].Code
+	})
+	return breakdown
 }
 
 // PagePerformance tracks page performance metrics
@@ -62,215 +128,232 @@ type PagePerformance struct {
 
 // Analyze analyzes crawl results and detects SEO issues
 func Analyze(results []*models.PageResult) *Summary {
-	summary := &Summary{
-		TotalPages:   len(results),
-		IssuesByType: make(map[IssueType]int),
-		Issues:       make([]Issue, 0),
-		SlowestPages: make([]PagePerformance, 0),
+	analysis := NewIncremental()
+	for _, result := range results {
+		analysis.Add(result)
 	}
+	return analysis.Summary()
+}
 
-	var totalResponseTime int64
-	var slowPages []PagePerformance
+// PageIssues runs the per-page SEO checks on one result. Image sizes are
+// not checked.
+func PageIssues(result *models.PageResult) []Issue {
+	var issues []Issue
 
-	// Analyze basic issues first
-	for _, result := range results {
-		// Track response times
-		totalResponseTime += result.ResponseTime
-		if result.ResponseTime > 2000 { // Slower than 2 seconds
-			slowPages = append(slowPages, PagePerformance{
-				URL:          result.URL,
-				ResponseTime: result.ResponseTime,
-			})
-		}
-
-		// Track errors
-		if result.Error != "" || result.StatusCode >= 400 {
-			summary.PagesWithErrors++
-			if result.StatusCode >= 400 {
-				summary.Issues = append(summary.Issues, Issue{
-					Type:           IssueBrokenLink,
-					Severity:       "error",
-					URL:            result.URL,
-					Message:        fmt.Sprintf("HTTP %d", result.StatusCode),
-					Value:          fmt.Sprintf("%d", result.StatusCode),
-					Recommendation: "Fix broken link or redirect",
-				})
-				summary.IssuesByType[IssueBrokenLink]++
-			}
-		}
+	// Check broken pages
+	if result.StatusCode >= 400 {
+		issues = append(issues, Issue{
+			Type:           IssueBrokenLink,
+			Severity:       models.SeverityError,
+			URL:            result.URL,
+			Message:        i18n.T("issue.broken_link.message", result.StatusCode),
+			Value:          fmt.Sprintf("%d", result.StatusCode),
+			Recommendation: i18n.T("issue.broken_link.recommendation"),
+		})
+	}
 
-		// Track redirects
-		if len(result.RedirectChain) > 0 {
-			summary.PagesWithRedirects++
-			summary.Issues = append(summary.Issues, Issue{
-				Type:           IssueRedirectChain,
-				Severity:       "warning",
-				URL:            result.URL,
-				Message:        fmt.Sprintf("Redirect chain: %s", strings.Join(result.RedirectChain, " -> ")),
-				Value:          strings.Join(result.RedirectChain, " -> "),
-				Recommendation: "Consider using direct links instead of redirect chains",
-			})
-			summary.IssuesByType[IssueRedirectChain]++
-		}
+	// Track redirects
+	if len(result.RedirectChain) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueRedirectChain,
+			Severity:       models.SeverityWarning,
+			URL:            result.URL,
+			Message:        i18n.T("issue.redirect_chain.message", strings.Join(result.RedirectChain, " -> ")),
+			Value:          strings.Join(result.RedirectChain, " -> "),
+			Recommendation: i18n.T("issue.redirect_chain.recommendation"),
+		})
+	}
 
-		// Check title issues
-		if result.Title == "" {
-			summary.Issues = append(summary.Issues, Issue{
-				Type:           IssueMissingTitle,
-				Severity:       "error",
+	// Check title issues
+	if result.Title == "" {
+		issues = append(issues, Issue{
+			Type:           IssueMissingTitle,
+			Severity:       models.SeverityError,
+			URL:            result.URL,
+			Message:        i18n.T("issue.missing_title.message"),
+			Recommendation: i18n.T("issue.missing_title.recommendation"),
+		})
+	} else {
+		titleLen := len(result.Title)
+		if titleLen < MinTitleLength {
+			issues = append(issues, Issue{
+				Type:           IssueShortTitle,
+				Severity:       models.SeverityWarning,
 				URL:            result.URL,
-				Message:        "Missing page title",
-				Recommendation: "Add a unique, descriptive title tag",
+				Message:        i18n.T("issue.short_title.message", titleLen),
+				Value:          result.Title,
+				Recommendation: i18n.T("issue.short_title.recommendation"),
 			})
-			summary.IssuesByType[IssueMissingTitle]++
-		} else {
-			titleLen := len(result.Title)
-			if titleLen < 30 {
-				summary.Issues = append(summary.Issues, Issue{
-					Type:           IssueShortTitle,
-					Severity:       "warning",
-					URL:            result.URL,
-					Message:        fmt.Sprintf("Title too short (%d characters)", titleLen),
-					Value:          result.Title,
-					Recommendation: "Aim for 30-60 characters for optimal SEO",
-				})
-				summary.IssuesByType[IssueShortTitle]++
-			} else if titleLen > 60 {
-				summary.Issues = append(summary.Issues, Issue{
-					Type:           IssueLongTitle,
-					Severity:       "warning",
-					URL:            result.URL,
-					Message:        fmt.Sprintf("Title too long (%d characters)", titleLen),
-					Value:          result.Title,
-					Recommendation: "Keep titles under 60 characters to avoid truncation",
-				})
-				summary.IssuesByType[IssueLongTitle]++
-			}
-		}
-
-		// Check meta description issues
-		if result.MetaDesc == "" {
-			summary.Issues = append(summary.Issues, Issue{
-				Type:           IssueMissingMetaDesc,
-				Severity:       "warning",
+		} else if titleLen > MaxTitleLength {
+			issues = append(issues, Issue{
+				Type:           IssueLongTitle,
+				Severity:       models.SeverityWarning,
 				URL:            result.URL,
-				Message:        "Missing meta description",
-				Recommendation: "Add a unique meta description (120-160 characters)",
+				Message:        i18n.T("issue.long_title.message", titleLen),
+				Value:          result.Title,
+				Recommendation: i18n.T("issue.long_title.recommendation"),
 			})
-			summary.IssuesByType[IssueMissingMetaDesc]++
-		} else {
-			descLen := len(result.MetaDesc)
-			if descLen < 120 {
-				summary.Issues = append(summary.Issues, Issue{
-					Type:           IssueShortMetaDesc,
-					Severity:       "info",
-					URL:            result.URL,
-					Message:        fmt.Sprintf("Meta description too short (%d characters)", descLen),
-					Value:          result.MetaDesc,
-					Recommendation: "Aim for 120-160 characters for optimal display",
-				})
-				summary.IssuesByType[IssueShortMetaDesc]++
-			} else if descLen > 160 {
-				summary.Issues = append(summary.Issues, Issue{
-					Type:           IssueLongMetaDesc,
-					Severity:       "warning",
-					URL:            result.URL,
-					Message:        fmt.Sprintf("Meta description too long (%d characters)", descLen),
-					Value:          result.MetaDesc,
-					Recommendation: "Keep under 160 characters to avoid truncation",
-				})
-				summary.IssuesByType[IssueLongMetaDesc]++
-			}
 		}
+	}
 
-		// Check H1 issues
-		if len(result.H1) == 0 {
-			summary.Issues = append(summary.Issues, Issue{
-				Type:           IssueMissingH1,
-				Severity:       "error",
-				URL:            result.URL,
-				Message:        "Missing H1 tag",
-				Recommendation: "Add exactly one H1 tag per page",
-			})
-			summary.IssuesByType[IssueMissingH1]++
-		} else if len(result.H1) > 1 {
-			summary.Issues = append(summary.Issues, Issue{
-				Type:           IssueMultipleH1,
-				Severity:       "warning",
+	// Check meta description issues
+	if result.MetaDesc == "" {
+		issues = append(issues, Issue{
+			Type:           IssueMissingMetaDesc,
+			Severity:       models.SeverityWarning,
+			URL:            result.URL,
+			Message:        i18n.T("issue.missing_meta_description.message"),
+			Recommendation: i18n.T("issue.missing_meta_description.recommendation"),
+		})
+	} else {
+		descLen := len(result.MetaDesc)
+		if descLen < MinMetaDescLength {
+			issues = append(issues, Issue{
+				Type:           IssueShortMetaDesc,
+				Severity:       models.SeverityInfo,
 				URL:            result.URL,
-				Message:        fmt.Sprintf("Multiple H1 tags found (%d)", len(result.H1)),
-				Value:          strings.Join(result.H1, ", "),
-				Recommendation: "Use only one H1 tag per page for better SEO",
+				Message:        i18n.T("issue.short_meta_description.message", descLen),
+				Value:          result.MetaDesc,
+				Recommendation: i18n.T("issue.short_meta_description.recommendation"),
 			})
-			summary.IssuesByType[IssueMultipleH1]++
-		} else if len(result.H1) == 1 && strings.TrimSpace(result.H1[0]) == "" {
-			summary.Issues = append(summary.Issues, Issue{
-				Type:           IssueEmptyH1,
-				Severity:       "error",
+		} else if descLen > MaxMetaDescLength {
+			issues = append(issues, Issue{
+				Type:           IssueLongMetaDesc,
+				Severity:       models.SeverityWarning,
 				URL:            result.URL,
-				Message:        "H1 tag is empty",
-				Recommendation: "Add meaningful content to H1 tag",
+				Message:        i18n.T("issue.long_meta_description.message", descLen),
+				Value:          result.MetaDesc,
+				Recommendation: i18n.T("issue.long_meta_description.recommendation"),
 			})
-			summary.IssuesByType[IssueEmptyH1]++
 		}
+	}
 
-		// Check canonical
-		if result.Canonical == "" {
-			summary.Issues = append(summary.Issues, Issue{
-				Type:           IssueNoCanonical,
-				Severity:       "info",
-				URL:            result.URL,
-				Message:        "No canonical tag found",
-				Recommendation: "Consider adding canonical tag to prevent duplicate content issues",
-			})
-			summary.IssuesByType[IssueNoCanonical]++
-		}
+	// Check H1 issues
+	if len(result.H1) == 0 {
+		issues = append(issues, Issue{
+			Type:           IssueMissingH1,
+			Severity:       models.SeverityError,
+			URL:            result.URL,
+			Message:        i18n.T("issue.missing_h1.message"),
+			Recommendation: i18n.T("issue.missing_h1.recommendation"),
+		})
+	} else if len(result.H1) > 1 {
+		issues = append(issues, Issue{
+			Type:           IssueMultipleH1,
+			Severity:       models.SeverityWarning,
+			URL:            result.URL,
+			Message:        i18n.T("issue.multiple_h1.message", len(result.H1)),
+			Value:          strings.Join(result.H1, ", "),
+			Recommendation: i18n.T("issue.multiple_h1.recommendation"),
+		})
+	} else if len(result.H1) == 1 && strings.TrimSpace(result.H1[0]) == "" {
+		issues = append(issues, Issue{
+			Type:           IssueEmptyH1,
+			Severity:       models.SeverityError,
+			URL:            result.URL,
+			Message:        i18n.T("issue.empty_h1.message"),
+			Recommendation: i18n.T("issue.empty_h1.recommendation"),
+		})
+	}
 
-		// Count links
-		summary.TotalInternalLinks += len(result.InternalLinks)
-		summary.TotalExternalLinks += len(result.ExternalLinks)
+	// Check canonical
+	if result.Canonical == "" {
+		issues = append(issues, Issue{
+			Type:           IssueNoCanonical,
+			Severity:       models.SeverityInfo,
+			URL:            result.URL,
+			Message:        i18n.T("issue.no_canonical.message"),
+			Recommendation: i18n.T("issue.no_canonical.recommendation"),
+		})
 	}
 
-	// Calculate average response time
-	if len(results) > 0 {
-		summary.AverageResponseTime = totalResponseTime / int64(len(results))
+	// Check click depth, as recorded by the crawler
+	if result.Depth > MaxClickDepth {
+		issues = append(issues, Issue{
+			Type:           IssueDeepPage,
+			Severity:       models.SeverityInfo,
+			URL:            result.URL,
+			Message:        i18n.T("issue.deep_page.message", result.Depth),
+			Value:          fmt.Sprintf("%d", result.Depth),
+			Recommendation: i18n.T("issue.deep_page.recommendation"),
+		})
 	}
 
-	// Sort slow pages
-	sort.Slice(slowPages, func(i, j int) bool {
-		return slowPages[i].ResponseTime > slowPages[j].ResponseTime
-	})
-	if len(slowPages) > 10 {
-		summary.SlowestPages = slowPages[:10]
-	} else {
-		summary.SlowestPages = slowPages
+	// Check JavaScript errors, captured only when the page was rendered.
+	// An uncaught exception can stop the page from rendering its content.
+	if len(result.JSErrors) > 0 {
+		severity := models.SeverityWarning
+		for _, jsErr := range result.JSErrors {
+			if jsErr.Kind == models.JSErrorException {
+				severity = models.SeverityError
+				break
+			}
+		}
+		issues = append(issues, Issue{
+			Type:           IssueJSErrors,
+			Severity:       severity,
+			URL:            result.URL,
+			Message:        i18n.T("issue.js_errors.message", len(result.JSErrors), result.JSErrors[0].Message),
+			Value:          fmt.Sprintf("%d", len(result.JSErrors)),
+			Recommendation: i18n.T("issue.js_errors.recommendation"),
+		})
+	}
+
+	if issue, ok := pageCacheIssue(result); ok {
+		issues = append(issues, issue)
+	}
+	if issue, ok := noindexConflictIssue(result); ok {
+		issues = append(issues, issue)
+	}
+	if issue, ok := nofollowLinkIssue(result); ok {
+		issues = append(issues, issue)
 	}
+	issues = append(issues, anchorTextIssues(result)...)
+	issues = append(issues, structuredDataIssues(result)...)
 
-	summary.TotalIssues = len(summary.Issues)
+	issues = append(issues, thirdPartyIssues(result)...)
+	issues = append(issues, mobileIssues(result)...)
 
-	return summary
+	return withDocKeys(issues)
 }
 
-// AnalyzeWithImages analyzes results including image size checking
-func AnalyzeWithImages(results []*models.PageResult, imageTimeout time.Duration) *Summary {
-	summary := Analyze(results)
+// withDocKeys links issues to the knowledge base article of their type
+func withDocKeys(issues []Issue) []Issue {
+	for i := range issues {
+		if issues[i].DocKey == "" {
+			issues[i].DocKey = kb.KeyFor(issues[i].Type)
+		}
+	}
+	return issues
+}
 
-	// Add image analysis
-	imageIssues := AnalyzeImages(results, imageTimeout)
-	summary.Issues = append(summary.Issues, imageIssues...)
+// SortIssues orders issues by page URL, then type, then value, so the same
+// results always produce the same issue list
+func SortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.URL != b.URL {
+			return a.URL < b.URL
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Value < b.Value
+	})
+}
 
-	// Update counts
-	for _, issue := range imageIssues {
-		summary.IssuesByType[issue.Type]++
+// AnalyzeWithImages analyzes results including image size checking
+func AnalyzeWithImages(results []*models.PageResult, imageTimeout time.Duration) *Summary {
+	analysis := NewIncrementalWithImages(imageTimeout)
+	for _, result := range results {
+		analysis.Add(result)
 	}
-	summary.TotalIssues = len(summary.Issues)
-
-	return summary
+	return analysis.Summary()
 }
 
 // GetIssueCountBySeverity returns counts grouped by severity
-func (s *Summary) GetIssueCountBySeverity() map[string]int {
-	counts := make(map[string]int)
+func (s *Summary) GetIssueCountBySeverity() map[models.Severity]int {
+	counts := make(map[models.Severity]int)
 	for _, issue := range s.Issues {
 		counts[issue.Severity]++
 	}
@@ -288,7 +371,10 @@ func (s *Summary) GetTopIssues(limit int) []IssueType {
 		counts = append(counts, count{issueType: issueType, count: cnt})
 	}
 	sort.Slice(counts, func(i, j int) bool {
-		return counts[i].count > counts[j].count
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].issueType < counts[j].issueType
 	})
 	result := make([]IssueType, 0, limit)
 	for i := 0; i < limit && i < len(counts); i++ {
@@ -296,4 +382,3 @@ func (s *Summary) GetTopIssues(limit int) []IssueType {
 	}
 	return result
 }
-
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// benchmarkSite returns the results of crawling a site of n pages shaped like
+// a blog: every page links to the same navigation pages plus a few others,
+// and some have the usual problems (missing descriptions and alt text,
+// duplicate titles and content, broken pages)
+func benchmarkSite(n int) []*models.PageResult {
+	url := func(i int) string {
+		return fmt.Sprintf("https://www.example.com/guides/%d/", i)
+	}
+	results := make([]*models.PageResult, n)
+	for i := range results {
+		page := &models.PageResult{
+			URL:          url(i),
+			StatusCode:   200,
+			ResponseTime: int64(80 + i%400),
+			TTFB:         int64(40 + i%200),
+			Depth:        1 + i%5,
+			Title:        fmt.Sprintf("Guide %d: How to Grow Vegetables in Raised Beds", i),
+			MetaDesc:     fmt.Sprintf("Everything you need to know about growing vegetables in raised beds, part %d of our series.", i),
+			Canonical:    url(i),
+			H1:           []string{fmt.Sprintf("Guide %d", i)},
+			H2:           []string{"Soil", "Watering", "Harvest"},
+			WordCount:    300 + i%1500,
+			ContentType:  "text/html; charset=utf-8",
+			Headers:      map[string]string{"Content-Type": "text/html; charset=utf-8", "Cache-Control": "max-age=600"},
+			ContentHash:  fmt.Sprintf("%064x", i),
+			TextHash:     fmt.Sprintf("%064x", i),
+			SimHash:      fmt.Sprintf("%016x", uint64(i)*0x9E3779B97F4A7C15),
+		}
+		switch {
+		case i%50 == 49:
+			page.StatusCode = 404
+		case i%13 == 12:
+			page.TextHash = results[i-1].TextHash
+			page.SimHash = results[i-1].SimHash
+		case i%11 == 10:
+			page.MetaDesc = ""
+		case i%7 == 6:
+			page.Title = results[i-1].Title
+		}
+
+		// Navigation, then links into the body of the site
+		for j := 0; j < 40 && j < n; j++ {
+			page.Links = append(page.Links, models.Link{URL: url(j), Text: fmt.Sprintf("Guide %d", j), Internal: true})
+		}
+		for j := 1; j <= 20; j++ {
+			target := url((i*31 + j*17) % n)
+			page.Links = append(page.Links, models.Link{URL: target, Text: "read more", Internal: true})
+		}
+		page.Links = append(page.Links, models.Link{URL: "https://extension.example.edu/vegetables", Text: "Extension guide"})
+		for _, link := range page.Links {
+			if link.Internal {
+				page.InternalLinks = append(page.InternalLinks, link.URL)
+			} else {
+				page.ExternalLinks = append(page.ExternalLinks, link.URL)
+			}
+		}
+
+		for j := 0; j < 8; j++ {
+			image := models.Image{URL: fmt.Sprintf("https://www.example.com/uploads/%d-%d.jpg", i, j), Alt: "Raised bed", Width: 1024, Height: 683}
+			if (i+j)%9 == 0 {
+				image.Alt = ""
+			}
+			page.Images = append(page.Images, image)
+		}
+		page.Assets = []string{"https://www.example.com/theme.css", "https://www.example.com/app.js"}
+		results[i] = page
+	}
+	return results
+}
+
+func BenchmarkAnalyze(b *testing.B) {
+	results := benchmarkSite(2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Analyze(results)
+	}
+}
+
+func BenchmarkPageIssues(b *testing.B) {
+	results := benchmarkSite(2000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		PageIssues(results[i%len(results)])
+	}
+}
@@ -0,0 +1,281 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+const (
+	// MinExactMatchPages is how many pages must link to a page before its
+	// anchor texts are checked for over-optimization
+	MinExactMatchPages = 5
+
+	// exactMatchShare is the share of a page's linking pages that must use
+	// one anchor text for it to count as over-optimized
+	exactMatchShare = 0.8
+
+	// exactMatchWords is the fewest words an over-optimized anchor can have;
+	// shorter repeated anchors are usually navigation
+	exactMatchWords = 3
+
+	// anchorExamples is how many anchors an issue's value lists
+	anchorExamples = 3
+)
+
+// Anchor text flags of AnchorText.Flag
+const (
+	AnchorEmpty         = "empty"
+	AnchorGeneric       = "generic"
+	AnchorOverOptimized = "over_optimized"
+)
+
+// genericAnchors are link texts that say nothing about where the link goes,
+// in English, French, German, and Spanish, after normalizeAnchor
+var genericAnchors = map[string]bool{
+	"click here": true, "click": true, "click this": true, "here": true, "right here": true,
+	"read more": true, "learn more": true, "more": true, "more info": true, "more information": true,
+	"find out more": true, "see more": true, "continue": true, "continue reading": true,
+	"this": true, "this page": true, "link": true, "this link": true, "go": true, "start": true,
+	"details": true, "information": true,
+	"cliquez ici": true, "ici": true, "en savoir plus": true, "lire la suite": true,
+	"hier klicken": true, "hier": true, "mehr": true, "weiterlesen": true, "mehr erfahren": true,
+	"haga clic aquí": true, "haz clic aquí": true, "aquí": true, "leer más": true, "más información": true,
+}
+
+// AnchorText is one anchor text of the internal links to a page
+type AnchorText struct {
+	Target  string  `json:"target"`
+	Text    string  `json:"text"`    // As first seen; "" for links without text
+	Pages   int     `json:"pages"`   // Pages linking to Target with this text
+	Share   float64 `json:"share"`   // Fraction of the pages linking to Target
+	Flag    string  `json:"flag"`    // AnchorEmpty, AnchorGeneric, AnchorOverOptimized, or ""
+	Example string  `json:"example"` // One of the pages linking with this text
+}
+
+// normalizeAnchor lowercases an anchor text, collapses its whitespace, and
+// trims punctuation and arrows around it, so "Read more »" matches
+// "read more"
+func normalizeAnchor(text string) string {
+	text = strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	return strings.TrimFunc(text, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+	})
+}
+
+// anchorTextIssues flags a page's internal links that have no anchor text,
+// or only generic text such as "click here". Both leave search engines and
+// screen reader users guessing where the link goes.
+func anchorTextIssues(result *models.PageResult) []Issue {
+	if result.Error != "" {
+		return nil
+	}
+
+	var empty, generic []string
+	seenGeneric := make(map[string]bool)
+	for _, link := range result.Links {
+		if !link.Internal {
+			continue
+		}
+		text := normalizeAnchor(link.Text)
+		switch {
+		case text == "":
+			empty = append(empty, link.URL)
+		case genericAnchors[text]:
+			if !seenGeneric[text] {
+				seenGeneric[text] = true
+				generic = append(generic, fmt.Sprintf("%q", link.Text))
+			}
+		}
+	}
+
+	var issues []Issue
+	if len(empty) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueEmptyAnchor,
+			Severity:       models.SeverityWarning,
+			URL:            result.URL,
+			Message:        i18n.T("issue.empty_anchor_text.message", len(empty)),
+			Value:          joinExamples(empty, anchorExamples),
+			Recommendation: i18n.T("issue.empty_anchor_text.recommendation"),
+		})
+	}
+	if len(generic) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueGenericAnchor,
+			Severity:       models.SeverityInfo,
+			URL:            result.URL,
+			Message:        i18n.T("issue.generic_anchor_text.message", len(seenGeneric)),
+			Value:          joinExamples(generic, anchorExamples),
+			Recommendation: i18n.T("issue.generic_anchor_text.recommendation"),
+		})
+	}
+	return issues
+}
+
+// joinExamples joins up to limit values, noting how many were left out
+func joinExamples(values []string, limit int) string {
+	joined := strings.Join(values[:min(len(values), limit)], ", ")
+	if len(values) > limit {
+		joined += fmt.Sprintf(" (+%d)", len(values)-limit)
+	}
+	return joined
+}
+
+// anchorSamples collects the anchor texts of the internal links to each
+// page, with the titles and headings of the pages crawled
+type anchorSamples struct {
+	targets map[string]*anchorTarget
+}
+
+// anchorTarget is what links to one page say about it
+type anchorTarget struct {
+	pages    int                   // Pages linking to the target
+	texts    map[string]*anchorUse // By normalized text
+	headings []string              // Normalized title and H1s, once the target is crawled
+}
+
+// anchorUse is one anchor text of the links to a page
+type anchorUse struct {
+	text    string
+	pages   int
+	example string
+}
+
+// target returns the samples of url, creating them on first use
+func (a *anchorSamples) target(url string) *anchorTarget {
+	if a.targets == nil {
+		a.targets = make(map[string]*anchorTarget)
+	}
+	target := a.targets[url]
+	if target == nil {
+		target = &anchorTarget{texts: make(map[string]*anchorUse)}
+		a.targets[url] = target
+	}
+	return target
+}
+
+// add records a page's internal link anchors, counting each target and
+// each anchor text once per page, and the page's own title and headings
+func (a *anchorSamples) add(result *models.PageResult) {
+	if result.Error != "" {
+		return
+	}
+	if result.StatusCode == 200 {
+		target := a.target(result.URL)
+		target.headings = target.headings[:0]
+		for _, heading := range append([]string{result.Title}, result.H1...) {
+			if heading = normalizeAnchor(heading); heading != "" {
+				target.headings = append(target.headings, heading)
+			}
+		}
+	}
+
+	seenTargets := make(map[string]bool)
+	seenTexts := make(map[string]bool)
+	for _, link := range result.Links {
+		if !link.Internal || link.URL == result.URL {
+			continue
+		}
+		target := a.target(link.URL)
+		if !seenTargets[link.URL] {
+			seenTargets[link.URL] = true
+			target.pages++
+		}
+		text := normalizeAnchor(link.Text)
+		if key := link.URL + "\x00" + text; !seenTexts[key] {
+			seenTexts[key] = true
+			use := target.texts[text]
+			if use == nil {
+				use = &anchorUse{text: link.Text, example: result.URL}
+				target.texts[text] = use
+			}
+			use.pages++
+		}
+	}
+}
+
+// overOptimized returns the anchor text most pages linking to the target
+// use when it looks like a keyword repeated on purpose: a phrase from the
+// target's title or main heading, used by at least exactMatchShare of at
+// least MinExactMatchPages linking pages
+func (t *anchorTarget) overOptimized() (string, *anchorUse) {
+	if t.pages < MinExactMatchPages {
+		return "", nil
+	}
+	for text, use := range t.texts {
+		if float64(use.pages) < exactMatchShare*float64(t.pages) || len(strings.Fields(text)) < exactMatchWords {
+			continue
+		}
+		for _, heading := range t.headings {
+			if strings.Contains(heading, text) {
+				return text, use
+			}
+		}
+	}
+	return "", nil
+}
+
+// report lists every anchor text of the links to each page, by target URL
+// and then by how many pages use it
+func (a *anchorSamples) report() []AnchorText {
+	var anchors []AnchorText
+	for url, target := range a.targets {
+		overOptimized, _ := target.overOptimized()
+		for text, use := range target.texts {
+			anchor := AnchorText{
+				Target:  url,
+				Text:    use.text,
+				Pages:   use.pages,
+				Share:   float64(use.pages) / float64(target.pages),
+				Example: use.example,
+			}
+			switch {
+			case text == "":
+				anchor.Flag = AnchorEmpty
+			case genericAnchors[text]:
+				anchor.Flag = AnchorGeneric
+			case text == overOptimized:
+				anchor.Flag = AnchorOverOptimized
+			}
+			anchors = append(anchors, anchor)
+		}
+	}
+	sort.Slice(anchors, func(i, j int) bool {
+		a, b := anchors[i], anchors[j]
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		if a.Pages != b.Pages {
+			return a.Pages > b.Pages
+		}
+		return a.Text < b.Text
+	})
+	return anchors
+}
+
+// exactMatchIssues flags the pages whose internal links nearly all use the
+// same keyword-rich anchor text
+func (a *anchorSamples) exactMatchIssues() []Issue {
+	var issues []Issue
+	for url, target := range a.targets {
+		_, use := target.overOptimized()
+		if use == nil {
+			continue
+		}
+		percent := use.pages * 100 / target.pages
+		issues = append(issues, Issue{
+			Type:           IssueExactAnchor,
+			Severity:       models.SeverityInfo,
+			URL:            url,
+			Message:        i18n.T("issue.exact_match_anchor.message", percent, target.pages),
+			Value:          use.text,
+			Recommendation: i18n.T("issue.exact_match_anchor.recommendation"),
+		})
+	}
+	return issues
+}
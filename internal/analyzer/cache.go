@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// MinAssetCacheTTL is the shortest browser cache lifetime expected of a
+// static asset (image, script, or stylesheet) on the crawled site
+const MinAssetCacheTTL = 24 * time.Hour
+
+// pageCacheIssue checks whether an HTML page can be cached at all: it is
+// flagged for Cache-Control: no-store, or when it sends no caching headers
+// and no ETag or Last-Modified to revalidate with. Results without response
+// headers, such as older CSV imports, are skipped.
+func pageCacheIssue(result *models.PageResult) (Issue, bool) {
+	if result.StatusCode != 200 || result.Error != "" || result.Headers == nil ||
+		!strings.Contains(result.Headers["Content-Type"], "text/html") {
+		return Issue{}, false
+	}
+
+	policy := models.ParseCachePolicy(result.Headers)
+	var value string
+	switch {
+	case policy.NoStore:
+		value = "no-store"
+	case policy.CacheControl == "" && policy.Expires == "" && !policy.HasValidator:
+		value = "no caching headers"
+	default:
+		return Issue{}, false
+	}
+	return Issue{
+		Type:           IssueUncacheablePage,
+		Severity:       models.SeverityInfo,
+		URL:            result.URL,
+		Message:        i18n.T("issue.uncacheable_page.message", value),
+		Value:          value,
+		Recommendation: i18n.T("issue.uncacheable_page.recommendation"),
+	}, true
+}
+
+// assetCacheIssue checks that a static asset stays fresh in the browser
+// cache for at least MinAssetCacheTTL. The issue's URL is the asset's, so
+// it is reported once however many pages load it.
+func assetCacheIssue(assetURL string, policy models.CachePolicy) (Issue, bool) {
+	ttl := policy.FreshFor()
+	if ttl >= MinAssetCacheTTL {
+		return Issue{}, false
+	}
+
+	value := formatTTL(ttl)
+	message := i18n.T("issue.short_cache_ttl.message", value)
+	if ttl == 0 {
+		value = "0"
+		message = i18n.T("issue.short_cache_ttl.uncached_message")
+	}
+	return Issue{
+		Type:           IssueShortCacheTTL,
+		Severity:       models.SeverityWarning,
+		URL:            assetURL,
+		Message:        message,
+		Value:          value,
+		Recommendation: i18n.T("issue.short_cache_ttl.recommendation"),
+	}, true
+}
+
+// formatTTL renders a cache lifetime in its largest whole unit
+func formatTTL(d time.Duration) string {
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
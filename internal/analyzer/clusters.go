@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// CanonicalCluster is a canonical target together with the crawled pages
+// whose canonical points to it
+type CanonicalCluster struct {
+	Canonical  string `json:"canonical"`   // Resolved target URL
+	Crawled    bool   `json:"crawled"`     // Whether the target itself was crawled
+	StatusCode int    `json:"status_code"` // The target's status, 0 unless crawled
+	Indexable  bool   `json:"indexable"`   // Whether the target is indexable
+
+	Members []CanonicalMember `json:"members"`
+	// IndexableDuplicates are indexable pages with the same text as the
+	// target or one of its members that don't point to the target, and so
+	// compete with it in search results
+	IndexableDuplicates []string `json:"indexable_duplicates"`
+
+	MemberCount    int `json:"member_count"`
+	DuplicateCount int `json:"duplicate_count"`
+}
+
+// CanonicalMember is a page whose canonical points to a cluster's target
+type CanonicalMember struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	SameText   bool   `json:"same_text"` // Whether its text is the target's
+}
+
+// CanonicalClusters groups a crawl's pages by the canonical they point to,
+// largest cluster first. Targets no other page points to are left out.
+func CanonicalClusters(results []*models.PageResult) []CanonicalCluster {
+	byKey := make(map[string]*models.PageResult, len(results))
+	for _, result := range results {
+		byKey[pageKey(result.URL)] = result
+	}
+
+	clusters := make(map[string]*CanonicalCluster) // By pageKey of the target
+	for _, result := range results {
+		if result.Error != "" || !result.Canonicalized() {
+			continue
+		}
+		canonical, err := utils.ResolveURL(result.URL, result.Canonical)
+		if err != nil || pageKey(canonical) == pageKey(result.URL) {
+			continue
+		}
+		key := pageKey(canonical)
+		cluster := clusters[key]
+		if cluster == nil {
+			cluster = &CanonicalCluster{Canonical: canonical}
+			if target := byKey[key]; target != nil {
+				cluster.Crawled = true
+				cluster.StatusCode = target.StatusCode
+				cluster.Indexable = target.Indexable()
+			}
+			clusters[key] = cluster
+		}
+		target := byKey[key]
+		cluster.Members = append(cluster.Members, CanonicalMember{
+			URL:        result.URL,
+			StatusCode: result.StatusCode,
+			SameText:   target != nil && result.TextHash != "" && result.TextHash == target.TextHash,
+		})
+	}
+
+	// Indexable pages sharing text with a cluster, by text hash
+	indexable := make(map[string][]string)
+	for _, result := range results {
+		if result.TextHash != "" && result.Indexable() {
+			indexable[result.TextHash] = append(indexable[result.TextHash], result.URL)
+		}
+	}
+
+	sorted := make([]CanonicalCluster, 0, len(clusters))
+	for key, cluster := range clusters {
+		hashes := make(map[string]bool)
+		if target := byKey[key]; target != nil && target.TextHash != "" {
+			hashes[target.TextHash] = true
+		}
+		for _, member := range cluster.Members {
+			if page := byKey[pageKey(member.URL)]; page.TextHash != "" {
+				hashes[page.TextHash] = true
+			}
+		}
+		for hash := range hashes {
+			for _, url := range indexable[hash] {
+				if pageKey(url) != key {
+					cluster.IndexableDuplicates = append(cluster.IndexableDuplicates, url)
+				}
+			}
+		}
+		slices.SortFunc(cluster.Members, func(a, b CanonicalMember) int { return strings.Compare(a.URL, b.URL) })
+		slices.Sort(cluster.IndexableDuplicates)
+		if cluster.IndexableDuplicates == nil {
+			cluster.IndexableDuplicates = []string{}
+		}
+		cluster.MemberCount = len(cluster.Members)
+		cluster.DuplicateCount = len(cluster.IndexableDuplicates)
+		sorted = append(sorted, *cluster)
+	}
+	slices.SortFunc(sorted, func(a, b CanonicalCluster) int {
+		if size := (b.MemberCount + b.DuplicateCount) - (a.MemberCount + a.DuplicateCount); size != 0 {
+			return size
+		}
+		return strings.Compare(a.Canonical, b.Canonical)
+	})
+	return sorted
+}
@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"math/bits"
+	"slices"
+	"strconv"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+const (
+	// nearDuplicateBits is the most bits two pages' simhashes can differ in
+	// for their text to count as nearly the same
+	nearDuplicateBits = 3
+
+	// nearDuplicateMinWords is the fewest words a page needs for the
+	// near-duplicate check; shorter pages, such as forms and stubs, are
+	// mostly the site's template and all look alike
+	nearDuplicateMinWords = 50
+
+	// simHashBands is how many slices a simhash is cut into to find
+	// candidates. Two simhashes within nearDuplicateBits of each other have
+	// at least one slice in common as long as this is larger.
+	simHashBands = 4
+
+	// duplicateExamples is how many of the other pages an issue's value lists
+	duplicateExamples = 3
+)
+
+// duplicatePage is what the duplicate content checks know of a crawled page
+type duplicatePage struct {
+	url      string
+	textHash string
+	simHash  uint64
+	words    int
+}
+
+// duplicateSamples collects the text fingerprints of the indexable pages,
+// which can only be compared with each other once the crawl is done
+type duplicateSamples struct {
+	pages []duplicatePage
+}
+
+// add records a page's fingerprints. Pages search engines won't index as
+// themselves are left out, since their duplicates don't compete.
+func (d *duplicateSamples) add(result *models.PageResult) {
+	if result.TextHash == "" || !result.Indexable() {
+		return
+	}
+	page := duplicatePage{url: result.URL, textHash: result.TextHash, words: result.WordCount}
+	if simHash, err := strconv.ParseUint(result.SimHash, 16, 64); err == nil {
+		page.simHash = simHash
+	} else {
+		page.words = 0 // Not compared for near duplicates
+	}
+	d.pages = append(d.pages, page)
+}
+
+// issues flags pages with the same text as other pages, and pages whose text
+// is nearly the same as other pages'
+func (d *duplicateSamples) issues() []Issue {
+	byText := make(map[string][]string)
+	for _, page := range d.pages {
+		byText[page.textHash] = append(byText[page.textHash], page.url)
+	}
+
+	var issues []Issue
+	for _, page := range d.pages {
+		others := slices.DeleteFunc(slices.Clone(byText[page.textHash]), func(url string) bool { return url == page.url })
+		if len(others) == 0 {
+			continue
+		}
+		slices.Sort(others)
+		issues = append(issues, Issue{
+			Type:           IssueDuplicateContent,
+			Severity:       models.SeverityWarning,
+			URL:            page.url,
+			Message:        i18n.T("issue.duplicate_content.message", len(others)),
+			Value:          joinExamples(others, duplicateExamples),
+			Recommendation: i18n.T("issue.duplicate_content.recommendation"),
+		})
+	}
+	return append(issues, d.nearDuplicateIssues()...)
+}
+
+// nearDuplicateIssues flags pages whose simhash is within nearDuplicateBits
+// of another page's. Pages are only compared with the pages sharing a slice
+// of their simhash, rather than with every other page. Exact duplicates are
+// reported as such instead.
+func (d *duplicateSamples) nearDuplicateIssues() []Issue {
+	const bandBits = 64 / simHashBands
+	bands := make(map[uint64][]int) // Band number and value -> indexes into d.pages
+	for i, page := range d.pages {
+		if page.words < nearDuplicateMinWords {
+			continue
+		}
+		for band := 0; band < simHashBands; band++ {
+			value := page.simHash >> (band * bandBits) & (1<<bandBits - 1)
+			key := uint64(band)<<bandBits | value
+			bands[key] = append(bands[key], i)
+		}
+	}
+
+	near := make(map[int][]string)
+	for _, members := range bands {
+		for x, i := range members {
+			for _, j := range members[x+1:] {
+				a, b := d.pages[i], d.pages[j]
+				if a.textHash == b.textHash || bits.OnesCount64(a.simHash^b.simHash) > nearDuplicateBits {
+					continue
+				}
+				// A pair sharing several bands is found once per band
+				if !slices.Contains(near[i], b.url) {
+					near[i] = append(near[i], b.url)
+					near[j] = append(near[j], a.url)
+				}
+			}
+		}
+	}
+
+	var issues []Issue
+	for i, others := range near {
+		slices.Sort(others)
+		issues = append(issues, Issue{
+			Type:           IssueNearDuplicate,
+			Severity:       models.SeverityInfo,
+			URL:            d.pages[i].url,
+			Message:        i18n.T("issue.near_duplicate_content.message", len(others)),
+			Value:          joinExamples(others, duplicateExamples),
+			Recommendation: i18n.T("issue.near_duplicate_content.recommendation"),
+		})
+	}
+	return issues
+}
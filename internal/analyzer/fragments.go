@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// fragmentExamples is how many broken links an issue's value lists
+const fragmentExamples = 3
+
+// fragmentSamples collects the #fragment links of every page and the ids
+// each page has, which can only be matched once the crawl is done
+type fragmentSamples struct {
+	links   map[string][]string        // Source page URL -> fragment links
+	anchors map[string]map[string]bool // By pageKey of the URL; only pages whose ids are all known
+}
+
+// add records a page's fragment links and, when the whole page was parsed,
+// the ids and names its elements have
+func (f *fragmentSamples) add(result *models.PageResult) {
+	if f.links == nil {
+		f.links = make(map[string][]string)
+		f.anchors = make(map[string]map[string]bool)
+	}
+	if len(result.FragmentLinks) > 0 {
+		f.links[result.URL] = result.FragmentLinks
+	}
+	// A page cut short may have its ids past the cut, and an error page
+	// isn't the one the link meant
+	if result.Error != "" || result.StatusCode != 200 || result.BodyTruncated || !isHTML(result) {
+		return
+	}
+	anchors := make(map[string]bool, len(result.Anchors))
+	for _, anchor := range result.Anchors {
+		anchors[anchor] = true
+	}
+	f.anchors[pageKey(result.URL)] = anchors
+}
+
+// isHTML reports whether a page was served as HTML, assuming so when it has
+// no Content-Type
+func isHTML(result *models.PageResult) bool {
+	return result.ContentType == "" || strings.Contains(strings.ToLower(result.ContentType), "html")
+}
+
+// issues flags pages linking to a #fragment that no element of the crawled
+// target page has as its id or name. Links to pages that weren't crawled, or
+// whose ids aren't all known, are left out.
+func (f *fragmentSamples) issues() []Issue {
+	var issues []Issue
+	for source, links := range f.links {
+		var broken []string
+		for _, link := range links {
+			target, fragment, _ := strings.Cut(link, "#")
+			anchors, ok := f.anchors[pageKey(target)]
+			if !ok || !checkedFragment(fragment) {
+				continue
+			}
+			if decoded, err := url.PathUnescape(fragment); err == nil {
+				fragment = decoded
+			}
+			if !anchors[fragment] {
+				broken = append(broken, link)
+			}
+		}
+		if len(broken) == 0 {
+			continue
+		}
+		slices.Sort(broken)
+		issues = append(issues, Issue{
+			Type:           IssueBrokenAnchor,
+			Severity:       models.SeverityWarning,
+			URL:            source,
+			Message:        i18n.T("issue.broken_anchor.message", len(broken)),
+			Value:          joinExamples(broken, fragmentExamples),
+			Recommendation: i18n.T("issue.broken_anchor.recommendation"),
+		})
+	}
+	return issues
+}
+
+// checkedFragment reports whether a fragment is meant to point to an
+// element. "#top" scrolls to the top without one, "#!" and "#/" are routes
+// of single-page apps, and "#:~:text=" highlights text.
+func checkedFragment(fragment string) bool {
+	return !strings.EqualFold(fragment, "top") &&
+		!strings.HasPrefix(fragment, "!") &&
+		!strings.HasPrefix(fragment, "/") &&
+		!strings.HasPrefix(fragment, ":~:")
+}
@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// Freshness is how recently the crawled HTML pages were last modified
+type Freshness struct {
+	Buckets []FreshnessBucket `json:"buckets"`
+	Unknown int               `json:"unknown"` // Pages with no known date
+}
+
+// FreshnessBucket counts the pages last modified within an age range
+type FreshnessBucket struct {
+	Bucket string `json:"bucket"`
+	Pages  int    `json:"pages"`
+}
+
+const day = 24 * time.Hour
+
+// freshnessBuckets are the age ranges pages are counted in, youngest first.
+// The last bucket has no upper limit.
+var freshnessBuckets = []struct {
+	name   string
+	maxAge time.Duration
+}{
+	{"under_30d", 30 * day},
+	{"30_90d", 90 * day},
+	{"90_180d", 180 * day},
+	{"180_365d", 365 * day},
+	{"1_2y", 730 * day},
+	{"over_2y", 0},
+}
+
+// LastModified returns the best known date a page's content last changed:
+// its article:modified_time, then its sitemap <lastmod>, then its
+// article:published_time. It returns nil when none is known.
+func LastModified(result *models.PageResult) *time.Time {
+	switch {
+	case result.ModifiedAt != nil:
+		return result.ModifiedAt
+	case result.SitemapLastMod != nil:
+		return result.SitemapLastMod
+	default:
+		return result.PublishedAt
+	}
+}
+
+// freshnessSamples collects the last modified dates of HTML pages
+type freshnessSamples struct {
+	dates   []time.Time
+	unknown int
+}
+
+// add records a page's last modified date. Pages other than successful HTML
+// responses are left out.
+func (f *freshnessSamples) add(result *models.PageResult) {
+	if result.StatusCode != 200 || result.Error != "" {
+		return
+	}
+	if contentType := result.Headers["Content-Type"]; contentType != "" && !strings.Contains(contentType, "text/html") {
+		return
+	}
+	if date := LastModified(result); date != nil {
+		f.dates = append(f.dates, *date)
+	} else {
+		f.unknown++
+	}
+}
+
+// report counts the pages by age at now, or returns nil when no page has a
+// known date
+func (f *freshnessSamples) report(now time.Time) *Freshness {
+	if len(f.dates) == 0 {
+		return nil
+	}
+	freshness := &Freshness{
+		Buckets: make([]FreshnessBucket, len(freshnessBuckets)),
+		Unknown: f.unknown,
+	}
+	for i, bucket := range freshnessBuckets {
+		freshness.Buckets[i].Bucket = bucket.name
+	}
+	for _, date := range f.dates {
+		age := now.Sub(date)
+		i := 0
+		for i < len(freshnessBuckets)-1 && age >= freshnessBuckets[i].maxAge {
+			i++
+		}
+		freshness.Buckets[i].Pages++
+	}
+	return freshness
+}
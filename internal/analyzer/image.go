@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/internal/utils"
 	"github.com/dillonlara115/barracuda/pkg/models"
 )
 
@@ -20,9 +23,12 @@ type ImageSizeInfo struct {
 	SizeKB int64
 	Size   int64
 	Error  error
+	// Cache is the response's caching policy, nil unless it returned 200
+	Cache *models.CachePolicy
 }
 
-// CheckImageSize fetches image size using HEAD request
+// CheckImageSize fetches image size using HEAD request. It works for any
+// static asset, and also records the response's caching policy.
 func CheckImageSize(imageURL string, timeout time.Duration) ImageSizeInfo {
 	info := ImageSizeInfo{
 		URL: imageURL,
@@ -48,6 +54,7 @@ func CheckImageSize(imageURL string, timeout time.Duration) ImageSizeInfo {
 
 	// Check Content-Length header
 	if resp.StatusCode == 200 {
+		info.Cache = cachePolicy(resp.Header)
 		contentLength := resp.ContentLength
 		if contentLength > 0 {
 			info.Size = contentLength
@@ -73,6 +80,7 @@ func CheckImageSize(imageURL string, timeout time.Duration) ImageSizeInfo {
 		defer getResp.Body.Close()
 
 		if getResp.StatusCode == 200 {
+			info.Cache = cachePolicy(getResp.Header)
 			// Try to get size from Content-Length
 			if getResp.ContentLength > 0 {
 				info.Size = getResp.ContentLength
@@ -96,59 +104,192 @@ func CheckImageSize(imageURL string, timeout time.Duration) ImageSizeInfo {
 	return info
 }
 
+// cachePolicy parses the caching headers of an asset response
+func cachePolicy(header http.Header) *models.CachePolicy {
+	flat := make(map[string]string, len(header))
+	for name := range header {
+		flat[name] = header.Get(name)
+	}
+	policy := models.ParseCachePolicy(flat)
+	return &policy
+}
+
 // AnalyzeImages analyzes images from page results and detects issues
 func AnalyzeImages(results []*models.PageResult, timeout time.Duration) []Issue {
 	var issues []Issue
-	imageSizeCache := make(map[string]ImageSizeInfo)
-
+	checker := newImageChecker(timeout)
 	for _, result := range results {
-		if result.StatusCode != 200 || result.Error != "" {
-			continue
+		issues = append(issues, checker.pageIssues(result)...)
+	}
+	return issues
+}
+
+// imageChecker runs the image and static asset checks, fetching each URL
+// only once across pages. It is safe for concurrent use.
+type imageChecker struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	sizes   map[string]ImageSizeInfo
+	// cacheReported holds assets already reported for a short cache TTL,
+	// since the issue is per asset rather than per page
+	cacheReported map[string]bool
+	formats       map[string]probedFormat // Large images' formats, by URL
+	savings       ImageSavings
+}
+
+// probedFormat is the format of a large image, if it could be read
+type probedFormat struct {
+	format ImageFormat
+	ok     bool
+}
+
+// newImageChecker creates an image checker using timeout for size requests
+func newImageChecker(timeout time.Duration) *imageChecker {
+	return &imageChecker{
+		timeout:       timeout,
+		sizes:         make(map[string]ImageSizeInfo),
+		cacheReported: make(map[string]bool),
+		formats:       make(map[string]probedFormat),
+	}
+}
+
+// size returns an image's size, fetching it on first use
+func (c *imageChecker) size(imageURL string) ImageSizeInfo {
+	c.mu.Lock()
+	info, cached := c.sizes[imageURL]
+	c.mu.Unlock()
+	if cached {
+		return info
+	}
+
+	// Fetch without holding the lock so other pages aren't held up
+	info = CheckImageSize(imageURL, c.timeout)
+	c.mu.Lock()
+	c.sizes[imageURL] = info
+	c.mu.Unlock()
+	return info
+}
+
+// format returns a large image's format and dimensions, probing it on first
+// use, or false when it couldn't be read
+func (c *imageChecker) format(imageURL string) (ImageFormat, bool) {
+	c.mu.Lock()
+	probe, cached := c.formats[imageURL]
+	c.mu.Unlock()
+	if cached {
+		return probe.format, probe.ok
+	}
+
+	// Probe without holding the lock, as in size
+	format, err := ProbeImage(imageURL, c.timeout)
+	probe = probedFormat{format: format, ok: err == nil}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, cached := c.formats[imageURL]; !cached {
+		c.formats[imageURL] = probe
+		if probe.ok {
+			c.savings.Images++
 		}
+	}
+	return probe.format, probe.ok
+}
 
-		for _, img := range result.Images {
-			// Check for missing alt text
-			if img.Alt == "" {
-				issues = append(issues, Issue{
-					Type:           IssueMissingImageAlt,
-					Severity:       "warning",
-					URL:            result.URL,
-					Message:        fmt.Sprintf("Image missing alt text: %s", img.URL),
-					Value:          img.URL,
-					Recommendation: "Add descriptive alt text for accessibility and SEO",
-				})
-			}
+// savingsReport returns the estimated image savings of the pages checked so
+// far, or nil when no large image could be read
+func (c *imageChecker) savingsReport() *ImageSavings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.savings.Images == 0 {
+		return nil
+	}
+	savings := c.savings
+	return &savings
+}
 
-			// Check image size (with caching)
-			if sizeInfo, cached := imageSizeCache[img.URL]; cached {
-				if sizeInfo.SizeKB > MaxImageSizeKB {
-					issues = append(issues, Issue{
-						Type:           IssueLargeImage,
-						Severity:       "warning",
-						URL:            result.URL,
-						Message:        fmt.Sprintf("Large image detected: %s (%d KB)", img.URL, sizeInfo.SizeKB),
-						Value:          fmt.Sprintf("%s (%d KB)", img.URL, sizeInfo.SizeKB),
-						Recommendation: fmt.Sprintf("Optimize image to reduce size below %d KB", MaxImageSizeKB),
-					})
-				}
-			} else {
-				// Fetch image size
-				sizeInfo := CheckImageSize(img.URL, timeout)
-				imageSizeCache[img.URL] = sizeInfo
-
-				if sizeInfo.Error == nil && sizeInfo.SizeKB > MaxImageSizeKB {
-					issues = append(issues, Issue{
-						Type:           IssueLargeImage,
-						Severity:       "warning",
-						URL:            result.URL,
-						Message:        fmt.Sprintf("Large image detected: %s (%d KB)", img.URL, sizeInfo.SizeKB),
-						Value:          fmt.Sprintf("%s (%d KB)", img.URL, sizeInfo.SizeKB),
-						Recommendation: fmt.Sprintf("Optimize image to reduce size below %d KB", MaxImageSizeKB),
-					})
-				}
+// pageIssues checks the images of one page for missing alt text and size,
+// and estimates how much lighter its large images could be
+func (c *imageChecker) pageIssues(result *models.PageResult) []Issue {
+	if result.StatusCode != 200 || result.Error != "" {
+		return nil
+	}
+
+	var issues []Issue
+	var large int
+	var weight, webpWeight, avifWeight int64
+	for _, img := range result.Images {
+		// Check for missing alt text
+		if img.Alt == "" {
+			issues = append(issues, Issue{
+				Type:           IssueMissingImageAlt,
+				Severity:       models.SeverityWarning,
+				URL:            result.URL,
+				Message:        i18n.T("issue.missing_image_alt.message", img.URL),
+				Value:          img.URL,
+				Recommendation: i18n.T("issue.missing_image_alt.recommendation"),
+			})
+		}
+
+		// Check image size
+		sizeInfo := c.size(img.URL)
+		if sizeInfo.Error == nil && sizeInfo.SizeKB > MaxImageSizeKB {
+			issues = append(issues, Issue{
+				Type:           IssueLargeImage,
+				Severity:       models.SeverityWarning,
+				URL:            result.URL,
+				Message:        i18n.T("issue.large_image.message", img.URL, sizeInfo.SizeKB),
+				Value:          fmt.Sprintf("%s (%d KB)", img.URL, sizeInfo.SizeKB),
+				Recommendation: i18n.T("issue.large_image.recommendation", MaxImageSizeKB),
+			})
+			if format, ok := c.format(img.URL); ok {
+				webp, avif := estimateImage(sizeInfo.Size, format, img)
+				large++
+				weight += sizeInfo.Size
+				webpWeight += webp
+				avifWeight += avif
 			}
 		}
+		if issue, ok := c.cacheIssue(result, img.URL, sizeInfo); ok {
+			issues = append(issues, issue)
+		}
 	}
 
-	return issues
+	for _, asset := range result.Assets {
+		if issue, ok := c.cacheIssue(result, asset, c.size(asset)); ok {
+			issues = append(issues, issue)
+		}
+	}
+
+	if large > 0 {
+		c.mu.Lock()
+		c.savings.Pages++
+		c.savings.Bytes += weight
+		c.savings.WebPBytes += webpWeight
+		c.savings.AVIFBytes += avifWeight
+		c.mu.Unlock()
+		if issue, ok := imageSavingsIssue(result, weight, webpWeight, avifWeight); ok {
+			issues = append(issues, issue)
+		}
+	}
+	return withDocKeys(issues)
+}
+
+// cacheIssue checks the cache lifetime of an asset served from the page's own
+// host, reporting each asset only once. Third-party assets are skipped since
+// their caching is out of the site's control.
+func (c *imageChecker) cacheIssue(result *models.PageResult, assetURL string, info ImageSizeInfo) (Issue, bool) {
+	if info.Cache == nil || !utils.IsSameDomain(result.URL, assetURL) {
+		return Issue{}, false
+	}
+	issue, ok := assetCacheIssue(assetURL, *info.Cache)
+	if !ok {
+		return Issue{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cacheReported[assetURL] {
+		return Issue{}, false
+	}
+	c.cacheReported[assetURL] = true
+	return issue, true
 }
@@ -0,0 +1,198 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	_ "image/gif" // Register decoders for image.DecodeConfig
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+const (
+	// MinImageSavingsKB is the estimated saving on a page's large images
+	// above which the page is reported
+	MinImageSavingsKB = 50
+
+	// imageProbeBytes is how much of a large image is downloaded to read its
+	// format and dimensions
+	imageProbeBytes = 64 * 1024
+
+	// displayDensity is the device pixel ratio images are allowed to be
+	// sized for, so high-density screens still get sharp images
+	displayDensity = 2
+)
+
+// conversionRatios estimates an image's size converted to WebP and AVIF as
+// a fraction of its current size, by current format. They are rough
+// averages from published comparisons at similar visual quality; actual
+// savings vary from image to image.
+var conversionRatios = map[string]struct{ webp, avif float64 }{
+	"jpeg": {webp: 0.70, avif: 0.50},
+	"png":  {webp: 0.74, avif: 0.60},
+	"gif":  {webp: 0.60, avif: 0.50},
+	"webp": {webp: 1, avif: 0.80},
+	"avif": {webp: 1, avif: 1},
+}
+
+// ImageFormat is an image's encoding and intrinsic size
+type ImageFormat struct {
+	Format string // "jpeg", "png", "gif", "webp", or "avif"
+	Width  int
+	Height int
+}
+
+// ImageSavings estimates how much lighter the crawled pages would be with
+// their large images converted to WebP or AVIF and resized to the size they
+// are displayed at. Bytes are summed over the pages showing each image, so
+// they measure page weight rather than storage.
+type ImageSavings struct {
+	Images    int   `json:"images"`     // Distinct large images whose format and dimensions were read
+	Pages     int   `json:"pages"`      // Pages showing any of them
+	Bytes     int64 `json:"bytes"`      // Current weight of the images
+	WebPBytes int64 `json:"webp_bytes"` // Estimated weight as WebP at display size
+	AVIFBytes int64 `json:"avif_bytes"` // Estimated weight as AVIF at display size
+}
+
+// Saved returns the estimated reduction with AVIF, the smaller format
+func (s *ImageSavings) Saved() int64 {
+	return s.Bytes - s.AVIFBytes
+}
+
+// ProbeImage downloads the start of an image to read its format and
+// dimensions. JPEG, PNG, GIF, WebP, and AVIF are recognized.
+func ProbeImage(imageURL string, timeout time.Duration) (ImageFormat, error) {
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return ImageFormat{}, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", imageProbeBytes-1))
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ImageFormat{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return ImageFormat{}, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	// Servers that ignore Range send the whole image; stop at the limit
+	head, err := io.ReadAll(io.LimitReader(resp.Body, imageProbeBytes))
+	if err != nil && len(head) == 0 {
+		return ImageFormat{}, err
+	}
+	return decodeImageHeader(head)
+}
+
+// decodeImageHeader reads the format and dimensions from the start of an
+// image file
+func decodeImageHeader(head []byte) (ImageFormat, error) {
+	if format, ok := webpHeader(head); ok {
+		return format, nil
+	}
+	if format, ok := avifHeader(head); ok {
+		return format, nil
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(head))
+	if err != nil {
+		return ImageFormat{}, fmt.Errorf("unrecognized image: %w", err)
+	}
+	return ImageFormat{Format: format, Width: config.Width, Height: config.Height}, nil
+}
+
+// webpHeader reads the canvas size of a WebP file from its first chunk
+func webpHeader(head []byte) (ImageFormat, bool) {
+	if len(head) < 30 || string(head[0:4]) != "RIFF" || string(head[8:12]) != "WEBP" {
+		return ImageFormat{}, false
+	}
+	format := ImageFormat{Format: "webp"}
+	switch string(head[12:16]) {
+	case "VP8 ": // Lossy: 14-bit dimensions after the frame start code
+		format.Width = int(binary.LittleEndian.Uint16(head[26:28]) & 0x3fff)
+		format.Height = int(binary.LittleEndian.Uint16(head[28:30]) & 0x3fff)
+	case "VP8L": // Lossless: 14-bit dimensions minus one after the signature byte
+		bits := binary.LittleEndian.Uint32(head[21:25])
+		format.Width = int(bits&0x3fff) + 1
+		format.Height = int(bits>>14&0x3fff) + 1
+	case "VP8X": // Extended: 24-bit canvas dimensions minus one
+		format.Width = int(uint32(head[24])|uint32(head[25])<<8|uint32(head[26])<<16) + 1
+		format.Height = int(uint32(head[27])|uint32(head[28])<<8|uint32(head[29])<<16) + 1
+	default:
+		return ImageFormat{}, false
+	}
+	return format, true
+}
+
+// avifHeader reads the size of an AVIF file from its first image spatial
+// extents ("ispe") property
+func avifHeader(head []byte) (ImageFormat, bool) {
+	if len(head) < 12 || string(head[4:8]) != "ftyp" {
+		return ImageFormat{}, false
+	}
+	if brand := string(head[8:12]); brand != "avif" && brand != "avis" {
+		return ImageFormat{}, false
+	}
+	format := ImageFormat{Format: "avif"}
+	// The property box is a size, "ispe", version and flags, then the width
+	// and height
+	if i := bytes.Index(head, []byte("ispe")); i >= 0 && i+16 <= len(head) {
+		format.Width = int(binary.BigEndian.Uint32(head[i+8 : i+12]))
+		format.Height = int(binary.BigEndian.Uint32(head[i+12 : i+16]))
+	}
+	return format, true
+}
+
+// displayScale estimates the fraction of an image's pixels needed to show
+// it at the size set by its width and height attributes, on screens up to
+// displayDensity. It is 1 when the attributes are missing or the image is
+// no larger than needed.
+func displayScale(format ImageFormat, img models.Image) float64 {
+	if img.Width == 0 || format.Width == 0 || format.Height == 0 {
+		return 1
+	}
+	scaleX := min(1, float64(img.Width*displayDensity)/float64(format.Width))
+	scaleY := scaleX
+	if img.Height > 0 {
+		scaleY = min(1, float64(img.Height*displayDensity)/float64(format.Height))
+	}
+	return scaleX * scaleY
+}
+
+// estimateImage returns the estimated size of an image of size bytes
+// converted to WebP and to AVIF, at the size the page displays it. Sizes
+// are assumed to shrink with the number of pixels.
+func estimateImage(size int64, format ImageFormat, img models.Image) (webp, avif int64) {
+	ratios, ok := conversionRatios[format.Format]
+	if !ok {
+		return size, size
+	}
+	scaled := float64(size) * displayScale(format, img)
+	return int64(scaled * ratios.webp), int64(scaled * ratios.avif)
+}
+
+// imageSavingsIssue reports a page whose large images could be made lighter
+// by at least MinImageSavingsKB
+func imageSavingsIssue(result *models.PageResult, weight, webpWeight, avifWeight int64) (Issue, bool) {
+	saved := weight - avifWeight
+	if saved < MinImageSavingsKB*1024 {
+		return Issue{}, false
+	}
+	percent := saved * 100 / weight
+	return Issue{
+		Type:           IssueImageSavings,
+		Severity:       models.SeverityInfo,
+		URL:            result.URL,
+		Message:        i18n.T("issue.image_savings.message", (weight-webpWeight)/1024, saved/1024, percent),
+		Value:          fmt.Sprintf("%d KB (%d%%)", saved/1024, percent),
+		Recommendation: i18n.T("issue.image_savings.recommendation"),
+	}, true
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// maxSlowestPages is how many pages Summary.SlowestPages lists
+const maxSlowestPages = 10
+
+// Incremental analyzes pages one at a time as they are crawled, keeping
+// running totals so the summary is ready as soon as the crawl finishes.
+// Add is safe to call from the crawler's progress callback.
+type Incremental struct {
+	images *imageChecker // nil when image sizes are not checked
+
+	mu                sync.Mutex
+	summary           Summary
+	totalResponseTime int64
+	latency           latencySamples
+	freshness         freshnessSamples
+	thirdParty        thirdPartySamples
+	anchors           anchorSamples
+	rels              relSamples
+	duplicates        duplicateSamples
+	fragments         fragmentSamples
+	slowPages         []PagePerformance
+}
+
+// NewIncremental creates an analyzer that runs the page checks
+func NewIncremental() *Incremental {
+	return &Incremental{
+		summary: Summary{
+			IssuesByType: make(map[IssueType]int),
+			Issues:       make([]Issue, 0),
+			PagesByDepth: make(map[int]int),
+			ErrorsByCode: make(map[string]int),
+		},
+		latency: newLatencySamples(),
+	}
+}
+
+// NewIncrementalWithImages creates an analyzer that also checks image sizes,
+// using imageTimeout for each size request
+func NewIncrementalWithImages(imageTimeout time.Duration) *Incremental {
+	a := NewIncremental()
+	a.images = newImageChecker(imageTimeout)
+	return a
+}
+
+// Add analyzes one page, folds it into the running summary, and returns the
+// issues found on it
+func (a *Incremental) Add(result *models.PageResult) []Issue {
+	// Run the checks before locking; image checks make network requests
+	issues := PageIssues(result)
+	if a.images != nil {
+		issues = append(issues, a.images.pageIssues(result)...)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s := &a.summary
+	s.TotalPages++
+	a.totalResponseTime += result.ResponseTime
+	a.latency.add(result)
+	a.freshness.add(result)
+	a.thirdParty.add(result)
+	a.anchors.add(result)
+	a.rels.add(result)
+	a.duplicates.add(result)
+	a.fragments.add(result)
+	if result.ResponseTime > 2000 { // Slower than 2 seconds
+		a.slowPages = append(a.slowPages, PagePerformance{
+			URL:          result.URL,
+			ResponseTime: result.ResponseTime,
+		})
+	}
+	if code := result.FailureCode(); code != "" {
+		s.PagesWithErrors++
+		s.ErrorsByCode[code]++
+	}
+	if len(result.RedirectChain) > 0 {
+		s.PagesWithRedirects++
+	}
+	s.PagesByDepth[result.Depth]++
+	s.TotalInternalLinks += len(result.InternalLinks)
+	s.TotalExternalLinks += len(result.ExternalLinks)
+
+	s.Issues = append(s.Issues, issues...)
+	for _, issue := range issues {
+		s.IssuesByType[issue.Type]++
+	}
+	s.TotalIssues = len(s.Issues)
+
+	return issues
+}
+
+// Summary returns the summary of every page added so far. It can be called
+// while pages are still being added; the result is a copy.
+func (a *Incremental) Summary() *Summary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	summary := a.summary
+	summary.Issues = append(make([]Issue, 0, len(a.summary.Issues)), a.summary.Issues...)
+	SortIssues(summary.Issues)
+	summary.IssuesByType = make(map[IssueType]int, len(a.summary.IssuesByType))
+	for issueType, count := range a.summary.IssuesByType {
+		summary.IssuesByType[issueType] = count
+	}
+	summary.PagesByDepth = make(map[int]int, len(a.summary.PagesByDepth))
+	for depth, count := range a.summary.PagesByDepth {
+		summary.PagesByDepth[depth] = count
+	}
+	summary.ErrorsByCode = make(map[string]int, len(a.summary.ErrorsByCode))
+	for code, count := range a.summary.ErrorsByCode {
+		summary.ErrorsByCode[code] = count
+	}
+
+	if summary.TotalPages > 0 {
+		summary.AverageResponseTime = a.totalResponseTime / int64(summary.TotalPages)
+	}
+	summary.ResponseTimes, summary.HostResponseTimes = a.latency.stats()
+	summary.Freshness = a.freshness.report(time.Now())
+	summary.ThirdPartyOrigins = a.thirdParty.report()
+	summary.AddIssues(withDocKeys(a.anchors.exactMatchIssues()))
+	summary.AddIssues(withDocKeys(a.rels.issues()))
+	summary.AddIssues(withDocKeys(a.duplicates.issues()))
+	summary.AddIssues(withDocKeys(a.fragments.issues()))
+	if a.images != nil {
+		summary.ImageSavings = a.images.savingsReport()
+	}
+
+	slowPages := append([]PagePerformance(nil), a.slowPages...)
+	sort.Slice(slowPages, func(i, j int) bool {
+		if slowPages[i].ResponseTime != slowPages[j].ResponseTime {
+			return slowPages[i].ResponseTime > slowPages[j].ResponseTime
+		}
+		return slowPages[i].URL < slowPages[j].URL
+	})
+	if len(slowPages) > maxSlowestPages {
+		slowPages = slowPages[:maxSlowestPages]
+	}
+	summary.SlowestPages = append(make([]PagePerformance, 0, len(slowPages)), slowPages...)
+
+	return &summary
+}
+
+// AnchorTexts returns the anchor texts of the internal links to every page
+// added so far, flagging empty, generic, and over-optimized ones
+func (a *Incremental) AnchorTexts() []AnchorText {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.anchors.report()
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"sort"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// LatencyStats are response time percentiles over a set of pages. Pages
+// whose request failed without a response are left out.
+type LatencyStats struct {
+	Pages int   `json:"pages"`
+	P50   int64 `json:"p50_ms"`
+	P90   int64 `json:"p90_ms"`
+	P99   int64 `json:"p99_ms"`
+	// Time to first byte percentiles; zero for crawls that didn't record it
+	TTFBP50 int64 `json:"ttfb_p50_ms,omitempty"`
+	TTFBP90 int64 `json:"ttfb_p90_ms,omitempty"`
+	TTFBP99 int64 `json:"ttfb_p99_ms,omitempty"`
+}
+
+// HostLatency is the response time percentiles of one host
+type HostLatency struct {
+	Host string `json:"host"`
+	LatencyStats
+}
+
+// latencySamples collects response times per host
+type latencySamples struct {
+	times map[string][]int64
+	ttfbs map[string][]int64
+}
+
+func newLatencySamples() latencySamples {
+	return latencySamples{
+		times: make(map[string][]int64),
+		ttfbs: make(map[string][]int64),
+	}
+}
+
+// add records a page's response time and time to first byte
+func (l latencySamples) add(result *models.PageResult) {
+	if result.StatusCode == 0 {
+		return
+	}
+	host, err := utils.ExtractDomain(result.URL)
+	if err != nil {
+		return
+	}
+	l.times[host] = append(l.times[host], result.ResponseTime)
+	// Results from before TTFB was recorded have it as 0 with a longer
+	// response time
+	if result.TTFB > 0 || result.ResponseTime == 0 {
+		l.ttfbs[host] = append(l.ttfbs[host], result.TTFB)
+	}
+}
+
+// stats returns the percentiles over all hosts and per host, busiest host
+// first
+func (l latencySamples) stats() (LatencyStats, []HostLatency) {
+	var allTimes, allTTFBs []int64
+	hosts := make([]HostLatency, 0, len(l.times))
+	for host, times := range l.times {
+		allTimes = append(allTimes, times...)
+		allTTFBs = append(allTTFBs, l.ttfbs[host]...)
+		hosts = append(hosts, HostLatency{Host: host, LatencyStats: latencyStats(times, l.ttfbs[host])})
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].Pages != hosts[j].Pages {
+			return hosts[i].Pages > hosts[j].Pages
+		}
+		return hosts[i].Host < hosts[j].Host
+	})
+	return latencyStats(allTimes, allTTFBs), hosts
+}
+
+func latencyStats(times, ttfbs []int64) LatencyStats {
+	times = sortedCopy(times)
+	ttfbs = sortedCopy(ttfbs)
+	return LatencyStats{
+		Pages:   len(times),
+		P50:     percentile(times, 50),
+		P90:     percentile(times, 90),
+		P99:     percentile(times, 99),
+		TTFBP50: percentile(ttfbs, 50),
+		TTFBP90: percentile(ttfbs, 90),
+		TTFBP99: percentile(ttfbs, 99),
+	}
+}
+
+func sortedCopy(values []int64) []int64 {
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// percentile returns the nearest-rank percentile p of sorted values, or 0
+// when there are none
+func percentile(sorted []int64, p int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	// Smallest value with at least p% of values at or below it
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
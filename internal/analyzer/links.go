@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// nofollowLinkExamples is how many of a page's nofollowed internal links
+// an issue's value lists
+const nofollowLinkExamples = 3
+
+// nofollowLinkIssue flags a page that links to other pages of the site only
+// with rel=nofollow, sponsored, or ugc, which asks search engines not to
+// credit them and can keep them from being crawled. Pages that are nofollow
+// as a whole are left to their robots directives.
+func nofollowLinkIssue(result *models.PageResult) (Issue, bool) {
+	if result.Error != "" || result.Nofollow() {
+		return Issue{}, false
+	}
+
+	followed := make(map[string]bool)
+	for _, link := range result.Links {
+		if link.Internal && link.Followed() {
+			followed[link.URL] = true
+		}
+	}
+	seen := make(map[string]bool)
+	var nofollowed []string
+	for _, link := range result.Links {
+		if link.Internal && !link.Followed() && !followed[link.URL] && !seen[link.URL] {
+			seen[link.URL] = true
+			nofollowed = append(nofollowed, link.URL)
+		}
+	}
+	if len(nofollowed) == 0 {
+		return Issue{}, false
+	}
+
+	return Issue{
+		Type:           IssueNofollowLink,
+		Severity:       models.SeverityInfo,
+		URL:            result.URL,
+		Message:        i18n.T("issue.nofollow_internal_link.message", len(nofollowed)),
+		Value:          joinExamples(nofollowed, nofollowLinkExamples),
+		Recommendation: i18n.T("issue.nofollow_internal_link.recommendation"),
+	}, true
+}
@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// mobileWordCountTolerance is how much the word counts of a page's desktop
+// and mobile versions may differ, as a fraction of the desktop count, before
+// the mobile version counts as missing content
+const mobileWordCountTolerance = 0.2
+
+// mobileIssues compares a page with what a smartphone crawler was served
+// (crawl --mobile-parity). With mobile-first indexing Google indexes the
+// mobile version, so content, metadata, or directives only on the desktop
+// version are lost to search. A mobile version that isn't an error also
+// needs a viewport to render at the device's width.
+func mobileIssues(result *models.PageResult) []Issue {
+	mobile := result.Mobile
+	if mobile == nil {
+		return nil
+	}
+
+	var issues []Issue
+	if diffs := mobileDifferences(result, mobile); len(diffs) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueMobileParity,
+			Severity:       models.SeverityWarning,
+			URL:            result.URL,
+			Message:        i18n.T("issue.mobile_parity.message", strings.Join(diffs, ", ")),
+			Value:          strings.Join(diffs, ","),
+			Recommendation: i18n.T("issue.mobile_parity.recommendation"),
+		})
+	}
+	if mobile.StatusCode == 200 && mobile.Error == "" && mobile.Viewport == "" {
+		issues = append(issues, Issue{
+			Type:           IssueMissingViewport,
+			Severity:       models.SeverityWarning,
+			URL:            result.URL,
+			Message:        i18n.T("issue.missing_viewport.message"),
+			Recommendation: i18n.T("issue.missing_viewport.recommendation"),
+		})
+	}
+	return issues
+}
+
+// mobileDifferences names the fields that differ between a page and its
+// mobile version. A mobile request that failed is reported as a status
+// difference. Word counts are only compared when neither version was
+// rendered, since the mobile version never is.
+func mobileDifferences(result *models.PageResult, mobile *models.MobileVariant) []string {
+	if mobile.StatusCode != result.StatusCode {
+		return []string{"status " + strconv.Itoa(mobile.StatusCode)}
+	}
+
+	var diffs []string
+	if strings.TrimSpace(mobile.Title) != strings.TrimSpace(result.Title) {
+		diffs = append(diffs, "title")
+	}
+	if strings.TrimSpace(mobile.MetaDesc) != strings.TrimSpace(result.MetaDesc) {
+		diffs = append(diffs, "meta_description")
+	}
+	if mobile.Canonical != result.Canonical {
+		diffs = append(diffs, "canonical")
+	}
+	if mobile.Noindex() != (result.Robots != nil && result.Robots.Noindex) {
+		diffs = append(diffs, "noindex")
+	}
+	if strings.Join(mobile.H1, "\n") != strings.Join(result.H1, "\n") {
+		diffs = append(diffs, "h1")
+	}
+	if !result.Rendered && result.WordCount > 0 &&
+		math.Abs(float64(mobile.WordCount-result.WordCount)) > mobileWordCountTolerance*float64(result.WordCount) {
+		diffs = append(diffs, "word_count "+strconv.Itoa(mobile.WordCount)+"/"+strconv.Itoa(result.WordCount))
+	}
+	return diffs
+}
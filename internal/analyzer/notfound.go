@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// notFoundMinWords is the least text an error page needs to tell visitors
+// the page is missing and where to go instead
+const notFoundMinWords = 10
+
+// NotFoundIssues checks how a site answered a request for a page that doesn't
+// exist (see crawler.Manager.ProbeNotFound). It should answer 404 or 410 at
+// that URL: a 200 gets missing pages indexed as soft 404s, and a redirect,
+// usually to the home page, hides broken links from search engines and
+// visitors alike. The error page itself should have a title, some text, and
+// links back into the site. A request that got no response yields no issues.
+func NotFoundIssues(page *models.PageResult) []Issue {
+	if page == nil || page.StatusCode == 0 {
+		return nil
+	}
+	gone := page.StatusCode == http.StatusNotFound || page.StatusCode == http.StatusGone
+
+	var issues []Issue
+	switch {
+	case len(page.RedirectChain) > 0:
+		// A redirect to an error page still answers 404, just not at the URL
+		target := page.RedirectChain[len(page.RedirectChain)-1]
+		severity := models.SeverityError
+		if gone {
+			severity = models.SeverityWarning
+		}
+		issues = append(issues, Issue{
+			Type:           IssueSoft404,
+			Severity:       severity,
+			URL:            page.URL,
+			Message:        i18n.T("issue.soft_404.redirect_message", target, page.StatusCode),
+			Value:          target,
+			Recommendation: i18n.T("issue.soft_404.recommendation"),
+		})
+	case !gone:
+		severity := models.SeverityError
+		if page.StatusCode >= 400 {
+			severity = models.SeverityWarning
+		}
+		issues = append(issues, Issue{
+			Type:           IssueSoft404,
+			Severity:       severity,
+			URL:            page.URL,
+			Message:        i18n.T("issue.soft_404.message", page.StatusCode),
+			Value:          strconv.Itoa(page.StatusCode),
+			Recommendation: i18n.T("issue.soft_404.recommendation"),
+		})
+	}
+
+	if gone {
+		var problems []string
+		if strings.TrimSpace(page.Title) == "" {
+			problems = append(problems, i18n.T("issue.unhelpful_404.no_title"))
+		}
+		if page.WordCount < notFoundMinWords {
+			problems = append(problems, i18n.T("issue.unhelpful_404.little_text"))
+		}
+		if len(page.InternalLinks) == 0 {
+			problems = append(problems, i18n.T("issue.unhelpful_404.no_links"))
+		}
+		if len(problems) > 0 {
+			issues = append(issues, Issue{
+				Type:           IssueUnhelpful404,
+				Severity:       models.SeverityWarning,
+				URL:            page.URL,
+				Message:        i18n.T("issue.unhelpful_404.message", strings.Join(problems, ", ")),
+				Value:          strconv.Itoa(page.WordCount),
+				Recommendation: i18n.T("issue.unhelpful_404.recommendation"),
+			})
+		}
+	}
+
+	SortIssues(issues)
+	return withDocKeys(issues)
+}
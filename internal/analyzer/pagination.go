@@ -0,0 +1,151 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// maxCanonicalHops is how many canonicals a chain's value follows before it
+// stops, so a long or looping chain can't grow without bound
+const maxCanonicalHops = 5
+
+// relPage is what the canonical and pagination checks know of a crawled page
+type relPage struct {
+	url        string
+	status     int
+	canonical  string // Resolved; "" unless it points to another URL
+	next, prev string
+}
+
+// relSamples collects the canonical, rel=next, and rel=prev targets of every
+// page, which can only be checked against each other once the crawl is done
+type relSamples struct {
+	pages map[string]*relPage // By pageKey of their URL
+}
+
+// pageKey identifies a URL the way PageResult.Canonicalized compares them,
+// ignoring a trailing slash
+func pageKey(url string) string {
+	return strings.TrimSuffix(url, "/")
+}
+
+// add records a page's link targets
+func (r *relSamples) add(result *models.PageResult) {
+	if r.pages == nil {
+		r.pages = make(map[string]*relPage)
+	}
+	page := &relPage{
+		url:    result.URL,
+		status: result.StatusCode,
+		next:   result.RelNext,
+		prev:   result.RelPrev,
+	}
+	if result.Error != "" {
+		page.status = 0
+	}
+	if result.Canonicalized() {
+		if canonical, err := utils.ResolveURL(result.URL, result.Canonical); err == nil {
+			page.canonical = canonical
+		}
+	}
+	r.pages[pageKey(result.URL)] = page
+}
+
+// canonicalTarget returns the crawled page a page's canonical points to when
+// it points somewhere else, or nil
+func (r *relSamples) canonicalTarget(page *relPage) *relPage {
+	if page.canonical == "" {
+		return nil
+	}
+	return r.pages[pageKey(page.canonical)]
+}
+
+// issues flags canonical chains and broken paginated series
+func (r *relSamples) issues() []Issue {
+	var issues []Issue
+	for _, page := range r.pages {
+		if page.status != 200 {
+			continue
+		}
+		if issue, ok := r.canonicalChainIssue(page); ok {
+			issues = append(issues, issue)
+		}
+		issues = append(issues, r.paginationIssues(page)...)
+	}
+	return issues
+}
+
+// canonicalChainIssue flags a page whose canonical points to a page that
+// canonicalizes somewhere else again. Search engines may not follow the
+// chain, and pick a canonical of their own instead.
+func (r *relSamples) canonicalChainIssue(page *relPage) (Issue, bool) {
+	target := r.canonicalTarget(page)
+	if target == nil || target.status != 200 || target.canonical == "" {
+		return Issue{}, false
+	}
+
+	chain := []string{page.url}
+	seen := map[string]bool{pageKey(page.url): true}
+	for hop := page; hop != nil && hop.canonical != "" && len(chain) <= maxCanonicalHops; hop = r.canonicalTarget(hop) {
+		chain = append(chain, hop.canonical)
+		if seen[pageKey(hop.canonical)] {
+			break
+		}
+		seen[pageKey(hop.canonical)] = true
+	}
+
+	return Issue{
+		Type:           IssueCanonicalChain,
+		Severity:       models.SeverityWarning,
+		URL:            page.url,
+		Message:        i18n.T("issue.canonical_chain.message", target.url, target.canonical),
+		Value:          strings.Join(chain, " -> "),
+		Recommendation: i18n.T("issue.canonical_chain.recommendation"),
+	}, true
+}
+
+// paginationIssues flags a page's rel=next and rel=prev links that lead to
+// pages that don't load or don't link back, and a page in a series whose
+// canonical points away from it, which hides the pages after the first
+func (r *relSamples) paginationIssues(page *relPage) []Issue {
+	var issues []Issue
+	issue := func(message, value string) {
+		issues = append(issues, Issue{
+			Type:           IssuePagination,
+			Severity:       models.SeverityWarning,
+			URL:            page.url,
+			Message:        message,
+			Value:          value,
+			Recommendation: i18n.T("issue.pagination_error.recommendation"),
+		})
+	}
+
+	for _, link := range []struct {
+		rel, target string
+		back        func(*relPage) string
+	}{
+		{"next", page.next, func(p *relPage) string { return p.prev }},
+		{"prev", page.prev, func(p *relPage) string { return p.next }},
+	} {
+		if link.target == "" {
+			continue
+		}
+		target := r.pages[pageKey(link.target)]
+		switch {
+		case target == nil, target.status == 0:
+			// Not crawled, or failed and reported as an error of its own
+		case target.status != 200:
+			issue(i18n.T("issue.pagination_error.broken_message", link.rel, target.status), link.target)
+		case pageKey(link.back(target)) != pageKey(page.url):
+			issue(i18n.T("issue.pagination_error.message", link.rel), link.target)
+		}
+	}
+
+	if page.prev != "" && page.canonical != "" {
+		issue(i18n.T("issue.pagination_error.canonical_message", page.canonical), page.canonical)
+	}
+	return issues
+}
@@ -0,0 +1,182 @@
+package analyzer
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"github.com/temoto/robotstxt"
+)
+
+// stagingLabel matches a hostname label used for non-production
+// environments, such as "staging", "dev2", "qa-eu", or "shop-staging"
+var stagingLabel = regexp.MustCompile(`^(staging|stage|stg|dev|develop|development|test|testing|qa|uat|preview|sandbox)(\d+|-[a-z0-9-]+)?$|-(staging|dev)$`)
+
+// stagingSuffixes are TLDs reserved for local and test networks
+var stagingSuffixes = []string{".local", ".test", ".localhost"}
+
+// PrelaunchIssues runs the pre-launch checks for leftovers from a staging
+// environment: a site-wide noindex, a robots.txt that blocks everything,
+// staging hostnames in canonicals, og:url, and the sitemap, and placeholder
+// text. robotsTxt is nil when the site has no robots.txt. Hosts that match the
+// start URL's own host are not reported, so a crawl of staging itself only
+// flags links to other environments.
+func PrelaunchIssues(startURL string, results []*models.PageResult, robotsTxt []byte, sitemapURLs []string) []Issue {
+	var issues []Issue
+	siteHost := ""
+	if u, err := url.Parse(startURL); err == nil {
+		siteHost = strings.ToLower(u.Hostname())
+	}
+	isStaging := func(rawURL string) (string, bool) {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", false
+		}
+		host := strings.ToLower(u.Hostname())
+		return host, host != "" && host != siteHost && isStagingHost(host)
+	}
+
+	pages, noindexPages := 0, 0
+	for _, result := range results {
+		if result.Error != "" || result.StatusCode != 200 {
+			continue
+		}
+		pages++
+		if result.Noindex() {
+			noindexPages++
+		}
+
+		for _, field := range []struct {
+			name, value string
+		}{
+			{"canonical", result.Canonical},
+			{"og:url", result.OGURL},
+		} {
+			if host, ok := isStaging(field.value); ok {
+				issues = append(issues, Issue{
+					Type:           IssueStagingURL,
+					Severity:       models.SeverityError,
+					URL:            result.URL,
+					Message:        i18n.T("issue.staging_url.message", field.name, host),
+					Value:          field.value,
+					Recommendation: i18n.T("issue.staging_url.recommendation"),
+				})
+			}
+		}
+
+		if result.Placeholder != "" {
+			issues = append(issues, Issue{
+				Type:           IssuePlaceholderText,
+				Severity:       models.SeverityError,
+				URL:            result.URL,
+				Message:        i18n.T("issue.placeholder_text.message", result.Placeholder),
+				Value:          result.Placeholder,
+				Recommendation: i18n.T("issue.placeholder_text.recommendation"),
+			})
+		}
+	}
+
+	if pages > 0 && noindexPages == pages {
+		issues = append(issues, Issue{
+			Type:           IssueSiteNoindex,
+			Severity:       models.SeverityError,
+			URL:            startURL,
+			Message:        i18n.T("issue.site_noindex.message", pages),
+			Value:          fmt.Sprintf("%d/%d", noindexPages, pages),
+			Recommendation: i18n.T("issue.site_noindex.recommendation"),
+		})
+	}
+
+	if robotsTxt != nil {
+		// An unparseable robots.txt is treated like a missing one, as crawlers do
+		if robots, err := robotstxt.FromBytes(robotsTxt); err == nil {
+			if group := robots.FindGroup("Googlebot"); group != nil && !group.Test("/") {
+				robotsURL := "/robots.txt"
+				if u, err := url.Parse(startURL); err == nil {
+					robotsURL = fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+				}
+				issues = append(issues, Issue{
+					Type:           IssueRobotsDisallow,
+					Severity:       models.SeverityError,
+					URL:            robotsURL,
+					Message:        i18n.T("issue.robots_disallow_all.message"),
+					Value:          "Disallow: /",
+					Recommendation: i18n.T("issue.robots_disallow_all.recommendation"),
+				})
+			}
+		}
+	}
+
+	// One issue per staging host in the sitemap, since a sitemap generated on
+	// staging usually points every entry there
+	sitemapHosts := make(map[string]int)
+	for _, u := range sitemapURLs {
+		if host, ok := isStaging(u); ok {
+			sitemapHosts[host]++
+		}
+	}
+	hosts := make([]string, 0, len(sitemapHosts))
+	for host := range sitemapHosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		issues = append(issues, Issue{
+			Type:           IssueStagingURL,
+			Severity:       models.SeverityError,
+			URL:            startURL,
+			Message:        i18n.T("issue.staging_url.sitemap_message", sitemapHosts[host], host),
+			Value:          host,
+			Recommendation: i18n.T("issue.staging_url.recommendation"),
+		})
+	}
+
+	SortIssues(issues)
+	return withDocKeys(issues)
+}
+
+// isStagingHost reports whether host looks like a non-production
+// environment: a staging-style subdomain label, localhost, an IP address, or
+// a reserved local TLD
+func isStagingHost(host string) bool {
+	if host == "localhost" || net.ParseIP(host) != nil {
+		return true
+	}
+	for _, suffix := range stagingSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		// The registrable domain itself (e.g. "test.com") isn't a staging label
+		return false
+	}
+	for _, label := range labels[:len(labels)-2] {
+		if stagingLabel.MatchString(label) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddIssues adds issues found outside the per-page checks to the summary
+func (s *Summary) AddIssues(issues []Issue) {
+	if len(issues) == 0 {
+		return
+	}
+	if s.IssuesByType == nil {
+		s.IssuesByType = make(map[IssueType]int)
+	}
+	s.Issues = append(s.Issues, issues...)
+	for _, issue := range issues {
+		s.IssuesByType[issue.Type]++
+	}
+	s.TotalIssues = len(s.Issues)
+	SortIssues(s.Issues)
+}
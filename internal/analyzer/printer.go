@@ -2,56 +2,112 @@ package analyzer
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
 )
 
+// maxPrintedOrigins is how many third-party origins the summary lists
+const maxPrintedOrigins = 10
+
 // PrintSummary prints a formatted summary to stdout
 func PrintSummary(summary *Summary) {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	FprintSummary(os.Stdout, summary)
+}
+
+// FprintSummary prints a formatted summary to out
+func FprintSummary(out io.Writer, summary *Summary) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
 	defer w.Flush()
 
-	fmt.Fprintf(os.Stdout, "\n")
-	fmt.Fprintf(os.Stdout, "═══════════════════════════════════════════════════════════\n")
-	fmt.Fprintf(os.Stdout, "                    SEO Analysis Summary                    \n")
-	fmt.Fprintf(os.Stdout, "═══════════════════════════════════════════════════════════\n")
-	fmt.Fprintf(os.Stdout, "\n")
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "═══════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(out, "%s\n", centered(i18n.T("summary.title"), 60))
+	fmt.Fprintf(out, "═══════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(out, "\n")
 
 	// Overall stats
-	fmt.Fprintf(w, "Total Pages Crawled:\t%d\n", summary.TotalPages)
-	fmt.Fprintf(w, "Total Issues Found:\t%d\n", summary.TotalIssues)
-	fmt.Fprintf(w, "Average Response Time:\t%d ms\n", summary.AverageResponseTime)
-	fmt.Fprintf(w, "Pages with Errors:\t%d\n", summary.PagesWithErrors)
-	fmt.Fprintf(w, "Pages with Redirects:\t%d\n", summary.PagesWithRedirects)
-	fmt.Fprintf(w, "Total Internal Links:\t%d\n", summary.TotalInternalLinks)
-	fmt.Fprintf(w, "Total External Links:\t%d\n", summary.TotalExternalLinks)
+	fmt.Fprintf(w, "%s:\t%d\n", i18n.T("summary.total_pages"), summary.TotalPages)
+	fmt.Fprintf(w, "%s:\t%d\n", i18n.T("summary.total_issues"), summary.TotalIssues)
+	if rt := summary.ResponseTimes; rt.Pages > 0 {
+		fmt.Fprintf(w, "%s:\t%d / %d / %d ms\n", i18n.T("summary.response_times"), rt.P50, rt.P90, rt.P99)
+		if rt.TTFBP99 > 0 {
+			fmt.Fprintf(w, "%s:\t%d / %d / %d ms\n", i18n.T("summary.ttfb"), rt.TTFBP50, rt.TTFBP90, rt.TTFBP99)
+		}
+	} else {
+		// Summaries saved before percentiles were recorded
+		fmt.Fprintf(w, "%s:\t%d ms\n", i18n.T("summary.avg_response_time"), summary.AverageResponseTime)
+	}
+	fmt.Fprintf(w, "%s:\t%d\n", i18n.T("summary.pages_with_errors"), summary.PagesWithErrors)
+	for _, row := range summary.ErrorBreakdown() {
+		fmt.Fprintf(w, "  %s:\t%d\n", i18n.T("summary.error."+row.Code), row.Count)
+	}
+	fmt.Fprintf(w, "%s:\t%d\n", i18n.T("summary.pages_with_redirects"), summary.PagesWithRedirects)
+	fmt.Fprintf(w, "%s:\t%d\n", i18n.T("summary.internal_links"), summary.TotalInternalLinks)
+	fmt.Fprintf(w, "%s:\t%d\n", i18n.T("summary.external_links"), summary.TotalExternalLinks)
 	fmt.Fprintf(w, "\n")
 
+	// Discovered URLs left out of the crawl, so missing pages aren't a mystery
+	if skipped := summary.Skipped; skipped != nil && (skipped.Total() > 0 || skipped.DuplicateLinks > 0) {
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.skipped", skipped.Total()))
+		for _, row := range []struct {
+			key   string
+			count int
+		}{
+			{"summary.skipped_robots", skipped.Robots},
+			{"summary.skipped_depth", skipped.Depth},
+			{"summary.skipped_domain", skipped.Domain},
+			{"summary.skipped_filter", skipped.Filter},
+			{"summary.skipped_scope", skipped.Scope},
+			{"summary.skipped_nofollow", skipped.Nofollow},
+			{"summary.duplicate_links", skipped.DuplicateLinks},
+		} {
+			if row.count > 0 {
+				fmt.Fprintf(w, "  %s:\t%d\n", i18n.T(row.key), row.count)
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
 	// Issues by severity
 	severityCounts := summary.GetIssueCountBySeverity()
 	if len(severityCounts) > 0 {
-		fmt.Fprintf(os.Stdout, "Issues by Severity:\n")
-		fmt.Fprintf(w, "  Errors:\t%d\n", severityCounts["error"])
-		fmt.Fprintf(w, "  Warnings:\t%d\n", severityCounts["warning"])
-		fmt.Fprintf(w, "  Info:\t%d\n", severityCounts["info"])
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.by_severity"))
+		fmt.Fprintf(w, "  %s:\t%d\n", i18n.T("summary.errors"), severityCounts["error"])
+		fmt.Fprintf(w, "  %s:\t%d\n", i18n.T("summary.warnings"), severityCounts["warning"])
+		fmt.Fprintf(w, "  %s:\t%d\n", i18n.T("summary.info"), severityCounts["info"])
 		fmt.Fprintf(w, "\n")
 	}
 
 	// Top issues by type
 	if len(summary.IssuesByType) > 0 {
-		fmt.Fprintf(os.Stdout, "Issues by Type:\n")
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.by_type"))
 		topIssues := summary.GetTopIssues(10)
 		for _, issueType := range topIssues {
 			count := summary.IssuesByType[issueType]
 			icon := getIssueIcon(issueType)
-			fmt.Fprintf(w, "  %s %s:\t%d\n", icon, formatIssueType(issueType), count)
+			fmt.Fprintf(w, "  %s %s:\t%d\n", icon, IssueTypeLabel(issueType), count)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Per-host percentiles, when the crawl spanned hosts
+	if len(summary.HostResponseTimes) > 1 {
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.host_response_times"))
+		for _, host := range summary.HostResponseTimes {
+			fmt.Fprintf(w, "  %s:\t%d / %d / %d ms\t%s\n", host.Host, host.P50, host.P90, host.P99, i18n.T("summary.host_pages", host.Pages))
 		}
 		fmt.Fprintf(w, "\n")
 	}
 
 	// Slowest pages
 	if len(summary.SlowestPages) > 0 {
-		fmt.Fprintf(os.Stdout, "Slowest Pages (>2s):\n")
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.slowest_pages"))
 		for i, page := range summary.SlowestPages {
 			if i >= 5 {
 				break
@@ -61,9 +117,95 @@ func PrintSummary(summary *Summary) {
 		fmt.Fprintf(w, "\n")
 	}
 
+	// Click depth distribution
+	if len(summary.PagesByDepth) > 1 {
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.pages_by_depth"))
+		depths := make([]int, 0, len(summary.PagesByDepth))
+		for depth := range summary.PagesByDepth {
+			depths = append(depths, depth)
+		}
+		sort.Ints(depths)
+		for _, depth := range depths {
+			fmt.Fprintf(w, "  %s:\t%d\n", i18n.T("summary.depth_level", depth), summary.PagesByDepth[depth])
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Content freshness by last modified date
+	if freshness := summary.Freshness; freshness != nil {
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.freshness"))
+		for _, bucket := range freshness.Buckets {
+			fmt.Fprintf(w, "  %s:\t%d\n", i18n.T("summary.freshness_"+bucket.Bucket), bucket.Pages)
+		}
+		if freshness.Unknown > 0 {
+			fmt.Fprintf(w, "  %s:\t%d\n", i18n.T("summary.freshness_unknown"), freshness.Unknown)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Sites the pages load scripts and stylesheets from
+	if len(summary.ThirdPartyOrigins) > 0 {
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.third_party_origins"))
+		for i, origin := range summary.ThirdPartyOrigins {
+			if i == maxPrintedOrigins {
+				fmt.Fprintf(w, "  %s\n", i18n.T("summary.and_more", len(summary.ThirdPartyOrigins)-maxPrintedOrigins))
+				break
+			}
+			details := i18n.T("summary.third_party_origin", origin.Pages, origin.Scripts, origin.Stylesheets)
+			if origin.Insecure > 0 {
+				details += ", ⚠️  " + i18n.T("summary.third_party_insecure", origin.Insecure)
+			} else if origin.WithoutSRI > 0 {
+				details += ", " + i18n.T("summary.third_party_without_sri", origin.WithoutSRI)
+			}
+			fmt.Fprintf(w, "  %s:\t%s\n", origin.Origin, details)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	// Estimated savings from modern image formats and resizing
+	if savings := summary.ImageSavings; savings != nil && savings.Bytes > 0 {
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.image_savings"))
+		fmt.Fprintf(w, "  %s\n", i18n.T("summary.image_savings_detail",
+			savings.Saved()/1024, savings.Bytes/1024, savings.Saved()*100/savings.Bytes,
+			(savings.Bytes-savings.WebPBytes)/1024, savings.Pages, savings.Images))
+		fmt.Fprintf(w, "\n")
+	}
+
+	// AI crawler access and llms.txt
+	if ai := summary.AIVisibility; ai != nil {
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.ai_visibility"))
+		if !ai.RobotsTxtFound {
+			fmt.Fprintf(w, "  %s\n", i18n.T("summary.ai_no_robots"))
+		} else {
+			for _, crawler := range ai.Crawlers {
+				status := "✓ " + i18n.T("summary.ai_allowed")
+				if !crawler.Allowed {
+					status = "✗ " + i18n.T("summary.ai_blocked")
+				}
+				group := ""
+				if crawler.Group != "" {
+					group = fmt.Sprintf(" (User-agent: %s)", crawler.Group)
+				}
+				fmt.Fprintf(w, "  %s:\t%s%s\n", crawler.UserAgent, status, group)
+			}
+		}
+		switch {
+		case !ai.LLMsTxt.Found:
+			fmt.Fprintf(w, "  %s\n", i18n.T("summary.llms_txt_missing", ai.LLMsTxt.URL))
+		case ai.LLMsTxt.Valid():
+			fmt.Fprintf(w, "  ✓ %s\n", i18n.T("summary.llms_txt_valid", ai.LLMsTxt.Links, ai.LLMsTxt.Sections))
+		default:
+			fmt.Fprintf(w, "  ⚠️  %s\n", i18n.T("summary.llms_txt_problems", len(ai.LLMsTxt.Problems)))
+			for _, problem := range ai.LLMsTxt.Problems {
+				fmt.Fprintf(w, "    • %s\n", problem)
+			}
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
 	// Top issues detail
 	if len(summary.Issues) > 0 {
-		fmt.Fprintf(os.Stdout, "Top Issues:\n")
+		fmt.Fprintf(out, "%s:\n", i18n.T("summary.top_issues"))
 		// Group by type and show first few examples
 		issueGroups := make(map[IssueType][]Issue)
 		for _, issue := range summary.Issues {
@@ -77,74 +219,56 @@ func PrintSummary(summary *Summary) {
 				continue
 			}
 			icon := getIssueIcon(issueType)
-			fmt.Fprintf(os.Stdout, "\n  %s %s:\n", icon, formatIssueType(issueType))
-			
+			fmt.Fprintf(out, "\n  %s %s:\n", icon, IssueTypeLabel(issueType))
+
 			// Show first 3 examples
 			for i := 0; i < 3 && i < len(issues); i++ {
 				issue := issues[i]
 				fmt.Fprintf(w, "    • %s\n", issue.URL)
 				fmt.Fprintf(w, "      %s\n", issue.Message)
 				if issue.Recommendation != "" {
-					fmt.Fprintf(w, "      Recommendation: %s\n", issue.Recommendation)
+					fmt.Fprintf(w, "      %s\n", i18n.T("summary.recommendation", issue.Recommendation))
 				}
 			}
 			if len(issues) > 3 {
-				fmt.Fprintf(w, "    ... and %d more\n", len(issues)-3)
+				fmt.Fprintf(w, "    %s\n", i18n.T("summary.and_more", len(issues)-3))
 			}
 		}
 	}
 
-	fmt.Fprintf(os.Stdout, "\n")
-	fmt.Fprintf(os.Stdout, "═══════════════════════════════════════════════════════════\n")
+	fmt.Fprintf(out, "\n")
+	fmt.Fprintf(out, "═══════════════════════════════════════════════════════════\n")
 }
 
 func getIssueIcon(issueType IssueType) string {
 	switch issueType {
-	case IssueMissingH1, IssueMissingTitle, IssueMissingMetaDesc, IssueBrokenLink, IssueEmptyH1:
+	case IssueMissingH1, IssueMissingTitle, IssueMissingMetaDesc, IssueBrokenLink, IssueEmptyH1,
+		IssueSiteNoindex, IssueRobotsDisallow, IssueStagingURL, IssuePlaceholderText, IssueInsecureAsset, IssueSoft404:
 		return "🔴"
-	case IssueLongTitle, IssueLongMetaDesc, IssueShortTitle, IssueShortMetaDesc, IssueMultipleH1, IssueRedirectChain, IssueLargeImage, IssueMissingImageAlt:
+	case IssueLongTitle, IssueLongMetaDesc, IssueShortTitle, IssueShortMetaDesc, IssueMultipleH1, IssueRedirectChain, IssueLargeImage, IssueMissingImageAlt, IssueJSErrors, IssueShortCacheTTL, IssueNoindexConflict, IssueEmptyAnchor, IssueInvalidSchema, IssueCanonicalChain, IssuePagination, IssueDuplicateContent, IssueBrokenAnchor, IssueUnhelpful404, IssueMobileParity, IssueMissingViewport:
 		return "⚠️"
-	case IssueNoCanonical, IssueSlowResponse:
+	case IssueNoCanonical, IssueSlowResponse, IssueDeepPage, IssueUncacheablePage, IssueMissingSRI, IssueImageSavings, IssueNofollowLink, IssueGenericAnchor, IssueExactAnchor, IssueMissingSchema, IssueNearDuplicate:
 		return "ℹ️"
 	default:
 		return "•"
 	}
 }
 
-func formatIssueType(issueType IssueType) string {
-	switch issueType {
-	case IssueMissingH1:
-		return "Missing H1"
-	case IssueMissingMetaDesc:
-		return "Missing Meta Description"
-	case IssueMissingTitle:
-		return "Missing Title"
-	case IssueLongTitle:
-		return "Long Title"
-	case IssueLongMetaDesc:
-		return "Long Meta Description"
-	case IssueShortTitle:
-		return "Short Title"
-	case IssueShortMetaDesc:
-		return "Short Meta Description"
-	case IssueLargeImage:
-		return "Large Images (>100KB)"
-	case IssueMissingImageAlt:
-		return "Missing Image Alt Text"
-	case IssueSlowResponse:
-		return "Slow Response"
-	case IssueRedirectChain:
-		return "Redirect Chain"
-	case IssueNoCanonical:
-		return "No Canonical"
-	case IssueBrokenLink:
-		return "Broken Links"
-	case IssueMultipleH1:
-		return "Multiple H1 Tags"
-	case IssueEmptyH1:
-		return "Empty H1 Tag"
-	default:
-		return string(issueType)
+// IssueTypeLabel returns the localized display label for an issue type
+func IssueTypeLabel(issueType IssueType) string {
+	key := "issue_type." + string(issueType)
+	if label := i18n.T(key); label != key {
+		return label
 	}
+	return string(issueType)
 }
 
+// centered pads s with spaces to center it within width columns
+func centered(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n >= width {
+		return s
+	}
+	left := (width - n) / 2
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", width-n-left)
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// noindexConflictIssue flags a page that asks not to be indexed while
+// other signals on the site ask for it to be: it is listed in the sitemap,
+// or its canonical points to itself. A noindex left over from staging or a
+// template often shows up this way.
+func noindexConflictIssue(result *models.PageResult) (Issue, bool) {
+	if result.StatusCode != 200 || result.Error != "" || !result.Noindex() {
+		return Issue{}, false
+	}
+
+	var reasons []string
+	if result.InSitemap {
+		reasons = append(reasons, i18n.T("issue.noindex_conflict.in_sitemap"))
+	}
+	if result.Canonical != "" && !result.Canonicalized() {
+		reasons = append(reasons, i18n.T("issue.noindex_conflict.self_canonical"))
+	}
+	if len(reasons) == 0 {
+		return Issue{}, false
+	}
+
+	return Issue{
+		Type:           IssueNoindexConflict,
+		Severity:       models.SeverityWarning,
+		URL:            result.URL,
+		Message:        i18n.T("issue.noindex_conflict.message", strings.Join(reasons, "; ")),
+		Value:          noindexSource(result),
+		Recommendation: i18n.T("issue.noindex_conflict.recommendation"),
+	}, true
+}
+
+// noindexSource returns the robots meta tag or X-Robots-Tag header that
+// makes a page noindex
+func noindexSource(result *models.PageResult) string {
+	if models.ParseRobotsDirectives(result.MetaRobots, "").Noindex {
+		return "meta robots: " + result.MetaRobots
+	}
+	return "X-Robots-Tag: " + result.Headers["X-Robots-Tag"]
+}
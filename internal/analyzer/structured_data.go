@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// invalidSchemaExamples is how many invalid items an issue's value lists
+const invalidSchemaExamples = 3
+
+// schemaSubtypes lists, for each type a page's markup can call for, the
+// schema.org types that provide it
+var schemaSubtypes = map[string][]string{
+	"Article":        {"Article", "NewsArticle", "BlogPosting", "TechArticle", "ScholarlyArticle", "Report", "LiveBlogPosting"},
+	"Product":        {"Product", "ProductGroup", "IndividualProduct", "ProductModel"},
+	"BreadcrumbList": {"BreadcrumbList"},
+}
+
+// structuredDataIssues flags a page's structured data that search engines
+// can't use, and the types its markup calls for that it doesn't declare: an
+// article or product by its Open Graph tags, or a breadcrumb trail
+func structuredDataIssues(result *models.PageResult) []Issue {
+	if result.Error != "" || result.StatusCode != 200 {
+		return nil
+	}
+
+	// An invalid item still declares its type; it is only reported as invalid
+	var invalid []string
+	declared := make(map[string]bool)
+	for _, item := range result.StructuredData {
+		if item.Type != "" {
+			declared[item.Type] = true
+		}
+		switch {
+		case item.Error == "":
+		case item.Type == "":
+			invalid = append(invalid, item.Error)
+		default:
+			invalid = append(invalid, fmt.Sprintf("%s: %s", item.Type, item.Error))
+		}
+	}
+
+	var missing []string
+	for _, expected := range result.ExpectedSchema {
+		if !slices.ContainsFunc(schemaSubtypes[expected], func(t string) bool { return declared[t] }) {
+			missing = append(missing, expected)
+		}
+	}
+
+	var issues []Issue
+	if len(invalid) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueInvalidSchema,
+			Severity:       models.SeverityWarning,
+			URL:            result.URL,
+			Message:        i18n.T("issue.invalid_structured_data.message", len(invalid)),
+			Value:          joinExamples(invalid, invalidSchemaExamples),
+			Recommendation: i18n.T("issue.invalid_structured_data.recommendation"),
+		})
+	}
+	if len(missing) > 0 {
+		issues = append(issues, Issue{
+			Type:           IssueMissingSchema,
+			Severity:       models.SeverityInfo,
+			URL:            result.URL,
+			Message:        i18n.T("issue.missing_structured_data.message", strings.Join(missing, ", ")),
+			Value:          strings.Join(missing, ", "),
+			Recommendation: i18n.T("issue.missing_structured_data.recommendation"),
+		})
+	}
+	return issues
+}
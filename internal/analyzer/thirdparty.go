@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// ThirdPartyOrigin is a site the crawled pages load scripts or stylesheets
+// from
+type ThirdPartyOrigin struct {
+	Origin      string `json:"origin"`      // scheme://host
+	Pages       int    `json:"pages"`       // Pages loading any asset from the origin
+	Scripts     int    `json:"scripts"`     // Distinct script URLs
+	Stylesheets int    `json:"stylesheets"` // Distinct stylesheet URLs
+	WithoutSRI  int    `json:"without_sri"` // Assets some page loads without an integrity hash
+	Insecure    int    `json:"insecure"`    // Assets loaded over plain HTTP
+}
+
+// thirdPartyIssues flags the page's third-party assets that are loaded over
+// plain HTTP, where anyone on the network can change them, or without a
+// Subresource Integrity hash that would stop a compromised host from
+// serving different code
+func thirdPartyIssues(result *models.PageResult) []Issue {
+	var issues []Issue
+	for _, asset := range result.ThirdParty {
+		switch {
+		case strings.HasPrefix(asset.URL, "http://"):
+			issues = append(issues, Issue{
+				Type:           IssueInsecureAsset,
+				Severity:       models.SeverityError,
+				URL:            result.URL,
+				Message:        i18n.T("issue.insecure_third_party_asset.message", asset.URL),
+				Value:          asset.URL,
+				Recommendation: i18n.T("issue.insecure_third_party_asset.recommendation"),
+			})
+		case !hasIntegrityHash(asset.Integrity):
+			issues = append(issues, Issue{
+				Type:           IssueMissingSRI,
+				Severity:       models.SeverityInfo,
+				URL:            result.URL,
+				Message:        i18n.T("issue.missing_sri.message", asset.URL),
+				Value:          asset.URL,
+				Recommendation: i18n.T("issue.missing_sri.recommendation"),
+			})
+		}
+	}
+	return issues
+}
+
+// hasIntegrityHash reports whether an integrity attribute has a hash that
+// browsers check. Tokens with other algorithms are ignored by browsers.
+func hasIntegrityHash(integrity string) bool {
+	for _, token := range strings.Fields(integrity) {
+		for _, algorithm := range []string{"sha256-", "sha384-", "sha512-"} {
+			if strings.HasPrefix(strings.ToLower(token), algorithm) && len(token) > len(algorithm) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// thirdPartySamples collects the third-party assets of every page by origin
+type thirdPartySamples struct {
+	origins map[string]*originSamples
+}
+
+// originSamples is what the crawled pages load from one origin
+type originSamples struct {
+	pages      int
+	assets     map[string]string // URL -> asset type
+	withoutSRI map[string]bool
+}
+
+// add records a page's third-party assets
+func (t *thirdPartySamples) add(result *models.PageResult) {
+	if len(result.ThirdParty) == 0 {
+		return
+	}
+	if t.origins == nil {
+		t.origins = make(map[string]*originSamples)
+	}
+
+	seen := make(map[string]bool)
+	for _, asset := range result.ThirdParty {
+		u, err := url.Parse(asset.URL)
+		if err != nil {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		samples, ok := t.origins[origin]
+		if !ok {
+			samples = &originSamples{assets: make(map[string]string), withoutSRI: make(map[string]bool)}
+			t.origins[origin] = samples
+		}
+		if !seen[origin] {
+			seen[origin] = true
+			samples.pages++
+		}
+		samples.assets[asset.URL] = asset.Type
+		if !hasIntegrityHash(asset.Integrity) {
+			samples.withoutSRI[asset.URL] = true
+		}
+	}
+}
+
+// report lists the origins by the number of pages depending on them, or
+// returns nil when no page loads third-party assets
+func (t *thirdPartySamples) report() []ThirdPartyOrigin {
+	if len(t.origins) == 0 {
+		return nil
+	}
+	origins := make([]ThirdPartyOrigin, 0, len(t.origins))
+	for origin, samples := range t.origins {
+		entry := ThirdPartyOrigin{
+			Origin:     origin,
+			Pages:      samples.pages,
+			WithoutSRI: len(samples.withoutSRI),
+		}
+		for _, assetType := range samples.assets {
+			if assetType == models.AssetScript {
+				entry.Scripts++
+			} else {
+				entry.Stylesheets++
+			}
+		}
+		if strings.HasPrefix(origin, "http://") {
+			entry.Insecure = len(samples.assets)
+		}
+		origins = append(origins, entry)
+	}
+	sort.Slice(origins, func(i, j int) bool {
+		if origins[i].Pages != origins[j].Pages {
+			return origins[i].Pages > origins[j].Pages
+		}
+		return origins[i].Origin < origins[j].Origin
+	})
+	return origins
+}
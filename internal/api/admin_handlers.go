@@ -0,0 +1,442 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/store"
+	"github.com/stripe/stripe-go/v78"
+	"github.com/stripe/stripe-go/v78/event"
+	"go.uber.org/zap"
+)
+
+// maxAdminPage caps how many users or events one admin request lists
+const maxAdminPage = 100
+
+// handleAdmin routes the admin API. Only users whose app_metadata role is
+// "admin" may use it; self-hosted, whoever holds the API token may.
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		s.respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	if !isAdminFromContext(r.Context()) {
+		s.respondError(w, http.StatusForbidden, "Admin access required")
+		return
+	}
+
+	// After StripPrefix("/api/v1"), the path is like "/admin/users/:id"
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/"), "/")
+	segments := strings.Split(path, "/")
+
+	switch {
+	case path == "users":
+		s.handleAdminListUsers(w, r)
+	case len(segments) == 2 && segments[0] == "users":
+		s.handleAdminGetUser(w, r, segments[1])
+	case len(segments) == 3 && segments[0] == "users" && segments[2] == "sync-subscription":
+		s.handleAdminSyncSubscription(w, r, segments[1])
+	case path == "webhook-events":
+		s.handleAdminListWebhookEvents(w, r)
+	case len(segments) == 3 && segments[0] == "webhook-events" && segments[2] == "replay":
+		s.handleAdminReplayWebhookEvent(w, r, segments[1])
+	case len(segments) == 3 && segments[0] == "projects" && segments[2] == "owner":
+		s.handleAdminReassignProject(w, r, segments[1])
+	case path == "crawls":
+		s.handleAdminListCrawls(w, r)
+	case len(segments) == 3 && segments[0] == "crawls" && segments[2] == "complete":
+		s.handleAdminCompleteCrawl(w, r, segments[1], userID)
+	default:
+		s.respondError(w, http.StatusNotFound, fmt.Sprintf("Admin resource not found: %s", path))
+	}
+}
+
+// requireSupabase responds with 501 and returns false when the server has
+// no Supabase, which holds users and their subscriptions
+func (s *Server) requireSupabase(w http.ResponseWriter) bool {
+	if !s.hasSupabase() {
+		s.respondError(w, http.StatusNotImplemented, "User and billing administration needs Supabase")
+		return false
+	}
+	return true
+}
+
+// requireStripe responds with 500 and returns false when Stripe isn't
+// configured
+func (s *Server) requireStripe(w http.ResponseWriter) bool {
+	if GetStripeConfig().SecretKey == "" {
+		s.respondError(w, http.StatusInternalServerError, "Stripe not configured")
+		return false
+	}
+	return true
+}
+
+// queryInt returns a positive integer query parameter, def when it is unset
+// or invalid, and at most limit
+func queryInt(r *http.Request, name string, def, limit int) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || value < 0 {
+		return def
+	}
+	return min(value, limit)
+}
+
+// handleAdminListUsers handles GET /api/v1/admin/users - user profiles,
+// optionally of one tier
+func (s *Server) handleAdminListUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !s.requireSupabase(w) {
+		return
+	}
+
+	limit := max(queryInt(r, "limit", 50, maxAdminPage), 1)
+	offset := queryInt(r, "offset", 0, math.MaxInt32)
+
+	query := s.serviceRole.From("profiles").Select(profileColumns, "", false)
+	if tier := r.URL.Query().Get("tier"); tier != "" {
+		query = query.Eq("subscription_tier", tier)
+	}
+	data, _, err := query.Order("id", nil).Range(offset, offset+limit-1, "").Execute()
+	if err != nil {
+		s.logger.Error("Failed to list profiles", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to list users")
+		return
+	}
+
+	users := make([]map[string]interface{}, 0)
+	if err := json.Unmarshal(data, &users); err != nil {
+		s.logger.Error("Failed to parse profiles", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to list users")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, AdminUsersResponse{Users: users, Count: len(users)})
+}
+
+// adminUserSummary returns a user's profile, latest subscription, and
+// entitlements, or nil when the user has no profile
+func (s *Server) adminUserSummary(userID string) (*BillingSummaryResponse, error) {
+	profile, err := s.fetchProfile(userID)
+	if err != nil || profile == nil {
+		return nil, err
+	}
+	subscription, err := s.fetchLatestSubscription(userID)
+	if err != nil {
+		return nil, err
+	}
+	tier, _ := profile["subscription_tier"].(string)
+	return &BillingSummaryResponse{
+		Profile:      profile,
+		Subscription: subscription,
+		Entitlements: s.config.Entitlements.For(tier),
+	}, nil
+}
+
+// handleAdminGetUser handles GET /api/v1/admin/users/:id - a user's
+// subscription state
+func (s *Server) handleAdminGetUser(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !s.requireSupabase(w) {
+		return
+	}
+
+	summary, err := s.adminUserSummary(userID)
+	if err != nil {
+		s.logger.Error("Failed to load user", zap.String("user_id", userID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to load user")
+		return
+	}
+	if summary == nil {
+		s.respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, summary)
+}
+
+// handleAdminSyncSubscription handles POST
+// /api/v1/admin/users/:id/sync-subscription - copies the user's latest
+// subscription from Stripe, for when its webhooks were missed
+func (s *Server) handleAdminSyncSubscription(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !s.requireSupabase(w) || !s.requireStripe(w) {
+		return
+	}
+
+	profile, err := s.fetchProfile(userID)
+	if err != nil {
+		s.logger.Error("Failed to load user", zap.String("user_id", userID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to load user")
+		return
+	}
+	if profile == nil {
+		s.respondError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	customerID, _ := profile["stripe_customer_id"].(string)
+	if customerID == "" {
+		s.respondError(w, http.StatusConflict, "User has no Stripe customer")
+		return
+	}
+
+	if err := s.syncSubscriptionFromStripe(customerID, userID); err != nil {
+		s.logger.Error("Failed to sync subscription", zap.String("user_id", userID), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, fmt.Sprintf("Failed to sync subscription: %v", err))
+		return
+	}
+
+	summary, err := s.adminUserSummary(userID)
+	if err != nil || summary == nil {
+		s.logger.Error("Failed to load user", zap.String("user_id", userID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to load user")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, summary)
+}
+
+// webhookEvent returns the fields of a Stripe event the admin API shows
+func webhookEvent(e *stripe.Event) WebhookEvent {
+	return WebhookEvent{
+		ID:              e.ID,
+		Type:            string(e.Type),
+		Created:         time.Unix(e.Created, 0).UTC(),
+		PendingWebhooks: e.PendingWebhooks,
+	}
+}
+
+// handleAdminListWebhookEvents handles GET /api/v1/admin/webhook-events -
+// recent Stripe events that failed to reach a webhook endpoint, newest
+// first. Stripe keeps events for 30 days.
+func (s *Server) handleAdminListWebhookEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !s.requireSupabase(w) || !s.requireStripe(w) {
+		return
+	}
+
+	limit := max(queryInt(r, "limit", 50, maxAdminPage), 1)
+	params := &stripe.EventListParams{DeliverySuccess: stripe.Bool(false)}
+	if eventType := r.URL.Query().Get("type"); eventType != "" {
+		params.Type = stripe.String(eventType)
+	}
+	params.Limit = stripe.Int64(int64(limit))
+
+	events := make([]WebhookEvent, 0, limit)
+	iter := event.List(params)
+	for len(events) < limit && iter.Next() {
+		events = append(events, webhookEvent(iter.Event()))
+	}
+	if err := iter.Err(); err != nil {
+		s.logger.Error("Failed to list Stripe events", zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to list webhook events")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, WebhookEventsResponse{Events: events, Count: len(events)})
+}
+
+// handleAdminReplayWebhookEvent handles POST
+// /api/v1/admin/webhook-events/:id/replay - fetches a Stripe event and
+// processes it as if its webhook had arrived
+func (s *Server) handleAdminReplayWebhookEvent(w http.ResponseWriter, r *http.Request, eventID string) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !s.requireSupabase(w) || !s.requireStripe(w) {
+		return
+	}
+
+	e, err := event.Get(eventID, nil)
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) && stripeErr.HTTPStatusCode == http.StatusNotFound {
+		s.respondError(w, http.StatusNotFound, "Webhook event not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to get Stripe event", zap.String("event_id", eventID), zap.Error(err))
+		s.respondError(w, http.StatusBadGateway, "Failed to get webhook event")
+		return
+	}
+
+	if err := s.processStripeEvent(e); err != nil {
+		s.logger.Error("Failed to replay Stripe event", zap.String("event_id", eventID), zap.Error(err))
+		s.respondError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Failed to replay webhook event: %v", err))
+		return
+	}
+	s.logger.Info("Replayed Stripe event", zap.String("event_id", eventID), zap.String("type", string(e.Type)))
+
+	s.respondJSON(w, http.StatusOK, webhookEvent(e))
+}
+
+// handleAdminReassignProject handles POST /api/v1/admin/projects/:id/owner -
+// makes another user a project's owner. The previous owner stays a member.
+func (s *Server) handleAdminReassignProject(w http.ResponseWriter, r *http.Request, projectID string) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req ReassignProjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if req.OwnerID == "" {
+		s.respondError(w, http.StatusBadRequest, "owner_id is required")
+		return
+	}
+
+	if _, err := s.store.GetProject(r.Context(), projectID); errors.Is(err, store.ErrNotFound) {
+		s.respondError(w, http.StatusNotFound, "Project not found")
+		return
+	} else if err != nil {
+		s.logger.Error("Failed to get project", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get project")
+		return
+	}
+
+	if s.hasSupabase() {
+		profile, err := s.fetchProfile(req.OwnerID)
+		if err != nil {
+			s.logger.Error("Failed to load user", zap.String("user_id", req.OwnerID), zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to load user")
+			return
+		}
+		if profile == nil {
+			s.respondError(w, http.StatusBadRequest, fmt.Sprintf("No user with ID %s", req.OwnerID))
+			return
+		}
+	}
+
+	if err := s.store.UpdateProject(r.Context(), projectID, store.ProjectUpdate{OwnerID: &req.OwnerID}); err != nil {
+		s.logger.Error("Failed to reassign project", zap.String("project_id", projectID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to reassign project")
+		return
+	}
+	if err := s.store.AddProjectMember(r.Context(), projectID, req.OwnerID, "owner"); err != nil {
+		// The new owner may already be a member; ownership alone grants access
+		s.logger.Warn("Failed to add new owner as project member", zap.Error(err))
+	}
+	s.logger.Info("Reassigned project", zap.String("project_id", projectID), zap.String("owner_id", req.OwnerID))
+
+	project, err := s.store.GetProject(r.Context(), projectID)
+	if err != nil {
+		s.logger.Error("Failed to get project", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get project")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, project)
+}
+
+// handleAdminListCrawls handles GET /api/v1/admin/crawls - crawls of every
+// project, newest first; ?status=running finds stuck ones
+func (s *Server) handleAdminListCrawls(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	crawls, err := s.store.ListCrawls(r.Context(), store.CrawlFilter{
+		ProjectID: r.URL.Query().Get("project_id"),
+		Status:    r.URL.Query().Get("status"),
+	})
+	if err != nil {
+		s.logger.Error("Failed to list crawls", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to list crawls")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, ListCrawlsResponse{Crawls: crawls, Count: len(crawls)})
+}
+
+// handleAdminCompleteCrawl handles POST /api/v1/admin/crawls/:id/complete -
+// ends a crawl left pending or running, e.g. by a server restart. A crawl
+// this server is still running is cancelled first.
+func (s *Server) handleAdminCompleteCrawl(w http.ResponseWriter, r *http.Request, crawlID, adminID string) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req CompleteCrawlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	switch req.Status {
+	case "":
+		req.Status = "failed"
+	case "failed", "succeeded", "cancelled":
+	default:
+		s.respondError(w, http.StatusBadRequest, `status must be "failed", "succeeded", or "cancelled"`)
+		return
+	}
+
+	crawl, err := s.store.GetCrawl(r.Context(), crawlID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.respondError(w, http.StatusNotFound, "Crawl not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to get crawl", zap.String("crawl_id", crawlID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get crawl")
+		return
+	}
+	if crawl.Status != "pending" && crawl.Status != "running" {
+		s.respondError(w, http.StatusConflict, fmt.Sprintf("Crawl already %s", crawl.Status))
+		return
+	}
+
+	// Meta replaces the stored object, so keep what the crawl recorded
+	meta := maps.Clone(crawl.Meta)
+	if meta == nil {
+		meta = make(map[string]interface{})
+	}
+	meta["completed_by"] = adminID
+	if req.Status != "succeeded" {
+		if req.Reason == "" {
+			req.Reason = fmt.Sprintf("Marked %s by an administrator", req.Status)
+		}
+		meta["error"] = req.Reason
+	}
+	if manager := s.running.get(crawlID); manager != nil {
+		manager.Cancel(fmt.Sprintf("Marked %s by administrator %s", req.Status, adminID))
+	}
+	completedAt := time.Now().UTC()
+	update := store.CrawlUpdate{Status: &req.Status, CompletedAt: &completedAt, Meta: meta}
+	if err := s.store.UpdateCrawl(r.Context(), crawlID, update); err != nil {
+		s.logger.Error("Failed to complete crawl", zap.String("crawl_id", crawlID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to complete crawl")
+		return
+	}
+	s.logger.Info("Completed stuck crawl", zap.String("crawl_id", crawlID), zap.String("status", req.Status), zap.String("admin_id", adminID))
+
+	crawl, err = s.store.GetCrawl(r.Context(), crawlID)
+	if err != nil {
+		s.logger.Error("Failed to get crawl", zap.String("crawl_id", crawlID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get crawl")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, crawl)
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/store"
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"go.uber.org/zap"
+)
+
+// artifactURLExpiry is how long a signed artifact download URL stays valid
+const artifactURLExpiry = 15 * time.Minute
+
+// handleCrawlArtifacts handles /api/v1/crawls/:id/artifacts[/name]. The
+// caller has checked the user's access to the crawl.
+func (s *Server) handleCrawlArtifacts(w http.ResponseWriter, r *http.Request, crawlID, name string) {
+	if s.artifacts == nil {
+		s.respondError(w, http.StatusNotImplemented, "Artifact storage needs Supabase Storage")
+		return
+	}
+
+	crawl, err := s.store.GetCrawl(r.Context(), crawlID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.respondError(w, http.StatusNotFound, "Crawl not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to get crawl", zap.String("crawl_id", crawlID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get crawl")
+		return
+	}
+
+	switch {
+	case name == "" && r.Method == http.MethodGet:
+		s.handleListArtifacts(w, r, crawl)
+	case name == "" && r.Method == http.MethodPost:
+		s.handleCreateArtifactUpload(w, r, crawl)
+	case name != "" && r.Method == http.MethodGet:
+		s.handleGetArtifact(w, r, crawl, name)
+	default:
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleListArtifacts handles GET /api/v1/crawls/:id/artifacts
+func (s *Server) handleListArtifacts(w http.ResponseWriter, r *http.Request, crawl *store.Crawl) {
+	artifacts, err := s.artifacts.List(r.Context(), crawl.ProjectID, crawl.ID)
+	if err != nil {
+		s.logger.Error("Failed to list artifacts", zap.String("crawl_id", crawl.ID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to list artifacts")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, ListArtifactsResponse{
+		Artifacts: artifacts,
+		Count:     len(artifacts),
+	})
+}
+
+// handleGetArtifact handles GET /api/v1/crawls/:id/artifacts/:name - returns
+// a signed download URL
+func (s *Server) handleGetArtifact(w http.ResponseWriter, r *http.Request, crawl *store.Crawl, name string) {
+	if err := store.ValidateArtifactName(name); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	url, err := s.artifacts.SignedURL(r.Context(), crawl.ProjectID, crawl.ID, name, artifactURLExpiry)
+	if errors.Is(err, store.ErrNotFound) {
+		s.respondError(w, http.StatusNotFound, fmt.Sprintf("Artifact not found: %s", name))
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to sign artifact URL", zap.String("crawl_id", crawl.ID), zap.String("name", name), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to sign artifact URL")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, ArtifactURLResponse{
+		Name:      name,
+		URL:       url,
+		ExpiresAt: time.Now().UTC().Add(artifactURLExpiry),
+	})
+}
+
+// handleCreateArtifactUpload handles POST /api/v1/crawls/:id/artifacts. The
+// client uploads to the returned URL directly, so large files never pass
+// through the API server.
+func (s *Server) handleCreateArtifactUpload(w http.ResponseWriter, r *http.Request, crawl *store.Crawl) {
+	var req CreateArtifactUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if err := store.ValidateArtifactName(req.Name); err != nil {
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	url, err := s.artifacts.SignedUploadURL(r.Context(), crawl.ProjectID, crawl.ID, req.Name)
+	if err != nil {
+		s.logger.Error("Failed to sign artifact upload URL", zap.String("crawl_id", crawl.ID), zap.String("name", req.Name), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to sign artifact upload URL")
+		return
+	}
+	s.respondJSON(w, http.StatusCreated, ArtifactUploadResponse{
+		Name:      req.Name,
+		UploadURL: url,
+	})
+}
+
+// saveCrawlArtifacts uploads the raw exports of a server-side crawl, and its
+// HTML snapshots when htmlDir is set, under the same names a CLI crawl
+// directory uses. Failures are logged; the crawl's rows are already stored.
+func (s *Server) saveCrawlArtifacts(ctx context.Context, crawlID, projectID string, results []*models.PageResult, summary *analyzer.Summary, graph map[string][]string, htmlDir string) {
+	put := func(name, contentType string, data []byte) {
+		if err := s.artifacts.Put(ctx, projectID, crawlID, name, contentType, bytes.NewReader(data)); err != nil {
+			s.logger.Warn("Failed to store crawl artifact", zap.String("crawl_id", crawlID), zap.String("name", name), zap.Error(err))
+		}
+	}
+	putJSON := func(name string, v interface{}) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			s.logger.Warn("Failed to encode crawl artifact", zap.String("crawl_id", crawlID), zap.String("name", name), zap.Error(err))
+			return
+		}
+		put(name, "application/json", data)
+	}
+
+	putJSON(crawldir.ResultsFile("json"), results)
+	putJSON(crawldir.IssuesFile, summary.Issues)
+	putJSON(crawldir.SummaryFile, summary)
+	putJSON(crawldir.GraphFile, graph)
+
+	if htmlDir == "" {
+		return
+	}
+	entries, err := os.ReadDir(htmlDir)
+	if err != nil {
+		s.logger.Warn("Failed to read HTML snapshots", zap.String("crawl_id", crawlID), zap.Error(err))
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(htmlDir, entry.Name()))
+		if err != nil {
+			s.logger.Warn("Failed to read HTML snapshot", zap.String("file", entry.Name()), zap.Error(err))
+			continue
+		}
+		put(path.Join(crawldir.HTMLDir, entry.Name()), mime.TypeByExtension(filepath.Ext(entry.Name())), data)
+	}
+	s.logger.Info("Stored crawl artifacts", zap.String("crawl_id", crawlID), zap.Int("html_snapshots", len(entries)))
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stripe/stripe-go/v78"
+	"github.com/stripe/stripe-go/v78/price"
+	"github.com/stripe/stripe-go/v78/promotioncode"
+	"go.uber.org/zap"
+)
+
+// Lookup keys of the Stripe Prices sold as plans. Set them on the prices in
+// the Stripe Dashboard; the STRIPE_PRICE_ID_* variables still name the price
+// of a plan when no active price has its key.
+const (
+	PlanProMonthly = "pro_monthly"
+	PlanProAnnual  = "pro_annual"
+	PlanTeamSeat   = "team_seat"
+)
+
+// planKeys lists the plans in the order the dashboard shows them
+var planKeys = []string{PlanProMonthly, PlanProAnnual, PlanTeamSeat}
+
+// plansCacheTTL is how long the plans read from Stripe are reused, so
+// price changes show up without a restart
+const plansCacheTTL = 5 * time.Minute
+
+// plansCache holds the plans last read from Stripe
+var plansCache struct {
+	sync.Mutex
+	plans  []BillingPlan
+	loaded time.Time
+}
+
+// planTier returns the subscription tier a plan grants
+func planTier(key string) string {
+	if key == PlanTeamSeat {
+		return "team"
+	}
+	return "pro"
+}
+
+// fallbackPriceIDs returns the price configured in the environment for
+// each plan
+func fallbackPriceIDs(stripeConfig StripeConfig) map[string]string {
+	return map[string]string{
+		PlanProMonthly: stripeConfig.PriceIDPro,
+		PlanProAnnual:  stripeConfig.PriceIDProAnnual,
+		PlanTeamSeat:   stripeConfig.PriceIDTeamSeat,
+	}
+}
+
+// billingPlans returns the plans for sale, reading them from Stripe when the
+// cached ones are older than plansCacheTTL
+func billingPlans() ([]BillingPlan, error) {
+	plansCache.Lock()
+	defer plansCache.Unlock()
+
+	if plansCache.plans != nil && time.Since(plansCache.loaded) < plansCacheTTL {
+		return plansCache.plans, nil
+	}
+	plans, err := loadBillingPlans(GetStripeConfig())
+	if err != nil {
+		return nil, err
+	}
+	plansCache.plans, plansCache.loaded = plans, time.Now()
+	return plans, nil
+}
+
+// loadBillingPlans reads the active price of each plan from Stripe, by its
+// lookup key or else its configured price ID
+func loadBillingPlans(stripeConfig StripeConfig) ([]BillingPlan, error) {
+	params := &stripe.PriceListParams{Active: stripe.Bool(true)}
+	for _, key := range planKeys {
+		params.LookupKeys = append(params.LookupKeys, stripe.String(key))
+	}
+	params.AddExpand("data.product")
+
+	prices := make(map[string]*stripe.Price)
+	iter := price.List(params)
+	for iter.Next() {
+		p := iter.Price()
+		prices[p.LookupKey] = p
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list Stripe prices: %w", err)
+	}
+
+	for key, priceID := range fallbackPriceIDs(stripeConfig) {
+		if prices[key] != nil || priceID == "" {
+			continue
+		}
+		priceParams := &stripe.PriceParams{}
+		priceParams.AddExpand("product")
+		p, err := price.Get(priceID, priceParams)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Stripe price %s: %w", priceID, err)
+		}
+		prices[key] = p
+	}
+
+	plans := make([]BillingPlan, 0, len(planKeys))
+	for _, key := range planKeys {
+		p := prices[key]
+		if p == nil {
+			continue
+		}
+		plan := BillingPlan{
+			ID:         key,
+			PriceID:    p.ID,
+			Tier:       planTier(key),
+			UnitAmount: p.UnitAmount,
+			Currency:   string(p.Currency),
+		}
+		if p.Product != nil {
+			plan.Name, plan.Description = p.Product.Name, p.Product.Description
+		}
+		if p.Recurring != nil {
+			plan.Interval, plan.IntervalCount = string(p.Recurring.Interval), p.Recurring.IntervalCount
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// findPlan returns the plan sold at priceID, or nil when none is
+func findPlan(priceID string) (*BillingPlan, error) {
+	plans, err := billingPlans()
+	if err != nil {
+		return nil, err
+	}
+	for i := range plans {
+		if plans[i].PriceID == priceID {
+			return &plans[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// tierForPrice returns the subscription tier a subscribed price grants, by
+// its lookup key or else the configured price IDs
+func tierForPrice(p *stripe.Price, stripeConfig StripeConfig) string {
+	if p == nil {
+		return "free"
+	}
+	for key, priceID := range fallbackPriceIDs(stripeConfig) {
+		if p.LookupKey == key || (priceID != "" && p.ID == priceID) {
+			return planTier(key)
+		}
+	}
+	return "free"
+}
+
+// findPromotionCode returns the ID of the active promotion code a customer
+// typed, or "" when there is none
+func findPromotionCode(code string) (string, error) {
+	params := &stripe.PromotionCodeListParams{
+		Active: stripe.Bool(true),
+		Code:   stripe.String(code),
+	}
+	params.Limit = stripe.Int64(1)
+
+	iter := promotioncode.List(params)
+	if iter.Next() {
+		return iter.PromotionCode().ID, nil
+	}
+	if err := iter.Err(); err != nil {
+		return "", fmt.Errorf("failed to look up promotion code: %w", err)
+	}
+	return "", nil
+}
+
+// handleBillingPlans returns the plans for sale with their Stripe prices
+func (s *Server) handleBillingPlans(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if GetStripeConfig().SecretKey == "" {
+		s.respondError(w, http.StatusInternalServerError, "Stripe not configured")
+		return
+	}
+
+	plans, err := billingPlans()
+	if err != nil {
+		s.logger.Error("Failed to load billing plans", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to load billing plans")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, BillingPlansResponse{Plans: plans})
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/store"
+	"go.uber.org/zap"
+)
+
+// runningCrawls are the crawls this server is running, by crawl ID
+type runningCrawls struct {
+	mu       sync.Mutex
+	managers map[string]*crawler.Manager
+}
+
+func (c *runningCrawls) add(crawlID string, manager *crawler.Manager) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.managers == nil {
+		c.managers = make(map[string]*crawler.Manager)
+	}
+	c.managers[crawlID] = manager
+}
+
+func (c *runningCrawls) remove(crawlID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.managers, crawlID)
+}
+
+// get returns the manager of a crawl, or nil when this server isn't running
+// it
+func (c *runningCrawls) get(crawlID string) *crawler.Manager {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.managers[crawlID]
+}
+
+// handleCancelCrawl handles POST /api/v1/crawls/:id/cancel - stops a crawl
+// this server is running. The pages being fetched finish and the pages
+// crawled so far are kept; the crawl then ends with status "cancelled" and
+// the reason as its error. The caller has checked the user's access to the
+// crawl.
+func (s *Server) handleCancelCrawl(w http.ResponseWriter, r *http.Request, crawlID, userID string) {
+	var req CancelCrawlRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+
+	crawl, err := s.store.GetCrawl(r.Context(), crawlID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.respondError(w, http.StatusNotFound, "Crawl not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to get crawl", zap.String("crawl_id", crawlID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get crawl")
+		return
+	}
+	if crawl.Status != "pending" && crawl.Status != "running" {
+		s.respondError(w, http.StatusConflict, fmt.Sprintf("Crawl already %s", crawl.Status))
+		return
+	}
+
+	// Crawls run by the CLI, or lost to a server restart, have no manager
+	// here; an administrator can still mark them complete
+	manager := s.running.get(crawlID)
+	if manager == nil {
+		s.respondError(w, http.StatusConflict, "Crawl isn't running on this server")
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = fmt.Sprintf("Cancelled by user %s", userID)
+	}
+	manager.Cancel(req.Reason)
+	s.logger.Info("Cancelled crawl", zap.String("crawl_id", crawlID), zap.String("user_id", userID), zap.String("reason", req.Reason))
+
+	s.respondJSON(w, http.StatusAccepted, CancelCrawlResponse{
+		CrawlID: crawlID,
+		Status:  "cancelling",
+		Message: "Crawl is stopping; the pages crawled so far are kept",
+	})
+}
@@ -6,7 +6,10 @@ import (
 
 type contextKey string
 
-const userIDKey contextKey = "user_id"
+const (
+	userIDKey contextKey = "user_id"
+	adminKey  contextKey = "admin"
+)
 
 // contextWithUserID adds user ID to context
 func contextWithUserID(ctx context.Context, userID string) context.Context {
@@ -19,3 +22,14 @@ func userIDFromContext(ctx context.Context) (string, bool) {
 	return userID, ok
 }
 
+
+// contextWithAdmin records whether the user may use the admin API
+func contextWithAdmin(ctx context.Context, admin bool) context.Context {
+	return context.WithValue(ctx, adminKey, admin)
+}
+
+// isAdminFromContext reports whether the user may use the admin API
+func isAdminFromContext(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey).(bool)
+	return admin
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/dillonlara115/barracuda/internal/entitlements"
+	"go.uber.org/zap"
+)
+
+// userEntitlements returns a user's subscription tier and what it entitles
+// them to. Self-hosted servers have no tiers and allow everything.
+func (s *Server) userEntitlements(userID string) (string, entitlements.Entitlements, error) {
+	if !s.hasSupabase() {
+		return "", entitlements.Unlimited, nil
+	}
+
+	profile, err := s.fetchProfile(userID)
+	if err != nil {
+		return "", entitlements.Entitlements{}, err
+	}
+	tier := entitlements.TierFree
+	if profile != nil {
+		if value, ok := profile["subscription_tier"].(string); ok && value != "" {
+			tier = value
+		}
+	}
+	return tier, s.config.Entitlements.For(tier), nil
+}
+
+// ownerEntitlements returns the entitlements of a project's owner, for
+// work done on the project without a user asking, such as scheduled syncs
+func (s *Server) ownerEntitlements(ctx context.Context, projectID string) (entitlements.Entitlements, error) {
+	project, err := s.store.GetProject(ctx, projectID)
+	if err != nil {
+		return entitlements.Entitlements{}, fmt.Errorf("failed to get project: %w", err)
+	}
+	_, ent, err := s.userEntitlements(project.OwnerID)
+	return ent, err
+}
+
+// requireEntitlement responds with 403 and returns false when the user's
+// plan doesn't include feature, a description such as "Search Console sync"
+func (s *Server) requireEntitlement(w http.ResponseWriter, userID, feature string, allowed func(entitlements.Entitlements) bool) bool {
+	tier, ent, err := s.userEntitlements(userID)
+	if err != nil {
+		s.logger.Error("Failed to load entitlements", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to verify subscription")
+		return false
+	}
+	if !allowed(ent) {
+		s.respondError(w, http.StatusForbidden, fmt.Sprintf("Your %s plan doesn't include %s. Please upgrade to use it.", tier, feature))
+		return false
+	}
+	return true
+}
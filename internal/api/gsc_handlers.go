@@ -1,7 +1,6 @@
 package api
 
 import (
-	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/dillonlara115/barracuda/internal/entitlements"
 	"github.com/dillonlara115/barracuda/internal/gsc"
 	"go.uber.org/zap"
 )
@@ -31,6 +31,15 @@ func (s *Server) handleProjectGSC(w http.ResponseWriter, r *http.Request, projec
 		return
 	}
 
+	// Connecting and syncing need a plan with Search Console sync; data
+	// already synced stays readable
+	switch segments[0] {
+	case "connect", "properties", "property", "trigger-sync":
+		if !s.requireEntitlement(w, userID, "Search Console sync", func(e entitlements.Entitlements) bool { return e.GSCSync }) {
+			return
+		}
+	}
+
 	switch segments[0] {
 	case "connect":
 		s.handleProjectGSCConnect(w, r, projectID)
@@ -67,9 +76,9 @@ func (s *Server) handleProjectGSCConnect(w http.ResponseWriter, r *http.Request,
 		s.logger.Warn("Failed to ensure GSC sync state", zap.Error(err))
 	}
 
-	s.respondJSON(w, http.StatusOK, map[string]string{
-		"auth_url": authURL,
-		"state":    state,
+	s.respondJSON(w, http.StatusOK, gsc.AuthURLResponse{
+		AuthURL: authURL,
+		State:   state,
 	})
 }
 
@@ -352,18 +361,7 @@ func (s *Server) handleGSCGlobalSync(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if s.cronSecret == "" {
-		s.respondError(w, http.StatusServiceUnavailable, "Cron sync secret not configured")
-		return
-	}
-
-	secret := r.Header.Get("X-Cron-Secret")
-	if secret == "" {
-		secret = r.URL.Query().Get("secret")
-	}
-
-	if subtle.ConstantTimeCompare([]byte(secret), []byte(s.cronSecret)) != 1 {
-		s.respondError(w, http.StatusUnauthorized, "Unauthorized")
+	if !s.checkCronSecret(w, r) {
 		return
 	}
 
@@ -445,6 +443,19 @@ func (s *Server) handleGSCGlobalSync(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
+		ent, err := s.ownerEntitlements(r.Context(), projectID)
+		if err != nil {
+			entry["status"] = "error"
+			entry["error"] = fmt.Sprintf("failed to load entitlements: %v", err)
+			results = append(results, entry)
+			continue
+		}
+		if !ent.GSCSync {
+			entry["message"] = "Owner's plan doesn't include Search Console sync"
+			results = append(results, entry)
+			continue
+		}
+
 		if _, err := s.ensureGSCSyncState(projectID, cfg.PropertyURL); err != nil {
 			entry["status"] = "error"
 			entry["error"] = fmt.Sprintf("failed to ensure sync state: %v", err)
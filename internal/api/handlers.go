@@ -1,10 +1,13 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
+	"net/url"
+	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,10 +15,12 @@ import (
 
 	"github.com/dillonlara115/barracuda/internal/analyzer"
 	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/entitlements"
 	"github.com/dillonlara115/barracuda/internal/gsc"
+	"github.com/dillonlara115/barracuda/internal/store"
 	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/internal/verify"
 	"github.com/dillonlara115/barracuda/pkg/models"
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -33,9 +38,9 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, map[string]string{
-		"status": "healthy",
-		"time":   time.Now().UTC().Format(time.RFC3339),
+	s.respondJSON(w, http.StatusOK, HealthResponse{
+		Status: "healthy",
+		Time:   time.Now().UTC().Format(time.RFC3339),
 	})
 }
 
@@ -76,6 +81,16 @@ func (s *Server) handleCreateCrawl(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Uploads come from a CLI crawl, run by hand or on a schedule
+	switch req.Source {
+	case "":
+		req.Source = "cli"
+	case "cli", "schedule":
+	default:
+		s.respondError(w, http.StatusBadRequest, `source must be "cli" or "schedule"`)
+		return
+	}
+
 	// Verify user has access to project
 	hasAccess, err := s.verifyProjectAccess(userID, req.ProjectID)
 	if err != nil {
@@ -87,127 +102,78 @@ func (s *Server) handleCreateCrawl(w http.ResponseWriter, r *http.Request) {
 		s.respondError(w, http.StatusForbidden, "You don't have access to this project")
 		return
 	}
+	if req.Source == "schedule" && !s.requireEntitlement(w, userID, "scheduled crawls", func(e entitlements.Entitlements) bool { return e.ScheduledCrawls }) {
+		return
+	}
 
 	// Analyze pages to detect issues
 	summary := analyzer.AnalyzeWithImages(req.Pages, 30*time.Second)
 
 	// Create crawl record
-	crawlID := uuid.New().String()
-	crawl := map[string]interface{}{
-		"id":           crawlID,
-		"project_id":   req.ProjectID,
-		"initiated_by": userID,
-		"source":       "cli",
-		"status":       "succeeded",
-		"started_at":   time.Now().UTC().Format(time.RFC3339),
-		"completed_at": time.Now().UTC().Format(time.RFC3339),
-		"total_pages":  len(req.Pages),
-		"total_issues": len(summary.Issues),
-		"meta": map[string]interface{}{
+	now := time.Now().UTC()
+	crawl := &store.Crawl{
+		ProjectID:   req.ProjectID,
+		InitiatedBy: userID,
+		Source:      req.Source,
+		Status:      "succeeded",
+		StartedAt:   now,
+		CompletedAt: &now,
+		TotalPages:  len(req.Pages),
+		TotalIssues: len(summary.Issues),
+		Meta: map[string]interface{}{
 			"user_agent": r.Header.Get("User-Agent"),
 		},
 	}
+	if req.Metadata != nil {
+		crawl.Meta[store.ManifestKey] = req.Metadata
+		crawl.RobotsTxt = req.Metadata.RobotsTxt
+		if !req.Metadata.StartedAt.IsZero() {
+			crawl.StartedAt = req.Metadata.StartedAt.UTC()
+		}
+		if !req.Metadata.CompletedAt.IsZero() {
+			completedAt := req.Metadata.CompletedAt.UTC()
+			crawl.CompletedAt = &completedAt
+		}
+	}
 
-	// Insert crawl using service role (bypasses RLS)
-	_, _, err = s.serviceRole.From("crawls").Insert(crawl, false, "", "", "").Execute()
-	if err != nil {
+	if err := s.store.SaveCrawl(r.Context(), crawl); err != nil {
 		s.logger.Error("Failed to insert crawl", zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to create crawl")
 		return
 	}
 
-	// Insert pages in batch
-	pages := make([]map[string]interface{}, 0, len(req.Pages))
-	for _, page := range req.Pages {
-		pageData := map[string]interface{}{
-			"crawl_id":         crawlID,
-			"url":              page.URL,
-			"status_code":      page.StatusCode,
-			"response_time_ms": page.ResponseTime,
-			"title":            page.Title,
-			"meta_description": page.MetaDesc,
-			"canonical_url":    page.Canonical,
-			"h1":               strings.Join(page.H1, ", "),
-			"word_count":       0, // TODO: calculate from content
-			"data": map[string]interface{}{
-				"h2":             page.H2,
-				"h3":             page.H3,
-				"h4":             page.H4,
-				"h5":             page.H5,
-				"h6":             page.H6,
-				"internal_links": page.InternalLinks,
-				"external_links": page.ExternalLinks,
-				"images":         page.Images,
-			},
-		}
-		pages = append(pages, pageData)
-	}
-
-	// Batch insert pages (Supabase supports up to 1000 rows per insert)
+	// Insert pages in batches (Supabase supports up to 1000 rows per insert)
 	batchSize := 1000
-	for i := 0; i < len(pages); i += batchSize {
-		end := i + batchSize
-		if end > len(pages) {
-			end = len(pages)
+	pageIDs := make(map[string]int64, len(req.Pages))
+	for i := 0; i < len(req.Pages); i += batchSize {
+		end := min(i+batchSize, len(req.Pages))
+		batch := make([]*store.Page, 0, end-i)
+		for _, page := range req.Pages[i:end] {
+			batch = append(batch, store.NewPage(crawl.ID, page))
 		}
-		batch := pages[i:end]
 
-		_, _, err = s.serviceRole.From("pages").Insert(batch, false, "", "", "").Execute()
-		if err != nil {
+		if err := s.store.SavePages(r.Context(), batch); err != nil {
 			s.logger.Error("Failed to insert pages batch", zap.Int("batch_start", i), zap.Error(err))
 			// Continue with other batches, but log error
+			continue
 		}
-	}
-
-	// Insert issues
-	issues := make([]map[string]interface{}, 0, len(summary.Issues))
-	for _, issue := range summary.Issues {
-		// Find page ID for this issue
-		var pageID *int64
-		for i, page := range req.Pages {
-			if page.URL == issue.URL {
-				// We'd need to fetch the page ID from the database
-				// For now, we'll insert without page_id and update later if needed
-				_ = i
-				break
-			}
-		}
-
-		issueData := map[string]interface{}{
-			"crawl_id":       crawlID,
-			"project_id":     req.ProjectID,
-			"type":           string(issue.Type),
-			"severity":       issue.Severity,
-			"message":        issue.Message,
-			"recommendation": issue.Recommendation,
-			"value":          issue.Value,
-			"status":         "new",
-		}
-		if pageID != nil {
-			issueData["page_id"] = *pageID
+		for _, page := range batch {
+			pageIDs[page.URL] = page.ID
 		}
-		issues = append(issues, issueData)
 	}
 
-	if len(issues) > 0 {
-		// Batch insert issues
-		for i := 0; i < len(issues); i += batchSize {
-			end := i + batchSize
-			if end > len(issues) {
-				end = len(issues)
-			}
-			batch := issues[i:end]
-
-			_, _, err = s.serviceRole.From("issues").Insert(batch, false, "", "", "").Execute()
-			if err != nil {
-				s.logger.Error("Failed to insert issues batch", zap.Int("batch_start", i), zap.Error(err))
-			}
+	// Insert issues
+	issues := issueRecords(crawl.ID, req.ProjectID, summary.Issues, pageIDs)
+	for i := 0; i < len(issues); i += batchSize {
+		end := min(i+batchSize, len(issues))
+		if err := s.store.SaveIssues(r.Context(), issues[i:end]); err != nil {
+			s.logger.Error("Failed to insert issues batch", zap.Int("batch_start", i), zap.Error(err))
 		}
 	}
 
 	// Return crawl response
 	response := CreateCrawlResponse{
-		CrawlID:     crawlID,
+		CrawlID:     crawl.ID,
 		ProjectID:   req.ProjectID,
 		TotalPages:  len(req.Pages),
 		TotalIssues: len(summary.Issues),
@@ -217,43 +183,43 @@ func (s *Server) handleCreateCrawl(w http.ResponseWriter, r *http.Request) {
 	s.respondJSON(w, http.StatusCreated, response)
 }
 
+// issueRecords converts analyzer issues for storage, linking each to its page
+// when the page ID is known
+func issueRecords(crawlID, projectID string, issues []analyzer.Issue, pageIDs map[string]int64) []*store.Issue {
+	records := make([]*store.Issue, 0, len(issues))
+	for _, issue := range issues {
+		record := store.NewIssue(crawlID, projectID, issue)
+		if pageID, ok := pageIDs[issue.URL]; ok {
+			record.PageID = &pageID
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
 // handleListCrawls handles GET /api/v1/crawls - list crawls
 func (s *Server) handleListCrawls(w http.ResponseWriter, r *http.Request) {
-	_, ok := userIDFromContext(r.Context())
+	userID, ok := userIDFromContext(r.Context())
 	if !ok {
 		s.respondError(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// Get project_id from query params (optional filter)
-	projectID := r.URL.Query().Get("project_id")
-
-	// Build query - user can only see crawls from projects they're a member of
-	query := s.supabase.From("crawls").Select("*", "", false)
-
-	if projectID != "" {
-		query = query.Eq("project_id", projectID)
-	}
-
-	// The RLS policies will automatically filter to only projects the user has access to
-	var crawls []map[string]interface{}
-	data, _, err := query.Execute()
+	// Users only see crawls from projects they own or are a member of,
+	// optionally filtered to one project
+	crawls, err := s.store.ListCrawls(r.Context(), store.CrawlFilter{
+		ProjectID: r.URL.Query().Get("project_id"),
+		UserID:    userID,
+	})
 	if err != nil {
 		s.logger.Error("Failed to list crawls", zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to list crawls")
 		return
 	}
 
-	// Parse data into crawls slice
-	if err := json.Unmarshal(data, &crawls); err != nil {
-		s.logger.Error("Failed to parse crawls data", zap.Error(err))
-		s.respondError(w, http.StatusInternalServerError, "Failed to parse crawls")
-		return
-	}
-
-	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"crawls": crawls,
-		"count":  len(crawls),
+	s.respondJSON(w, http.StatusOK, ListCrawlsResponse{
+		Crawls: crawls,
+		Count:  len(crawls),
 	})
 }
 
@@ -288,75 +254,78 @@ func (s *Server) handleCreateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	project := map[string]interface{}{
-		"name":     req.Name,
-		"domain":   req.Domain,
-		"owner_id": userID,
-		"settings": req.Settings,
+	// Only projects the user owns count toward their plan's limit
+	tier, ent, err := s.userEntitlements(userID)
+	if err != nil {
+		s.logger.Error("Failed to load entitlements", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to verify subscription")
+		return
+	}
+	if ent.MaxProjects > 0 {
+		projects, err := s.store.ListProjects(r.Context(), userID)
+		if err != nil {
+			s.logger.Error("Failed to list projects", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to create project")
+			return
+		}
+		owned := 0
+		for _, project := range projects {
+			if project.OwnerID == userID {
+				owned++
+			}
+		}
+		if owned >= ent.MaxProjects {
+			s.respondError(w, http.StatusForbidden, fmt.Sprintf("Your %s plan allows a maximum of %d projects. Please upgrade to create more projects.", tier, ent.MaxProjects))
+			return
+		}
 	}
 
-	var result []map[string]interface{}
-	data, _, err := s.supabase.From("projects").Insert(project, false, "", "", "").Execute()
+	token, err := verify.NewToken()
 	if err != nil {
-		s.logger.Error("Failed to create project", zap.Error(err))
+		s.logger.Error("Failed to generate verification token", zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to create project")
 		return
 	}
-
-	// Parse data into result slice
-	if err := json.Unmarshal(data, &result); err != nil {
-		s.logger.Error("Failed to parse project data", zap.Error(err))
-		s.respondError(w, http.StatusInternalServerError, "Failed to parse project")
-		return
+	project := &store.Project{
+		Name:              req.Name,
+		Domain:            req.Domain,
+		OwnerID:           userID,
+		Settings:          req.Settings,
+		VerificationToken: token,
 	}
-
-	if len(result) == 0 {
-		s.respondError(w, http.StatusInternalServerError, "Project created but no data returned")
+	if err := s.store.SaveProject(r.Context(), project); err != nil {
+		s.logger.Error("Failed to create project", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to create project")
 		return
 	}
 
 	// Also add the owner as a project member with 'owner' role
-	member := map[string]interface{}{
-		"project_id": result[0]["id"],
-		"user_id":    userID,
-		"role":       "owner",
-	}
-	_, _, err = s.serviceRole.From("project_members").Insert(member, false, "", "", "").Execute()
-	if err != nil {
+	if err := s.store.AddProjectMember(r.Context(), project.ID, userID, "owner"); err != nil {
 		s.logger.Warn("Failed to add owner as project member", zap.Error(err))
 		// Continue anyway - the project was created
 	}
 
-	s.respondJSON(w, http.StatusCreated, result[0])
+	s.respondJSON(w, http.StatusCreated, project)
 }
 
 // handleListProjects handles GET /api/v1/projects
 func (s *Server) handleListProjects(w http.ResponseWriter, r *http.Request) {
-	_, ok := userIDFromContext(r.Context())
+	userID, ok := userIDFromContext(r.Context())
 	if !ok {
 		s.respondError(w, http.StatusUnauthorized, "User not authenticated")
 		return
 	}
 
-	// RLS policies will automatically filter to projects user has access to
-	var projects []map[string]interface{}
-	data, _, err := s.supabase.From("projects").Select("*", "", false).Execute()
+	projects, err := s.store.ListProjects(r.Context(), userID)
 	if err != nil {
 		s.logger.Error("Failed to list projects", zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to list projects")
 		return
 	}
 
-	// Parse data into projects slice
-	if err := json.Unmarshal(data, &projects); err != nil {
-		s.logger.Error("Failed to parse projects data", zap.Error(err))
-		s.respondError(w, http.StatusInternalServerError, "Failed to parse projects")
-		return
-	}
-
-	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"projects": projects,
-		"count":    len(projects),
+	s.respondJSON(w, http.StatusOK, ListProjectsResponse{
+		Projects: projects,
+		Count:    len(projects),
 	})
 }
 
@@ -410,7 +379,17 @@ func (s *Server) handleProjectByID(w http.ResponseWriter, r *http.Request) {
 				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			}
 			return
+		case "verification":
+			s.handleProjectVerification(w, r, projectID, userID)
+			return
+		case "trends":
+			s.handleProjectTrends(w, r, projectID, userID)
+			return
 		case "gsc":
+			if !s.hasSupabase() {
+				s.respondError(w, http.StatusNotImplemented, "Search Console integration requires Supabase")
+				return
+			}
 			s.handleProjectGSC(w, r, projectID, userID, parts[2:])
 			return
 		default:
@@ -443,27 +422,18 @@ func (s *Server) handleGetProject(w http.ResponseWriter, r *http.Request, projec
 		return
 	}
 
-	var projects []map[string]interface{}
-	data, _, err := s.supabase.From("projects").Select("*", "", false).Eq("id", projectID).Execute()
+	project, err := s.store.GetProject(r.Context(), projectID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.respondError(w, http.StatusNotFound, "Project not found")
+		return
+	}
 	if err != nil {
 		s.logger.Error("Failed to get project", zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to get project")
 		return
 	}
 
-	// Parse data into projects slice
-	if err := json.Unmarshal(data, &projects); err != nil {
-		s.logger.Error("Failed to parse project data", zap.Error(err))
-		s.respondError(w, http.StatusInternalServerError, "Failed to parse project")
-		return
-	}
-
-	if len(projects) == 0 {
-		s.respondError(w, http.StatusNotFound, "Project not found")
-		return
-	}
-
-	s.respondJSON(w, http.StatusOK, projects[0])
+	s.respondJSON(w, http.StatusOK, project)
 }
 
 // handleListProjectCrawls handles GET /api/v1/projects/:id/crawls
@@ -480,24 +450,16 @@ func (s *Server) handleListProjectCrawls(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	var crawls []map[string]interface{}
-	data, _, err := s.supabase.From("crawls").Select("*", "", false).Eq("project_id", projectID).Order("started_at", nil).Execute()
+	crawls, err := s.store.ListCrawls(r.Context(), store.CrawlFilter{ProjectID: projectID})
 	if err != nil {
 		s.logger.Error("Failed to list project crawls", zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to list crawls")
 		return
 	}
 
-	// Parse data into crawls slice
-	if err := json.Unmarshal(data, &crawls); err != nil {
-		s.logger.Error("Failed to parse crawls data", zap.Error(err))
-		s.respondError(w, http.StatusInternalServerError, "Failed to parse crawls")
-		return
-	}
-
-	s.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"crawls": crawls,
-		"count":  len(crawls),
+	s.respondJSON(w, http.StatusOK, ListCrawlsResponse{
+		Crawls: crawls,
+		Count:  len(crawls),
 	})
 }
 
@@ -508,6 +470,15 @@ func (s *Server) handleExports(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		s.respondError(w, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+	if !s.requireEntitlement(w, userID, "exports", func(e entitlements.Entitlements) bool { return e.Exports }) {
+		return
+	}
+
 	// TODO: Implement export generation
 	s.respondError(w, http.StatusNotImplemented, "Export functionality not yet implemented")
 }
@@ -539,6 +510,26 @@ func (s *Server) handleTriggerCrawl(w http.ResponseWriter, r *http.Request, proj
 		s.respondError(w, http.StatusBadRequest, "url is required")
 		return
 	}
+	startURL, err := url.Parse(req.URL)
+	if err != nil || startURL.Hostname() == "" || (startURL.Scheme != "http" && startURL.Scheme != "https") {
+		s.respondError(w, http.StatusBadRequest, "url must be an absolute http or https URL")
+		return
+	}
+
+	project, err := s.store.GetProject(r.Context(), projectID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.respondError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to get project", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get project")
+		return
+	}
+	if reason := s.checkCrawlTarget(project, startURL.Hostname()); reason != "" {
+		s.respondError(w, http.StatusForbidden, reason)
+		return
+	}
 	if req.MaxDepth == 0 {
 		req.MaxDepth = 3
 	}
@@ -546,115 +537,68 @@ func (s *Server) handleTriggerCrawl(w http.ResponseWriter, r *http.Request, proj
 		req.Workers = 10
 	}
 
-	// Get user profile to check subscription tier
-	profile, err := s.fetchProfile(userID)
+	// Enforce the limits of the user's plan
+	tier, ent, err := s.userEntitlements(userID)
 	if err != nil {
-		s.logger.Error("Failed to fetch user profile", zap.Error(err))
+		s.logger.Error("Failed to load entitlements", zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to verify subscription")
 		return
 	}
-
-	// Determine max pages limit based on subscription tier
-	subscriptionTier := "free"
-	if profile != nil {
-		if tier, ok := profile["subscription_tier"].(string); ok && tier != "" {
-			subscriptionTier = tier
+	if req.MaxPages == 0 {
+		req.MaxPages = 1000
+		if ent.MaxPages > 0 {
+			req.MaxPages = ent.MaxPages
 		}
 	}
-
-	var maxPagesLimit int
-	switch subscriptionTier {
-	case "pro":
-		maxPagesLimit = 10000
-	case "team":
-		maxPagesLimit = 25000
-	default: // free
-		maxPagesLimit = 100
-	}
-
-	// Set default max pages if not provided
-	if req.MaxPages == 0 {
-		req.MaxPages = maxPagesLimit
+	if ent.MaxPages > 0 && req.MaxPages > ent.MaxPages {
+		s.respondError(w, http.StatusForbidden, fmt.Sprintf("Your %s plan allows a maximum of %d pages per crawl. Please upgrade to crawl more pages.", tier, ent.MaxPages))
+		return
 	}
-
-	// Enforce subscription limit
-	if req.MaxPages > maxPagesLimit {
-		s.respondError(w, http.StatusForbidden, fmt.Sprintf("Your %s plan allows a maximum of %d pages per crawl. Please upgrade to crawl more pages.", subscriptionTier, maxPagesLimit))
+	if req.Render && !ent.Render {
+		s.respondError(w, http.StatusForbidden, fmt.Sprintf("Your %s plan doesn't include rendered crawls. Please upgrade to use it.", tier))
 		return
 	}
 
 	// Create crawl record with status "running"
-	crawlID := uuid.New().String()
-	crawl := map[string]interface{}{
-		"id":           crawlID,
-		"project_id":   projectID,
-		"initiated_by": userID,
-		"source":       "web",
-		"status":       "running",
-		"started_at":   time.Now().UTC().Format(time.RFC3339),
-		"total_pages":  0,
-		"total_issues": 0,
-		"meta": map[string]interface{}{
+	crawl := &store.Crawl{
+		ProjectID:   projectID,
+		InitiatedBy: userID,
+		Source:      "web",
+		Status:      "running",
+		Meta: map[string]interface{}{
 			"url":            req.URL,
 			"max_depth":      req.MaxDepth,
 			"max_pages":      req.MaxPages,
 			"workers":        req.Workers,
 			"respect_robots": req.RespectRobots,
 			"parse_sitemap":  req.ParseSitemap,
+			"render":         req.Render,
 		},
 	}
 
-	// Insert crawl using service role (bypasses RLS)
-	s.logger.Info("Attempting to insert crawl", zap.String("crawl_id", crawlID), zap.String("project_id", projectID))
-	data, _, err := s.serviceRole.From("crawls").Insert(crawl, false, "", "", "").Execute()
-	if err != nil {
-		s.logger.Error("Failed to insert crawl", zap.String("crawl_id", crawlID), zap.Error(err), zap.Any("crawl_data", crawl))
+	s.logger.Info("Attempting to insert crawl", zap.String("project_id", projectID))
+	if err := s.store.SaveCrawl(r.Context(), crawl); err != nil {
+		s.logger.Error("Failed to insert crawl", zap.String("crawl_id", crawl.ID), zap.Error(err), zap.Any("crawl_data", crawl))
 		s.respondError(w, http.StatusInternalServerError, "Failed to create crawl")
 		return
 	}
-
-	// Verify the crawl was inserted by checking the returned data
-	if len(data) == 0 {
-		s.logger.Warn("Crawl insert returned no data", zap.String("crawl_id", crawlID))
-	} else {
-		s.logger.Info("Crawl created and verified", zap.String("crawl_id", crawlID), zap.String("status", "running"), zap.Int("data_length", len(data)))
-	}
-
-	// Double-check: Verify crawl exists in database before returning
-	// Add a small delay to ensure transaction is committed
-	time.Sleep(100 * time.Millisecond)
-
-	var verifyCrawls []map[string]interface{}
-	verifyData, _, verifyErr := s.serviceRole.From("crawls").Select("id,project_id", "", false).Eq("id", crawlID).Execute()
-	if verifyErr != nil {
-		s.logger.Error("Failed to verify crawl after insert", zap.String("crawl_id", crawlID), zap.Error(verifyErr))
-	} else if err := json.Unmarshal(verifyData, &verifyCrawls); err == nil {
-		if len(verifyCrawls) > 0 {
-			verifyProjectID, _ := verifyCrawls[0]["project_id"].(string)
-			s.logger.Info("Crawl verified in database",
-				zap.String("crawl_id", crawlID),
-				zap.String("project_id", verifyProjectID),
-				zap.String("expected_project_id", projectID))
-		} else {
-			s.logger.Error("Crawl not found in database after insert!",
-				zap.String("crawl_id", crawlID),
-				zap.String("project_id", projectID))
-		}
-	}
+	s.logger.Info("Crawl created", zap.String("crawl_id", crawl.ID), zap.String("status", crawl.Status))
 
 	// Start crawl asynchronously
-	go s.runCrawlAsync(crawlID, projectID, req)
+	go s.runCrawlAsync(crawl.ID, projectID, req)
 
 	// Return immediately with crawl ID
-	s.respondJSON(w, http.StatusAccepted, map[string]interface{}{
-		"crawl_id": crawlID,
-		"status":   "running",
-		"message":  "Crawl started",
+	s.respondJSON(w, http.StatusAccepted, TriggerCrawlResponse{
+		CrawlID: crawl.ID,
+		Status:  "running",
+		Message: "Crawl started",
 	})
 }
 
 // runCrawlAsync runs the crawler and stores results
 func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlRequest) {
+	ctx := context.Background()
+
 	// Initialize logger for crawler (enable debug temporarily to diagnose crawling issues)
 	if err := utils.InitLogger(true); err != nil {
 		s.logger.Error("Failed to initialize logger", zap.Error(err))
@@ -670,15 +614,30 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 		MaxPages:      req.MaxPages,
 		Workers:       req.Workers,
 		Delay:         0,
+		AutoThrottle:  true,
 		Timeout:       30 * time.Second,
 		UserAgent:     "barracuda/1.0.0",
 		RespectRobots: req.RespectRobots,
 		ParseSitemap:  req.ParseSitemap,
+		Render:        req.Render,
 		DomainFilter:  "same",
 		ExportFormat:  "csv", // Required for validation, but not used since we store in DB
 		ExportPath:    "",    // Not used for web crawls
 	}
 
+	// HTML snapshots are written to a temporary directory, then stored as
+	// artifacts once the crawl finishes
+	if req.SaveHTML && s.artifacts != nil {
+		htmlDir, err := os.MkdirTemp("", "barracuda-html-")
+		if err != nil {
+			s.logger.Error("Failed to create HTML snapshot directory", zap.Error(err))
+			s.updateCrawlStatus(crawlID, "failed", fmt.Sprintf("Failed to create HTML snapshot directory: %v", err))
+			return
+		}
+		defer os.RemoveAll(htmlDir)
+		config.SaveHTMLDir = htmlDir
+	}
+
 	// Validate config
 	if err := config.Validate(); err != nil {
 		s.logger.Error("Invalid crawl config", zap.Error(err))
@@ -686,86 +645,94 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 		return
 	}
 
-	// Create crawler manager
+	// Create crawler manager, which handleCancelCrawl can stop until the
+	// crawl's outcome is stored
 	manager := crawler.NewManager(config)
+	s.running.add(crawlID, manager)
+	defer s.running.remove(crawlID)
 
-	// Track pages and page URL to ID mapping for real-time storage
+	// Analyze pages as they are crawled so issues are stored with their pages
+	analysis := analyzer.NewIncrementalWithImages(config.Timeout)
+	manager.SetAnalyzer(analysis)
+
+	// Track pages, their issues, and page URL to ID mapping for real-time storage
 	batchSize := 50 // Smaller batches for more frequent updates
-	pages := make([]map[string]interface{}, 0, batchSize)
+	pages := make([]*store.Page, 0, batchSize)
+	pageIssues := make([]analyzer.Issue, 0)
 	pageURLToID := make(map[string]int64)
 	var pagesMu sync.Mutex
 	totalPagesProcessed := int32(0)
+	issuesFound := 0 // Guarded by pagesMu
+
+	// savePages stores the buffered pages and records their IDs, then stores
+	// the buffered issues linked to those pages. The caller must hold pagesMu.
+	savePages := func() error {
+		if err := s.store.SavePages(ctx, pages); err != nil {
+			return err
+		}
+		for _, page := range pages {
+			pageURLToID[page.URL] = page.ID
+		}
+		if len(pageIssues) > 0 {
+			if err := s.store.SaveIssues(ctx, issueRecords(crawlID, projectID, pageIssues, pageURLToID)); err != nil {
+				s.logger.Error("Failed to insert issues batch", zap.Error(err))
+			}
+			pageIssues = pageIssues[:0]
+		}
+		return nil
+	}
+
+	running := "running" // Ensure status stays as running
+
+	// Record why each URL was crawled or skipped
+	logs := &crawlLogRecorder{server: s, ctx: ctx, crawlID: crawlID}
+	manager.OnEvent(logs.Record)
 
-	// Set up progress callback to store pages in real-time
-	manager.SetProgressCallback(func(page *models.PageResult, totalPages int) {
+	// A page's issues are reported before the page, so each batch holds
+	// the issues of its pages
+	manager.OnIssueFound(func(page *models.PageResult, issue analyzer.Issue) {
 		pagesMu.Lock()
 		defer pagesMu.Unlock()
+		pageIssues = append(pageIssues, issue)
+		issuesFound++
+	})
 
-		pageData := map[string]interface{}{
-			"crawl_id":         crawlID,
-			"url":              page.URL,
-			"status_code":      page.StatusCode,
-			"response_time_ms": page.ResponseTime,
-			"title":            page.Title,
-			"meta_description": page.MetaDesc,
-			"canonical_url":    page.Canonical,
-			"h1":               strings.Join(page.H1, ", "),
-			"word_count":       0, // TODO: calculate from content
-			"data": map[string]interface{}{
-				"h2":             page.H2,
-				"h3":             page.H3,
-				"h4":             page.H4,
-				"h5":             page.H5,
-				"h6":             page.H6,
-				"internal_links": page.InternalLinks,
-				"external_links": page.ExternalLinks,
-				"images":         page.Images,
-			},
-		}
-		pages = append(pages, pageData)
+	// Store pages in real-time
+	manager.OnPageCrawled(func(page *models.PageResult, totalPages int) {
+		pagesMu.Lock()
+		defer pagesMu.Unlock()
+
+		pages = append(pages, store.NewPage(crawlID, page))
 
 		// Increment total pages processed (for each page)
 		atomic.AddInt32(&totalPagesProcessed, 1)
 		currentTotal := int(atomic.LoadInt32(&totalPagesProcessed))
+		currentIssues := issuesFound
+		// Each update is published to the dashboard as a Realtime event on the
+		// crawls row; see docs/SUPABASE_SCHEMA.md
+		update := store.CrawlUpdate{TotalPages: &currentTotal, TotalIssues: &currentIssues}
+		if manager.CancelReason() == "" {
+			// Pages finishing after a cancellation mustn't reopen a crawl an
+			// administrator has completed
+			update.Status = &running
+		}
 
 		// Insert in batches and update progress
 		if len(pages) >= batchSize {
-			var pageResults []map[string]interface{}
-			data, _, err := s.serviceRole.From("pages").Insert(pages, false, "", "", "").Execute()
-			if err != nil {
+			if err := savePages(); err != nil {
 				s.logger.Error("Failed to insert pages batch", zap.Error(err))
-			} else {
-				// Parse inserted pages to get IDs
-				if err := json.Unmarshal(data, &pageResults); err == nil {
-					for j, pageResult := range pageResults {
-						if pageID, ok := pageResult["id"].(float64); ok {
-							pageURLToID[pages[j]["url"].(string)] = int64(pageID)
-						}
-					}
-				}
-
+			} else if err := s.store.UpdateCrawl(ctx, crawlID, update); err != nil {
 				// Update crawl total_pages in real-time after batch insert
-				update := map[string]interface{}{
-					"total_pages": currentTotal,
-					"status":      "running", // Ensure status stays as running
-				}
-				_, _, err = s.serviceRole.From("crawls").Update(update, "", "").Eq("id", crawlID).Execute()
-				if err != nil {
-					s.logger.Warn("Failed to update crawl progress", zap.Error(err))
-				} else {
-					s.logger.Info("Updated crawl progress (batch)", zap.Int("total_pages", currentTotal), zap.String("status", "running"))
-				}
+				s.logger.Warn("Failed to update crawl progress", zap.Error(err))
+			} else {
+				s.logger.Info("Updated crawl progress (batch)", zap.Int("total_pages", currentTotal), zap.String("status", "running"))
 			}
-			pages = make([]map[string]interface{}, 0, batchSize)
+			pages = make([]*store.Page, 0, batchSize)
+			pageIssues = pageIssues[:0]
 		} else {
 			// Update progress for every page (best real-time updates)
 			// Only skip if we just updated in a batch to avoid redundant updates
-			update := map[string]interface{}{
-				"total_pages": currentTotal,
-				"status":      "running", // Ensure status stays as running
-			}
-			_, _, err := s.serviceRole.From("crawls").Update(update, "", "").Eq("id", crawlID).Execute()
-			if err != nil {
+			if err := s.store.UpdateCrawl(ctx, crawlID, update); err != nil {
 				s.logger.Warn("Failed to update crawl progress", zap.Error(err))
 			} else {
 				s.logger.Debug("Updated crawl progress (per-page)", zap.Int("total_pages", currentTotal), zap.String("status", "running"))
@@ -773,168 +740,113 @@ func (s *Server) runCrawlAsync(crawlID, projectID string, req TriggerCrawlReques
 		}
 	})
 
+	// Store any remaining pages and their issues once the crawl succeeds
+	manager.OnCrawlComplete(func(results []*models.PageResult, err error) {
+		if err != nil {
+			return
+		}
+		pagesMu.Lock()
+		defer pagesMu.Unlock()
+		if len(pages) > 0 {
+			if err := savePages(); err != nil {
+				s.logger.Error("Failed to insert final pages batch", zap.Error(err))
+			}
+		}
+		// Use the actual count from results, not the atomic counter (which might be off)
+		atomic.StoreInt32(&totalPagesProcessed, int32(len(results)))
+	})
+
 	// Run crawl
 	results, err := manager.Crawl()
+	logs.Flush()
+	if manager.Status() == crawler.StatusCancelled {
+		// The pages crawled before the cancellation stay stored. A crawl an
+		// administrator completed keeps the status they gave it.
+		if crawl, err := s.store.GetCrawl(ctx, crawlID); err == nil && crawl.Status != "pending" && crawl.Status != "running" {
+			return
+		}
+		s.updateCrawlStatus(crawlID, "cancelled", manager.CancelReason())
+		return
+	}
 	if err != nil {
 		s.logger.Error("Crawl failed", zap.Error(err))
 		s.updateCrawlStatus(crawlID, "failed", err.Error())
 		return
 	}
-
-	// Store any remaining pages
-	pagesMu.Lock()
-	if len(pages) > 0 {
-		var pageResults []map[string]interface{}
-		data, _, err := s.serviceRole.From("pages").Insert(pages, false, "", "", "").Execute()
-		if err != nil {
-			s.logger.Error("Failed to insert final pages batch", zap.Error(err))
-		} else {
-			// Parse inserted pages to get IDs
-			if err := json.Unmarshal(data, &pageResults); err == nil {
-				for j, pageResult := range pageResults {
-					if pageID, ok := pageResult["id"].(float64); ok {
-						pageURLToID[pages[j]["url"].(string)] = int64(pageID)
-					}
-				}
-			}
-		}
-	}
-	// Use the actual count from results, not the atomic counter (which might be off)
 	finalTotal := len(results)
-	// Ensure totalPagesProcessed matches finalTotal
-	atomic.StoreInt32(&totalPagesProcessed, int32(finalTotal))
-	pagesMu.Unlock()
-
-	// Analyze results
-	summary := analyzer.AnalyzeWithImages(results, config.Timeout)
-
-	// Store issues
-	issues := make([]map[string]interface{}, 0, len(summary.Issues))
-	for _, issue := range summary.Issues {
-		issueData := map[string]interface{}{
-			"crawl_id":       crawlID,
-			"project_id":     projectID,
-			"type":           string(issue.Type),
-			"severity":       issue.Severity,
-			"message":        issue.Message,
-			"recommendation": issue.Recommendation,
-			"value":          issue.Value,
-			"status":         "new",
-		}
-		// Try to find page ID
-		if pageID, ok := pageURLToID[issue.URL]; ok {
-			issueData["page_id"] = pageID
-		}
-		issues = append(issues, issueData)
-	}
-
-	if len(issues) > 0 {
-		// Batch insert issues
-		for i := 0; i < len(issues); i += batchSize {
-			end := i + batchSize
-			if end > len(issues) {
-				end = len(issues)
-			}
-			batch := issues[i:end]
 
-			_, _, err = s.serviceRole.From("issues").Insert(batch, false, "", "", "").Execute()
-			if err != nil {
-				s.logger.Error("Failed to insert issues batch", zap.Int("batch_start", i), zap.Error(err))
-			}
-		}
-	}
+	// Issues were stored alongside their pages; the summary only supplies totals
+	summary := analysis.Summary()
+	skipped := manager.SkipCounts()
+	summary.Skipped = &skipped
 
 	// Update crawl status to succeeded (total_pages already updated via callback)
 	s.updateCrawlStatus(crawlID, "succeeded", "")
-	update := map[string]interface{}{
-		"total_pages":  finalTotal, // Use the final count from callback
-		"total_issues": len(summary.Issues),
-		"completed_at": time.Now().UTC().Format(time.RFC3339),
-	}
-	_, _, err = s.serviceRole.From("crawls").Update(update, "", "").Eq("id", crawlID).Execute()
+	totalIssues := summary.TotalIssues
+	completedAt := time.Now().UTC()
+	err = s.store.UpdateCrawl(ctx, crawlID, store.CrawlUpdate{
+		TotalPages:  &finalTotal, // Use the final count from callback
+		TotalIssues: &totalIssues,
+		CompletedAt: &completedAt,
+		RobotsTxt:   manager.RobotsTxtFile(),
+	})
 	if err != nil {
 		s.logger.Error("Failed to update crawl stats", zap.Error(err))
 	}
+
+	if s.artifacts != nil {
+		s.saveCrawlArtifacts(ctx, crawlID, projectID, results, summary, manager.GetLinkGraph().GetAllEdges(), config.SaveHTMLDir)
+	}
 }
 
 // updateCrawlStatus updates the status of a crawl
 func (s *Server) updateCrawlStatus(crawlID, status, errorMsg string) {
-	update := map[string]interface{}{
-		"status": status,
-	}
-	if status == "failed" && errorMsg != "" {
-		update["meta"] = map[string]interface{}{
+	update := store.CrawlUpdate{Status: &status}
+	if (status == "failed" || status == "cancelled") && errorMsg != "" {
+		update.Meta = map[string]interface{}{
 			"error": errorMsg,
 		}
 	}
-	if status == "succeeded" || status == "failed" {
-		update["completed_at"] = time.Now().UTC().Format(time.RFC3339)
+	if status == "succeeded" || status == "failed" || status == "cancelled" {
+		completedAt := time.Now().UTC()
+		update.CompletedAt = &completedAt
 	}
 
-	_, _, err := s.serviceRole.From("crawls").Update(update, "", "").Eq("id", crawlID).Execute()
-	if err != nil {
+	if err := s.store.UpdateCrawl(context.Background(), crawlID, update); err != nil {
 		s.logger.Error("Failed to update crawl status", zap.String("crawl_id", crawlID), zap.String("status", status), zap.Error(err))
 	} else {
 		s.logger.Info("Updated crawl status", zap.String("crawl_id", crawlID), zap.String("status", status))
 	}
 }
 
-// verifyProjectAccess checks if user has access to a project
-// Uses service role client to bypass RLS since we've already validated the user's token
+// verifyProjectAccess checks if user owns or is a member of a project
 func (s *Server) verifyProjectAccess(userID, projectID string) (bool, error) {
+	ctx := context.Background()
 	s.logger.Debug("Verifying project access", zap.String("user_id", userID), zap.String("project_id", projectID))
 
-	// First check if user is a project member (using service role to bypass RLS)
-	var members []map[string]interface{}
-	data, _, err := s.serviceRole.From("project_members").
-		Select("*", "", false).
-		Eq("project_id", projectID).
-		Eq("user_id", userID).
-		Execute()
-
+	// First check if user is a project member
+	isMember, err := s.store.IsProjectMember(ctx, projectID, userID)
 	if err != nil {
 		s.logger.Error("Failed to query project_members", zap.Error(err))
 		return false, err
 	}
-
-	// Parse data into members slice
-	if err := json.Unmarshal(data, &members); err != nil {
-		s.logger.Error("Failed to parse project_members data", zap.Error(err))
-		return false, err
-	}
-
-	s.logger.Debug("Project members check", zap.Int("member_count", len(members)))
-
-	if len(members) > 0 {
+	if isMember {
 		return true, nil
 	}
 
-	// If not a member, check if user is the project owner (using service role to bypass RLS)
-	var projects []map[string]interface{}
-	projectData, _, err := s.serviceRole.From("projects").
-		Select("owner_id", "", false).
-		Eq("id", projectID).
-		Execute()
-
+	// If not a member, check if user is the project owner
+	project, err := s.store.GetProject(ctx, projectID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.logger.Debug("Access denied: project not found", zap.String("project_id", projectID))
+		return false, nil
+	}
 	if err != nil {
 		s.logger.Error("Failed to query projects", zap.Error(err))
 		return false, err
 	}
 
-	// Parse data into projects slice
-	if err := json.Unmarshal(projectData, &projects); err != nil {
-		s.logger.Error("Failed to parse projects data", zap.Error(err))
-		return false, err
-	}
-
-	s.logger.Debug("Projects check", zap.Int("project_count", len(projects)))
-
-	if len(projects) > 0 {
-		ownerID, ok := projects[0]["owner_id"].(string)
-		s.logger.Debug("Owner check", zap.String("owner_id", ownerID), zap.Bool("type_ok", ok), zap.Bool("matches", ok && ownerID == userID))
-		if ok && ownerID == userID {
-			return true, nil
-		}
+	if project.OwnerID == userID {
+		return true, nil
 	}
 
 	s.logger.Debug("Access denied", zap.String("user_id", userID), zap.String("project_id", projectID))
@@ -1119,6 +1031,30 @@ func (s *Server) handleCrawlByID(w http.ResponseWriter, r *http.Request) {
 				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 			}
 			return
+		case "artifacts":
+			s.handleCrawlArtifacts(w, r, crawlID, strings.Join(parts[2:], "/"))
+			return
+		case "logs":
+			if r.Method == http.MethodGet {
+				s.handleCrawlLogs(w, r, crawlID)
+			} else {
+				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+			return
+		case "canonicals":
+			if r.Method == http.MethodGet {
+				s.handleCrawlCanonicals(w, r, crawlID)
+			} else {
+				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+			return
+		case "cancel":
+			if r.Method == http.MethodPost {
+				s.handleCancelCrawl(w, r, crawlID, userID)
+			} else {
+				s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+			}
+			return
 		default:
 			s.respondError(w, http.StatusNotFound, fmt.Sprintf("Resource not found: %s", resource))
 			return
@@ -1138,285 +1074,160 @@ func (s *Server) handleCrawlByID(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleGetCrawl(w http.ResponseWriter, r *http.Request, crawlID string) {
 	s.logger.Info("Fetching crawl", zap.String("crawl_id", crawlID))
 
-	// Get crawl data using service role to ensure we get the latest updates
-	var crawls []map[string]interface{}
-	data, _, err := s.serviceRole.From("crawls").Select("*", "", false).Eq("id", crawlID).Execute()
+	crawl, err := s.store.GetCrawl(r.Context(), crawlID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.logger.Warn("Crawl not found in database", zap.String("crawl_id", crawlID))
+		s.respondError(w, http.StatusNotFound, "Crawl not found")
+		return
+	}
 	if err != nil {
 		s.logger.Error("Failed to query crawl from database", zap.String("crawl_id", crawlID), zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to get crawl")
 		return
 	}
-
-	s.logger.Info("Crawl query executed", zap.String("crawl_id", crawlID), zap.Int("data_length", len(data)))
-
-	if err := json.Unmarshal(data, &crawls); err != nil {
-		s.logger.Error("Failed to parse crawl data", zap.String("crawl_id", crawlID), zap.Error(err), zap.String("raw_data", string(data)))
-		s.respondError(w, http.StatusInternalServerError, "Failed to parse crawl")
-		return
-	}
-
-	if len(crawls) == 0 {
-		s.logger.Warn("Crawl not found in database", zap.String("crawl_id", crawlID), zap.String("raw_response", string(data)))
-		s.respondError(w, http.StatusNotFound, "Crawl not found")
-		return
-	}
-
-	statusStr := "unknown"
-	if status, ok := crawls[0]["status"].(string); ok {
-		statusStr = status
-	}
-	s.logger.Debug("Found crawl", zap.String("crawl_id", crawlID), zap.String("status", statusStr))
-
-	crawl := crawls[0]
-	originalTotalPages := 0
-	if tp, ok := crawl["total_pages"]; ok {
-		switch v := tp.(type) {
-		case float64:
-			originalTotalPages = int(v)
-		case int:
-			originalTotalPages = v
-		case int32:
-			originalTotalPages = int(v)
-		case int64:
-			originalTotalPages = int(v)
-		case string:
-			if parsed, err := strconv.Atoi(v); err == nil {
-				originalTotalPages = parsed
-			}
-		}
-	}
+	s.logger.Debug("Found crawl", zap.String("crawl_id", crawlID), zap.String("status", crawl.Status))
 
 	// Get real-time page count directly from pages table (more accurate than total_pages field)
-	var pages []map[string]interface{}
-	pageData, _, err := s.serviceRole.From("pages").
-		Select("id", "", false).
-		Eq("crawl_id", crawlID).
-		Execute()
-	pagesCount := 0
-	if err == nil {
-		if err := json.Unmarshal(pageData, &pages); err == nil {
-			// Update total_pages with actual count from database
-			pagesCount = len(pages)
-		}
+	pagesCount, err := s.store.CountPages(r.Context(), crawlID)
+	if err != nil {
+		s.logger.Warn("Failed to count crawl pages", zap.String("crawl_id", crawlID), zap.Error(err))
 	}
 
 	// Use whichever count is greater so we preserve in-memory progress updates while crawl is running
-	effectiveCount := originalTotalPages
+	effectiveCount := crawl.TotalPages
 	if pagesCount > effectiveCount {
 		effectiveCount = pagesCount
 	}
 
-	// total_pages in the crawl row already reflects streaming updates; keep it
-	crawl["page_count"] = effectiveCount
-	crawl["indexed_pages"] = pagesCount
-
-	// Ensure meta field is properly structured and includes max_pages for progress calculation
-	if meta, ok := crawl["meta"].(map[string]interface{}); ok {
-		// Meta exists, ensure max_pages is accessible
-		if maxPages, hasMaxPages := meta["max_pages"]; hasMaxPages {
-			// Add max_pages at top level for easier access
-			crawl["max_pages"] = maxPages
+	// The rollup lists every page, so it is left out of the progress polls
+	// made while a crawl runs
+	var indexability *models.IndexabilityCounts
+	if crawl.Status != "pending" && crawl.Status != "running" {
+		pages, err := s.store.ListPages(r.Context(), crawlID)
+		if err == nil {
+			indexability, err = s.crawlIndexability(r.Context(), crawlID, pages)
 		}
-	} else {
-		// Meta might be stored as JSON string, try to parse it
-		if metaStr, ok := crawl["meta"].(string); ok && metaStr != "" {
-			var meta map[string]interface{}
-			if err := json.Unmarshal([]byte(metaStr), &meta); err == nil {
-				crawl["meta"] = meta
-				if maxPages, hasMaxPages := meta["max_pages"]; hasMaxPages {
-					crawl["max_pages"] = maxPages
-				}
-			}
+		if err != nil {
+			s.logger.Warn("Failed to compute crawl indexability", zap.String("crawl_id", crawlID), zap.Error(err))
 		}
 	}
 
-	s.respondJSON(w, http.StatusOK, crawl)
+	// total_pages in the crawl row already reflects streaming updates; keep it.
+	// max_pages is copied from meta to the top level for progress calculation.
+	s.respondJSON(w, http.StatusOK, CrawlResponse{
+		Crawl:        crawl,
+		PageCount:    effectiveCount,
+		IndexedPages: pagesCount,
+		MaxPages:     crawl.Meta["max_pages"],
+		Indexability: indexability,
+	})
+}
+
+// crawlIndexability tallies the indexability of a crawl's stored pages. URLs
+// its log records as disallowed by robots.txt count as blocked; crawls
+// ingested from the CLI have no log, so they report none.
+func (s *Server) crawlIndexability(ctx context.Context, crawlID string, pages []*store.Page) (*models.IndexabilityCounts, error) {
+	counts := &models.IndexabilityCounts{}
+	for _, page := range pages {
+		counts.Add(page.Result())
+	}
+	blocked, err := s.store.ListCrawlLogs(ctx, crawlID, models.CrawlEventFilter{Event: models.EventSkipped, Reason: models.SkipRobots})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list robots.txt skips: %w", err)
+	}
+	counts.Blocked = len(blocked)
+	return counts, nil
 }
 
 // handleCrawlGraph handles GET /api/v1/crawls/:id/graph - returns link graph data
 func (s *Server) handleCrawlGraph(w http.ResponseWriter, r *http.Request, crawlID string) {
 	s.logger.Info("Fetching link graph", zap.String("crawl_id", crawlID))
-	
-	// Fetch all pages for this crawl using service role to ensure access
-	// Select all fields to ensure we get the data field properly
-	var pages []map[string]interface{}
-	data, _, err := s.serviceRole.From("pages").Select("*", "", false).Eq("crawl_id", crawlID).Execute()
+
+	pages, err := s.store.ListPages(r.Context(), crawlID)
 	if err != nil {
 		s.logger.Error("Failed to fetch pages for graph", zap.String("crawl_id", crawlID), zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to fetch pages")
 		return
 	}
 
-	if err := json.Unmarshal(data, &pages); err != nil {
-		s.logger.Error("Failed to parse pages data", zap.String("crawl_id", crawlID), zap.Error(err), zap.String("raw_data_preview", string(data[:min(200, len(data))])))
-		s.respondError(w, http.StatusInternalServerError, "Failed to parse pages")
-		return
-	}
-
-	s.logger.Info("Fetched pages for graph", zap.String("crawl_id", crawlID), zap.Int("page_count", len(pages)))
-	
-	// Log raw data structure for first page if available
-	if len(pages) > 0 {
-		firstPageRaw, _ := json.Marshal(pages[0])
-		s.logger.Info("First page raw data", zap.String("crawl_id", crawlID), zap.String("first_page_json", string(firstPageRaw)))
-	}
-
 	// Build graph structure: map[sourceURL][]targetURL
 	graph := make(map[string][]string)
-	pagesWithLinks := 0
 	totalLinks := 0
 
-	for i, page := range pages {
-		url, ok := page["url"].(string)
-		if !ok {
-			continue
-		}
-
-		// Log first page's data structure for debugging
-		if i == 0 {
-			s.logger.Info("Sample page data structure", 
-				zap.String("url", url),
-				zap.Any("data_type", fmt.Sprintf("%T", page["data"])),
-				zap.Any("data_value", page["data"]))
-		}
-
-		// Handle data field - it might be a map or a JSON string
-		var dataField map[string]interface{}
-		switch v := page["data"].(type) {
-		case map[string]interface{}:
-			dataField = v
-		case string:
-			// Try to unmarshal if it's a JSON string
-			if err := json.Unmarshal([]byte(v), &dataField); err != nil {
-				s.logger.Debug("Failed to parse data field as JSON string", zap.String("url", url), zap.Error(err))
-				continue
-			}
-		case nil:
-			// Data field is nil, skip this page
-			s.logger.Debug("Page has nil data field", zap.String("url", url))
-			continue
-		default:
-			// Try to marshal and unmarshal to handle other types
-			jsonBytes, err := json.Marshal(v)
-			if err != nil {
-				s.logger.Debug("Failed to marshal data field", zap.String("url", url), zap.Error(err))
-				continue
-			}
-			if err := json.Unmarshal(jsonBytes, &dataField); err != nil {
-				s.logger.Debug("Failed to parse data field", zap.String("url", url), zap.Error(err))
-				continue
-			}
-		}
-
-		if dataField == nil {
-			s.logger.Debug("Data field is nil after parsing", zap.String("url", url))
-			continue
-		}
-
-		// Log first page's parsed data structure
-		if i == 0 {
-			s.logger.Info("Sample parsed data field", 
-				zap.String("url", url),
-				zap.Any("data_field_keys", getMapKeys(dataField)),
-				zap.Any("internal_links", dataField["internal_links"]),
-				zap.Any("external_links", dataField["external_links"]))
-		}
-
-		// Extract internal and external links
+	for _, page := range pages {
 		var allLinks []string
-
-		if internalLinks, ok := dataField["internal_links"].([]interface{}); ok {
-			for _, link := range internalLinks {
-				if linkStr, ok := link.(string); ok {
-					allLinks = append(allLinks, linkStr)
-				}
-			}
-		}
-
-		if externalLinks, ok := dataField["external_links"].([]interface{}); ok {
-			for _, link := range externalLinks {
-				if linkStr, ok := link.(string); ok {
-					allLinks = append(allLinks, linkStr)
-				}
-			}
-		}
+		allLinks = append(allLinks, page.Data.InternalLinks...)
+		allLinks = append(allLinks, page.Data.ExternalLinks...)
 
 		if len(allLinks) > 0 {
-			graph[url] = allLinks
-			pagesWithLinks++
+			graph[page.URL] = allLinks
 			totalLinks += len(allLinks)
-		} else if i < 3 {
-			// Log first few pages with no links for debugging
-			s.logger.Debug("Page has no links", 
-				zap.String("url", url),
-				zap.Any("has_internal_links", dataField["internal_links"] != nil),
-				zap.Any("has_external_links", dataField["external_links"] != nil))
 		}
 	}
 
-	s.logger.Info("Built link graph", 
-		zap.String("crawl_id", crawlID), 
-		zap.Int("pages_with_links", pagesWithLinks),
+	s.logger.Info("Built link graph",
+		zap.String("crawl_id", crawlID),
+		zap.Int("pages_with_links", len(graph)),
 		zap.Int("total_links", totalLinks),
-		zap.Int("graph_size", len(graph)),
 		zap.Int("total_pages_processed", len(pages)))
 
 	// If we have pages but no links, log a warning
 	if len(pages) > 0 && len(graph) == 0 {
-		firstPageURL := "unknown"
-		if len(pages) > 0 {
-			if url, ok := pages[0]["url"].(string); ok {
-				firstPageURL = url
-			}
-		}
-		s.logger.Warn("No links found in pages", 
+		s.logger.Warn("No links found in pages",
 			zap.String("crawl_id", crawlID),
 			zap.Int("total_pages", len(pages)),
-			zap.String("first_page_url", firstPageURL))
+			zap.String("first_page_url", pages[0].URL))
 	}
 
-	s.respondJSON(w, http.StatusOK, graph)
+	indexability, err := s.crawlIndexability(r.Context(), crawlID, pages)
+	if err != nil {
+		s.logger.Error("Failed to compute crawl indexability", zap.String("crawl_id", crawlID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to compute indexability")
+		return
+	}
+
+	s.respondJSON(w, http.StatusOK, CrawlGraphResponse{Graph: graph, Indexability: indexability})
 }
 
-// getMapKeys returns the keys of a map for logging purposes
-func getMapKeys(m map[string]interface{}) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
+// handleCrawlCanonicals handles GET /api/v1/crawls/:id/canonicals - the
+// crawl's pages grouped by the canonical they point to
+func (s *Server) handleCrawlCanonicals(w http.ResponseWriter, r *http.Request, crawlID string) {
+	pages, err := s.store.ListPages(r.Context(), crawlID)
+	if err != nil {
+		s.logger.Error("Failed to fetch pages for canonical clusters", zap.String("crawl_id", crawlID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to fetch pages")
+		return
 	}
-	return keys
+
+	results := make([]*models.PageResult, 0, len(pages))
+	for _, page := range pages {
+		results = append(results, page.Result())
+	}
+	clusters := analyzer.CanonicalClusters(results)
+	s.respondJSON(w, http.StatusOK, CanonicalClustersResponse{
+		Clusters: clusters,
+		Count:    len(clusters),
+	})
 }
 
 // verifyCrawlAccess checks if user has access to a crawl (via project membership)
 func (s *Server) verifyCrawlAccess(userID, crawlID string) (bool, error) {
 	// Get the crawl's project_id
-	var crawls []map[string]interface{}
-	data, _, err := s.serviceRole.From("crawls").Select("project_id", "", false).Eq("id", crawlID).Execute()
+	crawl, err := s.store.GetCrawl(context.Background(), crawlID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.logger.Warn("Crawl not found during access verification", zap.String("crawl_id", crawlID), zap.String("user_id", userID))
+		return false, fmt.Errorf("crawl not found: %s", crawlID)
+	}
 	if err != nil {
 		s.logger.Error("Failed to query crawl for access verification", zap.String("crawl_id", crawlID), zap.Error(err))
 		return false, err
 	}
 
-	if err := json.Unmarshal(data, &crawls); err != nil {
-		s.logger.Error("Failed to parse crawl data for access verification", zap.String("crawl_id", crawlID), zap.Error(err))
-		return false, err
-	}
-
-	if len(crawls) == 0 {
-		s.logger.Warn("Crawl not found during access verification", zap.String("crawl_id", crawlID), zap.String("user_id", userID))
-		return false, fmt.Errorf("crawl not found: %s", crawlID)
-	}
-
-	projectID, ok := crawls[0]["project_id"].(string)
-	if !ok {
+	if crawl.ProjectID == "" {
 		s.logger.Warn("Crawl missing project_id", zap.String("crawl_id", crawlID))
 		return false, fmt.Errorf("crawl missing project_id: %s", crawlID)
 	}
 
 	// Verify user has access to the project
-	hasAccess, err := s.verifyProjectAccess(userID, projectID)
-	if err != nil {
-		return false, err
-	}
-	return hasAccess, nil
+	return s.verifyProjectAccess(userID, crawl.ProjectID)
 }
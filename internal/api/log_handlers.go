@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/dillonlara115/barracuda/internal/store"
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// crawlLogBatchSize is how many events a server-side crawl buffers before
+	// storing them
+	crawlLogBatchSize = 200
+	// defaultCrawlLogLimit and maxCrawlLogLimit bound the entries one request
+	// for a crawl's log returns
+	defaultCrawlLogLimit = 1000
+	maxCrawlLogLimit     = 10000
+)
+
+// handleCrawlLogs handles GET /api/v1/crawls/:id/logs. The caller has
+// checked the user's access to the crawl.
+func (s *Server) handleCrawlLogs(w http.ResponseWriter, r *http.Request, crawlID string) {
+	query := r.URL.Query()
+	filter := models.CrawlEventFilter{
+		Event:  query.Get("event"),
+		Reason: query.Get("reason"),
+		URL:    query.Get("url"),
+		Limit:  defaultCrawlLogLimit,
+	}
+	if v := query.Get("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 && parsed <= maxCrawlLogLimit {
+			filter.Limit = parsed
+		}
+	}
+
+	logs, err := s.store.ListCrawlLogs(r.Context(), crawlID, filter)
+	if err != nil {
+		s.logger.Error("Failed to list crawl logs", zap.String("crawl_id", crawlID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to list crawl logs")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, ListCrawlLogsResponse{
+		Logs:  logs,
+		Count: len(logs),
+	})
+}
+
+// crawlLogRecorder stores the events of a server-side crawl in batches
+type crawlLogRecorder struct {
+	server  *Server
+	ctx     context.Context
+	crawlID string
+
+	mu     sync.Mutex
+	events []models.CrawlEvent
+}
+
+// Record buffers an event, storing the buffer when it is full. It is the
+// crawl manager's event hook.
+func (r *crawlLogRecorder) Record(event models.CrawlEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	if len(r.events) >= crawlLogBatchSize {
+		r.flushLocked()
+	}
+}
+
+// Flush stores the buffered events
+func (r *crawlLogRecorder) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.flushLocked()
+}
+
+func (r *crawlLogRecorder) flushLocked() {
+	if len(r.events) == 0 {
+		return
+	}
+	if err := r.server.store.SaveCrawlLogs(r.ctx, store.NewCrawlLogs(r.crawlID, r.events)); err != nil {
+		r.server.logger.Warn("Failed to store crawl logs", zap.String("crawl_id", r.crawlID), zap.Int("events", len(r.events)), zap.Error(err))
+	}
+	r.events = r.events[:0]
+}
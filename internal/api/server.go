@@ -1,6 +1,7 @@
 package api
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,11 +10,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dillonlara115/barracuda/internal/entitlements"
 	"github.com/dillonlara115/barracuda/internal/gsc"
+	"github.com/dillonlara115/barracuda/internal/store"
 	"github.com/supabase-community/supabase-go"
 	"go.uber.org/zap"
 )
 
+// localUserID is the user every request acts as when the server is self-hosted
+// without Supabase Auth
+const localUserID = "local"
+
 // Config holds API server configuration
 type Config struct {
 	SupabaseURL        string
@@ -21,19 +28,64 @@ type Config struct {
 	SupabaseAnonKey    string
 	CronSyncSecret     string
 	Logger             *zap.Logger
+
+	// Store holds projects, crawls, pages, and issues. It defaults to the
+	// Supabase database when SupabaseURL is set.
+	Store store.Store
+
+	// AuthToken is the bearer token clients must send when running without
+	// Supabase. Search Console and billing are unavailable in that mode.
+	AuthToken string
+
+	// Artifacts holds raw exports and HTML snapshots of crawls. It defaults
+	// to Supabase Storage when SupabaseURL is set; without it the artifact
+	// endpoints are unavailable.
+	Artifacts store.ArtifactStore
+
+	// RequireDomainVerification only lets a project crawl from the server
+	// once its domain is verified, so the crawler can't be pointed at
+	// other people's sites
+	RequireDomainVerification bool
+
+	// Entitlements decides what each subscription tier may do. It defaults
+	// to entitlements.Defaults(); self-hosted servers allow everything.
+	Entitlements entitlements.Table
 }
 
 // Server represents the API server
 type Server struct {
 	config      Config
-	supabase    *supabase.Client
-	serviceRole *supabase.Client
+	store       store.Store
+	artifacts   store.ArtifactStore // nil when not configured
+	supabase    *supabase.Client // nil when self-hosted
+	serviceRole *supabase.Client // nil when self-hosted
 	logger      *zap.Logger
 	cronSecret  string
+	running     runningCrawls // Crawls started by runCrawlAsync, for cancelling
 }
 
 // NewServer creates a new API server instance
 func NewServer(cfg Config) (*Server, error) {
+	if cfg.Entitlements == nil {
+		cfg.Entitlements = entitlements.Defaults()
+	}
+
+	if cfg.SupabaseURL == "" {
+		if cfg.Store == nil {
+			return nil, fmt.Errorf("a store is required when Supabase is not configured")
+		}
+		if cfg.AuthToken == "" {
+			return nil, fmt.Errorf("an auth token is required when Supabase is not configured")
+		}
+		return &Server{
+			config:     cfg,
+			store:      cfg.Store,
+			artifacts:  cfg.Artifacts,
+			logger:     cfg.Logger,
+			cronSecret: cfg.CronSyncSecret,
+		}, nil
+	}
+
 	// Create Supabase client with anon key (for RLS-protected queries)
 	supabaseClient, err := supabase.NewClient(cfg.SupabaseURL, cfg.SupabaseAnonKey, nil)
 	if err != nil {
@@ -46,8 +98,23 @@ func NewServer(cfg Config) (*Server, error) {
 		return nil, fmt.Errorf("failed to create Supabase service role client: %w", err)
 	}
 
+	dataStore := cfg.Store
+	if dataStore == nil {
+		dataStore, err = store.NewSupabaseStore(cfg.SupabaseURL, cfg.SupabaseServiceKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	artifacts := cfg.Artifacts
+	if artifacts == nil {
+		artifacts = store.NewSupabaseArtifacts(cfg.SupabaseURL, cfg.SupabaseServiceKey)
+	}
+
 	return &Server{
 		config:      cfg,
+		store:       dataStore,
+		artifacts:   artifacts,
 		supabase:    supabaseClient,
 		serviceRole: serviceRoleClient,
 		logger:      cfg.Logger,
@@ -55,13 +122,46 @@ func NewServer(cfg Config) (*Server, error) {
 	}, nil
 }
 
+// hasSupabase reports whether Supabase-only features (Search Console and
+// billing) are available
+func (s *Server) hasSupabase() bool {
+	return s.serviceRole != nil
+}
+
 // Router returns the HTTP router with all routes configured
 func (s *Server) Router() http.Handler {
 	mux := http.NewServeMux()
 
 	// Health check (no auth required)
 	mux.HandleFunc("/health", s.handleHealth)
+	// Internal cron endpoint for the nightly trend aggregation (protected via shared secret)
+	mux.HandleFunc("/api/internal/trends/aggregate", s.handleAggregateTrends)
+
+	if s.hasSupabase() {
+		s.registerSupabaseRoutes(mux)
+	}
+
+	// API v1 routes
+	v1 := http.NewServeMux()
+	v1.HandleFunc("/crawls", s.handleCrawls)
+	v1.HandleFunc("/crawls/", s.handleCrawlByID)
+	v1.HandleFunc("/projects", s.handleProjects)
+	v1.HandleFunc("/projects/", s.handleProjectByID)
+	v1.HandleFunc("/exports", s.handleExports)
+	v1.HandleFunc("/admin/", s.handleAdmin)
+	if s.hasSupabase() {
+		v1.HandleFunc("/billing/", s.handleBilling)
+	}
+
+	// Wrap v1 routes with authentication middleware
+	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", s.authMiddleware(v1)))
+
+	return s.corsMiddleware(s.loggingMiddleware(mux))
+}
 
+// registerSupabaseRoutes adds the Search Console and Stripe routes, which keep
+// their data in Supabase
+func (s *Server) registerSupabaseRoutes(mux *http.ServeMux) {
 	// Initialize GSC OAuth (non-blocking - will fail gracefully if credentials not set)
 	// Use the API port for redirect URL
 	apiPort := os.Getenv("PORT")
@@ -90,23 +190,10 @@ func (s *Server) Router() http.Handler {
 
 	// Stripe webhook (no auth required - verified by signature)
 	mux.HandleFunc("/api/stripe/webhook", s.handleStripeWebhook)
-
-	// API v1 routes
-	v1 := http.NewServeMux()
-	v1.HandleFunc("/crawls", s.handleCrawls)
-	v1.HandleFunc("/crawls/", s.handleCrawlByID)
-	v1.HandleFunc("/projects", s.handleProjects)
-	v1.HandleFunc("/projects/", s.handleProjectByID)
-	v1.HandleFunc("/exports", s.handleExports)
-	v1.HandleFunc("/billing/", s.handleBilling)
-
-	// Wrap v1 routes with authentication middleware
-	mux.Handle("/api/v1/", http.StripPrefix("/api/v1", s.authMiddleware(v1)))
-
-	return s.corsMiddleware(s.loggingMiddleware(mux))
 }
 
-// authMiddleware validates Supabase JWT tokens
+// authMiddleware validates Supabase JWT tokens, or the configured API token
+// when self-hosted
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Extract token from Authorization header
@@ -138,14 +225,25 @@ func (s *Server) authMiddleware(next http.Handler) http.Handler {
 		// Add user info to request context
 		ctx := r.Context()
 		ctx = contextWithUserID(ctx, user.ID)
+		ctx = contextWithAdmin(ctx, user.IsAdmin())
 		r = r.WithContext(ctx)
 
 		next.ServeHTTP(w, r)
 	})
 }
 
-// validateToken validates a Supabase JWT token and returns user info
+// validateToken validates a bearer token and returns user info
 func (s *Server) validateToken(token string) (*User, error) {
+	if !s.hasSupabase() {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.AuthToken)) != 1 {
+			return nil, fmt.Errorf("invalid API token")
+		}
+		// Whoever holds the token runs the server
+		user := &User{ID: localUserID}
+		user.AppMetadata.Role = adminRole
+		return user, nil
+	}
+
 	// Validate token via Supabase Auth API
 	// In production, you might want to verify JWT signature locally for better performance
 	return s.validateTokenViaAPI(token)
@@ -246,9 +344,7 @@ func (rw *responseWriter) WriteHeader(code int) {
 func (s *Server) respondError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{
-		"error": message,
-	})
+	json.NewEncoder(w).Encode(ErrorResponse{Error: message})
 }
 
 // respondJSON sends a JSON response
@@ -264,4 +360,18 @@ func (s *Server) respondJSON(w http.ResponseWriter, status int, data interface{}
 type User struct {
 	ID    string `json:"id"`
 	Email string `json:"email"`
+
+	// AppMetadata is only writable with the service role key, so users
+	// can't grant themselves its role
+	AppMetadata struct {
+		Role string `json:"role"`
+	} `json:"app_metadata"`
+}
+
+// adminRole is the app_metadata role of users allowed the admin API
+const adminRole = "admin"
+
+// IsAdmin reports whether the user may use the admin API
+func (u *User) IsAdmin() bool {
+	return u.AppMetadata.Role == adminRole
 }
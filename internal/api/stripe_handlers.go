@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dillonlara115/barracuda/internal/entitlements"
 	"github.com/stripe/stripe-go/v78"
 	"github.com/stripe/stripe-go/v78/checkout/session"
 	"github.com/stripe/stripe-go/v78/customer"
@@ -54,6 +56,7 @@ func GetStripeConfig() StripeConfig {
 type CreateCheckoutSessionRequest struct {
 	PriceID string `json:"price_id"` // Stripe price ID (e.g., "price_xxxxx")
 	Quantity int   `json:"quantity,omitempty"` // For team seats, default 1
+	PromotionCode string `json:"promotion_code,omitempty"` // Code to apply, as the customer types it; without one, checkout asks for a code
 }
 
 // CreateCheckoutSessionResponse represents the checkout session response
@@ -62,9 +65,25 @@ type CreateCheckoutSessionResponse struct {
 	URL       string `json:"url"`
 }
 
+// Billing portal flows of CreatePortalSessionRequest.Flow, which open the
+// portal on one action instead of its home page
+const (
+	PortalFlowPaymentMethodUpdate = "payment_method_update"
+	PortalFlowSubscriptionCancel  = "subscription_cancel"
+	PortalFlowSubscriptionUpdate  = "subscription_update" // With PriceID, confirms the switch to that price
+)
+
+// CreatePortalSessionRequest is the optional body of a billing portal
+// session request
+type CreatePortalSessionRequest struct {
+	Flow    string `json:"flow,omitempty"`     // A PortalFlow constant; "" opens the portal home
+	PriceID string `json:"price_id,omitempty"` // Plan to switch to with PortalFlowSubscriptionUpdate
+}
+
 type BillingSummaryResponse struct {
-	Profile      map[string]interface{} `json:"profile"`
-	Subscription map[string]interface{} `json:"subscription"`
+	Profile      map[string]interface{}    `json:"profile"`
+	Subscription map[string]interface{}    `json:"subscription"`
+	Entitlements entitlements.Entitlements `json:"entitlements"` // What the profile's tier allows
 }
 
 // handleBilling routes billing sub-paths
@@ -77,6 +96,8 @@ func (s *Server) handleBilling(w http.ResponseWriter, r *http.Request) {
 	switch path {
 	case "summary":
 		s.handleBillingSummary(w, r)
+	case "plans":
+		s.handleBillingPlans(w, r)
 	case "checkout":
 		s.handleCreateCheckoutSession(w, r)
 	case "portal":
@@ -137,9 +158,11 @@ func (s *Server) handleBillingSummary(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	tier, _ := profile["subscription_tier"].(string)
 	s.respondJSON(w, http.StatusOK, BillingSummaryResponse{
 		Profile:      profile,
 		Subscription: subscription,
+		Entitlements: s.config.Entitlements.For(tier),
 	})
 }
 
@@ -178,6 +201,32 @@ func (s *Server) handleCreateCheckoutSession(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Only the plans read from Stripe can be bought
+	plan, err := findPlan(req.PriceID)
+	if err != nil {
+		s.logger.Error("Failed to load billing plans", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to load billing plans")
+		return
+	}
+	if plan == nil {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Unknown plan price: %q", req.PriceID))
+		return
+	}
+
+	promotionCodeID := ""
+	if code := strings.TrimSpace(req.PromotionCode); code != "" {
+		promotionCodeID, err = findPromotionCode(code)
+		if err != nil {
+			s.logger.Error("Failed to look up promotion code", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to look up promotion code")
+			return
+		}
+		if promotionCodeID == "" {
+			s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Promotion code %q is not valid", code))
+			return
+		}
+	}
+
 	// Get user profile to check for existing Stripe customer ID
 	// Create profile if it doesn't exist
 	var profiles []map[string]interface{}
@@ -300,8 +349,23 @@ func (s *Server) handleCreateCheckoutSession(w http.ResponseWriter, r *http.Requ
 			"user_id": userID,
 		},
 	}
+	// Stripe takes either a code applied up front or a field to enter one
+	if promotionCodeID != "" {
+		checkoutParams.Discounts = []*stripe.CheckoutSessionDiscountParams{
+			{PromotionCode: stripe.String(promotionCodeID)},
+		}
+	} else {
+		checkoutParams.AllowPromotionCodes = stripe.Bool(true)
+	}
 
 	sess, err := session.New(checkoutParams)
+	var stripeErr *stripe.Error
+	if promotionCodeID != "" && errors.As(err, &stripeErr) && stripeErr.Type == stripe.ErrorTypeInvalidRequest {
+		// Codes limited to first-time customers or minimum amounts are
+		// only refused here
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Promotion code %q can't be applied: %s", req.PromotionCode, stripeErr.Msg))
+		return
+	}
 	if err != nil {
 		s.logger.Error("Failed to create checkout session", zap.Error(err))
 		s.respondError(w, http.StatusInternalServerError, "Failed to create checkout session")
@@ -353,40 +417,44 @@ func (s *Server) handleStripeWebhook(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Handle the event
+	if err := s.processStripeEvent(&event); err != nil {
+		s.logger.Error("Error parsing webhook event", zap.String("type", string(event.Type)), zap.Error(err))
+		s.respondError(w, http.StatusBadRequest, "Error parsing webhook data")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// processStripeEvent applies a Stripe event to profiles and subscriptions,
+// whether it arrived by webhook or an admin replays it
+func (s *Server) processStripeEvent(event *stripe.Event) error {
 	switch event.Type {
 	case "checkout.session.completed":
 		var checkoutSession stripe.CheckoutSession
 		if err := json.Unmarshal(event.Data.Raw, &checkoutSession); err != nil {
-			s.logger.Error("Error parsing checkout.session.completed", zap.Error(err))
-			s.respondError(w, http.StatusBadRequest, "Error parsing webhook data")
-			return
+			return fmt.Errorf("failed to parse checkout session: %w", err)
 		}
 		s.handleCheckoutSessionCompleted(&checkoutSession)
 
 	case "customer.subscription.created", "customer.subscription.updated":
 		var subscription stripe.Subscription
 		if err := json.Unmarshal(event.Data.Raw, &subscription); err != nil {
-			s.logger.Error("Error parsing subscription event", zap.Error(err))
-			s.respondError(w, http.StatusBadRequest, "Error parsing webhook data")
-			return
+			return fmt.Errorf("failed to parse subscription: %w", err)
 		}
 		s.handleSubscriptionUpdate(&subscription)
 
 	case "customer.subscription.deleted":
 		var subscription stripe.Subscription
 		if err := json.Unmarshal(event.Data.Raw, &subscription); err != nil {
-			s.logger.Error("Error parsing subscription.deleted", zap.Error(err))
-			s.respondError(w, http.StatusBadRequest, "Error parsing webhook data")
-			return
+			return fmt.Errorf("failed to parse subscription: %w", err)
 		}
 		s.handleSubscriptionDeleted(&subscription)
 
 	default:
 		s.logger.Info("Unhandled event type", zap.String("type", string(event.Type)))
 	}
-
-	w.WriteHeader(http.StatusOK)
+	return nil
 }
 
 // handleCheckoutSessionCompleted processes a completed checkout session
@@ -436,16 +504,10 @@ func (s *Server) handleSubscriptionUpdate(sub *stripe.Subscription) {
 		return
 	}
 
-	// Determine tier based on price ID
+	// Determine tier based on price
 	tier := "free"
-	stripeConfig := GetStripeConfig()
-	if len(sub.Items.Data) > 0 && sub.Items.Data[0].Price != nil {
-		priceID := sub.Items.Data[0].Price.ID
-		if priceID == stripeConfig.PriceIDPro || priceID == stripeConfig.PriceIDProAnnual {
-			tier = "pro"
-		} else if priceID == stripeConfig.PriceIDTeamSeat {
-			tier = "team"
-		}
+	if len(sub.Items.Data) > 0 {
+		tier = tierForPrice(sub.Items.Data[0].Price, GetStripeConfig())
 	}
 
 	// Calculate quantity (team size)
@@ -591,10 +653,27 @@ func (s *Server) handleCreateBillingPortalSession(w http.ResponseWriter, r *http
 		return
 	}
 
-	// Get user's Stripe customer ID
+	// The body is optional; without one the session opens the portal home
+	var req CreatePortalSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		s.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	switch req.Flow {
+	case "", PortalFlowPaymentMethodUpdate, PortalFlowSubscriptionCancel, PortalFlowSubscriptionUpdate:
+	default:
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Unknown billing portal flow: %q", req.Flow))
+		return
+	}
+	if req.PriceID != "" && req.Flow != PortalFlowSubscriptionUpdate {
+		s.respondError(w, http.StatusBadRequest, "price_id is only used with the subscription_update flow")
+		return
+	}
+
+	// Get user's Stripe customer and subscription IDs
 	var profiles []map[string]interface{}
 	data, _, err := s.serviceRole.From("profiles").
-		Select("stripe_customer_id", "", false).
+		Select("stripe_customer_id, stripe_subscription_id", "", false).
 		Eq("id", userID).
 		Execute()
 	
@@ -608,6 +687,7 @@ func (s *Server) handleCreateBillingPortalSession(w http.ResponseWriter, r *http
 		return
 	}
 	
+	subscriptionID, _ := profiles[0]["stripe_subscription_id"].(string)
 	customerID, ok := profiles[0]["stripe_customer_id"].(string)
 	if !ok || customerID == "" {
 		// Attempt to fall back to latest subscription
@@ -619,6 +699,9 @@ func (s *Server) handleCreateBillingPortalSession(w http.ResponseWriter, r *http
 		}
 
 		if subscription != nil {
+			if val, ok := subscription["stripe_subscription_id"].(string); ok && subscriptionID == "" {
+				subscriptionID = val
+			}
 			if val, ok := subscription["stripe_customer_id"].(string); ok && val != "" {
 				customerID = val
 				// Persist the customer ID on the profile for next time
@@ -650,6 +733,15 @@ func (s *Server) handleCreateBillingPortalSession(w http.ResponseWriter, r *http
 		ReturnURL: stripe.String(stripeConfig.SuccessURL),
 	}
 
+	if req.Flow != "" {
+		flowData, status, msg := s.portalFlowData(req, subscriptionID, stripeConfig)
+		if msg != "" {
+			s.respondError(w, status, msg)
+			return
+		}
+		params.FlowData = flowData
+	}
+
 	sess, err := billingportalsession.New(params)
 	if err != nil {
 		s.logger.Error("Failed to create billing portal session", zap.Error(err))
@@ -657,17 +749,76 @@ func (s *Server) handleCreateBillingPortalSession(w http.ResponseWriter, r *http
 		return
 	}
 
-	s.respondJSON(w, http.StatusOK, map[string]string{
-		"url": sess.URL,
-	})
+	s.respondJSON(w, http.StatusOK, PortalSessionResponse{URL: sess.URL})
+}
+
+// portalFlowData builds the flow a billing portal session opens on, or
+// returns the HTTP status and message to respond with when the user can't take
+// that action
+func (s *Server) portalFlowData(req CreatePortalSessionRequest, subscriptionID string, stripeConfig StripeConfig) (*stripe.BillingPortalSessionFlowDataParams, int, string) {
+	flowData := &stripe.BillingPortalSessionFlowDataParams{Type: stripe.String(req.Flow)}
+	if stripeConfig.SuccessURL != "" {
+		flowData.AfterCompletion = &stripe.BillingPortalSessionFlowDataAfterCompletionParams{
+			Type:     stripe.String("redirect"),
+			Redirect: &stripe.BillingPortalSessionFlowDataAfterCompletionRedirectParams{ReturnURL: stripe.String(stripeConfig.SuccessURL)},
+		}
+	}
+	if req.Flow == PortalFlowPaymentMethodUpdate {
+		return flowData, http.StatusOK, ""
+	}
+
+	if subscriptionID == "" {
+		return nil, http.StatusBadRequest, "No active subscription found"
+	}
+	switch {
+	case req.Flow == PortalFlowSubscriptionCancel:
+		flowData.SubscriptionCancel = &stripe.BillingPortalSessionFlowDataSubscriptionCancelParams{
+			Subscription: stripe.String(subscriptionID),
+		}
+	case req.PriceID == "":
+		flowData.SubscriptionUpdate = &stripe.BillingPortalSessionFlowDataSubscriptionUpdateParams{
+			Subscription: stripe.String(subscriptionID),
+		}
+	default:
+		// Only the Pro plans read from Stripe can be switched to
+		plan, err := findPlan(req.PriceID)
+		if err != nil {
+			s.logger.Error("Failed to load billing plans", zap.Error(err))
+			return nil, http.StatusInternalServerError, "Failed to load billing plans"
+		}
+		if plan == nil || plan.Tier != "pro" {
+			return nil, http.StatusBadRequest, fmt.Sprintf("Unknown plan price: %q", req.PriceID)
+		}
+		sub, err := subscription.Get(subscriptionID, nil)
+		if err != nil {
+			s.logger.Error("Failed to get subscription for plan switch", zap.Error(err))
+			return nil, http.StatusInternalServerError, "Failed to load subscription"
+		}
+		if len(sub.Items.Data) == 0 {
+			return nil, http.StatusBadRequest, "Subscription has no plan to switch"
+		}
+		flowData.Type = stripe.String(string(stripe.BillingPortalSessionFlowTypeSubscriptionUpdateConfirm))
+		flowData.SubscriptionUpdateConfirm = &stripe.BillingPortalSessionFlowDataSubscriptionUpdateConfirmParams{
+			Subscription: stripe.String(subscriptionID),
+			Items: []*stripe.BillingPortalSessionFlowDataSubscriptionUpdateConfirmItemParams{{
+				ID:       stripe.String(sub.Items.Data[0].ID),
+				Price:    stripe.String(req.PriceID),
+				Quantity: stripe.Int64(sub.Items.Data[0].Quantity),
+			}},
+		}
+	}
+	return flowData, http.StatusOK, ""
 }
 
 // Helper functions
 
+// profileColumns are the columns of profiles the API reads
+const profileColumns = "id, display_name, subscription_tier, subscription_status, stripe_customer_id, stripe_subscription_id, team_size, subscription_current_period_end, subscription_cancel_at_period_end"
+
 func (s *Server) fetchProfile(userID string) (map[string]interface{}, error) {
 	var profiles []map[string]interface{}
 	data, _, err := s.serviceRole.From("profiles").
-		Select("id, display_name, subscription_tier, subscription_status, stripe_customer_id, stripe_subscription_id, team_size, subscription_current_period_end, subscription_cancel_at_period_end", "", false).
+		Select(profileColumns, "", false).
 		Eq("id", userID).
 		Limit(1, "").
 		Execute()
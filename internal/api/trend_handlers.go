@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/store"
+	"go.uber.org/zap"
+)
+
+const (
+	// trendDayFormat is the layout of IssueTrend.Day
+	trendDayFormat = "2006-01-02"
+	// defaultTrendDays and maxTrendDays bound the days of history one
+	// request for a project's trends, or one aggregation run, covers
+	defaultTrendDays = 90
+	maxTrendDays     = 365
+)
+
+// handleProjectTrends handles GET /api/v1/projects/:id/trends: a project's
+// daily page and issue counts over the last days (?days=, 90 by default)
+func (s *Server) handleProjectTrends(w http.ResponseWriter, r *http.Request, projectID, userID string) {
+	if r.Method != http.MethodGet {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	hasAccess, err := s.verifyProjectAccess(userID, projectID)
+	if err != nil {
+		s.logger.Error("Failed to verify project access", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to verify project access")
+		return
+	}
+	if !hasAccess {
+		s.respondError(w, http.StatusForbidden, "You don't have access to this project")
+		return
+	}
+
+	days := defaultTrendDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 && parsed <= maxTrendDays {
+			days = parsed
+		}
+	}
+	since := time.Now().UTC().AddDate(0, 0, -days).Format(trendDayFormat)
+
+	trends, err := s.store.ListIssueTrends(r.Context(), projectID, since)
+	if err != nil {
+		s.logger.Error("Failed to list issue trends", zap.String("project_id", projectID), zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to list issue trends")
+		return
+	}
+	s.respondJSON(w, http.StatusOK, IssueTrendsResponse{
+		Trends: trends,
+		Count:  len(trends),
+	})
+}
+
+// handleAggregateTrends handles POST /api/internal/trends/aggregate, which a
+// nightly cron job calls with the shared cron secret. It stores each
+// project's counts for the last days (1 by default: yesterday, in UTC).
+func (s *Server) handleAggregateTrends(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if !s.checkCronSecret(w, r) {
+		return
+	}
+
+	var req AggregateTrendsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if req.Days <= 0 {
+		req.Days = 1
+	}
+	if req.Days > maxTrendDays {
+		req.Days = maxTrendDays
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -req.Days)
+	trends, err := s.aggregateIssueTrends(r.Context(), from, to)
+	if err != nil {
+		s.logger.Error("Failed to aggregate issue trends", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to aggregate issue trends")
+		return
+	}
+	s.logger.Info("Aggregated issue trends", zap.Int("days", req.Days), zap.Int("rows", trends))
+	s.respondJSON(w, http.StatusOK, AggregateTrendsResponse{
+		From: from.Format(trendDayFormat),
+		To:   to.AddDate(0, 0, -1).Format(trendDayFormat),
+		Rows: trends,
+	})
+}
+
+// aggregateIssueTrends stores, for each project and day in [from, to), the
+// counts of the last crawl that succeeded that day, and returns how many it
+// stored. Days without a crawl are left out rather than carried forward.
+func (s *Server) aggregateIssueTrends(ctx context.Context, from, to time.Time) (int, error) {
+	crawls, err := s.store.ListCrawls(ctx, store.CrawlFilter{Status: "succeeded"})
+	if err != nil {
+		return 0, err
+	}
+
+	type projectDay struct{ project, day string }
+	latest := make(map[projectDay]*store.Crawl)
+	for _, crawl := range crawls {
+		if crawl.CompletedAt == nil || crawl.CompletedAt.Before(from) || !crawl.CompletedAt.Before(to) {
+			continue
+		}
+		key := projectDay{crawl.ProjectID, crawl.CompletedAt.UTC().Format(trendDayFormat)}
+		if current := latest[key]; current == nil || crawl.CompletedAt.After(*current.CompletedAt) {
+			latest[key] = crawl
+		}
+	}
+
+	trends := make([]*store.IssueTrend, 0, len(latest))
+	for key, crawl := range latest {
+		issues, err := s.store.ListIssues(ctx, crawl.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list issues of crawl %s: %w", crawl.ID, err)
+		}
+		trends = append(trends, store.NewIssueTrend(crawl, key.day, issues))
+	}
+	if len(trends) == 0 {
+		return 0, nil
+	}
+	if err := s.store.SaveIssueTrends(ctx, trends); err != nil {
+		return 0, err
+	}
+	return len(trends), nil
+}
+
+// checkCronSecret reports whether a request to an internal cron endpoint
+// carries the shared cron secret, in the X-Cron-Secret header or the secret
+// query parameter, responding with an error when it doesn't
+func (s *Server) checkCronSecret(w http.ResponseWriter, r *http.Request) bool {
+	if s.cronSecret == "" {
+		s.respondError(w, http.StatusServiceUnavailable, "Cron sync secret not configured")
+		return false
+	}
+
+	secret := r.Header.Get("X-Cron-Secret")
+	if secret == "" {
+		secret = r.URL.Query().Get("secret")
+	}
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(s.cronSecret)) != 1 {
+		s.respondError(w, http.StatusUnauthorized, "Unauthorized")
+		return false
+	}
+	return true
+}
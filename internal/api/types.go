@@ -1,12 +1,21 @@
 package api
 
-import "github.com/dillonlara115/barracuda/pkg/models"
+import (
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/store"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
 
 // CreateCrawlRequest represents a crawl ingestion request
 type CreateCrawlRequest struct {
 	ProjectID string              `json:"project_id"`
 	Pages     []*models.PageResult `json:"pages"`
 	Source    string              `json:"source,omitempty"` // "cli", "web", "schedule"
+	// Metadata is the crawl directory's metadata.json, when the CLI has one
+	Metadata *crawldir.Metadata `json:"metadata,omitempty"`
 }
 
 // CreateCrawlResponse represents the response after creating a crawl
@@ -25,6 +34,32 @@ type CreateProjectRequest struct {
 	Settings map[string]interface{} `json:"settings,omitempty"`
 }
 
+// VerifyDomainRequest asks the API to check a project's domain for its
+// verification token
+type VerifyDomainRequest struct {
+	Method string `json:"method"` // "dns" or "meta"
+}
+
+// DomainVerificationResponse is a project's domain verification state, with
+// the DNS record and meta tag either of which verifies it
+type DomainVerificationResponse struct {
+	Domain     string     `json:"domain"`
+	Required   bool       `json:"required"` // Whether the server only crawls verified domains
+	Verified   bool       `json:"verified"`
+	Method     string     `json:"method,omitempty"` // "dns" or "meta", once verified
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	Token      string     `json:"token"`
+	DNSRecord  DNSRecord  `json:"dns_record"`
+	MetaTag    string     `json:"meta_tag"` // For the <head> of the domain's home page
+}
+
+// DNSRecord is a DNS record to add to a domain
+type DNSRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
 // TriggerCrawlRequest represents a request to trigger a new crawl
 type TriggerCrawlRequest struct {
 	URL          string `json:"url"`           // Starting URL to crawl
@@ -33,5 +68,176 @@ type TriggerCrawlRequest struct {
 	Workers      int    `json:"workers"`       // Number of concurrent workers (default: 10)
 	RespectRobots bool  `json:"respect_robots"` // Respect robots.txt (default: true)
 	ParseSitemap  bool  `json:"parse_sitemap"`  // Parse sitemap.xml (default: false)
+	SaveHTML      bool  `json:"save_html,omitempty"` // Store each page's HTML as a crawl artifact (needs Supabase Storage)
+	Render        bool  `json:"render,omitempty"`    // Render pages in headless Chrome before parsing them (paid plans)
+}
+
+// TriggerCrawlResponse is returned when a triggered crawl has started
+type TriggerCrawlResponse struct {
+	CrawlID string `json:"crawl_id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// CancelCrawlRequest stops a crawl running on the server
+type CancelCrawlRequest struct {
+	Reason string `json:"reason,omitempty"` // Recorded as the crawl's error; defaults to who cancelled it
+}
+
+// CancelCrawlResponse is returned when a running crawl has been told to stop
+type CancelCrawlResponse struct {
+	CrawlID string `json:"crawl_id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// CrawlResponse is a crawl with its live page counts
+type CrawlResponse struct {
+	*store.Crawl
+	PageCount    int         `json:"page_count"`
+	IndexedPages int         `json:"indexed_pages"`
+	MaxPages     interface{} `json:"max_pages,omitempty"`
+	// Indexability rolls up the stored pages once the crawl has finished
+	Indexability *models.IndexabilityCounts `json:"indexability,omitempty"`
 }
 
+// CrawlGraphResponse is a crawl's link graph with its indexability rollup
+type CrawlGraphResponse struct {
+	Graph        map[string][]string        `json:"graph"` // Linked URLs by source URL
+	Indexability *models.IndexabilityCounts `json:"indexability"`
+}
+
+// CanonicalClustersResponse is a crawl's pages grouped by canonical target
+type CanonicalClustersResponse struct {
+	Clusters []analyzer.CanonicalCluster `json:"clusters"`
+	Count    int                         `json:"count"`
+}
+
+// ListCrawlsResponse is a list of crawls
+type ListCrawlsResponse struct {
+	Crawls []*store.Crawl `json:"crawls"`
+	Count  int            `json:"count"`
+}
+
+// ListProjectsResponse is a list of projects
+type ListProjectsResponse struct {
+	Projects []*store.Project `json:"projects"`
+	Count    int              `json:"count"`
+}
+
+// ListCrawlLogsResponse is the matching entries of a crawl's structured log
+type ListCrawlLogsResponse struct {
+	Logs  []*store.CrawlLog `json:"logs"`
+	Count int               `json:"count"`
+}
+
+// IssueTrendsResponse is a project's daily page and issue counts, oldest
+// first. Days without a finished crawl are missing.
+type IssueTrendsResponse struct {
+	Trends []*store.IssueTrend `json:"trends"`
+	Count  int                 `json:"count"`
+}
+
+// AggregateTrendsRequest is the body of a trend aggregation run
+type AggregateTrendsRequest struct {
+	Days int `json:"days,omitempty"` // Days to aggregate, ending yesterday; 1 by default
+}
+
+// AggregateTrendsResponse is the days a trend aggregation run covered and
+// how many project-days it stored
+type AggregateTrendsResponse struct {
+	From string `json:"from"` // YYYY-MM-DD
+	To   string `json:"to"`
+	Rows int    `json:"rows"`
+}
+
+// ListArtifactsResponse is a list of a crawl's stored artifacts
+type ListArtifactsResponse struct {
+	Artifacts []store.Artifact `json:"artifacts"`
+	Count     int              `json:"count"`
+}
+
+// ArtifactURLResponse is a signed URL that downloads an artifact
+type ArtifactURLResponse struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CreateArtifactUploadRequest names an artifact the client is about to upload,
+// e.g. "results.json" or "html/index.json"
+type CreateArtifactUploadRequest struct {
+	Name string `json:"name"`
+}
+
+// ArtifactUploadResponse is a signed URL to PUT the artifact's content to
+type ArtifactUploadResponse struct {
+	Name      string `json:"name"`
+	UploadURL string `json:"upload_url"`
+}
+
+// HealthResponse reports that the server is up
+type HealthResponse struct {
+	Status string `json:"status"`
+	Time   string `json:"time"` // RFC 3339, UTC
+}
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// BillingPlan is a plan for sale and its Stripe price
+type BillingPlan struct {
+	ID            string `json:"id"` // Lookup key of the price: pro_monthly, pro_annual, or team_seat
+	PriceID       string `json:"price_id"`
+	Tier          string `json:"tier"` // Subscription tier it grants: pro or team
+	Name          string `json:"name"` // Of the Stripe product
+	Description   string `json:"description,omitempty"`
+	UnitAmount    int64  `json:"unit_amount"` // In the currency's smallest unit, e.g. cents
+	Currency      string `json:"currency"`
+	Interval      string `json:"interval"` // month or year
+	IntervalCount int64  `json:"interval_count"`
+}
+
+// BillingPlansResponse lists the plans for sale
+type BillingPlansResponse struct {
+	Plans []BillingPlan `json:"plans"`
+}
+
+// PortalSessionResponse is the URL of a Stripe billing portal session
+type PortalSessionResponse struct {
+	URL string `json:"url"`
+}
+
+
+// AdminUsersResponse is a page of user profiles for the admin API
+type AdminUsersResponse struct {
+	Users []map[string]interface{} `json:"users"`
+	Count int                      `json:"count"`
+}
+
+// WebhookEvent is a Stripe event as listed and replayed by the admin API
+type WebhookEvent struct {
+	ID              string    `json:"id"`
+	Type            string    `json:"type"`
+	Created         time.Time `json:"created"`
+	PendingWebhooks int64     `json:"pending_webhooks"` // Endpoints Stripe hasn't delivered it to yet
+}
+
+// WebhookEventsResponse lists Stripe events whose delivery failed
+type WebhookEventsResponse struct {
+	Events []WebhookEvent `json:"events"`
+	Count  int            `json:"count"`
+}
+
+// ReassignProjectRequest names a project's new owner
+type ReassignProjectRequest struct {
+	OwnerID string `json:"owner_id"`
+}
+
+// CompleteCrawlRequest ends a stuck crawl. Status defaults to "failed".
+type CompleteCrawlRequest struct {
+	Status string `json:"status,omitempty"` // "failed", "succeeded", or "cancelled"
+	Reason string `json:"reason,omitempty"` // Recorded as the crawl's error
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/store"
+	"github.com/dillonlara115/barracuda/internal/verify"
+	"go.uber.org/zap"
+)
+
+// handleProjectVerification handles GET and POST
+// /api/v1/projects/:id/verification: the instructions for verifying the
+// project's domain, and checking them
+func (s *Server) handleProjectVerification(w http.ResponseWriter, r *http.Request, projectID, userID string) {
+	hasAccess, err := s.verifyProjectAccess(userID, projectID)
+	if err != nil {
+		s.logger.Error("Failed to verify project access", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to verify project access")
+		return
+	}
+	if !hasAccess {
+		s.respondError(w, http.StatusForbidden, "You don't have access to this project")
+		return
+	}
+
+	project, err := s.store.GetProject(r.Context(), projectID)
+	if errors.Is(err, store.ErrNotFound) {
+		s.respondError(w, http.StatusNotFound, "Project not found")
+		return
+	}
+	if err != nil {
+		s.logger.Error("Failed to get project", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to get project")
+		return
+	}
+
+	// Projects created before verification, or directly in Supabase, get
+	// their token on first use
+	if project.VerificationToken == "" {
+		token, err := verify.NewToken()
+		if err != nil {
+			s.logger.Error("Failed to generate verification token", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to generate verification token")
+			return
+		}
+		if err := s.store.UpdateProject(r.Context(), projectID, store.ProjectUpdate{VerificationToken: &token}); err != nil {
+			s.logger.Error("Failed to save verification token", zap.Error(err))
+			s.respondError(w, http.StatusInternalServerError, "Failed to save verification token")
+			return
+		}
+		project.VerificationToken = token
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.respondJSON(w, http.StatusOK, s.verificationResponse(project))
+	case http.MethodPost:
+		s.handleVerifyDomain(w, r, project)
+	default:
+		s.respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// handleVerifyDomain checks a project's domain for its verification token
+// and records the project as verified when it is found
+func (s *Server) handleVerifyDomain(w http.ResponseWriter, r *http.Request, project *store.Project) {
+	var req VerifyDomainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, http.StatusBadRequest, fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+
+	domain := verify.Domain(project.Domain)
+	err := verify.Check(r.Context(), req.Method, domain, project.VerificationToken)
+	switch {
+	case errors.Is(err, verify.ErrInvalidMethod), errors.Is(err, verify.ErrInvalidDomain):
+		s.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		s.logger.Info("Domain verification failed", zap.String("project_id", project.ID), zap.String("domain", domain), zap.String("method", req.Method), zap.Error(err))
+		s.respondError(w, http.StatusUnprocessableEntity, fmt.Sprintf("Could not verify %s: %v", domain, err))
+		return
+	}
+
+	now := time.Now().UTC()
+	update := store.ProjectUpdate{VerificationMethod: &req.Method, VerifiedAt: &now}
+	if err := s.store.UpdateProject(r.Context(), project.ID, update); err != nil {
+		s.logger.Error("Failed to save domain verification", zap.Error(err))
+		s.respondError(w, http.StatusInternalServerError, "Failed to save domain verification")
+		return
+	}
+	project.VerificationMethod, project.VerifiedAt = req.Method, &now
+	s.logger.Info("Domain verified", zap.String("project_id", project.ID), zap.String("domain", domain), zap.String("method", req.Method))
+
+	s.respondJSON(w, http.StatusOK, s.verificationResponse(project))
+}
+
+// verificationResponse describes a project's verification state and how
+// to verify it
+func (s *Server) verificationResponse(project *store.Project) DomainVerificationResponse {
+	domain := verify.Domain(project.Domain)
+	return DomainVerificationResponse{
+		Domain:     domain,
+		Required:   s.config.RequireDomainVerification,
+		Verified:   project.Verified(),
+		Method:     project.VerificationMethod,
+		VerifiedAt: project.VerifiedAt,
+		Token:      project.VerificationToken,
+		DNSRecord: DNSRecord{
+			Type:  "TXT",
+			Name:  domain,
+			Value: verify.TXTRecord(project.VerificationToken),
+		},
+		MetaTag: verify.MetaTag(project.VerificationToken),
+	}
+}
+
+// checkCrawlTarget returns why a project may not crawl host, or "" when it
+// may. When the server requires domain verification, the host must be on the
+// project's verified domain.
+func (s *Server) checkCrawlTarget(project *store.Project, host string) string {
+	if !s.config.RequireDomainVerification {
+		return ""
+	}
+
+	domain := verify.Domain(project.Domain)
+	if !project.Verified() {
+		return fmt.Sprintf("Verify that you own %s before crawling it", domain)
+	}
+	if !verify.Covers(project.VerificationMethod, domain, host) {
+		if project.VerificationMethod == verify.MethodMeta {
+			return fmt.Sprintf("%s is not covered by the meta tag verification of %s; verify the domain with a DNS TXT record to crawl its subdomains", host, domain)
+		}
+		return fmt.Sprintf("%s is not on this project's verified domain, %s", host, domain)
+	}
+	return ""
+}
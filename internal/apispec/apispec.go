@@ -0,0 +1,177 @@
+// Package apispec describes the HTTP API the web dashboard uses, both the
+// local serve command's and the cloud API server's, as an OpenAPI document
+// and a typed TypeScript client generated from the Go types the handlers
+// encode. The generated files are committed, and CI fails when they are
+// stale, so an API change that would break the dashboard shows up in review.
+package apispec
+
+//go:generate go run ./gen -spec ../../docs/openapi.json -client ../../web/src/lib/api.gen.ts
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+
+	endpoints []Endpoint // In declaration order, for the client
+}
+
+// Info names the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem holds the operations of one path, by lowercase HTTP method
+type PathItem map[string]*Operation
+
+// Operation is one method on a path
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"` // "path" or "query"
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is an operation's JSON request body
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is one response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a body in one content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas and the security schemes
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme is how a client authenticates
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// bearerAuth names the cloud API's security scheme
+const bearerAuth = "bearerAuth"
+
+// Build describes Endpoints as an OpenAPI document
+func Build() *Document {
+	schemas := newSchemaSet()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Barracuda API",
+			Description: "Endpoints of the dashboard served by `barracuda serve` (tag serve) and of the cloud API server (tag cloud).",
+			Version:     "1",
+		},
+		Paths: make(map[string]PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerAuth: {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "A Supabase access token, or the API token of a self-hosted server",
+				},
+			},
+		},
+		endpoints: Endpoints,
+	}
+	errorSchema := schemas.schemaFor(errorType)
+
+	for _, endpoint := range Endpoints {
+		op := &Operation{
+			OperationID: endpoint.OperationID,
+			Summary:     endpoint.Summary,
+			Tags:        []string{endpoint.Tag},
+			Responses: map[string]*Response{
+				strconv.Itoa(endpoint.status()): {
+					Description: http.StatusText(endpoint.status()),
+					Content:     jsonContent(schemas.schemaFor(endpoint.Response)),
+				},
+				"default": {
+					Description: "Error",
+					Content:     jsonContent(errorSchema),
+				},
+			},
+		}
+		for _, name := range endpoint.pathParams() {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+		for _, name := range endpoint.Query {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+		}
+		if endpoint.Request != nil {
+			op.RequestBody = &RequestBody{Required: !endpoint.Optional, Content: jsonContent(schemas.schemaFor(endpoint.Request))}
+		}
+		if endpoint.Tag == TagCloud && !endpoint.Public {
+			op.Security = []map[string][]string{{bearerAuth: {}}}
+		}
+
+		item, ok := doc.Paths[endpoint.Path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[endpoint.Path] = item
+		}
+		item[strings.ToLower(endpoint.Method)] = op
+	}
+
+	doc.Components.Schemas = schemas.components
+	return doc
+}
+
+// JSON encodes doc as indented JSON ending in a newline
+func JSON(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// typeOf returns the reflect.Type of T, which may be an interface or slice
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
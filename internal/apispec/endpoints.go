@@ -0,0 +1,153 @@
+package apispec
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/api"
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/gsc"
+	"github.com/dillonlara115/barracuda/internal/store"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// Endpoint tags
+const (
+	TagServe = "serve" // Local dashboard API of barracuda serve
+	TagCloud = "cloud" // Cloud API server (barracuda api)
+)
+
+// Endpoint is one operation of the API
+type Endpoint struct {
+	Method      string
+	Path        string // Path parameters are written {name}
+	OperationID string // Also the TypeScript client's method name
+	Summary     string
+	Tag         string
+	Public      bool     // Cloud endpoint that needs no token
+	Query       []string // Optional string query parameters
+	Request     reflect.Type
+	Optional    bool // The request body may be left out
+	Response    reflect.Type
+	Status      int // Success status; 200 when zero
+}
+
+// errorType is the body of error responses
+var errorType = typeOf[api.ErrorResponse]()
+
+// Endpoints lists the operations the dashboard calls. Keep it in step with
+// the handlers in cmd/serve.go and internal/api, then run go generate.
+var Endpoints = []Endpoint{
+	{Method: http.MethodGet, Path: "/api/results", OperationID: "getResults", Summary: "Page results of the served crawl", Tag: TagServe,
+		Response: typeOf[[]*models.PageResult]()},
+	{Method: http.MethodGet, Path: "/api/summary", OperationID: "getSummary", Summary: "Analysis summary of the served crawl", Tag: TagServe,
+		Response: typeOf[analyzer.Summary]()},
+	{Method: http.MethodGet, Path: "/api/metadata", OperationID: "getMetadata", Summary: "Metadata manifest of the served crawl; empty when it has none", Tag: TagServe,
+		Response: typeOf[crawldir.Metadata]()},
+	{Method: http.MethodGet, Path: "/api/backlinks", OperationID: "getBacklinks", Summary: "Referring domains by page URL", Tag: TagServe,
+		Response: typeOf[map[string]int]()},
+	{Method: http.MethodGet, Path: "/api/graph", OperationID: "getGraph", Summary: "Link graph: linked URLs by source URL", Tag: TagServe,
+		Response: typeOf[map[string][]string]()},
+	{Method: http.MethodGet, Path: "/api/logs", OperationID: "getLogs", Summary: "Crawled, failed, and skipped URLs of the served crawl", Tag: TagServe,
+		Query: []string{"event", "reason", "url", "limit"}, Response: typeOf[[]models.CrawlEvent]()},
+	{Method: http.MethodGet, Path: "/api/canonicals", OperationID: "getCanonicals", Summary: "Pages of the served crawl grouped by the canonical they point to", Tag: TagServe,
+		Response: typeOf[[]analyzer.CanonicalCluster]()},
+	{Method: http.MethodGet, Path: "/api/search", OperationID: "searchSnapshots", Summary: "Pages of the served crawl whose saved HTML contains, or with missing=true lacks, a string", Tag: TagServe,
+		Query: []string{"q", "regexp", "ignore_case", "missing"}, Response: typeOf[crawler.SnapshotSearch]()},
+	{Method: http.MethodGet, Path: "/api/gsc/connect", OperationID: "connectGSC", Summary: "Start connecting Search Console", Tag: TagServe,
+		Response: typeOf[gsc.AuthURLResponse]()},
+	{Method: http.MethodGet, Path: "/api/gsc/properties", OperationID: "getGSCProperties", Summary: "Search Console properties of the connected account", Tag: TagServe,
+		Query: []string{"user_id"}, Response: typeOf[[]*models.GSCProperty]()},
+	{Method: http.MethodPost, Path: "/api/gsc/performance", OperationID: "getGSCPerformance", Summary: "Search Console performance by page URL", Tag: TagServe,
+		Request: typeOf[gsc.PerformanceRequest](), Response: typeOf[map[string]*models.GSCPerformance]()},
+	{Method: http.MethodPost, Path: "/api/gsc/enrich-issues", OperationID: "enrichIssues", Summary: "Start prioritizing the served crawl's issues by Search Console performance, or reuse the last result for the same property and period", Tag: TagServe,
+		Query: []string{"refresh"}, Request: typeOf[gsc.PerformanceRequest](), Response: typeOf[gsc.EnrichJob](), Status: http.StatusAccepted},
+	{Method: http.MethodGet, Path: "/api/gsc/enrich-issues", OperationID: "getIssueEnrichment", Summary: "Progress of the issue enrichment and, once done, the prioritized issues", Tag: TagServe,
+		Response: typeOf[gsc.EnrichJob]()},
+
+	{Method: http.MethodGet, Path: "/health", OperationID: "getHealth", Summary: "Server health", Tag: TagCloud, Public: true,
+		Response: typeOf[api.HealthResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/crawls", OperationID: "createCrawl", Summary: "Ingest a crawl run by the CLI", Tag: TagCloud,
+		Request: typeOf[api.CreateCrawlRequest](), Response: typeOf[api.CreateCrawlResponse](), Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v1/crawls", OperationID: "listCrawls", Summary: "Crawls the user can see, newest first", Tag: TagCloud,
+		Query: []string{"project_id"}, Response: typeOf[api.ListCrawlsResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/crawls/{id}", OperationID: "getCrawl", Summary: "A crawl with its live page counts and, once finished, its indexability", Tag: TagCloud,
+		Response: typeOf[api.CrawlResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/crawls/{id}/graph", OperationID: "getCrawlGraph", Summary: "Link graph of a crawl, with its indexability", Tag: TagCloud,
+		Response: typeOf[api.CrawlGraphResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/crawls/{id}/logs", OperationID: "listCrawlLogs", Summary: "Crawled, failed, and skipped URLs of a crawl, in the order they were logged", Tag: TagCloud,
+		Query: []string{"event", "reason", "url", "limit"}, Response: typeOf[api.ListCrawlLogsResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/crawls/{id}/canonicals", OperationID: "getCrawlCanonicals", Summary: "Pages of a crawl grouped by the canonical they point to, with their indexable duplicates", Tag: TagCloud,
+		Response: typeOf[api.CanonicalClustersResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/crawls/{id}/cancel", OperationID: "cancelCrawl", Summary: "Stop a crawl running on the server, keeping the pages crawled so far; it ends with status cancelled", Tag: TagCloud,
+		Request: typeOf[api.CancelCrawlRequest](), Optional: true, Response: typeOf[api.CancelCrawlResponse](), Status: http.StatusAccepted},
+	{Method: http.MethodGet, Path: "/api/v1/crawls/{id}/artifacts", OperationID: "listCrawlArtifacts", Summary: "Raw exports and HTML snapshots stored for a crawl", Tag: TagCloud,
+		Response: typeOf[api.ListArtifactsResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/crawls/{id}/artifacts", OperationID: "createCrawlArtifactUpload", Summary: "Signed URL to upload a crawl artifact to", Tag: TagCloud,
+		Request: typeOf[api.CreateArtifactUploadRequest](), Response: typeOf[api.ArtifactUploadResponse](), Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v1/crawls/{id}/artifacts/{name}", OperationID: "getCrawlArtifact", Summary: "Signed download URL of a crawl artifact", Tag: TagCloud,
+		Response: typeOf[api.ArtifactURLResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/projects", OperationID: "createProject", Summary: "Create a project", Tag: TagCloud,
+		Request: typeOf[api.CreateProjectRequest](), Response: typeOf[store.Project](), Status: http.StatusCreated},
+	{Method: http.MethodGet, Path: "/api/v1/projects", OperationID: "listProjects", Summary: "Projects the user owns or is a member of", Tag: TagCloud,
+		Response: typeOf[api.ListProjectsResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/projects/{id}", OperationID: "getProject", Summary: "A project", Tag: TagCloud,
+		Response: typeOf[store.Project]()},
+	{Method: http.MethodGet, Path: "/api/v1/projects/{id}/crawls", OperationID: "listProjectCrawls", Summary: "Crawls of a project, newest first", Tag: TagCloud,
+		Response: typeOf[api.ListCrawlsResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/projects/{id}/verification", OperationID: "getProjectVerification", Summary: "Domain verification state of a project, with the DNS record and meta tag that verify it", Tag: TagCloud,
+		Response: typeOf[api.DomainVerificationResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/projects/{id}/verification", OperationID: "verifyProjectDomain", Summary: "Check a project's domain for its verification token", Tag: TagCloud,
+		Request: typeOf[api.VerifyDomainRequest](), Response: typeOf[api.DomainVerificationResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/projects/{id}/trends", OperationID: "getProjectTrends", Summary: "Daily page and issue counts of a project over the last days (90 by default), oldest first", Tag: TagCloud,
+		Query: []string{"days"}, Response: typeOf[api.IssueTrendsResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/projects/{id}/crawl", OperationID: "triggerCrawl", Summary: "Start a crawl of a project on the server; with domain verification required, only of its verified domain", Tag: TagCloud,
+		Request: typeOf[api.TriggerCrawlRequest](), Response: typeOf[api.TriggerCrawlResponse](), Status: http.StatusAccepted},
+	{Method: http.MethodGet, Path: "/api/v1/projects/{id}/gsc/connect", OperationID: "connectProjectGSC", Summary: "Start connecting Search Console to a project", Tag: TagCloud,
+		Response: typeOf[gsc.AuthURLResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/billing/summary", OperationID: "getBillingSummary", Summary: "The user's profile and subscription", Tag: TagCloud,
+		Response: typeOf[api.BillingSummaryResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/billing/plans", OperationID: "getBillingPlans", Summary: "The plans for sale, with their Stripe prices", Tag: TagCloud,
+		Response: typeOf[api.BillingPlansResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/billing/checkout", OperationID: "createCheckoutSession", Summary: "Start a Stripe checkout, with a promotion code or a field to enter one", Tag: TagCloud,
+		Request: typeOf[api.CreateCheckoutSessionRequest](), Response: typeOf[api.CreateCheckoutSessionResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/billing/portal", OperationID: "createBillingPortalSession", Summary: "Open the Stripe billing portal, on its home page or one action", Tag: TagCloud,
+		Request: typeOf[api.CreatePortalSessionRequest](), Optional: true, Response: typeOf[api.PortalSessionResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/admin/users", OperationID: "adminListUsers", Summary: "Admin: user profiles, optionally of one tier", Tag: TagCloud,
+		Query: []string{"tier", "limit", "offset"}, Response: typeOf[api.AdminUsersResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/admin/users/{id}", OperationID: "adminGetUser", Summary: "Admin: a user's profile, subscription, and entitlements", Tag: TagCloud,
+		Response: typeOf[api.BillingSummaryResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/admin/users/{id}/sync-subscription", OperationID: "adminSyncSubscription", Summary: "Admin: copy a user's latest subscription from Stripe", Tag: TagCloud,
+		Response: typeOf[api.BillingSummaryResponse]()},
+	{Method: http.MethodGet, Path: "/api/v1/admin/webhook-events", OperationID: "adminListWebhookEvents", Summary: "Admin: recent Stripe events that failed to reach a webhook, newest first", Tag: TagCloud,
+		Query: []string{"type", "limit"}, Response: typeOf[api.WebhookEventsResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/admin/webhook-events/{id}/replay", OperationID: "adminReplayWebhookEvent", Summary: "Admin: process a Stripe event as if its webhook had arrived", Tag: TagCloud,
+		Response: typeOf[api.WebhookEvent]()},
+	{Method: http.MethodPost, Path: "/api/v1/admin/projects/{id}/owner", OperationID: "adminReassignProject", Summary: "Admin: make another user a project's owner", Tag: TagCloud,
+		Request: typeOf[api.ReassignProjectRequest](), Response: typeOf[store.Project]()},
+	{Method: http.MethodGet, Path: "/api/v1/admin/crawls", OperationID: "adminListCrawls", Summary: "Admin: crawls of every project, newest first", Tag: TagCloud,
+		Query: []string{"status", "project_id"}, Response: typeOf[api.ListCrawlsResponse]()},
+	{Method: http.MethodPost, Path: "/api/v1/admin/crawls/{id}/complete", OperationID: "adminCompleteCrawl", Summary: "Admin: end a crawl left pending or running", Tag: TagCloud,
+		Request: typeOf[api.CompleteCrawlRequest](), Optional: true, Response: typeOf[store.Crawl]()},
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// pathParams returns the names of the endpoint's path parameters in order
+func (e Endpoint) pathParams() []string {
+	var names []string
+	for _, match := range pathParamPattern.FindAllStringSubmatch(e.Path, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+func (e Endpoint) status() int {
+	if e.Status == 0 {
+		return http.StatusOK
+	}
+	return e.Status
+}
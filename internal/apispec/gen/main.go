@@ -0,0 +1,53 @@
+// Command gen writes the API's OpenAPI document and TypeScript client. With
+// -check it writes nothing and fails when the files on disk are stale, which
+// CI uses to catch API changes the dashboard hasn't been updated for.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dillonlara115/barracuda/internal/apispec"
+)
+
+func main() {
+	specPath := flag.String("spec", "docs/openapi.json", "OpenAPI document to write")
+	clientPath := flag.String("client", "web/src/lib/api.gen.ts", "TypeScript client to write")
+	check := flag.Bool("check", false, "Fail if the files are out of date instead of writing them")
+	flag.Parse()
+
+	doc := apispec.Build()
+	spec, err := apispec.JSON(doc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+
+	files := []struct {
+		path string
+		data []byte
+	}{
+		{*specPath, spec},
+		{*clientPath, apispec.TypeScript(doc)},
+	}
+	stale := false
+	for _, file := range files {
+		if *check {
+			current, err := os.ReadFile(file.path)
+			if err != nil || !bytes.Equal(current, file.data) {
+				fmt.Fprintf(os.Stderr, "%s is out of date; run go generate ./internal/apispec\n", file.path)
+				stale = true
+			}
+			continue
+		}
+		if err := os.WriteFile(file.path, file.data, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", file.path, err)
+			os.Exit(1)
+		}
+	}
+	if stale {
+		os.Exit(1)
+	}
+}
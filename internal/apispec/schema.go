@@ -0,0 +1,166 @@
+package apispec
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is an OpenAPI 3.0 schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+
+	order []string // Property names in Go field order
+}
+
+const componentPrefix = "#/components/schemas/"
+
+var (
+	timeType    = typeOf[time.Time]()
+	rawJSONType = typeOf[json.RawMessage]()
+)
+
+// schemaSet turns Go types into schemas, collecting structs as named
+// components the way encoding/json would encode them
+type schemaSet struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// schemaFor returns the schema of values of type t
+func (s *schemaSet) schemaFor(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawJSONType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.schemaFor(t.Elem())
+		if schema.Ref != "" {
+			// $ref can't have sibling keywords in OpenAPI 3.0
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schemaFor(elem(t))}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schemaFor(elem(t))}
+	case reflect.Struct:
+		return &Schema{Ref: componentPrefix + s.component(t)}
+	default:
+		// interface{} and anything else encoding/json can't describe
+		return &Schema{}
+	}
+}
+
+// elem returns the element type of a slice or map. Pointer elements are
+// dereferenced: a nil element is a bug, not a value clients should handle.
+func elem(t reflect.Type) reflect.Type {
+	e := t.Elem()
+	for e.Kind() == reflect.Pointer {
+		e = e.Elem()
+	}
+	return e
+}
+
+// component registers struct type t as a named schema and returns its name
+func (s *schemaSet) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := s.components[name]; taken || name == "" {
+		// Two packages define the type, or it is anonymous
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	s.names[t] = name
+
+	// Register before filling in fields so recursive types terminate
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.components[name] = schema
+	s.addFields(schema, t)
+	return name
+}
+
+// addFields adds the JSON properties of struct type t to schema, promoting
+// the fields of untagged embedded structs as encoding/json does
+func (s *schemaSet) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				s.addFields(schema, fieldType)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if _, ok := schema.Properties[name]; ok {
+			continue
+		}
+
+		schema.Properties[name] = s.schemaFor(field.Type)
+		schema.order = append(schema.order, name)
+		if !hasOption(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
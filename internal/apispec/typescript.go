@@ -0,0 +1,198 @@
+package apispec
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// clientHeader starts the generated TypeScript client
+const clientHeader = `// Code generated by go generate ./internal/apispec; DO NOT EDIT.
+//
+// Types and client for the Barracuda API, from the Go types in
+// internal/apispec/endpoints.go.
+
+`
+
+// clientRuntime is the request helper shared by every client method
+const clientRuntime = `export class ApiError extends Error {
+  readonly status: number;
+
+  constructor(status: number, message: string) {
+    super(message);
+    this.name = 'ApiError';
+    this.status = status;
+  }
+}
+
+export interface ClientOptions {
+  /** Origin of the API, such as the cloud API's URL; empty for the page's own origin */
+  baseURL?: string;
+  /** Extra headers for each request, such as Authorization for the cloud API */
+  headers?: () => Record<string, string> | Promise<Record<string, string>>;
+  fetch?: typeof fetch;
+}
+
+type QueryParams = Record<string, string | undefined>;
+
+export function createClient(options: ClientOptions = {}) {
+  const baseURL = options.baseURL ?? '';
+  const doFetch = options.fetch ?? fetch.bind(globalThis);
+
+  async function request<T>(method: string, path: string, query?: QueryParams, body?: unknown): Promise<T> {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value !== undefined) params.set(key, value);
+    }
+    const search = params.toString();
+    const headers: Record<string, string> = options.headers ? { ...(await options.headers()) } : {};
+    if (body !== undefined) headers['Content-Type'] = 'application/json';
+
+    const response = await doFetch(baseURL + path + (search ? '?' + search : ''), {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    if (!response.ok) {
+      let message = response.statusText;
+      try {
+        const data = (await response.json()) as ErrorResponse;
+        if (data.error) message = data.error;
+      } catch {
+        // Not a JSON error body
+      }
+      throw new ApiError(response.status, message);
+    }
+    return (await response.json()) as T;
+  }
+
+  return {
+`
+
+const clientFooter = `  };
+}
+
+export type Client = ReturnType<typeof createClient>;
+`
+
+// TypeScript renders doc's schemas as interfaces and its operations as
+// methods of a fetch-based client
+func TypeScript(doc *Document) []byte {
+	var b bytes.Buffer
+	b.WriteString(clientHeader)
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schema := doc.Components.Schemas[name]
+		required := make(map[string]bool, len(schema.Required))
+		for _, property := range schema.Required {
+			required[property] = true
+		}
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+		for _, property := range schema.order {
+			optional := "?"
+			if required[property] {
+				optional = ""
+			}
+			fmt.Fprintf(&b, "  %s%s: %s;\n", tsKey(property), optional, tsType(schema.Properties[property]))
+		}
+		b.WriteString("}\n\n")
+	}
+
+	b.WriteString(clientRuntime)
+	for _, endpoint := range doc.endpoints {
+		writeMethod(&b, doc, endpoint)
+	}
+	b.WriteString(clientFooter)
+	return b.Bytes()
+}
+
+// writeMethod writes the client method of one endpoint
+func writeMethod(b *bytes.Buffer, doc *Document, endpoint Endpoint) {
+	op := doc.Paths[endpoint.Path][strings.ToLower(endpoint.Method)]
+
+	var params []string
+	for _, name := range endpoint.pathParams() {
+		params = append(params, name+": string")
+	}
+	var body string
+	if op.RequestBody != nil {
+		param := "body: "
+		if !op.RequestBody.Required {
+			param = "body?: "
+		}
+		params = append(params, param+tsType(op.RequestBody.Content["application/json"].Schema))
+		body = "body"
+	}
+	query := "undefined"
+	if len(endpoint.Query) > 0 {
+		fields := make([]string, len(endpoint.Query))
+		for i, name := range endpoint.Query {
+			fields[i] = tsKey(name) + "?: string"
+		}
+		params = append(params, "query: { "+strings.Join(fields, "; ")+" } = {}")
+		query = "query"
+	}
+
+	path := "'" + endpoint.Path + "'"
+	if len(endpoint.pathParams()) > 0 {
+		path = "`" + pathParamPattern.ReplaceAllString(endpoint.Path, "$${encodeURIComponent($1)}") + "`"
+	}
+	args := []string{"'" + endpoint.Method + "'", path}
+	if body != "" {
+		args = append(args, query, body)
+	} else if query != "undefined" {
+		args = append(args, query)
+	}
+
+	response := op.Responses[fmt.Sprint(endpoint.status())].Content["application/json"].Schema
+	fmt.Fprintf(b, "    /** %s */\n", endpoint.Summary)
+	fmt.Fprintf(b, "    %s: (%s) =>\n      request<%s>(%s),\n", endpoint.OperationID, strings.Join(params, ", "), tsType(response), strings.Join(args, ", "))
+}
+
+// tsType renders a schema as a TypeScript type
+func tsType(schema *Schema) string {
+	var t string
+	switch {
+	case schema.Ref != "":
+		t = strings.TrimPrefix(schema.Ref, componentPrefix)
+	case len(schema.AllOf) == 1:
+		t = tsType(schema.AllOf[0])
+	case schema.Type == "string":
+		t = "string"
+	case schema.Type == "integer" || schema.Type == "number":
+		t = "number"
+	case schema.Type == "boolean":
+		t = "boolean"
+	case schema.Type == "array":
+		t = tsType(schema.Items)
+		if strings.Contains(t, " ") {
+			t = "(" + t + ")"
+		}
+		t += "[]"
+	case schema.AdditionalProperties != nil:
+		t = "Record<string, " + tsType(schema.AdditionalProperties) + ">"
+	default:
+		t = "unknown"
+	}
+	if schema.Nullable && t != "unknown" {
+		t += " | null"
+	}
+	return t
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsKey quotes a property name that isn't a valid identifier
+func tsKey(name string) string {
+	if identifierPattern.MatchString(name) {
+		return name
+	}
+	return "'" + name + "'"
+}
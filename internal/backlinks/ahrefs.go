@@ -0,0 +1,60 @@
+package backlinks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AhrefsEndpoint is the Ahrefs API v3 backlinks-stats endpoint
+const AhrefsEndpoint = "https://api.ahrefs.com/v3/site-explorer/backlinks-stats"
+
+// Ahrefs reads live referring domains from the Ahrefs API v3
+type Ahrefs struct {
+	APIKey     string
+	Endpoint   string // defaults to AhrefsEndpoint
+	HTTPClient *http.Client
+}
+
+type ahrefsResponse struct {
+	Metrics struct {
+		LiveRefdomains int `json:"live_refdomains"`
+	} `json:"metrics"`
+}
+
+// Name implements Provider
+func (a *Ahrefs) Name() string { return "ahrefs" }
+
+// BatchSize implements Provider; Ahrefs takes one target per request
+func (a *Ahrefs) BatchSize() int { return 1 }
+
+// ReferringDomains implements Provider
+func (a *Ahrefs) ReferringDomains(ctx context.Context, urls []string) (map[string]int, error) {
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = AhrefsEndpoint
+	}
+
+	counts := make(map[string]int, len(urls))
+	for _, target := range urls {
+		query := url.Values{}
+		query.Set("target", target)
+		query.Set("mode", "exact")
+		query.Set("date", time.Now().Format("2006-01-02"))
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+a.APIKey)
+
+		var resp ahrefsResponse
+		if err := doJSON(a.HTTPClient, req, &resp); err != nil {
+			return nil, err
+		}
+		counts[target] = resp.Metrics.LiveRefdomains
+	}
+	return counts, nil
+}
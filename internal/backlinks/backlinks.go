@@ -0,0 +1,91 @@
+// Package backlinks looks up how many referring domains link to a URL using
+// third-party backlink APIs (Ahrefs, Majestic, or Moz).
+package backlinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// Provider fetches referring-domain counts from one backlink API
+type Provider interface {
+	// Name is the provider's name as used in the config file
+	Name() string
+	// ReferringDomains returns the number of referring domains for each URL.
+	// URLs the provider knows nothing about may be left out.
+	ReferringDomains(ctx context.Context, urls []string) (map[string]int, error)
+	// BatchSize is how many URLs one ReferringDomains call accepts
+	BatchSize() int
+}
+
+// New creates the provider named in cfg
+func New(cfg utils.BacklinksFileConfig) (Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("backlinks.api_key is required")
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	switch cfg.Provider {
+	case "ahrefs":
+		return &Ahrefs{APIKey: cfg.APIKey, HTTPClient: client}, nil
+	case "majestic":
+		return &Majestic{APIKey: cfg.APIKey, HTTPClient: client}, nil
+	case "moz":
+		if cfg.AccessID == "" {
+			return nil, fmt.Errorf("backlinks.access_id is required for moz")
+		}
+		return &Moz{AccessID: cfg.AccessID, SecretKey: cfg.APIKey, HTTPClient: client}, nil
+	case "":
+		return nil, fmt.Errorf("backlinks.provider is required")
+	default:
+		return nil, fmt.Errorf("unknown backlinks provider %q (expected ahrefs, majestic, or moz)", cfg.Provider)
+	}
+}
+
+// Lookup fetches referring-domain counts for urls in batches the provider
+// accepts. Counts fetched before a failing batch are returned with the error.
+func Lookup(ctx context.Context, provider Provider, urls []string) (map[string]int, error) {
+	counts := make(map[string]int, len(urls))
+	size := provider.BatchSize()
+	for start := 0; start < len(urls); start += size {
+		end := start + size
+		if end > len(urls) {
+			end = len(urls)
+		}
+		batch, err := provider.ReferringDomains(ctx, urls[start:end])
+		if err != nil {
+			return counts, fmt.Errorf("%s lookup failed: %w", provider.Name(), err)
+		}
+		for url, count := range batch {
+			counts[url] = count
+		}
+	}
+	return counts, nil
+}
+
+// doJSON sends req and decodes a JSON response into v
+func doJSON(client *http.Client, req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "barracuda/1.0.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, body)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package backlinks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// MajesticEndpoint is the Majestic JSON API endpoint
+const MajesticEndpoint = "https://api.majestic.com/api/json"
+
+// Majestic reads referring domains from Majestic's GetIndexItemInfo command
+// using the Fresh index
+type Majestic struct {
+	APIKey     string
+	Endpoint   string // defaults to MajesticEndpoint
+	HTTPClient *http.Client
+}
+
+type majesticResponse struct {
+	Code         string `json:"Code"`
+	ErrorMessage string `json:"ErrorMessage"`
+	DataTables   struct {
+		Results struct {
+			Data []struct {
+				Item       string `json:"Item"`
+				RefDomains int    `json:"RefDomains"`
+			} `json:"Data"`
+		} `json:"Results"`
+	} `json:"DataTables"`
+}
+
+// Name implements Provider
+func (m *Majestic) Name() string { return "majestic" }
+
+// BatchSize implements Provider; GetIndexItemInfo takes up to 100 items
+func (m *Majestic) BatchSize() int { return 100 }
+
+// ReferringDomains implements Provider
+func (m *Majestic) ReferringDomains(ctx context.Context, urls []string) (map[string]int, error) {
+	endpoint := m.Endpoint
+	if endpoint == "" {
+		endpoint = MajesticEndpoint
+	}
+
+	query := url.Values{}
+	query.Set("app_api_key", m.APIKey)
+	query.Set("cmd", "GetIndexItemInfo")
+	query.Set("datasource", "fresh")
+	query.Set("items", strconv.Itoa(len(urls)))
+	for i, target := range urls {
+		query.Set("item"+strconv.Itoa(i), target)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var resp majesticResponse
+	if err := doJSON(m.HTTPClient, req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Code != "OK" {
+		return nil, fmt.Errorf("majestic error %s: %s", resp.Code, resp.ErrorMessage)
+	}
+
+	// Results come back in request order
+	counts := make(map[string]int, len(urls))
+	for i, row := range resp.DataTables.Results.Data {
+		if i < len(urls) {
+			counts[urls[i]] = row.RefDomains
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,65 @@
+package backlinks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// MozEndpoint is the Moz Links API v2 url_metrics endpoint
+const MozEndpoint = "https://lsapi.seomoz.com/v2/url_metrics"
+
+// Moz reads root domains linking to each page from the Moz Links API v2
+type Moz struct {
+	AccessID   string
+	SecretKey  string
+	Endpoint   string // defaults to MozEndpoint
+	HTTPClient *http.Client
+}
+
+type mozResponse struct {
+	Results []struct {
+		RootDomainsToPage int `json:"root_domains_to_page"`
+	} `json:"results"`
+}
+
+// Name implements Provider
+func (m *Moz) Name() string { return "moz" }
+
+// BatchSize implements Provider; url_metrics takes up to 50 targets
+func (m *Moz) BatchSize() int { return 50 }
+
+// ReferringDomains implements Provider
+func (m *Moz) ReferringDomains(ctx context.Context, urls []string) (map[string]int, error) {
+	endpoint := m.Endpoint
+	if endpoint == "" {
+		endpoint = MozEndpoint
+	}
+
+	payload, err := json.Marshal(map[string][]string{"targets": urls})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(m.AccessID, m.SecretKey)
+
+	var resp mozResponse
+	if err := doJSON(m.HTTPClient, req, &resp); err != nil {
+		return nil, err
+	}
+
+	// Results come back in request order
+	counts := make(map[string]int, len(urls))
+	for i, row := range resp.Results {
+		if i < len(urls) {
+			counts[urls[i]] = row.RootDomainsToPage
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,354 @@
+// Package crawldiff compares two crawls of the same site.
+package crawldiff
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/internal/wayback"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// waybackConcurrency limits parallel Wayback Machine lookups
+const waybackConcurrency = 4
+
+// Crawl is one side of a comparison
+type Crawl struct {
+	Name      string
+	UserAgent string // User agent the crawl was made with, "" when unknown
+	Results   []*models.PageResult
+	Issues    []models.Issue
+	RobotsTxt *models.RobotsTxt // The start domain's robots.txt, nil when unknown
+}
+
+// RemovedPage is a page that was reachable in the old crawl but is not now
+type RemovedPage struct {
+	URL       string `json:"url"`
+	OldStatus int    `json:"old_status"`
+	// NewStatus is the page's status in the new crawl, or 0 if the new crawl
+	// did not reach it
+	NewStatus int               `json:"new_status,omitempty"`
+	Archived  *wayback.Snapshot `json:"archived,omitempty"`
+}
+
+// Gone reports whether the page now returns 404 or 410, as opposed to just
+// not being linked any more
+func (p RemovedPage) Gone() bool {
+	return p.NewStatus == http.StatusNotFound || p.NewStatus == http.StatusGone
+}
+
+// StatusChange is a page whose status code changed between crawls
+type StatusChange struct {
+	URL       string `json:"url"`
+	OldStatus int    `json:"old_status"`
+	NewStatus int    `json:"new_status"`
+}
+
+// FieldChange is a page field that differs between crawls
+type FieldChange struct {
+	Field string `json:"field"` // "status", "title", "description", "canonical", "robots", or "words"
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// CloakedPage is a page a site serves differently to the two crawls' user
+// agents, such as a different status code or title for Googlebot
+type CloakedPage struct {
+	URL     string        `json:"url"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// RobotsTxtChange is a change in the start domain's robots.txt between
+// crawls, listing the rules (lines without comments) each side lacks
+type RobotsTxtChange struct {
+	URL       string   `json:"url"`
+	OldStatus int      `json:"old_status"`
+	NewStatus int      `json:"new_status"`
+	OldSHA256 string   `json:"old_sha256,omitempty"`
+	NewSHA256 string   `json:"new_sha256,omitempty"`
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+}
+
+// Summary describes the change in one line
+func (c *RobotsTxtChange) Summary() string {
+	if c.OldStatus != c.NewStatus {
+		return fmt.Sprintf("%s now returns %d (was %d), %d rules added, %d removed", c.URL, c.NewStatus, c.OldStatus, len(c.Added), len(c.Removed))
+	}
+	return fmt.Sprintf("%s changed, %d rules added, %d removed", c.URL, len(c.Added), len(c.Removed))
+}
+
+// Diff is the difference between two crawls
+type Diff struct {
+	Old           string         `json:"old"`
+	New           string         `json:"new"`
+	OldPages      int            `json:"old_pages"`
+	NewPages      int            `json:"new_pages"`
+	OldIndexable  int            `json:"old_indexable"` // Pages search engines can index (see models.PageResult.Indexable)
+	NewIndexable  int            `json:"new_indexable"`
+	AddedPages    []string       `json:"added_pages"`
+	RemovedPages  []RemovedPage  `json:"removed_pages"`
+	StatusChanges []StatusChange `json:"status_changes"`
+	OldIssues     int            `json:"old_issues"`
+	NewIssues     int            `json:"new_issues"`
+	AddedIssues   []models.Issue `json:"added_issues"`
+	FixedIssues   []models.Issue `json:"fixed_issues"`
+
+	// With crawls made as different user agents, such as Googlebot and the
+	// default, the pages both reached that were served differently
+	OldUserAgent string        `json:"old_user_agent,omitempty"`
+	NewUserAgent string        `json:"new_user_agent,omitempty"`
+	Cloaking     []CloakedPage `json:"cloaking,omitempty"`
+
+	// RobotsTxt is set when both crawls kept their robots.txt and it changed
+	RobotsTxt *RobotsTxtChange `json:"robots_txt,omitempty"`
+}
+
+// CloakingWordChange is the relative difference in word count above which
+// a page's content counts as different between user agents, leaving room
+// for rotating blocks such as related posts
+const CloakingWordChange = 0.2
+
+// Compare diffs two crawls. Issues are matched by fingerprint, so a changed
+// message (such as a different --lang) does not count as a change.
+func Compare(before, after Crawl) *Diff {
+	diff := &Diff{
+		Old:           before.Name,
+		New:           after.Name,
+		OldPages:      len(before.Results),
+		NewPages:      len(after.Results),
+		OldIndexable:  countIndexable(before.Results),
+		NewIndexable:  countIndexable(after.Results),
+		AddedPages:    make([]string, 0),
+		RemovedPages:  make([]RemovedPage, 0),
+		StatusChanges: make([]StatusChange, 0),
+		OldIssues:     len(before.Issues),
+		NewIssues:     len(after.Issues),
+		AddedIssues:   make([]models.Issue, 0),
+		FixedIssues:   make([]models.Issue, 0),
+	}
+
+	oldPages := pagesByURL(before.Results)
+	newPages := pagesByURL(after.Results)
+
+	for url := range newPages {
+		if _, ok := oldPages[url]; !ok {
+			diff.AddedPages = append(diff.AddedPages, url)
+		}
+	}
+	for url, oldPage := range oldPages {
+		newPage, ok := newPages[url]
+		if oldPage.StatusCode >= 400 {
+			// Already broken; a change is still worth reporting
+			if ok && newPage.StatusCode != oldPage.StatusCode {
+				diff.StatusChanges = append(diff.StatusChanges, StatusChange{URL: url, OldStatus: oldPage.StatusCode, NewStatus: newPage.StatusCode})
+			}
+			continue
+		}
+		switch {
+		case !ok:
+			diff.RemovedPages = append(diff.RemovedPages, RemovedPage{URL: url, OldStatus: oldPage.StatusCode})
+		case newPage.StatusCode >= 400:
+			diff.RemovedPages = append(diff.RemovedPages, RemovedPage{URL: url, OldStatus: oldPage.StatusCode, NewStatus: newPage.StatusCode})
+		case newPage.StatusCode != oldPage.StatusCode:
+			diff.StatusChanges = append(diff.StatusChanges, StatusChange{URL: url, OldStatus: oldPage.StatusCode, NewStatus: newPage.StatusCode})
+		}
+	}
+
+	oldIssues := issuesByFingerprint(before.Issues)
+	newIssues := issuesByFingerprint(after.Issues)
+	for fingerprint, issue := range newIssues {
+		if _, ok := oldIssues[fingerprint]; !ok {
+			diff.AddedIssues = append(diff.AddedIssues, issue)
+		}
+	}
+	for fingerprint, issue := range oldIssues {
+		if _, ok := newIssues[fingerprint]; !ok {
+			diff.FixedIssues = append(diff.FixedIssues, issue)
+		}
+	}
+
+	if before.UserAgent != "" && after.UserAgent != "" && before.UserAgent != after.UserAgent {
+		diff.OldUserAgent = before.UserAgent
+		diff.NewUserAgent = after.UserAgent
+		diff.Cloaking = make([]CloakedPage, 0)
+		for url, oldPage := range oldPages {
+			if newPage, ok := newPages[url]; ok {
+				if changes := servedDifferently(oldPage, newPage); len(changes) > 0 {
+					diff.Cloaking = append(diff.Cloaking, CloakedPage{URL: url, Changes: changes})
+				}
+			}
+		}
+		sort.Slice(diff.Cloaking, func(i, j int) bool { return diff.Cloaking[i].URL < diff.Cloaking[j].URL })
+	}
+
+	if before.RobotsTxt != nil && after.RobotsTxt != nil {
+		diff.RobotsTxt = compareRobotsTxt(before.RobotsTxt, after.RobotsTxt)
+	}
+
+	sort.Strings(diff.AddedPages)
+	sort.Slice(diff.RemovedPages, func(i, j int) bool { return diff.RemovedPages[i].URL < diff.RemovedPages[j].URL })
+	sort.Slice(diff.StatusChanges, func(i, j int) bool { return diff.StatusChanges[i].URL < diff.StatusChanges[j].URL })
+	sortIssues(diff.AddedIssues)
+	sortIssues(diff.FixedIssues)
+	return diff
+}
+
+// FindArchived looks up the newest Wayback Machine snapshot of every removed
+// page that now returns 404 or 410. Failed lookups are logged and skipped;
+// it returns how many failed.
+func (d *Diff) FindArchived(ctx context.Context, client *wayback.Client) int {
+	var failed atomic.Int32
+	var lookups errgroup.Group
+	lookups.SetLimit(waybackConcurrency)
+	for i := range d.RemovedPages {
+		page := &d.RemovedPages[i]
+		if !page.Gone() {
+			continue
+		}
+		lookups.Go(func() error {
+			snapshot, err := client.Latest(ctx, page.URL)
+			if err != nil {
+				utils.Warn("Wayback Machine lookup failed", utils.NewField("url", page.URL), utils.NewField("error", err.Error()))
+				failed.Add(1)
+				return nil
+			}
+			page.Archived = snapshot
+			return nil
+		})
+	}
+	_ = lookups.Wait()
+	return int(failed.Load())
+}
+
+// compareRobotsTxt returns how a robots.txt changed, or nil when it didn't.
+// Only the status and content count; a comment-only edit still changes the
+// hash and is reported with no added or removed rules.
+func compareRobotsTxt(before, after *models.RobotsTxt) *RobotsTxtChange {
+	if before.StatusCode == after.StatusCode && before.SHA256 == after.SHA256 {
+		return nil
+	}
+	return &RobotsTxtChange{
+		URL:       after.URL,
+		OldStatus: before.StatusCode,
+		NewStatus: after.StatusCode,
+		OldSHA256: before.SHA256,
+		NewSHA256: after.SHA256,
+		Added:     missingLines(after.Rules(), before.Rules()),
+		Removed:   missingLines(before.Rules(), after.Rules()),
+	}
+}
+
+// missingLines returns the lines of a, in order, that b doesn't have,
+// counting repeated lines separately
+func missingLines(a, b []string) []string {
+	counts := make(map[string]int, len(b))
+	for _, line := range b {
+		counts[line]++
+	}
+	missing := make([]string, 0)
+	for _, line := range a {
+		if counts[line] > 0 {
+			counts[line]--
+			continue
+		}
+		missing = append(missing, line)
+	}
+	return missing
+}
+
+// servedDifferently compares what two user agents were served for one page:
+// the status code, the tags search engines read, and roughly how much text
+// there is. The body itself isn't compared, since tokens and timestamps make
+// it differ between any two requests.
+func servedDifferently(oldPage, newPage *models.PageResult) []FieldChange {
+	var changes []FieldChange
+	compare := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+	compare("status", strconv.Itoa(oldPage.StatusCode), strconv.Itoa(newPage.StatusCode))
+	if oldPage.StatusCode >= 300 || newPage.StatusCode >= 300 {
+		return changes
+	}
+	compare("title", oldPage.Title, newPage.Title)
+	compare("description", oldPage.MetaDesc, newPage.MetaDesc)
+	compare("canonical", oldPage.Canonical, newPage.Canonical)
+	compare("robots", robotsSummary(oldPage), robotsSummary(newPage))
+	oldWords, newWords := float64(oldPage.WordCount), float64(newPage.WordCount)
+	if math.Abs(newWords-oldWords) > CloakingWordChange*max(oldWords, newWords) {
+		compare("words", strconv.Itoa(oldPage.WordCount), strconv.Itoa(newPage.WordCount))
+	}
+	return changes
+}
+
+// robotsSummary describes a page's noindex and nofollow directives
+func robotsSummary(page *models.PageResult) string {
+	var directives []string
+	if page.Noindex() {
+		directives = append(directives, "noindex")
+	}
+	if page.Nofollow() {
+		directives = append(directives, "nofollow")
+	}
+	if len(directives) == 0 {
+		return "index, follow"
+	}
+	return strings.Join(directives, ", ")
+}
+
+// pagesByURL indexes results by URL
+func countIndexable(results []*models.PageResult) int {
+	count := 0
+	for _, result := range results {
+		if result.Indexable() {
+			count++
+		}
+	}
+	return count
+}
+
+func pagesByURL(results []*models.PageResult) map[string]*models.PageResult {
+	pages := make(map[string]*models.PageResult, len(results))
+	for _, result := range results {
+		pages[result.URL] = result
+	}
+	return pages
+}
+
+// issuesByFingerprint indexes issues by fingerprint
+func issuesByFingerprint(issues []models.Issue) map[string]models.Issue {
+	byFingerprint := make(map[string]models.Issue, len(issues))
+	for _, issue := range issues {
+		byFingerprint[issue.Fingerprint()] = issue
+	}
+	return byFingerprint
+}
+
+// sortIssues orders issues by severity, then type, then URL
+func sortIssues(issues []models.Issue) {
+	rank := map[models.Severity]int{models.SeverityError: 0, models.SeverityWarning: 1, models.SeverityInfo: 2}
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if rank[a.Severity] != rank[b.Severity] {
+			return rank[a.Severity] < rank[b.Severity]
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.URL != b.URL {
+			return a.URL < b.URL
+		}
+		return a.Value < b.Value
+	})
+}
@@ -0,0 +1,69 @@
+package crawldiff
+
+import (
+	"fmt"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// Regression kinds
+const (
+	RegressionNewErrors     = "new_errors"     // Error-severity issues the old crawl didn't have
+	RegressionIndexableDrop = "indexable_drop" // Fewer pages search engines can index
+	RegressionRobotsTxt     = "robots_txt"     // The start domain's robots.txt changed
+)
+
+// Thresholds decide which changes between two crawls are regressions. A
+// zero field disables that check.
+type Thresholds struct {
+	NewErrors     int     // Minimum number of new error-severity issues
+	IndexableDrop float64 // Minimum drop in indexable pages, in percent
+	RobotsTxt     bool    // Whether any robots.txt change counts
+}
+
+// Regression is a change between two crawls worth alerting on
+type Regression struct {
+	Kind    string         `json:"kind"`
+	Message string         `json:"message"`
+	Issues  []models.Issue `json:"issues,omitempty"` // The new error issues, for new_errors
+}
+
+// Regressions returns the changes in the diff that cross the thresholds
+func (d *Diff) Regressions(t Thresholds) []Regression {
+	var regressions []Regression
+
+	if t.NewErrors > 0 {
+		var errors []models.Issue
+		for _, issue := range d.AddedIssues {
+			if issue.Severity == models.SeverityError {
+				errors = append(errors, issue)
+			}
+		}
+		if len(errors) >= t.NewErrors {
+			regressions = append(regressions, Regression{
+				Kind:    RegressionNewErrors,
+				Message: fmt.Sprintf("%d new error-severity issues", len(errors)),
+				Issues:  errors,
+			})
+		}
+	}
+
+	if t.IndexableDrop > 0 && d.OldIndexable > 0 && d.NewIndexable < d.OldIndexable {
+		drop := float64(d.OldIndexable-d.NewIndexable) / float64(d.OldIndexable) * 100
+		if drop >= t.IndexableDrop {
+			regressions = append(regressions, Regression{
+				Kind:    RegressionIndexableDrop,
+				Message: fmt.Sprintf("Indexable pages fell %.0f%%, from %d to %d", drop, d.OldIndexable, d.NewIndexable),
+			})
+		}
+	}
+
+	if t.RobotsTxt && d.RobotsTxt != nil {
+		regressions = append(regressions, Regression{
+			Kind:    RegressionRobotsTxt,
+			Message: d.RobotsTxt.Summary(),
+		})
+	}
+
+	return regressions
+}
@@ -0,0 +1,285 @@
+package crawldir
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// DefaultParent is the directory crawl runs are created under by default
+const DefaultParent = "crawls"
+
+// Standard file names inside a crawl directory
+const (
+	GraphFile    = "graph.json"
+	AnchorsFile  = "anchors.csv" // Anchor text of the internal links to each page
+	SummaryFile  = "summary.json"
+	IssuesFile   = "issues.json"
+	LogFile      = "crawl.log"
+	EventsFile   = "events.ndjson" // Crawled, failed, and skipped URLs, one JSON object per line
+	MetadataFile = "metadata.json"
+	StateFile    = "state.json"  // Checkpoint of an unfinished crawl, for crawl --resume
+	AlertsFile   = "alerts.json" // Regressions against the previous scheduled run, when any
+	HTMLDir      = "html"        // Raw HTML snapshots, when saved
+
+	EnrichedIssuesFile = "gsc_issues.json" // Issues enriched with Search Console data by serve, when any
+)
+
+// timestampLayout is the suffix of every crawl directory name
+const timestampLayout = "2006-01-02_15-04-05"
+
+// ResultsFile returns the results file name for an export format
+func ResultsFile(format string) string {
+	return "results." + format
+}
+
+// Metadata describes a crawl run and the configuration it used
+type Metadata struct {
+	Version     string                `json:"version"`
+	URL         string                `json:"url"`
+	Status      string                `json:"status"` // "running", "succeeded", "interrupted", or "failed"
+	Error       string                `json:"error,omitempty"`
+	StartedAt   time.Time             `json:"started_at"`
+	CompletedAt time.Time             `json:"completed_at,omitempty"`
+	TotalPages  int                   `json:"total_pages"`
+	TotalIssues int                   `json:"total_issues"`
+	Hosts       []HostStats           `json:"hosts,omitempty"`
+	Config      utils.CrawlFileConfig `json:"config"`
+
+	// RobotsTxt is the start domain's robots.txt when the crawl ran, so a
+	// diff can tell when its rules changed
+	RobotsTxt *models.RobotsTxt `json:"robots_txt,omitempty"`
+}
+
+// HostStats summarizes the pages crawled on one host
+type HostStats struct {
+	Host            string `json:"host"`
+	Pages           int    `json:"pages"`
+	Errors          int    `json:"errors"` // Fetch errors and 4xx/5xx responses
+	AvgResponseTime int64  `json:"avg_response_time_ms"`
+}
+
+// HostStatsFor groups results by host, busiest host first
+func HostStatsFor(results []*models.PageResult) []HostStats {
+	var tally HostTally
+	for _, result := range results {
+		tally.Add(result)
+	}
+	return tally.Stats()
+}
+
+// HostTally groups pages by host as they are crawled, for crawls that don't
+// keep their results. It is safe for concurrent use.
+type HostTally struct {
+	mu     sync.Mutex
+	byHost map[string]*HostStats
+	totals map[string]int64
+}
+
+// Add counts a crawled page
+func (t *HostTally) Add(result *models.PageResult) {
+	host, err := utils.ExtractDomain(result.URL)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.byHost == nil {
+		t.byHost = make(map[string]*HostStats)
+		t.totals = make(map[string]int64)
+	}
+	stats, ok := t.byHost[host]
+	if !ok {
+		stats = &HostStats{Host: host}
+		t.byHost[host] = stats
+	}
+	stats.Pages++
+	if result.Error != "" || result.StatusCode >= 400 {
+		stats.Errors++
+	}
+	t.totals[host] += result.ResponseTime
+}
+
+// Stats returns the pages counted so far by host, busiest host first
+func (t *HostTally) Stats() []HostStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	hosts := make([]HostStats, 0, len(t.byHost))
+	for host, stats := range t.byHost {
+		stats := *stats
+		stats.AvgResponseTime = t.totals[host] / int64(stats.Pages)
+		hosts = append(hosts, stats)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].Pages != hosts[j].Pages {
+			return hosts[i].Pages > hosts[j].Pages
+		}
+		return hosts[i].Host < hosts[j].Host
+	})
+	return hosts
+}
+
+// Run is a crawl directory found on disk
+type Run struct {
+	Path     string
+	Name     string
+	Domain   string
+	Time     time.Time
+	Metadata *Metadata // nil when metadata.json is missing or unreadable
+}
+
+// Path returns the directory for a crawl of domain started at t
+func Path(parent, domain string, t time.Time) string {
+	return filepath.Join(parent, fmt.Sprintf("%s_%s", domain, t.Format(timestampLayout)))
+}
+
+// Create makes a new crawl directory and returns its path
+func Create(parent, domain string, t time.Time) (string, error) {
+	dir := Path(parent, domain, t)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create crawl directory: %w", err)
+	}
+	return dir, nil
+}
+
+// parseName splits a crawl directory name into its domain and timestamp
+func parseName(name string) (string, time.Time, bool) {
+	if len(name) <= len(timestampLayout)+1 || name[len(name)-len(timestampLayout)-1] != '_' {
+		return "", time.Time{}, false
+	}
+	domain := name[:len(name)-len(timestampLayout)-1]
+	t, err := time.ParseInLocation(timestampLayout, name[len(name)-len(timestampLayout):], time.Local)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return domain, t, true
+}
+
+// List returns every crawl directory under parent, newest first
+func List(parent string) ([]Run, error) {
+	entries, err := os.ReadDir(parent)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crawl directory: %w", err)
+	}
+
+	runs := make([]Run, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		domain, t, ok := parseName(entry.Name())
+		if !ok {
+			continue
+		}
+		path := filepath.Join(parent, entry.Name())
+		meta, _ := ReadMetadata(path)
+		runs = append(runs, Run{
+			Path:     path,
+			Name:     entry.Name(),
+			Domain:   domain,
+			Time:     t,
+			Metadata: meta,
+		})
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].Time.After(runs[j].Time)
+	})
+	return runs, nil
+}
+
+// Find resolves a run by directory name, path, or unique name prefix
+func Find(parent, ref string) (*Run, error) {
+	runs, err := List(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	ref = strings.TrimSuffix(filepath.Base(ref), string(filepath.Separator))
+	var matches []Run
+	for _, run := range runs {
+		if run.Name == ref {
+			return &run, nil
+		}
+		if strings.HasPrefix(run.Name, ref) {
+			matches = append(matches, run)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no crawl matching %q in %s", ref, parent)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("%q matches %d crawls; be more specific", ref, len(matches))
+	}
+}
+
+// Prune removes the oldest crawl directories for a domain so that at most
+// keep directories remain. It returns the paths that were removed.
+func Prune(parent, domain string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+
+	runs, err := List(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	kept := 0
+	for _, run := range runs {
+		if run.Domain != domain {
+			continue
+		}
+		if kept < keep {
+			kept++
+			continue
+		}
+		if err := os.RemoveAll(run.Path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", run.Path, err)
+		}
+		removed = append(removed, run.Path)
+	}
+
+	return removed, nil
+}
+
+// WriteMetadata writes metadata.json into dir
+func WriteMetadata(dir string, meta *Metadata) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, MetadataFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	return nil
+}
+
+// ReadMetadata reads metadata.json from dir
+func ReadMetadata(dir string) (*Metadata, error) {
+	data, err := os.ReadFile(filepath.Join(dir, MetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	var meta Metadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+	return &meta, nil
+}
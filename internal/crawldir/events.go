@@ -0,0 +1,93 @@
+package crawldir
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// EventLog appends crawl events to a crawl directory's EventsFile. It is
+// safe for concurrent use.
+type EventLog struct {
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	err     error // First write error; later events are dropped
+}
+
+// OpenEventLog opens the events file of a crawl directory for appending, so
+// a resumed crawl continues the log of the interrupted one
+func OpenEventLog(dir string) (*EventLog, error) {
+	file, err := os.OpenFile(filepath.Join(dir, EventsFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	writer := bufio.NewWriter(file)
+	return &EventLog{file: file, writer: writer, encoder: json.NewEncoder(writer)}, nil
+}
+
+// Write appends an event. Errors are kept and returned by Close.
+func (l *EventLog) Write(event models.CrawlEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = l.encoder.Encode(event)
+	}
+}
+
+// Close flushes and closes the file, returning the first error
+func (l *EventLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = l.writer.Flush()
+	}
+	if err := l.file.Close(); l.err == nil {
+		l.err = err
+	}
+	if l.err != nil {
+		return fmt.Errorf("failed to write event log: %w", l.err)
+	}
+	return nil
+}
+
+// ReadEvents returns the events of a crawl directory that match filter, in
+// the order they were logged. A directory without an events file has none.
+func ReadEvents(dir string, filter models.CrawlEventFilter) ([]models.CrawlEvent, error) {
+	events := make([]models.CrawlEvent, 0)
+	file, err := os.Open(filepath.Join(dir, EventsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return events, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event models.CrawlEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue // A line cut short by a crash
+		}
+		if !filter.Matches(event) {
+			continue
+		}
+		events = append(events, event)
+		if filter.Limit > 0 && len(events) >= filter.Limit {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	return events, nil
+}
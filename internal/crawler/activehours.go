@@ -0,0 +1,47 @@
+package crawler
+
+import (
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// activeHoursRecheck is the longest a paused worker sleeps before looking at
+// the clock again, so a suspended machine or a clock change can't oversleep
+// the window
+const activeHoursRecheck = time.Minute
+
+// waitActiveHours blocks while the time is outside the crawl's active hours.
+// The first caller of a pause reports it to the pause hooks. It returns false
+// if the crawl is stopped meanwhile.
+func (m *Manager) waitActiveHours() bool {
+	if m.activeHours == nil {
+		return true
+	}
+	for {
+		now := time.Now()
+		if m.activeHours.Contains(now) {
+			return true
+		}
+		resume := m.activeHours.NextStart(now)
+		m.pauseMu.Lock()
+		if !m.pausedUntil.Equal(resume) {
+			m.pausedUntil = resume
+			utils.Info("Outside active hours; pausing crawl",
+				utils.NewField("active_hours", m.activeHours.String()),
+				utils.NewField("resume_at", resume.Format(time.RFC3339)))
+			for _, hook := range m.hooks.pause {
+				hook(resume)
+			}
+		}
+		m.pauseMu.Unlock()
+
+		timer := time.NewTimer(min(time.Until(resume), activeHoursRecheck))
+		select {
+		case <-m.ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
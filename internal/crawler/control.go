@@ -0,0 +1,211 @@
+package crawler
+
+import (
+	"fmt"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// Pause stops workers from taking new pages until Resume is called. Pages
+// already being fetched finish, and checkpoints continue, so a paused crawl
+// can still be stopped and resumed from its state file. Pausing a paused
+// crawl does nothing.
+func (m *Manager) Pause() {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	if m.paused {
+		return
+	}
+	m.paused = true
+	m.controlChanged()
+	utils.Info("Crawl paused")
+}
+
+// Resume lets the workers of a paused crawl carry on
+func (m *Manager) Resume() {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	if !m.paused {
+		return
+	}
+	m.paused = false
+	m.controlChanged()
+	utils.Info("Crawl resumed")
+}
+
+// Paused reports whether the crawl is paused by Pause. Pauses outside active
+// hours are reported to the OnPause hooks instead.
+func (m *Manager) Paused() bool {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	return m.paused
+}
+
+// UpdateConcurrency changes how many workers fetch pages at once. It can be
+// called before or during a crawl. Workers are started as needed; workers
+// above the new count finish their current page and then wait, in case the
+// count is raised again.
+func (m *Manager) UpdateConcurrency(workers int) error {
+	if workers < 1 {
+		return fmt.Errorf("concurrency must be at least 1")
+	}
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	if workers == m.workerLimit {
+		return nil
+	}
+	m.workerLimit = workers
+	m.controlChanged()
+	// Only add workers while others are running: once they have all exited
+	// the crawl is over, and the group may already have been waited on
+	if m.runningWorkers > 0 {
+		for m.startedWorkers < workers {
+			m.startWorker()
+		}
+	}
+	utils.Info("Crawl concurrency changed", utils.NewField("workers", workers))
+	return nil
+}
+
+// startWorkers starts the crawl's workers, as many as the concurrency set by
+// the config or UpdateConcurrency
+func (m *Manager) startWorkers() {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	for m.startedWorkers < m.workerLimit {
+		m.startWorker()
+	}
+}
+
+// startWorker starts one more worker. controlMu must be held.
+func (m *Manager) startWorker() {
+	id := m.startedWorkers
+	m.startedWorkers++
+	m.runningWorkers++
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		m.worker(id)
+		m.controlMu.Lock()
+		m.runningWorkers--
+		m.controlMu.Unlock()
+	}()
+}
+
+// controlChanged wakes the workers waiting on a pause or the concurrency
+// limit so they check it again. controlMu must be held.
+func (m *Manager) controlChanged() {
+	if m.controlWake != nil {
+		close(m.controlWake)
+	}
+	m.controlWake = make(chan struct{})
+}
+
+// releaseWorkers wakes the workers waiting on a pause or the concurrency
+// limit once the queue is closed, so they find it empty and exit rather than
+// keep the crawl from returning
+func (m *Manager) releaseWorkers() {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	m.queueClosed = true
+	m.controlChanged()
+}
+
+// waitControl blocks while the crawl is paused or, with limited set, while
+// the worker's id is at or above the concurrency limit. It returns false if
+// the crawl is stopped meanwhile, and true once the queue is closed.
+func (m *Manager) waitControl(id int, limited bool) bool {
+	for {
+		m.controlMu.Lock()
+		run := m.queueClosed || (!m.paused && (!limited || id < m.workerLimit))
+		wake := m.controlWake
+		m.controlMu.Unlock()
+		if run {
+			return true
+		}
+		select {
+		case <-m.ctx.Done():
+			return false
+		case <-wake:
+		}
+	}
+}
+
+// CrawlStatus is where a crawl stands: running, or how it ended
+type CrawlStatus string
+
+const (
+	StatusPending     CrawlStatus = "pending"     // Crawl hasn't been called
+	StatusRunning     CrawlStatus = "running"     // Paused crawls too
+	StatusSucceeded   CrawlStatus = "succeeded"   // Ran until no pages were left or a limit was reached
+	StatusCancelled   CrawlStatus = "cancelled"   // Ended by Cancel
+	StatusInterrupted CrawlStatus = "interrupted" // Ended by a signal or Stop, and resumable from a state file
+	StatusFailed      CrawlStatus = "failed"      // Crawl returned an error
+)
+
+// Cancel ends a running crawl for reason, such as a user's request; Crawl
+// returns the pages fetched so far, and its status is StatusCancelled. Unlike
+// Stop, the crawl isn't meant to be resumed, so its state file is removed.
+// Cancel takes precedence over Stop and signals, whichever came first. Only
+// the first reason given is kept, and once the crawl has ended Cancel does
+// nothing.
+func (m *Manager) Cancel(reason string) {
+	m.controlMu.Lock()
+	if m.settled {
+		m.controlMu.Unlock()
+		return
+	}
+	if !m.cancelled {
+		m.cancelled = true
+		m.cancelReason = reason
+		utils.Info("Crawl cancelled", utils.NewField("reason", reason))
+	}
+	m.controlMu.Unlock()
+	m.cancel()
+}
+
+// Status returns where the crawl stands. Once Crawl has returned, it is how
+// the crawl ended, and the OnCrawlComplete hooks see it too.
+func (m *Manager) Status() CrawlStatus {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	return m.status
+}
+
+// CancelReason returns the reason given to Cancel, or "" when it wasn't
+// called
+func (m *Manager) CancelReason() string {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	return m.cancelReason
+}
+
+// settle fixes how the crawl ended, before its state file is saved or
+// removed, so a late Cancel can't leave a saved state behind a cancelled
+// status
+func (m *Manager) settle() {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	m.settled = true
+}
+
+// setStatus records the crawl as started, or as ended with err
+func (m *Manager) setStatus(running bool, err error) {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	if !running {
+		m.settled = true
+	}
+	switch {
+	case running:
+		m.status = StatusRunning
+	case m.cancelled:
+		m.status = StatusCancelled
+	case m.interrupted.Load():
+		m.status = StatusInterrupted
+	case err != nil:
+		m.status = StatusFailed
+	default:
+		m.status = StatusSucceeded
+	}
+}
@@ -0,0 +1,299 @@
+package crawler
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// testConfig returns a config crawling startURL without robots.txt or
+// sitemaps
+func testConfig(startURL string, workers int) *utils.Config {
+	config := utils.DefaultConfig()
+	config.StartURL = startURL
+	config.MaxPages = 1000
+	config.MaxDepth = 1000
+	config.Workers = workers
+	config.RespectRobots = false
+	config.ParseSitemap = false
+	return config
+}
+
+// crawlAsync runs Crawl in the background; the returned function waits for
+// it, failing the test if it doesn't return in time
+func crawlAsync(t *testing.T, m *Manager) func() ([]*models.PageResult, error) {
+	t.Helper()
+	type outcome struct {
+		results []*models.PageResult
+		err     error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		results, err := m.Crawl()
+		done <- outcome{results, err}
+	}()
+	return func() ([]*models.PageResult, error) {
+		t.Helper()
+		select {
+		case o := <-done:
+			return o.results, o.err
+		case <-time.After(30 * time.Second):
+			t.Fatal("Crawl didn't return")
+			return nil, nil
+		}
+	}
+}
+
+func TestUpdateConcurrencyDuringCrawl(t *testing.T) {
+	site := newSlowSite(t, 60)
+	m := NewManager(testConfig(site.URL, 4))
+	wait := crawlAsync(t, m)
+
+	// Lowered, workers 1-3 park; the crawl must still finish and return
+	time.Sleep(30 * time.Millisecond)
+	if err := m.UpdateConcurrency(1); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	site.maxInFlight.Store(0)
+	time.Sleep(50 * time.Millisecond)
+	if got := site.maxInFlight.Load(); got > 1 {
+		t.Errorf("%d requests in flight after lowering concurrency to 1", got)
+	}
+
+	if err := m.UpdateConcurrency(6); err != nil {
+		t.Fatal(err)
+	}
+	results, err := wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != site.pages+1 {
+		t.Errorf("crawled %d pages, want %d", len(results), site.pages+1)
+	}
+	if got := site.maxInFlight.Load(); got < 2 || got > 6 {
+		t.Errorf("up to %d requests in flight after raising concurrency to 6, want 2-6", got)
+	}
+}
+
+func TestUpdateConcurrencyLoweredUntilEnd(t *testing.T) {
+	site := newSlowSite(t, 40)
+	m := NewManager(testConfig(site.URL, 4))
+	wait := crawlAsync(t, m)
+
+	// Workers 1-3 are still parked when the queue closes
+	time.Sleep(30 * time.Millisecond)
+	if err := m.UpdateConcurrency(1); err != nil {
+		t.Fatal(err)
+	}
+	results, err := wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != site.pages+1 {
+		t.Errorf("crawled %d pages, want %d", len(results), site.pages+1)
+	}
+}
+
+func TestUpdateConcurrencyBeforeCrawl(t *testing.T) {
+	site := newSlowSite(t, 30)
+	m := NewManager(testConfig(site.URL, 4))
+	if err := m.UpdateConcurrency(1); err != nil {
+		t.Fatal(err)
+	}
+	results, err := crawlAsync(t, m)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != site.pages+1 {
+		t.Errorf("crawled %d pages, want %d", len(results), site.pages+1)
+	}
+	if got := site.maxInFlight.Load(); got != 1 {
+		t.Errorf("up to %d requests in flight with concurrency 1", got)
+	}
+}
+
+func TestPauseAndResume(t *testing.T) {
+	site := newSlowSite(t, 40)
+	m := NewManager(testConfig(site.URL, 3))
+	wait := crawlAsync(t, m)
+
+	time.Sleep(30 * time.Millisecond)
+	m.Pause()
+	if !m.Paused() {
+		t.Error("Paused() is false after Pause")
+	}
+	// Requests already underway finish
+	time.Sleep(50 * time.Millisecond)
+	paused := site.requests.Load()
+	time.Sleep(100 * time.Millisecond)
+	if got := site.requests.Load(); got != paused {
+		t.Errorf("%d requests made while paused", got-paused)
+	}
+
+	m.Resume()
+	results, err := wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != site.pages+1 {
+		t.Errorf("crawled %d pages, want %d", len(results), site.pages+1)
+	}
+}
+
+func TestUpdateConcurrencyRejectsZero(t *testing.T) {
+	m := NewManager(utils.DefaultConfig())
+	if err := m.UpdateConcurrency(0); err == nil {
+		t.Error("UpdateConcurrency(0) should fail")
+	}
+}
+
+// startedCrawl starts crawling a site of pages with a state file, and waits
+// until a few of them have been requested
+func startedCrawl(t *testing.T, pages int) (m *Manager, wait func() ([]*models.PageResult, error), stateFile string) {
+	t.Helper()
+	site := newSlowSite(t, pages)
+	m = NewManager(testConfig(site.URL, 2))
+	stateFile = filepath.Join(t.TempDir(), "state.json")
+	m.SetStateFile(stateFile, 0)
+	wait = crawlAsync(t, m)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for site.requests.Load() < 5 {
+		if time.Now().After(deadline) {
+			t.Fatal("crawl didn't start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return m, wait, stateFile
+}
+
+func stateFileExists(t *testing.T, path string) bool {
+	t.Helper()
+	_, err := os.Stat(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func TestStatusSucceeded(t *testing.T) {
+	site := newSlowSite(t, 10)
+	m := NewManager(testConfig(site.URL, 2))
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	m.SetStateFile(stateFile, time.Millisecond)
+	if m.Status() != StatusPending {
+		t.Errorf("status %q before Crawl, want %q", m.Status(), StatusPending)
+	}
+
+	if _, err := crawlAsync(t, m)(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Status() != StatusSucceeded {
+		t.Errorf("status %q, want %q", m.Status(), StatusSucceeded)
+	}
+	if stateFileExists(t, stateFile) {
+		t.Error("state file kept after the crawl finished")
+	}
+
+	// The crawl has ended, so there is nothing left to cancel
+	m.Cancel("too late")
+	if m.Status() != StatusSucceeded || m.CancelReason() != "" {
+		t.Errorf("Cancel after the crawl changed it to %q, reason %q", m.Status(), m.CancelReason())
+	}
+}
+
+func TestStatusCancelled(t *testing.T) {
+	m, wait, stateFile := startedCrawl(t, 200)
+	m.Cancel("wrong start URL")
+	m.Cancel("second reason")
+
+	results, err := wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 || len(results) == 201 {
+		t.Errorf("crawled %d pages, want the ones fetched before Cancel", len(results))
+	}
+	if m.Status() != StatusCancelled {
+		t.Errorf("status %q, want %q", m.Status(), StatusCancelled)
+	}
+	if got := m.CancelReason(); got != "wrong start URL" {
+		t.Errorf("cancel reason %q, want the first one given", got)
+	}
+	if m.Interrupted() {
+		t.Error("a cancelled crawl is reported as interrupted")
+	}
+	if stateFileExists(t, stateFile) {
+		t.Error("state file kept after Cancel")
+	}
+}
+
+func TestStatusInterrupted(t *testing.T) {
+	m, wait, stateFile := startedCrawl(t, 200)
+	m.Stop()
+
+	if _, err := wait(); err != nil {
+		t.Fatal(err)
+	}
+	if m.Status() != StatusInterrupted {
+		t.Errorf("status %q, want %q", m.Status(), StatusInterrupted)
+	}
+	if !m.Interrupted() {
+		t.Error("a stopped crawl isn't reported as interrupted")
+	}
+	if !stateFileExists(t, stateFile) {
+		t.Error("no state file saved after Stop")
+	}
+}
+
+func TestStatusCancelWinsOverStop(t *testing.T) {
+	for _, order := range []string{"stop first", "cancel first"} {
+		t.Run(order, func(t *testing.T) {
+			m, wait, stateFile := startedCrawl(t, 200)
+			if order == "stop first" {
+				m.Stop()
+				m.Cancel("user request")
+			} else {
+				m.Cancel("user request")
+				m.Stop()
+			}
+
+			if _, err := wait(); err != nil {
+				t.Fatal(err)
+			}
+			if m.Status() != StatusCancelled {
+				t.Errorf("status %q, want %q", m.Status(), StatusCancelled)
+			}
+			if m.Interrupted() {
+				t.Error("a cancelled crawl is reported as interrupted")
+			}
+			if stateFileExists(t, stateFile) {
+				t.Error("state file saved for a cancelled crawl")
+			}
+		})
+	}
+}
+
+func TestStatusFailed(t *testing.T) {
+	// The login page has no form, so signing in fails before any page is
+	// crawled
+	site := newSlowSite(t, 10)
+	config := testConfig(site.URL, 2)
+	config.LoginURL = site.URL + "/p/0"
+	config.LoginFields = []string{"email=me@example.com"}
+	config.LoginSuccess = "a.logout"
+	m := NewManager(config)
+
+	if _, err := crawlAsync(t, m)(); err == nil {
+		t.Fatal("Crawl didn't return the login error")
+	}
+	if m.Status() != StatusFailed {
+		t.Errorf("status %q, want %q", m.Status(), StatusFailed)
+	}
+}
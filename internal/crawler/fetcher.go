@@ -1,111 +1,309 @@
 package crawler
 
 import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net"
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptrace"
+	neturl "net/url"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/dillonlara115/barracuda/internal/utils"
 	"github.com/dillonlara115/barracuda/pkg/models"
+	"golang.org/x/net/publicsuffix"
 )
 
 // Fetcher handles HTTP requests and response processing
 type Fetcher struct {
 	client    *http.Client
+	transport *http.Transport // The client's, shared by all workers so connections are reused
 	userAgent string
+	renderer  *Renderer    // Renders HTML pages in a headless browser (nil fetches over plain HTTP only)
+	limiter   *rateLimiter // Paces retries and learns from responses (nil when not crawling)
+	headers   siteHeaders  // Extra headers, such as credentials, for the crawled site
+	cache     *HTTPCache   // Revalidates pages fetched by an earlier crawl (nil fetches every page in full)
+
+	maxBodySize int64 // Bytes of a page body read before it is cut off (0: no limit)
+}
+
+// siteHeaders are extra request headers that are only sent to the crawled
+// sites' domains, so credentials for them never reach other hosts
+type siteHeaders struct {
+	header http.Header
+	sites  []string // URLs on the domains that receive the headers
+}
+
+// forURL returns the headers to add to a request for url, or nil
+func (h siteHeaders) forURL(url string) http.Header {
+	if len(h.header) == 0 {
+		return nil
+	}
+	for _, site := range h.sites {
+		if utils.IsSameDomain(url, site) {
+			return h.header
+		}
+	}
+	return nil
 }
 
 // FetchResult contains the fetched page data
 type FetchResult struct {
-	PageResult *models.PageResult
-	Body       []byte
-	Error      error
+	PageResult  *models.PageResult
+	Body        []byte
+	ContentType string
+	Error       error
+}
+
+// TransportOptions tunes the connections a Fetcher keeps to the sites it
+// fetches from
+type TransportOptions struct {
+	MaxConnsPerHost   int  // Connections open to one host at a time, including idle ones (0: no limit)
+	IdleConnsPerHost  int  // Idle connections kept open to one host for reuse (0: the net/http default of 2)
+	DisableHTTP2      bool // Speak HTTP/1.1 only, even to servers that offer HTTP/2
+	DisableKeepAlives bool // Open a new connection for every request
 }
 
-// NewFetcher creates a new Fetcher instance
+// redirectChainKey is the request context key for the *[]string that
+// collects redirect destinations during a fetch
+type redirectChainKey struct{}
+
+// NewFetcher creates a new Fetcher instance. Its transport negotiates
+// HTTP/2 and keeps connections alive; SetTransportOptions tunes it.
 func NewFetcher(timeout time.Duration, userAgent string) *Fetcher {
+	// A clone rather than http.DefaultTransport, so tuning it affects no
+	// other client in the process
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 	client := &http.Client{
-		Timeout: timeout,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			// Follow redirects up to 10 times
-			if len(via) >= 10 {
-				return fmt.Errorf("stopped after 10 redirects")
-			}
-			return nil
-		},
+		Timeout:   timeout,
+		Transport: transport,
+	}
+
+	f := &Fetcher{
+		client:      client,
+		transport:   transport,
+		userAgent:   userAgent,
+		maxBodySize: utils.DefaultMaxBodySize,
+	}
+	client.CheckRedirect = f.checkRedirect
+	return f
+}
+
+// checkRedirect is the client's CheckRedirect: it follows up to 10 redirects
+// and records each destination in the fetch's redirect chain. The client
+// copies a request's headers onto the redirects it follows, so the site
+// headers are removed from a hop off the sites' domains and set again on a
+// hop back to them.
+func (f *Fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	// req is the request about to be made to follow the redirect, so its URL
+	// is the redirect destination
+	if chain, ok := req.Context().Value(redirectChainKey{}).(*[]string); ok {
+		*chain = append(*chain, req.URL.String())
+	}
+
+	if extra := f.headers.forURL(req.URL.String()); extra != nil {
+		for name, values := range extra {
+			req.Header[name] = values
+		}
+	} else {
+		for name := range f.headers.header {
+			delete(req.Header, name)
+		}
+	}
+
+	// Follow redirects up to 10 times
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	return nil
+}
+
+// SetMaxBodySize stops reading a page's body after size bytes, so a huge
+// response can't exhaust memory. Pass 0 for no limit. Fetch, used for
+// robots.txt and sitemaps, always reads the whole body.
+func (f *Fetcher) SetMaxBodySize(size int64) {
+	f.maxBodySize = size
+}
+
+// SetRenderer makes FetchWithRetry render HTML pages with r. Pass nil to
+// fetch over plain HTTP only.
+func (f *Fetcher) SetRenderer(r *Renderer) {
+	f.renderer = r
+}
+
+// SetHTTPCache makes FetchWithRetry revalidate pages kept in c from an
+// earlier crawl, reusing their cached body when the server answers 304 Not
+// Modified, and keep the pages it fetches in full there. Pass nil to fetch
+// every page in full.
+func (f *Fetcher) SetHTTPCache(c *HTTPCache) {
+	f.cache = c
+}
+
+// SetTransportOptions tunes the fetcher's connections. Like the other
+// setters, it must be called before fetching.
+func (f *Fetcher) SetTransportOptions(opts TransportOptions) {
+	f.transport.MaxConnsPerHost = opts.MaxConnsPerHost
+	if opts.IdleConnsPerHost > 0 {
+		f.transport.MaxIdleConnsPerHost = opts.IdleConnsPerHost
+		f.transport.MaxIdleConns = max(f.transport.MaxIdleConns, opts.IdleConnsPerHost)
+	}
+	if opts.MaxConnsPerHost > 0 && f.transport.MaxIdleConnsPerHost > opts.MaxConnsPerHost {
+		f.transport.MaxIdleConnsPerHost = opts.MaxConnsPerHost
 	}
+	f.transport.DisableKeepAlives = opts.DisableKeepAlives
+	if opts.DisableHTTP2 {
+		// A non-nil, empty TLSNextProto turns off HTTP/2 negotiation
+		f.transport.ForceAttemptHTTP2 = false
+		f.transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+}
 
-	return &Fetcher{
-		client:    client,
-		userAgent: userAgent,
+// SetHostRewrites connects to another host for the URLs on each host in
+// rewrites (keyed by lowercase host name), such as a staging server for a
+// site whose links use absolute production URLs. Requests keep the original
+// host in their URL, Host header, and TLS server name. A target without a
+// port keeps the URL's port.
+func (f *Fetcher) SetHostRewrites(rewrites map[string]string) {
+	if len(rewrites) == 0 {
+		return
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	f.transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, rewriteAddr(addr, rewrites))
+	}
+}
+
+// rewriteAddr returns the host:port to dial for addr under rewrites
+func rewriteAddr(addr string, rewrites map[string]string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	target, ok := rewrites[strings.ToLower(host)]
+	if !ok {
+		return addr
+	}
+	if _, _, err := net.SplitHostPort(target); err == nil {
+		return target
 	}
+	return net.JoinHostPort(target, port)
+}
+
+// SetHeaders adds header to every request for a URL on the domain of one of
+// sites, such as the cookies or credentials of a staging site. They replace
+// default headers of the same name.
+func (f *Fetcher) SetHeaders(header http.Header, sites ...string) {
+	f.headers = siteHeaders{header: header, sites: sites}
+}
+
+// EnableCookies keeps the cookies responses set and sends them back, as a
+// browser would, so a session started by a login lasts for the crawl
+func (f *Fetcher) EnableCookies() error {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return fmt.Errorf("failed to create cookie jar: %w", err)
+	}
+	f.client.Jar = jar
+	return nil
 }
 
 // Fetch retrieves a URL and returns the response (single attempt, no retry)
 func (f *Fetcher) Fetch(url string) *FetchResult {
+	return f.do(http.MethodGet, url, nil, false)
+}
+
+// Submit sends form values to url, in the body of a POST or the query of a
+// GET, the way a browser submits a form. Redirects are followed.
+func (f *Fetcher) Submit(method, url string, values neturl.Values) *FetchResult {
+	if method == http.MethodGet {
+		separator := "?"
+		if strings.Contains(url, "?") {
+			separator = "&"
+		}
+		return f.do(http.MethodGet, url+separator+values.Encode(), nil, false)
+	}
+	return f.do(http.MethodPost, url, values, false)
+}
+
+// do sends one request, with form values as its body when set. For a page,
+// the body is only read when it is HTML, and then up to the max body size.
+func (f *Fetcher) do(method, url string, form neturl.Values, page bool) *FetchResult {
 	result := &FetchResult{
 		PageResult: &models.PageResult{
-			URL:       url,
-			CrawledAt: time.Now(),
+			SchemaVersion: models.PageResultSchemaVersion,
+			URL:           url,
+			CrawledAt:     time.Now(),
 		},
 	}
 
 	startTime := time.Now()
 
-	req, err := http.NewRequest("GET", url, nil)
+	var requestBody io.Reader
+	if form != nil {
+		requestBody = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, url, requestBody)
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create request: %w", err)
 		result.PageResult.Error = result.Error.Error()
+		result.PageResult.ErrorCode = models.ErrorOther
 		return result
 	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 
 	req.Header.Set("User-Agent", f.userAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	for name, values := range f.headers.forURL(url) {
+		req.Header[name] = values
+	}
 
-	// Track redirect chain using CheckRedirect callback
-	// CheckRedirect is called when the HTTP client encounters a redirect response
-	var redirectChain []string
-	originalCheckRedirect := f.client.CheckRedirect
-	
-	// Temporarily override CheckRedirect to capture redirect URLs
-	f.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-		// When CheckRedirect is called:
-		// - 'via' contains all previous requests (via[0] = original request)
-		// - 'req' is the NEW request about to be made to follow the redirect
-		// - The redirect response came from the last request in 'via'
-		// 
-		// We want to capture the redirect destinations (the URLs we're redirecting TO)
-		// Each time CheckRedirect is called, we're following a redirect, so we capture req.URL
-		if len(via) > 0 {
-			// This is a redirect - capture the destination URL
-			redirectChain = append(redirectChain, req.URL.String())
+	// Revalidate a page cached by an earlier crawl instead of downloading it
+	var cached *httpCacheEntry
+	if page && f.cache != nil {
+		if cached = f.cache.lookup(url); cached != nil {
+			cached.setValidators(req)
 		}
-		
-		// Follow redirects up to 10 times
-		if len(via) >= 10 {
-			return fmt.Errorf("stopped after 10 redirects")
-		}
-		return nil
 	}
 
+	// Track the redirect chain through the request context; the client's
+	// CheckRedirect is shared by all workers, so it can't be swapped per request
+	var redirectChain []string
+	req = req.WithContext(context.WithValue(req.Context(), redirectChainKey{}, &redirectChain))
+
+	// Time each phase of the final request, and the first byte of its
+	// response from the start, including any redirects
+	timer := newRequestTimer()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), timer.trace()))
+
 	resp, err := f.client.Do(req)
 	responseTime := time.Since(startTime)
 
-	// Restore original CheckRedirect
-	f.client.CheckRedirect = originalCheckRedirect
-
 	if err != nil {
 		result.Error = fmt.Errorf("request failed: %w", err)
 		result.PageResult.Error = result.Error.Error()
+		result.PageResult.ErrorCode = requestErrorCode(err)
 		result.PageResult.ResponseTime = responseTime.Milliseconds()
+		result.PageResult.Timing = timer.timing(0)
 		return result
 	}
 	defer resp.Body.Close()
 
 	result.PageResult.StatusCode = resp.StatusCode
 	result.PageResult.ResponseTime = responseTime.Milliseconds()
+	result.PageResult.TTFB = timer.ttfb().Milliseconds()
+	result.PageResult.Timing = timer.timing(0)
 
 	// Only add redirect chain if we actually had redirects (status code indicates redirects were followed)
 	// If the final status is 3xx, it means we hit a redirect that wasn't followed, or
@@ -114,25 +312,107 @@ func (f *Fetcher) Fetch(url string) *FetchResult {
 		result.PageResult.RedirectChain = redirectChain
 	}
 
-	// Read body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		result.Error = fmt.Errorf("failed to read response body: %w", err)
-		result.PageResult.Error = result.Error.Error()
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.PageResult.ContentType = result.ContentType
+	result.PageResult.Headers = flattenHeaders(resp.Header)
+	if resp.Uncompressed {
+		// The transport drops Content-Encoding when it decompresses a gzip
+		// response itself; keep it so compression can be audited
+		result.PageResult.Headers["Content-Encoding"] = "gzip"
+	}
+
+	// A 304 after a redirect answers the validators of another URL, so only
+	// one for the URL itself reuses the cached page
+	if cached != nil && resp.StatusCode == http.StatusNotModified && len(redirectChain) == 0 {
+		f.cache.reuse(result, cached, resp.Header)
 		return result
 	}
 
-	result.Body = body
+	if page && !isHTMLContentType(result.ContentType) {
+		// Files linked from pages, such as PDFs and images, aren't parsed,
+		// so their bodies aren't downloaded
+		result.PageResult.PageSize = int(max(resp.ContentLength, 0))
+	} else {
+		downloadStart := time.Now()
+		err := f.readBody(result, resp, page)
+		result.PageResult.Timing.Download = time.Since(downloadStart).Milliseconds()
+		if err != nil {
+			result.Error = fmt.Errorf("failed to read response body: %w", err)
+			result.PageResult.Error = result.Error.Error()
+			result.PageResult.ErrorCode = requestErrorCode(err)
+			return result
+		}
+	}
+
+	if page && f.cache != nil && resp.StatusCode == http.StatusOK && len(redirectChain) == 0 &&
+		result.Body != nil && !result.PageResult.BodyTruncated {
+		if err := f.cache.store(result, resp.Header); err != nil {
+			utils.Warn("Failed to update the HTTP cache", utils.NewField("url", url), utils.NewField("error", err.Error()))
+		}
+	}
 
 	// Handle non-2xx status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		result.Error = fmt.Errorf("HTTP %d", resp.StatusCode)
 		result.PageResult.Error = result.Error.Error()
+		result.PageResult.ErrorCode = models.StatusErrorCode(resp.StatusCode)
 	}
 
 	return result
 }
 
+// readBody reads the response body into result, cutting a page's body off
+// at the max body size
+func (f *Fetcher) readBody(result *FetchResult, resp *http.Response, page bool) error {
+	limit := int64(0)
+	if page {
+		limit = f.maxBodySize
+	}
+
+	var body []byte
+	var err error
+	if limit > 0 {
+		// Read one byte past the limit to tell a body of exactly the limit
+		// from a longer one
+		body, err = io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	} else {
+		body, err = io.ReadAll(resp.Body)
+	}
+	if err != nil {
+		return err
+	}
+
+	result.PageResult.PageSize = len(body)
+	if limit > 0 && int64(len(body)) > limit {
+		body = body[:limit]
+		result.PageResult.BodyTruncated = true
+		result.PageResult.PageSize = int(limit)
+		if resp.ContentLength > limit {
+			result.PageResult.PageSize = int(resp.ContentLength)
+		}
+		utils.Warn("Response body exceeds --max-body-size; reading only the start",
+			utils.NewField("url", result.PageResult.URL), utils.NewField("max_body_size", limit))
+	}
+	result.Body = body
+	result.PageResult.ContentHash = contentHash(body)
+	return nil
+}
+
+// isHTMLContentType reports whether a Content-Type header names an HTML
+// document. A missing header counts as HTML, so the body is still sniffed
+// by the parser.
+func isHTMLContentType(contentType string) bool {
+	if strings.TrimSpace(contentType) == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Malformed parameters; judge by the type alone
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
 // isRetryableError checks if an error is retryable
 func isRetryableError(result *FetchResult) bool {
 	if result.Error == nil {
@@ -154,6 +434,35 @@ func isRetryableError(result *FetchResult) bool {
 	return false
 }
 
+// requestErrorCode classifies the error of a request that got no response,
+// or whose body couldn't be read, for PageResult.ErrorCode
+func requestErrorCode(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var alertErr tls.AlertError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	switch {
+	case errors.As(err, &dnsErr):
+		return models.ErrorDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return models.ErrorConnectionRefused
+	case errors.As(err, &certErr), errors.As(err, &alertErr), errors.As(err, &recordErr),
+		errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return models.ErrorTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return models.ErrorTimeout
+	case strings.Contains(err.Error(), "tls: "):
+		// Handshake failures the tls package reports as plain errors
+		return models.ErrorTLS
+	default:
+		return models.ErrorOther
+	}
+}
+
 // containsAny checks if a string contains any of the substrings
 func containsAny(s string, substrings []string) bool {
 	for _, substr := range substrings {
@@ -164,22 +473,40 @@ func containsAny(s string, substrings []string) bool {
 	return false
 }
 
-// FetchWithRetry retrieves a URL with retry logic for transient errors
+// FetchWithRetry retrieves a URL with retry logic for transient errors. With
+// a renderer set, a successful HTML page's body is replaced by the rendered DOM.
 func (f *Fetcher) FetchWithRetry(url string, maxRetries int) *FetchResult {
+	result := f.fetchWithRetry(url, maxRetries)
+	if f.renderer != nil {
+		f.render(result)
+	}
+	return result
+}
+
+func (f *Fetcher) fetchWithRetry(url string, maxRetries int) *FetchResult {
 	var lastResult *FetchResult
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff: wait 2^attempt seconds
-			backoff := time.Duration(1<<uint(attempt-1)) * time.Second
-			time.Sleep(backoff)
+			// Exponential backoff: wait 2^attempt seconds, unless the rate
+			// limiter has already backed off from the host's response
+			if !f.limiter.throttles(lastResult.PageResult.StatusCode) {
+				backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+				time.Sleep(backoff)
+			}
+			if f.limiter != nil && !f.limiter.Wait(url) {
+				return lastResult
+			}
 		}
 
-		result := f.Fetch(url)
+		result := f.do(http.MethodGet, url, nil, true)
 		lastResult = result
+		if f.limiter != nil {
+			f.limiter.Observe(url, result.PageResult.StatusCode, result.PageResult.Headers)
+		}
 
 		// If successful or not retryable, return immediately
-		if result.Error == nil || !isRetryableError(result) {
+		if result.Error == nil || !(isRetryableError(result) || f.limiter.throttles(result.PageResult.StatusCode)) {
 			return result
 		}
 
@@ -193,3 +520,39 @@ func (f *Fetcher) FetchWithRetry(url string, maxRetries int) *FetchResult {
 
 	return lastResult
 }
+
+// render replaces an HTML page's body with the DOM rendered by the headless
+// browser and records its JavaScript errors. The HTTP body is kept when
+// rendering fails, so the page is still parsed.
+func (f *Fetcher) render(result *FetchResult) {
+	if result.Error != nil || result.PageResult.StatusCode != 200 || !isHTMLContentType(result.ContentType) {
+		return
+	}
+	body, jsErrors, err := f.renderer.Render(result.PageResult.URL)
+	if err != nil {
+		utils.Warn("Rendering failed; using the HTTP response", utils.NewField("url", result.PageResult.URL), utils.NewField("error", err.Error()))
+		return
+	}
+	result.Body = body
+	result.PageResult.Rendered = true
+	result.PageResult.JSErrors = jsErrors
+}
+
+// contentHash returns the hex SHA-256 of a response body
+func contentHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// flattenHeaders joins repeated response headers, dropping Set-Cookie so
+// session tokens never end up in exported results
+func flattenHeaders(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		if name == "Set-Cookie" {
+			continue
+		}
+		flat[name] = strings.Join(values, ", ")
+	}
+	return flat
+}
@@ -0,0 +1,125 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// headerRecorder is a server that records the X-Staging-Token header of the
+// requests it gets by path
+type headerRecorder struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func newHeaderRecorder(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *headerRecorder {
+	rec := &headerRecorder{tokens: make(map[string]string)}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec.mu.Lock()
+		rec.tokens[r.URL.Path] = r.Header.Get("X-Staging-Token")
+		rec.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (rec *headerRecorder) token(path string) (string, bool) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	token, ok := rec.tokens[path]
+	return token, ok
+}
+
+func TestSiteHeadersNotSentAcrossRedirects(t *testing.T) {
+	// The site redirects to another host, which redirects back
+	var site *headerRecorder
+	other := newHeaderRecorder(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, site.URL+"/back", http.StatusFound)
+	})
+	site = newHeaderRecorder(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/away" {
+			http.Redirect(w, r, other.URL+"/elsewhere", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>ok</body></html>"))
+	})
+
+	f := NewFetcher(5*time.Second, "barracuda-test")
+	header := make(http.Header)
+	header.Set("X-Staging-Token", "secret")
+	f.SetHeaders(header, site.URL)
+
+	result := f.Fetch(site.URL + "/away")
+	if result.Error != nil {
+		t.Fatal(result.Error)
+	}
+	if got := result.PageResult.RedirectChain; len(got) != 2 {
+		t.Errorf("redirect chain %v, want 2 hops", got)
+	}
+
+	if token, _ := site.token("/away"); token != "secret" {
+		t.Errorf("site got token %q, want it sent", token)
+	}
+	if token, ok := other.token("/elsewhere"); !ok {
+		t.Error("other host wasn't requested")
+	} else if token != "" {
+		t.Errorf("other host got the site's token %q", token)
+	}
+	if token, _ := site.token("/back"); token != "secret" {
+		t.Errorf("site got token %q after a redirect back, want it sent", token)
+	}
+}
+
+func TestRedirectChainsPerFetch(t *testing.T) {
+	// /r/<n>/<hops> redirects hops times before answering, through URLs
+	// unique to n
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/r/"), "/")
+		hops, _ := strconv.Atoi(parts[1])
+		if hops > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/r/%s/%d", parts[0], hops-1), http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>ok</body></html>"))
+	}))
+	defer server.Close()
+
+	// The fetcher's CheckRedirect is shared, so fetches running at once must
+	// each record only their own hops
+	f := NewFetcher(5*time.Second, "barracuda-test")
+	var wg sync.WaitGroup
+	for n := 0; n < 50; n++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			hops := n % 5
+			result := f.Fetch(fmt.Sprintf("%s/r/%d/%d", server.URL, n, hops))
+			if result.Error != nil {
+				t.Errorf("fetch %d: %v", n, result.Error)
+				return
+			}
+			chain := result.PageResult.RedirectChain
+			if len(chain) != hops {
+				t.Errorf("fetch %d: redirect chain %v, want %d hops", n, chain, hops)
+				return
+			}
+			for i, hop := range chain {
+				if want := fmt.Sprintf("%s/r/%d/%d", server.URL, n, hops-1-i); hop != want {
+					t.Errorf("fetch %d: hop %d is %s, want %s", n, i, hop, want)
+				}
+			}
+		}(n)
+	}
+	wg.Wait()
+}
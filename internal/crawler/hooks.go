@@ -0,0 +1,142 @@
+package crawler
+
+import (
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// PageHook is called from crawl workers as each page is crawled, with the
+// number of pages crawled so far. It must be safe for concurrent use.
+type PageHook func(page *models.PageResult, totalPages int)
+
+// IssueHook is called from crawl workers for each issue found on a page. It
+// must be safe for concurrent use.
+type IssueHook func(page *models.PageResult, issue models.Issue)
+
+// CompleteHook is called once when Crawl returns, with its results and error
+type CompleteHook func(results []*models.PageResult, err error)
+
+// EventHook is called from crawl workers for each entry of the crawl's
+// structured log. It must be safe for concurrent use.
+type EventHook func(event models.CrawlEvent)
+
+// PauseHook is called when the crawl pauses outside its active hours, with
+// the time it resumes
+type PauseHook func(resumeAt time.Time)
+
+// PageAnalyzer finds the issues on a crawled page. analyzer.Incremental
+// implements it.
+type PageAnalyzer interface {
+	Add(page *models.PageResult) []models.Issue
+}
+
+// hooks holds the event hooks registered on a Manager, in registration order
+type hooks struct {
+	analyzer PageAnalyzer
+	page     []PageHook
+	issue    []IssueHook
+	complete []CompleteHook
+	event    []EventHook
+	pause    []PauseHook
+}
+
+// OnPageCrawled registers a hook called as each page is crawled. Hooks must
+// be registered before Crawl is called.
+func (m *Manager) OnPageCrawled(hook PageHook) {
+	m.hooks.page = append(m.hooks.page, hook)
+}
+
+// OnIssueFound registers a hook called for each issue the analyzer set with
+// SetAnalyzer finds. A page's issues are reported before its OnPageCrawled
+// hooks run, so a page hook can flush them together with the page.
+func (m *Manager) OnIssueFound(hook IssueHook) {
+	m.hooks.issue = append(m.hooks.issue, hook)
+}
+
+// OnCrawlComplete registers a hook called once when the crawl ends
+func (m *Manager) OnCrawlComplete(hook CompleteHook) {
+	m.hooks.complete = append(m.hooks.complete, hook)
+}
+
+// OnEvent registers a hook called for each crawled, failed, or skipped URL.
+// Hooks must be registered before Crawl is called.
+func (m *Manager) OnEvent(hook EventHook) {
+	m.hooks.event = append(m.hooks.event, hook)
+}
+
+// OnPause registers a hook called each time the crawl pauses outside the
+// config's active hours. Hooks must be registered before Crawl is called.
+func (m *Manager) OnPause(hook PauseHook) {
+	m.hooks.pause = append(m.hooks.pause, hook)
+}
+
+// SetAnalyzer sets the analyzer run on each crawled page. Without one, no
+// OnIssueFound hooks are called.
+func (m *Manager) SetAnalyzer(analyzer PageAnalyzer) {
+	m.hooks.analyzer = analyzer
+}
+
+// pageCrawled analyzes a stored page and runs the issue and page hooks
+func (m *Manager) pageCrawled(page *models.PageResult, totalPages int) {
+	if m.hooks.analyzer != nil {
+		for _, issue := range m.hooks.analyzer.Add(page) {
+			for _, hook := range m.hooks.issue {
+				hook(page, issue)
+			}
+		}
+	}
+	for _, hook := range m.hooks.page {
+		hook(page, totalPages)
+	}
+}
+
+// logEvent timestamps an event and runs the event hooks
+func (m *Manager) logEvent(event models.CrawlEvent) {
+	if len(m.hooks.event) == 0 {
+		return
+	}
+	event.Time = time.Now().UTC()
+	for _, hook := range m.hooks.event {
+		hook(event)
+	}
+}
+
+// logFetch logs a fetched page as crawled or failed
+func (m *Manager) logFetch(page *models.PageResult) {
+	event := models.CrawlEvent{
+		Event:  models.EventCrawled,
+		URL:    page.URL,
+		Depth:  page.Depth,
+		Status: page.StatusCode,
+	}
+	if page.Error != "" || page.StatusCode >= 400 {
+		event.Event = models.EventFailed
+		event.Error = page.Error
+	}
+	m.logEvent(event)
+}
+
+// logSkip records a URL that won't be crawled for SkipCounts and logs it,
+// once per URL and reason. source is the page it was found on, or empty when
+// unknown.
+func (m *Manager) logSkip(url, source string, depth int, reason SkipReason) {
+	if !m.skipped.add(url, reason) || len(m.hooks.event) == 0 {
+		return
+	}
+
+	m.logEvent(models.CrawlEvent{
+		Event:  models.EventSkipped,
+		URL:    url,
+		Depth:  depth,
+		Reason: string(reason),
+		Source: source,
+	})
+}
+
+// crawlComplete runs the completion hooks
+func (m *Manager) crawlComplete(results []*models.PageResult, err error) {
+	for _, hook := range m.hooks.complete {
+		hook(results, err)
+	}
+}
@@ -0,0 +1,160 @@
+package crawler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPCache keeps the validators and bodies of fetched pages in a directory,
+// so a later crawl of the same site can send If-None-Match and
+// If-Modified-Since and reuse the cached body when the server answers 304
+// Not Modified. Files are named by a hash of the URL, like HTML snapshots.
+type HTTPCache struct {
+	dir  string
+	hits atomic.Int64 // Pages answered 304 and served from the cache
+}
+
+// httpCacheEntry is what the cache keeps of a page besides its body
+type httpCacheEntry struct {
+	URL          string            `json:"url"`
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"last_modified,omitempty"`
+	ContentType  string            `json:"content_type,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	StoredAt     time.Time         `json:"stored_at"`
+
+	body []byte // Read from the body file alongside
+}
+
+// NewHTTPCache creates dir if needed and returns a cache keeping its
+// entries there
+func NewHTTPCache(dir string) (*HTTPCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create HTTP cache directory: %w", err)
+	}
+	return &HTTPCache{dir: dir}, nil
+}
+
+// Hits returns how many pages were answered 304 Not Modified and parsed from
+// the cache
+func (c *HTTPCache) Hits() int {
+	if c == nil {
+		return 0
+	}
+	return int(c.hits.Load())
+}
+
+// paths returns the files of a URL's entry and body
+func (c *HTTPCache) paths(url string) (entry, body string) {
+	name := strings.TrimSuffix(SnapshotFileName(url), ".html")
+	return filepath.Join(c.dir, name+".json"), filepath.Join(c.dir, name+".body")
+}
+
+// lookup returns the cached entry of url with its body, or nil when there is
+// none or it can't be read
+func (c *HTTPCache) lookup(url string) *httpCacheEntry {
+	entryPath, bodyPath := c.paths(url)
+	data, err := os.ReadFile(entryPath)
+	if err != nil {
+		return nil
+	}
+	var entry httpCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil
+	}
+	if entry.body, err = os.ReadFile(bodyPath); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// store saves a page's body and validators. Pages without an ETag or
+// Last-Modified header can't be revalidated and aren't kept, and neither are
+// those the server asks not to store.
+func (c *HTTPCache) store(result *FetchResult, header http.Header) error {
+	entry := httpCacheEntry{
+		URL:          result.PageResult.URL,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		ContentType:  result.ContentType,
+		Headers:      result.PageResult.Headers,
+		StoredAt:     time.Now(),
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return nil
+	}
+	if strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-store") {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode HTTP cache entry: %w", err)
+	}
+	entryPath, bodyPath := c.paths(entry.URL)
+	// The body goes first, so an entry never refers to a body that isn't there
+	if err := writeFileAtomic(bodyPath, result.Body); err != nil {
+		return fmt.Errorf("failed to save HTTP cache body: %w", err)
+	}
+	if err := writeFileAtomic(entryPath, data); err != nil {
+		return fmt.Errorf("failed to save HTTP cache entry: %w", err)
+	}
+	return nil
+}
+
+// setValidators adds the conditional request headers for entry to req
+func (e *httpCacheEntry) setValidators(req *http.Request) {
+	if e.ETag != "" {
+		req.Header.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		req.Header.Set("If-Modified-Since", e.LastModified)
+	}
+}
+
+// reuse fills in a page answered 304 Not Modified from the cached entry. The
+// page is recorded as the 200 it was when cached, with the headers the 304
+// sent, such as a new Cache-Control, replacing the cached ones.
+func (c *HTTPCache) reuse(result *FetchResult, entry *httpCacheEntry, header http.Header) {
+	c.hits.Add(1)
+	page := result.PageResult
+	page.StatusCode = http.StatusOK
+	page.NotModified = true
+	page.Headers = make(map[string]string, len(entry.Headers))
+	for name, value := range entry.Headers {
+		page.Headers[name] = value
+	}
+	for name, value := range flattenHeaders(header) {
+		page.Headers[name] = value
+	}
+	result.ContentType = entry.ContentType
+	page.ContentType = entry.ContentType
+	result.Body = entry.body
+	page.PageSize = len(entry.body)
+	page.ContentHash = contentHash(entry.body)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path,
+// so a crash mid-write, or another crawl sharing the cache, never leaves
+// half a file
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,188 @@
+package crawler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// loginForm is a form found on a login page, ready to submit
+type loginForm struct {
+	method string // "GET" or "POST"
+	action string // Absolute URL the form submits to
+	values url.Values
+}
+
+// login signs in through the form on the login page and checks that the
+// page it leads to matches the success selector. The session cookies stay
+// in the fetcher's jar, so the rest of the crawl runs as the signed-in user.
+func (m *Manager) login() error {
+	fields, err := m.config.LoginFormValues()
+	if err != nil {
+		return err
+	}
+	success, err := cascadia.Compile(m.config.LoginSuccess)
+	if err != nil {
+		return fmt.Errorf("invalid login success selector %q: %w", m.config.LoginSuccess, err)
+	}
+	if err := m.fetcher.EnableCookies(); err != nil {
+		return err
+	}
+
+	page := m.fetcher.Fetch(m.config.LoginURL)
+	if page.Error != nil {
+		return fmt.Errorf("failed to load login page: %w", page.Error)
+	}
+	form, err := findLoginForm(page.Body, finalURL(page), fields)
+	if err != nil {
+		return err
+	}
+	for name, values := range fields {
+		form.values[name] = values
+	}
+
+	utils.Info("Logging in", utils.NewField("url", form.action))
+	result := m.fetcher.Submit(form.method, form.action, form.values)
+	if result.Error != nil {
+		return fmt.Errorf("login request failed: %w", result.Error)
+	}
+	doc, err := html.Parse(bytes.NewReader(result.Body))
+	if err != nil {
+		return fmt.Errorf("failed to parse the page after login: %w", err)
+	}
+	if cascadia.Query(doc, success) == nil {
+		return fmt.Errorf("login failed: %s has no element matching %q", finalURL(result), m.config.LoginSuccess)
+	}
+	utils.Info("Logged in", utils.NewField("url", finalURL(result)))
+	return nil
+}
+
+// finalURL returns the URL a fetch ended at after its redirects
+func finalURL(result *FetchResult) string {
+	if chain := result.PageResult.RedirectChain; len(chain) > 0 {
+		return chain[len(chain)-1]
+	}
+	return result.PageResult.URL
+}
+
+// findLoginForm returns the first form on the page with an input for each of
+// the fields. Its hidden and prefilled inputs keep their values, so CSRF
+// tokens are sent back.
+func findLoginForm(body []byte, pageURL string, fields url.Values) (*loginForm, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse login page: %w", err)
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid login page URL: %w", err)
+	}
+
+	var found *loginForm
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		if found != nil {
+			return
+		}
+		if n.Type == html.ElementNode && n.DataAtom == atom.Form {
+			inputs, values := formInputs(n)
+			for name := range fields {
+				if !inputs[name] {
+					return
+				}
+			}
+			action, _ := attr(n, "action")
+			target, err := base.Parse(strings.TrimSpace(action))
+			if err != nil {
+				return
+			}
+			method := http.MethodGet
+			if value, _ := attr(n, "method"); strings.EqualFold(strings.TrimSpace(value), http.MethodPost) {
+				method = http.MethodPost
+			}
+			target.Fragment = ""
+			found = &loginForm{method: method, action: target.String(), values: values}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(doc)
+
+	if found == nil {
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		return nil, fmt.Errorf("no form on %s has the login fields %s", pageURL, strings.Join(names, ", "))
+	}
+	return found, nil
+}
+
+// formInputs returns the names of a form's inputs, and the values a browser
+// would submit for them without any typing, including its first named submit
+// button as if it was clicked
+func formInputs(form *html.Node) (map[string]bool, url.Values) {
+	inputs := make(map[string]bool)
+	values := make(url.Values)
+	clicked := false
+	submitter := func(n *html.Node, name string) {
+		if !clicked {
+			clicked = true
+			value, _ := attr(n, "value")
+			values.Add(name, value)
+		}
+	}
+	var visit func(n *html.Node)
+	visit = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			name, _ := attr(n, "name")
+			switch {
+			case name == "":
+			case n.DataAtom == atom.Input:
+				inputType, _ := attr(n, "type")
+				switch strings.ToLower(inputType) {
+				case "submit":
+					submitter(n, name)
+				case "button", "image", "reset", "file":
+				case "checkbox", "radio":
+					inputs[name] = true
+					if hasAttr(n, "checked") {
+						value, ok := attr(n, "value")
+						if !ok {
+							value = "on"
+						}
+						values.Add(name, value)
+					}
+				default:
+					inputs[name] = true
+					value, _ := attr(n, "value")
+					values.Add(name, value)
+				}
+			case n.DataAtom == atom.Textarea:
+				inputs[name] = true
+				values.Add(name, nodeText(n))
+			case n.DataAtom == atom.Select:
+				inputs[name] = true
+			case n.DataAtom == atom.Button:
+				if buttonType, _ := attr(n, "type"); buttonType == "" || strings.EqualFold(buttonType, "submit") {
+					submitter(n, name)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			visit(c)
+		}
+	}
+	visit(form)
+	return inputs, values
+}
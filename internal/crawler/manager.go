@@ -3,8 +3,10 @@ package crawler
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"os/signal"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"syscall"
@@ -15,33 +17,85 @@ import (
 	"github.com/dillonlara115/barracuda/pkg/models"
 )
 
-// ProgressCallback is called when a page is crawled to allow real-time updates
-type ProgressCallback func(page *models.PageResult, totalPages int)
-
 // Manager orchestrates the crawling process
 type Manager struct {
 	config           *utils.Config
 	fetcher          *Fetcher
+	mobileFetcher    *Fetcher         // Fetches pages again as Googlebot Smartphone (nil without --mobile-parity)
+	limiter          *rateLimiter     // Per-host request pacing (--delay, --max-rps, --auto-throttle)
 	robotsChecker    *RobotsChecker
 	sitemapParser    *SitemapParser
 	linkGraph        *graph.Graph
-	visited          sync.Map // map[string]bool for visited URLs
-	queue            chan crawlTask
+	visited          *visitedSet
+	queue            *taskQueue       // Unbounded, spilling to disk on large crawls (see queue.go)
 	results          []*models.PageResult
 	resultsMu        sync.Mutex
-	wg               sync.WaitGroup
+	pageCount        int              // Pages crawled, whether kept in results or streamed
+	sink             ResultSink       // Optional; crawled pages are written to it instead of results (see sink.go)
+	sinkErr          error            // First failed sink write, which stops the crawl
+	streamed         map[string]bool  // URLs of the pages written to the sink, for SkipCounts
+	tasks            sync.WaitGroup // Outstanding tasks: queued or being processed
 	ctx              context.Context
 	cancel           context.CancelFunc
-	pending          int32 // Track pending tasks (atomic)
-	queueClosed      int32 // Atomic flag to track if queue is closed
-	progressCallback ProgressCallback // Optional callback for progress updates
+	hooks            hooks            // Event hooks for crawled pages, issues, and completion
+	skipped          skipTracker      // Discovered URLs not crawled, for SkipCounts
 	normalizedStartURL string // Store normalized start URL for domain comparison
+	startURLs        []string         // All normalized start URLs, normalizedStartURL first (see scope.go)
+	urlFilter        *utils.URLFilter // Optional include/exclude rules (nil allows all)
+	snapshots        *SnapshotStore   // Optional raw HTML store (nil when --save-html is unset)
+	sitemapLastMod   map[string]*time.Time // <lastmod> of the URLs seeded from the sitemap, nil for those without one
+	sitemapPriority  map[string]float64    // <priority> of the URLs seeded from the sitemap, for --strategy priority
+	activeHours      *utils.ActiveWindow   // Daily window the crawl runs in, nil for any time (see activehours.go)
+	pauseMu          sync.Mutex
+	pausedUntil      time.Time             // End of the pause last reported to the pause hooks
+
+	// Checkpointing (see state.go)
+	stateFile          string             // Where state is saved ("" disables checkpoints)
+	checkpointInterval time.Duration
+	checkpointMu       sync.RWMutex       // Held for reading while a task runs, for writing while saving
+	pending            map[crawlTask]int  // Queued tasks not crawled yet, when checkpointing
+	pendingMu          sync.Mutex
+	resumeState        *CrawlState        // Saved crawl to continue instead of seeding
+
+	// Final retry pass of transient failures (see retry.go)
+	retryMu  sync.Mutex      // Also held while discarding the queue of a cancelled crawl
+	retries  []crawlTask     // Pages held back after a transient failure
+	retried  map[string]bool // URLs given their final attempt; true until it is crawled
+	interrupted        atomic.Bool        // Stopped by a signal or Stop rather than finishing
+
+	// Live control of a running crawl (see control.go)
+	controlMu      sync.Mutex
+	controlWake    chan struct{}  // Closed and replaced when paused or workerLimit changes
+	paused         bool           // By Pause, not active hours
+	workerLimit    int            // Workers allowed to take pages
+	workers        sync.WaitGroup // Started workers, which exit when the queue closes
+	startedWorkers int
+	runningWorkers int
+	queueClosed    bool // Every task is done, so waiting workers should exit
+	status         CrawlStatus
+	cancelled      bool   // By Cancel, which also stops the crawl
+	cancelReason   string // Given to Cancel
+	settled        bool   // The crawl has ended, so Cancel no longer applies
 }
 
+// SkipReason explains why a URL would not be crawled
+type SkipReason string
+
+const (
+	SkipNone      SkipReason = ""
+	SkipRobots    SkipReason = models.SkipRobots
+	SkipDomain    SkipReason = models.SkipDomain
+	SkipFilter    SkipReason = models.SkipFilter
+	SkipScope     SkipReason = models.SkipScope
+	SkipNofollow  SkipReason = models.SkipNofollow
+	SkipDepth     SkipReason = models.SkipDepth
+)
+
 // crawlTask represents a URL to be crawled with its depth
 type crawlTask struct {
-	URL   string
-	Depth int
+	URL      string `json:"url"`
+	Depth    int    `json:"depth"`
+	Priority int    `json:"priority,omitempty"` // Higher is crawled first under --strategy priority (see strategy.go)
 }
 
 // NewManager creates a new Manager instance
@@ -51,10 +105,46 @@ func NewManager(config *utils.Config) *Manager {
 	manager := &Manager{
 		config:  config,
 		fetcher: NewFetcher(config.Timeout, config.UserAgent),
-		queue:   make(chan crawlTask, config.MaxPages*2), // Buffer for queue
 		results: make([]*models.PageResult, 0, config.MaxPages),
+		visited: newVisitedSet(config.VisitedLimit, config.MaxPages*2, config.VisitedFPRate, config.LowMemory),
 		ctx:     ctx,
 		cancel:  cancel,
+
+		controlWake: make(chan struct{}),
+		workerLimit: config.Workers,
+		status:      StatusPending,
+	}
+	// Tasks lost to an unreadable queue segment are no longer outstanding
+	segmentSize := queueSegmentSize
+	if config.LowMemory {
+		segmentSize = lowMemoryQueueSegmentSize
+	}
+	manager.queue = newTaskQueue(segmentSize, config.Strategy, func(n int) { manager.tasks.Add(-n) })
+
+	// Pace requests per host; the fetcher also paces its retries with it
+	manager.limiter = newRateLimiter(ctx, config.MaxRPS, config.Delay, config.AutoThrottle)
+	manager.fetcher.limiter = manager.limiter
+
+	// Send --header, --cookie, and --basic-auth to the crawled sites only.
+	// Validate has already rejected malformed values.
+	if header, err := config.RequestHeader(); err == nil && header != nil {
+		manager.fetcher.SetHeaders(header, config.StartURLs()...)
+	}
+	if rewrites, err := config.HostRewriteMap(); err == nil {
+		manager.fetcher.SetHostRewrites(rewrites)
+	}
+	if size, err := config.MaxBodyBytes(); err == nil {
+		manager.fetcher.SetMaxBodySize(size)
+	}
+	// Keep an idle connection per worker, so each can reuse one
+	manager.fetcher.SetTransportOptions(TransportOptions{
+		MaxConnsPerHost:   config.MaxConnsPerHost,
+		IdleConnsPerHost:  config.Workers,
+		DisableHTTP2:      config.DisableHTTP2,
+		DisableKeepAlives: config.DisableKeepAlives,
+	})
+	if config.MobileParity {
+		manager.mobileFetcher = manager.fetcher.withUserAgent(utils.UserAgentPresets[utils.MobilePreset].UserAgent)
 	}
 
 	// Initialize robots checker
@@ -62,97 +152,431 @@ func NewManager(config *utils.Config) *Manager {
 
 	// Initialize sitemap parser
 	manager.sitemapParser = NewSitemapParser(manager.fetcher)
+	if since, err := config.SitemapSinceTime(); err == nil && !since.IsZero() {
+		manager.sitemapParser.SetModifiedSince(since)
+	}
 
 	// Initialize link graph
 	manager.linkGraph = graph.NewGraph()
 
+	// Pause outside --active-hours (already checked by Config.Validate)
+	if window, err := config.ActiveWindow(); err == nil {
+		manager.activeHours = window
+	}
+
+	// Compile include/exclude rules (already checked by Config.Validate)
+	if filter, err := utils.NewURLFilter(config.Include, config.Exclude); err != nil {
+		utils.Warn("Ignoring invalid URL filter", utils.NewField("error", err.Error()))
+	} else {
+		manager.urlFilter = filter
+	}
+
 	// Setup graceful shutdown
 	go manager.handleSignals()
 
 	return manager
 }
 
-// SetProgressCallback sets a callback function that will be called when each page is crawled
-func (m *Manager) SetProgressCallback(callback ProgressCallback) {
-	m.progressCallback = callback
+// Stop ends a running crawl; Crawl returns the pages fetched so far. With a
+// state file set, the crawl's state is saved so it can be resumed.
+func (m *Manager) Stop() {
+	m.interrupted.Store(true)
+	m.cancel()
 }
 
-// Crawl starts the crawling process
-func (m *Manager) Crawl() ([]*models.PageResult, error) {
-	// Normalize start URL
-	startURL, err := utils.NormalizeURL(m.config.StartURL)
+// SeedURLs resolves the normalized URLs a crawl starts from: for each start
+// URL, the entries of its host's sitemaps (those robots.txt lists, or
+// /sitemap.xml) when sitemap parsing is enabled and yields results, otherwise
+// the start URL itself. No pages are fetched other
+// than the sitemaps.
+func (m *Manager) SeedURLs() ([]string, error) {
+	// Normalize start URLs for domain comparison
+	if err := m.setStartURLs(); err != nil {
+		return nil, err
+	}
+
+	// List mode crawls exactly the given URLs
+	if len(m.config.URLList) > 0 {
+		return m.config.URLList, nil
+	}
+
+	var seedURLs []string
+	sitemaps := make(map[string]bool) // Sitemap URL -> whether it listed any URLs
+	for _, startURL := range m.startURLs {
+		// Start URLs on one host share its sitemaps
+		if m.config.ParseSitemap {
+			listed := false
+			for _, sitemapURL := range m.sitemapParser.DiscoverSitemapURLs(startURL, m.robotsChecker) {
+				found, parsed := sitemaps[sitemapURL]
+				if !parsed {
+					entries := m.sitemapSeeds(sitemapURL)
+					seedURLs = append(seedURLs, entries...)
+					found = len(entries) > 0
+					sitemaps[sitemapURL] = found
+				}
+				listed = listed || found
+			}
+			if listed {
+				continue
+			}
+		}
+
+		// If no sitemap URLs found, use start URL
+		seedURLs = append(seedURLs, startURL)
+	}
+
+	return seedURLs, nil
+}
+
+// sitemapSeeds returns the URLs a sitemap lists, recording their <lastmod>
+// and <priority>, or nil when it can't be parsed
+func (m *Manager) sitemapSeeds(sitemapURL string) []string {
+	utils.Info("Parsing sitemap", utils.NewField("url", sitemapURL))
+
+	entries, err := m.sitemapParser.ParseSitemapEntries(sitemapURL)
+	if err != nil {
+		utils.Warn("Failed to parse sitemap; crawling from the start URL", utils.NewField("url", sitemapURL), utils.NewField("error", err.Error()))
+		return nil
+	}
+	if m.sitemapLastMod == nil {
+		m.sitemapLastMod = make(map[string]*time.Time, len(entries))
+	}
+	if m.sitemapPriority == nil {
+		m.sitemapPriority = make(map[string]float64)
+	}
+	seedURLs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		seedURLs = append(seedURLs, entry.URL)
+		// Kept without a <lastmod> too, to record which pages are listed
+		m.sitemapLastMod[entry.URL] = entry.LastMod
+		if entry.Priority != nil {
+			m.sitemapPriority[entry.URL] = *entry.Priority
+		}
+	}
+	utils.Info("Found URLs in sitemap", utils.NewField("url", sitemapURL), utils.NewField("count", len(seedURLs)))
+	return seedURLs
+}
+
+// Evaluate reports why a URL would be skipped under the current configuration,
+// or SkipNone if it would be crawled. SeedURLs must be called first so the
+// start domains are known.
+func (m *Manager) Evaluate(targetURL string) SkipReason {
+	if m.config.DomainFilter == "same" && !m.inScope(targetURL) {
+		return SkipDomain
+	}
+	if !m.isStartURL(targetURL) && !m.inScopePath(targetURL) {
+		return SkipScope
+	}
+	if !m.isStartURL(targetURL) && !m.urlFilter.Allows(targetURL) {
+		return SkipFilter
+	}
+	if allowed, err := m.robotsChecker.IsAllowed(targetURL); err == nil && !allowed {
+		return SkipRobots
+	}
+	return SkipNone
+}
+
+// RobotsTxt fetches robots.txt for the start domain and returns its URL.
+// The error is non-nil when it could not be fetched, in which case crawling
+// proceeds as if everything were allowed.
+func (m *Manager) RobotsTxt() (string, error) {
+	u, err := url.Parse(m.normalizedStartURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid start URL: %w", err)
+	}
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+	_, err = m.robotsChecker.fetchRobotsTxt(robotsURL)
+	return robotsURL, err
+}
+
+// RobotsDirectives returns the Crawl-delay the start domain's robots.txt
+// sets, 0 when it sets none or isn't respected, and the sitemaps it lists
+func (m *Manager) RobotsDirectives() (time.Duration, []string) {
+	return m.robotsChecker.CrawlDelay(m.normalizedStartURL), m.robotsChecker.Sitemaps(m.normalizedStartURL)
+}
+
+// RobotsTxtFile returns the start domain's robots.txt as the crawl fetched
+// it, fetching it if the crawl hasn't
+func (m *Manager) RobotsTxtFile() *models.RobotsTxt {
+	return m.robotsChecker.File(m.normalizedStartURL)
+}
+
+// SiteFile fetches a file such as /llms.txt from the root of the start URL's
+// host. It returns nil content when the file is missing (any non-200 status)
+// and an error when the request itself fails.
+func (m *Manager) SiteFile(path string) ([]byte, error) {
+	u, err := url.Parse(m.normalizedStartURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid start URL: %w", err)
 	}
-	
-	// Store normalized start URL for domain comparison
-	m.normalizedStartURL = startURL
+	result := m.fetcher.Fetch(fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, path))
+	if result.PageResult.StatusCode != 0 && result.PageResult.StatusCode != 200 {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Body, nil
+}
 
-	// Parse sitemap if enabled
-	var seedURLs []string
-	if m.config.ParseSitemap {
-		sitemapURL := m.sitemapParser.DiscoverSitemapURL(startURL)
-		utils.Info("Parsing sitemap", utils.NewField("url", sitemapURL))
-		
-		urls, err := m.sitemapParser.ParseSitemap(sitemapURL)
-		if err != nil {
-			utils.Debug("Failed to parse sitemap", utils.NewField("url", sitemapURL), utils.NewField("error", err.Error()))
-		} else {
-			seedURLs = urls
-			utils.Info("Found URLs in sitemap", utils.NewField("count", len(seedURLs)))
+// Crawl starts the crawling process and runs the OnCrawlComplete hooks when
+// it ends
+func (m *Manager) Crawl() ([]*models.PageResult, error) {
+	m.setStatus(true, nil)
+	results, err := m.crawl()
+	m.setStatus(false, err)
+	m.crawlComplete(results, err)
+	return results, err
+}
+
+func (m *Manager) crawl() ([]*models.PageResult, error) {
+	// Not even robots.txt and the sitemap are fetched outside active hours
+	if !m.waitActiveHours() {
+		return nil, fmt.Errorf("crawl cancelled: %w", m.ctx.Err())
+	}
+
+	// Sign in first so robots.txt, the sitemap, and every page are fetched
+	// with the session
+	if m.config.LoginURL != "" {
+		if err := m.login(); err != nil {
+			return nil, err
 		}
 	}
 
-	// If no sitemap URLs found, use start URL
-	if len(seedURLs) == 0 {
-		seedURLs = []string{startURL}
+	var seeds []crawlTask
+	var err error
+	if m.resumeState != nil {
+		seeds, err = m.restoreState(m.resumeState)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		seedURLs, err := m.SeedURLs()
+		if err != nil {
+			return nil, err
+		}
+		seeds = m.seedTasks(seedURLs)
 	}
 
-	// Start worker pool
-	for i := 0; i < m.config.Workers; i++ {
-		m.wg.Add(1)
-		go m.worker(i)
+	// Revalidate the pages an earlier crawl kept in the HTTP cache
+	if m.config.HTTPCacheDir != "" {
+		cache, err := NewHTTPCache(m.config.HTTPCacheDir)
+		if err != nil {
+			return nil, err
+		}
+		m.fetcher.SetHTTPCache(cache)
 	}
 
-	// Enqueue initial tasks (don't mark as visited yet - workers will do that)
-	enqueueDone := make(chan bool)
-	go func() {
-		defer close(enqueueDone)
-		for _, url := range seedURLs {
-			// Normalize URL
-			normalized, err := utils.NormalizeURL(url)
-			if err != nil {
-				utils.Debug("Failed to normalize seed URL", utils.NewField("url", url), utils.NewField("error", err.Error()))
-				continue
+	// Save raw HTML snapshots when requested
+	if m.config.SaveHTMLDir != "" {
+		m.snapshots, err = NewSnapshotStore(m.config.SaveHTMLDir)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := m.snapshots.Close(); err != nil {
+				utils.Warn("Failed to write HTML snapshot index", utils.NewField("error", err.Error()))
 			}
-			
-			atomic.AddInt32(&m.pending, 1)
-			m.queue <- crawlTask{
-				URL:   normalized,
-				Depth: 0,
+		}()
+		// Keep the index entries of pages crawled before the interruption
+		if m.resumeState != nil {
+			if entries, err := LoadSnapshotIndex(m.config.SaveHTMLDir); err == nil {
+				m.snapshots.entries = entries
 			}
 		}
+	}
+
+	// Pass restored pages to the analyzer and hooks as if just crawled
+	for i, page := range m.results {
+		m.pageCrawled(page, i+1)
+	}
+	if m.sink != nil && m.resumeState != nil {
+		if err := m.restoreStreamed(m.resumeState.Stream); err != nil {
+			return nil, err
+		}
+	}
+
+	// Render pages in a headless browser when requested, or fall back to
+	// plain HTTP when none can be started
+	if m.config.Render {
+		rewrites, _ := m.config.HostRewriteMap()
+		renderer, err := NewRenderer(m.config.Timeout, m.config.UserAgent, rewrites)
+		if err != nil {
+			utils.Warn("Rendering disabled; crawling over plain HTTP", utils.NewField("error", err.Error()))
+		} else {
+			renderer.headers = m.fetcher.headers
+			renderer.cookies = m.fetcher.client.Jar
+			m.fetcher.SetRenderer(renderer)
+			defer func() {
+				m.fetcher.SetRenderer(nil)
+				renderer.Close()
+			}()
+		}
+	}
+
+	// Start worker pool. Workers exit when the queue is closed or the
+	// crawl is cancelled.
+	m.startWorkers()
+
+	// Seeds count as pending until crawled, including any still waiting to be
+	// enqueued when the crawl is interrupted
+	for _, seed := range seeds {
+		m.addPending(seed)
+	}
+	stopCheckpoints := m.startCheckpoints()
+
+	// The queue never fills, so every seed is enqueued before the queue can
+	// be closed
+	for _, seed := range seeds {
+		m.tasks.Add(1)
+		m.queue.push(seed)
+	}
+	utils.Debug("Initial tasks enqueued", utils.NewField("count", len(seeds)))
+
+	// Close the queue once every task is done. Tasks are only enqueued above
+	// or while a task is being processed, so nothing can be pushed after the
+	// count reaches zero.
+	// Before that, pages held back after a transient failure get their final
+	// attempt, which may discover more pages.
+	drained := make(chan struct{})
+	go func() {
+		m.tasks.Wait()
+		for m.requeueRetries() {
+			m.tasks.Wait()
+		}
+		m.queue.close()
+		m.releaseWorkers()
+		close(drained)
 	}()
 
-	// Wait for initial enqueueing to complete
-	<-enqueueDone
-	utils.Debug("Initial tasks enqueued", utils.NewField("count", len(seedURLs)))
+	// Wait for all workers to finish, including those UpdateConcurrency added
+	m.workers.Wait()
+
+	// After a cancellation, tasks left in the queue were never processed.
+	// Discard them so the closer goroutine can finish. They stay pending, so
+	// a resumed crawl picks them up.
+	if m.ctx.Err() != nil {
+		m.retryMu.Lock()
+		m.tasks.Add(-m.queue.discard())
+		m.retryMu.Unlock()
+	}
+	<-drained
+
+	// Save an interrupted crawl for --resume, or clear a finished one's state
+	stopCheckpoints()
+	m.finishState()
+
+	if stats := m.visited.Stats(); stats.BloomInUse {
+		utils.Info("Visited set overflowed into bloom filter",
+			utils.NewField("exact", stats.Exact),
+			utils.NewField("bloom", stats.Bloom),
+			utils.NewField("bloom_skips", stats.BloomSkips),
+			utils.NewField("estimated_false_positives", stats.EstimatedFalsePositives))
+	}
 
-	// Monitor queue and close when done
-	go m.monitorQueue()
+	// Workers finish in no particular order; sort so repeated crawls of the
+	// same site export identically
+	SortResults(m.results)
 
-	// Wait for all workers to finish
-	m.wg.Wait()
+	if m.sinkErr != nil {
+		return nil, m.sinkErr
+	}
 
 	// Return results - don't treat cancellation as error if we got results
 	// (cancellation might be due to reaching max-pages, which is success)
-	if m.ctx.Err() != nil && len(m.results) == 0 {
+	if m.ctx.Err() != nil && m.pageCount == 0 {
 		return m.results, fmt.Errorf("crawl cancelled: %w", m.ctx.Err())
 	}
 
 	return m.results, nil
 }
 
+// seedTasks normalizes seed URLs into depth-0 tasks, dropping those the
+// include/exclude rules reject
+func (m *Manager) seedTasks(seedURLs []string) []crawlTask {
+	tasks := make([]crawlTask, 0, len(seedURLs))
+	for _, url := range seedURLs {
+		normalized, err := utils.NormalizeURL(url)
+		if err != nil {
+			utils.Debug("Failed to normalize seed URL", utils.NewField("url", url), utils.NewField("error", err.Error()))
+			continue
+		}
+
+		// Start URLs are always crawled so links can be discovered from them
+		if !m.isStartURL(normalized) && !m.inScopePath(normalized) {
+			utils.Debug("Skipping seed URL - outside scope path", utils.NewField("url", normalized))
+			m.logSkip(normalized, "", 0, SkipScope)
+			continue
+		}
+		if !m.isStartURL(normalized) && !m.urlFilter.Allows(normalized) {
+			utils.Debug("Skipping seed URL - excluded by URL filter", utils.NewField("url", normalized))
+			m.logSkip(normalized, "", 0, SkipFilter)
+			continue
+		}
+		tasks = append(tasks, crawlTask{URL: normalized, Depth: 0, Priority: m.taskPriority(normalized, 0, "")})
+	}
+	return tasks
+}
+
+// logDepthSkips logs the links of a page at the maximum depth that the crawl
+// would otherwise have followed
+func (m *Manager) logDepthSkips(task crawlTask, links []string, nofollow map[string]bool) {
+	for _, linkURL := range links {
+		if m.config.DomainFilter == "same" && !m.inScope(linkURL) {
+			continue
+		}
+		if m.config.RespectNofollow && nofollow[linkURL] {
+			continue
+		}
+		if (!m.isStartURL(linkURL) && !m.inScopePath(linkURL)) || !m.urlFilter.Allows(linkURL) {
+			continue
+		}
+		if m.visited.Contains(linkURL) {
+			m.skipped.duplicate()
+			continue
+		}
+		m.logSkip(linkURL, task.URL, task.Depth+1, SkipDepth)
+	}
+}
+
+// SkipCounts tallies the discovered URLs the crawl didn't fetch, by reason.
+// Call it after Crawl returns. A resumed crawl counts only what it skipped
+// itself.
+func (m *Manager) SkipCounts() models.SkipCounts {
+	m.resultsMu.Lock()
+	crawled := m.streamed
+	if crawled == nil {
+		crawled = make(map[string]bool, len(m.results))
+		for _, page := range m.results {
+			crawled[page.URL] = true
+		}
+	}
+	m.resultsMu.Unlock()
+	return m.skipped.counts(crawled)
+}
+
+// SortResults orders results by crawl depth, then URL, so pages appear
+// roughly in discovery order and identical crawls produce identical exports
+func SortResults(results []*models.PageResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Depth != results[j].Depth {
+			return results[i].Depth < results[j].Depth
+		}
+		return results[i].URL < results[j].URL
+	})
+}
+
+// VisitedStats reports the visited set's size and any bloom filter skips
+func (m *Manager) VisitedStats() VisitedStats {
+	return m.visited.Stats()
+}
+
+// CacheHits returns how many pages the server reported unchanged since they
+// were kept in the HTTP cache, and were parsed from it
+func (m *Manager) CacheHits() int {
+	return m.fetcher.cache.Hits()
+}
+
 // GetLinkGraph returns the link graph
 func (m *Manager) GetLinkGraph() *graph.Graph {
 	return m.linkGraph
@@ -160,278 +584,324 @@ func (m *Manager) GetLinkGraph() *graph.Graph {
 
 // worker processes crawl tasks from the queue
 func (m *Manager) worker(id int) {
-	defer m.wg.Done()
-
 	for {
-		select {
-		case <-m.ctx.Done():
+		// Wait here rather than in processTask, so checkpoints aren't held
+		// up for the length of a pause
+		if !m.waitActiveHours() || !m.waitControl(id, true) {
 			utils.Debug("Worker stopping", utils.NewField("worker_id", id))
 			return
-		case task, ok := <-m.queue:
-			if !ok {
-				utils.Debug("Worker queue closed", utils.NewField("worker_id", id))
-				return
-			}
+		}
+		task, ok := m.queue.pop(m.ctx)
+		if !ok {
+			utils.Debug("Worker stopping", utils.NewField("worker_id", id))
+			return
+		}
+		// Hold a task taken just as the crawl was paused; a stopped crawl's
+		// task is left pending by processTask
+		m.waitControl(id, false)
+		m.processTask(task)
+	}
+}
 
-			// Decrement pending counter (but don't let it go negative)
-			currentPending := atomic.AddInt32(&m.pending, -1)
-			if currentPending < 0 {
-				// Reset if it went negative (shouldn't happen, but safety check)
-				atomic.StoreInt32(&m.pending, 0)
-			}
+// processTask crawls one task and enqueues the links it discovers
+func (m *Manager) processTask(task crawlTask) {
+	defer m.tasks.Done()
 
-			// Check if we've reached max pages BEFORE processing
-			m.resultsMu.Lock()
-			if len(m.results) >= m.config.MaxPages {
-				m.resultsMu.Unlock()
-				// Cancel to signal other workers to stop
-				m.cancel()
-				return
-			}
-			m.resultsMu.Unlock()
+	// The crawl may have been cancelled while the task was queued. A task
+	// that isn't fetched stays pending so a resumed crawl picks it up.
+	if m.ctx.Err() != nil {
+		return
+	}
+	fetched := true
+	defer func() {
+		if fetched {
+			m.donePending(task)
+		}
+	}()
 
-			// Check depth limit - pages at max depth should still be crawled,
-			// but we won't discover links from them (handled later)
-			// Only skip if depth exceeds max depth
-			if task.Depth > m.config.MaxDepth {
-				utils.Debug("Skipping task - depth exceeds max", utils.NewField("url", task.URL), utils.NewField("depth", task.Depth), utils.NewField("max_depth", m.config.MaxDepth))
-				continue
-			}
+	// Checkpoints wait for running tasks so they never save half a task
+	m.checkpointMu.RLock()
+	defer m.checkpointMu.RUnlock()
+
+	// Check if we've reached max pages BEFORE processing
+	m.resultsMu.Lock()
+	if m.pageCount >= m.config.MaxPages {
+		m.resultsMu.Unlock()
+		// Cancel to signal other workers to stop
+		m.cancel()
+		return
+	}
+	m.resultsMu.Unlock()
+
+	// Check depth limit - pages at max depth should still be crawled,
+	// but we won't discover links from them (handled later)
+	// Only skip if depth exceeds max depth
+	if task.Depth > m.config.MaxDepth {
+		utils.Debug("Skipping task - depth exceeds max", utils.NewField("url", task.URL), utils.NewField("depth", task.Depth), utils.NewField("max_depth", m.config.MaxDepth))
+		m.logSkip(task.URL, "", task.Depth, SkipDepth)
+		return
+	}
 
-			// Check if already visited (before marking to avoid race condition)
-			if _, visited := m.visited.LoadOrStore(task.URL, true); visited {
-				continue
-			}
+	// Check if already visited (before marking to avoid race condition)
+	if m.visited.Add(task.URL) && !m.takeRetry(task.URL) {
+		m.skipped.duplicate()
+		return
+	}
 
-			// Check robots.txt before fetching
-			if allowed, err := m.robotsChecker.IsAllowed(task.URL); err != nil {
-				utils.Debug("Robots check error", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
-			} else if !allowed {
-				utils.Debug("URL disallowed by robots.txt", utils.NewField("url", task.URL))
-				continue
-			}
+	// Check robots.txt before fetching
+	if allowed, err := m.robotsChecker.IsAllowed(task.URL); err != nil {
+		utils.Debug("Robots check error", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+	} else if !allowed {
+		utils.Debug("URL disallowed by robots.txt", utils.NewField("url", task.URL))
+		m.logSkip(task.URL, "", task.Depth, SkipRobots)
+		return
+	}
 
-			// Apply delay if configured
-			if m.config.Delay > 0 {
-				select {
-				case <-m.ctx.Done():
-					return
-				case <-time.After(m.config.Delay):
-				}
-			}
+	// Space requests to the host as its robots.txt asks
+	if delay := m.robotsChecker.CrawlDelay(task.URL); delay > 0 {
+		m.limiter.SetMinInterval(task.URL, delay)
+	}
 
-			// Fetch the URL with retry logic
-			result := m.fetcher.FetchWithRetry(task.URL, 3)
+	// Wait for the host's turn under its rate limit
+	if !m.limiter.Wait(task.URL) {
+		fetched = false
+		return
+	}
 
-			// Store result (check limit again before storing)
-			m.resultsMu.Lock()
-			resultCount := len(m.results)
-			if resultCount >= m.config.MaxPages {
-				m.resultsMu.Unlock()
-				m.cancel()
-				return
-			}
-			m.results = append(m.results, result.PageResult)
-			resultCount = len(m.results)
-			m.resultsMu.Unlock()
-
-			utils.Info("Crawled page",
-				utils.NewField("url", task.URL),
-				utils.NewField("status", result.PageResult.StatusCode),
-				utils.NewField("depth", task.Depth),
-				utils.NewField("total", resultCount),
-			)
-
-			// Call progress callback if set (for real-time updates)
-			if m.progressCallback != nil {
-				m.progressCallback(result.PageResult, resultCount)
-			}
+	// Fetch the URL with retry logic
+	result := m.fetcher.FetchWithRetry(task.URL, 3)
+	if m.deferRetry(task, result) {
+		// Stays pending, so an interrupted crawl retries it too
+		fetched = false
+		return
+	}
+	result.PageResult.Depth = task.Depth
+	result.PageResult.SitemapLastMod, result.PageResult.InSitemap = m.sitemapLastMod[task.URL]
 
-			// Check if we've reached max pages after storing
-			if resultCount >= m.config.MaxPages {
-				m.cancel()
-				return
-			}
+	// Parse before storing so snapshots and progress callbacks see
+	// the full result
+	parsedData := m.parseResult(task, result)
+	setRobots(result.PageResult)
 
-			// If fetch failed or not HTML, don't discover links
-			if result.Error != nil || result.PageResult.StatusCode != 200 {
-				utils.Info("Skipping link discovery - fetch failed or non-200", 
-					utils.NewField("url", task.URL),
-					utils.NewField("error", result.Error),
-					utils.NewField("status", result.PageResult.StatusCode))
+	// Compare with what a smartphone is served; only HTML pages are checked
+	if m.mobileFetcher != nil && parsedData != nil {
+		result.PageResult.Mobile = m.fetchMobile(task.URL)
+	}
+
+	// Store result (check limit again before storing)
+	m.resultsMu.Lock()
+	resultCount := m.pageCount
+	if resultCount >= m.config.MaxPages {
+		m.resultsMu.Unlock()
+		m.cancel()
+		return
+	}
+	if err := m.storeResult(result.PageResult); err != nil {
+		m.resultsMu.Unlock()
+		fetched = false
+		m.sinkFailed(err)
+		return
+	}
+	resultCount = m.pageCount
+	m.resultsMu.Unlock()
+
+	utils.Info("Crawled page",
+		utils.NewField("url", task.URL),
+		utils.NewField("status", result.PageResult.StatusCode),
+		utils.NewField("depth", task.Depth),
+		utils.NewField("total", resultCount),
+	)
+	m.logFetch(result.PageResult)
+
+	// Save the raw body for later re-analysis
+	if m.snapshots != nil && len(result.Body) > 0 {
+		if err := m.snapshots.Save(result); err != nil {
+			utils.Warn("Failed to save HTML snapshot", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+		}
+	}
+
+	// Analyze the page and notify hooks (for real-time updates)
+	m.pageCrawled(result.PageResult, resultCount)
+
+	// Check if we've reached max pages after storing
+	if resultCount >= m.config.MaxPages {
+		m.cancel()
+		return
+	}
+
+	// Only parsed pages discover links
+	if parsedData == nil {
+		return
+	}
+
+	// Add edges to link graph
+	m.linkGraph.AddEdges(task.URL, parsedData.InternalLinks)
+	m.linkGraph.AddEdges(task.URL, parsedData.ExternalLinks)
+
+	// Flag nofollow links in the graph; --respect-nofollow also stops them
+	// being followed
+	nofollow := nofollowLinks(parsedData)
+	nofollowTargets := make([]string, 0, len(nofollow))
+	for url := range nofollow {
+		nofollowTargets = append(nofollowTargets, url)
+	}
+	m.linkGraph.MarkNofollow(task.URL, nofollowTargets)
+
+	// Enqueue discovered internal links for crawling
+	// Only discover links if we haven't reached max depth yet
+	if task.Depth < m.config.MaxDepth && len(m.config.URLList) == 0 {
+		enqueuedCount := 0
+		domainSkippedCount := 0
+		visitedSkippedCount := 0
+		filterSkippedCount := 0
+		scopeSkippedCount := 0
+		nofollowSkippedCount := 0
+		
+		utils.Info("Discovering links", 
+			utils.NewField("url", task.URL),
+			utils.NewField("depth", task.Depth),
+			utils.NewField("max_depth", m.config.MaxDepth),
+			utils.NewField("total_internal_links", len(parsedData.InternalLinks)))
+		
+		for _, linkURL := range m.followLinks(parsedData) {
+			// Check domain filter (use normalized start URLs for comparison)
+			if m.config.DomainFilter == "same" && !m.inScope(linkURL) {
+				domainSkippedCount++
+				utils.Info("Skipping link - different domain", 
+					utils.NewField("link", linkURL), 
+					utils.NewField("start_url", m.normalizedStartURL))
+				m.logSkip(linkURL, task.URL, task.Depth+1, SkipDomain)
 				continue
 			}
 
-			// Check if we have body content
-			if len(result.Body) == 0 {
-				utils.Warn("No body content to parse", utils.NewField("url", task.URL))
+			// Nofollow links are recorded but not followed, as search engines do
+			if m.config.RespectNofollow && nofollow[linkURL] {
+				nofollowSkippedCount++
+				utils.Debug("Skipping link - nofollow", utils.NewField("link", linkURL))
+				m.logSkip(linkURL, task.URL, task.Depth+1, SkipNofollow)
 				continue
 			}
 
-			// Parse HTML and discover links
-			parser, err := NewParser(task.URL)
-			if err != nil {
-				utils.Error("Failed to create parser", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+			// Links outside --scope-path stay in the link graph but aren't followed
+			if !m.isStartURL(linkURL) && !m.inScopePath(linkURL) {
+				scopeSkippedCount++
+				utils.Debug("Skipping link - outside scope path", utils.NewField("link", linkURL))
+				m.logSkip(linkURL, task.URL, task.Depth+1, SkipScope)
 				continue
 			}
 
-			// Merge parsed SEO data into result
-			parsedData, err := parser.Parse(result.Body)
-			if err != nil {
-				utils.Error("Failed to parse HTML", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+			// Check include/exclude rules
+			if !m.urlFilter.Allows(linkURL) {
+				filterSkippedCount++
+				utils.Debug("Skipping link - excluded by URL filter", utils.NewField("link", linkURL))
+				m.logSkip(linkURL, task.URL, task.Depth+1, SkipFilter)
 				continue
 			}
-			
-			utils.Info("Parsed page", 
-				utils.NewField("url", task.URL), 
-				utils.NewField("depth", task.Depth),
-				utils.NewField("internal_links", len(parsedData.InternalLinks)),
-				utils.NewField("external_links", len(parsedData.ExternalLinks)),
-				utils.NewField("body_size", len(result.Body)))
-
-			// Merge parsed data into page result
-			result.PageResult.Title = parsedData.Title
-			result.PageResult.MetaDesc = parsedData.MetaDesc
-			result.PageResult.Canonical = parsedData.Canonical
-			result.PageResult.H1 = parsedData.H1
-			result.PageResult.H2 = parsedData.H2
-			result.PageResult.H3 = parsedData.H3
-			result.PageResult.H4 = parsedData.H4
-			result.PageResult.H5 = parsedData.H5
-			result.PageResult.H6 = parsedData.H6
-			result.PageResult.InternalLinks = parsedData.InternalLinks
-			result.PageResult.ExternalLinks = parsedData.ExternalLinks
-
-			// Add edges to link graph
-			m.linkGraph.AddEdges(task.URL, parsedData.InternalLinks)
-			m.linkGraph.AddEdges(task.URL, parsedData.ExternalLinks)
-
-			// Enqueue discovered internal links for crawling
-			// Only discover links if we haven't reached max depth yet
-			if task.Depth < m.config.MaxDepth {
-				enqueuedCount := 0
-				skippedCount := 0
-				domainSkippedCount := 0
-				visitedSkippedCount := 0
-				
-				utils.Info("Discovering links", 
-					utils.NewField("url", task.URL),
-					utils.NewField("depth", task.Depth),
-					utils.NewField("max_depth", m.config.MaxDepth),
-					utils.NewField("total_internal_links", len(parsedData.InternalLinks)))
-				
-				for _, linkURL := range parsedData.InternalLinks {
-					// Check domain filter (use normalized start URL for comparison)
-					if m.config.DomainFilter == "same" && !utils.IsSameDomain(linkURL, m.normalizedStartURL) {
-						domainSkippedCount++
-						utils.Info("Skipping link - different domain", 
-							utils.NewField("link", linkURL), 
-							utils.NewField("start_url", m.normalizedStartURL))
-						continue
-					}
-
-					// Check if already visited
-					if _, visited := m.visited.Load(linkURL); visited {
-						visitedSkippedCount++
-						utils.Info("Skipping link - already visited", utils.NewField("link", linkURL))
-						continue
-					}
-
-					// Enqueue new task (check if queue is still open)
-					// Check if queue is closed before attempting to send
-					if atomic.LoadInt32(&m.queueClosed) == 1 {
-						utils.Warn("Queue closed, stopping link discovery", utils.NewField("url", task.URL))
-						return
-					}
-					
-					select {
-					case <-m.ctx.Done():
-						utils.Info("Context cancelled, stopping link discovery")
-						return
-					case m.queue <- crawlTask{URL: linkURL, Depth: task.Depth + 1}:
-						// Successfully enqueued
-						atomic.AddInt32(&m.pending, 1)
-						enqueuedCount++
-						utils.Info("Enqueued link", utils.NewField("link", linkURL), utils.NewField("new_depth", task.Depth+1))
-					default:
-						// Queue full, skip (but don't panic)
-						utils.Warn("Queue full, skipping link", utils.NewField("url", linkURL))
-						skippedCount++
-					}
-				}
-				utils.Info("Link discovery complete", 
-					utils.NewField("url", task.URL),
-					utils.NewField("enqueued", enqueuedCount),
-					utils.NewField("skipped_domain", domainSkippedCount),
-					utils.NewField("skipped_visited", visitedSkippedCount),
-					utils.NewField("skipped_queue_full", skippedCount),
-					utils.NewField("total_internal", len(parsedData.InternalLinks)))
-			} else {
-				utils.Info("Max depth reached, not discovering links", 
-					utils.NewField("url", task.URL),
-					utils.NewField("depth", task.Depth),
-					utils.NewField("max_depth", m.config.MaxDepth))
+
+			// Check if already visited
+			if m.visited.Contains(linkURL) {
+				visitedSkippedCount++
+				m.skipped.duplicate()
+				utils.Info("Skipping link - already visited", utils.NewField("link", linkURL))
+				continue
 			}
 
-			// Check if we've reached max pages
-			if resultCount >= m.config.MaxPages {
-				m.cancel()
-				return
+			// After a cancellation, links are only kept as pending for a
+			// resumed crawl
+			next := crawlTask{URL: linkURL, Depth: task.Depth + 1, Priority: m.taskPriority(linkURL, task.Depth+1, task.URL)}
+			m.addPending(next)
+			if m.ctx.Err() != nil {
+				continue
 			}
+
+			// Enqueue new task. The queue stays open while this task is
+			// outstanding, so the push can't race with closing it.
+			m.tasks.Add(1)
+			m.queue.push(next)
+			enqueuedCount++
+			utils.Info("Enqueued link", utils.NewField("link", linkURL), utils.NewField("new_depth", task.Depth+1))
 		}
+		utils.Info("Link discovery complete", 
+			utils.NewField("url", task.URL),
+			utils.NewField("enqueued", enqueuedCount),
+			utils.NewField("skipped_domain", domainSkippedCount),
+			utils.NewField("skipped_visited", visitedSkippedCount),
+			utils.NewField("skipped_filter", filterSkippedCount),
+			utils.NewField("skipped_scope", scopeSkippedCount),
+			utils.NewField("skipped_nofollow", nofollowSkippedCount),
+			utils.NewField("total_internal", len(parsedData.InternalLinks)))
+	} else {
+		utils.Info("Max depth reached, not discovering links", 
+			utils.NewField("url", task.URL),
+			utils.NewField("depth", task.Depth),
+			utils.NewField("max_depth", m.config.MaxDepth))
+		if len(m.config.URLList) == 0 {
+			m.logDepthSkips(task, m.followLinks(parsedData), nofollow)
+		}
+	}
+
+	// Check if we've reached max pages
+	if resultCount >= m.config.MaxPages {
+		m.cancel()
+		return
 	}
 }
 
-// monitorQueue closes the queue when all tasks are processed
-func (m *Manager) monitorQueue() {
-	ticker := time.NewTicker(2 * time.Second) // Check every 2 seconds (less frequent)
-	defer ticker.Stop()
-	
-	emptyCount := 0 // Count consecutive empty checks
-	const maxEmptyChecks = 5 // Close after 5 consecutive empty checks (10 seconds total)
-	// Increased timeout to allow workers time to discover and enqueue links
+// parseResult parses a fetched page and merges the SEO data into its result.
+// It returns nil if the fetch failed, the page isn't a 200, or parsing failed.
+func (m *Manager) parseResult(task crawlTask, result *FetchResult) *models.PageResult {
+	// If fetch failed or not HTML, don't discover links
+	if result.Error != nil || result.PageResult.StatusCode != 200 {
+		utils.Info("Skipping link discovery - fetch failed or non-200", 
+			utils.NewField("url", task.URL),
+			utils.NewField("error", result.Error),
+			utils.NewField("status", result.PageResult.StatusCode))
+		return nil
+	}
 
-	for {
-		select {
-		case <-m.ctx.Done():
-			utils.Info("Monitor queue: context cancelled, closing queue")
-			atomic.StoreInt32(&m.queueClosed, 1)
-			close(m.queue)
-			return
-		case <-ticker.C:
-			// Check if queue is empty and no pending tasks
-			pending := atomic.LoadInt32(&m.pending)
-			queueLen := len(m.queue)
-			resultCount := len(m.results)
-			
-			utils.Info("Monitor queue check", 
-				utils.NewField("pending", pending),
-				utils.NewField("queue_len", queueLen),
-				utils.NewField("results", resultCount),
-				utils.NewField("empty_count", emptyCount),
-				utils.NewField("max_pages", m.config.MaxPages))
-			
-			// Only close if:
-			// 1. Queue is empty AND no pending tasks
-			// 2. We haven't reached max pages (if we have, workers will cancel)
-			// 3. We've had multiple consecutive empty checks
-			if pending <= 0 && queueLen == 0 && resultCount < m.config.MaxPages {
-				emptyCount++
-				if emptyCount >= maxEmptyChecks {
-					utils.Info("Closing queue - no pending tasks after multiple checks", 
-						utils.NewField("empty_checks", emptyCount),
-						utils.NewField("total_results", resultCount),
-						utils.NewField("max_pages", m.config.MaxPages))
-					atomic.StoreInt32(&m.queueClosed, 1)
-					close(m.queue)
-					return
-				}
-			} else {
-				// Reset counter if we have pending work or reached max pages
-				emptyCount = 0
-			}
-		}
+	// Files such as PDFs and images have no links to discover
+	if !isHTMLContentType(result.ContentType) {
+		utils.Debug("Skipping link discovery - not HTML",
+			utils.NewField("url", task.URL),
+			utils.NewField("content_type", result.ContentType))
+		return nil
+	}
+
+	// Check if we have body content
+	if len(result.Body) == 0 {
+		utils.Warn("No body content to parse", utils.NewField("url", task.URL))
+		return nil
+	}
+
+	// Parse HTML and discover links
+	parser, err := NewParser(task.URL)
+	if err != nil {
+		utils.Error("Failed to create parser", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+		result.PageResult.Error = fmt.Sprintf("failed to create parser: %v", err)
+		result.PageResult.ErrorCode = models.ErrorParse
+		return nil
 	}
+
+	// Merge parsed SEO data into result
+	parsedData, err := parser.Parse(result.Body)
+	if err != nil {
+		utils.Error("Failed to parse HTML", utils.NewField("url", task.URL), utils.NewField("error", err.Error()))
+		result.PageResult.Error = fmt.Sprintf("failed to parse HTML: %v", err)
+		result.PageResult.ErrorCode = models.ErrorParse
+		return nil
+	}
+	
+	utils.Info("Parsed page", 
+		utils.NewField("url", task.URL), 
+		utils.NewField("depth", task.Depth),
+		utils.NewField("internal_links", len(parsedData.InternalLinks)),
+		utils.NewField("external_links", len(parsedData.ExternalLinks)),
+		utils.NewField("body_size", len(result.Body)))
+
+	// Merge parsed data into page result
+	mergeParsed(result.PageResult, parsedData)
+
+	return parsedData
 }
 
 // handleSignals sets up graceful shutdown on interrupt signals
@@ -440,7 +910,10 @@ func (m *Manager) handleSignals() {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	<-sigChan
+	// A second interrupt kills the process as usual
+	signal.Stop(sigChan)
 	utils.Info("Received interrupt signal, shutting down gracefully...")
+	m.interrupted.Store(true)
 	m.cancel()
 }
 
@@ -0,0 +1,192 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// spillSite is a site whose home page links to more pages than a
+// --low-memory queue keeps in memory, so the crawl's queue spills to disk.
+// Each page links to two others, so tasks are pushed while the queue
+// drains, and the flaky pages answer 503 until they have been requested
+// failures times. Pages take delay to answer, and the requests made and in
+// flight are counted.
+type spillSite struct {
+	*httptest.Server
+
+	pages    int
+	failures int
+	flaky    map[int]bool
+	delay    time.Duration
+
+	mu        sync.Mutex
+	requested map[int]int
+
+	requests    atomic.Int32
+	inFlight    atomic.Int32
+	maxInFlight atomic.Int32
+}
+
+func newSpillSite(t *testing.T, pages int, flaky ...int) *spillSite {
+	return startSpillSite(t, &spillSite{pages: pages}, flaky)
+}
+
+// newSlowSite returns a spillSite whose pages take 10ms to answer, so crawls
+// of it last long enough to be controlled
+func newSlowSite(t *testing.T, pages int) *spillSite {
+	return startSpillSite(t, &spillSite{pages: pages, delay: 10 * time.Millisecond}, nil)
+}
+
+func startSpillSite(t *testing.T, site *spillSite, flaky []int) *spillSite {
+	site.failures = 4
+	site.flaky = make(map[int]bool)
+	site.requested = make(map[int]int)
+	for _, n := range flaky {
+		site.flaky[n] = true
+	}
+	site.Server = httptest.NewServer(http.HandlerFunc(site.serve))
+	t.Cleanup(site.Close)
+	return site
+}
+
+func (s *spillSite) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	if r.URL.Path == "/" {
+		fmt.Fprint(w, "<html><head><title>Home</title></head><body>")
+		for i := 0; i < s.pages; i++ {
+			fmt.Fprintf(w, `<a href="/p/%d">%d</a>`, i, i)
+		}
+		fmt.Fprint(w, "</body></html>")
+		return
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/p/"))
+	if err != nil || n >= s.pages {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.requests.Add(1)
+	current := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		max := s.maxInFlight.Load()
+		if current <= max || s.maxInFlight.CompareAndSwap(max, current) {
+			break
+		}
+	}
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	s.requested[n]++
+	requests := s.requested[n]
+	s.mu.Unlock()
+	if s.flaky[n] && requests <= s.failures {
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintf(w, `<html><head><title>Page %d</title></head><body><a href="/p/%d">next</a> <a href="/p/%d">other</a></body></html>`,
+		n, (n+1)%s.pages, (n*7+3)%s.pages)
+}
+
+// spilledSegments returns how many segments m's queue wrote to disk
+func spilledSegments(m *Manager) int {
+	m.queue.mu.Lock()
+	defer m.queue.mu.Unlock()
+	return m.queue.nextSegment
+}
+
+func TestCrawlCompletesWithSpilledQueueAndRetries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits out the fetcher's retry backoff")
+	}
+	site := newSpillSite(t, 2500, 10, 1200, 2400)
+	config := testConfig(site.URL, 8)
+	config.MaxPages = 5000
+	config.LowMemory = true
+	m := NewManager(config)
+
+	results, err := crawlAsync(t, m)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spilledSegments(m) == 0 {
+		t.Error("the queue never spilled to disk")
+	}
+
+	// Every page is crawled once, and the flaky pages recover on the final
+	// retry pass
+	if len(results) != site.pages+1 {
+		t.Errorf("crawled %d pages, want %d", len(results), site.pages+1)
+	}
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if seen[result.URL] {
+			t.Errorf("%s crawled twice", result.URL)
+		}
+		seen[result.URL] = true
+		if result.StatusCode != http.StatusOK {
+			t.Errorf("%s: status %d", result.URL, result.StatusCode)
+		}
+	}
+	site.mu.Lock()
+	defer site.mu.Unlock()
+	for n := range site.flaky {
+		if got := site.requested[n]; got != site.failures+1 {
+			t.Errorf("/p/%d requested %d times, want %d", n, got, site.failures+1)
+		}
+	}
+	if m.Status() != StatusSucceeded {
+		t.Errorf("status %q, want %q", m.Status(), StatusSucceeded)
+	}
+}
+
+func TestCrawlStopsWithSpilledQueue(t *testing.T) {
+	site := newSpillSite(t, 2500)
+	config := testConfig(site.URL, 8)
+	config.MaxPages = 5000
+	config.LowMemory = true
+	m := NewManager(config)
+	wait := crawlAsync(t, m)
+
+	// Stop once the home page's links are queued, leaving spilled tasks
+	// that are discarded rather than waited on
+	deadline := time.Now().Add(10 * time.Second)
+	for spilledSegments(m) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	m.Stop()
+
+	results, err := wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) > site.pages {
+		t.Errorf("crawled %d pages after Stop", len(results))
+	}
+	if m.Status() != StatusInterrupted {
+		t.Errorf("status %q, want %q", m.Status(), StatusInterrupted)
+	}
+}
+
+func TestCrawlCompletesRepeatedly(t *testing.T) {
+	// Small crawls with more workers than pages finish without waiting on a
+	// worker that never got a task
+	for i := 0; i < 20; i++ {
+		site := newSpillSite(t, 5)
+		m := NewManager(testConfig(site.URL, 16))
+		results, err := crawlAsync(t, m)()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != site.pages+1 {
+			t.Fatalf("run %d crawled %d pages, want %d", i, len(results), site.pages+1)
+		}
+	}
+}
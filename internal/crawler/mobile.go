@@ -0,0 +1,39 @@
+package crawler
+
+import (
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// withUserAgent returns a fetcher sending userAgent instead, sharing f's
+// connections, headers, cookies, and rate limiter. It fetches over plain
+// HTTP without the cache, whose entries hold f's responses.
+func (f *Fetcher) withUserAgent(userAgent string) *Fetcher {
+	other := *f
+	other.userAgent = userAgent
+	other.renderer = nil
+	other.cache = nil
+	return &other
+}
+
+// fetchMobile fetches a page again as a smartphone crawler (--mobile-parity)
+// and records what it was served. It returns nil when the crawl was
+// cancelled while waiting for the host's turn.
+func (m *Manager) fetchMobile(url string) *models.MobileVariant {
+	if !m.limiter.Wait(url) {
+		return nil
+	}
+	result := m.mobileFetcher.FetchWithRetry(url, 3)
+	page := result.PageResult
+	if page.StatusCode == 200 && len(result.Body) > 0 && isHTMLContentType(result.ContentType) {
+		if parser, err := NewParser(finalURL(result)); err == nil {
+			if parsed, err := parser.Parse(result.Body); err == nil {
+				mergeParsed(page, parsed)
+			} else {
+				utils.Debug("Failed to parse mobile page", utils.NewField("url", url), utils.NewField("error", err.Error()))
+			}
+		}
+	}
+	setRobots(page)
+	return models.NewMobileVariant(page)
+}
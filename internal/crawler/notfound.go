@@ -0,0 +1,47 @@
+package crawler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// notFoundProbePrefix starts the made-up path ProbeNotFound requests
+const notFoundProbePrefix = "/barracuda-404-check-"
+
+// ProbeNotFound requests a random path that can't exist on the start URL's
+// host, to see how the site answers for missing pages. Redirects are
+// followed, and the final response is parsed whatever its status, so the
+// error page's title and links can be checked. A failed request is reported
+// through the page's Error, as in a crawl.
+func (m *Manager) ProbeNotFound() (*models.PageResult, error) {
+	u, err := url.Parse(m.normalizedStartURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start URL: %w", err)
+	}
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("failed to generate probe path: %w", err)
+	}
+	probeURL := fmt.Sprintf("%s://%s%s%s", u.Scheme, u.Host, notFoundProbePrefix, hex.EncodeToString(token))
+
+	result := m.fetcher.Fetch(probeURL)
+	page := result.PageResult
+	defer setRobots(page)
+	// Error is also set for the 404 itself, so only a missing response or
+	// body means there is nothing to parse
+	if page.StatusCode == 0 || len(result.Body) == 0 || !isHTMLContentType(result.ContentType) {
+		return page, nil
+	}
+	parser, err := NewParser(finalURL(result))
+	if err != nil {
+		return page, nil
+	}
+	if parsed, err := parser.Parse(result.Body); err == nil {
+		mergeParsed(page, parsed)
+	}
+	return page, nil
+}
@@ -0,0 +1,76 @@
+package crawler
+
+import (
+	"fmt"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// FetchPage fetches a single URL and parses it the same way the crawler does,
+// without discovering or following links. A failed fetch is reported through
+// the returned page's Error rather than an error value, as in a crawl.
+func (f *Fetcher) FetchPage(url string, maxRetries int) *models.PageResult {
+	result := f.FetchWithRetry(url, maxRetries)
+	page := result.PageResult
+	defer setRobots(page)
+	if result.Error != nil || page.StatusCode != 200 || len(result.Body) == 0 {
+		return page
+	}
+
+	parser, err := NewParser(url)
+	if err != nil {
+		page.Error = fmt.Sprintf("failed to create parser: %v", err)
+		page.ErrorCode = models.ErrorParse
+		return page
+	}
+	parsed, err := parser.Parse(result.Body)
+	if err != nil {
+		page.Error = fmt.Sprintf("failed to parse HTML: %v", err)
+		page.ErrorCode = models.ErrorParse
+		return page
+	}
+	mergeParsed(page, parsed)
+	return page
+}
+
+// setRobots records the robots directives of a fetched and parsed page
+func setRobots(page *models.PageResult) {
+	if directives := page.RobotsDirectives(); directives != (models.RobotsDirectives{}) {
+		page.Robots = &directives
+	}
+}
+
+// mergeParsed copies the SEO data extracted by the parser into page
+func mergeParsed(page, parsed *models.PageResult) {
+	page.Title = parsed.Title
+	page.MetaDesc = parsed.MetaDesc
+	page.Canonical = parsed.Canonical
+	page.RelNext = parsed.RelNext
+	page.RelPrev = parsed.RelPrev
+	page.OGURL = parsed.OGURL
+	page.PublishedAt = parsed.PublishedAt
+	page.ModifiedAt = parsed.ModifiedAt
+	page.H1 = parsed.H1
+	page.H2 = parsed.H2
+	page.H3 = parsed.H3
+	page.H4 = parsed.H4
+	page.H5 = parsed.H5
+	page.H6 = parsed.H6
+	page.InternalLinks = parsed.InternalLinks
+	page.ExternalLinks = parsed.ExternalLinks
+	page.Links = parsed.Links
+	page.FragmentLinks = parsed.FragmentLinks
+	page.Anchors = parsed.Anchors
+	page.Images = parsed.Images
+	page.Assets = parsed.Assets
+	page.ThirdParty = parsed.ThirdParty
+	page.MetaRobots = parsed.MetaRobots
+	page.Viewport = parsed.Viewport
+	page.Hreflang = parsed.Hreflang
+	page.StructuredData = parsed.StructuredData
+	page.ExpectedSchema = parsed.ExpectedSchema
+	page.WordCount = parsed.WordCount
+	page.TextHash = parsed.TextHash
+	page.SimHash = parsed.SimHash
+	page.Placeholder = parsed.Placeholder
+}
@@ -1,36 +1,45 @@
 package crawler
 
 import (
+	"bytes"
+	"encoding/json"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
-	"github.com/PuerkitoBio/goquery"
 	"github.com/dillonlara115/barracuda/internal/utils"
 	"github.com/dillonlara115/barracuda/pkg/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 // Parser extracts SEO data from HTML content
 type Parser struct {
 	baseURL string
+	base    *url.URL // Parsed once so links resolve without re-parsing it
 	domain  string
 }
 
 // NewParser creates a new Parser instance
 func NewParser(baseURL string) (*Parser, error) {
-	domain, err := utils.ExtractDomain(baseURL)
+	base, err := url.Parse(baseURL)
 	if err != nil {
-		return nil, err
+		return nil, utils.ErrInvalidURL
 	}
 
 	return &Parser{
 		baseURL: baseURL,
-		domain:  domain,
+		base:    base,
+		domain:  base.Host,
 	}, nil
 }
 
-// Parse extracts SEO data from HTML content
+// Parse extracts SEO data from HTML content in a single pass over the document
 func (p *Parser) Parse(htmlContent []byte) (*models.PageResult, error) {
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(htmlContent)))
+	doc, err := html.Parse(bytes.NewReader(htmlContent))
 	if err != nil {
 		return nil, err
 	}
@@ -48,156 +57,619 @@ func (p *Parser) Parse(htmlContent []byte) (*models.PageResult, error) {
 		Images:        make([]models.Image, 0),
 	}
 
-	// Extract title
-	result.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	state := &parseState{
+		result:        result,
+		seenLinks:     make(map[string]bool),
+		seenURLs:      make(map[string]bool),
+		seenFragments: make(map[string]bool),
+		seenAnchors:   make(map[string]bool),
+		seenImages:    make(map[string]bool),
+		seenAssets:    make(map[string]bool),
+	}
+	p.walk(doc, state)
+	result.WordCount = state.words.count
+	result.TextHash, result.SimHash = state.text.sums()
 
-	// Extract meta description
-	doc.Find("meta[name='description']").Each(func(i int, s *goquery.Selection) {
-		if content, exists := s.Attr("content"); exists {
-			result.MetaDesc = strings.TrimSpace(content)
+	return result, nil
+}
+
+// parseState carries what Parse has collected while walking the document
+type parseState struct {
+	result        *models.PageResult
+	titleFound    bool
+	seenLinks     map[string]bool // URL + "\x00" + anchor text
+	seenURLs      map[string]bool // Internal and external link URLs
+	seenFragments map[string]bool // Internal link URLs with their fragment
+	seenAnchors   map[string]bool
+	seenImages    map[string]bool
+	seenAssets    map[string]bool
+
+	// Open elements that affect extraction
+	inBody     int // Inside <body>
+	inSkipped  int // Inside script, style, noscript, or template (not visible words)
+	inItemType int // Inside an element with itemscope
+
+	words wordCounter
+	text  textHasher
+}
+
+// walk visits n and its descendants in document order
+func (p *Parser) walk(n *html.Node, state *parseState) {
+	switch n.Type {
+	case html.TextNode:
+		if state.inBody > 0 && state.inSkipped == 0 {
+			state.words.Write(n.Data)
+			state.text.Write(n.Data)
+			if state.result.Placeholder == "" {
+				state.result.Placeholder = findPlaceholder(n.Data)
+			}
 		}
-	})
+		return
+	case html.ElementNode:
+		p.element(n, state)
+	}
+
+	body := n.Type == html.ElementNode && n.DataAtom == atom.Body
+	skipped := n.Type == html.ElementNode && isNonContent(n.DataAtom)
+	itemScope := n.Type == html.ElementNode && hasAttr(n, "itemscope")
+	if body {
+		state.inBody++
+	}
+	if skipped {
+		state.inSkipped++
+	}
+	if itemScope {
+		state.inItemType++
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.walk(c, state)
+	}
+
+	if body {
+		state.inBody--
+	}
+	if skipped {
+		state.inSkipped--
+	}
+	if itemScope {
+		state.inItemType--
+	}
+}
 
-	// Extract canonical link
-	doc.Find("link[rel='canonical']").Each(func(i int, s *goquery.Selection) {
-		if href, exists := s.Attr("href"); exists {
+// element extracts the SEO data of a single element
+func (p *Parser) element(n *html.Node, state *parseState) {
+	result := state.result
+
+	switch n.DataAtom {
+	case atom.Title:
+		if !state.titleFound {
+			state.titleFound = true
+			result.Title = strings.TrimSpace(nodeText(n))
+		}
+	case atom.Meta:
+		content, ok := attr(n, "content")
+		if !ok {
+			break
+		}
+		switch property, _ := attr(n, "property"); property {
+		case "og:url":
+			result.OGURL = strings.TrimSpace(content)
+		case "og:type":
+			switch strings.ToLower(strings.TrimSpace(content)) {
+			case "article":
+				expectSchema(result, "Article")
+			case "product", "og:product", "product.item":
+				expectSchema(result, "Product")
+			}
+		case "product:price:amount":
+			expectSchema(result, "Product")
+		case "article:published_time":
+			expectSchema(result, "Article")
+			if result.PublishedAt == nil {
+				result.PublishedAt = parseDate(content)
+			}
+		case "article:modified_time", "og:updated_time":
+			// article:modified_time wins over og:updated_time
+			if result.ModifiedAt == nil || property == "article:modified_time" {
+				if t := parseDate(content); t != nil {
+					result.ModifiedAt = t
+				}
+			}
+		}
+		switch name, _ := attr(n, "name"); strings.ToLower(name) {
+		case "description":
+			result.MetaDesc = strings.TrimSpace(content)
+		case "viewport":
+			result.Viewport = strings.TrimSpace(content)
+		case "robots":
+			// Search engines combine the directives of every robots tag
+			if content = strings.TrimSpace(content); result.MetaRobots != "" && content != "" {
+				result.MetaRobots += ", " + content
+			} else if content != "" {
+				result.MetaRobots = content
+			}
+		}
+	case atom.Link:
+		rel, _ := attr(n, "rel")
+		href, hasHref := attr(n, "href")
+		if !hasHref {
+			break
+		}
+		if hasToken(rel, "stylesheet") {
+			p.addAsset(n, href, models.AssetStylesheet, state)
+		}
+		switch rel {
+		case "canonical":
 			result.Canonical = strings.TrimSpace(href)
+		case "next", "prev", "previous":
+			if resolvedURL, err := utils.ResolveURL(p.baseURL, href); err == nil {
+				if rel == "next" {
+					result.RelNext = resolvedURL
+				} else {
+					result.RelPrev = resolvedURL
+				}
+			}
+		case "alternate":
+			if lang, ok := attr(n, "hreflang"); ok {
+				if resolvedURL, err := utils.ResolveURL(p.baseURL, href); err == nil {
+					result.Hreflang = append(result.Hreflang, models.Hreflang{
+						Lang: strings.TrimSpace(lang),
+						URL:  resolvedURL,
+					})
+				}
+			}
 		}
-	})
-
-	// Extract headings
-	doc.Find("h1").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
-			result.H1 = append(result.H1, text)
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := strings.TrimSpace(nodeText(n))
+		if text == "" {
+			break
 		}
-	})
-	doc.Find("h2").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
+		switch n.DataAtom {
+		case atom.H1:
+			result.H1 = append(result.H1, text)
+		case atom.H2:
 			result.H2 = append(result.H2, text)
-		}
-	})
-	doc.Find("h3").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
+		case atom.H3:
 			result.H3 = append(result.H3, text)
-		}
-	})
-	doc.Find("h4").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
+		case atom.H4:
 			result.H4 = append(result.H4, text)
-		}
-	})
-	doc.Find("h5").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
+		case atom.H5:
 			result.H5 = append(result.H5, text)
-		}
-	})
-	doc.Find("h6").Each(func(i int, s *goquery.Selection) {
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
+		case atom.H6:
 			result.H6 = append(result.H6, text)
 		}
-	})
+	case atom.A:
+		if href, ok := attr(n, "href"); ok {
+			p.addLink(n, href, state)
+		}
+		if name, ok := attr(n, "name"); ok {
+			addAnchor(name, state)
+		}
+	case atom.Img:
+		if src, ok := attr(n, "src"); ok {
+			p.addImage(n, src, state)
+		}
+	case atom.Script:
+		if src, ok := attr(n, "src"); ok {
+			p.addAsset(n, src, models.AssetScript, state)
+		}
+		if typ, _ := attr(n, "type"); typ == "application/ld+json" {
+			result.StructuredData = append(result.StructuredData, jsonLDItems(nodeText(n))...)
+		}
+	}
 
-	// Extract links
-	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
-		href, exists := s.Attr("href")
-		if !exists {
-			return
+	// Only top-level microdata items; nested ones describe properties
+	if state.inItemType == 0 && hasAttr(n, "itemscope") {
+		if itemType, ok := attr(n, "itemtype"); ok {
+			for _, t := range strings.Fields(itemType) {
+				result.StructuredData = append(result.StructuredData, models.StructuredData{Format: "microdata", Type: schemaType(t)})
+			}
 		}
+	}
 
-		// Resolve relative URLs
-		resolvedURL, err := utils.ResolveURL(p.baseURL, href)
-		if err != nil {
-			return
+	if id, ok := attr(n, "id"); ok {
+		addAnchor(id, state)
+	}
+
+	if state.inBody > 0 && isBreadcrumb(n) {
+		expectSchema(result, "BreadcrumbList")
+	}
+}
+
+// expectSchema records that a page's markup calls for structured data of
+// type t
+func expectSchema(result *models.PageResult, t string) {
+	if !slices.Contains(result.ExpectedSchema, t) {
+		result.ExpectedSchema = append(result.ExpectedSchema, t)
+	}
+}
+
+// isBreadcrumb reports whether an element is a breadcrumb trail, going by
+// the aria-label, class, or id themes and accessibility guides give them
+func isBreadcrumb(n *html.Node) bool {
+	for _, key := range []string{"aria-label", "class", "id"} {
+		if value, ok := attr(n, key); ok && strings.Contains(strings.ToLower(value), "breadcrumb") {
+			return true
 		}
+	}
+	return false
+}
 
-		// Normalize URL
-		normalizedURL, err := utils.NormalizeURL(resolvedURL)
-		if err != nil {
-			return
+// addLink records an anchor's link and categorizes it as internal or external
+func (p *Parser) addLink(n *html.Node, href string, state *parseState) {
+	normalizedURL, host, ok := p.resolveHTTP(href)
+	if !ok {
+		return
+	}
+
+	result := state.result
+	internal := utils.IsSameHost(host, p.domain)
+
+	// Record every distinct anchor with its text, rel, and target
+	rel, _ := attr(n, "rel")
+	target, _ := attr(n, "target")
+	link := models.Link{
+		URL:      normalizedURL,
+		Text:     anchorText(n),
+		Rel:      strings.TrimSpace(rel),
+		Target:   strings.TrimSpace(target),
+		Internal: internal,
+	}
+	if key := link.URL + "\x00" + link.Text; !state.seenLinks[key] {
+		state.seenLinks[key] = true
+		result.Links = append(result.Links, link)
+	}
+
+	// Keep the fragments of internal links so their targets can be checked
+	if _, fragment, ok := strings.Cut(href, "#"); ok && internal && fragment != "" {
+		if key := normalizedURL + "#" + fragment; !state.seenFragments[key] {
+			state.seenFragments[key] = true
+			result.FragmentLinks = append(result.FragmentLinks, key)
 		}
+	}
+
+	// Categorize as internal or external, avoiding duplicates
+	if state.seenURLs[normalizedURL] {
+		return
+	}
+	state.seenURLs[normalizedURL] = true
+	if internal {
+		result.InternalLinks = append(result.InternalLinks, normalizedURL)
+	} else {
+		result.ExternalLinks = append(result.ExternalLinks, normalizedURL)
+	}
+}
+
+// addAnchor records an id or name a link's #fragment can point to
+func addAnchor(value string, state *parseState) {
+	if value == "" || state.seenAnchors[value] {
+		return
+	}
+	state.seenAnchors[value] = true
+	state.result.Anchors = append(state.result.Anchors, value)
+}
+
+// addImage records an image, skipping data URIs and duplicates
+func (p *Parser) addImage(n *html.Node, src string, state *parseState) {
+	normalizedURL, _, ok := p.resolveHTTP(src)
+	if !ok || state.seenImages[normalizedURL] {
+		return
+	}
+	state.seenImages[normalizedURL] = true
+
+	alt, _ := attr(n, "alt")
+	state.result.Images = append(state.result.Images, models.Image{
+		URL:    normalizedURL,
+		Alt:    alt,
+		Width:  pixelAttr(n, "width"),
+		Height: pixelAttr(n, "height"),
+	})
+}
+
+// addAsset records a script or stylesheet the page loads, and adds those
+// from other sites to the page's third-party inventory with their integrity
+// attribute
+func (p *Parser) addAsset(n *html.Node, ref, assetType string, state *parseState) {
+	normalizedURL, host, ok := p.resolveHTTP(ref)
+	if !ok || state.seenAssets[normalizedURL] {
+		return
+	}
+	state.seenAssets[normalizedURL] = true
+	state.result.Assets = append(state.result.Assets, normalizedURL)
 
-		// Skip fragments, javascript:, mailto:, etc.
-		u, err := url.Parse(normalizedURL)
-		if err != nil {
-			return
+	if utils.IsSameHost(host, p.domain) {
+		return
+	}
+	integrity, _ := attr(n, "integrity")
+	state.result.ThirdParty = append(state.result.ThirdParty, models.ThirdPartyAsset{
+		URL:       normalizedURL,
+		Type:      assetType,
+		Integrity: strings.TrimSpace(integrity),
+	})
+}
+
+// hasToken reports whether a space-separated attribute value such as rel
+// contains token, ignoring case
+func hasToken(value, token string) bool {
+	for _, field := range strings.Fields(value) {
+		if strings.EqualFold(field, token) {
+			return true
 		}
+	}
+	return false
+}
+
+// resolveHTTP resolves a reference against the page URL and normalizes it
+// like utils.ResolveURL followed by utils.NormalizeURL, parsing only the
+// reference. It also returns the host, and reports false for unparseable
+// references and non-HTTP schemes such as javascript:, mailto:, and data:.
+func (p *Parser) resolveHTTP(ref string) (string, string, bool) {
+	rel, err := url.Parse(ref)
+	if err != nil {
+		return "", "", false
+	}
+	resolved := p.base.ResolveReference(rel)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return "", "", false
+	}
 
-		if u.Scheme != "http" && u.Scheme != "https" {
-			return
+	resolved.Fragment = ""
+	resolved.RawFragment = ""
+	normalized := resolved.String()
+	// Both normalizations strip one trailing slash
+	root := resolved.Scheme + "://" + resolved.Host + "/"
+	for i := 0; i < 2 && normalized != root && strings.HasSuffix(normalized, "/"); i++ {
+		normalized = normalized[:len(normalized)-1]
+	}
+	return normalized, resolved.Host, true
+}
+
+// isNonContent reports whether an element's text is not visible page content
+func isNonContent(a atom.Atom) bool {
+	return a == atom.Script || a == atom.Style || a == atom.Noscript || a == atom.Template
+}
+
+// attr returns the value of an element's attribute
+func attr(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val, true
 		}
+	}
+	return "", false
+}
 
-		// Categorize as internal or external
-		if utils.IsSameDomain(normalizedURL, p.baseURL) {
-			// Avoid duplicates
-			for _, existing := range result.InternalLinks {
-				if existing == normalizedURL {
-					return
-				}
-			}
-			result.InternalLinks = append(result.InternalLinks, normalizedURL)
-		} else {
-			// Avoid duplicates
-			for _, existing := range result.ExternalLinks {
-				if existing == normalizedURL {
-					return
+// hasAttr reports whether an element has an attribute
+func hasAttr(n *html.Node, key string) bool {
+	_, ok := attr(n, key)
+	return ok
+}
+
+// pixelAttr returns a width or height attribute in pixels, or 0 when it is
+// missing or not a plain pixel count, such as "50%"
+func pixelAttr(n *html.Node, key string) int {
+	value, _ := attr(n, key)
+	pixels, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(value), "px"))
+	if err != nil || pixels <= 0 {
+		return 0
+	}
+	return pixels
+}
+
+// anchorText returns the text search engines read for a link: the text
+// inside it, or for an image link the images' alt text, or failing both its
+// aria-label. Whitespace is collapsed.
+func anchorText(n *html.Node) string {
+	if text := strings.Join(strings.Fields(nodeText(n)), " "); text != "" {
+		return text
+	}
+	var alts []string
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.DataAtom == atom.Img {
+				if alt, _ := attr(c, "alt"); strings.TrimSpace(alt) != "" {
+					alts = append(alts, alt)
 				}
 			}
-			result.ExternalLinks = append(result.ExternalLinks, normalizedURL)
+			walk(c)
 		}
-	})
+	}
+	walk(n)
+	if len(alts) > 0 {
+		return strings.Join(strings.Fields(strings.Join(alts, " ")), " ")
+	}
+	label, _ := attr(n, "aria-label")
+	return strings.Join(strings.Fields(label), " ")
+}
 
-	// Extract images
-	doc.Find("img").Each(func(i int, s *goquery.Selection) {
-		src, exists := s.Attr("src")
-		if !exists {
-			return
+// nodeText returns the concatenated text of n's descendants
+func nodeText(n *html.Node) string {
+	// Most elements hold a single text node; return it without copying
+	if c := n.FirstChild; c != nil && c.NextSibling == nil && c.Type == html.TextNode {
+		return c.Data
+	}
+	var b strings.Builder
+	appendText(&b, n)
+	return b.String()
+}
+
+// appendText writes the text of n's descendants to b
+func appendText(b *strings.Builder, n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		} else {
+			appendText(b, c)
 		}
+	}
+}
 
-		// Resolve relative URLs
-		resolvedURL, err := utils.ResolveURL(p.baseURL, src)
-		if err != nil {
-			return
+// placeholderPhrases are filler text left over from templates and mockups
+var placeholderPhrases = []string{
+	"lorem ipsum",
+	"dolor sit amet",
+	"consectetur adipiscing",
+}
+
+// findPlaceholder returns the first placeholder phrase in text, or ""
+func findPlaceholder(text string) string {
+	lower := strings.ToLower(text)
+	for _, phrase := range placeholderPhrases {
+		if strings.Contains(lower, phrase) {
+			return phrase
 		}
+	}
+	return ""
+}
+
+// dateLayouts are the date formats accepted in meta tags and sitemaps: the
+// W3C Datetime profile of ISO 8601, plus a date and time with no zone
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
 
-		// Normalize URL
-		normalizedURL, err := utils.NormalizeURL(resolvedURL)
-		if err != nil {
-			return
+// parseDate parses a content date, returning nil when it is empty or in
+// none of dateLayouts
+func parseDate(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
 		}
+	}
+	return nil
+}
 
-		// Skip data URIs and non-HTTP schemes
-		u, err := url.Parse(normalizedURL)
-		if err != nil {
-			return
+// wordCounter counts whitespace-separated words across consecutive writes,
+// as if the written text were concatenated and passed to strings.Fields
+type wordCounter struct {
+	count  int
+	inWord bool
+}
+
+// Write counts the words in s, continuing a word left open by the previous write
+func (w *wordCounter) Write(s string) {
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			w.inWord = false
+		} else if !w.inWord {
+			w.inWord = true
+			w.count++
 		}
+	}
+}
+
+// jsonLDRequired lists the properties search engines need before showing a
+// type as a rich result; items without them are recorded with an Error
+var jsonLDRequired = map[string][]string{
+	"Article":        {"headline"},
+	"NewsArticle":    {"headline"},
+	"BlogPosting":    {"headline"},
+	"Product":        {"name"},
+	"BreadcrumbList": {"itemListElement"},
+}
+
+// jsonLDItems returns the items of a JSON-LD script. A script that isn't
+// valid JSON, or whose top-level objects lack @context or declare no @type,
+// is recorded as one item with an Error.
+func jsonLDItems(text string) []models.StructuredData {
+	invalid := func(reason string) []models.StructuredData {
+		return []models.StructuredData{{Format: "json-ld", Error: reason}}
+	}
 
-		if u.Scheme != "http" && u.Scheme != "https" {
-			return
+	var data interface{}
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		return invalid("invalid JSON: " + err.Error())
+	}
+	nodes, ok := data.([]interface{})
+	if !ok {
+		nodes = []interface{}{data}
+	}
+	for _, node := range nodes {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return invalid("not a JSON object")
+		}
+		if _, ok := object["@context"]; !ok {
+			return invalid("no @context")
 		}
+	}
 
-		// Get alt text
-		alt := s.AttrOr("alt", "")
+	items := jsonLDNodes(data)
+	if len(items) == 0 {
+		return invalid("no @type")
+	}
+	return items
+}
 
-		// Avoid duplicates
-		for _, existing := range result.Images {
-			if existing.URL == normalizedURL {
-				return
+// jsonLDNodes returns the typed items of a JSON-LD document: the top-level
+// ones, those in @graph, and a page's mainEntity and breadcrumb, which are
+// often nested in a WebPage item
+func jsonLDNodes(data interface{}) []models.StructuredData {
+	var items []models.StructuredData
+	switch v := data.(type) {
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, jsonLDNodes(item)...)
+		}
+	case map[string]interface{}:
+		var types []string
+		switch t := v["@type"].(type) {
+		case string:
+			types = append(types, t)
+		case []interface{}:
+			for _, item := range t {
+				if s, ok := item.(string); ok {
+					types = append(types, s)
+				}
+			}
+		}
+		for _, t := range types {
+			item := models.StructuredData{Format: "json-ld", Type: schemaType(t)}
+			for _, property := range jsonLDRequired[item.Type] {
+				if jsonLDEmpty(v[property]) {
+					item.Error = "missing " + property
+					break
+				}
+			}
+			items = append(items, item)
+		}
+		for _, key := range []string{"@graph", "mainEntity", "breadcrumb"} {
+			if nested, ok := v[key]; ok {
+				items = append(items, jsonLDNodes(nested)...)
 			}
 		}
+	}
+	return items
+}
 
-		result.Images = append(result.Images, models.Image{
-			URL: normalizedURL,
-			Alt: alt,
-		})
-	})
+// jsonLDEmpty reports whether a property value is absent, null, or an empty
+// string or list
+func jsonLDEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
 
-	return result, nil
+// schemaType reduces a type written as a URL or prefixed name, such as
+// "https://schema.org/Product" or "schema:Product", to "Product"
+func schemaType(t string) string {
+	t = strings.TrimRight(strings.TrimSpace(t), "/")
+	if i := strings.LastIndexAny(t, "/:#"); i >= 0 {
+		t = t[i+1:]
+	}
+	return t
 }
 
 // ExtractLinks extracts all links from HTML content and returns them as a slice
@@ -0,0 +1,35 @@
+package crawler
+
+import (
+	"os"
+	"testing"
+)
+
+// BenchmarkParse parses a 45KB article page with the head, navigation,
+// structured data, images, and comments of a typical CMS site
+func BenchmarkParse(b *testing.B) {
+	body, err := os.ReadFile("testdata/article.html")
+	if err != nil {
+		b.Fatal(err)
+	}
+	parser, err := NewParser("https://www.greenrow.example/guides/grow-tomatoes-raised-beds/")
+	if err != nil {
+		b.Fatal(err)
+	}
+	result, err := parser.Parse(body)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if len(result.InternalLinks) < 100 || len(result.Images) < 10 {
+		b.Fatalf("fixture parsed to %d internal links and %d images", len(result.InternalLinks), len(result.Images))
+	}
+
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parser.Parse(body); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
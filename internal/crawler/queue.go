@@ -0,0 +1,351 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// queueSegmentSize is how many tasks the queue keeps in memory at each end;
+// tasks in between are written to disk in segments of this size
+const queueSegmentSize = 10000
+
+// lowMemoryQueueSegmentSize is the segment size under --low-memory
+const lowMemoryQueueSegmentSize = 1000
+
+// taskQueue is an unbounded queue of crawl tasks, popped in the order of
+// the crawl strategy: first in, first out for breadth-first crawls, last in,
+// first out for depth-first ones, and highest priority first for priority
+// crawls. The tasks of each priority are a taskList whose oldest and newest
+// tasks are kept in memory, and full segments in between spill to files in a
+// temporary directory, so discovering millions of URLs neither drops links
+// nor holds them all in memory.
+type taskQueue struct {
+	mu         sync.Mutex
+	lifo       bool              // Pop the newest task first (depth-first)
+	prioritize bool              // Pop by crawlTask.Priority; otherwise all tasks share one list
+	lists      map[int]*taskList // Tasks by priority
+	levels     []int             // Priorities with tasks, highest first
+	size       int               // Tasks in the queue, including spilled ones
+	closed     bool
+	ready      chan struct{} // Signalled when tasks are pushed or the queue closes
+
+	segmentSize int
+	dir         string // Spill directory, created on first use
+	spillFailed bool   // Stop spilling after an error and keep tasks in memory
+	nextSegment int
+
+	// dropped is called with the number of tasks lost when a spilled
+	// segment can't be read back, outside the queue's lock
+	dropped func(n int)
+}
+
+// taskList is the tasks of one priority, oldest first
+type taskList struct {
+	head     []crawlTask    // Oldest tasks
+	segments []queueSegment // Spilled tasks, oldest segment first
+	tail     []crawlTask    // Newest tasks, partly spilled when they fill a segment
+	size     int
+}
+
+// queueSegment is a file of spilled tasks
+type queueSegment struct {
+	path  string
+	count int
+}
+
+// newTaskQueue creates an empty queue for a crawl strategy, spilling
+// segments of segmentSize tasks
+func newTaskQueue(segmentSize int, strategy string, dropped func(n int)) *taskQueue {
+	return &taskQueue{
+		lifo:        strategy == utils.StrategyDFS,
+		prioritize:  strategy == utils.StrategyPriority,
+		lists:       make(map[int]*taskList),
+		ready:       make(chan struct{}, 1),
+		segmentSize: segmentSize,
+		dropped:     dropped,
+	}
+}
+
+// push adds a task to the queue. It never blocks; a task pushed after close
+// is ignored.
+func (q *taskQueue) push(task crawlTask) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	list := q.list(task.Priority)
+	q.size++
+	list.size++
+	if len(list.segments) == 0 && len(list.tail) == 0 && len(list.head) < q.segmentSize {
+		list.head = append(list.head, task)
+	} else {
+		list.tail = append(list.tail, task)
+		// Depth-first pops take from the tail, so keep a segment's worth of
+		// it in memory rather than spilling each time it refills
+		limit := q.segmentSize
+		if q.lifo {
+			limit *= 2
+		}
+		if len(list.tail) >= limit && !q.spillFailed {
+			q.spill(list)
+		}
+	}
+	q.signal()
+}
+
+// pop removes the next task, waiting for one to be pushed. It returns false
+// once the queue is closed and empty, or when ctx is done.
+func (q *taskQueue) pop(ctx context.Context) (crawlTask, bool) {
+	for {
+		task, ok, closed := q.tryPop()
+		if ok {
+			return task, true
+		}
+		if closed {
+			return crawlTask{}, false
+		}
+		select {
+		case <-q.ready:
+		case <-ctx.Done():
+			return crawlTask{}, false
+		}
+	}
+}
+
+// tryPop removes the next task without waiting. closed reports whether the
+// queue is closed, when there is no task.
+func (q *taskQueue) tryPop() (task crawlTask, ok, closed bool) {
+	q.mu.Lock()
+	lost := 0
+	for !ok && len(q.levels) > 0 {
+		level := q.levels[0]
+		list := q.lists[level]
+		var n int
+		task, ok, n = q.take(list)
+		lost += n
+		if list.size == 0 {
+			delete(q.lists, level)
+			q.levels = q.levels[1:]
+		}
+	}
+	if ok {
+		q.size--
+		// Wake another waiter for the tasks left, since one signal can stand
+		// for several pushes
+		if q.size > 0 {
+			q.signal()
+		}
+	}
+	closed = q.closed
+	q.mu.Unlock()
+
+	if lost > 0 && q.dropped != nil {
+		q.dropped(lost)
+	}
+	return task, ok, closed
+}
+
+// take removes the oldest task of a list, or the newest when depth-first,
+// and returns the number of tasks lost to unreadable segments. The caller
+// holds q.mu.
+func (q *taskQueue) take(list *taskList) (task crawlTask, ok bool, lost int) {
+	if q.lifo {
+		for len(list.tail) == 0 && len(list.segments) > 0 {
+			lost += q.load(list, true)
+		}
+		switch {
+		case len(list.tail) > 0:
+			task, list.tail = list.tail[len(list.tail)-1], list.tail[:len(list.tail)-1]
+		case len(list.head) > 0:
+			task, list.head = list.head[len(list.head)-1], list.head[:len(list.head)-1]
+		default:
+			return crawlTask{}, false, lost
+		}
+	} else {
+		for len(list.head) == 0 && len(list.segments) > 0 {
+			lost += q.load(list, false)
+		}
+		if len(list.head) == 0 {
+			list.head, list.tail = list.tail, nil
+		}
+		if len(list.head) == 0 {
+			return crawlTask{}, false, lost
+		}
+		task, list.head = list.head[0], list.head[1:]
+	}
+	list.size--
+	return task, true, lost
+}
+
+// list returns the list of a priority, creating it when needed. The caller
+// holds q.mu.
+func (q *taskQueue) list(priority int) *taskList {
+	if !q.prioritize {
+		priority = 0
+	}
+	if list, ok := q.lists[priority]; ok {
+		return list
+	}
+	list := &taskList{}
+	q.lists[priority] = list
+	i := sort.Search(len(q.levels), func(i int) bool { return q.levels[i] < priority })
+	q.levels = slices.Insert(q.levels, i, priority)
+	return list
+}
+
+// close marks the queue as finished, waking every waiting pop, and removes
+// the spill directory
+func (q *taskQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.ready)
+	q.removeDir()
+}
+
+// discard empties the queue, including spilled tasks, and returns how many
+// tasks it held
+func (q *taskQueue) discard() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := q.size
+	q.lists, q.levels, q.size = make(map[int]*taskList), nil, 0
+	q.removeDir()
+	return n
+}
+
+// signal wakes a waiting pop, if any. The caller holds q.mu.
+func (q *taskQueue) signal() {
+	if q.closed {
+		return
+	}
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// spill writes the oldest segment's worth of a list's tail to a new segment
+// file. On failure the tasks stay in memory and later pushes aren't spilled.
+// The caller holds q.mu.
+func (q *taskQueue) spill(list *taskList) {
+	segment, err := q.writeSegment(list.tail[:q.segmentSize])
+	if err != nil {
+		q.spillFailed = true
+		utils.Warn("Failed to spill crawl queue to disk; keeping it in memory", utils.NewField("error", err.Error()))
+		return
+	}
+	list.segments = append(list.segments, segment)
+	list.tail = append([]crawlTask(nil), list.tail[q.segmentSize:]...)
+}
+
+func (q *taskQueue) writeSegment(tasks []crawlTask) (queueSegment, error) {
+	if q.dir == "" {
+		dir, err := os.MkdirTemp("", "barracuda-queue-")
+		if err != nil {
+			return queueSegment{}, fmt.Errorf("failed to create queue directory: %w", err)
+		}
+		q.dir = dir
+		utils.Debug("Spilling crawl queue to disk", utils.NewField("dir", dir))
+	}
+
+	path := filepath.Join(q.dir, fmt.Sprintf("segment-%06d.ndjson", q.nextSegment))
+	file, err := os.Create(path)
+	if err != nil {
+		return queueSegment{}, fmt.Errorf("failed to create queue segment: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	for _, task := range tasks {
+		if err := encoder.Encode(task); err != nil {
+			file.Close()
+			os.Remove(path)
+			return queueSegment{}, fmt.Errorf("failed to write queue segment: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		os.Remove(path)
+		return queueSegment{}, fmt.Errorf("failed to write queue segment: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(path)
+		return queueSegment{}, fmt.Errorf("failed to write queue segment: %w", err)
+	}
+
+	q.nextSegment++
+	return queueSegment{path: path, count: len(tasks)}, nil
+}
+
+// load reads a list's segment back, the oldest into the head or the newest
+// into the tail, and deletes its file. It returns the number of tasks lost
+// when the segment can't be read. The caller holds q.mu.
+func (q *taskQueue) load(list *taskList, newest bool) int {
+	index := 0
+	if newest {
+		index = len(list.segments) - 1
+	}
+	segment := list.segments[index]
+	list.segments = slices.Delete(list.segments, index, index+1)
+	defer os.Remove(segment.path)
+
+	tasks, err := readSegment(segment.path)
+	if err != nil {
+		utils.Warn("Failed to read spilled crawl queue; its URLs won't be crawled",
+			utils.NewField("path", segment.path),
+			utils.NewField("tasks", segment.count),
+			utils.NewField("error", err.Error()))
+		q.size -= segment.count
+		list.size -= segment.count
+		return segment.count
+	}
+	if newest {
+		list.tail = tasks
+	} else {
+		list.head = tasks
+	}
+	return 0
+}
+
+func readSegment(path string) ([]crawlTask, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var tasks []crawlTask
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for decoder.More() {
+		var task crawlTask
+		if err := decoder.Decode(&task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// removeDir deletes the spill directory. The caller holds q.mu.
+func (q *taskQueue) removeDir() {
+	if q.dir == "" {
+		return
+	}
+	if err := os.RemoveAll(q.dir); err != nil {
+		utils.Debug("Failed to remove crawl queue directory", utils.NewField("dir", q.dir), utils.NewField("error", err.Error()))
+	}
+	q.dir = ""
+}
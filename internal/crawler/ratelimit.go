@@ -0,0 +1,209 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+const (
+	// throttleMinInterval is the gap a throttled host starts at when no
+	// rate was configured
+	throttleMinInterval = 250 * time.Millisecond
+	// throttleMaxInterval caps how far repeated backoff slows a host
+	throttleMaxInterval = 30 * time.Second
+	// maxRetryAfter caps how long a Retry-After header can pause a host
+	maxRetryAfter = 5 * time.Minute
+	// maxCrawlDelay caps the gap a robots.txt Crawl-delay sets, so a
+	// huge value can't stall the crawl
+	maxCrawlDelay = time.Minute
+)
+
+// rateLimiter spaces requests to each host. Every host has a token bucket
+// holding one token that refills after the host's interval, so requests to
+// one host never come closer together than that, while other hosts are
+// unaffected. With auto-throttling, 429 and 503 responses double a host's
+// interval and a Retry-After header pauses it; successful responses bring the
+// interval back down to the configured rate.
+type rateLimiter struct {
+	ctx          context.Context
+	interval     time.Duration // Configured gap between requests to one host
+	autoThrottle bool
+
+	mu    sync.Mutex
+	hosts map[string]*hostBucket
+}
+
+// hostBucket is the rate state of one host
+type hostBucket struct {
+	next     time.Time     // When the next token is available
+	interval time.Duration // Current gap; above minimum while throttled
+	minimum  time.Duration // The configured gap, or the host's Crawl-delay if longer
+}
+
+// newRateLimiter limits each host to maxRPS requests a second (0: no limit)
+// and at least delay between requests, whichever is slower. Waits end when
+// ctx is cancelled.
+func newRateLimiter(ctx context.Context, maxRPS float64, delay time.Duration, autoThrottle bool) *rateLimiter {
+	interval := delay
+	if maxRPS > 0 {
+		if perRequest := time.Duration(float64(time.Second) / maxRPS); perRequest > interval {
+			interval = perRequest
+		}
+	}
+	return &rateLimiter{
+		ctx:          ctx,
+		interval:     interval,
+		autoThrottle: autoThrottle,
+		hosts:        make(map[string]*hostBucket),
+	}
+}
+
+// Wait blocks until a request to rawURL's host may start, and takes the
+// host's token. It returns false if the crawl was cancelled meanwhile.
+func (l *rateLimiter) Wait(rawURL string) bool {
+	host := hostKey(rawURL)
+	l.mu.Lock()
+	bucket := l.bucket(host)
+	now := time.Now()
+	start := bucket.next
+	if start.Before(now) {
+		start = now
+	}
+	bucket.next = start.Add(bucket.interval)
+	l.mu.Unlock()
+
+	wait := time.Until(start)
+	if wait <= 0 {
+		return l.ctx.Err() == nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-l.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// SetMinInterval keeps requests to rawURL's host at least interval apart,
+// for its robots.txt Crawl-delay. It never shortens the configured gap.
+func (l *rateLimiter) SetMinInterval(rawURL string, interval time.Duration) {
+	if interval > maxCrawlDelay {
+		interval = maxCrawlDelay
+	}
+	host := hostKey(rawURL)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket := l.bucket(host)
+	if interval <= bucket.minimum {
+		return
+	}
+	bucket.minimum = interval
+	if bucket.interval < interval {
+		bucket.interval = interval
+	}
+	utils.Info("Applying robots.txt crawl-delay", utils.NewField("host", host), utils.NewField("delay", interval.String()))
+}
+
+// Observe adjusts the host's rate after a response. A status of 0 (a
+// connection error) leaves it unchanged.
+func (l *rateLimiter) Observe(rawURL string, status int, header map[string]string) {
+	if !l.autoThrottle || status == 0 {
+		return
+	}
+	host := hostKey(rawURL)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket := l.bucket(host)
+
+	if status != http.StatusTooManyRequests && status != http.StatusServiceUnavailable {
+		// Recover gradually so one good response doesn't undo the backoff
+		if bucket.interval > bucket.minimum {
+			bucket.interval = bucket.interval * 3 / 4
+			if bucket.interval < bucket.minimum {
+				bucket.interval = bucket.minimum
+			}
+		}
+		return
+	}
+
+	previous := bucket.interval
+	bucket.interval *= 2
+	if bucket.interval < throttleMinInterval {
+		bucket.interval = throttleMinInterval
+	}
+	if bucket.interval > throttleMaxInterval {
+		bucket.interval = throttleMaxInterval
+	}
+	if retryAfter, ok := parseRetryAfter(header["Retry-After"], time.Now()); ok {
+		if retryAfter > maxRetryAfter {
+			retryAfter = maxRetryAfter
+		}
+		if until := time.Now().Add(retryAfter); until.After(bucket.next) {
+			bucket.next = until
+		}
+	}
+	if bucket.interval != previous {
+		utils.Warn("Host is rate limiting the crawl; slowing down",
+			utils.NewField("host", host),
+			utils.NewField("status", status),
+			utils.NewField("interval", bucket.interval.String()),
+			utils.NewField("retry_after", header["Retry-After"]))
+	}
+}
+
+// throttles reports whether the limiter backs off from responses with
+// status, so a request that got one is worth retrying. It is false for a nil
+// limiter.
+func (l *rateLimiter) throttles(status int) bool {
+	return l != nil && l.autoThrottle && (status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable)
+}
+
+// bucket returns the state of host. The caller holds mu.
+func (l *rateLimiter) bucket(host string) *hostBucket {
+	bucket, ok := l.hosts[host]
+	if !ok {
+		bucket = &hostBucket{interval: l.interval, minimum: l.interval}
+		l.hosts[host] = bucket
+	}
+	return bucket
+}
+
+// hostKey returns the host a URL's requests are limited under
+func hostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// parseRetryAfter reads a Retry-After header, which is either a number of
+// seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,288 @@
+package crawler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+
+	"github.com/dillonlara115/barracuda/internal/render"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// renderSettleTime is how long a rendered page may keep making requests
+// after its load event before its DOM is read anyway
+const renderSettleTime = 3 * time.Second
+
+// Renderer loads pages in a headless Chrome so the parser sees the DOM built
+// by JavaScript frameworks, and records the JavaScript errors seen on the way.
+// One browser is shared by all workers; each page gets its own tab.
+type Renderer struct {
+	browser       context.Context
+	cancelBrowser context.CancelFunc
+	cancelAlloc   context.CancelFunc
+	timeout       time.Duration
+	headers       siteHeaders    // Extra headers for the crawled site, added to its requests
+	cookies       http.CookieJar // Session cookies of a login, copied into the browser (nil without one)
+}
+
+// NewRenderer starts a headless browser found by render.FindBrowser. Its
+// requests for the hosts in hostRewrites go to their targets, as with
+// Fetcher.SetHostRewrites.
+func NewRenderer(timeout time.Duration, userAgent string, hostRewrites map[string]string) (*Renderer, error) {
+	path, err := render.FindBrowser()
+	if err != nil {
+		return nil, fmt.Errorf("render mode needs Chrome or Chromium (set %s to its path): %w", render.BrowserEnvVar, err)
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.ExecPath(path),
+		chromedp.UserAgent(userAgent),
+	)
+	if len(hostRewrites) > 0 {
+		rules := make([]string, 0, len(hostRewrites))
+		for from, to := range hostRewrites {
+			rules = append(rules, fmt.Sprintf("MAP %s %s", from, to))
+		}
+		sort.Strings(rules)
+		opts = append(opts, chromedp.Flag("host-resolver-rules", strings.Join(rules, ", ")))
+	}
+	// Chrome refuses to start as root without disabling its sandbox
+	if os.Geteuid() == 0 {
+		opts = append(opts, chromedp.NoSandbox)
+	}
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	browser, cancelBrowser := chromedp.NewContext(allocCtx)
+
+	// Start the browser now so a broken install fails before the crawl does
+	if err := chromedp.Run(browser); err != nil {
+		cancelBrowser()
+		cancelAlloc()
+		return nil, fmt.Errorf("failed to start headless browser: %w", err)
+	}
+
+	return &Renderer{
+		browser:       browser,
+		cancelBrowser: cancelBrowser,
+		cancelAlloc:   cancelAlloc,
+		timeout:       timeout,
+	}, nil
+}
+
+// Close shuts the browser down
+func (r *Renderer) Close() {
+	r.cancelBrowser()
+	r.cancelAlloc()
+}
+
+// Render loads url in a new tab, waits for its network to go idle, and
+// returns the rendered HTML with the JavaScript errors seen while loading
+func (r *Renderer) Render(url string) ([]byte, []models.JSError, error) {
+	tab, cancelTab := chromedp.NewContext(r.browser)
+	defer cancelTab()
+	ctx, cancel := context.WithTimeout(tab, r.timeout)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		jsErrors  []models.JSError
+		requests  = make(map[network.RequestID]string) // Resource URLs by request
+		lifecycle = make(chan *page.EventLifecycleEvent, 32)
+	)
+	addError := func(jsErr models.JSError) {
+		mu.Lock()
+		jsErrors = append(jsErrors, jsErr)
+		mu.Unlock()
+	}
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *runtime.EventExceptionThrown:
+			addError(exceptionError(ev.ExceptionDetails))
+		case *runtime.EventConsoleAPICalled:
+			if ev.Type == runtime.APITypeError {
+				addError(consoleError(ev))
+			}
+		case *network.EventRequestWillBeSent:
+			mu.Lock()
+			requests[ev.RequestID] = ev.Request.URL
+			mu.Unlock()
+		case *network.EventResponseReceived:
+			// The page itself was already checked over plain HTTP
+			if ev.Type != network.ResourceTypeDocument && ev.Response.Status >= 400 {
+				addError(models.JSError{
+					Kind:    models.JSErrorNetwork,
+					Message: fmt.Sprintf("HTTP %d", ev.Response.Status),
+					Source:  ev.Response.URL,
+				})
+			}
+		case *network.EventLoadingFailed:
+			if ev.Canceled || ev.Type == network.ResourceTypeDocument {
+				return
+			}
+			mu.Lock()
+			source := requests[ev.RequestID]
+			mu.Unlock()
+			addError(models.JSError{Kind: models.JSErrorNetwork, Message: ev.ErrorText, Source: source})
+		case *fetch.EventRequestPaused:
+			go r.continueRequest(ctx, ev)
+		case *page.EventLifecycleEvent:
+			select {
+			case lifecycle <- ev:
+			default:
+			}
+		}
+	})
+
+	var html string
+	err := chromedp.Run(ctx,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			return r.setCookies(ctx, url)
+		}),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			// Pause requests to add the site's extra headers; see continueRequest
+			if len(r.headers.header) == 0 {
+				return nil
+			}
+			return fetch.Enable().Do(ctx)
+		}),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			_, loaderID, errorText, err := page.Navigate(url).Do(ctx)
+			if err != nil {
+				return err
+			}
+			if errorText != "" {
+				return fmt.Errorf("navigation failed: %s", errorText)
+			}
+			return waitForNetworkIdle(ctx, lifecycle, loaderID)
+		}),
+		chromedp.Evaluate(`document.documentElement.outerHTML`, &html),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render %s: %w", url, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return []byte("<!DOCTYPE html>\n" + html), jsErrors, nil
+}
+
+// setCookies gives the browser the fetcher's cookies for url, such as the
+// session of a login, before the page is loaded
+func (r *Renderer) setCookies(ctx context.Context, url string) error {
+	if r.cookies == nil {
+		return nil
+	}
+	u, err := neturl.Parse(url)
+	if err != nil {
+		return err
+	}
+	for _, cookie := range r.cookies.Cookies(u) {
+		if err := network.SetCookie(cookie.Name, cookie.Value).WithURL(url).Do(ctx); err != nil {
+			return fmt.Errorf("failed to set cookie %s: %w", cookie.Name, err)
+		}
+	}
+	return nil
+}
+
+// continueRequest resumes a request paused by the fetch domain, adding the
+// extra headers when it goes to the crawled site. Other hosts, such as the
+// page's third-party scripts, get the request unchanged.
+func (r *Renderer) continueRequest(ctx context.Context, ev *fetch.EventRequestPaused) {
+	continued := fetch.ContinueRequest(ev.RequestID)
+	if extra := r.headers.forURL(ev.Request.URL); extra != nil {
+		headers := make([]*fetch.HeaderEntry, 0, len(ev.Request.Headers)+len(extra))
+		for name, value := range ev.Request.Headers {
+			if _, replaced := extra[http.CanonicalHeaderKey(name)]; !replaced {
+				headers = append(headers, &fetch.HeaderEntry{Name: name, Value: fmt.Sprint(value)})
+			}
+		}
+		for name, values := range extra {
+			for _, value := range values {
+				headers = append(headers, &fetch.HeaderEntry{Name: name, Value: value})
+			}
+		}
+		continued = continued.WithHeaders(headers)
+	}
+	if err := continued.Do(cdp.WithExecutor(ctx, chromedp.FromContext(ctx).Target)); err != nil {
+		utils.Debug("Failed to continue paused request", utils.NewField("url", ev.Request.URL), utils.NewField("error", err.Error()))
+	}
+}
+
+// waitForNetworkIdle returns once the navigation identified by loaderID
+// reports network idle, or renderSettleTime after its load event for pages
+// that keep polling
+func waitForNetworkIdle(ctx context.Context, events <-chan *page.EventLifecycleEvent, loaderID cdp.LoaderID) error {
+	var settled <-chan time.Time
+	for {
+		select {
+		case ev := <-events:
+			if ev.LoaderID != loaderID {
+				continue
+			}
+			switch ev.Name {
+			case "networkIdle":
+				return nil
+			case "load":
+				if settled == nil {
+					settled = time.After(renderSettleTime)
+				}
+			}
+		case <-settled:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// exceptionError describes an uncaught exception
+func exceptionError(details *runtime.ExceptionDetails) models.JSError {
+	message := details.Text
+	if details.Exception != nil && details.Exception.Description != "" {
+		// The description is the message followed by the stack trace
+		message, _, _ = strings.Cut(details.Exception.Description, "\n")
+	}
+	source := details.URL
+	if source == "" && details.StackTrace != nil && len(details.StackTrace.CallFrames) > 0 {
+		source = details.StackTrace.CallFrames[0].URL
+	}
+	if source != "" {
+		source = fmt.Sprintf("%s:%d", source, details.LineNumber+1)
+	}
+	return models.JSError{Kind: models.JSErrorException, Message: message, Source: source}
+}
+
+// consoleError describes a console.error() call
+func consoleError(ev *runtime.EventConsoleAPICalled) models.JSError {
+	args := make([]string, 0, len(ev.Args))
+	for _, arg := range ev.Args {
+		var s string
+		if json.Unmarshal(arg.Value, &s) == nil {
+			args = append(args, s)
+		} else if arg.Description != "" {
+			args = append(args, arg.Description)
+		} else if len(arg.Value) > 0 {
+			args = append(args, string(arg.Value))
+		}
+	}
+	var source string
+	if ev.StackTrace != nil && len(ev.StackTrace.CallFrames) > 0 {
+		frame := ev.StackTrace.CallFrames[0]
+		source = fmt.Sprintf("%s:%d", frame.URL, frame.LineNumber+1)
+	}
+	return models.JSError{Kind: models.JSErrorConsole, Message: strings.Join(args, " "), Source: source}
+}
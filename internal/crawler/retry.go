@@ -0,0 +1,65 @@
+package crawler
+
+import (
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// deferRetry holds back a page that failed with a transient error, such as a
+// timeout or a 5xx, for one more attempt once the rest of the crawl is done,
+// so a momentary blip isn't reported as a broken page. It reports false when
+// the failure should be recorded now: the error isn't transient, or this was
+// the page's final attempt.
+func (m *Manager) deferRetry(task crawlTask, result *FetchResult) bool {
+	if !isRetryableError(result) {
+		return false
+	}
+
+	m.retryMu.Lock()
+	defer m.retryMu.Unlock()
+	if _, retried := m.retried[task.URL]; retried {
+		return false
+	}
+	m.retries = append(m.retries, task)
+	utils.Info("Deferring transient failure to the final retry pass",
+		utils.NewField("url", task.URL),
+		utils.NewField("status", result.PageResult.StatusCode),
+		utils.NewField("error", result.Error.Error()))
+	return true
+}
+
+// requeueRetries queues the held-back pages for their final attempt once
+// every other task is done. It reports false when there are none, or when
+// the crawl was cancelled, in which case they stay pending for a resumed
+// crawl.
+func (m *Manager) requeueRetries() bool {
+	m.retryMu.Lock()
+	defer m.retryMu.Unlock()
+	if len(m.retries) == 0 || m.ctx.Err() != nil {
+		return false
+	}
+
+	utils.Info("Retrying transient failures", utils.NewField("count", len(m.retries)))
+	if m.retried == nil {
+		m.retried = make(map[string]bool)
+	}
+	for _, task := range m.retries {
+		m.retried[task.URL] = true
+		m.tasks.Add(1)
+		m.queue.push(task)
+	}
+	m.retries = nil
+	return true
+}
+
+// takeRetry reports whether a task for url is a page's final attempt, which
+// is crawled even though its URL is already visited. It is true only once
+// per URL.
+func (m *Manager) takeRetry(url string) bool {
+	m.retryMu.Lock()
+	defer m.retryMu.Unlock()
+	if m.retried[url] {
+		m.retried[url] = false
+		return true
+	}
+	return false
+}
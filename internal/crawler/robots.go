@@ -4,26 +4,35 @@ import (
 	"fmt"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
 	"github.com/temoto/robotstxt"
 )
 
 // RobotsChecker handles robots.txt checking and caching
 type RobotsChecker struct {
 	fetcher       *Fetcher
-	cache         map[string]*robotstxt.Group
+	cache         map[string]*robotsRules
 	cacheMu       sync.RWMutex
 	userAgent     string
 	respectRobots bool
 }
 
+// robotsRules is what one host's robots.txt tells the crawler
+type robotsRules struct {
+	group    *robotstxt.Group  // nil means allow all
+	sitemaps []string          // Sitemap: URLs, absolute
+	file     *models.RobotsTxt // The response the rules came from
+}
+
 // NewRobotsChecker creates a new RobotsChecker instance
 func NewRobotsChecker(fetcher *Fetcher, userAgent string, respectRobots bool) *RobotsChecker {
 	return &RobotsChecker{
 		fetcher:       fetcher,
-		cache:         make(map[string]*robotstxt.Group),
-		userAgent:     userAgent,
+		cache:         make(map[string]*robotsRules),
+		userAgent:     utils.RobotsAgent(userAgent),
 		respectRobots: respectRobots,
 	}
 }
@@ -39,67 +48,115 @@ func (r *RobotsChecker) IsAllowed(targetURL string) (bool, error) {
 		return false, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+	group := r.rules(u).group
+	if group == nil {
+		return true, nil
+	}
+	// Rules match the path and query, not the full URL
+	return group.Test(u.RequestURI()), nil
+}
+
+// CrawlDelay returns the Crawl-delay robots.txt asks of the crawler on a
+// URL's host, or 0 when it sets none or robots.txt isn't respected
+func (r *RobotsChecker) CrawlDelay(targetURL string) time.Duration {
+	if !r.respectRobots {
+		return 0
+	}
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return 0
+	}
+	if group := r.rules(u).group; group != nil {
+		return group.CrawlDelay
+	}
+	return 0
+}
+
+// Sitemaps returns the Sitemap: URLs of a URL's host's robots.txt. They only
+// point at more URLs to crawl, so they are read even when robots.txt isn't
+// respected.
+func (r *RobotsChecker) Sitemaps(targetURL string) []string {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return nil
+	}
+	return r.rules(u).sitemaps
+}
+
+// File returns the robots.txt of a URL's host as it was fetched for the
+// crawl, or nil when the URL is invalid
+func (r *RobotsChecker) File(targetURL string) *models.RobotsTxt {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return nil
+	}
+	return r.rules(u).file
+}
+
+// rules returns the robots.txt rules of a URL's host, fetching them on first
+// use. A robots.txt that can't be fetched or parsed allows everything.
+func (r *RobotsChecker) rules(u *url.URL) *robotsRules {
 	domain := u.Host
 
 	// Check cache
 	r.cacheMu.RLock()
 	cached, exists := r.cache[domain]
 	r.cacheMu.RUnlock()
-
-	if exists && cached != nil {
-		return cached.Test(targetURL), nil
+	if exists {
+		return cached
 	}
 
-	// Fetch robots.txt
-	robotsData, err := r.fetchRobotsTxt(robotsURL)
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+	file, err := r.fetchRobotsTxt(robotsURL)
+	rules := &robotsRules{file: file}
 	if err != nil {
-		// If robots.txt can't be fetched, allow by default
 		utils.Debug("Could not fetch robots.txt", utils.NewField("url", robotsURL), utils.NewField("error", err.Error()))
-		
-		// Cache a permissive group to avoid repeated fetches
-		r.cacheMu.Lock()
-		r.cache[domain] = nil // nil means allow all
-		r.cacheMu.Unlock()
-		
-		return true, nil
-	}
-
-	// Parse robots.txt
-	robotsGroup, err := robotstxt.FromBytes(robotsData)
-	if err != nil {
+	} else if robotsGroup, err := robotstxt.FromString(file.Content); err != nil {
 		utils.Debug("Could not parse robots.txt", utils.NewField("url", robotsURL), utils.NewField("error", err.Error()))
-		
-		// Cache a permissive group
-		r.cacheMu.Lock()
-		r.cache[domain] = nil
-		r.cacheMu.Unlock()
-		
-		return true, nil
+	} else {
+		rules.group = robotsGroup.FindGroup(r.userAgent)
+		rules.sitemaps = resolveSitemaps(robotsURL, robotsGroup.Sitemaps)
 	}
 
-	// Get group for user agent
-	group := robotsGroup.FindGroup(r.userAgent)
-	
-	// Cache the group
+	// Cache failures too, to avoid repeated fetches
 	r.cacheMu.Lock()
-	r.cache[domain] = group
+	r.cache[domain] = rules
 	r.cacheMu.Unlock()
+	return rules
+}
 
-	return group.Test(targetURL), nil
+// resolveSitemaps makes robots.txt's Sitemap: URLs absolute, dropping
+// invalid and repeated ones
+func resolveSitemaps(robotsURL string, sitemaps []string) []string {
+	base, err := url.Parse(robotsURL)
+	if err != nil {
+		return nil
+	}
+	var resolved []string
+	seen := make(map[string]bool)
+	for _, sitemap := range sitemaps {
+		u, err := base.Parse(sitemap)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || seen[u.String()] {
+			continue
+		}
+		seen[u.String()] = true
+		resolved = append(resolved, u.String())
+	}
+	return resolved
 }
 
-// fetchRobotsTxt fetches robots.txt content
-func (r *RobotsChecker) fetchRobotsTxt(robotsURL string) ([]byte, error) {
+// fetchRobotsTxt fetches robots.txt. The file is returned even with an
+// error, recording the status the site answered with.
+func (r *RobotsChecker) fetchRobotsTxt(robotsURL string) (*models.RobotsTxt, error) {
 	result := r.fetcher.Fetch(robotsURL)
+	file := models.NewRobotsTxt(robotsURL, result.PageResult.StatusCode, result.Body)
 	if result.Error != nil {
-		return nil, result.Error
+		return file, result.Error
 	}
-	
+
 	if result.PageResult.StatusCode != 200 {
-		return nil, fmt.Errorf("HTTP %d", result.PageResult.StatusCode)
+		return file, fmt.Errorf("HTTP %d", result.PageResult.StatusCode)
 	}
-	
-	return result.Body, nil
-}
 
+	return file, nil
+}
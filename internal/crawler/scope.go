@@ -0,0 +1,114 @@
+package crawler
+
+import (
+	"fmt"
+	neturl "net/url"
+	"slices"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// setStartURLs normalizes the configured start URLs, dropping duplicates.
+// The first one is the crawl's main site, whose robots.txt and site files
+// are reported.
+func (m *Manager) setStartURLs() error {
+	startURLs := m.config.StartURLs()
+	if len(startURLs) == 0 {
+		return fmt.Errorf("invalid start URL: %w", utils.ErrEmptyStartURL)
+	}
+	m.startURLs = make([]string, 0, len(startURLs))
+	for _, startURL := range startURLs {
+		normalized, err := utils.NormalizeURL(startURL)
+		if err != nil {
+			return fmt.Errorf("invalid start URL %q: %w", startURL, err)
+		}
+		if !slices.Contains(m.startURLs, normalized) {
+			m.startURLs = append(m.startURLs, normalized)
+		}
+	}
+	m.normalizedStartURL = m.startURLs[0]
+	return nil
+}
+
+// inScope reports whether url is on the domain of any start URL, which is
+// what the "same" domain filter allows
+func (m *Manager) inScope(url string) bool {
+	for _, startURL := range m.startURLs {
+		if utils.IsSameDomain(url, startURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// inScopePath reports whether url's path is under the --scope-path prefix,
+// or true when no prefix is set. The prefix's own directory matches without
+// its trailing slash, since normalized URLs drop it.
+func (m *Manager) inScopePath(url string) bool {
+	prefix := m.config.ScopePath
+	if prefix == "" {
+		return true
+	}
+	u, err := neturl.Parse(url)
+	if err != nil {
+		return false
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+	return strings.HasPrefix(path, prefix) || path+"/" == prefix
+}
+
+// isStartURL reports whether url is one of the normalized start URLs, which
+// are crawled whatever the include/exclude rules say
+func (m *Manager) isStartURL(url string) bool {
+	return slices.Contains(m.startURLs, url)
+}
+
+// nofollowLinks returns the URLs a page only links to with nofollow: all of
+// its links when the page itself is nofollow, otherwise those whose every
+// anchor on the page has rel=nofollow, sponsored, or ugc
+func nofollowLinks(page *models.PageResult) map[string]bool {
+	nofollow := make(map[string]bool)
+	if page.Nofollow() {
+		for _, link := range page.InternalLinks {
+			nofollow[link] = true
+		}
+		for _, link := range page.ExternalLinks {
+			nofollow[link] = true
+		}
+		return nofollow
+	}
+
+	followed := make(map[string]bool)
+	for _, link := range page.Links {
+		if !link.Followed() {
+			nofollow[link.URL] = true
+		} else {
+			followed[link.URL] = true
+		}
+	}
+	for url := range followed {
+		delete(nofollow, url)
+	}
+	return nofollow
+}
+
+// followLinks returns the links of a page the crawl follows: its internal
+// links and, when the start URLs span several domains, its links to those
+// other domains
+func (m *Manager) followLinks(page *models.PageResult) []string {
+	if len(m.startURLs) < 2 {
+		return page.InternalLinks
+	}
+	links := append([]string(nil), page.InternalLinks...)
+	for _, link := range page.ExternalLinks {
+		if m.inScope(link) {
+			links = append(links, link)
+		}
+	}
+	return links
+}
@@ -0,0 +1,105 @@
+package crawler
+
+import (
+	"fmt"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// ResultSink receives crawled pages in place of the results Crawl returns.
+// It is only written to from one goroutine at a time. exporter.Stream
+// implements it.
+type ResultSink interface {
+	// Write stores a crawled page
+	Write(page *models.PageResult) error
+	// Path returns where pages are stored, for a resumed crawl to find them
+	Path() string
+	// Size returns how many bytes have been written, for checkpoints
+	Size() int64
+	// Resume drops what was written after the first size bytes and passes
+	// each page stored before them to fn
+	Resume(size int64, fn func(page *models.PageResult)) error
+}
+
+// StreamState records the pages a checkpointed crawl wrote to its sink
+type StreamState struct {
+	Path  string `json:"path"`
+	Size  int64  `json:"size"` // Bytes written by the checkpoint
+	Pages int    `json:"pages"`
+}
+
+// SetResultSink writes each crawled page to sink instead of keeping it, so
+// memory stays flat however many pages are crawled. Crawl then returns no
+// results, while the analyzer and hooks still see every page. It must be
+// called before Crawl.
+func (m *Manager) SetResultSink(sink ResultSink) {
+	m.sink = sink
+	m.streamed = make(map[string]bool)
+}
+
+// PageCount returns the number of pages crawled, including those written to
+// a result sink
+func (m *Manager) PageCount() int {
+	m.resultsMu.Lock()
+	defer m.resultsMu.Unlock()
+	return m.pageCount
+}
+
+// storeResult keeps a crawled page, or writes it to the sink. The caller
+// holds resultsMu.
+func (m *Manager) storeResult(page *models.PageResult) error {
+	if m.sink == nil {
+		m.results = append(m.results, page)
+		m.pageCount++
+		return nil
+	}
+	if err := m.sink.Write(page); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	m.streamed[page.URL] = true
+	m.pageCount++
+	return nil
+}
+
+// sinkFailed stops the crawl after a page couldn't be written to the sink,
+// saving its state so it can be resumed once the problem is fixed
+func (m *Manager) sinkFailed(err error) {
+	m.resultsMu.Lock()
+	if m.sinkErr == nil {
+		m.sinkErr = err
+		utils.Warn("Stopping crawl", utils.NewField("error", err.Error()))
+	}
+	m.resultsMu.Unlock()
+	m.Stop()
+}
+
+// restoreStreamed passes the pages a resumed crawl wrote to its sink before
+// the checkpoint to the analyzer and hooks, and marks them visited
+func (m *Manager) restoreStreamed(state *StreamState) error {
+	if state == nil {
+		return nil
+	}
+	err := m.sink.Resume(state.Size, func(page *models.PageResult) {
+		m.visited.Add(page.URL)
+		m.resultsMu.Lock()
+		m.streamed[page.URL] = true
+		m.pageCount++
+		count := m.pageCount
+		m.resultsMu.Unlock()
+		m.pageCrawled(page, count)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resume results file: %w", err)
+	}
+	return nil
+}
+
+// captureStream records the sink's progress for a checkpoint, or returns nil
+// without a sink. The caller holds resultsMu.
+func (m *Manager) captureStream() *StreamState {
+	if m.sink == nil {
+		return nil
+	}
+	return &StreamState{Path: m.sink.Path(), Size: m.sink.Size(), Pages: m.pageCount}
+}
@@ -1,17 +1,37 @@
 package crawler
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
 
 	"github.com/dillonlara115/barracuda/internal/utils"
 )
 
+// Limits the sitemap protocol (sitemaps.org) sets for one sitemap file.
+// Larger files are read up to the limit and reported.
+const (
+	MaxSitemapURLs  = 50000
+	MaxSitemapBytes = 50 * 1024 * 1024 // Uncompressed
+)
+
+// maxSitemapDepth limits nested sitemap indexes. The protocol doesn't allow
+// an index to list other indexes, but some sites do it anyway.
+const maxSitemapDepth = 3
+
 // SitemapIndex represents a sitemap index file
 type SitemapIndex struct {
-	XMLName xml.Name `xml:"sitemapindex"`
+	XMLName  xml.Name  `xml:"sitemapindex"`
 	Sitemaps []Sitemap `xml:"sitemap"`
 }
 
@@ -28,12 +48,92 @@ type URLSet struct {
 
 // URL represents a single URL in a sitemap
 type URL struct {
-	Loc string `xml:"loc"`
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// SitemapEntry is a normalized URL listed in a sitemap
+type SitemapEntry struct {
+	URL      string
+	LastMod  *time.Time // nil when <lastmod> is missing or invalid
+	Priority *float64   // <priority> from 0 to 1; nil when missing or invalid
+}
+
+// sitemapItem is one listed element of any supported format: <url> and
+// <sitemap> in sitemaps, <item> in RSS, and <entry> in Atom
+type sitemapItem struct {
+	Loc      string     `xml:"loc"`
+	LastMod  string     `xml:"lastmod"`
+	Priority string     `xml:"priority"`
+	Links    []feedLink `xml:"link"`
+	PubDate  string     `xml:"pubDate"` // RSS
+	Updated  string     `xml:"updated"` // Atom
+}
+
+// feedLink is an RSS <link>, whose text is the URL, or an Atom <link>, whose
+// href is
+type feedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Text string `xml:",chardata"`
+}
+
+// location returns the URL the item points to
+func (item sitemapItem) location() string {
+	if item.Loc != "" {
+		return item.Loc
+	}
+	for _, link := range item.Links {
+		if link.Href != "" && (link.Rel == "" || link.Rel == "alternate") {
+			return link.Href
+		}
+		if text := strings.TrimSpace(link.Text); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// lastMod returns when the item last changed, or nil
+func (item sitemapItem) lastMod() *time.Time {
+	if t := parseDate(item.LastMod); t != nil {
+		return t
+	}
+	if t := parseDate(item.Updated); t != nil {
+		return t
+	}
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123} {
+		if t, err := time.Parse(layout, strings.TrimSpace(item.PubDate)); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// priority returns the item's <priority>, or nil when it is missing or
+// outside 0 to 1
+func (item sitemapItem) priority() *float64 {
+	p, err := strconv.ParseFloat(strings.TrimSpace(item.Priority), 64)
+	if err != nil || p < 0 || p > 1 {
+		return nil
+	}
+	return &p
+}
+
+// sitemapItemElements names the listed element of each supported root
+// element. RDF is RSS 1.0.
+var sitemapItemElements = map[string]string{
+	"urlset":       "url",
+	"sitemapindex": "sitemap",
+	"rss":          "item",
+	"RDF":          "item",
+	"feed":         "entry",
 }
 
 // SitemapParser parses sitemap.xml files
 type SitemapParser struct {
 	fetcher *Fetcher
+	since   time.Time // When set, only URLs modified since then are returned
 }
 
 // NewSitemapParser creates a new SitemapParser instance
@@ -43,53 +143,289 @@ func NewSitemapParser(fetcher *Fetcher) *SitemapParser {
 	}
 }
 
+// SetModifiedSince limits the parsed URLs to those whose <lastmod> is at or
+// after since. URLs without a <lastmod> are left out, and the sitemaps an
+// index dates before since aren't fetched.
+func (s *SitemapParser) SetModifiedSince(since time.Time) {
+	s.since = since
+}
+
 // ParseSitemap fetches and parses a sitemap URL, returning all URLs found
 func (s *SitemapParser) ParseSitemap(sitemapURL string) ([]string, error) {
+	entries, err := s.ParseSitemapEntries(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(entries))
+	for i, entry := range entries {
+		urls[i] = entry.URL
+	}
+	return urls, nil
+}
+
+// ParseSitemapEntries fetches and parses a sitemap URL, returning all URLs
+// found with their last modification dates. XML sitemaps and sitemap
+// indexes, text sitemaps of one URL per line, and RSS and Atom feeds are
+// accepted, gzipped or not. Sitemaps that break the protocol's rules are
+// read as far as possible and logged as warnings.
+func (s *SitemapParser) ParseSitemapEntries(sitemapURL string) ([]SitemapEntry, error) {
+	entries, err := s.parse(sitemapURL, 0, make(map[string]bool))
+	if err != nil || s.since.IsZero() {
+		return entries, err
+	}
+
+	modified := entries[:0]
+	for _, entry := range entries {
+		if entry.LastMod != nil && !entry.LastMod.Before(s.since) {
+			modified = append(modified, entry)
+		}
+	}
+	if skipped := len(entries) - len(modified); skipped > 0 {
+		utils.Info("Skipping sitemap URLs not modified since --sitemap-since",
+			utils.NewField("url", sitemapURL),
+			utils.NewField("since", s.since.Format(time.RFC3339)),
+			utils.NewField("skipped", skipped),
+			utils.NewField("kept", len(modified)))
+	}
+	return modified, nil
+}
+
+// parse reads one sitemap file, following index entries up to
+// maxSitemapDepth. seen holds the sitemaps already read, so an index that
+// lists itself doesn't loop.
+func (s *SitemapParser) parse(sitemapURL string, depth int, seen map[string]bool) ([]SitemapEntry, error) {
+	seen[sitemapURL] = true
+
 	result := s.fetcher.Fetch(sitemapURL)
-	if result.Error != nil {
+	if result.Error != nil && result.PageResult.StatusCode == 0 {
 		return nil, fmt.Errorf("failed to fetch sitemap: %w", result.Error)
 	}
-
 	if result.PageResult.StatusCode != 200 {
 		return nil, fmt.Errorf("sitemap returned HTTP %d", result.PageResult.StatusCode)
 	}
 
-	// Try parsing as sitemap index first
-	var index SitemapIndex
-	err := xml.Unmarshal(result.Body, &index)
-	if err == nil && len(index.Sitemaps) > 0 {
-		// It's a sitemap index, recursively parse each sitemap
-		urls := make([]string, 0)
-		for _, sitemap := range index.Sitemaps {
-			subURLs, err := s.ParseSitemap(strings.TrimSpace(sitemap.Loc))
-			if err != nil {
-				utils.Debug("Failed to parse sub-sitemap", utils.NewField("url", sitemap.Loc), utils.NewField("error", err.Error()))
-				continue
-			}
-			urls = append(urls, subURLs...)
+	body, err := sitemapBody(sitemapURL, result.Body)
+	if err != nil {
+		return nil, err
+	}
+	content := bufio.NewReader(body)
+	if !looksLikeXML(content) {
+		if strings.Contains(result.ContentType, "html") {
+			return nil, fmt.Errorf("%s is an HTML page, not a sitemap", sitemapURL)
 		}
-		return urls, nil
+		return parseTextSitemap(sitemapURL, content, body)
 	}
 
-	// Try parsing as URL set
-	var urlSet URLSet
-	err = xml.Unmarshal(result.Body, &urlSet)
+	decoder := xml.NewDecoder(content)
+	decoder.CharsetReader = charset.NewReaderLabel
+	root, err := rootElement(decoder)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
 	}
+	itemElement, ok := sitemapItemElements[root]
+	if !ok {
+		if strings.EqualFold(root, "html") {
+			return nil, fmt.Errorf("%s is an HTML page, not a sitemap", sitemapURL)
+		}
+		return nil, fmt.Errorf("%s is not a sitemap or feed (root element <%s>)", sitemapURL, root)
+	}
 
-	// Extract URLs and normalize them
-	urls := make([]string, 0, len(urlSet.URLs))
-	for _, u := range urlSet.URLs {
-		normalized, err := utils.NormalizeURL(strings.TrimSpace(u.Loc))
+	var items []sitemapItem
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			utils.Debug("Invalid URL in sitemap", utils.NewField("url", u.Loc), utils.NewField("error", err.Error()))
+			if body.exceeded {
+				break // Reported below
+			}
+			if len(items) == 0 {
+				return nil, fmt.Errorf("failed to parse sitemap XML: %w", err)
+			}
+			utils.Warn("Malformed sitemap; using the URLs before the error",
+				utils.NewField("url", sitemapURL), utils.NewField("urls", len(items)), utils.NewField("error", err.Error()))
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != itemElement {
 			continue
 		}
-		urls = append(urls, normalized)
+		if len(items) == MaxSitemapURLs {
+			utils.Warn("Sitemap lists more than the protocol's limit; ignoring the rest",
+				utils.NewField("url", sitemapURL), utils.NewField("limit", MaxSitemapURLs))
+			break
+		}
+		var item sitemapItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			continue // The next Token call reports the syntax error
+		}
+		items = append(items, item)
+	}
+	if body.exceeded {
+		utils.Warn("Sitemap is larger than the protocol's limit; only the start was read",
+			utils.NewField("url", sitemapURL), utils.NewField("limit_bytes", MaxSitemapBytes), utils.NewField("urls", len(items)))
 	}
 
-	return urls, nil
+	if root != "sitemapindex" {
+		return sitemapEntries(sitemapURL, items), nil
+	}
+
+	// A sitemap index: parse each listed sitemap
+	if depth >= maxSitemapDepth {
+		utils.Warn("Sitemap indexes nested too deeply; skipping", utils.NewField("url", sitemapURL))
+		return nil, nil
+	}
+	entries := make([]SitemapEntry, 0)
+	for _, item := range items {
+		loc := strings.TrimSpace(item.location())
+		if loc == "" || seen[loc] {
+			continue
+		}
+		// A sitemap that hasn't changed since then lists no URL that has
+		if lastMod := item.lastMod(); !s.since.IsZero() && lastMod != nil && lastMod.Before(s.since) {
+			utils.Debug("Skipping sitemap not modified since --sitemap-since", utils.NewField("url", loc))
+			continue
+		}
+		subEntries, err := s.parse(loc, depth+1, seen)
+		if err != nil {
+			utils.Warn("Failed to parse sub-sitemap", utils.NewField("url", loc), utils.NewField("error", err.Error()))
+			continue
+		}
+		entries = append(entries, subEntries...)
+	}
+	return entries, nil
+}
+
+// sitemapEntries normalizes the URLs of a sitemap's items
+func sitemapEntries(sitemapURL string, items []sitemapItem) []SitemapEntry {
+	entries := make([]SitemapEntry, 0, len(items))
+	invalid := 0
+	for _, item := range items {
+		normalized, err := utils.NormalizeURL(strings.TrimSpace(item.location()))
+		if err != nil {
+			utils.Debug("Invalid URL in sitemap", utils.NewField("url", item.location()), utils.NewField("error", err.Error()))
+			invalid++
+			continue
+		}
+		entries = append(entries, SitemapEntry{URL: normalized, LastMod: item.lastMod(), Priority: item.priority()})
+	}
+	if invalid > 0 {
+		utils.Warn("Sitemap has invalid URLs; skipping them", utils.NewField("url", sitemapURL), utils.NewField("count", invalid))
+	}
+	return entries
+}
+
+// parseTextSitemap reads a text sitemap: one absolute URL per line
+func parseTextSitemap(sitemapURL string, content *bufio.Reader, body *limitedBody) ([]SitemapEntry, error) {
+	var items []sitemapItem
+	scanner := bufio.NewScanner(content)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if u, err := url.Parse(line); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			if len(line) > 80 {
+				line = line[:80] + "…"
+			}
+			return nil, fmt.Errorf("%s is not a sitemap: line %q is not a URL", sitemapURL, line)
+		}
+		if len(items) == MaxSitemapURLs {
+			utils.Warn("Sitemap lists more than the protocol's limit; ignoring the rest",
+				utils.NewField("url", sitemapURL), utils.NewField("limit", MaxSitemapURLs))
+			break
+		}
+		items = append(items, sitemapItem{Loc: line})
+	}
+	if body.exceeded {
+		utils.Warn("Sitemap is larger than the protocol's limit; only the start was read",
+			utils.NewField("url", sitemapURL), utils.NewField("limit_bytes", MaxSitemapBytes), utils.NewField("urls", len(items)))
+	}
+	return sitemapEntries(sitemapURL, items), nil
+}
+
+// limitedBody reads at most MaxSitemapBytes and records whether there was more
+type limitedBody struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.n <= 0 {
+		// Peek one byte to tell a file of exactly the limit from a larger one
+		var one [1]byte
+		if n, _ := b.r.Read(one[:]); n > 0 {
+			b.exceeded = true
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.n {
+		p = p[:b.n]
+	}
+	n, err := b.r.Read(p)
+	b.n -= int64(n)
+	return n, err
+}
+
+// gzipMagic starts every gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// sitemapBody returns a reader of the sitemap's uncompressed content, limited
+// to MaxSitemapBytes. Gzipped sitemaps (.xml.gz) are recognized by their
+// content rather than their name, since servers label them inconsistently.
+func sitemapBody(sitemapURL string, data []byte) (*limitedBody, error) {
+	var r io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(data, gzipMagic) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress sitemap %s: %w", sitemapURL, err)
+		}
+		r = gz
+	}
+	return &limitedBody{r: r, n: MaxSitemapBytes}, nil
+}
+
+// looksLikeXML reports whether content starts with markup, skipping a byte
+// order mark and whitespace
+func looksLikeXML(content *bufio.Reader) bool {
+	for {
+		r, _, err := content.ReadRune()
+		if err != nil {
+			return false
+		}
+		if r == '\uFEFF' || r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			continue
+		}
+		_ = content.UnreadRune()
+		return r == '<'
+	}
+}
+
+// rootElement returns the local name of the document's root element
+func rootElement(decoder *xml.Decoder) (string, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if err == io.EOF {
+				return "", errors.New("document has no root element")
+			}
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// DiscoverSitemapURLs returns the sitemaps of a base URL's host: those its
+// robots.txt lists, or /sitemap.xml when it lists none
+func (s *SitemapParser) DiscoverSitemapURLs(baseURL string, robots *RobotsChecker) []string {
+	if sitemaps := robots.Sitemaps(baseURL); len(sitemaps) > 0 {
+		return sitemaps
+	}
+	return []string{s.DiscoverSitemapURL(baseURL)}
 }
 
 // DiscoverSitemapURL attempts to discover sitemap.xml URL from a base URL
@@ -100,4 +436,3 @@ func (s *SitemapParser) DiscoverSitemapURL(baseURL string) string {
 	}
 	return fmt.Sprintf("%s://%s/sitemap.xml", u.Scheme, u.Host)
 }
-
@@ -0,0 +1,71 @@
+package crawler
+
+import (
+	"sync"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// skipKey identifies a skipped URL under one reason
+type skipKey struct {
+	url    string
+	reason SkipReason
+}
+
+// skipTracker records the URLs a crawl skipped, and counts the links to URLs
+// that were already crawled or queued
+type skipTracker struct {
+	mu         sync.Mutex
+	urls       map[skipKey]bool
+	duplicates int
+}
+
+// add records a skipped URL and reports whether it is new for this reason
+func (t *skipTracker) add(url string, reason SkipReason) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.urls == nil {
+		t.urls = make(map[skipKey]bool)
+	}
+	key := skipKey{url: url, reason: reason}
+	if t.urls[key] {
+		return false
+	}
+	t.urls[key] = true
+	return true
+}
+
+// duplicate counts a link to a URL that was already crawled or queued
+func (t *skipTracker) duplicate() {
+	t.mu.Lock()
+	t.duplicates++
+	t.mu.Unlock()
+}
+
+// counts tallies the skipped URLs by reason, leaving out those crawled in
+// the end, such as a link beyond the max depth that a shallower page also had
+func (t *skipTracker) counts(crawled map[string]bool) models.SkipCounts {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts := models.SkipCounts{DuplicateLinks: t.duplicates}
+	for key := range t.urls {
+		if crawled[key.url] {
+			continue
+		}
+		switch key.reason {
+		case SkipRobots:
+			counts.Robots++
+		case SkipDepth:
+			counts.Depth++
+		case SkipDomain:
+			counts.Domain++
+		case SkipFilter:
+			counts.Filter++
+		case SkipScope:
+			counts.Scope++
+		case SkipNofollow:
+			counts.Nofollow++
+		}
+	}
+	return counts
+}
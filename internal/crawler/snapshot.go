@@ -0,0 +1,104 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SnapshotIndexFile is the manifest written alongside saved HTML snapshots
+const SnapshotIndexFile = "index.json"
+
+// Snapshot describes one saved page body
+type Snapshot struct {
+	URL         string    `json:"url"`
+	File        string    `json:"file"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"content_type,omitempty"`
+	Size        int       `json:"size"`
+	SHA256      string    `json:"sha256"` // Hash of the body, for spotting changed content
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// SnapshotStore writes fetched page bodies to a directory. Files are named by
+// a hash of the URL, so the same page maps to the same file across crawls.
+type SnapshotStore struct {
+	dir     string
+	mu      sync.Mutex
+	entries []Snapshot
+}
+
+// NewSnapshotStore creates dir if needed and returns a store writing into it
+func NewSnapshotStore(dir string) (*SnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create HTML snapshot directory: %w", err)
+	}
+	return &SnapshotStore{dir: dir}, nil
+}
+
+// SnapshotFileName returns the file name used for a URL's snapshot
+func SnapshotFileName(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])[:32] + ".html"
+}
+
+// Save writes a page body and records it for the index. It is safe for concurrent use.
+func (s *SnapshotStore) Save(result *FetchResult) error {
+	url := result.PageResult.URL
+	name := SnapshotFileName(url)
+	if err := os.WriteFile(filepath.Join(s.dir, name), result.Body, 0644); err != nil {
+		return fmt.Errorf("failed to save HTML snapshot: %w", err)
+	}
+
+	sum := sha256.Sum256(result.Body)
+	s.mu.Lock()
+	s.entries = append(s.entries, Snapshot{
+		URL:         url,
+		File:        name,
+		StatusCode:  result.PageResult.StatusCode,
+		ContentType: result.ContentType,
+		Size:        len(result.Body),
+		SHA256:      hex.EncodeToString(sum[:]),
+		FetchedAt:   result.PageResult.CrawledAt,
+	})
+	s.mu.Unlock()
+	return nil
+}
+
+// Close writes the index manifest, sorted by URL
+func (s *SnapshotStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].URL < s.entries[j].URL
+	})
+
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.dir, SnapshotIndexFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot index: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshotIndex reads the index manifest of a snapshot directory
+func LoadSnapshotIndex(dir string) ([]Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, SnapshotIndexFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot index: %w", err)
+	}
+	var entries []Snapshot
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot index: %w", err)
+	}
+	return entries, nil
+}
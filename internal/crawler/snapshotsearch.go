@@ -0,0 +1,109 @@
+package crawler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// snippetContext is how many bytes of a page are kept on each side of a
+// match in its snippets
+const snippetContext = 40
+
+// maxSnippets is how many matches of each page get a snippet
+const maxSnippets = 3
+
+// SnapshotQuery is what SearchSnapshots looks for in saved page bodies
+type SnapshotQuery struct {
+	Pattern    string
+	Regexp     bool // Pattern is a regular expression rather than literal text
+	IgnoreCase bool
+	Missing    bool // List the pages without a match instead
+}
+
+// SnapshotMatch is a saved page found by SearchSnapshots
+type SnapshotMatch struct {
+	URL        string   `json:"url"`
+	File       string   `json:"file"`
+	StatusCode int      `json:"status_code"`
+	Count      int      `json:"count"`              // Matches in the page; 0 for pages missing the pattern
+	Snippets   []string `json:"snippets,omitempty"` // The first matches with the text around them
+}
+
+// SnapshotSearch is the result of searching a snapshot directory
+type SnapshotSearch struct {
+	Searched int             `json:"searched"` // HTML pages whose bodies were searched
+	Matches  []SnapshotMatch `json:"matches"`  // In index order, that is by URL
+}
+
+// SearchSnapshots searches the HTML pages saved in a snapshot directory (see
+// SnapshotStore) for a pattern, such as an analytics tag's ID. Other saved
+// bodies, such as PDFs, are skipped. With Missing set, the pages returning
+// 200 that don't contain the pattern are listed instead, since error pages
+// rarely carry the site's tags.
+func SearchSnapshots(dir string, query SnapshotQuery) (*SnapshotSearch, error) {
+	if query.Pattern == "" {
+		return nil, fmt.Errorf("search pattern is empty")
+	}
+	expr := query.Pattern
+	if !query.Regexp {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if query.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search pattern: %w", err)
+	}
+
+	entries, err := LoadSnapshotIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	search := &SnapshotSearch{Matches: []SnapshotMatch{}}
+	for _, entry := range entries {
+		if entry.ContentType != "" && !isHTMLContentType(entry.ContentType) {
+			continue
+		}
+		if query.Missing && entry.StatusCode != 200 {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join(dir, entry.File))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot of %s: %w", entry.URL, err)
+		}
+		search.Searched++
+
+		locs := re.FindAllIndex(body, -1)
+		if query.Missing != (len(locs) == 0) {
+			continue
+		}
+		match := SnapshotMatch{URL: entry.URL, File: entry.File, StatusCode: entry.StatusCode, Count: len(locs)}
+		for _, loc := range locs {
+			if len(match.Snippets) == maxSnippets {
+				break
+			}
+			match.Snippets = append(match.Snippets, snippet(body, loc[0], loc[1]))
+		}
+		search.Matches = append(search.Matches, match)
+	}
+	return search, nil
+}
+
+// snippet returns the match at body[start:end] with up to snippetContext
+// bytes on each side, on one line
+func snippet(body []byte, start, end int) string {
+	from, to := max(start-snippetContext, 0), min(end+snippetContext, len(body))
+	text := strings.Join(strings.Fields(string(body[from:to])), " ")
+	if from > 0 {
+		text = "…" + text
+	}
+	if to < len(body) {
+		text += "…"
+	}
+	return strings.ToValidUTF8(text, "")
+}
@@ -0,0 +1,255 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// CrawlStateVersion is the format version of saved crawl state
+const CrawlStateVersion = 1
+
+// DefaultCheckpointInterval is how often a crawl with a state file saves it
+const DefaultCheckpointInterval = time.Minute
+
+// CrawlState is a checkpoint of an unfinished crawl, with everything needed
+// to continue it through Manager.ResumeFrom
+type CrawlState struct {
+	Version         int                   `json:"version"`
+	SavedAt         time.Time             `json:"saved_at"`
+	Config          utils.CrawlFileConfig `json:"config"`
+	URLList         []string              `json:"url_list,omitempty"` // List mode URLs
+	Pending         []crawlTask           `json:"pending"`            // Queued URLs not crawled yet
+	Results         []*models.PageResult  `json:"results"`            // Also the visited set of the resumed crawl
+	Stream          *StreamState          `json:"stream,omitempty"`   // Where results went instead, when streamed
+	Graph           map[string][]string   `json:"graph,omitempty"`
+	Nofollow        map[string][]string   `json:"nofollow,omitempty"` // Graph edges flagged as nofollow
+	SitemapLastMod  map[string]*time.Time `json:"sitemap_lastmod,omitempty"`
+	SitemapPriority map[string]float64    `json:"sitemap_priority,omitempty"`
+}
+
+// PageCount returns the number of pages the saved crawl had crawled
+func (s *CrawlState) PageCount() int {
+	if s.Stream != nil {
+		return len(s.Results) + s.Stream.Pages
+	}
+	return len(s.Results)
+}
+
+// LoadCrawlState reads a state file written by a checkpointing crawl
+func LoadCrawlState(path string) (*CrawlState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("no saved crawl state at %s; the crawl finished or was never checkpointed", path)
+		}
+		return nil, fmt.Errorf("failed to read crawl state: %w", err)
+	}
+	var state CrawlState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse crawl state %s: %w", path, err)
+	}
+	if state.Version != CrawlStateVersion {
+		return nil, fmt.Errorf("crawl state %s has version %d; this version of barracuda reads version %d", path, state.Version, CrawlStateVersion)
+	}
+	return &state, nil
+}
+
+// SetStateFile makes the crawl checkpoint its state to path every interval
+// (0 disables periodic checkpoints) and when it is interrupted. The file is
+// removed when the crawl finishes without interruption.
+func (m *Manager) SetStateFile(path string, interval time.Duration) {
+	m.stateFile = path
+	m.checkpointInterval = interval
+	m.pending = make(map[crawlTask]int)
+}
+
+// ResumeFrom continues the crawl saved in state instead of seeding a new
+// one. It must be called before Crawl; restored pages are passed to the
+// hooks before any new page is crawled.
+func (m *Manager) ResumeFrom(state *CrawlState) {
+	m.resumeState = state
+}
+
+// Interrupted reports whether the crawl was stopped by a signal or Stop
+// rather than finishing, and so can be resumed. A crawl also ended by Cancel
+// isn't.
+func (m *Manager) Interrupted() bool {
+	m.controlMu.Lock()
+	defer m.controlMu.Unlock()
+	return m.interrupted.Load() && !m.cancelled
+}
+
+// restoreState loads a saved crawl's progress and returns its queued URLs
+func (m *Manager) restoreState(state *CrawlState) ([]crawlTask, error) {
+	if err := m.setStartURLs(); err != nil {
+		return nil, err
+	}
+
+	// URLs skipped by robots.txt aren't saved as visited; they are simply
+	// checked again if still pending
+	for _, page := range state.Results {
+		m.visited.Add(page.URL)
+	}
+	m.results = append(m.results, state.Results...)
+	m.pageCount += len(state.Results)
+	for source, targets := range state.Graph {
+		m.linkGraph.AddEdges(source, targets)
+	}
+	for source, targets := range state.Nofollow {
+		m.linkGraph.MarkNofollow(source, targets)
+	}
+	m.sitemapLastMod = state.SitemapLastMod
+	m.sitemapPriority = state.SitemapPriority
+
+	utils.Info("Resuming crawl",
+		utils.NewField("pages", len(state.Results)),
+		utils.NewField("pending", len(state.Pending)),
+		utils.NewField("saved_at", state.SavedAt))
+	return state.Pending, nil
+}
+
+// addPending records a task that is queued but not crawled yet
+func (m *Manager) addPending(task crawlTask) {
+	if m.pending == nil {
+		return
+	}
+	m.pendingMu.Lock()
+	m.pending[task]++
+	m.pendingMu.Unlock()
+}
+
+// donePending forgets a task once it has been crawled or dropped
+func (m *Manager) donePending(task crawlTask) {
+	if m.pending == nil {
+		return
+	}
+	m.pendingMu.Lock()
+	if m.pending[task] <= 1 {
+		delete(m.pending, task)
+	} else {
+		m.pending[task]--
+	}
+	m.pendingMu.Unlock()
+}
+
+// startCheckpoints saves the state every checkpoint interval until the
+// returned function is called
+func (m *Manager) startCheckpoints() (stop func()) {
+	if m.stateFile == "" || m.checkpointInterval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(m.checkpointInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := m.checkpoint(); err != nil {
+					utils.Warn("Failed to save crawl state", utils.NewField("path", m.stateFile), utils.NewField("error", err.Error()))
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// finishState saves the state of an interrupted crawl, or removes the state
+// file of one that finished or was cancelled
+func (m *Manager) finishState() {
+	m.settle()
+	if m.stateFile == "" {
+		return
+	}
+	if m.Interrupted() {
+		if err := m.checkpoint(); err != nil {
+			utils.Warn("Failed to save crawl state", utils.NewField("path", m.stateFile), utils.NewField("error", err.Error()))
+			return
+		}
+		utils.Info("Saved crawl state", utils.NewField("path", m.stateFile))
+		return
+	}
+	if err := os.Remove(m.stateFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		utils.Warn("Failed to remove crawl state", utils.NewField("path", m.stateFile), utils.NewField("error", err.Error()))
+	}
+}
+
+// checkpoint writes the crawl state to the state file. Tasks in progress
+// finish first, so the visited set, queue, and results agree.
+func (m *Manager) checkpoint() error {
+	m.checkpointMu.Lock()
+	state := m.captureState()
+	data, err := json.Marshal(state)
+	m.checkpointMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode crawl state: %w", err)
+	}
+
+	// Write then rename so a crash mid-write keeps the previous checkpoint
+	tmp, err := os.CreateTemp(filepath.Dir(m.stateFile), filepath.Base(m.stateFile)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write crawl state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write crawl state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write crawl state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.stateFile); err != nil {
+		return fmt.Errorf("failed to write crawl state: %w", err)
+	}
+	return nil
+}
+
+// captureState copies the crawl's progress. The caller holds checkpointMu.
+func (m *Manager) captureState() *CrawlState {
+	m.pendingMu.Lock()
+	pending := make([]crawlTask, 0, len(m.pending))
+	for task := range m.pending {
+		pending = append(pending, task)
+	}
+	m.pendingMu.Unlock()
+	sort.Slice(pending, func(i, j int) bool {
+		if pending[i].Depth != pending[j].Depth {
+			return pending[i].Depth < pending[j].Depth
+		}
+		return pending[i].URL < pending[j].URL
+	})
+
+	m.resultsMu.Lock()
+	results := append([]*models.PageResult(nil), m.results...)
+	stream := m.captureStream()
+	m.resultsMu.Unlock()
+
+	return &CrawlState{
+		Version:         CrawlStateVersion,
+		SavedAt:         time.Now(),
+		Config:          utils.CrawlFileConfigFrom(m.config),
+		URLList:         m.config.URLList,
+		Pending:         pending,
+		Results:         results,
+		Stream:          stream,
+		Graph:           m.linkGraph.GetAllEdges(),
+		Nofollow:        m.linkGraph.GetNofollowEdges(),
+		SitemapLastMod:  m.sitemapLastMod,
+		SitemapPriority: m.sitemapPriority,
+	}
+}
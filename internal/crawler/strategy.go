@@ -0,0 +1,34 @@
+package crawler
+
+import (
+	"math"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// startURLPriority puts start URLs ahead of every other task under
+// --strategy priority
+const startURLPriority = 100
+
+// taskPriority scores a URL for --strategy priority; tasks with higher
+// scores are crawled first, and all score 0 under other strategies. The
+// score is ten times the URL's sitemap <priority> (5 when it isn't listed),
+// plus 10 when it is linked from a start page, less 5 per level of depth, so
+// a homepage link outranks a deep page whatever the sitemap says. source is
+// the page the URL was found on, or empty for seeds.
+func (m *Manager) taskPriority(url string, depth int, source string) int {
+	if m.config.Strategy != utils.StrategyPriority {
+		return 0
+	}
+	if m.isStartURL(url) {
+		return startURLPriority
+	}
+	score := 5
+	if priority, ok := m.sitemapPriority[url]; ok {
+		score = int(math.Round(priority * 10))
+	}
+	if source != "" && m.isStartURL(source) {
+		score += 10
+	}
+	return score - 5*depth
+}
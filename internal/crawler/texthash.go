@@ -0,0 +1,75 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// simHashShingle is how many consecutive words make up one feature of a
+// page's simhash
+const simHashShingle = 3
+
+// textHasher collects a page's visible text while the parser walks the
+// document, to fingerprint it once the whole page has been read
+type textHasher struct {
+	text strings.Builder
+}
+
+// Write adds text, continuing a word left open by the previous write
+func (h *textHasher) Write(s string) {
+	h.text.WriteString(s)
+}
+
+// sums returns the SHA-256 of the page's words, lowercased and without
+// punctuation, and a 64-bit simhash of their shingles, both in hex. Markup,
+// whitespace, and letter case don't change either, and pages whose text
+// differs in a few words get simhashes only a few bits apart. Both are ""
+// for a page without text.
+func (h *textHasher) sums() (textHash, simHash string) {
+	words := strings.FieldsFunc(strings.ToLower(h.text.String()), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return "", ""
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(words, " ")))
+	return hex.EncodeToString(sum[:]), formatSimHash(simHashWords(words))
+}
+
+// simHashWords computes the simhash of the shingles of words: each bit is
+// set when most shingle hashes have it set
+func simHashWords(words []string) uint64 {
+	var weights [64]int
+	shingles := max(len(words)-simHashShingle+1, 1)
+	for i := 0; i < shingles; i++ {
+		hash := fnv.New64a()
+		hash.Write([]byte(strings.Join(words[i:min(i+simHashShingle, len(words))], " ")))
+		sum := hash.Sum64()
+		for bit := range weights {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var simHash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			simHash |= 1 << bit
+		}
+	}
+	return simHash
+}
+
+// formatSimHash writes a simhash as 16 hex digits; JSON numbers can't hold
+// 64 bits in JavaScript
+func formatSimHash(simHash uint64) string {
+	return fmt.Sprintf("%016x", simHash)
+}
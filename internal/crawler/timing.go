@@ -0,0 +1,116 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// requestTimer records when each phase of a request began and ended, through
+// an httptrace.ClientTrace. A redirect starts the phases over, so they are
+// those of the final request.
+type requestTimer struct {
+	mu     sync.Mutex
+	start  time.Time // Of the first request
+	phases requestPhases
+}
+
+// requestPhases are the times a request reached each phase; zero for those
+// it skipped, such as DNS and connecting when a connection is reused
+type requestPhases struct {
+	request             time.Time
+	dnsStart, dnsDone   time.Time
+	connStart, connDone time.Time
+	tlsStart, tlsDone   time.Time
+	wrote, firstByte    time.Time
+	reused              bool
+}
+
+// newRequestTimer returns a timer of a request starting now
+func newRequestTimer() *requestTimer {
+	return &requestTimer{start: time.Now()}
+}
+
+// trace returns the hooks that record the request's phases. The transport
+// may call them from other goroutines, and dial several addresses at once,
+// so phases keep their first start and last end.
+func (t *requestTimer) trace() *httptrace.ClientTrace {
+	mark := func(field func(p *requestPhases) *time.Time, first bool) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if at := field(&t.phases); !first || at.IsZero() {
+			*at = time.Now()
+		}
+	}
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.phases = requestPhases{request: time.Now()}
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			mark(func(p *requestPhases) *time.Time { return &p.dnsStart }, true)
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			mark(func(p *requestPhases) *time.Time { return &p.dnsDone }, false)
+		},
+		ConnectStart: func(string, string) {
+			mark(func(p *requestPhases) *time.Time { return &p.connStart }, true)
+		},
+		ConnectDone: func(string, string, error) {
+			mark(func(p *requestPhases) *time.Time { return &p.connDone }, false)
+		},
+		TLSHandshakeStart: func() {
+			mark(func(p *requestPhases) *time.Time { return &p.tlsStart }, true)
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			mark(func(p *requestPhases) *time.Time { return &p.tlsDone }, false)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.phases.reused = info.Reused
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			mark(func(p *requestPhases) *time.Time { return &p.wrote }, false)
+		},
+		GotFirstResponseByte: func() {
+			mark(func(p *requestPhases) *time.Time { return &p.firstByte }, false)
+		},
+	}
+}
+
+// ttfb returns the time from the first request to the first byte of the
+// final response, or 0 when no response came
+func (t *requestTimer) ttfb() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return elapsed(t.start, t.phases.firstByte)
+}
+
+// timing returns the phases of a request whose body took download to read
+func (t *requestTimer) timing(download time.Duration) *models.RequestTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.phases
+	return &models.RequestTiming{
+		Redirects:  elapsed(t.start, p.request).Milliseconds(),
+		DNS:        elapsed(p.dnsStart, p.dnsDone).Milliseconds(),
+		Connect:    elapsed(p.connStart, p.connDone).Milliseconds(),
+		TLS:        elapsed(p.tlsStart, p.tlsDone).Milliseconds(),
+		Server:     elapsed(p.wrote, p.firstByte).Milliseconds(),
+		Download:   download.Milliseconds(),
+		ConnReused: p.reused,
+	}
+}
+
+// elapsed returns the time from start to end, or 0 unless both happened
+func elapsed(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
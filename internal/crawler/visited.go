@@ -0,0 +1,211 @@
+package crawler
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// DefaultVisitedFPRate is the bloom filter false-positive rate used when
+// none is configured
+const DefaultVisitedFPRate = 0.001
+
+// VisitedStats describes the visited set at the end of a crawl
+type VisitedStats struct {
+	Exact      int  // URLs held in the exact set
+	Bloom      int  // URLs added to the bloom filter after the exact set filled up
+	BloomInUse bool // Whether the exact limit was reached
+	// BloomSkips counts URLs treated as visited only because the bloom filter
+	// matched them. Some of these may be false positives that were never crawled.
+	BloomSkips int
+	// EstimatedFalsePositives is an upper bound on how many BloomSkips were
+	// false positives, from the filter's fill level at each lookup
+	EstimatedFalsePositives float64
+}
+
+// visitedSet records crawled URLs. URLs are stored exactly up to limit; after
+// that, new URLs go into a bloom filter so memory stays bounded on very large
+// crawls at the cost of occasionally skipping an uncrawled URL. A compact set
+// stores 64-bit hashes of the URLs instead of the URLs themselves, a fraction
+// of the memory, and only confuses two URLs if their hashes collide.
+type visitedSet struct {
+	mu     sync.Mutex
+	exact  map[string]struct{}
+	hashes map[uint64]struct{} // Replaces exact in a compact set
+	limit  int                 // 0 keeps every URL exactly
+	bloom  *bloomFilter
+	fpRate float64
+	// capacity is the expected number of URLs that will overflow into the
+	// bloom filter, used to size it
+	capacity int
+
+	bloomSkips int
+	expectedFP float64
+}
+
+// newVisitedSet creates a visited set that switches to a bloom filter after
+// limit URLs (0 for no limit), sized for capacity overflow URLs. compact
+// stores URL hashes rather than URLs.
+func newVisitedSet(limit, capacity int, fpRate float64, compact bool) *visitedSet {
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = DefaultVisitedFPRate
+	}
+	v := &visitedSet{
+		limit:    limit,
+		fpRate:   fpRate,
+		capacity: capacity,
+	}
+	if compact {
+		v.hashes = make(map[uint64]struct{})
+	} else {
+		v.exact = make(map[string]struct{})
+	}
+	return v
+}
+
+// Add marks a URL as visited and reports whether it already was
+func (v *visitedSet) Add(url string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.seen(url) {
+		return true
+	}
+	if v.limit == 0 || v.exactCount() < v.limit {
+		if v.hashes != nil {
+			v.hashes[urlHash(url)] = struct{}{}
+		} else {
+			v.exact[url] = struct{}{}
+		}
+		return false
+	}
+	if v.bloom == nil {
+		v.bloom = newBloomFilter(v.capacity, v.fpRate)
+	}
+	v.bloom.Add(url)
+	return false
+}
+
+// Contains reports whether a URL has been visited
+func (v *visitedSet) Contains(url string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.seen(url)
+}
+
+// seen checks the exact set, then the bloom filter. The caller must hold v.mu.
+func (v *visitedSet) seen(url string) bool {
+	if v.hashes != nil {
+		if _, ok := v.hashes[urlHash(url)]; ok {
+			return true
+		}
+	} else if _, ok := v.exact[url]; ok {
+		return true
+	}
+	if v.bloom == nil {
+		return false
+	}
+	// The chance that this lookup is a false positive, if the URL is new
+	rate := v.bloom.FalsePositiveRate()
+	if !v.bloom.Test(url) {
+		return false
+	}
+	v.bloomSkips++
+	v.expectedFP += rate
+	return true
+}
+
+// exactCount returns the number of URLs held exactly, as URLs or hashes. The
+// caller must hold v.mu.
+func (v *visitedSet) exactCount() int {
+	return len(v.exact) + len(v.hashes)
+}
+
+// Stats returns the set's size and bloom filter skip counts
+func (v *visitedSet) Stats() VisitedStats {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	stats := VisitedStats{
+		Exact:                   v.exactCount(),
+		BloomInUse:              v.bloom != nil,
+		BloomSkips:              v.bloomSkips,
+		EstimatedFalsePositives: v.expectedFP,
+	}
+	if v.bloom != nil {
+		stats.Bloom = v.bloom.count
+	}
+	return stats
+}
+
+// bloomFilter is a fixed-size bloom filter using double hashing
+type bloomFilter struct {
+	bits  []uint64
+	m     uint64 // Number of bits
+	k     uint64 // Number of hash functions
+	count int    // Items added
+}
+
+// newBloomFilter sizes a filter for n items at false-positive rate p
+func newBloomFilter(n int, p float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// Add inserts an item
+func (b *bloomFilter) Add(item string) {
+	h1, h2 := bloomHashes(item)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+	b.count++
+}
+
+// Test reports whether an item may have been added
+func (b *bloomFilter) Test(item string) bool {
+	h1, h2 := bloomHashes(item)
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// FalsePositiveRate estimates the current false-positive rate from the
+// number of items added
+func (b *bloomFilter) FalsePositiveRate() float64 {
+	return math.Pow(1-math.Exp(-float64(b.k)*float64(b.count)/float64(b.m)), float64(b.k))
+}
+
+// urlHash returns the 64-bit FNV-1a hash of a URL
+func urlHash(item string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(item))
+	return h.Sum64()
+}
+
+// bloomHashes derives the two base hashes for double hashing from one
+// 64-bit FNV-1a hash
+func bloomHashes(item string) (uint64, uint64) {
+	sum := urlHash(item)
+	h1 := sum & 0xffffffff
+	h2 := sum>>32 | 1 // Odd, so successive probes don't repeat early
+	return h1, h2
+}
@@ -0,0 +1,284 @@
+// Package encrypt encrypts crawl output at rest in the age format
+// (https://age-encryption.org), so it can also be decrypted with the age
+// command-line tool.
+package encrypt
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// Ext is the extension added to encrypted files
+const Ext = ".age"
+
+// TarExt is the extension of an encrypted directory, archived with tar
+const TarExt = ".tar" + Ext
+
+// PassphraseEnv is the environment variable the passphrase is read from
+// when no key file is given
+const PassphraseEnv = "BARRACUDA_PASSPHRASE"
+
+// ErrNoKey is returned when neither a key file nor a passphrase is given
+var ErrNoKey = errors.New("encryption needs a key file or the " + PassphraseEnv + " environment variable")
+
+// Encryptor encrypts files to a passphrase or to age public keys
+type Encryptor struct {
+	recipients []age.Recipient
+}
+
+// NewEncryptor returns an Encryptor for the keys in keyFile, or for
+// passphrase when keyFile is "". keyFile holds age public keys ("age1…"),
+// one per line, or an identity made by age-keygen, whose public key is used.
+func NewEncryptor(keyFile, passphrase string) (*Encryptor, error) {
+	if keyFile == "" {
+		if passphrase == "" {
+			return nil, ErrNoKey
+		}
+		recipient, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return &Encryptor{recipients: []age.Recipient{recipient}}, nil
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	if recipients, err := age.ParseRecipients(bytes.NewReader(data)); err == nil {
+		return &Encryptor{recipients: recipients}, nil
+	}
+	identities, _ := age.ParseIdentities(bytes.NewReader(data))
+	e := &Encryptor{}
+	for _, identity := range identities {
+		if x25519, ok := identity.(*age.X25519Identity); ok {
+			e.recipients = append(e.recipients, x25519.Recipient())
+		}
+	}
+	if len(e.recipients) == 0 {
+		return nil, fmt.Errorf("key file %s holds no age public keys or identities", keyFile)
+	}
+	return e, nil
+}
+
+// File encrypts path to path+Ext and removes path. It returns the encrypted
+// file's path.
+func (e *Encryptor) File(path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer in.Close()
+
+	encrypted := path + Ext
+	if err := e.write(encrypted, func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	in.Close()
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove unencrypted %s: %w", filepath.Base(path), err)
+	}
+	return encrypted, nil
+}
+
+// Dir archives dir with tar, encrypts the archive to dir+TarExt, and
+// removes dir. It returns the encrypted archive's path.
+func (e *Encryptor) Dir(dir string) (string, error) {
+	dir = filepath.Clean(dir)
+	encrypted := dir + TarExt
+	if err := e.write(encrypted, func(w io.Writer) error {
+		return writeTar(w, dir)
+	}); err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("failed to remove unencrypted %s: %w", filepath.Base(dir), err)
+	}
+	return encrypted, nil
+}
+
+// write creates path and fills it with the encryption of what fill writes,
+// removing it again on failure
+func (e *Encryptor) write(path string, fill func(io.Writer) error) (err error) {
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(path), err)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to write %s: %w", filepath.Base(path), closeErr)
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	w, err := age.Encrypt(out, e.recipients...)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", filepath.Base(path), err)
+	}
+	if err := fill(w); err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", filepath.Base(path), err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to encrypt %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// writeTar writes the regular files under dir to w as a tar archive, with
+// paths relative to dir
+func writeTar(w io.Writer, dir string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// Decryptor decrypts files made by an Encryptor
+type Decryptor struct {
+	identities []age.Identity
+}
+
+// NewDecryptor returns a Decryptor for the identities in keyFile, as made by
+// age-keygen, or for passphrase when keyFile is ""
+func NewDecryptor(keyFile, passphrase string) (*Decryptor, error) {
+	if keyFile == "" {
+		if passphrase == "" {
+			return nil, ErrNoKey
+		}
+		identity, err := age.NewScryptIdentity(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return &Decryptor{identities: []age.Identity{identity}}, nil
+	}
+
+	file, err := os.Open(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	defer file.Close()
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("key file %s holds no age identities: %w", keyFile, err)
+	}
+	return &Decryptor{identities: identities}, nil
+}
+
+// File decrypts path, which must end in Ext, next to it without the
+// extension. An encrypted directory (TarExt) is extracted into a directory.
+// The encrypted file is kept. It returns the decrypted path.
+func (d *Decryptor) File(path string) (string, error) {
+	if !strings.HasSuffix(path, Ext) {
+		return "", fmt.Errorf("%s is not an encrypted file (no %s extension)", path, Ext)
+	}
+	in, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", filepath.Base(path), err)
+	}
+	defer in.Close()
+
+	r, err := age.Decrypt(in, d.identities...)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", filepath.Base(path), err)
+	}
+
+	if strings.HasSuffix(path, TarExt) {
+		dir := strings.TrimSuffix(path, TarExt)
+		if err := extractTar(r, dir); err != nil {
+			return "", fmt.Errorf("failed to extract %s: %w", filepath.Base(path), err)
+		}
+		return dir, nil
+	}
+
+	decrypted := strings.TrimSuffix(path, Ext)
+	out, err := os.Create(decrypted)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Base(decrypted), err)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, r); err != nil {
+		os.Remove(decrypted)
+		return "", fmt.Errorf("failed to decrypt %s: %w", filepath.Base(path), err)
+	}
+	return decrypted, out.Close()
+}
+
+// extractTar writes the regular files of a tar archive under dir, refusing
+// paths that would land outside it
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("unsafe path %q in archive", header.Name)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		out, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(out, tr)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package entitlements decides what each subscription tier may do on the
+// hosted API: how many pages a crawl and how many projects a user may have,
+// and which paid features they get. The limits live in one table, which a
+// JSON file can override, so plans change without code edits.
+package entitlements
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Subscription tiers of profiles.subscription_tier
+const (
+	TierFree = "free"
+	TierPro  = "pro"
+	TierTeam = "team"
+)
+
+// Entitlements is what one tier may do. Limits of 0 are unlimited.
+type Entitlements struct {
+	MaxPages        int  `json:"max_pages"`        // Pages per crawl
+	MaxProjects     int  `json:"max_projects"`     // Projects the user owns
+	ScheduledCrawls bool `json:"scheduled_crawls"` // Upload crawls run on a schedule
+	GSCSync         bool `json:"gsc_sync"`         // Connect and sync Google Search Console
+	Render          bool `json:"render"`           // Crawl with a headless browser
+	Exports         bool `json:"exports"`          // Generate exports on the server
+}
+
+// Unlimited is what self-hosted servers, which have no tiers, allow
+var Unlimited = Entitlements{ScheduledCrawls: true, GSCSync: true, Render: true, Exports: true}
+
+// Table holds the entitlements of each tier by name
+type Table map[string]Entitlements
+
+// Defaults returns the built-in entitlements of the free, pro, and team
+// tiers
+func Defaults() Table {
+	return Table{
+		TierFree: {MaxPages: 100, MaxProjects: 3},
+		TierPro:  {MaxPages: 10000, ScheduledCrawls: true, GSCSync: true, Render: true, Exports: true},
+		TierTeam: {MaxPages: 25000, ScheduledCrawls: true, GSCSync: true, Render: true, Exports: true},
+	}
+}
+
+// Load reads a JSON object of tiers from path over the defaults. A tier in
+// the file replaces only the fields it sets, and tiers the defaults lack,
+// such as a new "agency" plan, are added:
+//
+//	{"free": {"max_pages": 250}, "agency": {"max_pages": 100000, "gsc_sync": true}}
+func Load(path string) (Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entitlements: %w", err)
+	}
+
+	var overrides map[string]json.RawMessage
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse entitlements %s: %w", path, err)
+	}
+
+	table := Defaults()
+	for tier, raw := range overrides {
+		entitlements := table[tier]
+		if err := json.Unmarshal(raw, &entitlements); err != nil {
+			return nil, fmt.Errorf("failed to parse entitlements of tier %q: %w", tier, err)
+		}
+		if entitlements.MaxPages < 0 || entitlements.MaxProjects < 0 {
+			return nil, fmt.Errorf("entitlements of tier %q: limits can't be negative", tier)
+		}
+		table[tier] = entitlements
+	}
+	return table, nil
+}
+
+// For returns the entitlements of tier; tiers the table doesn't know, and
+// users without one, get the free tier's
+func (t Table) For(tier string) Entitlements {
+	if entitlements, ok := t[tier]; ok {
+		return entitlements
+	}
+	return t[TierFree]
+}
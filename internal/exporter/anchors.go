@@ -0,0 +1,53 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+)
+
+// anchorsHeader names the columns written by ExportAnchors
+var anchorsHeader = []string{
+	"Target URL",
+	"Anchor Text",
+	"Linking Pages",
+	"Share (%)",
+	"Flag",
+	"Example Source",
+}
+
+// ExportAnchors exports the anchor text report to a CSV file, one row per
+// anchor text of each linked page
+func ExportAnchors(anchors []analyzer.AnchorText, filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create anchor text file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(anchorsHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, anchor := range anchors {
+		row := []string{
+			anchor.Target,
+			anchor.Text,
+			strconv.Itoa(anchor.Pages),
+			strconv.FormatFloat(anchor.Share*100, 'f', 1, 64),
+			anchor.Flag,
+			anchor.Example,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write anchor text file: %w", err)
+	}
+	return file.Close()
+}
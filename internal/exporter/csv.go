@@ -1,16 +1,49 @@
 package exporter
 
 import (
+	"bytes"
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dillonlara115/barracuda/internal/analyzer"
 	"github.com/dillonlara115/barracuda/pkg/models"
 )
 
+func init() {
+	Register(csvExporter{})
+}
+
+// csvExporter writes one row per page; the summary is left out
+type csvExporter struct{}
+
+func (csvExporter) Format() string { return "csv" }
+
+func (csvExporter) Export(results []*models.PageResult, _ *analyzer.Summary, path string) error {
+	out, err := createOutput(path)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer out.Close()
+
+	return WriteCSV(out, results)
+}
+
+func (csvExporter) header() ([]byte, error) { return csvLine(csvHeader) }
+
+func (csvExporter) encode(result *models.PageResult) ([]byte, error) {
+	return csvLine(csvRow(result))
+}
+
+func (csvExporter) decode(r io.Reader, fn func(result *models.PageResult) error) error {
+	_, err := readCSV(r, fn)
+	return err
+}
+
 // ExportCSV exports page results to a CSV file
 func ExportCSV(results []*models.PageResult, filePath string) error {
 	file, err := os.Create(filePath)
@@ -19,55 +52,19 @@ func ExportCSV(results []*models.PageResult, filePath string) error {
 	}
 	defer file.Close()
 
-	writer := csv.NewWriter(file)
+	return WriteCSV(file, results)
+}
+
+// WriteCSV writes page results as CSV to w
+func WriteCSV(w io.Writer, results []*models.PageResult) error {
+	writer := csv.NewWriter(w)
 	defer writer.Flush()
 
-	// Write header
-	header := []string{
-		"URL",
-		"Status Code",
-		"Response Time (ms)",
-		"Title",
-		"Meta Description",
-		"Canonical",
-		"H1",
-		"H2",
-		"H3",
-		"H4",
-		"H5",
-		"H6",
-		"Internal Links",
-		"External Links",
-		"Redirect Chain",
-		"Error",
-		"Crawled At",
-	}
-	if err := writer.Write(header); err != nil {
+	if err := writer.Write(csvHeader); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
-
-	// Write rows
 	for _, result := range results {
-		row := []string{
-			result.URL,
-			strconv.Itoa(result.StatusCode),
-			strconv.FormatInt(result.ResponseTime, 10),
-			result.Title,
-			result.MetaDesc,
-			result.Canonical,
-			strings.Join(result.H1, " | "),
-			strings.Join(result.H2, " | "),
-			strings.Join(result.H3, " | "),
-			strings.Join(result.H4, " | "),
-			strings.Join(result.H5, " | "),
-			strings.Join(result.H6, " | "),
-			strings.Join(result.InternalLinks, " | "),
-			strings.Join(result.ExternalLinks, " | "),
-			strings.Join(result.RedirectChain, " -> "),
-			result.Error,
-			result.CrawledAt.Format(time.RFC3339),
-		}
-		if err := writer.Write(row); err != nil {
+		if err := writer.Write(csvRow(result)); err != nil {
 			return fmt.Errorf("failed to write CSV row: %w", err)
 		}
 	}
@@ -75,3 +72,174 @@ func ExportCSV(results []*models.PageResult, filePath string) error {
 	return nil
 }
 
+// csvHeader names the columns written by csvRow
+var csvHeader = []string{
+	"URL",
+	"Status Code",
+	"Response Time (ms)",
+	"Title",
+	"Meta Description",
+	"Canonical",
+	"H1",
+	"H2",
+	"H3",
+	"H4",
+	"H5",
+	"H6",
+	"Internal Links",
+	"External Links",
+	"Redirect Chain",
+	"Error",
+	"Crawled At",
+	"Depth",
+	"Word Count",
+	"Page Size (bytes)",
+	"Content Hash",
+	"Meta Robots",
+	"Hreflang",
+	"Structured Data",
+	"Expected Schema",
+	"Schema Version",
+	"Suggested Title",
+	"Suggested Meta Description",
+	"Cache-Control",
+	"Expires",
+	"Age",
+	"TTFB (ms)",
+	"Published",
+	"Modified",
+	"Sitemap Lastmod",
+	"Error Code",
+	"Content Type",
+	"Body Truncated",
+	"X-Robots-Tag",
+	"Content-Encoding",
+	"Server",
+	"In Sitemap",
+	"Rel Next",
+	"Rel Prev",
+	"Text Hash",
+	"Simhash",
+	"Redirects (ms)",
+	"DNS (ms)",
+	"Connect (ms)",
+	"TLS (ms)",
+	"Server (ms)",
+	"Download (ms)",
+}
+
+// csvRow renders a page as a row of csvHeader's columns
+func csvRow(result *models.PageResult) []string {
+	row := []string{
+		result.URL,
+		strconv.Itoa(result.StatusCode),
+		strconv.FormatInt(result.ResponseTime, 10),
+		result.Title,
+		result.MetaDesc,
+		result.Canonical,
+		strings.Join(result.H1, " | "),
+		strings.Join(result.H2, " | "),
+		strings.Join(result.H3, " | "),
+		strings.Join(result.H4, " | "),
+		strings.Join(result.H5, " | "),
+		strings.Join(result.H6, " | "),
+		strings.Join(result.InternalLinks, " | "),
+		strings.Join(result.ExternalLinks, " | "),
+		strings.Join(result.RedirectChain, " -> "),
+		result.Error,
+		result.CrawledAt.Format(time.RFC3339),
+		strconv.Itoa(result.Depth),
+		strconv.Itoa(result.WordCount),
+		strconv.Itoa(result.PageSize),
+		result.ContentHash,
+		result.MetaRobots,
+		formatHreflang(result.Hreflang),
+		formatStructuredData(result.StructuredData),
+		strings.Join(result.ExpectedSchema, " | "),
+		strconv.Itoa(schemaVersion(result)),
+		result.SuggestedTitle,
+		result.SuggestedMetaDesc,
+		result.Headers["Cache-Control"],
+		result.Headers["Expires"],
+		result.Headers["Age"],
+		strconv.FormatInt(result.TTFB, 10),
+		formatDate(result.PublishedAt),
+		formatDate(result.ModifiedAt),
+		formatDate(result.SitemapLastMod),
+		result.ErrorCode,
+		result.ContentType,
+		strconv.FormatBool(result.BodyTruncated),
+		result.Headers["X-Robots-Tag"],
+		result.Headers["Content-Encoding"],
+		result.Headers["Server"],
+		strconv.FormatBool(result.InSitemap),
+		result.RelNext,
+		result.RelPrev,
+		result.TextHash,
+		result.SimHash,
+	}
+	return append(row, timingColumns(result.Timing)...)
+}
+
+// timingColumns renders a page's request timing as the phase columns, left
+// empty for pages fetched before timing was recorded
+func timingColumns(timing *models.RequestTiming) []string {
+	if timing == nil {
+		return make([]string, 6)
+	}
+	columns := make([]string, 0, 6)
+	for _, ms := range []int64{timing.Redirects, timing.DNS, timing.Connect, timing.TLS, timing.Server, timing.Download} {
+		columns = append(columns, strconv.FormatInt(ms, 10))
+	}
+	return columns
+}
+
+// formatHreflang renders alternates as "lang=url | lang=url"
+func formatHreflang(alternates []models.Hreflang) string {
+	parts := make([]string, len(alternates))
+	for i, alt := range alternates {
+		parts[i] = alt.Lang + "=" + alt.URL
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatStructuredData renders structured data as "format:type | format:type",
+// following an invalid item with " (invalid: error)"
+func formatStructuredData(items []models.StructuredData) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = item.Format + ":" + item.Type
+		if item.Error != "" {
+			parts[i] += " (invalid: " + item.Error + ")"
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// formatDate renders an optional date as RFC 3339, or "" when unset
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// schemaVersion returns the schema version a result was written with,
+// treating results from before versioning as version 1
+func schemaVersion(result *models.PageResult) int {
+	if result.SchemaVersion == 0 {
+		return 1
+	}
+	return result.SchemaVersion
+}
+
+// csvLine renders one CSV record, with its line ending
+func csvLine(record []string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(record); err != nil {
+		return nil, fmt.Errorf("failed to write CSV row: %w", err)
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
@@ -3,6 +3,7 @@ package exporter
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -19,132 +20,271 @@ func ImportCSV(filePath string) ([]*models.PageResult, error) {
 	}
 	defer file.Close()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	var results []*models.PageResult
+	rows, err := readCSV(file, func(result *models.PageResult) error {
+		results = append(results, result)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV: %w", err)
+		return nil, err
 	}
-
-	if len(records) < 2 {
+	if rows == 0 {
 		return nil, fmt.Errorf("CSV file must have at least a header and one data row")
 	}
+	return results, nil
+}
+
+// readCSV parses results CSV from r, passing each page to fn, and returns
+// the number of data rows read
+func readCSV(r io.Reader, fn func(result *models.PageResult) error) (int, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err == io.EOF {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read CSV: %w", err)
+	}
 
 	// Parse header
-	header := records[0]
 	headerMap := make(map[string]int)
 	for i, h := range header {
 		headerMap[strings.ToLower(strings.TrimSpace(h))] = i
 	}
 
-	results := make([]*models.PageResult, 0, len(records)-1)
-
 	// Parse data rows
-	for i := 1; i < len(records); i++ {
-		row := records[i]
+	rows := 0
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		rows++
 		if len(row) == 0 {
 			continue
 		}
-
-		result := &models.PageResult{
-			H1:            make([]string, 0),
-			H2:            make([]string, 0),
-			H3:            make([]string, 0),
-			H4:            make([]string, 0),
-			H5:            make([]string, 0),
-			H6:            make([]string, 0),
-			InternalLinks: make([]string, 0),
-			ExternalLinks: make([]string, 0),
-			Images:        make([]models.Image, 0),
-			RedirectChain: make([]string, 0),
+		if result := parseCSVRow(headerMap, row); result != nil {
+			if err := fn(result); err != nil {
+				return rows, err
+			}
 		}
+	}
+}
 
-		// Helper to get field value safely
-		getField := func(name string) string {
-			if idx, ok := headerMap[name]; ok && idx < len(row) {
-				return strings.TrimSpace(row[idx])
-			}
-			return ""
+// parseCSVRow parses one data row, returning nil when it has no URL
+func parseCSVRow(headerMap map[string]int, row []string) *models.PageResult {
+	result := &models.PageResult{
+		H1:            make([]string, 0),
+		H2:            make([]string, 0),
+		H3:            make([]string, 0),
+		H4:            make([]string, 0),
+		H5:            make([]string, 0),
+		H6:            make([]string, 0),
+		InternalLinks: make([]string, 0),
+		ExternalLinks: make([]string, 0),
+		Images:        make([]models.Image, 0),
+		RedirectChain: make([]string, 0),
+	}
+
+	// Helper to get field value safely
+	getField := func(name string) string {
+		if idx, ok := headerMap[name]; ok && idx < len(row) {
+			return strings.TrimSpace(row[idx])
 		}
+		return ""
+	}
 
-		// Parse fields
-		result.URL = getField("url")
-		if result.URL == "" {
-			continue // Skip rows without URL
+	// Parse fields
+	result.URL = getField("url")
+	if result.URL == "" {
+		return nil // Skip rows without URL
+	}
+
+	// Status code
+	if statusStr := getField("status code"); statusStr != "" {
+		if status, err := strconv.Atoi(statusStr); err == nil {
+			result.StatusCode = status
 		}
+	}
 
-		// Status code
-		if statusStr := getField("status code"); statusStr != "" {
-			if status, err := strconv.Atoi(statusStr); err == nil {
-				result.StatusCode = status
-			}
+	// Response time
+	if timeStr := getField("response time (ms)"); timeStr != "" {
+		if timeMs, err := strconv.ParseInt(timeStr, 10, 64); err == nil {
+			result.ResponseTime = timeMs
 		}
+	}
 
-		// Response time
-		if timeStr := getField("response time (ms)"); timeStr != "" {
-			if timeMs, err := strconv.ParseInt(timeStr, 10, 64); err == nil {
-				result.ResponseTime = timeMs
-			}
+	if ttfbStr := getField("ttfb (ms)"); ttfbStr != "" {
+		if ttfb, err := strconv.ParseInt(ttfbStr, 10, 64); err == nil {
+			result.TTFB = ttfb
 		}
+	}
 
-		// Simple fields
-		result.Title = getField("title")
-		result.MetaDesc = getField("meta description")
-		result.Canonical = getField("canonical")
-		result.Error = getField("error")
+	// Simple fields
+	result.Title = getField("title")
+	result.MetaDesc = getField("meta description")
+	result.Canonical = getField("canonical")
+	result.RelNext = getField("rel next")
+	result.RelPrev = getField("rel prev")
+	result.Error = getField("error")
+	result.ErrorCode = getField("error code")
 
-		// Parse array fields (pipe-separated)
-		if h1Str := getField("h1"); h1Str != "" {
-			result.H1 = strings.Split(h1Str, " | ")
-		}
-		if h2Str := getField("h2"); h2Str != "" {
-			result.H2 = strings.Split(h2Str, " | ")
-		}
-		if h3Str := getField("h3"); h3Str != "" {
-			result.H3 = strings.Split(h3Str, " | ")
-		}
-		if h4Str := getField("h4"); h4Str != "" {
-			result.H4 = strings.Split(h4Str, " | ")
-		}
-		if h5Str := getField("h5"); h5Str != "" {
-			result.H5 = strings.Split(h5Str, " | ")
-		}
-		if h6Str := getField("h6"); h6Str != "" {
-			result.H6 = strings.Split(h6Str, " | ")
-		}
-		if internalStr := getField("internal links"); internalStr != "" {
-			result.InternalLinks = strings.Split(internalStr, " | ")
-		}
-		if externalStr := getField("external links"); externalStr != "" {
-			result.ExternalLinks = strings.Split(externalStr, " | ")
+	// Parse array fields (pipe-separated)
+	if h1Str := getField("h1"); h1Str != "" {
+		result.H1 = strings.Split(h1Str, " | ")
+	}
+	if h2Str := getField("h2"); h2Str != "" {
+		result.H2 = strings.Split(h2Str, " | ")
+	}
+	if h3Str := getField("h3"); h3Str != "" {
+		result.H3 = strings.Split(h3Str, " | ")
+	}
+	if h4Str := getField("h4"); h4Str != "" {
+		result.H4 = strings.Split(h4Str, " | ")
+	}
+	if h5Str := getField("h5"); h5Str != "" {
+		result.H5 = strings.Split(h5Str, " | ")
+	}
+	if h6Str := getField("h6"); h6Str != "" {
+		result.H6 = strings.Split(h6Str, " | ")
+	}
+	if internalStr := getField("internal links"); internalStr != "" {
+		result.InternalLinks = strings.Split(internalStr, " | ")
+	}
+	if externalStr := getField("external links"); externalStr != "" {
+		result.ExternalLinks = strings.Split(externalStr, " | ")
+	}
+	if redirectStr := getField("redirect chain"); redirectStr != "" {
+		result.RedirectChain = strings.Split(redirectStr, " -> ")
+	}
+
+	// Schema v2 fields; files written before v2 lack these columns
+	result.Depth = parseIntField(getField("depth"))
+	result.WordCount = parseIntField(getField("word count"))
+	result.PageSize = parseIntField(getField("page size (bytes)"))
+	result.ContentHash = getField("content hash")
+	result.TextHash = getField("text hash")
+	result.SimHash = getField("simhash")
+	result.MetaRobots = getField("meta robots")
+	result.Hreflang = parseHreflang(getField("hreflang"))
+	result.StructuredData = parseStructuredData(getField("structured data"))
+	if expected := getField("expected schema"); expected != "" {
+		result.ExpectedSchema = strings.Split(expected, " | ")
+	}
+	result.SchemaVersion = parseIntField(getField("schema version"))
+	if result.SchemaVersion == 0 {
+		result.SchemaVersion = 1
+	}
+	result.SuggestedTitle = getField("suggested title")
+	result.SuggestedMetaDesc = getField("suggested meta description")
+	for _, name := range []string{"Cache-Control", "Expires", "Age", "X-Robots-Tag", "Content-Encoding", "Server"} {
+		if value := getField(strings.ToLower(name)); value != "" {
+			if result.Headers == nil {
+				result.Headers = make(map[string]string)
+			}
+			result.Headers[name] = value
 		}
-		if redirectStr := getField("redirect chain"); redirectStr != "" {
-			result.RedirectChain = strings.Split(redirectStr, " -> ")
+	}
+
+	result.PublishedAt = parseDateField(getField("published"))
+	result.ModifiedAt = parseDateField(getField("modified"))
+	result.SitemapLastMod = parseDateField(getField("sitemap lastmod"))
+	result.ContentType = getField("content type")
+	result.BodyTruncated = getField("body truncated") == "true"
+	result.InSitemap = getField("in sitemap") == "true"
+	if getField("server (ms)") != "" {
+		result.Timing = &models.RequestTiming{
+			Redirects: int64(parseIntField(getField("redirects (ms)"))),
+			DNS:       int64(parseIntField(getField("dns (ms)"))),
+			Connect:   int64(parseIntField(getField("connect (ms)"))),
+			TLS:       int64(parseIntField(getField("tls (ms)"))),
+			Server:    int64(parseIntField(getField("server (ms)"))),
+			Download:  int64(parseIntField(getField("download (ms)"))),
 		}
+	}
+	if directives := result.RobotsDirectives(); directives != (models.RobotsDirectives{}) {
+		result.Robots = &directives
+	}
 
-		// Parse crawled at timestamp
-		if crawledStr := getField("crawled at"); crawledStr != "" {
-			if t, err := time.Parse(time.RFC3339, crawledStr); err == nil {
-				result.CrawledAt = t
-			} else {
-				// Try other common formats
-				for _, layout := range []string{
-					time.RFC3339Nano,
-					"2006-01-02 15:04:05",
-					"2006-01-02T15:04:05Z07:00",
-				} {
-					if t, err := time.Parse(layout, crawledStr); err == nil {
-						result.CrawledAt = t
-						break
-					}
+	// Parse crawled at timestamp
+	if crawledStr := getField("crawled at"); crawledStr != "" {
+		if t, err := time.Parse(time.RFC3339, crawledStr); err == nil {
+			result.CrawledAt = t
+		} else {
+			// Try other common formats
+			for _, layout := range []string{
+				time.RFC3339Nano,
+				"2006-01-02 15:04:05",
+				"2006-01-02T15:04:05Z07:00",
+			} {
+				if t, err := time.Parse(layout, crawledStr); err == nil {
+					result.CrawledAt = t
+					break
 				}
 			}
-		} else {
-			result.CrawledAt = time.Now()
 		}
+	} else {
+		result.CrawledAt = time.Now()
+	}
 
-		results = append(results, result)
+	return result
+}
+
+// parseIntField parses an integer column, returning 0 when empty or invalid
+func parseIntField(value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
 	}
+	return n
+}
 
-	return results, nil
+// parseDateField parses an RFC 3339 date column, returning nil when empty or
+// invalid
+func parseDateField(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// parseHreflang parses the "lang=url | lang=url" format written by WriteCSV
+func parseHreflang(value string) []models.Hreflang {
+	if value == "" {
+		return nil
+	}
+	var alternates []models.Hreflang
+	for _, part := range strings.Split(value, " | ") {
+		lang, url, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		alternates = append(alternates, models.Hreflang{Lang: lang, URL: url})
+	}
+	return alternates
 }
 
+// parseStructuredData parses the "format:type | format:type" format written by
+// WriteCSV, with the " (invalid: error)" of invalid items
+func parseStructuredData(value string) []models.StructuredData {
+	if value == "" {
+		return nil
+	}
+	var items []models.StructuredData
+	for _, part := range strings.Split(value, " | ") {
+		format, itemType, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		item := models.StructuredData{Format: format, Type: itemType}
+		if itemType, reason, ok := strings.Cut(itemType, " (invalid: "); ok {
+			item.Type, item.Error = itemType, strings.TrimSuffix(reason, ")")
+		}
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// StdoutPath is the export path that writes to standard output
+const StdoutPath = "-"
+
+// Exporter writes crawl results in one file format. Exporters register
+// themselves with Register so commands can pick one by name.
+type Exporter interface {
+	// Export writes results to path, or to stdout when path is StdoutPath
+	// and the format is text. summary may be nil; formats without room for
+	// it ignore it.
+	Export(results []*models.PageResult, summary *analyzer.Summary, path string) error
+	// Format returns the name the exporter is selected by, which is also
+	// its file extension
+	Format() string
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Exporter)
+)
+
+// Register makes an exporter available under its Format. It panics when
+// the format is already registered, so clashes show up at startup.
+func Register(e Exporter) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	format := e.Format()
+	if _, exists := registry[format]; exists {
+		panic(fmt.Sprintf("exporter: format %q registered twice", format))
+	}
+	registry[format] = e
+}
+
+// Lookup returns the exporter registered for format
+func Lookup(format string) (Exporter, error) {
+	registryMu.RLock()
+	e, ok := registry[format]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q (valid: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return e, nil
+}
+
+// Formats lists the registered formats in alphabetical order
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	formats := make([]string, 0, len(registry))
+	for format := range registry {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// createOutput opens path for writing, or returns stdout for StdoutPath. The
+// caller closes the result; closing stdout is a no-op.
+func createOutput(path string) (io.WriteCloser, error) {
+	if path == StdoutPath {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+// nopCloser is a Writer whose Close does nothing
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
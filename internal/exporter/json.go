@@ -3,11 +3,34 @@ package exporter
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
+	"github.com/dillonlara115/barracuda/internal/analyzer"
 	"github.com/dillonlara115/barracuda/pkg/models"
 )
 
+func init() {
+	Register(jsonExporter{})
+}
+
+// jsonExporter writes the results as an array of pages, indented in files and
+// compact on stdout; the summary is left out
+type jsonExporter struct{}
+
+func (jsonExporter) Format() string { return "json" }
+
+func (jsonExporter) Export(results []*models.PageResult, _ *analyzer.Summary, path string) error {
+	out, err := createOutput(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON file: %w", err)
+	}
+	defer out.Close()
+
+	return WriteJSON(out, results, path != StdoutPath)
+}
+
 // ExportJSON exports page results to a JSON file
 func ExportJSON(results []*models.PageResult, filePath string, pretty bool) error {
 	file, err := os.Create(filePath)
@@ -16,7 +39,12 @@ func ExportJSON(results []*models.PageResult, filePath string, pretty bool) erro
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
+	return WriteJSON(file, results, pretty)
+}
+
+// WriteJSON writes page results as JSON to w
+func WriteJSON(w io.Writer, results []*models.PageResult, pretty bool) error {
+	encoder := json.NewEncoder(w)
 	if pretty {
 		encoder.SetIndent("", "  ")
 	}
@@ -28,3 +56,39 @@ func ExportJSON(results []*models.PageResult, filePath string, pretty bool) erro
 	return nil
 }
 
+// ImportJSON imports page results from a JSON file
+func ImportJSON(filePath string) ([]*models.PageResult, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file: %w", err)
+	}
+
+	var results []*models.PageResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse results JSON: %w", err)
+	}
+
+	// Files written before schema versioning are version 1
+	for _, result := range results {
+		if result != nil && result.SchemaVersion == 0 {
+			result.SchemaVersion = 1
+		}
+	}
+	return results, nil
+}
+
+// ImportResults imports page results from a CSV, JSON Lines, or JSON file,
+// chosen by extension
+func ImportResults(filePath string) ([]*models.PageResult, error) {
+	if strings.HasSuffix(strings.ToLower(filePath), ".jsonl") {
+		return ImportJSONL(filePath)
+	}
+	if strings.HasSuffix(strings.ToLower(filePath), ".csv") {
+		results, err := ImportCSV(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import CSV: %w", err)
+		}
+		return results, nil
+	}
+	return ImportJSON(filePath)
+}
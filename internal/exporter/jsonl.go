@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+func init() {
+	Register(jsonlExporter{})
+}
+
+// jsonlExporter writes one compact JSON page per line, which can be appended
+// to as a crawl runs and read back a page at a time; the summary is left out
+type jsonlExporter struct{}
+
+func (jsonlExporter) Format() string { return "jsonl" }
+
+func (jsonlExporter) Export(results []*models.PageResult, _ *analyzer.Summary, path string) error {
+	out, err := createOutput(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JSONL file: %w", err)
+	}
+	defer out.Close()
+
+	return WriteJSONL(out, results)
+}
+
+func (jsonlExporter) header() ([]byte, error) { return nil, nil }
+
+func (jsonlExporter) encode(result *models.PageResult) ([]byte, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func (jsonlExporter) decode(r io.Reader, fn func(result *models.PageResult) error) error {
+	return readJSONL(r, fn)
+}
+
+// WriteJSONL writes page results as JSON Lines to w
+func WriteJSONL(w io.Writer, results []*models.PageResult) error {
+	buffered := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffered)
+	for _, result := range results {
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	}
+	return buffered.Flush()
+}
+
+// ImportJSONL imports page results from a JSON Lines file
+func ImportJSONL(filePath string) ([]*models.PageResult, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results file: %w", err)
+	}
+	defer file.Close()
+
+	var results []*models.PageResult
+	err = readJSONL(file, func(result *models.PageResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// readJSONL parses JSON Lines results from r, passing each page to fn
+func readJSONL(r io.Reader, fn func(result *models.PageResult) error) error {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for decoder.More() {
+		var result models.PageResult
+		if err := decoder.Decode(&result); err != nil {
+			return fmt.Errorf("failed to parse results JSONL: %w", err)
+		}
+		if result.SchemaVersion == 0 {
+			result.SchemaVersion = 1
+		}
+		if err := fn(&result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/internal/kb"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// Report sections, in the order they are rendered
+const (
+	SectionExecutive   = "executive"
+	SectionIssues      = "issues"
+	SectionPages       = "pages"
+	SectionPerformance = "performance"
+)
+
+// AllSections lists every report section in render order
+var AllSections = []string{SectionExecutive, SectionIssues, SectionPages, SectionPerformance}
+
+// Branding customizes the look of a report
+type Branding struct {
+	Name    string // Agency or company name shown in the header
+	LogoURI string // Image URL or data URI for the header logo
+	Color   string // CSS accent color, e.g. "#0b6efd"
+}
+
+// ReportOptions controls what a report contains
+type ReportOptions struct {
+	Title       string   // Defaults to "SEO Audit: <site>"
+	Sections    []string // Defaults to AllSections
+	MaxExamples int      // Example URLs listed per issue type (default 10)
+	MaxPages    int      // Rows in the pages table (default 100)
+	Branding    Branding
+}
+
+// Report is the data rendered into a client-facing report
+type Report struct {
+	Title       string
+	Site        string
+	CrawledAt   time.Time
+	GeneratedAt time.Time
+	Branding    Branding
+	Sections    map[string]bool
+
+	Summary     *analyzer.Summary
+	HealthScore int
+	Severity    map[models.Severity]int
+	IssueGroups []IssueGroup
+	Pages       []PageRow
+	MorePages   int
+}
+
+// IssueGroup collects the issues of one type for display
+type IssueGroup struct {
+	Type           analyzer.IssueType
+	Label          string
+	Severity       models.Severity
+	Count          int
+	Recommendation string
+	Doc            *kb.Article // Knowledge base article; nil for issue types without one
+	Examples       []analyzer.Issue
+	More           int
+}
+
+// Action returns what to do about the group's issues in a few words
+func (g IssueGroup) Action() string {
+	if g.Doc != nil {
+		return g.Doc.Title
+	}
+	return g.Recommendation
+}
+
+// PageRow is one row of the pages table
+type PageRow struct {
+	URL          string
+	StatusCode   int
+	Title        string
+	Issues       int
+	ResponseTime int64
+}
+
+// severityRank orders severities from most to least important
+var severityRank = map[models.Severity]int{models.SeverityError: 0, models.SeverityWarning: 1, models.SeverityInfo: 2}
+
+// NewReport builds a report from a crawl's summary and page results.
+// results may be nil, in which case the pages section is left empty.
+func NewReport(site string, crawledAt time.Time, summary *analyzer.Summary, results []*models.PageResult, opts ReportOptions) *Report {
+	if opts.MaxExamples <= 0 {
+		opts.MaxExamples = 10
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = 100
+	}
+	if len(opts.Sections) == 0 {
+		opts.Sections = AllSections
+	}
+
+	report := &Report{
+		Title:       opts.Title,
+		Site:        site,
+		CrawledAt:   crawledAt,
+		GeneratedAt: time.Now(),
+		Branding:    opts.Branding,
+		Sections:    make(map[string]bool),
+		Summary:     summary,
+		HealthScore: HealthScore(summary),
+		Severity:    summary.GetIssueCountBySeverity(),
+	}
+	if report.Title == "" {
+		report.Title = i18n.T("report.default_title", site)
+	}
+	for _, section := range opts.Sections {
+		report.Sections[section] = true
+	}
+
+	// Group issues by type, most severe and most frequent first
+	groups := make(map[analyzer.IssueType]*IssueGroup)
+	issuesPerPage := make(map[string]int)
+	for _, issue := range summary.Issues {
+		issuesPerPage[issue.URL]++
+		group, ok := groups[issue.Type]
+		if !ok {
+			group = &IssueGroup{
+				Type:           issue.Type,
+				Label:          analyzer.IssueTypeLabel(issue.Type),
+				Severity:       issue.Severity,
+				Recommendation: issue.Recommendation,
+			}
+			// Results saved before issues had doc keys still get their article
+			docKey := issue.DocKey
+			if docKey == "" {
+				docKey = kb.KeyFor(issue.Type)
+			}
+			if article, ok := kb.Lookup(docKey); ok {
+				group.Doc = article
+			}
+			groups[issue.Type] = group
+		}
+		group.Count++
+		if len(group.Examples) < opts.MaxExamples {
+			group.Examples = append(group.Examples, issue)
+		} else {
+			group.More++
+		}
+	}
+	for _, group := range groups {
+		report.IssueGroups = append(report.IssueGroups, *group)
+	}
+	sort.Slice(report.IssueGroups, func(i, j int) bool {
+		a, b := report.IssueGroups[i], report.IssueGroups[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Label < b.Label
+	})
+
+	// Pages with the most issues first
+	for _, result := range results {
+		report.Pages = append(report.Pages, PageRow{
+			URL:          result.URL,
+			StatusCode:   result.StatusCode,
+			Title:        result.Title,
+			Issues:       issuesPerPage[result.URL],
+			ResponseTime: result.ResponseTime,
+		})
+	}
+	sort.SliceStable(report.Pages, func(i, j int) bool {
+		return report.Pages[i].Issues > report.Pages[j].Issues
+	})
+	if len(report.Pages) > opts.MaxPages {
+		report.MorePages = len(report.Pages) - opts.MaxPages
+		report.Pages = report.Pages[:opts.MaxPages]
+	}
+
+	return report
+}
+
+// HealthScore rates a crawl from 0 to 100. Each error costs 10 points per
+// page, each warning 3, and each informational notice 1, averaged over all pages.
+func HealthScore(summary *analyzer.Summary) int {
+	if summary.TotalPages == 0 {
+		return 0
+	}
+
+	penalty := 0
+	for _, issue := range summary.Issues {
+		switch issue.Severity {
+		case models.SeverityError:
+			penalty += 10
+		case models.SeverityWarning:
+			penalty += 3
+		default:
+			penalty++
+		}
+	}
+
+	score := 100 - penalty/summary.TotalPages
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// ExecutiveSummary returns the plain-language overview paragraph
+func (r *Report) ExecutiveSummary() string {
+	overview := i18n.T("report.exec_overview",
+		r.Summary.TotalPages, r.Site, r.Summary.TotalIssues,
+		r.Severity[models.SeverityError], r.Severity[models.SeverityWarning], r.Severity[models.SeverityInfo])
+	if r.Summary.TotalIssues == 0 {
+		return overview + " " + i18n.T("report.exec_clean")
+	}
+	return overview
+}
+
+// TopFindings returns up to limit issue groups for the executive summary
+func (r *Report) TopFindings(limit int) []IssueGroup {
+	if len(r.IssueGroups) < limit {
+		return r.IssueGroups
+	}
+	return r.IssueGroups[:limit]
+}
+
+// Has reports whether a section is included
+func (r *Report) Has(section string) bool {
+	return r.Sections[section]
+}
+
+// ParseSections validates a list of section names
+func ParseSections(names []string) ([]string, error) {
+	valid := make(map[string]bool)
+	for _, section := range AllSections {
+		valid[section] = true
+	}
+
+	var sections []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !valid[name] {
+			return nil, fmt.Errorf("unknown report section %q (valid: %s)", name, strings.Join(AllSections, ", "))
+		}
+		sections = append(sections, name)
+	}
+	return sections, nil
+}
+
+// severityLabel returns the localized label for an issue severity
+func severityLabel(severity models.Severity) string {
+	return i18n.T("report.severity." + string(severity))
+}
@@ -0,0 +1,182 @@
+package exporter
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+)
+
+// defaultBrandColor is the accent color used when no branding color is set
+const defaultBrandColor = "#0b6efd"
+
+// cssColor matches the color values accepted for branding
+var cssColor = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|[a-zA-Z]+)$`)
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"t":        i18n.T,
+	"severity": severityLabel,
+	"safeURL": func(s string) template.URL {
+		return template.URL(s)
+	},
+	"scoreClass": func(score int) string {
+		switch {
+		case score >= 80:
+			return "good"
+		case score >= 50:
+			return "fair"
+		default:
+			return "poor"
+		}
+	},
+}).Parse(reportHTML))
+
+// WriteHTMLReport renders a report as a self-contained HTML page
+func WriteHTMLReport(w io.Writer, r *Report) error {
+	color := r.Branding.Color
+	if !cssColor.MatchString(color) {
+		color = defaultBrandColor
+	}
+
+	data := struct {
+		*Report
+		Lang  string
+		Color template.CSS
+	}{r, i18n.Locale(), template.CSS(color)}
+
+	if err := reportTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
+
+const reportHTML = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+  :root { --brand: {{.Color}}; }
+  body { font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; color: #1f2933; margin: 0 auto; max-width: 960px; padding: 32px; line-height: 1.5; }
+  header { display: flex; align-items: center; gap: 24px; border-bottom: 4px solid var(--brand); padding-bottom: 16px; margin-bottom: 32px; }
+  header img { max-height: 64px; max-width: 200px; }
+  h1 { margin: 0; font-size: 28px; }
+  h2 { color: var(--brand); border-bottom: 1px solid #e4e7eb; padding-bottom: 4px; margin-top: 40px; }
+  .meta { color: #616e7c; font-size: 14px; }
+  .score { display: inline-block; font-size: 40px; font-weight: bold; padding: 8px 20px; border-radius: 8px; color: #fff; }
+  .score.good { background: #2f9e44; } .score.fair { background: #f08c00; } .score.poor { background: #e03131; }
+  .stats { display: grid; grid-template-columns: repeat(3, 1fr); gap: 12px; margin: 16px 0; }
+  .stat { background: #f5f7fa; border-radius: 6px; padding: 12px; }
+  .stat b { display: block; font-size: 24px; }
+  .badge { display: inline-block; font-size: 12px; padding: 2px 8px; border-radius: 10px; color: #fff; vertical-align: middle; }
+  .badge.error { background: #e03131; } .badge.warning { background: #f08c00; } .badge.info { background: #1c7ed6; }
+  .issue { page-break-inside: avoid; margin-bottom: 24px; }
+  .issue h3 { margin-bottom: 4px; }
+  .recommendation { background: #f5f7fa; border-left: 4px solid var(--brand); padding: 8px 12px; }
+  .recommendation p { margin: 0 0 8px; } .recommendation ol { margin: 0 0 8px; padding-left: 20px; }
+  .recommendation pre { background: #fff; border: 1px solid #e4e7eb; padding: 8px; font-size: 12px; overflow-x: auto; white-space: pre-wrap; }
+  table { border-collapse: collapse; width: 100%; font-size: 13px; }
+  th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #e4e7eb; vertical-align: top; }
+  th { background: #f5f7fa; }
+  td.url { word-break: break-all; }
+  td.sub { padding-left: 24px; }
+  ul.examples { font-size: 13px; }
+  footer { margin-top: 48px; color: #9aa5b1; font-size: 12px; text-align: center; }
+  @media print { body { padding: 0; } h2 { page-break-after: avoid; } }
+</style>
+</head>
+<body>
+<header>
+  {{if .Branding.LogoURI}}<img src="{{safeURL .Branding.LogoURI}}" alt="{{.Branding.Name}}">{{end}}
+  <div>
+    <h1>{{.Title}}</h1>
+    <div class="meta">
+      {{if .Branding.Name}}{{t "report.prepared_by" .Branding.Name}} · {{end}}
+      {{if not .CrawledAt.IsZero}}{{t "report.crawled_at" (.CrawledAt.Format "2006-01-02 15:04")}} · {{end}}
+      {{t "report.generated_at" (.GeneratedAt.Format "2006-01-02 15:04")}}
+    </div>
+  </div>
+</header>
+
+{{if .Has "executive"}}
+<section>
+  <h2>{{t "report.section.executive"}}</h2>
+  <p>{{t "report.health_score"}}: <span class="score {{scoreClass .HealthScore}}">{{.HealthScore}}/100</span></p>
+  <p>{{.ExecutiveSummary}}</p>
+  <div class="stats">
+    <div class="stat"><b>{{.Summary.TotalPages}}</b>{{t "summary.total_pages"}}</div>
+    <div class="stat"><b>{{.Summary.TotalIssues}}</b>{{t "summary.total_issues"}}</div>
+    <div class="stat"><b>{{.Summary.AverageResponseTime}} ms</b>{{t "summary.avg_response_time"}}</div>
+  </div>
+  {{with .TopFindings 5}}
+  <p>{{t "report.top_findings"}}</p>
+  <ul>
+    {{range .}}<li><span class="badge {{.Severity}}">{{severity .Severity}}</span> <b>{{.Label}}</b> ({{.Count}}): {{.Action}}</li>{{end}}
+  </ul>
+  {{end}}
+</section>
+{{end}}
+
+{{if and (.Has "issues") .IssueGroups}}
+<section>
+  <h2>{{t "report.section.issues"}}</h2>
+  {{range .IssueGroups}}
+  <div class="issue">
+    <h3>{{.Label}} <span class="badge {{.Severity}}">{{severity .Severity}}</span> <span class="meta">({{.Count}})</span></h3>
+    {{with .Doc}}
+    <div class="recommendation">
+      <p>{{.Description}}</p>
+      <p><b>{{t "report.how_to_fix"}}</b></p>
+      <ol>{{range .Steps}}<li>{{.}}</li>{{end}}</ol>
+      {{if .Example}}<pre>{{.Example}}</pre>{{end}}
+      {{if .Links}}<p>{{t "report.learn_more"}} {{range $i, $link := .Links}}{{if $i}} · {{end}}<a href="{{$link.URL}}">{{$link.Title}}</a>{{end}}</p>{{end}}
+    </div>
+    {{else}}{{if .Recommendation}}<div class="recommendation">{{t "summary.recommendation" .Recommendation}}</div>{{end}}{{end}}
+    <ul class="examples">
+      {{range .Examples}}<li><span class="url">{{.URL}}</span> — {{.Message}}</li>{{end}}
+      {{if .More}}<li>{{t "summary.and_more" .More}}</li>{{end}}
+    </ul>
+  </div>
+  {{end}}
+</section>
+{{end}}
+
+{{if and (.Has "pages") .Pages}}
+<section>
+  <h2>{{t "report.section.pages"}}</h2>
+  <table>
+    <tr><th>{{t "report.col.url"}}</th><th>{{t "report.col.status"}}</th><th>{{t "report.col.title"}}</th><th>{{t "report.col.issues"}}</th><th>{{t "report.col.response_time"}}</th></tr>
+    {{range .Pages}}<tr><td class="url">{{.URL}}</td><td>{{.StatusCode}}</td><td>{{.Title}}</td><td>{{.Issues}}</td><td>{{.ResponseTime}} ms</td></tr>
+    {{end}}
+  </table>
+  {{if .MorePages}}<p class="meta">{{t "summary.and_more" .MorePages}}</p>{{end}}
+</section>
+{{end}}
+
+{{if .Has "performance"}}
+<section>
+  <h2>{{t "report.section.performance"}}</h2>
+  <table>
+    <tr><td>{{t "summary.avg_response_time"}}</td><td>{{.Summary.AverageResponseTime}} ms</td></tr>
+    <tr><td>{{t "summary.pages_with_errors"}}</td><td>{{.Summary.PagesWithErrors}}</td></tr>
+    {{range .Summary.ErrorBreakdown}}<tr><td class="sub">{{t (print "summary.error." .Code)}}</td><td>{{.Count}}</td></tr>
+    {{end}}
+    <tr><td>{{t "summary.pages_with_redirects"}}</td><td>{{.Summary.PagesWithRedirects}}</td></tr>
+    <tr><td>{{t "summary.internal_links"}}</td><td>{{.Summary.TotalInternalLinks}}</td></tr>
+    <tr><td>{{t "summary.external_links"}}</td><td>{{.Summary.TotalExternalLinks}}</td></tr>
+  </table>
+  {{with .Summary.SlowestPages}}
+  <h3>{{t "summary.slowest_pages"}}</h3>
+  <table>
+    {{range .}}<tr><td class="url">{{.URL}}</td><td>{{.ResponseTime}} ms</td></tr>{{end}}
+  </table>
+  {{end}}
+</section>
+{{end}}
+
+<footer>{{if .Branding.Name}}{{.Branding.Name}} · {{end}}{{.Site}}</footer>
+</body>
+</html>
+`
@@ -0,0 +1,119 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+)
+
+// WriteMarkdownReport renders a report as Markdown
+func WriteMarkdownReport(w io.Writer, r *Report) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintf(b, "# %s\n\n", r.Title)
+	if r.Branding.LogoURI != "" && !strings.HasPrefix(r.Branding.LogoURI, "data:") {
+		fmt.Fprintf(b, "![%s](%s)\n\n", r.Branding.Name, r.Branding.LogoURI)
+	}
+	if r.Branding.Name != "" {
+		fmt.Fprintf(b, "%s  \n", i18n.T("report.prepared_by", r.Branding.Name))
+	}
+	if !r.CrawledAt.IsZero() {
+		fmt.Fprintf(b, "%s  \n", i18n.T("report.crawled_at", r.CrawledAt.Format("2006-01-02 15:04")))
+	}
+	fmt.Fprintf(b, "%s\n\n", i18n.T("report.generated_at", r.GeneratedAt.Format("2006-01-02 15:04")))
+
+	if r.Has(SectionExecutive) {
+		fmt.Fprintf(b, "## %s\n\n", i18n.T("report.section.executive"))
+		fmt.Fprintf(b, "**%s: %d/100**\n\n", i18n.T("report.health_score"), r.HealthScore)
+		fmt.Fprintf(b, "%s\n\n", r.ExecutiveSummary())
+		if findings := r.TopFindings(5); len(findings) > 0 {
+			fmt.Fprintf(b, "%s\n\n", i18n.T("report.top_findings"))
+			for _, group := range findings {
+				fmt.Fprintf(b, "- **%s** (%d): %s\n", group.Label, group.Count, group.Action())
+			}
+			fmt.Fprintf(b, "\n")
+		}
+	}
+
+	if r.Has(SectionIssues) && len(r.IssueGroups) > 0 {
+		fmt.Fprintf(b, "## %s\n\n", i18n.T("report.section.issues"))
+		for _, group := range r.IssueGroups {
+			fmt.Fprintf(b, "### %s — %s (%d)\n\n", group.Label, severityLabel(group.Severity), group.Count)
+			if doc := group.Doc; doc != nil {
+				fmt.Fprintf(b, "%s\n\n", mdEscape(doc.Description))
+				fmt.Fprintf(b, "**%s**\n\n", i18n.T("report.how_to_fix"))
+				for i, step := range doc.Steps {
+					fmt.Fprintf(b, "%d. %s\n", i+1, mdEscape(step))
+				}
+				fmt.Fprintf(b, "\n")
+				if doc.Example != "" {
+					fmt.Fprintf(b, "```\n%s\n```\n\n", doc.Example)
+				}
+				if len(doc.Links) > 0 {
+					links := make([]string, len(doc.Links))
+					for i, link := range doc.Links {
+						links[i] = fmt.Sprintf("[%s](%s)", link.Title, link.URL)
+					}
+					fmt.Fprintf(b, "%s %s\n\n", i18n.T("report.learn_more"), strings.Join(links, " · "))
+				}
+			} else if group.Recommendation != "" {
+				fmt.Fprintf(b, "%s\n\n", i18n.T("summary.recommendation", group.Recommendation))
+			}
+			for _, issue := range group.Examples {
+				fmt.Fprintf(b, "- %s — %s\n", issue.URL, mdEscape(issue.Message))
+			}
+			if group.More > 0 {
+				fmt.Fprintf(b, "- %s\n", i18n.T("summary.and_more", group.More))
+			}
+			fmt.Fprintf(b, "\n")
+		}
+	}
+
+	if r.Has(SectionPages) && len(r.Pages) > 0 {
+		fmt.Fprintf(b, "## %s\n\n", i18n.T("report.section.pages"))
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n",
+			i18n.T("report.col.url"), i18n.T("report.col.status"), i18n.T("report.col.title"),
+			i18n.T("report.col.issues"), i18n.T("report.col.response_time"))
+		fmt.Fprintf(b, "|---|---|---|---|---|\n")
+		for _, page := range r.Pages {
+			fmt.Fprintf(b, "| %s | %d | %s | %d | %d ms |\n",
+				mdEscape(page.URL), page.StatusCode, mdEscape(page.Title), page.Issues, page.ResponseTime)
+		}
+		if r.MorePages > 0 {
+			fmt.Fprintf(b, "\n%s\n", i18n.T("summary.and_more", r.MorePages))
+		}
+		fmt.Fprintf(b, "\n")
+	}
+
+	if r.Has(SectionPerformance) {
+		fmt.Fprintf(b, "## %s\n\n", i18n.T("report.section.performance"))
+		fmt.Fprintf(b, "- %s: %d ms\n", i18n.T("summary.avg_response_time"), r.Summary.AverageResponseTime)
+		fmt.Fprintf(b, "- %s: %d\n", i18n.T("summary.pages_with_errors"), r.Summary.PagesWithErrors)
+		for _, row := range r.Summary.ErrorBreakdown() {
+			fmt.Fprintf(b, "  - %s: %d\n", i18n.T("summary.error."+row.Code), row.Count)
+		}
+		fmt.Fprintf(b, "- %s: %d\n", i18n.T("summary.pages_with_redirects"), r.Summary.PagesWithRedirects)
+		fmt.Fprintf(b, "- %s: %d\n", i18n.T("summary.internal_links"), r.Summary.TotalInternalLinks)
+		fmt.Fprintf(b, "- %s: %d\n", i18n.T("summary.external_links"), r.Summary.TotalExternalLinks)
+		if len(r.Summary.SlowestPages) > 0 {
+			fmt.Fprintf(b, "\n%s:\n\n", i18n.T("summary.slowest_pages"))
+			for _, page := range r.Summary.SlowestPages {
+				fmt.Fprintf(b, "- %s — %d ms\n", page.URL, page.ResponseTime)
+			}
+		}
+		fmt.Fprintf(b, "\n")
+	}
+
+	if err := b.Flush(); err != nil {
+		return fmt.Errorf("failed to write Markdown report: %w", err)
+	}
+	return nil
+}
+
+// mdEscape keeps text from breaking Markdown tables and formatting
+func mdEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "*", `\*`, "_", `\_`, "<", `\<`).Replace(s)
+}
@@ -0,0 +1,176 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// streamFormat is implemented by exporters whose files can be appended to a
+// page at a time and read back the same way
+type streamFormat interface {
+	// header returns what a file starts with, or nil for nothing
+	header() ([]byte, error)
+	// encode renders one page
+	encode(result *models.PageResult) ([]byte, error)
+	// decode passes each page read from r to fn
+	decode(r io.Reader, fn func(result *models.PageResult) error) error
+}
+
+// StreamFormats lists the registered formats that can be streamed, in
+// alphabetical order
+func StreamFormats() []string {
+	var formats []string
+	for _, format := range Formats() {
+		if e, _ := Lookup(format); e != nil {
+			if _, ok := e.(streamFormat); ok {
+				formats = append(formats, format)
+			}
+		}
+	}
+	return formats
+}
+
+// Stream writes results a page at a time as a crawl runs, so they needn't be
+// held in memory. It implements crawler.ResultSink. Each page is written
+// straight to the file, so it is complete up to the last page whenever the
+// crawl stops.
+type Stream struct {
+	mu     sync.Mutex
+	format streamFormat
+	path   string
+	file   *os.File // nil when streaming to stdout
+	size   int64    // Bytes written, including those of earlier runs
+}
+
+// OpenStream streams results in format to path, or to stdout when path is
+// StdoutPath. With resume set, an existing file is kept for Resume to
+// continue; otherwise it is replaced.
+func OpenStream(format, path string, resume bool) (*Stream, error) {
+	e, err := Lookup(format)
+	if err != nil {
+		return nil, err
+	}
+	sf, ok := e.(streamFormat)
+	if !ok {
+		return nil, fmt.Errorf("export format %q can't be streamed (valid: %s)", format, strings.Join(StreamFormats(), ", "))
+	}
+
+	s := &Stream{format: sf, path: path}
+	if path == StdoutPath {
+		return s, nil
+	}
+	flags := os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	if resume {
+		flags = os.O_RDWR | os.O_CREATE
+	}
+	s.file, err = os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create results file: %w", err)
+	}
+	if s.size, err = s.file.Seek(0, io.SeekEnd); err != nil {
+		s.file.Close()
+		return nil, fmt.Errorf("failed to open results file: %w", err)
+	}
+	return s, nil
+}
+
+// Write appends a page, after the format's header when the file is empty
+func (s *Stream) Write(result *models.PageResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data []byte
+	if s.size == 0 {
+		header, err := s.format.header()
+		if err != nil {
+			return err
+		}
+		data = header
+	}
+	page, err := s.format.encode(result)
+	if err != nil {
+		return err
+	}
+	data = append(data, page...)
+
+	out := io.Writer(os.Stdout)
+	if s.file != nil {
+		out = s.file
+	}
+	n, err := out.Write(data)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write results file: %w", err)
+	}
+	return nil
+}
+
+// Path returns the file results are streamed to
+func (s *Stream) Path() string {
+	return s.path
+}
+
+// Size returns the number of bytes written so far
+func (s *Stream) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.size
+}
+
+// Resume cuts the file back to its first size bytes, dropping pages written
+// after the checkpoint a resumed crawl continues from, and passes each page
+// left to fn
+func (s *Stream) Resume(size int64, fn func(result *models.PageResult)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return errors.New("results streamed to stdout can't be resumed")
+	}
+	if s.size < size {
+		return fmt.Errorf("results file %s has %d bytes; the saved crawl wrote %d", s.path, s.size, size)
+	}
+	if err := s.file.Truncate(size); err != nil {
+		return fmt.Errorf("failed to truncate results file: %w", err)
+	}
+	if _, err := s.file.Seek(size, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to truncate results file: %w", err)
+	}
+	s.size = size
+	return s.format.decode(io.NewSectionReader(s.file, 0, size), func(result *models.PageResult) error {
+		fn(result)
+		return nil
+	})
+}
+
+// ReadAll reads back every page written, for steps that need all the
+// results at once
+func (s *Stream) ReadAll() ([]*models.PageResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil, errors.New("results streamed to stdout can't be read back")
+	}
+	var results []*models.PageResult
+	err := s.format.decode(io.NewSectionReader(s.file, 0, s.size), func(result *models.PageResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Close closes the file
+func (s *Stream) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Close()
+}
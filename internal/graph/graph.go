@@ -1,19 +1,22 @@
 package graph
 
 import (
+	"sort"
 	"sync"
 )
 
 // Graph represents a link graph with source -> target edges
 type Graph struct {
-	edges map[string][]string
-	mu    sync.RWMutex
+	edges    map[string][]string
+	nofollow map[string]map[string]bool // Edges only linked with rel=nofollow or from a meta nofollow page
+	mu       sync.RWMutex
 }
 
 // NewGraph creates a new Graph instance
 func NewGraph() *Graph {
 	return &Graph{
-		edges: make(map[string][]string),
+		edges:    make(map[string][]string),
+		nofollow: make(map[string]map[string]bool),
 	}
 }
 
@@ -51,6 +54,48 @@ func (g *Graph) AddEdges(source string, targets []string) {
 	}
 }
 
+// MarkNofollow adds edges from a source to targets and flags them as
+// nofollow links
+func (g *Graph) MarkNofollow(source string, targets []string) {
+	if len(targets) == 0 {
+		return
+	}
+	g.AddEdges(source, targets)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.nofollow[source] == nil {
+		g.nofollow[source] = make(map[string]bool)
+	}
+	for _, target := range targets {
+		g.nofollow[source][target] = true
+	}
+}
+
+// IsNofollow reports whether the edge from source to target is flagged as
+// nofollow
+func (g *Graph) IsNofollow(source, target string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.nofollow[source][target]
+}
+
+// GetNofollowEdges returns the edges flagged as nofollow, with each source's
+// targets sorted
+func (g *Graph) GetNofollowEdges() map[string][]string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	result := make(map[string][]string, len(g.nofollow))
+	for source, targets := range g.nofollow {
+		for target := range targets {
+			result[source] = append(result[source], target)
+		}
+		sort.Strings(result[source])
+	}
+	return result
+}
+
 // GetEdges returns all edges from a source node
 func (g *Graph) GetEdges(source string) []string {
 	g.mu.RLock()
@@ -71,14 +116,21 @@ func (g *Graph) GetAllEdges() map[string][]string {
 	return result
 }
 
-// GetEdgeList returns a flat list of edges as [source, target] pairs
+// GetEdgeList returns a flat list of edges as [source, target] pairs,
+// ordered by source URL
 func (g *Graph) GetEdgeList() [][]string {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
+	sources := make([]string, 0, len(g.edges))
+	for source := range g.edges {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
 	edgeList := make([][]string, 0)
-	for source, targets := range g.edges {
-		for _, target := range targets {
+	for _, source := range sources {
+		for _, target := range g.edges[source] {
 			edgeList = append(edgeList, []string{source, target})
 		}
 	}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchmarkLinks returns the internal links of a site of n pages: each links
+// to the same 40 navigation pages and 40 others, a few of them twice, as a
+// crawl of a typical CMS site finds them
+func benchmarkLinks(n int) (sources []string, targets [][]string) {
+	url := func(i int) string {
+		return fmt.Sprintf("https://www.example.com/guides/%d/", i)
+	}
+	for i := 0; i < n; i++ {
+		links := make([]string, 0, 85)
+		for j := 0; j < 40; j++ {
+			links = append(links, url(j%n))
+		}
+		for j := 1; j <= 40; j++ {
+			links = append(links, url((i*31+j*17)%n))
+		}
+		links = append(links, links[40:45]...)
+		sources = append(sources, url(i))
+		targets = append(targets, links)
+	}
+	return sources, targets
+}
+
+func BenchmarkAddEdges(b *testing.B) {
+	sources, targets := benchmarkLinks(5000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g := NewGraph()
+		for k, source := range sources {
+			g.AddEdges(source, targets[k])
+		}
+	}
+}
+
+func BenchmarkGetEdgeList(b *testing.B) {
+	sources, targets := benchmarkLinks(5000)
+	g := NewGraph()
+	for k, source := range sources {
+		g.AddEdges(source, targets[k])
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.GetEdgeList()
+	}
+}
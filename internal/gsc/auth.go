@@ -77,6 +77,13 @@ func InitializeOAuth(redirectURL string) error {
 	return nil
 }
 
+// AuthURLResponse is the authorization URL a client opens to connect
+// Search Console, and the state it is bound to
+type AuthURLResponse struct {
+	AuthURL string `json:"auth_url"`
+	State   string `json:"state"`
+}
+
 // GenerateAuthURL creates an OAuth2 authorization URL and binds it to a project
 func GenerateAuthURL(projectID string) (string, string, error) {
 	if oauthConfig == nil {
@@ -3,24 +3,45 @@ package gsc
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/searchconsole/v1"
 	
 	"github.com/dillonlara115/barracuda/internal/analyzer"
 	"github.com/dillonlara115/barracuda/pkg/models"
 )
 
-// EnrichedIssue extends analyzer.Issue with GSC performance data
+// EnrichedIssue extends analyzer.Issue with GSC performance data and, when a
+// backlink provider is configured, the page's referring-domain count
 type EnrichedIssue struct {
 	Issue              analyzer.Issue           `json:"issue"`
 	GSCPerformance     *models.GSCPerformance  `json:"gsc_performance,omitempty"`
+	ReferringDomains   *int                    `json:"referring_domains,omitempty"`
 	EnrichedPriority   float64                 `json:"enriched_priority"`
 	RecommendationReason string                `json:"recommendation_reason"`
 }
 
-// FetchPerformanceData fetches Search Analytics data for a property
-func FetchPerformanceData(userID string, siteURL string, startDate, endDate time.Time) (map[string]*models.GSCPerformance, error) {
+// queryConcurrency limits parallel top-query lookups
+const queryConcurrency = 4
+
+// queryInterval spaces out the top-query lookups, keeping them under Search
+// Analytics' quota of 1,200 queries per minute per property
+const queryInterval = 60 * time.Millisecond
+
+// PerformanceRequest asks for a property's performance over the last Days
+// days (30 when zero)
+type PerformanceRequest struct {
+	UserID  string `json:"user_id"`
+	SiteURL string `json:"site_url"`
+	Days    int    `json:"days"`
+}
+
+// FetchPerformanceData fetches Search Analytics data for a property, with
+// the top queries of its pages. progress, when not nil, is called as each
+// page's top queries are fetched.
+func FetchPerformanceData(userID string, siteURL string, startDate, endDate time.Time, progress func(done, total int)) (map[string]*models.GSCPerformance, error) {
 	service, err := GetService(userID)
 	if err != nil {
 		return nil, err
@@ -59,7 +80,7 @@ func FetchPerformanceData(userID string, siteURL string, startDate, endDate time
 	}
 
 	// Fetch query data for top pages
-	if err := fetchQueryData(userID, siteURL, startDate, endDate, performanceMap); err != nil {
+	if err := fetchQueryData(userID, siteURL, startDate, endDate, performanceMap, progress); err != nil {
 		// Log error but don't fail - query data is optional
 		fmt.Printf("Warning: Failed to fetch query data: %v\n", err)
 	}
@@ -67,57 +88,84 @@ func FetchPerformanceData(userID string, siteURL string, startDate, endDate time
 	return performanceMap, nil
 }
 
-// fetchQueryData fetches top queries for pages
-func fetchQueryData(userID string, siteURL string, startDate, endDate time.Time, performanceMap map[string]*models.GSCPerformance) error {
+// fetchQueryData fetches top queries for pages, several at a time
+func fetchQueryData(userID string, siteURL string, startDate, endDate time.Time, performanceMap map[string]*models.GSCPerformance, progress func(done, total int)) error {
 	service, err := GetService(userID)
 	if err != nil {
 		return err
 	}
 
-	// For each page with significant traffic, fetch top queries
-	// Note: This is a simplified version - in production, you might want to batch this
-	for url, perf := range performanceMap {
-		if perf.Impressions < 100 {
-			continue // Skip low-traffic pages
+	// Only pages with significant traffic have queries worth showing
+	var pages []*models.GSCPerformance
+	for _, perf := range performanceMap {
+		if perf.Impressions >= 100 {
+			pages = append(pages, perf)
 		}
+	}
+	if progress != nil {
+		progress(0, len(pages))
+	}
+
+	throttle := time.NewTicker(queryInterval)
+	defer throttle.Stop()
+	var done atomic.Int64
+	var lookups errgroup.Group
+	lookups.SetLimit(queryConcurrency)
+	for _, perf := range pages {
+		perf := perf
+		<-throttle.C
+		lookups.Go(func() error {
+			// A page whose lookup fails just goes without top queries
+			if queries, err := fetchTopQueries(service, siteURL, startDate, endDate, perf.URL); err == nil {
+				perf.TopQueries = queries
+			}
+			if progress != nil {
+				progress(int(done.Add(1)), len(pages))
+			}
+			return nil
+		})
+	}
+	lookups.Wait()
+
+	return nil
+}
 
-		request := &searchconsole.SearchAnalyticsQueryRequest{
-			StartDate:  startDate.Format("2006-01-02"),
-			EndDate:    endDate.Format("2006-01-02"),
-			Dimensions: []string{"query"},
-			DimensionFilterGroups: []*searchconsole.ApiDimensionFilterGroup{
-				{
-					Filters: []*searchconsole.ApiDimensionFilter{
-						{
-							Dimension:  "page",
-							Expression:  url,
-							Operator:   "equals",
-						},
+// fetchTopQueries fetches a page's top 10 queries
+func fetchTopQueries(service *searchconsole.Service, siteURL string, startDate, endDate time.Time, url string) ([]models.Query, error) {
+	request := &searchconsole.SearchAnalyticsQueryRequest{
+		StartDate:  startDate.Format("2006-01-02"),
+		EndDate:    endDate.Format("2006-01-02"),
+		Dimensions: []string{"query"},
+		DimensionFilterGroups: []*searchconsole.ApiDimensionFilterGroup{
+			{
+				Filters: []*searchconsole.ApiDimensionFilter{
+					{
+						Dimension:  "page",
+						Expression:  url,
+						Operator:   "equals",
 					},
 				},
 			},
-			RowLimit: 10, // Top 10 queries
-		}
-
-		response, err := service.Searchanalytics.Query(siteURL, request).Do()
-		if err != nil {
-			continue // Skip if query fails
-		}
+		},
+		RowLimit: 10, // Top 10 queries
+	}
 
-		queries := make([]models.Query, 0, len(response.Rows))
-		for _, row := range response.Rows {
-			queries = append(queries, models.Query{
-				Query:       row.Keys[0],
-				Impressions: int64(row.Impressions),
-				Clicks:      int64(row.Clicks),
-				CTR:         row.Ctr,
-				Position:    row.Position,
-			})
-		}
-		perf.TopQueries = queries
+	response, err := service.Searchanalytics.Query(siteURL, request).Do()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	queries := make([]models.Query, 0, len(response.Rows))
+	for _, row := range response.Rows {
+		queries = append(queries, models.Query{
+			Query:       row.Keys[0],
+			Impressions: int64(row.Impressions),
+			Clicks:      int64(row.Clicks),
+			CTR:         row.Ctr,
+			Position:    row.Position,
+		})
+	}
+	return queries, nil
 }
 
 // normalizeURL normalizes URLs to match crawl results
@@ -129,8 +177,9 @@ func normalizeURL(url string) string {
 	return url
 }
 
-// EnrichIssues merges GSC performance data with issues
-func EnrichIssues(issues []analyzer.Issue, performanceMap map[string]*models.GSCPerformance) []EnrichedIssue {
+// EnrichIssues merges GSC performance data and referring-domain counts (keyed
+// by page URL; may be nil) with issues
+func EnrichIssues(issues []analyzer.Issue, performanceMap map[string]*models.GSCPerformance, referringDomains map[string]int) []EnrichedIssue {
 	enriched := make([]EnrichedIssue, 0, len(issues))
 
 	for _, issue := range issues {
@@ -151,6 +200,14 @@ func EnrichIssues(issues []analyzer.Issue, performanceMap map[string]*models.GSC
 			enrichedIssue.EnrichedPriority = float64(getSeverityWeight(issue.Severity))
 		}
 
+		if count, exists := referringDomains[issue.URL]; exists {
+			enrichedIssue.ReferringDomains = &count
+			enrichedIssue.EnrichedPriority *= backlinkMultiplier(count)
+			if reason := backlinkReason(count); reason != "" {
+				enrichedIssue.RecommendationReason = strings.TrimSpace(enrichedIssue.RecommendationReason + " " + reason)
+			}
+		}
+
 		enriched = append(enriched, enrichedIssue)
 	}
 
@@ -186,6 +243,29 @@ func calculateEnrichedPriority(issue analyzer.Issue, perf *models.GSCPerformance
 	return basePriority * trafficMultiplier * ctrMultiplier * positionMultiplier
 }
 
+// backlinkMultiplier raises the priority of pages other sites link to, since
+// their authority is at stake when they have problems
+func backlinkMultiplier(referringDomains int) float64 {
+	if referringDomains > 100 {
+		return 2.0 // Strongly linked page = 2x priority
+	} else if referringDomains > 10 {
+		return 1.5
+	} else if referringDomains > 0 {
+		return 1.2
+	}
+	return 1.0
+}
+
+// backlinkReason explains the backlink part of the priority
+func backlinkReason(referringDomains int) string {
+	if referringDomains > 100 {
+		return fmt.Sprintf("%d domains link to this page, so fixing it protects valuable link equity.", referringDomains)
+	} else if referringDomains > 10 {
+		return fmt.Sprintf("%d domains link to this page.", referringDomains)
+	}
+	return ""
+}
+
 // generateRecommendationReason creates contextual recommendation based on GSC data
 func generateRecommendationReason(issue analyzer.Issue, perf *models.GSCPerformance) string {
 	if perf.Impressions > 10000 {
@@ -202,13 +282,13 @@ func generateRecommendationReason(issue analyzer.Issue, perf *models.GSCPerforma
 }
 
 // getSeverityWeight returns weight for severity level
-func getSeverityWeight(severity string) int {
+func getSeverityWeight(severity models.Severity) int {
 	switch severity {
-	case "error":
+	case models.SeverityError:
 		return 10
-	case "warning":
+	case models.SeverityWarning:
 		return 5
-	case "info":
+	case models.SeverityInfo:
 		return 1
 	default:
 		return 1
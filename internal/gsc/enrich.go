@@ -0,0 +1,155 @@
+package gsc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// Enrichment job statuses
+const (
+	EnrichRunning = "running"
+	EnrichDone    = "done"
+	EnrichFailed  = "failed"
+)
+
+// EnrichJob is a background enrichment of a crawl's issues with Search
+// Console data. Done and Total count the pages whose top queries have been
+// fetched.
+type EnrichJob struct {
+	SiteURL    string          `json:"site_url"`
+	Days       int             `json:"days"`
+	Status     string          `json:"status"`
+	Done       int             `json:"done"`
+	Total      int             `json:"total"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	Issues     []EnrichedIssue `json:"issues"`
+}
+
+// Enricher enriches one crawl's issues in the background, one job at a time.
+// A finished job is saved to a file, when given one, and reused until it is
+// refreshed or asked for another property or period.
+type Enricher struct {
+	issues           []analyzer.Issue
+	referringDomains map[string]int
+	path             string
+
+	mu  sync.Mutex
+	job *EnrichJob
+}
+
+// NewEnricher creates an Enricher for issues, weighing in referringDomains
+// (keyed by page URL; may be nil). path is where finished jobs are saved, or
+// "" to keep them in memory; a job saved there earlier is loaded.
+func NewEnricher(issues []analyzer.Issue, referringDomains map[string]int, path string) *Enricher {
+	e := &Enricher{issues: issues, referringDomains: referringDomains, path: path}
+	if path == "" {
+		return e
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return e
+	}
+	var job EnrichJob
+	if err := json.Unmarshal(data, &job); err != nil || job.Status != EnrichDone {
+		utils.Warn("Ignoring unreadable enriched issues", utils.NewField("path", path))
+		return e
+	}
+	e.job = &job
+	return e
+}
+
+// Start starts enriching the issues with req's property and period, unless
+// a job is already running or a finished one for the same request can be
+// reused. It returns the job's current state.
+func (e *Enricher) Start(req PerformanceRequest, refresh bool) EnrichJob {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.job != nil {
+		running := e.job.Status == EnrichRunning
+		reusable := e.job.Status == EnrichDone && !refresh && e.job.SiteURL == req.SiteURL && e.job.Days == req.Days
+		if running || reusable {
+			return *e.job
+		}
+	}
+
+	job := &EnrichJob{
+		SiteURL:   req.SiteURL,
+		Days:      req.Days,
+		Status:    EnrichRunning,
+		StartedAt: time.Now(),
+	}
+	e.job = job
+	go e.run(job, req)
+	return *job
+}
+
+// Job returns the state of the latest job, or false when none has run
+func (e *Enricher) Job() (EnrichJob, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.job == nil {
+		return EnrichJob{}, false
+	}
+	return *e.job, true
+}
+
+// run fetches the performance data and enriches the issues with it
+func (e *Enricher) run(job *EnrichJob, req PerformanceRequest) {
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -req.Days)
+	performanceMap, err := FetchPerformanceData(req.UserID, req.SiteURL, startDate, endDate, func(done, total int) {
+		e.mu.Lock()
+		job.Done, job.Total = done, total
+		e.mu.Unlock()
+	})
+
+	var issues []EnrichedIssue
+	if err == nil {
+		issues = EnrichIssues(e.issues, performanceMap, e.referringDomains)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	finished := time.Now()
+	job.FinishedAt = &finished
+	if err != nil {
+		job.Status = EnrichFailed
+		job.Error = fmt.Sprintf("Failed to fetch performance data: %v", err)
+		utils.Warn("Issue enrichment failed", utils.NewField("site_url", req.SiteURL), utils.NewField("error", err.Error()))
+		return
+	}
+	job.Status = EnrichDone
+	job.Issues = issues
+	utils.Info("Enriched issues with Search Console data",
+		utils.NewField("site_url", req.SiteURL),
+		utils.NewField("issues", len(issues)),
+		utils.NewField("duration", finished.Sub(job.StartedAt).String()))
+
+	if e.path == "" {
+		return
+	}
+	if err := saveEnrichJob(e.path, job); err != nil {
+		utils.Warn("Could not save enriched issues", utils.NewField("path", e.path), utils.NewField("error", err.Error()))
+	}
+}
+
+// saveEnrichJob writes a finished job to path
+func saveEnrichJob(path string, job *EnrichJob) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode enriched issues: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write enriched issues: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used when no locale is selected and as the fallback for
+// messages missing from another catalog
+const DefaultLocale = "en"
+
+// EnvVar selects the locale when --lang is not given
+const EnvVar = "BARRACUDA_LANG"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var (
+	loadOnce sync.Once
+	catalogs map[string]map[string]string
+
+	mu      sync.RWMutex
+	current = DefaultLocale
+)
+
+// load reads every embedded catalog once
+func load() map[string]map[string]string {
+	loadOnce.Do(func() {
+		catalogs = make(map[string]map[string]string)
+		entries, _ := localeFiles.ReadDir("locales")
+		for _, entry := range entries {
+			data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+			if err != nil {
+				continue
+			}
+			var messages map[string]string
+			if err := json.Unmarshal(data, &messages); err != nil {
+				panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+			}
+			catalogs[strings.TrimSuffix(entry.Name(), ".json")] = messages
+		}
+	})
+	return catalogs
+}
+
+// Available returns the supported locale codes, sorted
+func Available() []string {
+	var locales []string
+	for locale := range load() {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Normalize reduces a locale such as "pt_BR.UTF-8" or "de-AT" to its language code
+func Normalize(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "_-.@"); i >= 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
+
+// SetLocale selects the catalog used by T. An empty locale is resolved from
+// BARRACUDA_LANG and then the system LC_ALL, LC_MESSAGES, and LANG variables,
+// falling back to English when none of them names a supported language.
+func SetLocale(locale string) error {
+	available := load()
+
+	if locale != "" {
+		code := Normalize(locale)
+		if _, ok := available[code]; !ok {
+			return fmt.Errorf("unsupported language %q (available: %s)", locale, strings.Join(Available(), ", "))
+		}
+		mu.Lock()
+		current = code
+		mu.Unlock()
+		return nil
+	}
+
+	code := DefaultLocale
+	for _, env := range []string{EnvVar, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		candidate := Normalize(os.Getenv(env))
+		if candidate == "" {
+			continue
+		}
+		if _, ok := available[candidate]; ok {
+			code = candidate
+		}
+		break
+	}
+
+	mu.Lock()
+	current = code
+	mu.Unlock()
+	return nil
+}
+
+// Locale returns the selected locale code
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// T returns the message for key in the selected locale, formatted with args.
+// Missing messages fall back to English and then to the key itself.
+func T(key string, args ...interface{}) string {
+	messages := load()
+	msg, ok := messages[Locale()][key]
+	if !ok {
+		msg, ok = messages[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
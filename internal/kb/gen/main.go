@@ -0,0 +1,48 @@
+// Command gen writes the dashboard's copy of the knowledge base. With -check
+// it writes nothing and fails when the file on disk is stale.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/internal/kb"
+)
+
+func main() {
+	outPath := flag.String("out", "web/src/lib/knowledge.gen.json", "JSON file to write")
+	check := flag.Bool("check", false, "Fail if the file is out of date instead of writing it")
+	flag.Parse()
+
+	// Keyed by article key, which is what issues carry as doc_key
+	articles := make(map[string]*kb.Article)
+	for _, article := range kb.All(i18n.DefaultLocale) {
+		articles[article.Key] = article
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(articles); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode knowledge base: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *check {
+		current, err := os.ReadFile(*outPath)
+		if err != nil || !bytes.Equal(current, buf.Bytes()) {
+			fmt.Fprintf(os.Stderr, "%s is out of date; run go generate ./internal/kb\n", *outPath)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := os.WriteFile(*outPath, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", *outPath, err)
+		os.Exit(1)
+	}
+}
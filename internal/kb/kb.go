@@ -0,0 +1,121 @@
+// Package kb is the built-in knowledge base of issue types: what each issue
+// means, why it matters, and the steps to fix it. Issues refer to an article
+// by their DocKey. The dashboard gets a copy of the English articles from
+// go generate, and HTML and Markdown reports render them in place of the
+// one-line recommendations.
+package kb
+
+//go:generate go run ./gen -out ../../web/src/lib/knowledge.gen.json
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dillonlara115/barracuda/internal/i18n"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// Impact is how much fixing an issue matters
+type Impact string
+
+const (
+	ImpactCritical Impact = "critical"
+	ImpactHigh     Impact = "high"
+	ImpactMedium   Impact = "medium"
+	ImpactLow      Impact = "low"
+)
+
+// Link points to further reading
+type Link struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Article explains one kind of issue
+type Article struct {
+	Key         string   `json:"key"`
+	Title       string   `json:"title"` // What to do, e.g. "Add a Page Title"
+	Impact      Impact   `json:"impact"`
+	Description string   `json:"description"`       // What the issue is and why it matters
+	Steps       []string `json:"steps"`             // How to fix it, in order
+	Example     string   `json:"example,omitempty"` // Code or config showing the fix
+	Links       []Link   `json:"links,omitempty"`
+}
+
+// Articles are stored one file per locale. A locale's file may leave
+// articles out; English is used for those.
+//
+//go:embed articles/*.json
+var articleFiles embed.FS
+
+var (
+	loadOnce sync.Once
+	catalogs map[string]map[string]*Article
+)
+
+// load reads every embedded locale once
+func load() map[string]map[string]*Article {
+	loadOnce.Do(func() {
+		catalogs = make(map[string]map[string]*Article)
+		entries, _ := articleFiles.ReadDir("articles")
+		for _, entry := range entries {
+			data, err := articleFiles.ReadFile(path.Join("articles", entry.Name()))
+			if err != nil {
+				continue
+			}
+			var articles map[string]*Article
+			if err := json.Unmarshal(data, &articles); err != nil {
+				panic(fmt.Sprintf("kb: invalid articles %s: %v", entry.Name(), err))
+			}
+			for key, article := range articles {
+				article.Key = key
+			}
+			catalogs[strings.TrimSuffix(entry.Name(), ".json")] = articles
+		}
+	})
+	return catalogs
+}
+
+// Lookup returns the article with the given key in the current locale
+func Lookup(key string) (*Article, bool) {
+	return LookupLocale(i18n.Locale(), key)
+}
+
+// LookupLocale returns the article with the given key in locale, falling
+// back to English
+func LookupLocale(locale, key string) (*Article, bool) {
+	catalogs := load()
+	if article, ok := catalogs[locale][key]; ok {
+		return article, true
+	}
+	article, ok := catalogs[i18n.DefaultLocale][key]
+	return article, ok
+}
+
+// All returns every article in locale, sorted by key
+func All(locale string) []*Article {
+	english := load()[i18n.DefaultLocale]
+	articles := make([]*Article, 0, len(english))
+	for key := range english {
+		article, _ := LookupLocale(locale, key)
+		articles = append(articles, article)
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		return articles[i].Key < articles[j].Key
+	})
+	return articles
+}
+
+// KeyFor returns the key of the article explaining an issue type, or ""
+// when the knowledge base has none
+func KeyFor(issueType models.IssueType) string {
+	if _, ok := load()[i18n.DefaultLocale][string(issueType)]; ok {
+		return string(issueType)
+	}
+	return ""
+}
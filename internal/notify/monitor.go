@@ -0,0 +1,59 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// URLCheck is the outcome of checking one monitored URL
+type URLCheck struct {
+	URL          string   `json:"url"`
+	StatusCode   int      `json:"status_code"`
+	ResponseTime int64    `json:"response_time_ms"`
+	HasTitle     bool     `json:"has_title"`
+	Error        string   `json:"error,omitempty"`
+	Changes      []string `json:"changes"`
+}
+
+// MonitorAlert is the payload sent when monitored URLs change between checks
+type MonitorAlert struct {
+	Event     string     `json:"event"` // always "monitor_alert"
+	CheckedAt time.Time  `json:"checked_at"`
+	Changed   []URLCheck `json:"changed"`
+}
+
+// NewMonitorAlert creates an alert for the URLs that changed in one check
+func NewMonitorAlert(changed []URLCheck) *MonitorAlert {
+	return &MonitorAlert{
+		Event:     "monitor_alert",
+		CheckedAt: time.Now(),
+		Changed:   changed,
+	}
+}
+
+// Subject returns a one-line summary suitable for an email subject
+func (a *MonitorAlert) Subject() string {
+	if len(a.Changed) == 1 {
+		return fmt.Sprintf("[barracuda] Monitor: %s changed", a.Changed[0].URL)
+	}
+	return fmt.Sprintf("[barracuda] Monitor: %d URLs changed", len(a.Changed))
+}
+
+// Body returns a plain-text description of the alert
+func (a *MonitorAlert) Body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Checked: %s\n", a.CheckedAt.Format(time.RFC3339))
+	for _, check := range a.Changed {
+		fmt.Fprintf(&b, "\n%s\n", check.URL)
+		if check.Error != "" {
+			fmt.Fprintf(&b, "  Error: %s\n", check.Error)
+		} else {
+			fmt.Fprintf(&b, "  Status: %d (%d ms)\n", check.StatusCode, check.ResponseTime)
+		}
+		for _, change := range check.Changes {
+			fmt.Fprintf(&b, "  - %s\n", change)
+		}
+	}
+	return b.String()
+}
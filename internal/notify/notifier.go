@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+)
+
+// discordMaxContent is the longest message Discord accepts
+const discordMaxContent = 2000
+
+// Notifier delivers messages to one channel
+type Notifier interface {
+	// Name identifies the channel in error messages
+	Name() string
+	Notify(msg Message) error
+}
+
+// Notifiers returns a Notifier for every channel configured in cfg
+func Notifiers(cfg utils.NotificationFileConfig) []Notifier {
+	var notifiers []Notifier
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, &Webhook{URL: cfg.WebhookURL})
+	}
+	if cfg.SlackWebhookURL != "" {
+		notifiers = append(notifiers, &Slack{WebhookURL: cfg.SlackWebhookURL})
+	}
+	if cfg.DiscordWebhookURL != "" {
+		notifiers = append(notifiers, &Discord{WebhookURL: cfg.DiscordWebhookURL})
+	}
+	if cfg.Email.Enabled() {
+		notifiers = append(notifiers, &Email{Config: cfg.Email})
+	}
+	if cfg.Desktop {
+		notifiers = append(notifiers, Desktop{})
+	}
+	return notifiers
+}
+
+// Webhook POSTs messages as JSON to a URL
+type Webhook struct {
+	URL string
+}
+
+// Name returns "webhook"
+func (w *Webhook) Name() string { return "webhook" }
+
+// Notify sends msg to the webhook
+func (w *Webhook) Notify(msg Message) error {
+	return SendWebhook(w.URL, msg)
+}
+
+// Slack posts messages to a Slack incoming webhook
+type Slack struct {
+	WebhookURL string
+}
+
+// Name returns "slack"
+func (s *Slack) Name() string { return "slack" }
+
+// Notify posts msg's subject in bold above its body
+func (s *Slack) Notify(msg Message) error {
+	text := fmt.Sprintf("*%s*\n```%s```", msg.Subject(), strings.TrimSpace(msg.Body()))
+	return postJSON(s.WebhookURL, map[string]string{"text": text})
+}
+
+// Discord posts messages to a Discord channel webhook
+type Discord struct {
+	WebhookURL string
+}
+
+// Name returns "discord"
+func (d *Discord) Name() string { return "discord" }
+
+// Notify posts msg's subject in bold above its body, shortened to fit
+// Discord's message limit
+func (d *Discord) Notify(msg Message) error {
+	subject := fmt.Sprintf("**%s**\n", msg.Subject())
+	body := strings.TrimSpace(msg.Body())
+	if room := discordMaxContent - len(subject) - len("```\n…```"); len(body) > room {
+		body = strings.ToValidUTF8(body[:room], "") + "\n…"
+	}
+	return postJSON(d.WebhookURL, map[string]string{"content": subject + "```" + body + "```"})
+}
+
+// Email sends messages over SMTP
+type Email struct {
+	Config utils.EmailFileConfig
+}
+
+// Name returns "email"
+func (e *Email) Name() string { return "email" }
+
+// Notify emails msg
+func (e *Email) Notify(msg Message) error {
+	return SendEmail(e.Config, msg)
+}
+
+// Desktop shows message subjects as desktop notifications, through
+// notify-send on Linux, osascript on macOS, and PowerShell on Windows
+type Desktop struct{}
+
+// Name returns "desktop"
+func (Desktop) Name() string { return "desktop" }
+
+// Notify shows msg's subject
+func (Desktop) Notify(msg Message) error {
+	const title = "barracuda"
+	text := strings.TrimPrefix(msg.Subject(), "[barracuda] ")
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("notify-send", "--app-name", title, title, text)
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(text), appleScriptString(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, %s, %s, 'Info')
+Start-Sleep -Seconds 5
+$n.Dispose()`, powerShellString(title), powerShellString(text))
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil && len(bytes.TrimSpace(output)) > 0 {
+		return fmt.Errorf("desktop notification failed: %w: %s", err, bytes.TrimSpace(output))
+	} else if err != nil {
+		return fmt.Errorf("desktop notification failed: %w", err)
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// powerShellString quotes s as a PowerShell single-quoted string literal
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// postJSON POSTs payload encoded as JSON to url
+func postJSON(url string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "barracuda/1.0.0")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned HTTP %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// Message is anything that can be sent as a notification. Webhooks receive
+// it encoded as JSON; email uses its subject and body.
+type Message interface {
+	Subject() string
+	Body() string
+}
+
+// CrawlReport is the payload sent when a crawl finishes
+type CrawlReport struct {
+	URL         string                  `json:"url"`
+	CrawlDir    string                  `json:"crawl_dir,omitempty"`
+	Status      string                  `json:"status"` // "succeeded" or "failed"
+	Error       string                  `json:"error,omitempty"`
+	TotalPages  int                     `json:"total_pages"`
+	TotalIssues int                     `json:"total_issues"`
+	BySeverity  map[models.Severity]int `json:"issues_by_severity,omitempty"`
+	StartedAt   time.Time               `json:"started_at"`
+	CompletedAt time.Time               `json:"completed_at"`
+}
+
+// Subject returns a one-line summary suitable for an email subject
+func (r *CrawlReport) Subject() string {
+	if r.Status == "failed" {
+		return fmt.Sprintf("[barracuda] Crawl failed: %s", r.URL)
+	}
+	return fmt.Sprintf("[barracuda] Crawl complete: %s (%d pages, %d issues)", r.URL, r.TotalPages, r.TotalIssues)
+}
+
+// Body returns a plain-text description of the report
+func (r *CrawlReport) Body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "URL: %s\n", r.URL)
+	fmt.Fprintf(&b, "Status: %s\n", r.Status)
+	if r.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", r.Error)
+	}
+	fmt.Fprintf(&b, "Started: %s\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Completed: %s\n", r.CompletedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Pages crawled: %d\n", r.TotalPages)
+	fmt.Fprintf(&b, "Issues found: %d\n", r.TotalIssues)
+	if len(r.BySeverity) > 0 {
+		fmt.Fprintf(&b, "  Errors: %d\n", r.BySeverity[models.SeverityError])
+		fmt.Fprintf(&b, "  Warnings: %d\n", r.BySeverity[models.SeverityWarning])
+		fmt.Fprintf(&b, "  Info: %d\n", r.BySeverity[models.SeverityInfo])
+	}
+	if r.CrawlDir != "" {
+		fmt.Fprintf(&b, "Results: %s\n", r.CrawlDir)
+	}
+	return b.String()
+}
+
+// SendWebhook POSTs the message as JSON to the given URL
+func SendWebhook(webhookURL string, msg Message) error {
+	return postJSON(webhookURL, msg)
+}
+
+// SendEmail delivers the message over SMTP using PLAIN auth when credentials are set
+func SendEmail(cfg utils.EmailFileConfig, report Message) error {
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	addr := fmt.Sprintf("%s:%d", cfg.SMTPHost, port)
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.SMTPHost)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", report.Subject())
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=UTF-8\r\n")
+	fmt.Fprintf(&msg, "\r\n%s", report.Body())
+
+	if err := smtp.SendMail(addr, auth, cfg.From, cfg.To, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// Send dispatches the message to every channel configured in cfg.
+// Failures are collected so one broken channel doesn't block the others.
+func Send(cfg utils.NotificationFileConfig, report Message) error {
+	var errs []string
+
+	for _, notifier := range Notifiers(cfg) {
+		if err := notifier.Notify(report); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", notifier.Name(), err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
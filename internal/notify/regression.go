@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/crawldiff"
+)
+
+// maxAlertIssues limits how many new issues an alert email lists
+const maxAlertIssues = 20
+
+// RegressionAlert is the payload sent when a scheduled crawl regressed
+// against the previous run of the same site
+type RegressionAlert struct {
+	Event         string                 `json:"event"` // always "crawl_regression"
+	URL           string                 `json:"url"`
+	CrawlDir      string                 `json:"crawl_dir"`
+	PreviousCrawl string                 `json:"previous_crawl"`
+	CheckedAt     time.Time              `json:"checked_at"`
+	Regressions   []crawldiff.Regression `json:"regressions"`
+}
+
+// NewRegressionAlert creates an alert for the regressions found comparing the
+// crawl in crawlDir with previousCrawl
+func NewRegressionAlert(url, crawlDir, previousCrawl string, regressions []crawldiff.Regression) *RegressionAlert {
+	return &RegressionAlert{
+		Event:         "crawl_regression",
+		URL:           url,
+		CrawlDir:      crawlDir,
+		PreviousCrawl: previousCrawl,
+		CheckedAt:     time.Now(),
+		Regressions:   regressions,
+	}
+}
+
+// Subject returns a one-line summary suitable for an email subject
+func (a *RegressionAlert) Subject() string {
+	if len(a.Regressions) == 1 {
+		return fmt.Sprintf("[barracuda] Regression on %s: %s", a.URL, a.Regressions[0].Message)
+	}
+	return fmt.Sprintf("[barracuda] %d regressions on %s", len(a.Regressions), a.URL)
+}
+
+// Body returns a plain-text description of the alert
+func (a *RegressionAlert) Body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "URL: %s\n", a.URL)
+	fmt.Fprintf(&b, "Compared with: %s\n", a.PreviousCrawl)
+	if a.CrawlDir != "" {
+		fmt.Fprintf(&b, "Results: %s\n", a.CrawlDir)
+	}
+	for _, regression := range a.Regressions {
+		fmt.Fprintf(&b, "\n%s\n", regression.Message)
+		for i, issue := range regression.Issues {
+			if i == maxAlertIssues {
+				fmt.Fprintf(&b, "  ... and %d more\n", len(regression.Issues)-maxAlertIssues)
+				break
+			}
+			fmt.Fprintf(&b, "  - %s: %s\n", issue.URL, issue.Message)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,149 @@
+package redirectmap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Formats lists the output formats Write accepts
+var Formats = []string{"csv", "nginx", "apache", "cloudflare"}
+
+// ValidFormat reports whether format is one of Formats
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Write writes redirects in one of Formats with the given HTTP status.
+// Unmatched redirects are kept in the CSV, commented out in nginx and Apache
+// configs, and left out of Cloudflare lists.
+func Write(w io.Writer, redirects []Redirect, format string, status int) error {
+	switch format {
+	case "csv":
+		return writeCSV(w, redirects, status)
+	case "nginx":
+		return writeNginx(w, redirects, status)
+	case "apache":
+		return writeApache(w, redirects, status)
+	case "cloudflare":
+		return writeCloudflare(w, redirects, status)
+	default:
+		return fmt.Errorf("unknown format %q (valid: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// writeCSV writes a reviewable spreadsheet of every old URL
+func writeCSV(w io.Writer, redirects []Redirect, status int) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"Old URL", "New URL", "Status", "Match", "Score"}); err != nil {
+		return err
+	}
+	for _, r := range redirects {
+		code := ""
+		if r.Matched() {
+			code = strconv.Itoa(status)
+		}
+		if err := writer.Write([]string{r.From, r.To, code, r.Match, strconv.FormatFloat(r.Score, 'f', 2, 64)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeNginx writes exact-match location blocks for the old site's server
+// block. Old URLs with a query string can't be matched by location and are
+// commented out.
+func writeNginx(w io.Writer, redirects []Redirect, status int) error {
+	for _, r := range redirects {
+		from, query := sourcePath(r.From)
+		switch {
+		case !r.Matched():
+			fmt.Fprintf(w, "# No match: %s\n", from)
+		case query:
+			fmt.Fprintf(w, "# Query strings need a map or if block: %s -> %s\n", from, r.To)
+		default:
+			fmt.Fprintf(w, "location = %s { return %d %s; }\n", nginxQuote(from), status, r.To)
+		}
+	}
+	return nil
+}
+
+// nginxQuote quotes a location path containing characters nginx would
+// otherwise parse
+func nginxQuote(s string) string {
+	if strings.ContainsAny(s, " \t;{}\"'") {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+	return s
+}
+
+// writeApache writes mod_alias RedirectMatch rules anchored on the whole
+// path, since plain Redirect matches prefixes
+func writeApache(w io.Writer, redirects []Redirect, status int) error {
+	for _, r := range redirects {
+		from, query := sourcePath(r.From)
+		switch {
+		case !r.Matched():
+			fmt.Fprintf(w, "# No match: %s\n", from)
+		case query:
+			fmt.Fprintf(w, "# Query strings need mod_rewrite: %s -> %s\n", from, r.To)
+		default:
+			fmt.Fprintf(w, "RedirectMatch %d ^%s$ %s\n", status, regexp.QuoteMeta(from), apacheTarget(r.To))
+		}
+	}
+	return nil
+}
+
+// apacheTarget escapes the characters RedirectMatch treats specially in the
+// target URL
+func apacheTarget(s string) string {
+	s = strings.ReplaceAll(s, "$", `\$`)
+	if strings.ContainsAny(s, " \t") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// writeCloudflare writes a Bulk Redirects list CSV: source without scheme,
+// target, and status, with no header row
+func writeCloudflare(w io.Writer, redirects []Redirect, status int) error {
+	writer := csv.NewWriter(w)
+	for _, r := range redirects {
+		if !r.Matched() {
+			continue
+		}
+		source := strings.TrimPrefix(strings.TrimPrefix(r.From, "https://"), "http://")
+		if err := writer.Write([]string{source, r.To, strconv.Itoa(status)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// sourcePath returns the path of an old URL as the old server sees it, and
+// whether it has a query string
+func sourcePath(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL, false
+	}
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	if u.RawQuery != "" {
+		return p + "?" + u.RawQuery, true
+	}
+	return p, false
+}
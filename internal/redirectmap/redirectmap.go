@@ -0,0 +1,325 @@
+// Package redirectmap builds redirect maps for site migrations by matching
+// the pages of an old crawl to the most similar pages of a new one.
+package redirectmap
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// DefaultMinScore is the lowest similarity accepted as a match
+const DefaultMinScore = 0.5
+
+// How a redirect's target was chosen
+const (
+	MatchMapping = "mapping" // From the mapping file
+	MatchPath    = "path"    // Same path on the new site
+	MatchContent = "content" // Identical response body
+	MatchSlug    = "slug"    // Same last path segment
+	MatchSimilar = "similar" // Best slug, title, and content similarity
+	MatchNone    = "none"    // No new page was similar enough
+)
+
+// Redirect maps an old URL to a new one. To is empty when no match was found.
+type Redirect struct {
+	From  string  `json:"from"`
+	To    string  `json:"to,omitempty"`
+	Match string  `json:"match"`
+	Score float64 `json:"score"`
+}
+
+// Matched reports whether the redirect has a target
+func (r Redirect) Matched() bool {
+	return r.To != ""
+}
+
+// Options controls matching
+type Options struct {
+	// Mapping lists explicit redirects by old URL or path; they take
+	// precedence over matching
+	Mapping map[string]string
+	// MinScore is the lowest similarity accepted as a match (default
+	// DefaultMinScore)
+	MinScore float64
+}
+
+// page is a crawled page with the tokens used for similarity
+type page struct {
+	result  *models.PageResult
+	path    string
+	slug    string
+	pathSet map[string]bool
+	title   map[string]bool
+	content map[string]bool
+}
+
+// Build matches every page of the old crawl that the new crawl no longer
+// serves at the same URL to its most similar new page. Old pages that
+// returned an error are skipped. newResults may be nil when only the mapping
+// is used. Redirects are sorted by old URL.
+func Build(oldResults, newResults []*models.PageResult, opts Options) []Redirect {
+	if opts.MinScore <= 0 {
+		opts.MinScore = DefaultMinScore
+	}
+
+	live := make(map[string]bool)
+	byPath := make(map[string]*page)
+	bySlug := make(map[string][]*page)
+	byHash := make(map[string]*page)
+	index := make(map[string][]*page)
+	for _, result := range newResults {
+		if !servesPage(result) {
+			continue
+		}
+		p := newPage(result)
+		live[result.URL] = true
+		if _, ok := byPath[p.path]; !ok {
+			byPath[p.path] = p
+		}
+		if p.slug != "" {
+			bySlug[p.slug] = append(bySlug[p.slug], p)
+		}
+		if result.ContentHash != "" {
+			if _, ok := byHash[result.ContentHash]; !ok {
+				byHash[result.ContentHash] = p
+			}
+		}
+		for _, token := range p.tokens() {
+			index[token] = append(index[token], p)
+		}
+	}
+
+	var redirects []Redirect
+	seen := make(map[string]bool)
+	usedMapping := make(map[string]bool)
+	for _, result := range oldResults {
+		if !servesPage(result) || live[result.URL] || seen[result.URL] {
+			continue
+		}
+		seen[result.URL] = true
+		old := newPage(result)
+
+		if key, ok := lookupMapping(opts.Mapping, result.URL, old.path); ok {
+			usedMapping[key] = true
+			to := opts.Mapping[key]
+			redirects = append(redirects, Redirect{From: result.URL, To: to, Match: MatchMapping, Score: 1})
+			continue
+		}
+		if p, ok := byPath[old.path]; ok {
+			redirects = append(redirects, Redirect{From: result.URL, To: p.result.URL, Match: MatchPath, Score: 1})
+			continue
+		}
+		if p, ok := byHash[result.ContentHash]; ok && result.ContentHash != "" {
+			redirects = append(redirects, Redirect{From: result.URL, To: p.result.URL, Match: MatchContent, Score: 1})
+			continue
+		}
+		if candidates := bySlug[old.slug]; len(candidates) == 1 && old.slug != "" {
+			redirects = append(redirects, Redirect{From: result.URL, To: candidates[0].result.URL, Match: MatchSlug, Score: 0.9})
+			continue
+		}
+
+		redirect := Redirect{From: result.URL, Match: MatchNone}
+		if best, score := bestMatch(old, index); best != nil && score >= opts.MinScore {
+			redirect.To = best.result.URL
+			redirect.Match = MatchSimilar
+			redirect.Score = score
+		}
+		redirects = append(redirects, redirect)
+	}
+
+	// Mapping entries for old URLs the crawl didn't reach still apply
+	for from, to := range opts.Mapping {
+		if !usedMapping[from] && !seen[from] && !live[from] {
+			redirects = append(redirects, Redirect{From: from, To: to, Match: MatchMapping, Score: 1})
+		}
+	}
+
+	sort.Slice(redirects, func(i, j int) bool {
+		return redirects[i].From < redirects[j].From
+	})
+	return redirects
+}
+
+// servesPage reports whether a result is a successfully fetched page
+func servesPage(result *models.PageResult) bool {
+	return result.Error == "" && result.StatusCode >= 200 && result.StatusCode < 300
+}
+
+// lookupMapping finds an old page's mapping entry by full URL, then by path,
+// and returns its key
+func lookupMapping(mapping map[string]string, rawURL, urlPath string) (string, bool) {
+	for _, key := range []string{rawURL, urlPath} {
+		if _, ok := mapping[key]; ok {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// bestMatch scores the new pages that share a token with old and returns the
+// most similar one. Ties go to the shorter, then alphabetically first, URL.
+func bestMatch(old *page, index map[string][]*page) (*page, float64) {
+	candidates := make(map[*page]bool)
+	for _, token := range old.tokens() {
+		for _, p := range index[token] {
+			candidates[p] = true
+		}
+	}
+
+	var best *page
+	bestScore := 0.0
+	for p := range candidates {
+		score := similarity(old, p)
+		if best == nil || score > bestScore ||
+			(score == bestScore && lessURL(p.result.URL, best.result.URL)) {
+			best, bestScore = p, score
+		}
+	}
+	return best, bestScore
+}
+
+func lessURL(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+// similarity weighs the overlap of path, title, and content tokens. A signal
+// missing from both pages is left out rather than counted as a mismatch.
+func similarity(a, b *page) float64 {
+	var total, weight float64
+	for _, signal := range []struct {
+		a, b   map[string]bool
+		weight float64
+	}{
+		{a.pathSet, b.pathSet, 0.4},
+		{a.title, b.title, 0.4},
+		{a.content, b.content, 0.2},
+	} {
+		if len(signal.a) == 0 && len(signal.b) == 0 {
+			continue
+		}
+		total += signal.weight * jaccard(signal.a, signal.b)
+		weight += signal.weight
+	}
+	if weight == 0 {
+		return 0
+	}
+	return total / weight
+}
+
+// jaccard is the size of the intersection of two sets over their union
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for token := range a {
+		if b[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func newPage(result *models.PageResult) *page {
+	p := &page{result: result, path: "/"}
+	if u, err := url.Parse(result.URL); err == nil {
+		if u.Path != "" {
+			p.path = u.Path
+		}
+		if u.RawQuery != "" {
+			p.path += "?" + u.RawQuery
+		}
+	}
+
+	urlPath := strings.SplitN(p.path, "?", 2)[0]
+	slug := path.Base(strings.TrimSuffix(urlPath, "/"))
+	if slug != "/" && slug != "." {
+		p.slug = strings.ToLower(strings.TrimSuffix(slug, path.Ext(slug)))
+	}
+	p.pathSet = tokenSet(urlPath)
+	p.title = tokenSet(result.Title)
+	content := append(append([]string{result.MetaDesc}, result.H1...), result.H2...)
+	p.content = tokenSet(strings.Join(content, " "))
+	return p
+}
+
+// tokens returns every token of the page, for the candidate index
+func (p *page) tokens() []string {
+	var tokens []string
+	for _, set := range []map[string]bool{p.pathSet, p.title, p.content} {
+		for token := range set {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// stopWords are too common to say anything about a page
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "to": true,
+	"in": true, "for": true, "on": true, "with": true, "is": true, "at": true,
+	"by": true, "or": true, "html": true, "htm": true, "php": true, "www": true,
+}
+
+// tokenSet splits text into lowercase words, dropping stop words
+func tokenSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127)
+	}) {
+		if !stopWords[word] {
+			set[word] = true
+		}
+	}
+	return set
+}
+
+// ReadMapping reads a mapping file of "old,new" CSV rows. Old entries may be
+// full URLs or paths. Blank lines, # comments, and a header row are skipped.
+func ReadMapping(r io.Reader) (map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	mapping := make(map[string]string)
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mapping: %w", err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("mapping line %d: expected old,new", line)
+		}
+		from, to := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if !isURLOrPath(from) {
+			if len(mapping) == 0 {
+				// Header row
+				continue
+			}
+			return nil, fmt.Errorf("mapping line %d: %q is not a URL or path", line, from)
+		}
+		if !isURLOrPath(to) {
+			return nil, fmt.Errorf("mapping line %d: %q is not a URL or path", line, to)
+		}
+		mapping[from] = to
+	}
+	return mapping, nil
+}
+
+func isURLOrPath(s string) bool {
+	return strings.HasPrefix(s, "/") || strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
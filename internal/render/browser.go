@@ -0,0 +1,66 @@
+package render
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// BrowserEnvVar overrides headless browser discovery with an explicit binary path
+const BrowserEnvVar = "BARRACUDA_CHROME"
+
+// candidateBinaries are Chrome/Chromium executable names searched on PATH
+var candidateBinaries = []string{
+	"google-chrome",
+	"google-chrome-stable",
+	"chromium",
+	"chromium-browser",
+	"chrome",
+	"microsoft-edge",
+}
+
+// FindBrowser locates a Chrome-compatible browser for headless rendering.
+// BARRACUDA_CHROME takes precedence, then PATH, then well-known install locations.
+func FindBrowser() (string, error) {
+	if path := os.Getenv(BrowserEnvVar); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("%s points to %s, which does not exist", BrowserEnvVar, path)
+		}
+		return path, nil
+	}
+
+	for _, name := range candidateBinaries {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+
+	for _, path := range platformPaths() {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no Chrome or Chromium browser found")
+}
+
+// platformPaths returns default install locations that are usually not on PATH
+func platformPaths() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{
+			"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+			"/Applications/Chromium.app/Contents/MacOS/Chromium",
+			"/Applications/Microsoft Edge.app/Contents/MacOS/Microsoft Edge",
+		}
+	case "windows":
+		return []string{
+			`C:\Program Files\Google\Chrome\Application\chrome.exe`,
+			`C:\Program Files (x86)\Google\Chrome\Application\chrome.exe`,
+			`C:\Program Files (x86)\Microsoft\Edge\Application\msedge.exe`,
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package render
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// PrintPDF converts a local HTML file to PDF with a headless browser
+func PrintPDF(ctx context.Context, htmlPath, pdfPath string) error {
+	browser, err := FindBrowser()
+	if err != nil {
+		return fmt.Errorf("PDF output needs Chrome or Chromium (set %s to its path): %w", BrowserEnvVar, err)
+	}
+
+	htmlAbs, err := filepath.Abs(htmlPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", htmlPath, err)
+	}
+	pdfAbs, err := filepath.Abs(pdfPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", pdfPath, err)
+	}
+
+	args := []string{
+		"--headless",
+		"--disable-gpu",
+		"--no-pdf-header-footer",
+		"--print-to-pdf=" + pdfAbs,
+	}
+	// Chrome refuses to start as root without disabling its sandbox
+	if os.Geteuid() == 0 {
+		args = append(args, "--no-sandbox")
+	}
+	fileURL := filepath.ToSlash(htmlAbs)
+	if !strings.HasPrefix(fileURL, "/") {
+		fileURL = "/" + fileURL // Windows drive paths
+	}
+	args = append(args, "file://"+fileURL)
+
+	cmd := exec.CommandContext(ctx, browser, args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("browser failed to print PDF: %w\n%s", err, output)
+	}
+
+	if _, err := os.Stat(pdfAbs); err != nil {
+		return fmt.Errorf("browser did not write %s", pdfPath)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Artifact is a file kept alongside a crawl's rows, such as a raw export or
+// an HTML snapshot
+type Artifact struct {
+	Name        string    `json:"name"` // Path within the crawl, e.g. html/<hash>.html
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ArtifactStore keeps crawl files too large for the database. Each crawl's
+// artifacts live under a <project_id>/<crawl_id>/ prefix and are downloaded
+// through short-lived signed URLs rather than through the API server.
+type ArtifactStore interface {
+	// Put stores an artifact, replacing any artifact of the same name
+	Put(ctx context.Context, projectID, crawlID, name, contentType string, data io.Reader) error
+	// List returns a crawl's artifacts sorted by name
+	List(ctx context.Context, projectID, crawlID string) ([]Artifact, error)
+	// SignedURL returns a URL that downloads an artifact until expiry passes.
+	// It returns ErrNotFound if the artifact doesn't exist.
+	SignedURL(ctx context.Context, projectID, crawlID, name string, expiry time.Duration) (string, error)
+	// SignedUploadURL returns a URL a client can PUT an artifact to directly
+	SignedUploadURL(ctx context.Context, projectID, crawlID, name string) (string, error)
+}
+
+var _ ArtifactStore = (*SupabaseArtifacts)(nil)
+
+// ArtifactKey returns the storage key of a crawl artifact
+func ArtifactKey(projectID, crawlID, name string) string {
+	return projectID + "/" + crawlID + "/" + name
+}
+
+// ValidateArtifactName rejects names that would escape the crawl's prefix or
+// that object storage can't hold
+func ValidateArtifactName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("artifact name is required")
+	case len(name) > 512:
+		return fmt.Errorf("artifact name is longer than 512 characters")
+	case strings.HasPrefix(name, "/") || path.Clean(name) != name || name == "." || name == ".." || strings.HasPrefix(name, "../"):
+		return fmt.Errorf("artifact name %q must be a clean relative path", name)
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || r == '\\' {
+			return fmt.Errorf("artifact name %q contains an invalid character", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"github.com/google/uuid"
+)
+
+// MemoryStore keeps everything in memory. It backs the local dashboard and
+// handler tests, and loses its data when the process exits.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	projects   map[string]*Project
+	members    map[string]map[string]string // project ID -> user ID -> role
+	crawls     map[string]*Crawl
+	pages      map[string][]*Page // by crawl ID
+	issues     map[string][]*Issue
+	logs       map[string][]*CrawlLog
+	trends     map[string]map[string]*IssueTrend // project ID -> day
+	nextPageID int64
+	nextIssue  int64
+	nextLogID  int64
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		projects: make(map[string]*Project),
+		members:  make(map[string]map[string]string),
+		crawls:   make(map[string]*Crawl),
+		pages:    make(map[string][]*Page),
+		issues:   make(map[string][]*Issue),
+		logs:     make(map[string][]*CrawlLog),
+		trends:   make(map[string]map[string]*IssueTrend),
+	}
+}
+
+// SaveProject inserts a project
+func (m *MemoryStore) SaveProject(ctx context.Context, project *Project) error {
+	prepareProject(project)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *project
+	m.projects[project.ID] = &stored
+	return nil
+}
+
+// GetProject returns a project by ID
+func (m *MemoryStore) GetProject(ctx context.Context, id string) (*Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	project, ok := m.projects[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *project
+	return &copied, nil
+}
+
+// ListProjects returns the projects a user owns or is a member of
+func (m *MemoryStore) ListProjects(ctx context.Context, userID string) ([]*Project, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	projects := make([]*Project, 0)
+	for id, project := range m.projects {
+		if m.canAccess(id, userID) {
+			copied := *project
+			projects = append(projects, &copied)
+		}
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].CreatedAt.After(projects[j].CreatedAt)
+	})
+	return projects, nil
+}
+
+// UpdateProject changes the fields set in update
+func (m *MemoryStore) UpdateProject(ctx context.Context, id string, update ProjectUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	project, ok := m.projects[id]
+	if !ok {
+		return ErrNotFound
+	}
+	update.apply(project)
+	return nil
+}
+
+// AddProjectMember grants a user a role on a project
+func (m *MemoryStore) AddProjectMember(ctx context.Context, projectID, userID, role string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.members[projectID] == nil {
+		m.members[projectID] = make(map[string]string)
+	}
+	m.members[projectID][userID] = role
+	return nil
+}
+
+// IsProjectMember reports whether a user is a member of a project
+func (m *MemoryStore) IsProjectMember(ctx context.Context, projectID, userID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.members[projectID][userID]
+	return ok, nil
+}
+
+// canAccess reports whether a user owns or is a member of a project.
+// The caller must hold m.mu.
+func (m *MemoryStore) canAccess(projectID, userID string) bool {
+	if _, ok := m.members[projectID][userID]; ok {
+		return true
+	}
+	project, ok := m.projects[projectID]
+	return ok && project.OwnerID == userID
+}
+
+// SaveCrawl inserts a crawl
+func (m *MemoryStore) SaveCrawl(ctx context.Context, crawl *Crawl) error {
+	prepareCrawl(crawl)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *crawl
+	m.crawls[crawl.ID] = &stored
+	return nil
+}
+
+// UpdateCrawl changes the fields set in update
+func (m *MemoryStore) UpdateCrawl(ctx context.Context, id string, update CrawlUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	crawl, ok := m.crawls[id]
+	if !ok {
+		return ErrNotFound
+	}
+	update.apply(crawl)
+	return nil
+}
+
+// GetCrawl returns a crawl by ID
+func (m *MemoryStore) GetCrawl(ctx context.Context, id string) (*Crawl, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	crawl, ok := m.crawls[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *crawl
+	return &copied, nil
+}
+
+// ListCrawls returns matching crawls, newest first
+func (m *MemoryStore) ListCrawls(ctx context.Context, filter CrawlFilter) ([]*Crawl, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	crawls := make([]*Crawl, 0)
+	for _, crawl := range m.crawls {
+		if filter.ProjectID != "" && crawl.ProjectID != filter.ProjectID {
+			continue
+		}
+		if filter.UserID != "" && !m.canAccess(crawl.ProjectID, filter.UserID) {
+			continue
+		}
+		if filter.Status != "" && crawl.Status != filter.Status {
+			continue
+		}
+		copied := *crawl
+		crawls = append(crawls, &copied)
+	}
+	sort.Slice(crawls, func(i, j int) bool {
+		return crawls[i].StartedAt.After(crawls[j].StartedAt)
+	})
+	return crawls, nil
+}
+
+// SavePages inserts pages and sets their IDs
+func (m *MemoryStore) SavePages(ctx context.Context, pages []*Page) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, page := range pages {
+		m.nextPageID++
+		page.ID = m.nextPageID
+		stored := *page
+		m.pages[page.CrawlID] = append(m.pages[page.CrawlID], &stored)
+	}
+	return nil
+}
+
+// ListPages returns the pages of a crawl in insertion order
+func (m *MemoryStore) ListPages(ctx context.Context, crawlID string) ([]*Page, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pages := make([]*Page, 0, len(m.pages[crawlID]))
+	for _, page := range m.pages[crawlID] {
+		copied := *page
+		pages = append(pages, &copied)
+	}
+	return pages, nil
+}
+
+// CountPages returns the number of pages stored for a crawl
+func (m *MemoryStore) CountPages(ctx context.Context, crawlID string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.pages[crawlID]), nil
+}
+
+// SaveIssues inserts issues and sets their IDs
+func (m *MemoryStore) SaveIssues(ctx context.Context, issues []*Issue) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, issue := range issues {
+		m.nextIssue++
+		issue.ID = m.nextIssue
+		stored := *issue
+		m.issues[issue.CrawlID] = append(m.issues[issue.CrawlID], &stored)
+	}
+	return nil
+}
+
+// ListIssues returns the issues of a crawl in insertion order
+func (m *MemoryStore) ListIssues(ctx context.Context, crawlID string) ([]*Issue, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	issues := make([]*Issue, 0, len(m.issues[crawlID]))
+	for _, issue := range m.issues[crawlID] {
+		copied := *issue
+		issues = append(issues, &copied)
+	}
+	return issues, nil
+}
+
+// SaveCrawlLogs inserts log entries and sets their IDs
+func (m *MemoryStore) SaveCrawlLogs(ctx context.Context, logs []*CrawlLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, log := range logs {
+		m.nextLogID++
+		log.ID = m.nextLogID
+		stored := *log
+		m.logs[log.CrawlID] = append(m.logs[log.CrawlID], &stored)
+	}
+	return nil
+}
+
+// ListCrawlLogs returns a crawl's matching log entries in insertion order
+func (m *MemoryStore) ListCrawlLogs(ctx context.Context, crawlID string, filter models.CrawlEventFilter) ([]*CrawlLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	logs := make([]*CrawlLog, 0)
+	for _, log := range m.logs[crawlID] {
+		if !filter.Matches(log.CrawlEvent) {
+			continue
+		}
+		copied := *log
+		logs = append(logs, &copied)
+		if filter.Limit > 0 && len(logs) >= filter.Limit {
+			break
+		}
+	}
+	return logs, nil
+}
+
+// SaveIssueTrends stores days of issue counts, replacing those of the same
+// project and day
+func (m *MemoryStore) SaveIssueTrends(ctx context.Context, trends []*IssueTrend) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, trend := range trends {
+		if m.trends[trend.ProjectID] == nil {
+			m.trends[trend.ProjectID] = make(map[string]*IssueTrend)
+		}
+		stored := *trend
+		m.trends[trend.ProjectID][trend.Day] = &stored
+	}
+	return nil
+}
+
+// ListIssueTrends returns a project's issue counts from since on, oldest first
+func (m *MemoryStore) ListIssueTrends(ctx context.Context, projectID, since string) ([]*IssueTrend, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	trends := make([]*IssueTrend, 0)
+	for day, trend := range m.trends[projectID] {
+		if day >= since {
+			copied := *trend
+			trends = append(trends, &copied)
+		}
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		return trends[i].Day < trends[j].Day
+	})
+	return trends, nil
+}
+
+// Close is a no-op for the in-memory store
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// prepareProject fills in a new project's ID and timestamps
+func prepareProject(project *Project) {
+	if project.ID == "" {
+		project.ID = uuid.New().String()
+	}
+	now := time.Now().UTC()
+	if project.CreatedAt.IsZero() {
+		project.CreatedAt = now
+	}
+	if project.UpdatedAt.IsZero() {
+		project.UpdatedAt = now
+	}
+}
+
+// prepareCrawl fills in a new crawl's ID and start time
+func prepareCrawl(crawl *Crawl) {
+	if crawl.ID == "" {
+		crawl.ID = uuid.New().String()
+	}
+	if crawl.StartedAt.IsZero() {
+		crawl.StartedAt = time.Now().UTC()
+	}
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// Page is a stored page result. Frequently queried fields are columns and
+// the rest of the result is kept in Data.
+type Page struct {
+	ID              int64    `json:"id,omitempty"`
+	CrawlID         string   `json:"crawl_id"`
+	URL             string   `json:"url"`
+	StatusCode      int      `json:"status_code"`
+	ResponseTimeMs  int64    `json:"response_time_ms"`
+	Title           string   `json:"title"`
+	MetaDescription string   `json:"meta_description"`
+	CanonicalURL    string   `json:"canonical_url"`
+	H1              string   `json:"h1"` // Comma-joined; the full list is in Data.H1
+	WordCount       int      `json:"word_count"`
+	ContentHash     string   `json:"content_hash,omitempty"`
+	Data            PageData `json:"data"`
+}
+
+// PageData holds the page result fields that are not columns
+type PageData struct {
+	H1             []string                 `json:"h1,omitempty"`
+	H2             []string                 `json:"h2"`
+	H3             []string                 `json:"h3"`
+	H4             []string                 `json:"h4"`
+	H5             []string                 `json:"h5"`
+	H6             []string                 `json:"h6"`
+	InternalLinks  []string                 `json:"internal_links"`
+	ExternalLinks  []string                 `json:"external_links"`
+	Images         []models.Image           `json:"images"`
+	Assets         []string                 `json:"assets,omitempty"`
+	ThirdParty     []models.ThirdPartyAsset `json:"third_party_assets,omitempty"`
+	SchemaVersion  int                      `json:"schema_version,omitempty"`
+	Depth          int                      `json:"depth"`
+	TTFB           int64                    `json:"ttfb_ms,omitempty"`
+	Timing         *models.RequestTiming    `json:"timing,omitempty"`
+	MetaRobots     string                   `json:"meta_robots,omitempty"`
+	Robots         *models.RobotsDirectives `json:"robots,omitempty"`
+	Links          []models.Link            `json:"links,omitempty"`
+	FragmentLinks  []string                 `json:"fragment_links,omitempty"`
+	Anchors        []string                 `json:"anchors,omitempty"`
+	Hreflang       []models.Hreflang        `json:"hreflang,omitempty"`
+	StructuredData []models.StructuredData  `json:"structured_data,omitempty"`
+	ExpectedSchema []string                 `json:"expected_schema,omitempty"`
+	RelNext        string                   `json:"rel_next,omitempty"`
+	RelPrev        string                   `json:"rel_prev,omitempty"`
+	TextHash       string                   `json:"text_hash,omitempty"`
+	SimHash        string                   `json:"simhash,omitempty"`
+	PageSize       int                      `json:"page_size_bytes,omitempty"`
+	ContentType    string                   `json:"content_type,omitempty"`
+	BodyTruncated  bool                     `json:"body_truncated,omitempty"`
+	Headers        map[string]string        `json:"headers,omitempty"`
+	RedirectChain  []string                 `json:"redirect_chain,omitempty"`
+	Error          string                   `json:"error,omitempty"`
+	ErrorCode      string                   `json:"error_code,omitempty"`
+	CrawledAt      *time.Time               `json:"crawled_at,omitempty"`
+	PublishedAt    *time.Time               `json:"published_at,omitempty"`
+	ModifiedAt     *time.Time               `json:"modified_at,omitempty"`
+	SitemapLastMod *time.Time               `json:"sitemap_lastmod,omitempty"`
+	InSitemap      bool                     `json:"in_sitemap,omitempty"`
+	Viewport       string                   `json:"viewport,omitempty"`
+	Mobile         *models.MobileVariant    `json:"mobile,omitempty"`
+}
+
+// NewPage converts a crawled page result into a stored page
+func NewPage(crawlID string, result *models.PageResult) *Page {
+	page := &Page{
+		CrawlID:         crawlID,
+		URL:             result.URL,
+		StatusCode:      result.StatusCode,
+		ResponseTimeMs:  result.ResponseTime,
+		Title:           result.Title,
+		MetaDescription: result.MetaDesc,
+		CanonicalURL:    result.Canonical,
+		H1:              strings.Join(result.H1, ", "),
+		WordCount:       result.WordCount,
+		ContentHash:     result.ContentHash,
+		Data: PageData{
+			H1:             result.H1,
+			H2:             result.H2,
+			H3:             result.H3,
+			H4:             result.H4,
+			H5:             result.H5,
+			H6:             result.H6,
+			InternalLinks:  result.InternalLinks,
+			ExternalLinks:  result.ExternalLinks,
+			Images:         result.Images,
+			Assets:         result.Assets,
+			ThirdParty:     result.ThirdParty,
+			SchemaVersion:  result.SchemaVersion,
+			Depth:          result.Depth,
+			TTFB:           result.TTFB,
+			Timing:         result.Timing,
+			MetaRobots:     result.MetaRobots,
+			Robots:         result.Robots,
+			Links:          result.Links,
+			FragmentLinks:  result.FragmentLinks,
+			Anchors:        result.Anchors,
+			Hreflang:       result.Hreflang,
+			StructuredData: result.StructuredData,
+			ExpectedSchema: result.ExpectedSchema,
+			RelNext:        result.RelNext,
+			RelPrev:        result.RelPrev,
+			TextHash:       result.TextHash,
+			SimHash:        result.SimHash,
+			PageSize:       result.PageSize,
+			ContentType:    result.ContentType,
+			BodyTruncated:  result.BodyTruncated,
+			Headers:        result.Headers,
+			RedirectChain:  result.RedirectChain,
+			Error:          result.Error,
+			ErrorCode:      result.ErrorCode,
+			PublishedAt:    result.PublishedAt,
+			ModifiedAt:     result.ModifiedAt,
+			SitemapLastMod: result.SitemapLastMod,
+			InSitemap:      result.InSitemap,
+			Viewport:       result.Viewport,
+			Mobile:         result.Mobile,
+		},
+	}
+	if !result.CrawledAt.IsZero() {
+		crawledAt := result.CrawledAt
+		page.Data.CrawledAt = &crawledAt
+	}
+	return page
+}
+
+// Result converts a stored page back into a page result
+func (p *Page) Result() *models.PageResult {
+	result := &models.PageResult{
+		SchemaVersion:  p.Data.SchemaVersion,
+		URL:            p.URL,
+		StatusCode:     p.StatusCode,
+		ResponseTime:   p.ResponseTimeMs,
+		Depth:          p.Data.Depth,
+		TTFB:           p.Data.TTFB,
+		Timing:         p.Data.Timing,
+		Title:          p.Title,
+		MetaDesc:       p.MetaDescription,
+		MetaRobots:     p.Data.MetaRobots,
+		Robots:         p.Data.Robots,
+		Canonical:      p.CanonicalURL,
+		RelNext:        p.Data.RelNext,
+		RelPrev:        p.Data.RelPrev,
+		H1:             p.Data.H1,
+		H2:             p.Data.H2,
+		H3:             p.Data.H3,
+		H4:             p.Data.H4,
+		H5:             p.Data.H5,
+		H6:             p.Data.H6,
+		InternalLinks:  p.Data.InternalLinks,
+		ExternalLinks:  p.Data.ExternalLinks,
+		Links:          p.Data.Links,
+		FragmentLinks:  p.Data.FragmentLinks,
+		Anchors:        p.Data.Anchors,
+		Images:         p.Data.Images,
+		Assets:         p.Data.Assets,
+		ThirdParty:     p.Data.ThirdParty,
+		Hreflang:       p.Data.Hreflang,
+		StructuredData: p.Data.StructuredData,
+		ExpectedSchema: p.Data.ExpectedSchema,
+		WordCount:      p.WordCount,
+		PageSize:       p.Data.PageSize,
+		ContentType:    p.Data.ContentType,
+		BodyTruncated:  p.Data.BodyTruncated,
+		ContentHash:    p.ContentHash,
+		TextHash:       p.Data.TextHash,
+		SimHash:        p.Data.SimHash,
+		Headers:        p.Data.Headers,
+		RedirectChain:  p.Data.RedirectChain,
+		Error:          p.Data.Error,
+		ErrorCode:      p.Data.ErrorCode,
+		PublishedAt:    p.Data.PublishedAt,
+		ModifiedAt:     p.Data.ModifiedAt,
+		SitemapLastMod: p.Data.SitemapLastMod,
+		InSitemap:      p.Data.InSitemap,
+		Viewport:       p.Data.Viewport,
+		Mobile:         p.Data.Mobile,
+	}
+	// Pages stored before the full heading list was kept only have the column
+	if result.H1 == nil && p.H1 != "" {
+		result.H1 = strings.Split(p.H1, ", ")
+	}
+	if result.SchemaVersion == 0 {
+		result.SchemaVersion = 1
+	}
+	if p.Data.CrawledAt != nil {
+		result.CrawledAt = *p.Data.CrawledAt
+	}
+	return result
+}
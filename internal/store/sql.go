@@ -0,0 +1,787 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+	_ "github.com/lib/pq"           // PostgreSQL driver
+	_ "github.com/mattn/go-sqlite3" // SQLite driver (requires cgo)
+)
+
+// dialect holds the SQL differences between the supported databases
+type dialect struct {
+	driver    string
+	serial    string // Auto-incrementing integer primary key
+	json      string // Column type for JSON documents
+	timestamp string
+	position  string // Function finding a substring: position(haystack, needle), 0 when absent
+	numbered  bool   // Placeholders are $1, $2, ... instead of ?
+}
+
+var (
+	sqliteDialect = dialect{
+		driver:    "sqlite3",
+		serial:    "integer primary key autoincrement",
+		json:      "text",
+		timestamp: "timestamp",
+		position:  "instr",
+	}
+	postgresDialect = dialect{
+		driver:    "postgres",
+		serial:    "bigserial primary key",
+		json:      "jsonb",
+		timestamp: "timestamptz",
+		position:  "strpos",
+		numbered:  true,
+	}
+)
+
+// SQLStore stores data in SQLite or plain PostgreSQL, creating its tables on open
+type SQLStore struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+// OpenSQLite opens or creates a SQLite database file
+func OpenSQLite(path string) (*SQLStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("SQLite store needs a file path")
+	}
+	// Foreign keys are off by default in SQLite; a busy timeout lets crawl
+	// workers and request handlers share the file
+	return openSQL(sqliteDialect, path+"?_foreign_keys=on&_busy_timeout=5000")
+}
+
+// OpenPostgres connects to a PostgreSQL database
+func OpenPostgres(dsn string) (*SQLStore, error) {
+	return openSQL(postgresDialect, dsn)
+}
+
+func openSQL(d dialect, dsn string) (*SQLStore, error) {
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database: %w", d.driver, err)
+	}
+	if d.driver == sqliteDialect.driver {
+		db.SetMaxOpenConns(1)
+	}
+
+	s := &SQLStore{db: db, dialect: d}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate creates any missing tables
+func (s *SQLStore) migrate() error {
+	d := s.dialect
+	statements := []string{
+		`create table if not exists projects (
+			id text primary key,
+			name text not null,
+			domain text not null,
+			owner_id text not null,
+			settings ` + d.json + `,
+			created_at ` + d.timestamp + ` not null,
+			updated_at ` + d.timestamp + ` not null,
+			verification_token text,
+			verification_method text,
+			verified_at ` + d.timestamp + `
+		)`,
+		`create table if not exists project_members (
+			project_id text not null references projects (id) on delete cascade,
+			user_id text not null,
+			role text not null default 'viewer',
+			primary key (project_id, user_id)
+		)`,
+		`create table if not exists crawls (
+			id text primary key,
+			project_id text not null,
+			initiated_by text,
+			source text not null default 'cli',
+			status text not null,
+			started_at ` + d.timestamp + ` not null,
+			completed_at ` + d.timestamp + `,
+			total_pages integer not null default 0,
+			total_issues integer not null default 0,
+			meta ` + d.json + `,
+			robots_txt ` + d.json + `
+		)`,
+		`create index if not exists idx_crawls_project_started on crawls (project_id, started_at)`,
+		`create table if not exists pages (
+			id ` + d.serial + `,
+			crawl_id text not null references crawls (id) on delete cascade,
+			url text not null,
+			status_code integer,
+			response_time_ms integer,
+			title text,
+			meta_description text,
+			canonical_url text,
+			h1 text,
+			word_count integer,
+			content_hash text,
+			data ` + d.json + `
+		)`,
+		`create index if not exists idx_pages_crawl on pages (crawl_id)`,
+		`create table if not exists issues (
+			id ` + d.serial + `,
+			crawl_id text not null references crawls (id) on delete cascade,
+			page_id bigint references pages (id) on delete set null,
+			project_id text,
+			type text not null,
+			severity text not null,
+			message text not null,
+			recommendation text,
+			value text,
+			fingerprint text,
+			status text not null default 'new'
+		)`,
+		`create index if not exists idx_issues_crawl on issues (crawl_id)`,
+		`create table if not exists crawl_logs (
+			id ` + d.serial + `,
+			crawl_id text not null references crawls (id) on delete cascade,
+			time ` + d.timestamp + ` not null,
+			event text not null,
+			url text not null,
+			depth integer not null default 0,
+			reason text,
+			source text,
+			status integer,
+			error text
+		)`,
+		`create index if not exists idx_crawl_logs_crawl on crawl_logs (crawl_id, id)`,
+		`create table if not exists issue_trends (
+			project_id text not null,
+			day text not null,
+			crawl_id text not null,
+			total_pages integer not null default 0,
+			total_issues integer not null default 0,
+			by_severity ` + d.json + `,
+			by_type ` + d.json + `,
+			primary key (project_id, day)
+		)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := s.db.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to create %s schema: %w", s.dialect.driver, err)
+		}
+	}
+
+	// Databases created before issues had fingerprints lack the column
+	if _, err := s.db.Exec(`select fingerprint from issues limit 0`); err != nil {
+		if _, err := s.db.Exec(`alter table issues add column fingerprint text`); err != nil {
+			return fmt.Errorf("failed to add issue fingerprint column: %w", err)
+		}
+	}
+	// Databases created before domain verification lack its columns
+	if _, err := s.db.Exec(`select verified_at from projects limit 0`); err != nil {
+		for _, column := range []string{"verification_token text", "verification_method text", "verified_at " + d.timestamp} {
+			if _, err := s.db.Exec(`alter table projects add column ` + column); err != nil {
+				return fmt.Errorf("failed to add project verification columns: %w", err)
+			}
+		}
+	}
+	// Databases created before robots.txt snapshots lack the column
+	if _, err := s.db.Exec(`select robots_txt from crawls limit 0`); err != nil {
+		if _, err := s.db.Exec(`alter table crawls add column robots_txt ` + d.json); err != nil {
+			return fmt.Errorf("failed to add crawl robots_txt column: %w", err)
+		}
+	}
+	if _, err := s.db.Exec(`create index if not exists idx_issues_project_fingerprint on issues (project_id, fingerprint)`); err != nil {
+		return fmt.Errorf("failed to create %s schema: %w", s.dialect.driver, err)
+	}
+	return nil
+}
+
+// rebind rewrites ? placeholders for databases that number them
+func (s *SQLStore) rebind(query string) string {
+	if !s.dialect.numbered {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s *SQLStore) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.db.ExecContext(ctx, s.rebind(query), args...)
+}
+
+func (s *SQLStore) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return s.db.QueryContext(ctx, s.rebind(query), args...)
+}
+
+// SaveProject inserts a project
+func (s *SQLStore) SaveProject(ctx context.Context, project *Project) error {
+	prepareProject(project)
+	settings, err := encodeJSON(project.Settings)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.exec(ctx,
+		`insert into projects (id, name, domain, owner_id, settings, created_at, updated_at, verification_token, verification_method, verified_at)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		project.ID, project.Name, project.Domain, project.OwnerID, settings, project.CreatedAt, project.UpdatedAt,
+		project.VerificationToken, project.VerificationMethod, project.VerifiedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert project: %w", err)
+	}
+	return nil
+}
+
+const projectColumns = `id, name, domain, owner_id, settings, created_at, updated_at, verification_token, verification_method, verified_at`
+
+// GetProject returns a project by ID
+func (s *SQLStore) GetProject(ctx context.Context, id string) (*Project, error) {
+	projects, err := s.queryProjects(ctx, `select `+projectColumns+` from projects where id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, ErrNotFound
+	}
+	return projects[0], nil
+}
+
+// ListProjects returns the projects a user owns or is a member of
+func (s *SQLStore) ListProjects(ctx context.Context, userID string) ([]*Project, error) {
+	return s.queryProjects(ctx,
+		`select `+projectColumns+` from projects
+		where owner_id = ? or id in (select project_id from project_members where user_id = ?)
+		order by created_at desc`,
+		userID, userID)
+}
+
+func (s *SQLStore) queryProjects(ctx context.Context, query string, args ...interface{}) ([]*Project, error) {
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := make([]*Project, 0)
+	for rows.Next() {
+		var p Project
+		var settings, token, method sql.NullString
+		var verifiedAt sql.NullTime
+		if err := rows.Scan(&p.ID, &p.Name, &p.Domain, &p.OwnerID, &settings, &p.CreatedAt, &p.UpdatedAt, &token, &method, &verifiedAt); err != nil {
+			return nil, fmt.Errorf("failed to read project: %w", err)
+		}
+		p.VerificationToken, p.VerificationMethod = token.String, method.String
+		if verifiedAt.Valid {
+			p.VerifiedAt = &verifiedAt.Time
+		}
+		if err := decodeJSON(settings, &p.Settings); err != nil {
+			return nil, err
+		}
+		projects = append(projects, &p)
+	}
+	return projects, rows.Err()
+}
+
+// UpdateProject changes the fields set in update
+func (s *SQLStore) UpdateProject(ctx context.Context, id string, update ProjectUpdate) error {
+	sets := []string{"updated_at = ?"}
+	args := []interface{}{time.Now().UTC()}
+	if update.OwnerID != nil {
+		sets = append(sets, "owner_id = ?")
+		args = append(args, *update.OwnerID)
+	}
+	if update.VerificationToken != nil {
+		sets = append(sets, "verification_token = ?")
+		args = append(args, *update.VerificationToken)
+	}
+	if update.VerificationMethod != nil {
+		sets = append(sets, "verification_method = ?")
+		args = append(args, *update.VerificationMethod)
+	}
+	if update.VerifiedAt != nil {
+		sets = append(sets, "verified_at = ?")
+		args = append(args, *update.VerifiedAt)
+	}
+
+	args = append(args, id)
+	result, err := s.exec(ctx, `update projects set `+strings.Join(sets, ", ")+` where id = ?`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddProjectMember grants a user a role on a project
+func (s *SQLStore) AddProjectMember(ctx context.Context, projectID, userID, role string) error {
+	_, err := s.exec(ctx,
+		`insert into project_members (project_id, user_id, role) values (?, ?, ?)
+		on conflict (project_id, user_id) do update set role = excluded.role`,
+		projectID, userID, role)
+	if err != nil {
+		return fmt.Errorf("failed to insert project member: %w", err)
+	}
+	return nil
+}
+
+// IsProjectMember reports whether a user is a member of a project
+func (s *SQLStore) IsProjectMember(ctx context.Context, projectID, userID string) (bool, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, s.rebind(`select count(*) from project_members where project_id = ? and user_id = ?`), projectID, userID).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("failed to query project_members: %w", err)
+	}
+	return n > 0, nil
+}
+
+// SaveCrawl inserts a crawl
+func (s *SQLStore) SaveCrawl(ctx context.Context, crawl *Crawl) error {
+	prepareCrawl(crawl)
+	meta, err := encodeJSON(crawl.Meta)
+	if err != nil {
+		return err
+	}
+	robotsTxt, err := encodeJSON(crawl.RobotsTxt)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.exec(ctx,
+		`insert into crawls (id, project_id, initiated_by, source, status, started_at, completed_at, total_pages, total_issues, meta, robots_txt)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		crawl.ID, crawl.ProjectID, crawl.InitiatedBy, crawl.Source, crawl.Status, crawl.StartedAt,
+		crawl.CompletedAt, crawl.TotalPages, crawl.TotalIssues, meta, robotsTxt)
+	if err != nil {
+		return fmt.Errorf("failed to insert crawl: %w", err)
+	}
+	return nil
+}
+
+// UpdateCrawl changes the fields set in update
+func (s *SQLStore) UpdateCrawl(ctx context.Context, id string, update CrawlUpdate) error {
+	var sets []string
+	var args []interface{}
+	if update.Status != nil {
+		sets = append(sets, "status = ?")
+		args = append(args, *update.Status)
+	}
+	if update.CompletedAt != nil {
+		sets = append(sets, "completed_at = ?")
+		args = append(args, *update.CompletedAt)
+	}
+	if update.TotalPages != nil {
+		sets = append(sets, "total_pages = ?")
+		args = append(args, *update.TotalPages)
+	}
+	if update.TotalIssues != nil {
+		sets = append(sets, "total_issues = ?")
+		args = append(args, *update.TotalIssues)
+	}
+	if update.Meta != nil {
+		meta, err := encodeJSON(update.Meta)
+		if err != nil {
+			return err
+		}
+		sets = append(sets, "meta = ?")
+		args = append(args, meta)
+	}
+	if update.RobotsTxt != nil {
+		robotsTxt, err := encodeJSON(update.RobotsTxt)
+		if err != nil {
+			return err
+		}
+		sets = append(sets, "robots_txt = ?")
+		args = append(args, robotsTxt)
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	args = append(args, id)
+	result, err := s.exec(ctx, `update crawls set `+strings.Join(sets, ", ")+` where id = ?`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update crawl: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const crawlColumns = `id, project_id, initiated_by, source, status, started_at, completed_at, total_pages, total_issues, meta, robots_txt`
+
+// GetCrawl returns a crawl by ID
+func (s *SQLStore) GetCrawl(ctx context.Context, id string) (*Crawl, error) {
+	crawls, err := s.queryCrawls(ctx, `select `+crawlColumns+` from crawls where id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(crawls) == 0 {
+		return nil, ErrNotFound
+	}
+	return crawls[0], nil
+}
+
+// ListCrawls returns matching crawls, newest first
+func (s *SQLStore) ListCrawls(ctx context.Context, filter CrawlFilter) ([]*Crawl, error) {
+	query := `select ` + crawlColumns + ` from crawls where 1 = 1`
+	var args []interface{}
+	if filter.ProjectID != "" {
+		query += ` and project_id = ?`
+		args = append(args, filter.ProjectID)
+	}
+	if filter.UserID != "" {
+		query += ` and project_id in (
+			select id from projects where owner_id = ?
+			union select project_id from project_members where user_id = ?)`
+		args = append(args, filter.UserID, filter.UserID)
+	}
+	if filter.Status != "" {
+		query += ` and status = ?`
+		args = append(args, filter.Status)
+	}
+	return s.queryCrawls(ctx, query+` order by started_at desc`, args...)
+}
+
+func (s *SQLStore) queryCrawls(ctx context.Context, query string, args ...interface{}) ([]*Crawl, error) {
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query crawls: %w", err)
+	}
+	defer rows.Close()
+
+	crawls := make([]*Crawl, 0)
+	for rows.Next() {
+		var c Crawl
+		var initiatedBy, meta, robotsTxt sql.NullString
+		var completedAt sql.NullTime
+		err := rows.Scan(&c.ID, &c.ProjectID, &initiatedBy, &c.Source, &c.Status, &c.StartedAt,
+			&completedAt, &c.TotalPages, &c.TotalIssues, &meta, &robotsTxt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read crawl: %w", err)
+		}
+		c.InitiatedBy = initiatedBy.String
+		if completedAt.Valid {
+			c.CompletedAt = &completedAt.Time
+		}
+		if err := decodeJSON(meta, &c.Meta); err != nil {
+			return nil, err
+		}
+		if err := decodeJSON(robotsTxt, &c.RobotsTxt); err != nil {
+			return nil, err
+		}
+		crawls = append(crawls, &c)
+	}
+	return crawls, rows.Err()
+}
+
+// SavePages inserts pages in one transaction and sets their IDs
+func (s *SQLStore) SavePages(ctx context.Context, pages []*Page) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(
+		`insert into pages (crawl_id, url, status_code, response_time_ms, title, meta_description, canonical_url, h1, word_count, content_hash, data)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) returning id`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare page insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, page := range pages {
+		data, err := encodeJSON(page.Data)
+		if err != nil {
+			return err
+		}
+		err = stmt.QueryRowContext(ctx, page.CrawlID, page.URL, page.StatusCode, page.ResponseTimeMs, page.Title,
+			page.MetaDescription, page.CanonicalURL, page.H1, page.WordCount, page.ContentHash, data).Scan(&page.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert page %s: %w", page.URL, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListPages returns the pages of a crawl in insertion order
+func (s *SQLStore) ListPages(ctx context.Context, crawlID string) ([]*Page, error) {
+	rows, err := s.query(ctx,
+		`select id, crawl_id, url, status_code, response_time_ms, title, meta_description, canonical_url, h1, word_count, content_hash, data
+		from pages where crawl_id = ? order by id`, crawlID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pages: %w", err)
+	}
+	defer rows.Close()
+
+	pages := make([]*Page, 0)
+	for rows.Next() {
+		var p Page
+		var title, metaDesc, canonical, h1, contentHash, data sql.NullString
+		var statusCode, responseTime, wordCount sql.NullInt64
+		err := rows.Scan(&p.ID, &p.CrawlID, &p.URL, &statusCode, &responseTime, &title, &metaDesc,
+			&canonical, &h1, &wordCount, &contentHash, &data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page: %w", err)
+		}
+		p.StatusCode = int(statusCode.Int64)
+		p.ResponseTimeMs = responseTime.Int64
+		p.Title = title.String
+		p.MetaDescription = metaDesc.String
+		p.CanonicalURL = canonical.String
+		p.H1 = h1.String
+		p.WordCount = int(wordCount.Int64)
+		p.ContentHash = contentHash.String
+		if err := decodeJSON(data, &p.Data); err != nil {
+			return nil, err
+		}
+		pages = append(pages, &p)
+	}
+	return pages, rows.Err()
+}
+
+// CountPages returns the number of pages stored for a crawl
+func (s *SQLStore) CountPages(ctx context.Context, crawlID string) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, s.rebind(`select count(*) from pages where crawl_id = ?`), crawlID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count pages: %w", err)
+	}
+	return n, nil
+}
+
+// SaveIssues inserts issues in one transaction and sets their IDs
+func (s *SQLStore) SaveIssues(ctx context.Context, issues []*Issue) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(
+		`insert into issues (crawl_id, page_id, project_id, type, severity, message, recommendation, value, fingerprint, status)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) returning id`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare issue insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, issue := range issues {
+		err := stmt.QueryRowContext(ctx, issue.CrawlID, issue.PageID, issue.ProjectID, issue.Type, issue.Severity,
+			issue.Message, issue.Recommendation, issue.Value, issue.Fingerprint, issue.Status).Scan(&issue.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert issue: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListIssues returns the issues of a crawl in insertion order
+func (s *SQLStore) ListIssues(ctx context.Context, crawlID string) ([]*Issue, error) {
+	rows, err := s.query(ctx,
+		`select id, crawl_id, page_id, project_id, type, severity, message, recommendation, value, fingerprint, status
+		from issues where crawl_id = ? order by id`, crawlID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	defer rows.Close()
+
+	issues := make([]*Issue, 0)
+	for rows.Next() {
+		var i Issue
+		var pageID sql.NullInt64
+		var projectID, recommendation, value, fingerprint sql.NullString
+		err := rows.Scan(&i.ID, &i.CrawlID, &pageID, &projectID, &i.Type, &i.Severity, &i.Message,
+			&recommendation, &value, &fingerprint, &i.Status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read issue: %w", err)
+		}
+		if pageID.Valid {
+			i.PageID = &pageID.Int64
+		}
+		i.ProjectID = projectID.String
+		i.Recommendation = recommendation.String
+		i.Value = value.String
+		i.Fingerprint = fingerprint.String
+		issues = append(issues, &i)
+	}
+	return issues, rows.Err()
+}
+
+// SaveCrawlLogs inserts log entries in one transaction and sets their IDs
+func (s *SQLStore) SaveCrawlLogs(ctx context.Context, logs []*CrawlLog) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(
+		`insert into crawl_logs (crawl_id, time, event, url, depth, reason, source, status, error)
+		values (?, ?, ?, ?, ?, ?, ?, ?, ?) returning id`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare crawl log insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, log := range logs {
+		err := stmt.QueryRowContext(ctx, log.CrawlID, log.Time, log.Event, log.URL, log.Depth,
+			log.Reason, log.Source, log.Status, log.Error).Scan(&log.ID)
+		if err != nil {
+			return fmt.Errorf("failed to insert crawl log: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListCrawlLogs returns a crawl's matching log entries in insertion order
+func (s *SQLStore) ListCrawlLogs(ctx context.Context, crawlID string, filter models.CrawlEventFilter) ([]*CrawlLog, error) {
+	query := `select id, crawl_id, time, event, url, depth, reason, source, status, error
+		from crawl_logs where crawl_id = ?`
+	args := []interface{}{crawlID}
+	if filter.Event != "" {
+		query += ` and event = ?`
+		args = append(args, filter.Event)
+	}
+	if filter.Reason != "" {
+		query += ` and reason = ?`
+		args = append(args, filter.Reason)
+	}
+	if filter.URL != "" {
+		query += ` and ` + s.dialect.position + `(url, ?) > 0`
+		args = append(args, filter.URL)
+	}
+	query += ` order by id`
+	if filter.Limit > 0 {
+		query += ` limit ` + strconv.Itoa(filter.Limit)
+	}
+
+	rows, err := s.query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query crawl logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := make([]*CrawlLog, 0)
+	for rows.Next() {
+		var l CrawlLog
+		var reason, source, errMsg sql.NullString
+		var status sql.NullInt64
+		err := rows.Scan(&l.ID, &l.CrawlID, &l.Time, &l.Event, &l.URL, &l.Depth, &reason, &source, &status, &errMsg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read crawl log: %w", err)
+		}
+		l.Reason = reason.String
+		l.Source = source.String
+		l.Status = int(status.Int64)
+		l.Error = errMsg.String
+		logs = append(logs, &l)
+	}
+	return logs, rows.Err()
+}
+
+// SaveIssueTrends upserts days of issue counts in one transaction
+func (s *SQLStore) SaveIssueTrends(ctx context.Context, trends []*IssueTrend) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(
+		`insert into issue_trends (project_id, day, crawl_id, total_pages, total_issues, by_severity, by_type)
+		values (?, ?, ?, ?, ?, ?, ?)
+		on conflict (project_id, day) do update set crawl_id = excluded.crawl_id,
+			total_pages = excluded.total_pages, total_issues = excluded.total_issues,
+			by_severity = excluded.by_severity, by_type = excluded.by_type`))
+	if err != nil {
+		return fmt.Errorf("failed to prepare issue trend insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, trend := range trends {
+		bySeverity, err := encodeJSON(trend.BySeverity)
+		if err != nil {
+			return err
+		}
+		byType, err := encodeJSON(trend.ByType)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, trend.ProjectID, trend.Day, trend.CrawlID, trend.TotalPages,
+			trend.TotalIssues, bySeverity, byType); err != nil {
+			return fmt.Errorf("failed to insert issue trend: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListIssueTrends returns a project's issue counts from since on, oldest first
+func (s *SQLStore) ListIssueTrends(ctx context.Context, projectID, since string) ([]*IssueTrend, error) {
+	rows, err := s.query(ctx,
+		`select project_id, day, crawl_id, total_pages, total_issues, by_severity, by_type
+		from issue_trends where project_id = ? and day >= ? order by day`, projectID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue trends: %w", err)
+	}
+	defer rows.Close()
+
+	trends := make([]*IssueTrend, 0)
+	for rows.Next() {
+		var t IssueTrend
+		var bySeverity, byType sql.NullString
+		if err := rows.Scan(&t.ProjectID, &t.Day, &t.CrawlID, &t.TotalPages, &t.TotalIssues, &bySeverity, &byType); err != nil {
+			return nil, fmt.Errorf("failed to read issue trend: %w", err)
+		}
+		if err := decodeJSON(bySeverity, &t.BySeverity); err != nil {
+			return nil, err
+		}
+		if err := decodeJSON(byType, &t.ByType); err != nil {
+			return nil, err
+		}
+		trends = append(trends, &t)
+	}
+	return trends, rows.Err()
+}
+
+// Close closes the database connection
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+// encodeJSON marshals a JSON column value
+func encodeJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode JSON column: %w", err)
+	}
+	return string(data), nil
+}
+
+// decodeJSON unmarshals a JSON column, leaving v unchanged for NULL
+func decodeJSON(column sql.NullString, v interface{}) error {
+	if !column.Valid || column.String == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(column.String), v); err != nil {
+		return fmt.Errorf("failed to decode JSON column: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+// Package store persists projects, crawls, pages, and issues for the API
+// server and the local dashboard.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/crawldir"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// ErrNotFound is returned when a requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// Store is a storage backend for crawl results
+type Store interface {
+	// SaveProject inserts a project, assigning its ID and timestamps if unset
+	SaveProject(ctx context.Context, project *Project) error
+	GetProject(ctx context.Context, id string) (*Project, error)
+	// ListProjects returns the projects a user owns or is a member of
+	ListProjects(ctx context.Context, userID string) ([]*Project, error)
+	// UpdateProject changes a project's owner or domain verification state
+	UpdateProject(ctx context.Context, id string, update ProjectUpdate) error
+	AddProjectMember(ctx context.Context, projectID, userID, role string) error
+	IsProjectMember(ctx context.Context, projectID, userID string) (bool, error)
+
+	// SaveCrawl inserts a crawl, assigning its ID if unset
+	SaveCrawl(ctx context.Context, crawl *Crawl) error
+	UpdateCrawl(ctx context.Context, id string, update CrawlUpdate) error
+	GetCrawl(ctx context.Context, id string) (*Crawl, error)
+	// ListCrawls returns matching crawls, newest first
+	ListCrawls(ctx context.Context, filter CrawlFilter) ([]*Crawl, error)
+
+	// SavePages inserts pages and sets their IDs
+	SavePages(ctx context.Context, pages []*Page) error
+	ListPages(ctx context.Context, crawlID string) ([]*Page, error)
+	CountPages(ctx context.Context, crawlID string) (int, error)
+
+	SaveIssues(ctx context.Context, issues []*Issue) error
+	ListIssues(ctx context.Context, crawlID string) ([]*Issue, error)
+
+	// SaveCrawlLogs inserts entries of crawls' structured logs
+	SaveCrawlLogs(ctx context.Context, logs []*CrawlLog) error
+	// ListCrawlLogs returns a crawl's log entries that match filter, in the
+	// order they were logged
+	ListCrawlLogs(ctx context.Context, crawlID string, filter models.CrawlEventFilter) ([]*CrawlLog, error)
+
+	// SaveIssueTrends stores days of projects' issue counts, replacing any
+	// already stored for the same project and day
+	SaveIssueTrends(ctx context.Context, trends []*IssueTrend) error
+	// ListIssueTrends returns a project's issue counts from the day since
+	// (YYYY-MM-DD) on, oldest first
+	ListIssueTrends(ctx context.Context, projectID, since string) ([]*IssueTrend, error)
+
+	Close() error
+}
+
+// Compile-time checks that every backend implements Store
+var (
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*SupabaseStore)(nil)
+	_ Store = (*SQLStore)(nil)
+)
+
+// Project is a site tracked by one or more users
+type Project struct {
+	ID        string                 `json:"id,omitempty"`
+	Name      string                 `json:"name"`
+	Domain    string                 `json:"domain"`
+	OwnerID   string                 `json:"owner_id"`
+	Settings  map[string]interface{} `json:"settings"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+
+	// Domain verification: the token the user publishes on the domain, and
+	// how and when the API found it (see internal/verify)
+	VerificationToken  string     `json:"verification_token,omitempty"`
+	VerificationMethod string     `json:"verification_method,omitempty"` // "dns" or "meta", once verified
+	VerifiedAt         *time.Time `json:"verified_at,omitempty"`
+}
+
+// Verified reports whether the project's domain has been verified
+func (p *Project) Verified() bool {
+	return p.VerifiedAt != nil
+}
+
+// ProjectUpdate changes the non-nil fields of a project
+type ProjectUpdate struct {
+	OwnerID            *string
+	VerificationToken  *string
+	VerificationMethod *string
+	VerifiedAt         *time.Time
+}
+
+// apply copies the update onto a project
+func (u ProjectUpdate) apply(project *Project) {
+	if u.OwnerID != nil {
+		project.OwnerID = *u.OwnerID
+	}
+	if u.VerificationToken != nil {
+		project.VerificationToken = *u.VerificationToken
+	}
+	if u.VerificationMethod != nil {
+		project.VerificationMethod = *u.VerificationMethod
+	}
+	if u.VerifiedAt != nil {
+		verifiedAt := *u.VerifiedAt
+		project.VerifiedAt = &verifiedAt
+	}
+	project.UpdatedAt = time.Now().UTC()
+}
+
+// Crawl is one crawl run of a project
+type Crawl struct {
+	ID          string                 `json:"id,omitempty"`
+	ProjectID   string                 `json:"project_id"`
+	InitiatedBy string                 `json:"initiated_by,omitempty"`
+	Source      string                 `json:"source"` // "cli", "web", or "schedule"
+	Status      string                 `json:"status"` // "pending", "running", "succeeded", "failed", or "cancelled"
+	StartedAt   time.Time              `json:"started_at"`
+	CompletedAt *time.Time             `json:"completed_at"`
+	TotalPages  int                    `json:"total_pages"`
+	TotalIssues int                    `json:"total_issues"`
+	Meta        map[string]interface{} `json:"meta"`
+
+	// RobotsTxt is the start domain's robots.txt when the crawl ran
+	RobotsTxt *models.RobotsTxt `json:"robots_txt,omitempty"`
+}
+
+// ManifestKey is the Meta key holding the crawl's metadata.json manifest
+const ManifestKey = "manifest"
+
+// Manifest returns the metadata manifest the crawl was ingested with, or nil
+// if it had none
+func (c *Crawl) Manifest() *crawldir.Metadata {
+	raw, ok := c.Meta[ManifestKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	// Meta round-trips through JSON in every store, so decode the same way
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var manifest crawldir.Metadata
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil
+	}
+	return &manifest
+}
+
+// CrawlUpdate changes the non-nil fields of a crawl. Meta replaces the
+// existing meta object rather than merging into it.
+type CrawlUpdate struct {
+	Status      *string
+	CompletedAt *time.Time
+	TotalPages  *int
+	TotalIssues *int
+	Meta        map[string]interface{}
+	RobotsTxt   *models.RobotsTxt
+}
+
+// apply copies the update onto a crawl
+func (u CrawlUpdate) apply(crawl *Crawl) {
+	if u.Status != nil {
+		crawl.Status = *u.Status
+	}
+	if u.CompletedAt != nil {
+		completedAt := *u.CompletedAt
+		crawl.CompletedAt = &completedAt
+	}
+	if u.TotalPages != nil {
+		crawl.TotalPages = *u.TotalPages
+	}
+	if u.TotalIssues != nil {
+		crawl.TotalIssues = *u.TotalIssues
+	}
+	if u.Meta != nil {
+		crawl.Meta = u.Meta
+	}
+	if u.RobotsTxt != nil {
+		crawl.RobotsTxt = u.RobotsTxt
+	}
+}
+
+// CrawlFilter selects crawls in ListCrawls. Empty fields match everything.
+type CrawlFilter struct {
+	ProjectID string
+	UserID    string // Only crawls of projects the user can access
+	Status    string
+}
+
+// Issue is an SEO issue found in a crawl
+type Issue struct {
+	ID             int64            `json:"id,omitempty"`
+	CrawlID        string           `json:"crawl_id"`
+	PageID         *int64           `json:"page_id,omitempty"`
+	ProjectID      string           `json:"project_id"`
+	Type           models.IssueType `json:"type"`
+	Severity       models.Severity  `json:"severity"`
+	Message        string           `json:"message"`
+	Recommendation string           `json:"recommendation"`
+	Value          string           `json:"value"`
+	Fingerprint    string           `json:"fingerprint"` // models.Issue.Fingerprint, stable across crawls
+	Status         string           `json:"status"`      // "new", "in_progress", "fixed", or "ignored"
+}
+
+// CrawlLog is an entry of a crawl's structured log: a URL that was crawled,
+// failed, or skipped
+type CrawlLog struct {
+	ID      int64  `json:"id,omitempty"`
+	CrawlID string `json:"crawl_id"`
+	models.CrawlEvent
+}
+
+// IssueTrend is one day of a project's health: the page and issue counts of
+// the last crawl that finished that day. The API server aggregates them
+// nightly so charts don't have to count issue rows.
+type IssueTrend struct {
+	ProjectID   string                   `json:"project_id"`
+	Day         string                   `json:"day"` // YYYY-MM-DD, in UTC
+	CrawlID     string                   `json:"crawl_id"`
+	TotalPages  int                      `json:"total_pages"`
+	TotalIssues int                      `json:"total_issues"`
+	BySeverity  map[models.Severity]int  `json:"by_severity"`
+	ByType      map[models.IssueType]int `json:"by_type"`
+}
+
+// NewIssueTrend counts a crawl's stored issues by severity and type for the
+// day it finished
+func NewIssueTrend(crawl *Crawl, day string, issues []*Issue) *IssueTrend {
+	trend := &IssueTrend{
+		ProjectID:   crawl.ProjectID,
+		Day:         day,
+		CrawlID:     crawl.ID,
+		TotalPages:  crawl.TotalPages,
+		TotalIssues: len(issues),
+		BySeverity:  make(map[models.Severity]int),
+		ByType:      make(map[models.IssueType]int),
+	}
+	for _, issue := range issues {
+		trend.BySeverity[issue.Severity]++
+		trend.ByType[issue.Type]++
+	}
+	return trend
+}
+
+// NewCrawlLogs converts crawl events for storage in a crawl
+func NewCrawlLogs(crawlID string, events []models.CrawlEvent) []*CrawlLog {
+	logs := make([]*CrawlLog, 0, len(events))
+	for _, event := range events {
+		logs = append(logs, &CrawlLog{CrawlID: crawlID, CrawlEvent: event})
+	}
+	return logs
+}
+
+// NewIssue converts a detected issue for storage in a crawl
+func NewIssue(crawlID, projectID string, issue models.Issue) *Issue {
+	return &Issue{
+		CrawlID:        crawlID,
+		ProjectID:      projectID,
+		Type:           issue.Type,
+		Severity:       issue.Severity,
+		Message:        issue.Message,
+		Recommendation: issue.Recommendation,
+		Value:          issue.Value,
+		Fingerprint:    issue.Fingerprint(),
+		Status:         "new",
+	}
+}
+
+// Open opens a self-hosted store from a DSN:
+//
+//	memory                      in-memory, lost on exit
+//	sqlite://path/to/file.db    local SQLite database (also any *.db or *.sqlite path)
+//	postgres://user@host/db     plain PostgreSQL
+//
+// Supabase stores are created with NewSupabaseStore since they need API keys.
+func Open(dsn string) (Store, error) {
+	switch {
+	case dsn == "memory" || dsn == "memory://":
+		return NewMemoryStore(), nil
+	case strings.HasPrefix(dsn, "sqlite://"):
+		return OpenSQLite(strings.TrimPrefix(dsn, "sqlite://"))
+	case strings.HasSuffix(dsn, ".db") || strings.HasSuffix(dsn, ".sqlite"):
+		return OpenSQLite(dsn)
+	case strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://"):
+		return OpenPostgres(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported store %q: use memory, sqlite://<path>, or postgres://<dsn>", dsn)
+	}
+}
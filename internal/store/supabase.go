@@ -0,0 +1,358 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dillonlara115/barracuda/pkg/models"
+	"github.com/supabase-community/postgrest-go"
+	"github.com/supabase-community/supabase-go"
+)
+
+// SupabaseStore stores data in the hosted Supabase schema
+// (see supabase/migrations). It uses the service role key, so access checks
+// are done by the caller rather than by row level security.
+type SupabaseStore struct {
+	client *supabase.Client
+}
+
+// NewSupabaseStore creates a store backed by a Supabase project
+func NewSupabaseStore(url, serviceKey string) (*SupabaseStore, error) {
+	client, err := supabase.NewClient(url, serviceKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Supabase client: %w", err)
+	}
+	return &SupabaseStore{client: client}, nil
+}
+
+// SaveProject inserts a project
+func (s *SupabaseStore) SaveProject(ctx context.Context, project *Project) error {
+	prepareProject(project)
+	if _, _, err := s.client.From("projects").Insert(project, false, "", "minimal", "").Execute(); err != nil {
+		return fmt.Errorf("failed to insert project: %w", err)
+	}
+	return nil
+}
+
+// GetProject returns a project by ID
+func (s *SupabaseStore) GetProject(ctx context.Context, id string) (*Project, error) {
+	var projects []*Project
+	if _, err := s.client.From("projects").Select("*", "", false).Eq("id", id).ExecuteTo(&projects); err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if len(projects) == 0 {
+		return nil, ErrNotFound
+	}
+	return projects[0], nil
+}
+
+// ListProjects returns the projects a user owns or is a member of
+func (s *SupabaseStore) ListProjects(ctx context.Context, userID string) ([]*Project, error) {
+	ids, err := s.accessibleProjectIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]*Project, 0)
+	if len(ids) == 0 {
+		return projects, nil
+	}
+	if _, err := s.client.From("projects").Select("*", "", false).In("id", ids).Order("created_at", nil).ExecuteTo(&projects); err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	return projects, nil
+}
+
+// UpdateProject changes the fields set in update
+func (s *SupabaseStore) UpdateProject(ctx context.Context, id string, update ProjectUpdate) error {
+	fields := make(map[string]interface{})
+	if update.OwnerID != nil {
+		fields["owner_id"] = *update.OwnerID
+	}
+	if update.VerificationToken != nil {
+		fields["verification_token"] = *update.VerificationToken
+	}
+	if update.VerificationMethod != nil {
+		fields["verification_method"] = *update.VerificationMethod
+	}
+	if update.VerifiedAt != nil {
+		fields["verified_at"] = update.VerifiedAt.UTC().Format(time.RFC3339)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if _, _, err := s.client.From("projects").Update(fields, "minimal", "").Eq("id", id).Execute(); err != nil {
+		return fmt.Errorf("failed to update project: %w", err)
+	}
+	return nil
+}
+
+// accessibleProjectIDs returns the IDs of projects a user owns or is a member of
+func (s *SupabaseStore) accessibleProjectIDs(userID string) ([]string, error) {
+	var rows []struct {
+		ID        string `json:"id"`
+		ProjectID string `json:"project_id"`
+	}
+	if _, err := s.client.From("project_members").Select("project_id", "", false).Eq("user_id", userID).ExecuteTo(&rows); err != nil {
+		return nil, fmt.Errorf("failed to query project_members: %w", err)
+	}
+	seen := make(map[string]bool)
+	var ids []string
+	for _, row := range rows {
+		if !seen[row.ProjectID] {
+			seen[row.ProjectID] = true
+			ids = append(ids, row.ProjectID)
+		}
+	}
+
+	rows = nil
+	if _, err := s.client.From("projects").Select("id", "", false).Eq("owner_id", userID).ExecuteTo(&rows); err != nil {
+		return nil, fmt.Errorf("failed to query projects: %w", err)
+	}
+	for _, row := range rows {
+		if !seen[row.ID] {
+			seen[row.ID] = true
+			ids = append(ids, row.ID)
+		}
+	}
+	return ids, nil
+}
+
+// AddProjectMember grants a user a role on a project
+func (s *SupabaseStore) AddProjectMember(ctx context.Context, projectID, userID, role string) error {
+	member := map[string]interface{}{
+		"project_id": projectID,
+		"user_id":    userID,
+		"role":       role,
+	}
+	if _, _, err := s.client.From("project_members").Insert(member, false, "", "minimal", "").Execute(); err != nil {
+		return fmt.Errorf("failed to insert project member: %w", err)
+	}
+	return nil
+}
+
+// IsProjectMember reports whether a user is a member of a project
+func (s *SupabaseStore) IsProjectMember(ctx context.Context, projectID, userID string) (bool, error) {
+	var members []map[string]interface{}
+	_, err := s.client.From("project_members").
+		Select("user_id", "", false).
+		Eq("project_id", projectID).
+		Eq("user_id", userID).
+		ExecuteTo(&members)
+	if err != nil {
+		return false, fmt.Errorf("failed to query project_members: %w", err)
+	}
+	return len(members) > 0, nil
+}
+
+// SaveCrawl inserts a crawl
+func (s *SupabaseStore) SaveCrawl(ctx context.Context, crawl *Crawl) error {
+	prepareCrawl(crawl)
+	if _, _, err := s.client.From("crawls").Insert(crawl, false, "", "minimal", "").Execute(); err != nil {
+		return fmt.Errorf("failed to insert crawl: %w", err)
+	}
+	return nil
+}
+
+// UpdateCrawl changes the fields set in update
+func (s *SupabaseStore) UpdateCrawl(ctx context.Context, id string, update CrawlUpdate) error {
+	fields := make(map[string]interface{})
+	if update.Status != nil {
+		fields["status"] = *update.Status
+	}
+	if update.CompletedAt != nil {
+		fields["completed_at"] = update.CompletedAt.UTC().Format(time.RFC3339)
+	}
+	if update.TotalPages != nil {
+		fields["total_pages"] = *update.TotalPages
+	}
+	if update.TotalIssues != nil {
+		fields["total_issues"] = *update.TotalIssues
+	}
+	if update.Meta != nil {
+		fields["meta"] = update.Meta
+	}
+	if update.RobotsTxt != nil {
+		fields["robots_txt"] = update.RobotsTxt
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if _, _, err := s.client.From("crawls").Update(fields, "minimal", "").Eq("id", id).Execute(); err != nil {
+		return fmt.Errorf("failed to update crawl: %w", err)
+	}
+	return nil
+}
+
+// GetCrawl returns a crawl by ID
+func (s *SupabaseStore) GetCrawl(ctx context.Context, id string) (*Crawl, error) {
+	var crawls []*Crawl
+	if _, err := s.client.From("crawls").Select("*", "", false).Eq("id", id).ExecuteTo(&crawls); err != nil {
+		return nil, fmt.Errorf("failed to get crawl: %w", err)
+	}
+	if len(crawls) == 0 {
+		return nil, ErrNotFound
+	}
+	return crawls[0], nil
+}
+
+// ListCrawls returns matching crawls, newest first
+func (s *SupabaseStore) ListCrawls(ctx context.Context, filter CrawlFilter) ([]*Crawl, error) {
+	query := s.client.From("crawls").Select("*", "", false)
+	if filter.ProjectID != "" {
+		query = query.Eq("project_id", filter.ProjectID)
+	}
+	if filter.Status != "" {
+		query = query.Eq("status", filter.Status)
+	}
+
+	crawls := make([]*Crawl, 0)
+	if filter.UserID != "" {
+		ids, err := s.accessibleProjectIDs(filter.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return crawls, nil
+		}
+		query = query.In("project_id", ids)
+	}
+
+	if _, err := query.Order("started_at", nil).ExecuteTo(&crawls); err != nil {
+		return nil, fmt.Errorf("failed to list crawls: %w", err)
+	}
+	return crawls, nil
+}
+
+// SavePages inserts pages and sets their IDs
+func (s *SupabaseStore) SavePages(ctx context.Context, pages []*Page) error {
+	if len(pages) == 0 {
+		return nil
+	}
+
+	data, _, err := s.client.From("pages").Insert(pages, false, "", "", "").Execute()
+	if err != nil {
+		return fmt.Errorf("failed to insert pages: %w", err)
+	}
+
+	// Rows come back in insertion order with their generated IDs
+	var inserted []struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(data, &inserted); err != nil {
+		return fmt.Errorf("failed to parse inserted pages: %w", err)
+	}
+	for i := range inserted {
+		if i < len(pages) {
+			pages[i].ID = inserted[i].ID
+		}
+	}
+	return nil
+}
+
+// ListPages returns the pages of a crawl
+func (s *SupabaseStore) ListPages(ctx context.Context, crawlID string) ([]*Page, error) {
+	pages := make([]*Page, 0)
+	if _, err := s.client.From("pages").Select("*", "", false).Eq("crawl_id", crawlID).ExecuteTo(&pages); err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+	return pages, nil
+}
+
+// CountPages returns the number of pages stored for a crawl
+func (s *SupabaseStore) CountPages(ctx context.Context, crawlID string) (int, error) {
+	_, count, err := s.client.From("pages").Select("id", "exact", true).Eq("crawl_id", crawlID).Execute()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pages: %w", err)
+	}
+	return int(count), nil
+}
+
+// SaveIssues inserts issues
+func (s *SupabaseStore) SaveIssues(ctx context.Context, issues []*Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	if _, _, err := s.client.From("issues").Insert(issues, false, "", "minimal", "").Execute(); err != nil {
+		return fmt.Errorf("failed to insert issues: %w", err)
+	}
+	return nil
+}
+
+// ListIssues returns the issues of a crawl
+func (s *SupabaseStore) ListIssues(ctx context.Context, crawlID string) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+	if _, err := s.client.From("issues").Select("*", "", false).Eq("crawl_id", crawlID).ExecuteTo(&issues); err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	return issues, nil
+}
+
+// SaveCrawlLogs inserts log entries
+func (s *SupabaseStore) SaveCrawlLogs(ctx context.Context, logs []*CrawlLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	if _, _, err := s.client.From("crawl_logs").Insert(logs, false, "", "minimal", "").Execute(); err != nil {
+		return fmt.Errorf("failed to insert crawl logs: %w", err)
+	}
+	return nil
+}
+
+// ListCrawlLogs returns a crawl's matching log entries in insertion order
+func (s *SupabaseStore) ListCrawlLogs(ctx context.Context, crawlID string, filter models.CrawlEventFilter) ([]*CrawlLog, error) {
+	query := s.client.From("crawl_logs").Select("*", "", false).Eq("crawl_id", crawlID)
+	if filter.Event != "" {
+		query = query.Eq("event", filter.Event)
+	}
+	if filter.Reason != "" {
+		query = query.Eq("reason", filter.Reason)
+	}
+	if filter.URL != "" {
+		query = query.Like("url", "*"+filter.URL+"*")
+	}
+	query = query.Order("id", &postgrest.OrderOpts{Ascending: true})
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit, "")
+	}
+
+	logs := make([]*CrawlLog, 0)
+	if _, err := query.ExecuteTo(&logs); err != nil {
+		return nil, fmt.Errorf("failed to list crawl logs: %w", err)
+	}
+	return logs, nil
+}
+
+// SaveIssueTrends upserts days of issue counts by project and day
+func (s *SupabaseStore) SaveIssueTrends(ctx context.Context, trends []*IssueTrend) error {
+	if len(trends) == 0 {
+		return nil
+	}
+	if _, _, err := s.client.From("issue_trends").Insert(trends, true, "project_id,day", "minimal", "").Execute(); err != nil {
+		return fmt.Errorf("failed to save issue trends: %w", err)
+	}
+	return nil
+}
+
+// ListIssueTrends returns a project's issue counts from since on, oldest first
+func (s *SupabaseStore) ListIssueTrends(ctx context.Context, projectID, since string) ([]*IssueTrend, error) {
+	trends := make([]*IssueTrend, 0)
+	_, err := s.client.From("issue_trends").Select("*", "", false).
+		Eq("project_id", projectID).
+		Gte("day", since).
+		Order("day", &postgrest.OrderOpts{Ascending: true}).
+		ExecuteTo(&trends)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issue trends: %w", err)
+	}
+	return trends, nil
+}
+
+// Close is a no-op; the Supabase client holds no connections open
+func (s *SupabaseStore) Close() error {
+	return nil
+}
@@ -0,0 +1,217 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArtifactBucket is the private Supabase Storage bucket crawl artifacts are
+// kept in (see supabase/migrations)
+const ArtifactBucket = "crawl-artifacts"
+
+// artifactListPage is how many objects one Storage list request returns
+const artifactListPage = 1000
+
+// SupabaseArtifacts stores crawl artifacts in Supabase Storage. It talks to
+// the Storage REST API directly: storage-go shares request headers between
+// calls, so an upload's content type would leak into the requests after it.
+type SupabaseArtifacts struct {
+	baseURL    string // Storage API root, e.g. https://<ref>.supabase.co/storage/v1
+	serviceKey string
+	bucket     string
+	client     *http.Client
+}
+
+// NewSupabaseArtifacts creates an artifact store in a Supabase project's
+// ArtifactBucket. It uses the service role key; callers check access.
+func NewSupabaseArtifacts(supabaseURL, serviceKey string) *SupabaseArtifacts {
+	return &SupabaseArtifacts{
+		baseURL:    strings.TrimSuffix(supabaseURL, "/") + "/storage/v1",
+		serviceKey: serviceKey,
+		bucket:     ArtifactBucket,
+		client:     &http.Client{Timeout: 5 * time.Minute}, // Uploads can be large
+	}
+}
+
+// Put uploads an artifact, overwriting an existing one
+func (s *SupabaseArtifacts) Put(ctx context.Context, projectID, crawlID, name, contentType string, data io.Reader) error {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	req, err := s.newRequest(ctx, http.MethodPost, "/object/"+s.bucket+"/"+escapeKey(ArtifactKey(projectID, crawlID, name)), data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-upsert", "true")
+	if err := s.do(req, nil); err != nil {
+		return fmt.Errorf("failed to upload artifact %s: %w", name, err)
+	}
+	return nil
+}
+
+// List returns every artifact under the crawl's prefix, descending into
+// folders such as html/
+func (s *SupabaseArtifacts) List(ctx context.Context, projectID, crawlID string) ([]Artifact, error) {
+	prefix := ArtifactKey(projectID, crawlID, "")
+	artifacts := make([]Artifact, 0)
+	if err := s.list(ctx, prefix, "", &artifacts); err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+	return artifacts, nil
+}
+
+// storageObject is an entry of a Storage list response. Folders have no ID.
+type storageObject struct {
+	Name      string     `json:"name"`
+	ID        *string    `json:"id"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	Metadata  *struct {
+		Size     int64  `json:"size"`
+		MimeType string `json:"mimetype"`
+	} `json:"metadata"`
+}
+
+// list appends the objects in the folder prefix+dir, recursively
+func (s *SupabaseArtifacts) list(ctx context.Context, prefix, dir string, artifacts *[]Artifact) error {
+	for offset := 0; ; offset += artifactListPage {
+		body, _ := json.Marshal(map[string]interface{}{
+			"prefix": prefix + dir,
+			"limit":  artifactListPage,
+			"offset": offset,
+			"sortBy": map[string]string{"column": "name", "order": "asc"},
+		})
+		req, err := s.newRequest(ctx, http.MethodPost, "/object/list/"+s.bucket, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		var objects []storageObject
+		if err := s.do(req, &objects); err != nil {
+			return err
+		}
+
+		for _, object := range objects {
+			if object.ID == nil {
+				if err := s.list(ctx, prefix, dir+object.Name+"/", artifacts); err != nil {
+					return err
+				}
+				continue
+			}
+			artifact := Artifact{Name: dir + object.Name}
+			if object.UpdatedAt != nil {
+				artifact.UpdatedAt = *object.UpdatedAt
+			}
+			if object.Metadata != nil {
+				artifact.Size = object.Metadata.Size
+				artifact.ContentType = object.Metadata.MimeType
+			}
+			*artifacts = append(*artifacts, artifact)
+		}
+		if len(objects) < artifactListPage {
+			return nil
+		}
+	}
+}
+
+// SignedURL signs a download URL for an artifact
+func (s *SupabaseArtifacts) SignedURL(ctx context.Context, projectID, crawlID, name string, expiry time.Duration) (string, error) {
+	body, _ := json.Marshal(map[string]int{"expiresIn": int(expiry.Seconds())})
+	req, err := s.newRequest(ctx, http.MethodPost, "/object/sign/"+s.bucket+"/"+escapeKey(ArtifactKey(projectID, crawlID, name)), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var signed struct {
+		SignedURL string `json:"signedURL"`
+	}
+	if err := s.do(req, &signed); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return "", err
+		}
+		return "", fmt.Errorf("failed to sign artifact URL: %w", err)
+	}
+	return s.baseURL + signed.SignedURL, nil
+}
+
+// SignedUploadURL signs a URL the artifact can be PUT to without the
+// service key. The URL is valid for two hours.
+func (s *SupabaseArtifacts) SignedUploadURL(ctx context.Context, projectID, crawlID, name string) (string, error) {
+	req, err := s.newRequest(ctx, http.MethodPost, "/object/upload/sign/"+s.bucket+"/"+escapeKey(ArtifactKey(projectID, crawlID, name)), strings.NewReader("{}"))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-upsert", "true")
+	var signed struct {
+		URL string `json:"url"`
+	}
+	if err := s.do(req, &signed); err != nil {
+		return "", fmt.Errorf("failed to sign artifact upload URL: %w", err)
+	}
+	return s.baseURL + signed.URL, nil
+}
+
+func (s *SupabaseArtifacts) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.serviceKey)
+	req.Header.Set("apikey", s.serviceKey)
+	return req, nil
+}
+
+// do sends a Storage request and decodes its JSON response into v. Missing
+// objects are reported as ErrNotFound; Storage answers them with a 400 whose
+// body carries the real status.
+func (s *SupabaseArtifacts) do(req *http.Request, v interface{}) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var storageErr struct {
+			StatusCode string `json:"statusCode"`
+			Error      string `json:"error"`
+			Message    string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		_ = json.Unmarshal(data, &storageErr)
+		if resp.StatusCode == http.StatusNotFound || storageErr.StatusCode == "404" {
+			return ErrNotFound
+		}
+		if storageErr.Message != "" {
+			return fmt.Errorf("storage returned %d: %s", resp.StatusCode, storageErr.Message)
+		}
+		return fmt.Errorf("storage returned %d", resp.StatusCode)
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode storage response: %w", err)
+	}
+	return nil
+}
+
+// escapeKey escapes each segment of a storage key for use in a URL path
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
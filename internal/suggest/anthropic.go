@@ -0,0 +1,83 @@
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AnthropicEndpoint is the Anthropic Messages API endpoint
+const AnthropicEndpoint = "https://api.anthropic.com/v1/messages"
+
+// AnthropicDefaultModel is used when no model is configured
+const AnthropicDefaultModel = "claude-3-5-haiku-latest"
+
+// anthropicVersion is the API version sent with every request
+const anthropicVersion = "2023-06-01"
+
+// Anthropic completes prompts with the Anthropic Messages API
+type Anthropic struct {
+	APIKey     string
+	Model      string // defaults to AnthropicDefaultModel
+	Endpoint   string // defaults to AnthropicEndpoint
+	HTTPClient *http.Client
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// Name implements Provider
+func (a *Anthropic) Name() string { return "anthropic" }
+
+// Complete implements Provider
+func (a *Anthropic) Complete(ctx context.Context, prompt string) (string, error) {
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = AnthropicEndpoint
+	}
+	model := a.Model
+	if model == "" {
+		model = AnthropicDefaultModel
+	}
+
+	request := anthropicRequest{
+		Model:     model,
+		MaxTokens: 512,
+		Messages:  []anthropicMessage{{Role: "user", Content: prompt}},
+	}
+	var resp anthropicResponse
+	headers := map[string]string{
+		"x-api-key":         a.APIKey,
+		"anthropic-version": anthropicVersion,
+	}
+	if err := doJSON(ctx, a.HTTPClient, endpoint, headers, request, &resp); err != nil {
+		return "", err
+	}
+
+	var text strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("response has no text")
+	}
+	return text.String(), nil
+}
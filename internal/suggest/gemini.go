@@ -0,0 +1,73 @@
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GeminiEndpoint is the base URL of the Gemini API's models
+const GeminiEndpoint = "https://generativelanguage.googleapis.com/v1beta/models"
+
+// GeminiDefaultModel is used when no model is configured
+const GeminiDefaultModel = "gemini-1.5-flash"
+
+// Gemini completes prompts with the Gemini generateContent API
+type Gemini struct {
+	APIKey     string
+	Model      string // defaults to GeminiDefaultModel
+	Endpoint   string // defaults to GeminiEndpoint
+	HTTPClient *http.Client
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiRequest struct {
+	Contents []geminiContent `json:"contents"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+}
+
+// Name implements Provider
+func (g *Gemini) Name() string { return "gemini" }
+
+// Complete implements Provider
+func (g *Gemini) Complete(ctx context.Context, prompt string) (string, error) {
+	endpoint := g.Endpoint
+	if endpoint == "" {
+		endpoint = GeminiEndpoint
+	}
+	model := g.Model
+	if model == "" {
+		model = GeminiDefaultModel
+	}
+
+	request := geminiRequest{Contents: []geminiContent{{Parts: []geminiPart{{Text: prompt}}}}}
+	var resp geminiResponse
+	target := fmt.Sprintf("%s/%s:generateContent", endpoint, url.PathEscape(model))
+	headers := map[string]string{"x-goog-api-key": g.APIKey}
+	if err := doJSON(ctx, g.HTTPClient, target, headers, request, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Candidates) == 0 {
+		return "", fmt.Errorf("response has no candidates")
+	}
+
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String(), nil
+}
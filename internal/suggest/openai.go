@@ -0,0 +1,66 @@
+package suggest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// OpenAIEndpoint is the OpenAI chat completions endpoint
+const OpenAIEndpoint = "https://api.openai.com/v1/chat/completions"
+
+// OpenAIDefaultModel is used when no model is configured
+const OpenAIDefaultModel = "gpt-4o-mini"
+
+// OpenAI completes prompts with the OpenAI chat completions API
+type OpenAI struct {
+	APIKey     string
+	Model      string // defaults to OpenAIDefaultModel
+	Endpoint   string // defaults to OpenAIEndpoint
+	HTTPClient *http.Client
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// Name implements Provider
+func (o *OpenAI) Name() string { return "openai" }
+
+// Complete implements Provider
+func (o *OpenAI) Complete(ctx context.Context, prompt string) (string, error) {
+	endpoint := o.Endpoint
+	if endpoint == "" {
+		endpoint = OpenAIEndpoint
+	}
+	model := o.Model
+	if model == "" {
+		model = OpenAIDefaultModel
+	}
+
+	request := openAIRequest{
+		Model:    model,
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+	}
+	var resp openAIResponse
+	headers := map[string]string{"Authorization": "Bearer " + o.APIKey}
+	if err := doJSON(ctx, o.HTTPClient, endpoint, headers, request, &resp); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("response has no choices")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
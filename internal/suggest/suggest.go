@@ -0,0 +1,216 @@
+// Package suggest proposes title and meta description rewrites for pages
+// with title or description issues, using a large language model API
+// (OpenAI, Anthropic, or Gemini) with the user's own API key.
+package suggest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// concurrency limits parallel requests to the provider
+const concurrency = 4
+
+// Provider sends a prompt to one LLM API and returns the text of its reply
+type Provider interface {
+	// Name is the provider's name as used in the config file
+	Name() string
+	Complete(ctx context.Context, prompt string) (string, error)
+}
+
+// New creates the provider named in cfg
+func New(cfg utils.LLMFileConfig) (Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("llm.api_key is required")
+	}
+	client := &http.Client{Timeout: 60 * time.Second}
+
+	switch cfg.Provider {
+	case "openai":
+		return &OpenAI{APIKey: cfg.APIKey, Model: cfg.Model, Endpoint: cfg.Endpoint, HTTPClient: client}, nil
+	case "anthropic":
+		return &Anthropic{APIKey: cfg.APIKey, Model: cfg.Model, Endpoint: cfg.Endpoint, HTTPClient: client}, nil
+	case "gemini":
+		return &Gemini{APIKey: cfg.APIKey, Model: cfg.Model, Endpoint: cfg.Endpoint, HTTPClient: client}, nil
+	case "":
+		return nil, fmt.Errorf("llm.provider is required")
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q (expected openai, anthropic, or gemini)", cfg.Provider)
+	}
+}
+
+// Rewrite is a suggested replacement for a page's title and/or meta
+// description. A field is empty when it needs no rewrite.
+type Rewrite struct {
+	Title    string `json:"title"`
+	MetaDesc string `json:"meta_description"`
+}
+
+// fieldsToRewrite reports which of a page's fields have issues
+func fieldsToRewrite(issues []models.Issue) (title, metaDesc bool) {
+	for _, issue := range issues {
+		switch issue.Type {
+		case models.IssueMissingTitle, models.IssueShortTitle, models.IssueLongTitle:
+			title = true
+		case models.IssueMissingMetaDesc, models.IssueShortMetaDesc, models.IssueLongMetaDesc:
+			metaDesc = true
+		}
+	}
+	return title, metaDesc
+}
+
+// Suggest asks the provider for a rewrite of the fields of page that have
+// issues. It returns an empty Rewrite when neither field has one.
+func Suggest(ctx context.Context, provider Provider, page *models.PageResult, issues []models.Issue) (Rewrite, error) {
+	title, metaDesc := fieldsToRewrite(issues)
+	if !title && !metaDesc {
+		return Rewrite{}, nil
+	}
+
+	reply, err := provider.Complete(ctx, buildPrompt(page, title, metaDesc))
+	if err != nil {
+		return Rewrite{}, fmt.Errorf("%s request failed: %w", provider.Name(), err)
+	}
+
+	// Models sometimes wrap JSON in a Markdown code fence despite instructions
+	reply = strings.TrimSpace(reply)
+	reply = strings.TrimPrefix(reply, "```json")
+	reply = strings.TrimPrefix(reply, "```")
+	reply = strings.TrimSuffix(reply, "```")
+
+	var rewrite Rewrite
+	if err := json.Unmarshal([]byte(strings.TrimSpace(reply)), &rewrite); err != nil {
+		return Rewrite{}, fmt.Errorf("%s returned an unexpected reply: %w", provider.Name(), err)
+	}
+	if !title {
+		rewrite.Title = ""
+	}
+	if !metaDesc {
+		rewrite.MetaDesc = ""
+	}
+	rewrite.Title = strings.TrimSpace(rewrite.Title)
+	rewrite.MetaDesc = strings.TrimSpace(rewrite.MetaDesc)
+	return rewrite, nil
+}
+
+// buildPrompt describes the page and asks for a JSON rewrite
+func buildPrompt(page *models.PageResult, title, metaDesc bool) string {
+	var b strings.Builder
+	b.WriteString("You are an SEO copywriter. Suggest improved HTML metadata for this web page.\n\n")
+	fmt.Fprintf(&b, "URL: %s\n", page.URL)
+	fmt.Fprintf(&b, "Current title: %q\n", page.Title)
+	fmt.Fprintf(&b, "Current meta description: %q\n", page.MetaDesc)
+	if len(page.H1) > 0 {
+		fmt.Fprintf(&b, "H1: %q\n", page.H1[0])
+	}
+	if len(page.H2) > 0 {
+		headings := page.H2
+		if len(headings) > 5 {
+			headings = headings[:5]
+		}
+		fmt.Fprintf(&b, "H2 headings: %s\n", strings.Join(headings, "; "))
+	}
+
+	b.WriteString("\nWrite in the page's language. ")
+	if title {
+		fmt.Fprintf(&b, "Write a title of %d to %d characters. ", analyzer.MinTitleLength, analyzer.MaxTitleLength)
+	}
+	if metaDesc {
+		fmt.Fprintf(&b, "Write a meta description of %d to %d characters. ", analyzer.MinMetaDescLength, analyzer.MaxMetaDescLength)
+	}
+	b.WriteString("Reply with only a JSON object with the keys \"title\" and \"meta_description\"")
+	if !title || !metaDesc {
+		b.WriteString("; leave the key you were not asked to write empty")
+	}
+	b.WriteString(".\n")
+	return b.String()
+}
+
+// Enrich fills SuggestedTitle and SuggestedMetaDesc on up to limit pages that
+// have title or meta description issues (all of them if limit is 0). Failed
+// requests are logged and skipped; it returns how many pages got a
+// suggestion and how many requests failed.
+func Enrich(ctx context.Context, provider Provider, results []*models.PageResult, issues []models.Issue, limit int) (suggested, failed int) {
+	byURL := make(map[string][]models.Issue)
+	for _, issue := range issues {
+		byURL[issue.URL] = append(byURL[issue.URL], issue)
+	}
+
+	var pages []*models.PageResult
+	for _, page := range results {
+		if title, metaDesc := fieldsToRewrite(byURL[page.URL]); title || metaDesc {
+			pages = append(pages, page)
+		}
+	}
+	if limit > 0 && len(pages) > limit {
+		pages = pages[:limit]
+	}
+
+	var suggestedCount, failedCount atomic.Int32
+	var requests errgroup.Group
+	requests.SetLimit(concurrency)
+	for _, page := range pages {
+		page := page
+		requests.Go(func() error {
+			rewrite, err := Suggest(ctx, provider, page, byURL[page.URL])
+			if err != nil {
+				utils.Warn("Rewrite suggestion failed", utils.NewField("url", page.URL), utils.NewField("error", err.Error()))
+				failedCount.Add(1)
+				return nil
+			}
+			page.SuggestedTitle = rewrite.Title
+			page.SuggestedMetaDesc = rewrite.MetaDesc
+			if rewrite.Title != "" || rewrite.MetaDesc != "" {
+				suggestedCount.Add(1)
+			}
+			return nil
+		})
+	}
+	_ = requests.Wait()
+	return int(suggestedCount.Load()), int(failedCount.Load())
+}
+
+// doJSON POSTs payload as JSON and decodes the JSON response into v
+func doJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload, v interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "barracuda/1.0.0")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, snippet)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
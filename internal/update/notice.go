@@ -0,0 +1,101 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DisableEnvVar turns off the background version check when set to any value
+const DisableEnvVar = "BARRACUDA_NO_UPDATE_CHECK"
+
+// checkInterval is how often the background check queries GitHub
+const checkInterval = 24 * time.Hour
+
+// checkState is persisted between runs so GitHub is queried at most once a day
+type checkState struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	LatestVersion string    `json:"latest_version"`
+}
+
+// statePath returns the file the last check result is cached in
+func statePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "barracuda", "update-check.json"), nil
+}
+
+func loadState() checkState {
+	var state checkState
+	path, err := statePath()
+	if err != nil {
+		return state
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(data, &state)
+	return state
+}
+
+func saveState(state checkState) {
+	path, err := statePath()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
+
+// Notice checks for a newer release without blocking the caller. It returns a
+// function that yields the newer version (or "") once the command has finished;
+// that function waits at most briefly for a refresh still in flight.
+// Failures are silent: the notice is best-effort only.
+func Notice(current string) func() string {
+	if os.Getenv(DisableEnvVar) != "" {
+		return func() string { return "" }
+	}
+
+	state := loadState()
+	done := make(chan struct{})
+
+	if time.Since(state.CheckedAt) < checkInterval {
+		close(done)
+	} else {
+		go func() {
+			defer close(done)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			release, err := LatestRelease(ctx)
+			if err != nil {
+				return
+			}
+			state = checkState{CheckedAt: time.Now(), LatestVersion: release.Version()}
+			saveState(state)
+		}()
+	}
+
+	return func() string {
+		select {
+		case <-done:
+		case <-time.After(500 * time.Millisecond):
+			return ""
+		}
+		if state.LatestVersion != "" && IsNewer(state.LatestVersion, current) {
+			return state.LatestVersion
+		}
+		return ""
+	}
+}
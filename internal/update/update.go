@@ -0,0 +1,291 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repository is the GitHub repository releases are published to
+const Repository = "dillonlara115/barracuda"
+
+// ChecksumsAsset is the release asset listing SHA-256 sums of every archive
+const ChecksumsAsset = "checksums.txt"
+
+// Release is the subset of the GitHub release API response used for updates
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a downloadable file attached to a release
+type Asset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the leading "v"
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// FindAsset returns the asset with the given name, or nil
+func (r *Release) FindAsset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// LatestRelease fetches the newest published release from GitHub
+func LatestRelease(ctx context.Context) (*Release, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", Repository)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GitHub releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub releases returned HTTP %d", resp.StatusCode)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+	return &release, nil
+}
+
+// IsNewer reports whether version a is newer than version b.
+// Versions are dotted integers with an optional "v" prefix; pre-release
+// suffixes ("-rc1") are ignored.
+func IsNewer(a, b string) bool {
+	pa, pb := parseVersion(a), parseVersion(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return false
+}
+
+func parseVersion(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
+
+// BinaryName returns the executable name inside the release archive for this platform
+func BinaryName() string {
+	name := fmt.Sprintf("barracuda-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// ArchiveName returns the release asset name for this platform
+func ArchiveName() string {
+	name := fmt.Sprintf("barracuda-%s-%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		return name + ".zip"
+	}
+	return name + ".tar.gz"
+}
+
+// Apply downloads this platform's binary from the release, verifies its
+// checksum against checksums.txt, and replaces the executable at target.
+func Apply(ctx context.Context, release *Release, target string) error {
+	archive := release.FindAsset(ArchiveName())
+	if archive == nil {
+		return fmt.Errorf("release %s has no build for %s/%s", release.TagName, runtime.GOOS, runtime.GOARCH)
+	}
+	sums := release.FindAsset(ChecksumsAsset)
+	if sums == nil {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.TagName, ChecksumsAsset)
+	}
+
+	checksumData, err := download(ctx, sums.BrowserDownloadURL)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
+	expected, err := lookupChecksum(checksumData, archive.Name)
+	if err != nil {
+		return err
+	}
+
+	archiveData, err := download(ctx, archive.BrowserDownloadURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", archive.Name, err)
+	}
+	sum := sha256.Sum256(archiveData)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archive.Name, expected, actual)
+	}
+
+	binary, err := extractBinary(archive.Name, archiveData, BinaryName())
+	if err != nil {
+		return err
+	}
+
+	return replaceExecutable(target, binary)
+}
+
+// download fetches a URL into memory
+func download(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// lookupChecksum finds the SHA-256 for name in sha256sum-formatted data
+func lookupChecksum(data []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// extractBinary pulls the named file out of a .tar.gz or .zip archive
+func extractBinary(archiveName string, data []byte, binaryName string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip archive: %w", err)
+		}
+		for _, f := range zr.File {
+			if filepath.Base(f.Name) != binaryName {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", binaryName, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s not found in %s", binaryName, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		if filepath.Base(hdr.Name) == binaryName {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in %s", binaryName, archiveName)
+}
+
+// replaceExecutable writes binary next to target and swaps it in. The old
+// executable is renamed first because Windows cannot overwrite a running binary.
+func replaceExecutable(target string, binary []byte) error {
+	dir := filepath.Dir(target)
+	tmp, err := os.CreateTemp(dir, ".barracuda-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file in %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0755); err != nil {
+		return fmt.Errorf("failed to make new binary executable: %w", err)
+	}
+
+	oldPath := target + ".old"
+	_ = os.Remove(oldPath)
+	if err := os.Rename(target, oldPath); err != nil {
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		// Restore the original so the install isn't left broken
+		_ = os.Rename(oldPath, target)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	if runtime.GOOS != "windows" {
+		_ = os.Remove(oldPath)
+	}
+
+	return nil
+}
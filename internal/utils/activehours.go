@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ActiveWindow is a daily window of local time, such as 01:00-06:00. A
+// window that ends before it starts, such as 22:00-06:00, spans midnight.
+type ActiveWindow struct {
+	Start time.Duration // Since midnight
+	End   time.Duration // Since midnight; the window is open until just before it
+}
+
+// ParseActiveWindow parses a window written as "HH:MM-HH:MM"
+func ParseActiveWindow(s string) (*ActiveWindow, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidActiveHours, s)
+	}
+	start, startErr := parseClock(from)
+	end, endErr := parseClock(to)
+	if startErr != nil || endErr != nil || start == end {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidActiveHours, s)
+	}
+	return &ActiveWindow{Start: start, End: end}, nil
+}
+
+// parseClock parses a time of day as HH:MM, up to 24:00
+func parseClock(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t falls inside the window
+func (w *ActiveWindow) Contains(t time.Time) bool {
+	offset := t.Sub(midnight(t))
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// NextStart returns when the window next opens after t, in t's location
+func (w *ActiveWindow) NextStart(t time.Time) time.Time {
+	start := midnight(t).Add(w.Start)
+	if !start.After(t) {
+		start = midnight(t.AddDate(0, 0, 1)).Add(w.Start)
+	}
+	return start
+}
+
+// String formats the window as it is parsed
+func (w *ActiveWindow) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(w.Start) + "-" + clock(w.End)
+}
+
+// midnight returns the start of t's day in t's location
+func midnight(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -1,23 +1,73 @@
 package utils
 
 import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// Crawl strategies of Config.Strategy: the order queued URLs are crawled in
+const (
+	StrategyBFS      = "bfs"      // Breadth-first: in the order they were found
+	StrategyDFS      = "dfs"      // Depth-first: the most recently found first
+	StrategyPriority = "priority" // Shallow and high-value URLs first
+)
+
+// DefaultMaxBodySize is the largest response body read when Config.MaxBodySize
+// is empty
+const DefaultMaxBodySize = 10 << 20
+
 // Config holds all crawl configuration settings
 type Config struct {
-	StartURL      string
-	MaxDepth      int
-	MaxPages      int
-	DomainFilter  string        // "same" or "all"
-	Workers       int
-	Delay         time.Duration
-	Timeout       time.Duration
-	UserAgent     string
-	RespectRobots bool
-	ParseSitemap  bool
-	ExportFormat  string // "csv" or "json"
-	ExportPath    string
+	StartURL        string
+	ExtraStartURLs  []string // Further start URLs crawled with StartURL, sharing its visited set and domain scope
+	MaxDepth        int
+	MaxPages        int
+	DomainFilter    string // "same" or "all"
+	Workers         int
+	Delay           time.Duration // Minimum gap between requests to one host
+	MaxRPS          float64       // Maximum requests per second to one host (0: no limit)
+	AutoThrottle    bool          // Slow down for hosts that answer 429 or 503, honoring Retry-After
+	Timeout         time.Duration
+	UserAgent       string
+	RespectRobots   bool
+	ParseSitemap    bool
+	SitemapSince    string // With ParseSitemap, only seed URLs whose <lastmod> is on or after this date (see SitemapSinceTime)
+	ExportFormat    string // "csv", "json", or "jsonl"
+	ExportPath      string
+	Include         []string // Regex patterns; when set, only matching URLs are crawled
+	Exclude         []string // Regex patterns; matching URLs are never crawled
+	ScopePath       string   // When set, only URLs whose path starts with this prefix are crawled
+	RespectNofollow bool     // Don't follow rel=nofollow, sponsored, or ugc links or the links of meta nofollow pages
+	SkipImageCheck  bool     // Skip fetching images to check their file size during analysis
+	URLList         []string // List mode: crawl exactly these URLs without following links
+	SaveHTMLDir     string   // When set, write each fetched page body here with an index.json manifest
+	Render          bool     // Render HTML pages in a headless browser before parsing them
+	VisitedLimit    int      // Visited URLs kept exactly before overflowing into a bloom filter (0 keeps all)
+	VisitedFPRate   float64  // Target false-positive rate of the visited bloom filter (0 uses the default)
+	LowMemory       bool     // Keep visited URLs as hashes and fewer queued URLs in memory
+	Strategy        string   // StrategyBFS (default when empty), StrategyDFS, or StrategyPriority
+	StreamResults   bool     // Append each page to the export file as it is crawled instead of holding results in memory
+	HostRewrites    []string // "from=to[:port]": fetch URLs on host from from host to, keeping from in the Host header
+	MaxBodySize     string   // Largest response body read, such as "10MB" or "0" for no limit (see MaxBodyBytes)
+	Headers         []string // Extra request headers as "Name: Value", sent only to the start URLs' domains
+	Cookies         []string // Cookies as "name=value", sent only to the start URLs' domains
+	BasicAuth       string   // "user:password" for HTTP basic auth on the start URLs' domains
+	LoginURL        string   // Page with a login form to sign in through before crawling
+	LoginFields     []string // Login form fields as "name=value", such as the username and password
+	LoginSuccess    string   // CSS selector of an element only shown to signed-in users
+	ActiveHours     string   // "HH:MM-HH:MM" of local time the crawl may run in; it pauses outside them (see ActiveWindow)
+	HTTPCacheDir    string   // When set, keep fetched pages here and revalidate them with If-None-Match/If-Modified-Since on later crawls
+	MobileParity    bool     // Also fetch each HTML page as Googlebot Smartphone and record what it was served (see models.MobileVariant)
+
+	// Connections to the crawled sites, shared by all workers
+	MaxConnsPerHost   int  // Connections open to one host at a time (0: no limit)
+	DisableHTTP2      bool // Speak HTTP/1.1 only
+	DisableKeepAlives bool // Open a new connection for every request
 }
 
 // DefaultConfig returns a Config with sensible defaults
@@ -28,20 +78,36 @@ func DefaultConfig() *Config {
 		DomainFilter:  "same",
 		Workers:       10,
 		Delay:         0,
+		AutoThrottle:  true,
 		Timeout:       30 * time.Second,
 		UserAgent:     "barracuda/1.0.0",
 		RespectRobots: true,
 		ParseSitemap:  false,
+		Strategy:      StrategyBFS,
 		ExportFormat:  "csv",
 		ExportPath:    "",
 	}
 }
 
+// StartURLs returns StartURL followed by ExtraStartURLs, or nil when no
+// start URL is set
+func (c *Config) StartURLs() []string {
+	if c.StartURL == "" {
+		return nil
+	}
+	return append([]string{c.StartURL}, c.ExtraStartURLs...)
+}
+
 // Validate checks that the configuration is valid
 func (c *Config) Validate() error {
 	if c.StartURL == "" {
 		return ErrEmptyStartURL
 	}
+	for _, extra := range c.ExtraStartURLs {
+		if extra == "" {
+			return ErrEmptyStartURL
+		}
+	}
 	if c.MaxDepth < 0 {
 		return ErrInvalidMaxDepth
 	}
@@ -51,9 +117,179 @@ func (c *Config) Validate() error {
 	if c.Workers < 1 {
 		return ErrInvalidWorkers
 	}
-	if c.ExportFormat != "csv" && c.ExportFormat != "json" {
-		return ErrInvalidExportFormat
+	if c.ExportFormat == "" {
+		return ErrEmptyExportFormat
+	}
+	if c.MaxRPS < 0 {
+		return ErrInvalidMaxRPS
+	}
+	if c.MaxConnsPerHost < 0 {
+		return ErrInvalidMaxConnsPerHost
+	}
+	if c.VisitedLimit < 0 {
+		return ErrInvalidVisitedLimit
+	}
+	if c.VisitedFPRate < 0 || c.VisitedFPRate >= 1 {
+		return ErrInvalidVisitedFPRate
+	}
+	if _, err := NewURLFilter(c.Include, c.Exclude); err != nil {
+		return err
+	}
+	if c.ScopePath != "" && !strings.HasPrefix(c.ScopePath, "/") {
+		return fmt.Errorf("%w: %q", ErrInvalidScopePath, c.ScopePath)
+	}
+	switch c.Strategy {
+	case "", StrategyBFS, StrategyDFS, StrategyPriority:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidStrategy, c.Strategy)
+	}
+	if since, err := c.SitemapSinceTime(); err != nil {
+		return err
+	} else if !since.IsZero() && !c.ParseSitemap {
+		return ErrSitemapSinceNeedsSitemap
+	}
+	if _, err := c.HostRewriteMap(); err != nil {
+		return err
+	}
+	if _, err := c.MaxBodyBytes(); err != nil {
+		return err
+	}
+	if _, err := c.RequestHeader(); err != nil {
+		return err
+	}
+	if _, err := c.ActiveWindow(); err != nil {
+		return err
+	}
+	if c.LoginURL == "" && (len(c.LoginFields) > 0 || c.LoginSuccess != "") {
+		return ErrLoginURLRequired
+	}
+	if c.LoginURL != "" {
+		if len(c.LoginFields) == 0 || c.LoginSuccess == "" {
+			return ErrIncompleteLogin
+		}
+		if _, err := c.LoginFormValues(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// SitemapSinceTime parses SitemapSince, a date (YYYY-MM-DD, midnight UTC) or
+// an RFC 3339 time. It returns the zero time when SitemapSince is empty.
+func (c *Config) SitemapSinceTime() (time.Time, error) {
+	if c.SitemapSince == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, c.SitemapSince); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidSitemapSince, c.SitemapSince)
+}
+
+// HostRewriteMap parses HostRewrites into the host to connect to, with an
+// optional port, by lowercase host name. It returns nil when none are set.
+func (c *Config) HostRewriteMap() (map[string]string, error) {
+	if len(c.HostRewrites) == 0 {
+		return nil, nil
+	}
+	rewrites := make(map[string]string, len(c.HostRewrites))
+	for _, rewrite := range c.HostRewrites {
+		from, to, ok := strings.Cut(rewrite, "=")
+		from, to = strings.ToLower(strings.TrimSpace(from)), strings.TrimSpace(to)
+		target, err := url.Parse("//" + to)
+		if !ok || from == "" || strings.ContainsAny(from, ":/") || err != nil || target.Host != to || target.Hostname() == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidHostRewrite, rewrite)
+		}
+		rewrites[from] = to
+	}
+	return rewrites, nil
+}
+
+// MaxBodyBytes parses MaxBodySize, a number of bytes with an optional B, KB,
+// MB, or GB suffix (powers of 1024). It returns DefaultMaxBodySize when
+// MaxBodySize is empty, and 0, meaning no limit, for "0".
+func (c *Config) MaxBodyBytes() (int64, error) {
+	size := strings.ToUpper(strings.TrimSpace(c.MaxBodySize))
+	if size == "" {
+		return DefaultMaxBodySize, nil
+	}
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		bytes  int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if strings.HasSuffix(size, unit.suffix) {
+			size, multiplier = strings.TrimSpace(strings.TrimSuffix(size, unit.suffix)), unit.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || n < 0 || n > (1<<62)/multiplier {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMaxBodySize, c.MaxBodySize)
+	}
+	return n * multiplier, nil
+}
+
+// ActiveWindow parses ActiveHours. It returns nil when ActiveHours is empty,
+// meaning the crawl may run at any time.
+func (c *Config) ActiveWindow() (*ActiveWindow, error) {
+	if strings.TrimSpace(c.ActiveHours) == "" {
+		return nil, nil
+	}
+	return ParseActiveWindow(c.ActiveHours)
+}
+
+// LoginFormValues parses LoginFields into the values to fill in on the
+// login form
+func (c *Config) LoginFormValues() (url.Values, error) {
+	values := make(url.Values)
+	for _, field := range c.LoginFields {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidLoginField, field)
+		}
+		values.Add(strings.TrimSpace(name), value)
+	}
+	return values, nil
+}
+
+// RequestHeader combines Headers, Cookies, and BasicAuth into the extra
+// headers for requests to the start URLs' domains. It returns nil when none
+// are set.
+func (c *Config) RequestHeader() (http.Header, error) {
+	if len(c.Headers) == 0 && len(c.Cookies) == 0 && c.BasicAuth == "" {
+		return nil, nil
+	}
+
+	header := make(http.Header)
+	for _, line := range c.Headers {
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidHeader, line)
+		}
+		header.Add(name, strings.TrimSpace(value))
+	}
+
+	cookies := header.Values("Cookie")
+	for _, cookie := range c.Cookies {
+		name, _, ok := strings.Cut(cookie, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCookie, cookie)
+		}
+		cookies = append(cookies, strings.TrimSpace(cookie))
+	}
+	if len(cookies) > 0 {
+		header.Set("Cookie", strings.Join(cookies, "; "))
+	}
+
+	if c.BasicAuth != "" {
+		if !strings.Contains(c.BasicAuth, ":") {
+			return nil, ErrInvalidBasicAuth
+		}
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.BasicAuth)))
+	}
+	return header, nil
+}
@@ -0,0 +1,354 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the config file loaded automatically when present in the working directory
+const DefaultConfigFile = "barracuda.yaml"
+
+// FileConfig represents the contents of a barracuda.yaml config file
+type FileConfig struct {
+	Crawl         CrawlFileConfig        `yaml:"crawl,omitempty"`
+	Schedule      ScheduleFileConfig     `yaml:"schedule,omitempty"`
+	Notifications NotificationFileConfig `yaml:"notifications,omitempty"`
+	Backlinks     BacklinksFileConfig    `yaml:"backlinks,omitempty"`
+	LLM           LLMFileConfig          `yaml:"llm,omitempty"`
+}
+
+// CrawlFileConfig holds crawl settings from the config file.
+// Pointer fields distinguish "not set" from zero values.
+type CrawlFileConfig struct {
+	URL             string   `yaml:"url,omitempty" json:"url,omitempty"`
+	ExtraURLs       []string `yaml:"extra_urls,omitempty" json:"extra_urls,omitempty"` // Further start URLs crawled with url
+	MaxDepth        *int     `yaml:"max_depth,omitempty" json:"max_depth,omitempty"`
+	MaxPages        *int     `yaml:"max_pages,omitempty" json:"max_pages,omitempty"`
+	Workers         *int     `yaml:"workers,omitempty" json:"workers,omitempty"`
+	Delay           string   `yaml:"delay,omitempty" json:"delay,omitempty"` // e.g. "100ms"
+	MaxRPS          *float64 `yaml:"max_rps,omitempty" json:"max_rps,omitempty"`
+	AutoThrottle    *bool    `yaml:"auto_throttle,omitempty" json:"auto_throttle,omitempty"`
+	Timeout         string   `yaml:"timeout,omitempty" json:"timeout,omitempty"` // e.g. "30s"
+	MaxConnsPerHost *int     `yaml:"max_conns_per_host,omitempty" json:"max_conns_per_host,omitempty"`
+	HTTP2           *bool    `yaml:"http2,omitempty" json:"http2,omitempty"`
+	KeepAlive       *bool    `yaml:"keep_alive,omitempty" json:"keep_alive,omitempty"`
+	UserAgent       string   `yaml:"user_agent,omitempty" json:"user_agent,omitempty"`
+	UserAgentPreset string   `yaml:"user_agent_preset,omitempty" json:"user_agent_preset,omitempty"`
+	RespectRobots   *bool    `yaml:"respect_robots,omitempty" json:"respect_robots,omitempty"`
+	ParseSitemap    *bool    `yaml:"parse_sitemap,omitempty" json:"parse_sitemap,omitempty"`
+	SitemapSince    string   `yaml:"sitemap_since,omitempty" json:"sitemap_since,omitempty"` // e.g. "2024-05-01"
+	DomainFilter    string   `yaml:"domain_filter,omitempty" json:"domain_filter,omitempty"`
+	ExportFormat    string   `yaml:"format,omitempty" json:"format,omitempty"`
+	Include         []string `yaml:"include,omitempty" json:"include,omitempty"`
+	Exclude         []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+	ScopePath       string   `yaml:"scope_path,omitempty" json:"scope_path,omitempty"`
+	RespectNofollow *bool    `yaml:"respect_nofollow,omitempty" json:"respect_nofollow,omitempty"`
+	SkipImageCheck  *bool    `yaml:"skip_image_check,omitempty" json:"skip_image_check,omitempty"`
+	SaveHTML        string   `yaml:"save_html,omitempty" json:"save_html,omitempty"`
+	Render          *bool    `yaml:"render,omitempty" json:"render,omitempty"`
+	VisitedLimit    *int     `yaml:"visited_limit,omitempty" json:"visited_limit,omitempty"`
+	VisitedFPRate   *float64 `yaml:"visited_fp_rate,omitempty" json:"visited_fp_rate,omitempty"`
+	LowMemory       *bool    `yaml:"low_memory,omitempty" json:"low_memory,omitempty"`
+	Strategy        string   `yaml:"strategy,omitempty" json:"strategy,omitempty"` // "bfs", "dfs", or "priority"
+	StreamResults   *bool    `yaml:"stream,omitempty" json:"stream,omitempty"`
+	HostRewrites    []string `yaml:"host_rewrite,omitempty" json:"host_rewrite,omitempty"`   // "from=to[:port]" pairs
+	MaxBodySize     string   `yaml:"max_body_size,omitempty" json:"max_body_size,omitempty"` // e.g. "10MB", or "0" for no limit
+	ActiveHours     string   `yaml:"active_hours,omitempty" json:"active_hours,omitempty"`   // e.g. "01:00-06:00"
+	HTTPCache       string   `yaml:"http_cache,omitempty" json:"http_cache,omitempty"`
+	MobileParity    *bool    `yaml:"mobile_parity,omitempty" json:"mobile_parity,omitempty"`
+}
+
+// ScheduleFileConfig holds scheduler settings from the config file
+type ScheduleFileConfig struct {
+	Cron      string           `yaml:"cron,omitempty"`       // 5-field cron expression or descriptor (@daily, @every 6h)
+	OutputDir string           `yaml:"output_dir,omitempty"` // Parent directory for rolling crawl directories
+	Keep      int              `yaml:"keep,omitempty"`       // Number of crawl directories to retain
+	Alerts    AlertsFileConfig `yaml:"alerts,omitempty"`
+}
+
+// AlertsFileConfig sets when a scheduled crawl raises a regression alert
+// against the previous run. Unset fields use the defaults; 0 disables a check.
+type AlertsFileConfig struct {
+	NewErrors     *int     `yaml:"new_errors,omitempty"`     // New error-severity issues that trigger an alert (default 1)
+	IndexableDrop *float64 `yaml:"indexable_drop,omitempty"` // Percent drop in indexable pages that triggers an alert (default 10)
+	RobotsTxt     *bool    `yaml:"robots_txt,omitempty"`     // Whether a robots.txt change triggers an alert (default true)
+}
+
+// NotificationFileConfig holds notification settings from the config file
+type NotificationFileConfig struct {
+	WebhookURL        string          `yaml:"webhook_url,omitempty"`
+	SlackWebhookURL   string          `yaml:"slack_webhook_url,omitempty"`
+	DiscordWebhookURL string          `yaml:"discord_webhook_url,omitempty"`
+	Email             EmailFileConfig `yaml:"email,omitempty"`
+	Desktop           bool            `yaml:"desktop,omitempty"`
+	OnCrawl           string          `yaml:"on_crawl,omitempty"` // When crawl notifies: "always" (default), "failure", or "never"
+}
+
+// When the crawl command sends notifications
+const (
+	NotifyAlways  = "always"
+	NotifyFailure = "failure"
+	NotifyNever   = "never"
+)
+
+// NotifyCrawl reports whether the crawl command should send notifications
+// for a crawl that failed or not
+func (n NotificationFileConfig) NotifyCrawl(failed bool) bool {
+	switch n.OnCrawl {
+	case NotifyFailure:
+		return failed
+	case NotifyNever:
+		return false
+	default:
+		return true
+	}
+}
+
+// BacklinksFileConfig selects a third-party backlink API for referring-domain
+// counts. For moz, api_key is the secret key.
+type BacklinksFileConfig struct {
+	Provider string `yaml:"provider,omitempty"` // "ahrefs", "majestic", or "moz"
+	APIKey   string `yaml:"api_key,omitempty"`
+	AccessID string `yaml:"access_id,omitempty"` // moz only
+}
+
+// LLMFileConfig selects a large language model API for title and meta
+// description rewrite suggestions
+type LLMFileConfig struct {
+	Provider string `yaml:"provider,omitempty"` // "openai", "anthropic", or "gemini"
+	APIKey   string `yaml:"api_key,omitempty"`
+	Model    string `yaml:"model,omitempty"`    // provider default if empty
+	Endpoint string `yaml:"endpoint,omitempty"` // API URL override, e.g. an OpenAI-compatible server
+}
+
+// EmailFileConfig holds SMTP settings for email notifications
+type EmailFileConfig struct {
+	SMTPHost string   `yaml:"smtp_host,omitempty"`
+	SMTPPort int      `yaml:"smtp_port,omitempty"`
+	Username string   `yaml:"username,omitempty"`
+	Password string   `yaml:"password,omitempty"`
+	From     string   `yaml:"from,omitempty"`
+	To       []string `yaml:"to,omitempty"`
+}
+
+// Enabled reports whether enough SMTP settings are present to send email
+func (e EmailFileConfig) Enabled() bool {
+	return e.SMTPHost != "" && e.From != "" && len(e.To) > 0
+}
+
+// LoadFileConfig reads and parses a YAML config file
+func LoadFileConfig(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var fc FileConfig
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	// Environment variable fallbacks keep secrets out of the file
+	if fc.Notifications.Email.Password == "" {
+		fc.Notifications.Email.Password = os.Getenv("BARRACUDA_SMTP_PASSWORD")
+	}
+	if fc.Backlinks.APIKey == "" {
+		fc.Backlinks.APIKey = os.Getenv("BARRACUDA_BACKLINKS_API_KEY")
+	}
+	if fc.LLM.APIKey == "" {
+		fc.LLM.APIKey = os.Getenv("BARRACUDA_LLM_API_KEY")
+	}
+
+	switch fc.Notifications.OnCrawl {
+	case "", NotifyAlways, NotifyFailure, NotifyNever:
+	default:
+		return nil, fmt.Errorf("invalid notifications.on_crawl %q in %s (expected always, failure, or never)", fc.Notifications.OnCrawl, path)
+	}
+
+	return &fc, nil
+}
+
+// ApplyTo copies crawl settings that are set in the file onto cfg
+func (c CrawlFileConfig) ApplyTo(cfg *Config) error {
+	if c.URL != "" {
+		cfg.StartURL = c.URL
+	}
+	if len(c.ExtraURLs) > 0 {
+		cfg.ExtraStartURLs = c.ExtraURLs
+	}
+	if c.MaxDepth != nil {
+		cfg.MaxDepth = *c.MaxDepth
+	}
+	if c.MaxPages != nil {
+		cfg.MaxPages = *c.MaxPages
+	}
+	if c.Workers != nil {
+		cfg.Workers = *c.Workers
+	}
+	if c.Delay != "" {
+		d, err := time.ParseDuration(c.Delay)
+		if err != nil {
+			return fmt.Errorf("invalid crawl.delay: %w", err)
+		}
+		cfg.Delay = d
+	}
+	if c.MaxRPS != nil {
+		cfg.MaxRPS = *c.MaxRPS
+	}
+	if c.AutoThrottle != nil {
+		cfg.AutoThrottle = *c.AutoThrottle
+	}
+	if c.MaxConnsPerHost != nil {
+		cfg.MaxConnsPerHost = *c.MaxConnsPerHost
+	}
+	if c.HTTP2 != nil {
+		cfg.DisableHTTP2 = !*c.HTTP2
+	}
+	if c.KeepAlive != nil {
+		cfg.DisableKeepAlives = !*c.KeepAlive
+	}
+	if c.Timeout != "" {
+		d, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid crawl.timeout: %w", err)
+		}
+		cfg.Timeout = d
+	}
+	if c.UserAgent != "" {
+		cfg.UserAgent = c.UserAgent
+	} else if c.UserAgentPreset != "" {
+		userAgent, err := PresetUserAgent(c.UserAgentPreset)
+		if err != nil {
+			return fmt.Errorf("invalid crawl.user_agent_preset: %w", err)
+		}
+		cfg.UserAgent = userAgent
+	}
+	if c.RespectRobots != nil {
+		cfg.RespectRobots = *c.RespectRobots
+	}
+	if c.ParseSitemap != nil {
+		cfg.ParseSitemap = *c.ParseSitemap
+	}
+	if c.SitemapSince != "" {
+		cfg.SitemapSince = c.SitemapSince
+	}
+	if c.DomainFilter != "" {
+		cfg.DomainFilter = c.DomainFilter
+	}
+	if c.ExportFormat != "" {
+		cfg.ExportFormat = c.ExportFormat
+	}
+	if len(c.Include) > 0 {
+		cfg.Include = c.Include
+	}
+	if len(c.Exclude) > 0 {
+		cfg.Exclude = c.Exclude
+	}
+	if c.ScopePath != "" {
+		cfg.ScopePath = c.ScopePath
+	}
+	if c.RespectNofollow != nil {
+		cfg.RespectNofollow = *c.RespectNofollow
+	}
+	if c.SkipImageCheck != nil {
+		cfg.SkipImageCheck = *c.SkipImageCheck
+	}
+	if c.SaveHTML != "" {
+		cfg.SaveHTMLDir = c.SaveHTML
+	}
+	if c.Render != nil {
+		cfg.Render = *c.Render
+	}
+	if c.VisitedLimit != nil {
+		cfg.VisitedLimit = *c.VisitedLimit
+	}
+	if c.VisitedFPRate != nil {
+		cfg.VisitedFPRate = *c.VisitedFPRate
+	}
+	if c.LowMemory != nil {
+		cfg.LowMemory = *c.LowMemory
+	}
+	if c.Strategy != "" {
+		cfg.Strategy = c.Strategy
+	}
+	if c.StreamResults != nil {
+		cfg.StreamResults = *c.StreamResults
+	}
+	if len(c.HostRewrites) > 0 {
+		cfg.HostRewrites = c.HostRewrites
+	}
+	if c.MaxBodySize != "" {
+		cfg.MaxBodySize = c.MaxBodySize
+	}
+	if c.ActiveHours != "" {
+		cfg.ActiveHours = c.ActiveHours
+	}
+	if c.HTTPCache != "" {
+		cfg.HTTPCacheDir = c.HTTPCache
+	}
+	if c.MobileParity != nil {
+		cfg.MobileParity = *c.MobileParity
+	}
+	return nil
+}
+
+// CrawlFileConfigFrom captures every crawl setting of cfg in config file form
+func CrawlFileConfigFrom(cfg *Config) CrawlFileConfig {
+	http2, keepAlive := !cfg.DisableHTTP2, !cfg.DisableKeepAlives
+	return CrawlFileConfig{
+		URL:             cfg.StartURL,
+		ExtraURLs:       cfg.ExtraStartURLs,
+		MaxDepth:        &cfg.MaxDepth,
+		MaxPages:        &cfg.MaxPages,
+		Workers:         &cfg.Workers,
+		Delay:           cfg.Delay.String(),
+		MaxRPS:          &cfg.MaxRPS,
+		AutoThrottle:    &cfg.AutoThrottle,
+		MaxConnsPerHost: &cfg.MaxConnsPerHost,
+		HTTP2:           &http2,
+		KeepAlive:       &keepAlive,
+		Timeout:         cfg.Timeout.String(),
+		UserAgent:       cfg.UserAgent,
+		RespectRobots:   &cfg.RespectRobots,
+		ParseSitemap:    &cfg.ParseSitemap,
+		SitemapSince:    cfg.SitemapSince,
+		DomainFilter:    cfg.DomainFilter,
+		ExportFormat:    cfg.ExportFormat,
+		Include:         cfg.Include,
+		Exclude:         cfg.Exclude,
+		ScopePath:       cfg.ScopePath,
+		RespectNofollow: &cfg.RespectNofollow,
+		SkipImageCheck:  &cfg.SkipImageCheck,
+		SaveHTML:        cfg.SaveHTMLDir,
+		Render:          &cfg.Render,
+		VisitedLimit:    &cfg.VisitedLimit,
+		VisitedFPRate:   &cfg.VisitedFPRate,
+		LowMemory:       &cfg.LowMemory,
+		Strategy:        cfg.Strategy,
+		StreamResults:   &cfg.StreamResults,
+		HostRewrites:    cfg.HostRewrites,
+		MaxBodySize:     cfg.MaxBodySize,
+		ActiveHours:     cfg.ActiveHours,
+		HTTPCache:       cfg.HTTPCacheDir,
+		MobileParity:    &cfg.MobileParity,
+	}
+}
+
+// SaveFileConfig writes fc to path as YAML, creating parent directories
+func SaveFileConfig(path string, fc *FileConfig) error {
+	data, err := yaml.Marshal(fc)
+	if err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// URLFilter applies include/exclude regular expressions to URLs
+type URLFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewURLFilter compiles include and exclude patterns. A nil filter allows everything.
+func NewURLFilter(include, exclude []string) (*URLFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	f := &URLFilter{}
+	for _, pattern := range include {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		f.include = append(f.include, re)
+	}
+	for _, pattern := range exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+		f.exclude = append(f.exclude, re)
+	}
+
+	return f, nil
+}
+
+// Match reports whether a URL passes the filter and which rule decided it.
+// Exclude rules win over include rules; with include rules present, a URL
+// must match at least one of them.
+func (f *URLFilter) Match(rawURL string) (bool, string) {
+	if f == nil {
+		return true, ""
+	}
+
+	for _, re := range f.exclude {
+		if re.MatchString(rawURL) {
+			return false, "exclude " + re.String()
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true, ""
+	}
+
+	for _, re := range f.include {
+		if re.MatchString(rawURL) {
+			return true, "include " + re.String()
+		}
+	}
+
+	return false, "no include pattern matched"
+}
+
+// Allows reports whether a URL passes the filter
+func (f *URLFilter) Allows(rawURL string) bool {
+	allowed, _ := f.Match(rawURL)
+	return allowed
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"fmt"
+	"os"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -8,26 +11,122 @@ import (
 // Logger is a global logger instance
 var Logger *zap.Logger
 
+// LogOptions controls where and how log entries are written
+type LogOptions struct {
+	Debug  bool   // Log at debug level instead of info
+	Format string // "console" or "json"; empty uses console on the terminal and json in files
+	File   string // Write logs to this file; the terminal then only shows warnings and errors
+
+	Quiet       bool // Only show errors on the terminal
+	SummaryOnly bool // Hide per-page info logs on the terminal, showing warnings and errors
+}
+
+var (
+	// logOptions are the options the current logger was built with
+	logOptions LogOptions
+	// logFile is the open --log-file, if any
+	logFile *os.File
+)
+
 // InitLogger initializes the global logger
 func InitLogger(debug bool) error {
-	var config zap.Config
-	if debug {
-		config = zap.NewDevelopmentConfig()
-		config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
-	} else {
-		config = zap.NewProductionConfig()
-		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
+	return ConfigureLogger(LogOptions{Debug: debug})
+}
+
+// ConfigureLogger builds the global logger from opts
+func ConfigureLogger(opts LogOptions) error {
+	if opts.Format != "" && opts.Format != "console" && opts.Format != "json" {
+		return fmt.Errorf("invalid log format %q: use 'console' or 'json'", opts.Format)
 	}
 
-	logger, err := config.Build(zap.AddCallerSkip(1))
-	if err != nil {
-		return err
+	var file *os.File
+	if opts.File != "" {
+		f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		file = f
 	}
 
-	Logger = logger
+	if logFile != nil {
+		logFile.Close()
+	}
+	logFile = file
+	logOptions = opts
+
+	Logger = zap.New(zapcore.NewTee(baseCores(opts, file, false)...), zap.AddCaller(), zap.AddCallerSkip(1))
 	return nil
 }
 
+// baseCores returns the terminal core and, when set, the --log-file core.
+// The terminal only shows warnings and errors when logs also go to a file.
+func baseCores(opts LogOptions, file *os.File, extraFile bool) []zapcore.Core {
+	level := zapcore.InfoLevel
+	if opts.Debug {
+		level = zapcore.DebugLevel
+	}
+
+	terminalLevel := level
+	if file != nil || extraFile || opts.SummaryOnly {
+		terminalLevel = zapcore.WarnLevel
+	}
+	if opts.Quiet {
+		terminalLevel = zapcore.ErrorLevel
+	}
+
+	cores := []zapcore.Core{
+		zapcore.NewCore(newEncoder(opts.Format, "console"), zapcore.Lock(os.Stderr), terminalLevel),
+	}
+	if file != nil {
+		cores = append(cores, zapcore.NewCore(newEncoder(opts.Format, "json"), zapcore.AddSync(file), level))
+	}
+	return cores
+}
+
+// newEncoder returns a console or JSON encoder, using fallback when format is empty
+func newEncoder(format, fallback string) zapcore.Encoder {
+	if format == "" {
+		format = fallback
+	}
+	if format == "json" {
+		return zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	}
+
+	config := zap.NewDevelopmentEncoderConfig()
+	config.EncodeTime = zapcore.TimeEncoderOfLayout("15:04:05")
+	return zapcore.NewConsoleEncoder(config)
+}
+
+// LogToFile additionally writes all log entries as JSON lines to path (such as
+// a crawl directory's crawl.log), leaving only warnings and errors on the terminal.
+// The returned function closes the file and restores the previous logger.
+func LogToFile(path string) (func(), error) {
+	if Logger == nil {
+		return func() {}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	level := zapcore.InfoLevel
+	if logOptions.Debug {
+		level = zapcore.DebugLevel
+	}
+
+	previous := Logger
+	cores := append(baseCores(logOptions, logFile, true),
+		zapcore.NewCore(newEncoder("json", "json"), zapcore.AddSync(file), level))
+	Logger = zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddCallerSkip(1))
+
+	return func() {
+		Logger.Sync()
+		Logger = previous
+		file.Close()
+	}, nil
+}
+
 // Info logs an info message
 func Info(msg string, fields ...zap.Field) {
 	if Logger != nil {
@@ -78,4 +177,3 @@ func NewField(key string, value interface{}) zap.Field {
 		return zap.Any(key, value)
 	}
 }
-
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// lastAnswersFile stores the most recent interactive answers in the user config directory
+const lastAnswersFile = "last-interactive.yaml"
+
+// profileNamePattern restricts profile names to safe file names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// UserConfigDir returns the per-user barracuda directory (e.g. ~/.config/barracuda)
+func UserConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "barracuda"), nil
+}
+
+// ProfilePath returns the file a named profile is stored in. Profiles use the
+// barracuda.yaml format, so they can also be passed to --config.
+func ProfilePath(name string) (string, error) {
+	if !profileNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name %q: use letters, numbers, '-' and '_'", name)
+	}
+	dir, err := UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles", name+".yaml"), nil
+}
+
+// ListProfiles returns the names of saved profiles in alphabetical order
+func ListProfiles() ([]string, error) {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "profiles"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yaml"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadProfile reads a named profile
+func LoadProfile(name string) (*FileConfig, error) {
+	path, err := ProfilePath(name)
+	if err != nil {
+		return nil, err
+	}
+	return LoadFileConfig(path)
+}
+
+// SaveProfile stores crawl settings under a profile name
+func SaveProfile(name string, cfg *Config) (string, error) {
+	path, err := ProfilePath(name)
+	if err != nil {
+		return "", err
+	}
+	if err := SaveFileConfig(path, &FileConfig{Crawl: CrawlFileConfigFrom(cfg)}); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// loadLastAnswers returns the settings from the previous interactive run, or nil
+func loadLastAnswers() *FileConfig {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return nil
+	}
+	fc, err := LoadFileConfig(filepath.Join(dir, lastAnswersFile))
+	if err != nil {
+		return nil
+	}
+	return fc
+}
+
+// saveLastAnswers remembers interactive answers for the next run
+func saveLastAnswers(cfg *Config) error {
+	dir, err := UserConfigDir()
+	if err != nil {
+		return err
+	}
+	return SaveFileConfig(filepath.Join(dir, lastAnswersFile), &FileConfig{Crawl: CrawlFileConfigFrom(cfg)})
+}
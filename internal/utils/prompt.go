@@ -12,9 +12,12 @@ import (
 	"time"
 )
 
+// stdinReader is shared by all prompts so buffered input isn't lost between them
+var stdinReader = bufio.NewReader(os.Stdin)
+
 // PromptString prompts the user for a string input
 func PromptString(prompt string, defaultValue string, required bool) (string, error) {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 	
 	for {
 		if defaultValue != "" {
@@ -47,7 +50,7 @@ func PromptString(prompt string, defaultValue string, required bool) (string, er
 
 // PromptInt prompts the user for an integer input
 func PromptInt(prompt string, defaultValue int, required bool) (int, error) {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 	
 	for {
 		defaultStr := ""
@@ -91,7 +94,7 @@ func PromptInt(prompt string, defaultValue int, required bool) (int, error) {
 
 // PromptBool prompts the user for a yes/no input
 func PromptBool(prompt string, defaultValue bool) (bool, error) {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 	
 	for {
 		fmt.Printf("%s", prompt)
@@ -127,7 +130,7 @@ func PromptBool(prompt string, defaultValue bool) (bool, error) {
 
 // PromptChoice prompts the user to select from choices
 func PromptChoice(prompt string, choices []string, defaultValue string) (string, error) {
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 	
 	fmt.Printf("%s\n", prompt)
 	for i, choice := range choices {
@@ -235,7 +238,7 @@ func PromptSelect(prompt string, choices []string, defaultValue string) (string,
 
 	// Set terminal to raw mode (simplified - works on Unix)
 	// For cross-platform, we'll try to read escape sequences
-	reader := bufio.NewReader(os.Stdin)
+	reader := stdinReader
 	for {
 		char, _, err := reader.ReadRune()
 		if err != nil {
@@ -283,112 +286,305 @@ func PromptSelect(prompt string, choices []string, defaultValue string) (string,
 	return choices[selected], nil
 }
 
-// PromptInteractive prompts the user for all crawl configuration interactively
+// PromptDuration prompts the user for a duration such as "500ms" or "30s"
+func PromptDuration(prompt string, defaultValue time.Duration) (time.Duration, error) {
+	for {
+		input, err := PromptString(prompt, defaultValue.String(), false)
+		if err != nil {
+			return 0, err
+		}
+
+		d, err := time.ParseDuration(input)
+		if err != nil || d < 0 {
+			fmt.Println("⚠️  Please enter a duration like 500ms, 2s, or 1m. Try again.")
+			continue
+		}
+
+		return d, nil
+	}
+}
+
+// PromptList prompts the user for a comma-separated list of values
+func PromptList(prompt string, defaultValue []string) ([]string, error) {
+	input, err := PromptString(prompt+" (comma-separated, '-' for none)", strings.Join(defaultValue, ","), false)
+	if err != nil {
+		return nil, err
+	}
+	if input == "-" {
+		return nil, nil
+	}
+
+	var values []string
+	for _, v := range strings.Split(input, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// Interactive mode represents "no limit" with very large values
+const (
+	unlimitedPages = 999999
+	unlimitedDepth = 9999
+)
+
+// interactiveDefaults returns the starting answers for interactive mode:
+// the previous run's answers when available, otherwise crawl everything
+func interactiveDefaults() *Config {
+	config := DefaultConfig()
+	config.MaxPages = unlimitedPages
+	config.MaxDepth = unlimitedDepth
+	config.ParseSitemap = true
+	config.ExportFormat = "json"
+
+	if last := loadLastAnswers(); last != nil {
+		remembered := *config
+		if err := last.Crawl.ApplyTo(&remembered); err == nil {
+			return &remembered
+		}
+	}
+	return config
+}
+
+// PromptInteractive prompts the user for all crawl configuration interactively.
+// The quick path asks only for the URL and export settings; the advanced path
+// exposes every crawl and analysis option. Answers are remembered for the next
+// run and can be saved as a named profile.
 func PromptInteractive() (*Config, string, string, bool, error) {
 	fmt.Println()
 	fmt.Println("🐊 Barracuda - Interactive Crawl Setup")
 	fmt.Println("═══════════════════════════════════════")
 	fmt.Println()
-	
+
+	config := interactiveDefaults()
+
+	// Offer saved profiles
+	profiles, err := ListProfiles()
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	if len(profiles) > 0 {
+		choice, err := PromptSelect("Load a saved profile?", append([]string{"(none)"}, profiles...), "(none)")
+		if err != nil {
+			return nil, "", "", false, err
+		}
+		if choice != "(none)" {
+			profile, err := LoadProfile(choice)
+			if err != nil {
+				return nil, "", "", false, err
+			}
+			if err := profile.Crawl.ApplyTo(config); err != nil {
+				return nil, "", "", false, fmt.Errorf("invalid profile %s: %w", choice, err)
+			}
+			fmt.Printf("✓ Loaded profile %s\n\n", choice)
+		}
+	}
+
 	// Get URL
-	urlInput, err := PromptString("What is the URL you want to scan?", "", true)
+	urlInput, err := PromptString("What is the URL you want to scan?", config.StartURL, true)
 	if err != nil {
 		return nil, "", "", false, err
 	}
-	
+
 	// Validate URL
-	_, err = url.Parse(urlInput)
+	parsedURL, err := url.Parse(urlInput)
 	if err != nil {
 		return nil, "", "", false, fmt.Errorf("invalid URL: %w", err)
 	}
-	
+	config.StartURL = urlInput
+
 	// Extract domain for directory naming
-	parsedURL, _ := url.Parse(urlInput)
 	domain := parsedURL.Hostname()
 	if domain == "" {
 		domain = "unknown"
 	}
-	
+
 	// Create crawl directory
 	timestamp := time.Now().Format("2006-01-02_15-04-05")
 	crawlDir := fmt.Sprintf("crawls/%s_%s", domain, timestamp)
-	
+
 	if err := os.MkdirAll(crawlDir, 0755); err != nil {
 		return nil, "", "", false, fmt.Errorf("failed to create crawl directory: %w", err)
 	}
-	
+
 	fmt.Printf("\n📁 Results will be saved to: %s/\n\n", crawlDir)
-	
-	// Default to unlimited pages (no prompt)
-	maxPages := 0 // Will be set to 999999 for "unlimited"
-	
-	// Default to unlimited depth (no prompt)
-	maxDepth := 9999 // Very large number for "unlimited"
-	
-	// Default to 10 workers (no prompt)
-	workers := 10
-	
-	// Get export format (using arrow key selection, default to JSON)
-	format, err := PromptSelect("Export format?", []string{"json", "csv"}, "json")
+
+	// Get export format (using arrow key selection)
+	format, err := PromptSelect("Export format?", []string{"json", "csv"}, config.ExportFormat)
 	if err != nil {
 		return nil, "", "", false, err
 	}
-	
+	config.ExportFormat = format
+
 	// Get export path
 	exportFilename := fmt.Sprintf("results.%s", format)
-	exportPath := filepath.Join(crawlDir, exportFilename)
-	
+	config.ExportPath = filepath.Join(crawlDir, exportFilename)
+
 	// Ask if they want to customize export path
 	customPath, err := PromptBool("Use custom export filename?", false)
 	if err != nil {
 		return nil, "", "", false, err
 	}
-	
+
 	if customPath {
 		customFilename, err := PromptString("Export filename", exportFilename, false)
 		if err != nil {
 			return nil, "", "", false, err
 		}
 		if customFilename != "" {
-			exportPath = filepath.Join(crawlDir, customFilename)
+			config.ExportPath = filepath.Join(crawlDir, customFilename)
 		}
 	}
-	
-	// Advanced options
-	fmt.Println("\n📋 Advanced Options:")
-	
-	// Default to respecting robots.txt (no prompt)
-	respectRobots := true
-	
-	// Default to parsing sitemap.xml (no prompt)
-	parseSitemap := true
-	
-	// Always export link graph (no prompt)
+
+	// Link graph and browser are on by default
 	graphExport := filepath.Join(crawlDir, "graph.json")
-	
-	// Always open browser after crawl (no prompt)
 	openBrowser := true
-	
-	// Set maxPages to unlimited if 0
-	if maxPages == 0 {
-		maxPages = 999999 // Very large number for "unlimited"
+
+	mode, err := PromptSelect("Setup mode?", []string{"quick", "advanced"}, "quick")
+	if err != nil {
+		return nil, "", "", false, err
 	}
-	
-	// Build config
-	config := &Config{
-		StartURL:      urlInput,
-		MaxDepth:      maxDepth,
-		MaxPages:      maxPages,
-		Workers:       workers,
-		Delay:         0,
-		Timeout:       30 * time.Second,
-		UserAgent:     "barracuda/1.0.0",
-		RespectRobots: respectRobots,
-		ParseSitemap:  parseSitemap,
-		ExportFormat:  format,
-		ExportPath:    exportPath,
-		DomainFilter:  "same",
+
+	if mode == "advanced" {
+		var exportGraph bool
+		exportGraph, openBrowser, err = promptAdvanced(config)
+		if err != nil {
+			return nil, "", "", false, err
+		}
+		if !exportGraph {
+			graphExport = ""
+		}
 	}
-	
+
+	if err := config.Validate(); err != nil {
+		return nil, "", "", false, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Remember answers for next time (best-effort)
+	if err := saveLastAnswers(config); err != nil {
+		Debug("Failed to save interactive answers", NewField("error", err.Error()))
+	}
+
+	if mode == "advanced" {
+		if err := promptSaveProfile(config); err != nil {
+			return nil, "", "", false, err
+		}
+	}
+
 	return config, graphExport, crawlDir, openBrowser, nil
 }
+
+// promptAdvanced asks for every crawl and analysis option, updating config in
+// place. It returns whether to export the link graph and open the browser.
+func promptAdvanced(config *Config) (bool, bool, error) {
+	fmt.Println("\n📋 Advanced Options:")
+
+	// Limits (0 = unlimited)
+	defaultPages := config.MaxPages
+	if defaultPages >= unlimitedPages {
+		defaultPages = 0
+	}
+	maxPages, err := PromptInt("Maximum pages to crawl (empty = unlimited)", defaultPages, false)
+	if err != nil {
+		return false, false, err
+	}
+	if maxPages <= 0 {
+		maxPages = unlimitedPages
+	}
+	config.MaxPages = maxPages
+
+	defaultDepth := config.MaxDepth
+	if defaultDepth >= unlimitedDepth {
+		defaultDepth = 0
+	}
+	maxDepth, err := PromptInt("Maximum crawl depth (empty = unlimited)", defaultDepth, false)
+	if err != nil {
+		return false, false, err
+	}
+	if maxDepth <= 0 {
+		maxDepth = unlimitedDepth
+	}
+	config.MaxDepth = maxDepth
+
+	// Throughput
+	if config.Workers, err = PromptInt("Concurrent workers", config.Workers, true); err != nil {
+		return false, false, err
+	}
+	if config.Delay, err = PromptDuration("Delay between requests", config.Delay); err != nil {
+		return false, false, err
+	}
+	if config.Timeout, err = PromptDuration("HTTP request timeout", config.Timeout); err != nil {
+		return false, false, err
+	}
+	if config.UserAgent, err = PromptString("User agent", config.UserAgent, true); err != nil {
+		return false, false, err
+	}
+
+	// Scope
+	if config.RespectRobots, err = PromptBool("Respect robots.txt?", config.RespectRobots); err != nil {
+		return false, false, err
+	}
+	if config.ParseSitemap, err = PromptBool("Seed the crawl from sitemap.xml?", config.ParseSitemap); err != nil {
+		return false, false, err
+	}
+	if config.DomainFilter, err = PromptSelect("Which domains should be crawled?", []string{"same", "all"}, config.DomainFilter); err != nil {
+		return false, false, err
+	}
+	for {
+		if config.Include, err = PromptList("Only crawl URLs matching regex", config.Include); err != nil {
+			return false, false, err
+		}
+		if config.Exclude, err = PromptList("Skip URLs matching regex", config.Exclude); err != nil {
+			return false, false, err
+		}
+		if _, err := NewURLFilter(config.Include, config.Exclude); err != nil {
+			fmt.Printf("⚠️  %v. Try again.\n", err)
+			continue
+		}
+		break
+	}
+
+	// Analysis and output
+	checkImages, err := PromptBool("Check image file sizes (slower)?", !config.SkipImageCheck)
+	if err != nil {
+		return false, false, err
+	}
+	config.SkipImageCheck = !checkImages
+	if config.Render, err = PromptBool("Render pages in headless Chrome for JavaScript-built sites (slower)?", config.Render); err != nil {
+		return false, false, err
+	}
+
+	exportGraph, err := PromptBool("Export link graph?", true)
+	if err != nil {
+		return false, false, err
+	}
+	openBrowser, err := PromptBool("Open the dashboard when the crawl finishes?", true)
+	if err != nil {
+		return false, false, err
+	}
+
+	return exportGraph, openBrowser, nil
+}
+
+// promptSaveProfile offers to store the chosen settings as a named profile
+func promptSaveProfile(config *Config) error {
+	save, err := PromptBool("Save these settings as a profile?", false)
+	if err != nil || !save {
+		return err
+	}
+
+	for {
+		name, err := PromptString("Profile name", "", true)
+		if err != nil {
+			return err
+		}
+		path, err := SaveProfile(name, config)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			continue
+		}
+		fmt.Printf("✓ Profile saved to %s (use it with --config or --profile %s)\n", path, name)
+		return nil
+	}
+}
@@ -12,7 +12,25 @@ var (
 	ErrInvalidMaxDepth = errors.New("max depth must be non-negative")
 	ErrInvalidMaxPages = errors.New("max pages must be at least 1")
 	ErrInvalidWorkers  = errors.New("workers must be at least 1")
-	ErrInvalidExportFormat = errors.New("export format must be 'csv' or 'json'")
+	ErrEmptyExportFormat = errors.New("export format cannot be empty")
+	ErrInvalidVisitedLimit = errors.New("visited limit must be non-negative")
+	ErrInvalidVisitedFPRate = errors.New("visited false-positive rate must be between 0 and 1")
+	ErrInvalidMaxRPS = errors.New("max requests per second must be non-negative")
+	ErrInvalidMaxConnsPerHost = errors.New("max connections per host must be non-negative")
+	ErrInvalidHeader = errors.New("header must be \"Name: Value\"")
+	ErrInvalidCookie = errors.New("cookie must be \"name=value\"")
+	ErrInvalidBasicAuth = errors.New("basic auth must be \"user:password\"")
+	ErrInvalidLoginField = errors.New("login field must be \"name=value\"")
+	ErrLoginURLRequired = errors.New("login fields and success selector need a login URL")
+	ErrIncompleteLogin = errors.New("login needs at least one login field and a success selector")
+	ErrInvalidScopePath = errors.New("scope path must start with \"/\"")
+	ErrInvalidStrategy = errors.New("strategy must be bfs, dfs, or priority")
+	ErrInvalidSitemapSince = errors.New("sitemap since must be a date (YYYY-MM-DD) or an RFC 3339 time")
+	ErrSitemapSinceNeedsSitemap = errors.New("sitemap since needs sitemap parsing")
+	ErrInvalidHostRewrite = errors.New("host rewrite must be \"from=to\" host names, with an optional port on to")
+	ErrInvalidMaxBodySize = errors.New("max body size must be a number of bytes, optionally with a KB, MB, or GB suffix")
+	ErrInvalidUserAgentPreset = errors.New("user agent preset must be default, googlebot, googlebot-smartphone, bingbot, or mobile")
+	ErrInvalidActiveHours = errors.New("active hours must be a daily window of local time as \"HH:MM-HH:MM\", such as 01:00-06:00")
 )
 
 // NormalizeURL normalizes a URL by removing fragments and trailing slashes
@@ -50,7 +68,11 @@ func IsSameDomain(url1, url2 string) bool {
 	if err1 != nil || err2 != nil {
 		return false
 	}
-	
+	return IsSameHost(domain1, domain2)
+}
+
+// IsSameHost compares two hosts the way IsSameDomain compares URLs
+func IsSameHost(domain1, domain2 string) bool {
 	// Exact match
 	if domain1 == domain2 {
 		return true
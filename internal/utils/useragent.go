@@ -0,0 +1,59 @@
+package utils
+
+import "fmt"
+
+// UserAgentPreset is a user agent a crawl can present itself as
+type UserAgentPreset struct {
+	UserAgent string
+	// RobotsAgent is the name robots.txt groups address the crawler by, or ""
+	// to match groups against UserAgent itself
+	RobotsAgent string
+}
+
+// UserAgentPresets are the user agents selectable by name with
+// --user-agent-preset, to see a site the way a search engine or a phone does
+var UserAgentPresets = map[string]UserAgentPreset{
+	"default": {UserAgent: "barracuda/1.0.0"},
+	"googlebot": {
+		UserAgent:   "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		RobotsAgent: "Googlebot",
+	},
+	"bingbot": {
+		UserAgent:   "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+		RobotsAgent: "bingbot",
+	},
+	"googlebot-smartphone": {
+		UserAgent:   "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.6478.126 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+		RobotsAgent: "Googlebot",
+	},
+	"mobile": {
+		UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+	},
+}
+
+// MobilePreset is the preset crawl --mobile uses, and the one each page's
+// mobile variant is fetched as with --mobile-parity: the crawler Google
+// indexes most sites with
+const MobilePreset = "googlebot-smartphone"
+
+// PresetUserAgent returns the user agent string of the named preset
+func PresetUserAgent(name string) (string, error) {
+	preset, ok := UserAgentPresets[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUserAgentPreset, name)
+	}
+	return preset.UserAgent, nil
+}
+
+// RobotsAgent returns the name to match robots.txt groups against for a
+// user agent. Search engine user agents are full browser-style strings that
+// only mention the crawler's name, such as Googlebot, part way through, so
+// they would otherwise fall through to the "*" group.
+func RobotsAgent(userAgent string) string {
+	for _, preset := range UserAgentPresets {
+		if preset.UserAgent == userAgent && preset.RobotsAgent != "" {
+			return preset.RobotsAgent
+		}
+	}
+	return userAgent
+}
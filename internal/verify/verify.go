@@ -0,0 +1,223 @@
+// Package verify checks that a user controls a domain before the hosted
+// crawler is pointed at it, through a DNS TXT record on the domain or a meta
+// tag on its home page.
+package verify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// Verification methods
+const (
+	MethodDNS  = "dns"  // TXT record on the domain; covers its subdomains
+	MethodMeta = "meta" // Meta tag on the home page; covers the domain and its www host
+)
+
+const (
+	// MetaName is the name of the meta tag holding the token
+	MetaName = "barracuda-verification"
+
+	// TXTPrefix starts the value of the TXT record holding the token
+	TXTPrefix = MetaName + "="
+
+	// homePageLimit is how much of the home page is read looking for the
+	// meta tag
+	homePageLimit = 1 << 20
+
+	// checkTimeout bounds each DNS lookup and home page request
+	checkTimeout = 10 * time.Second
+)
+
+var (
+	ErrInvalidMethod = errors.New("verification method must be \"dns\" or \"meta\"")
+	ErrInvalidDomain = errors.New("invalid domain")
+	ErrTokenNotFound = errors.New("verification token not found")
+)
+
+// client fetches home pages for meta tag verification. It only follows
+// redirects that stay on the home page's host or its www twin, since a tag
+// found anywhere else proves nothing about the domain.
+var client = &http.Client{
+	Timeout:       checkTimeout,
+	CheckRedirect: checkRedirect,
+}
+
+// NewToken returns a random verification token
+func NewToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// TXTRecord returns the TXT record value that verifies token
+func TXTRecord(token string) string {
+	return TXTPrefix + token
+}
+
+// MetaTag returns the meta tag that verifies token
+func MetaTag(token string) string {
+	return fmt.Sprintf(`<meta name="%s" content="%s">`, MetaName, token)
+}
+
+// Domain reduces a project domain, which may be written as a URL, to its
+// lowercase host name without a leading "www."
+func Domain(raw string) string {
+	raw = strings.TrimSpace(strings.ToLower(raw))
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSuffix(u.Hostname(), "."), "www.")
+}
+
+// Covers reports whether a domain verified with method lets the crawler
+// fetch host: DNS verification covers every subdomain, while a meta tag only
+// proves control of the home page's host and its www twin
+func Covers(method, domain, host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if domain == "" || host == "" {
+		return false
+	}
+	if host == domain || host == "www."+domain {
+		return true
+	}
+	return method == MethodDNS && strings.HasSuffix(host, "."+domain)
+}
+
+// Check looks for token on domain with method, returning ErrTokenNotFound
+// (wrapped with what was checked) when it isn't there
+func Check(ctx context.Context, method, domain, token string) error {
+	if domain == "" || strings.ContainsAny(domain, "/:@ ") {
+		return fmt.Errorf("%w: %q", ErrInvalidDomain, domain)
+	}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	switch method {
+	case MethodDNS:
+		return checkDNS(ctx, domain, token)
+	case MethodMeta:
+		return checkMeta(ctx, domain, token)
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMethod, method)
+	}
+}
+
+// checkDNS looks for the token's TXT record on domain
+func checkDNS(ctx context.Context, domain, token string) error {
+	records, err := net.DefaultResolver.LookupTXT(ctx, domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return fmt.Errorf("%w: no TXT records on %s", ErrTokenNotFound, domain)
+		}
+		return fmt.Errorf("failed to look up TXT records of %s: %w", domain, err)
+	}
+	want := TXTRecord(token)
+	for _, record := range records {
+		if strings.TrimSpace(record) == want {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no TXT record %q on %s", ErrTokenNotFound, want, domain)
+}
+
+// checkMeta looks for the token's meta tag on the domain's home page, over
+// HTTPS and then plain HTTP
+func checkMeta(ctx context.Context, domain, token string) error {
+	var lastErr error
+	for _, scheme := range []string{"https", "http"} {
+		homePage := scheme + "://" + domain + "/"
+		content, err := fetchMeta(ctx, homePage)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if content == token {
+			return nil
+		}
+		if content == "" {
+			return fmt.Errorf("%w: no %s meta tag on %s", ErrTokenNotFound, MetaName, homePage)
+		}
+		return fmt.Errorf("%w: the %s meta tag on %s has a different token", ErrTokenNotFound, MetaName, homePage)
+	}
+	return lastErr
+}
+
+// checkRedirect allows a home page redirect to the same host, on any scheme,
+// or to its www twin, up to 10 of them
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	from := strings.TrimPrefix(strings.ToLower(via[0].URL.Host), "www.")
+	to := strings.TrimPrefix(strings.ToLower(req.URL.Host), "www.")
+	if from != to {
+		return fmt.Errorf("redirected to another host, %s", req.URL.Host)
+	}
+	return nil
+}
+
+// fetchMeta returns the content of the verification meta tag on a page, or
+// "" when it has none
+func fetchMeta(ctx context.Context, pageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "barracuda/1.0.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch %s: HTTP %d", pageURL, resp.StatusCode)
+	}
+
+	tokenizer := html.NewTokenizer(io.LimitReader(resp.Body, homePageLimit))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return "", nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tag := tokenizer.Token()
+			if tag.Data == "body" {
+				return "", nil
+			}
+			if tag.Data != "meta" {
+				continue
+			}
+			var name, content string
+			for _, attr := range tag.Attr {
+				switch strings.ToLower(attr.Key) {
+				case "name":
+					name = strings.ToLower(strings.TrimSpace(attr.Val))
+				case "content":
+					content = strings.TrimSpace(attr.Val)
+				}
+			}
+			if name == MetaName {
+				return content, nil
+			}
+		}
+	}
+}
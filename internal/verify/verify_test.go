@@ -0,0 +1,45 @@
+package verify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchMetaRedirects(t *testing.T) {
+	const token = "0123456789abcdef"
+	tagged := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head>` + MetaTag(token) + `</head><body></body></html>`))
+	}
+
+	// The other server carries the tag too, as a site the user doesn't
+	// control could
+	other := httptest.NewServer(http.HandlerFunc(tagged))
+	defer other.Close()
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/away":
+			http.Redirect(w, r, other.URL+"/", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/", http.StatusMovedPermanently)
+		default:
+			tagged(w, r)
+		}
+	}))
+	defer site.Close()
+
+	content, err := fetchMeta(context.Background(), site.URL+"/moved")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != token {
+		t.Errorf("meta tag content %q after a redirect on the same host, want %q", content, token)
+	}
+
+	content, err = fetchMeta(context.Background(), site.URL+"/away")
+	if err == nil {
+		t.Errorf("followed a redirect to another host and read %q", content)
+	}
+}
@@ -0,0 +1,95 @@
+// Package wayback looks up archived copies of pages in the Internet
+// Archive's Wayback Machine.
+package wayback
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultEndpoint is the Wayback Machine availability API
+const DefaultEndpoint = "https://archive.org/wayback/available"
+
+// timestampLayout is the format of Wayback Machine snapshot timestamps
+const timestampLayout = "20060102150405"
+
+// Snapshot is an archived copy of a page
+type Snapshot struct {
+	URL       string    `json:"url"`
+	Timestamp time.Time `json:"timestamp"`
+	Status    string    `json:"status"` // HTTP status the page had when archived
+}
+
+// Client queries the availability API
+type Client struct {
+	Endpoint   string
+	HTTPClient *http.Client
+	UserAgent  string
+}
+
+// NewClient creates a client for the public availability API
+func NewClient(timeout time.Duration, userAgent string) *Client {
+	return &Client{
+		Endpoint:   DefaultEndpoint,
+		HTTPClient: &http.Client{Timeout: timeout},
+		UserAgent:  userAgent,
+	}
+}
+
+// availabilityResponse is the body returned by the availability API
+type availabilityResponse struct {
+	ArchivedSnapshots struct {
+		Closest *struct {
+			Available bool   `json:"available"`
+			URL       string `json:"url"`
+			Timestamp string `json:"timestamp"`
+			Status    string `json:"status"`
+		} `json:"closest"`
+	} `json:"archived_snapshots"`
+}
+
+// Latest returns the most recent archived snapshot of pageURL, or nil if the
+// Wayback Machine has none
+func (c *Client) Latest(ctx context.Context, pageURL string) (*Snapshot, error) {
+	query := url.Values{}
+	query.Set("url", pageURL)
+	// A timestamp in the future makes "closest" the newest snapshot
+	query.Set("timestamp", time.Now().UTC().Format(timestampLayout))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.Endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Wayback Machine: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Wayback Machine returned HTTP %d", resp.StatusCode)
+	}
+
+	var body availabilityResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode Wayback Machine response: %w", err)
+	}
+
+	closest := body.ArchivedSnapshots.Closest
+	if closest == nil || !closest.Available || closest.URL == "" {
+		return nil, nil
+	}
+	snapshot := &Snapshot{URL: closest.URL, Status: closest.Status}
+	if t, err := time.Parse(timestampLayout, closest.Timestamp); err == nil {
+		snapshot.Timestamp = t
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,277 @@
+// Package barracuda embeds the barracuda SEO crawler in other Go programs.
+//
+// A typical program crawls a site, analyzes the pages, and exports them:
+//
+//	result, err := barracuda.Crawl(ctx, barracuda.CrawlOptions{
+//		URL:      "https://example.com",
+//		MaxPages: 200,
+//	})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	summary := barracuda.Analyze(result.Pages, barracuda.AnalyzeOptions{})
+//	fmt.Println(summary.TotalIssues, "issues")
+//
+//	err = barracuda.Export(os.Stdout, result.Pages, barracuda.ExportOptions{Format: barracuda.FormatJSON})
+//
+// The functions in this package and the types it exposes are kept backward
+// compatible; everything under internal/ may change between releases.
+package barracuda
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dillonlara115/barracuda/internal/analyzer"
+	"github.com/dillonlara115/barracuda/internal/crawler"
+	"github.com/dillonlara115/barracuda/internal/exporter"
+	"github.com/dillonlara115/barracuda/internal/utils"
+	"github.com/dillonlara115/barracuda/pkg/models"
+)
+
+// Page is the SEO data extracted from one crawled URL
+type Page = models.PageResult
+
+// Summary is the result of analyzing crawled pages
+type Summary = analyzer.Summary
+
+// Issue is a single SEO problem found on a page
+type Issue = models.Issue
+
+// IssueType identifies the kind of SEO problem
+type IssueType = models.IssueType
+
+// Severity is how serious an issue is: error, warning, or info
+type Severity = models.Severity
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// CrawlOptions configures Crawl. Zero values use the same defaults as the CLI.
+type CrawlOptions struct {
+	URL             string        // Start URL (required unless URLs is set)
+	URLs            []string      // List mode: crawl exactly these URLs without following links
+	ExtraURLs       []string      // More start URLs crawled with URL, sharing its visited set and domain scope
+	MaxDepth        int           // Default 3
+	MaxPages        int           // Default 1000, or len(URLs) in list mode
+	Workers         int           // Default 10
+	Delay           time.Duration // Delay between requests per worker
+	Timeout         time.Duration // HTTP request timeout, default 30s
+	UserAgent       string        // Default "barracuda/1.0.0"
+	UserAgentPreset string        // "default", "googlebot", "googlebot-smartphone", "bingbot", or "mobile"; ignored when UserAgent is set
+	IgnoreRobots    bool          // Crawl URLs disallowed by robots.txt
+	RespectNofollow bool          // Don't follow nofollow, sponsored, or ugc links or the links of meta nofollow pages
+	ParseSitemap    bool          // Seed the crawl from sitemap.xml
+	SitemapSince    time.Time     // With ParseSitemap, only seed URLs whose <lastmod> is at or after this
+	AllDomains      bool          // Follow links to other domains
+	Include         []string      // Only crawl URLs matching these regular expressions
+	Exclude         []string      // Never crawl URLs matching these regular expressions
+	ScopePath       string        // Only crawl URLs whose path starts with this prefix, e.g. "/docs/"
+	SaveHTMLDir     string        // Save raw page bodies and an index.json manifest here
+	HTTPCacheDir    string        // Keep pages here and revalidate them on later crawls, reusing those answered 304 Not Modified
+	MobileParity    bool          // Also fetch each page as Googlebot Smartphone and flag differences from the desktop version
+	VisitedLimit    int           // Track this many visited URLs exactly, then use a bloom filter (0: no limit)
+	LowMemory       bool          // Keep visited URLs as hashes and fewer queued URLs in memory
+	Strategy        string        // Crawl order: "bfs" (default), "dfs", or "priority"
+	MaxBodySize     int64         // Bytes of a page body read before it is cut off (0: 10 MB, negative: no limit)
+	ActiveHours     string        // Daily window of local time to crawl in, e.g. "01:00-06:00"; the crawl pauses outside it
+	MaxConnsPerHost int           // Connections open to one host at a time, shared by all workers (0: no limit)
+	DisableHTTP2    bool          // Speak HTTP/1.1 only, even to servers that offer HTTP/2
+
+	// HostRewrites fetches the URLs on each key's host from the value's
+	// host[:port] instead, keeping the key in the Host header, e.g. to crawl a
+	// staging server whose links use production URLs.
+	HostRewrites map[string]string
+
+	// Progress, if set, is called from crawl workers after each page is fetched.
+	// It must be safe for concurrent use.
+	Progress func(page *Page, pagesCrawled int)
+
+	// OnIssue, if set, is called from crawl workers for each issue found on a
+	// page, before Progress is called for the page. Pages are analyzed as they
+	// are crawled, without image size checks. It must be safe for concurrent
+	// use.
+	OnIssue func(page *Page, issue Issue)
+
+	// OnComplete, if set, is called once when the crawl ends, with the pages
+	// fetched and the crawl error, if any
+	OnComplete func(pages []*Page, err error)
+}
+
+// CrawlResult holds the pages and link graph of a finished crawl
+type CrawlResult struct {
+	Pages     []*Page
+	LinkGraph map[string][]string // Source URL to the internal URLs it links to
+	Nofollow  map[string][]string // The LinkGraph edges that are nofollow links, by source URL
+}
+
+// Crawl crawls a site and returns every fetched page. Cancelling ctx stops the
+// crawl early; the pages fetched so far are returned without an error.
+func Crawl(ctx context.Context, opts CrawlOptions) (*CrawlResult, error) {
+	config := utils.DefaultConfig()
+	config.StartURL = opts.URL
+	config.ExtraStartURLs = opts.ExtraURLs
+	config.URLList = opts.URLs
+	if config.StartURL == "" && len(opts.URLs) > 0 {
+		config.StartURL = opts.URLs[0]
+	}
+	if len(opts.URLs) > 0 && opts.MaxPages == 0 {
+		config.MaxPages = len(opts.URLs)
+	}
+	if opts.MaxDepth > 0 {
+		config.MaxDepth = opts.MaxDepth
+	}
+	if opts.MaxPages > 0 {
+		config.MaxPages = opts.MaxPages
+	}
+	if opts.Workers > 0 {
+		config.Workers = opts.Workers
+	}
+	if opts.Timeout > 0 {
+		config.Timeout = opts.Timeout
+	}
+	if opts.UserAgent != "" {
+		config.UserAgent = opts.UserAgent
+	} else if opts.UserAgentPreset != "" {
+		userAgent, err := utils.PresetUserAgent(opts.UserAgentPreset)
+		if err != nil {
+			return nil, err
+		}
+		config.UserAgent = userAgent
+	}
+	if opts.AllDomains {
+		config.DomainFilter = "all"
+	}
+	config.Delay = opts.Delay
+	config.RespectRobots = !opts.IgnoreRobots
+	config.ParseSitemap = opts.ParseSitemap
+	if !opts.SitemapSince.IsZero() {
+		config.SitemapSince = opts.SitemapSince.Format(time.RFC3339)
+	}
+	config.Include = opts.Include
+	config.Exclude = opts.Exclude
+	config.ScopePath = opts.ScopePath
+	config.RespectNofollow = opts.RespectNofollow
+	config.SaveHTMLDir = opts.SaveHTMLDir
+	config.HTTPCacheDir = opts.HTTPCacheDir
+	config.MobileParity = opts.MobileParity
+	config.VisitedLimit = opts.VisitedLimit
+	config.LowMemory = opts.LowMemory
+	if opts.Strategy != "" {
+		config.Strategy = opts.Strategy
+	}
+	if opts.MaxBodySize > 0 {
+		config.MaxBodySize = strconv.FormatInt(opts.MaxBodySize, 10)
+	} else if opts.MaxBodySize < 0 {
+		config.MaxBodySize = "0"
+	}
+	config.ActiveHours = opts.ActiveHours
+	config.MaxConnsPerHost = opts.MaxConnsPerHost
+	config.DisableHTTP2 = opts.DisableHTTP2
+	for from, to := range opts.HostRewrites {
+		config.HostRewrites = append(config.HostRewrites, from+"="+to)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid crawl options: %w", err)
+	}
+
+	manager := crawler.NewManager(config)
+	if opts.Progress != nil {
+		manager.OnPageCrawled(opts.Progress)
+	}
+	if opts.OnIssue != nil {
+		manager.SetAnalyzer(analyzer.NewIncremental())
+		manager.OnIssueFound(opts.OnIssue)
+	}
+	if opts.OnComplete != nil {
+		manager.OnCrawlComplete(opts.OnComplete)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			manager.Stop()
+		case <-done:
+		}
+	}()
+
+	pages, err := manager.Crawl()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CrawlResult{
+		Pages:     pages,
+		LinkGraph: manager.GetLinkGraph().GetAllEdges(),
+		Nofollow:  manager.GetLinkGraph().GetNofollowEdges(),
+	}, nil
+}
+
+// AnalyzeOptions configures Analyze
+type AnalyzeOptions struct {
+	// CheckImages fetches each image to flag oversized files. It makes one
+	// request per unique image, so it is off by default.
+	CheckImages  bool
+	ImageTimeout time.Duration // Per-image request timeout, default 10s
+}
+
+// Analyze detects SEO issues in crawled pages
+func Analyze(pages []*Page, opts AnalyzeOptions) *Summary {
+	if !opts.CheckImages {
+		return analyzer.Analyze(pages)
+	}
+
+	imageTimeout := opts.ImageTimeout
+	if imageTimeout <= 0 {
+		imageTimeout = 10 * time.Second
+	}
+	return analyzer.AnalyzeWithImages(pages, imageTimeout)
+}
+
+// ExportOptions configures Export and ExportFile
+type ExportOptions struct {
+	Format string // FormatCSV (default) or FormatJSON
+	Pretty bool   // Indent JSON output
+}
+
+// Export writes pages to w as CSV or JSON
+func Export(w io.Writer, pages []*Page, opts ExportOptions) error {
+	switch opts.Format {
+	case "", FormatCSV:
+		return exporter.WriteCSV(w, pages)
+	case FormatJSON:
+		return exporter.WriteJSON(w, pages, opts.Pretty)
+	default:
+		return fmt.Errorf("unsupported export format %q: use %q or %q", opts.Format, FormatCSV, FormatJSON)
+	}
+}
+
+// ExportFile writes pages to a CSV or JSON file at path
+func ExportFile(path string, pages []*Page, opts ExportOptions) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer file.Close()
+
+	if err := Export(file, pages, opts); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Import reads pages from a CSV or JSON results file written by Export or the CLI
+func Import(path string) ([]*Page, error) {
+	return exporter.ImportResults(path)
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicy is how a response may be cached, from its Cache-Control,
+// Expires, Age, and validator headers
+type CachePolicy struct {
+	CacheControl string `json:"cache_control,omitempty"`
+	Expires      string `json:"expires,omitempty"`
+	Age          string `json:"age,omitempty"`
+	NoStore      bool   `json:"no_store,omitempty"`
+	NoCache      bool   `json:"no_cache,omitempty"` // Must revalidate before every use
+	Private      bool   `json:"private,omitempty"`
+	// TTL is how long the response stays fresh in a browser cache, or nil
+	// when no header sets it
+	TTL *time.Duration `json:"ttl,omitempty"`
+	// HasValidator is whether an ETag or Last-Modified header allows
+	// conditional requests
+	HasValidator bool `json:"has_validator,omitempty"`
+}
+
+// ParseCachePolicy reads the caching headers of a response. Header names are
+// in canonical form, as http.Header stores them.
+func ParseCachePolicy(headers map[string]string) CachePolicy {
+	policy := CachePolicy{
+		CacheControl: headers["Cache-Control"],
+		Expires:      headers["Expires"],
+		Age:          headers["Age"],
+		HasValidator: headers["Etag"] != "" || headers["Last-Modified"] != "",
+	}
+
+	for _, directive := range strings.Split(policy.CacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store":
+			policy.NoStore = true
+		case "no-cache":
+			policy.NoCache = true
+		case "private":
+			policy.Private = true
+		case "max-age":
+			// max-age wins over Expires
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				ttl := time.Duration(seconds) * time.Second
+				policy.TTL = &ttl
+			}
+		}
+	}
+
+	if policy.TTL == nil && policy.Expires != "" {
+		// An invalid Expires, such as "0", means already expired
+		ttl := time.Duration(0)
+		if expires, err := http.ParseTime(policy.Expires); err == nil {
+			date := time.Now()
+			if d, err := http.ParseTime(headers["Date"]); err == nil {
+				date = d
+			}
+			if expires.After(date) {
+				ttl = expires.Sub(date)
+			}
+		}
+		policy.TTL = &ttl
+	}
+	if policy.TTL != nil && *policy.TTL < 0 {
+		zero := time.Duration(0)
+		policy.TTL = &zero
+	}
+	return policy
+}
+
+// FreshFor is how long the response may be reused without revalidating: zero
+// when it must be revalidated or has no lifetime set
+func (c CachePolicy) FreshFor() time.Duration {
+	if c.NoStore || c.NoCache || c.TTL == nil {
+		return 0
+	}
+	return *c.TTL
+}
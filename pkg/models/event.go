@@ -0,0 +1,69 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Crawl event kinds
+const (
+	EventCrawled = "crawled" // Fetched; Status holds the response code
+	EventFailed  = "failed"  // Fetched with an error or a 4xx/5xx response
+	EventSkipped = "skipped" // Not crawled; Reason says why
+)
+
+// Skip reasons of EventSkipped
+const (
+	SkipRobots   = "robots"   // Disallowed by robots.txt
+	SkipDepth    = "depth"    // Beyond --max-depth
+	SkipDomain   = "domain"   // On another domain than the start URL
+	SkipFilter   = "filter"   // Rejected by the include/exclude rules
+	SkipScope    = "scope"    // Outside --scope-path
+	SkipNofollow = "nofollow" // Only linked as nofollow, under --respect-nofollow
+)
+
+// CrawlEvent is one entry of a crawl's structured log. A URL is logged as
+// skipped once per reason, however many pages link to it.
+type CrawlEvent struct {
+	Time   time.Time `json:"time"`
+	Event  string    `json:"event"`
+	URL    string    `json:"url"`
+	Depth  int       `json:"depth"`
+	Reason string    `json:"reason,omitempty"`
+	Source string    `json:"source,omitempty"` // Page the URL was found on, when known
+	Status int       `json:"status,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// SkipCounts tallies the discovered URLs a crawl didn't fetch, by reason.
+// Each URL counts once per reason, and URLs crawled in the end through
+// another link aren't counted.
+type SkipCounts struct {
+	Robots         int `json:"robots"`
+	Depth          int `json:"depth"`
+	Domain         int `json:"domain"`
+	Filter         int `json:"filter"`
+	Scope          int `json:"scope"`
+	Nofollow       int `json:"nofollow"`
+	DuplicateLinks int `json:"duplicate_links"` // Links to URLs already crawled or queued
+}
+
+// Total returns the number of skipped URLs, not counting duplicate links
+func (c SkipCounts) Total() int {
+	return c.Robots + c.Depth + c.Domain + c.Filter + c.Scope + c.Nofollow
+}
+
+// CrawlEventFilter selects crawl events. Empty fields match everything.
+type CrawlEventFilter struct {
+	Event  string
+	Reason string
+	URL    string // Substring of the event's URL
+	Limit  int    // Maximum number of events (0: no limit)
+}
+
+// Matches reports whether an event passes the filter, ignoring Limit
+func (f CrawlEventFilter) Matches(event CrawlEvent) bool {
+	return (f.Event == "" || event.Event == f.Event) &&
+		(f.Reason == "" || event.Reason == f.Reason) &&
+		(f.URL == "" || strings.Contains(event.URL, f.URL))
+}
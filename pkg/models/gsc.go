@@ -37,5 +37,5 @@ type GSCAuthState struct {
 }
 
 // Note: EnrichedIssue is defined in internal/gsc/client.go to avoid circular dependency
-// It extends analyzer.Issue with GSC performance data
+// It extends Issue with GSC performance data
 
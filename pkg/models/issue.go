@@ -0,0 +1,113 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// IssueType is the stable code of a kind of SEO issue. Codes are written to
+// exports and databases, so an existing code must never be renamed.
+type IssueType string
+
+const (
+	IssueMissingH1        IssueType = "missing_h1"
+	IssueMissingMetaDesc  IssueType = "missing_meta_description"
+	IssueMissingTitle     IssueType = "missing_title"
+	IssueLongTitle        IssueType = "long_title"
+	IssueLongMetaDesc     IssueType = "long_meta_description"
+	IssueShortTitle       IssueType = "short_title"
+	IssueShortMetaDesc    IssueType = "short_meta_description"
+	IssueLargeImage       IssueType = "large_image"
+	IssueMissingImageAlt  IssueType = "missing_image_alt"
+	IssueSlowResponse     IssueType = "slow_response"
+	IssueRedirectChain    IssueType = "redirect_chain"
+	IssueNoCanonical      IssueType = "no_canonical"
+	IssueBrokenLink       IssueType = "broken_link"
+	IssueMultipleH1       IssueType = "multiple_h1"
+	IssueEmptyH1          IssueType = "empty_h1"
+	IssueDeepPage         IssueType = "deep_page"
+	IssueJSErrors         IssueType = "js_errors"
+	IssueUncacheablePage  IssueType = "uncacheable_page"
+	IssueShortCacheTTL    IssueType = "short_cache_ttl"
+	IssueInsecureAsset    IssueType = "insecure_third_party_asset"
+	IssueMissingSRI       IssueType = "missing_sri"
+	IssueImageSavings     IssueType = "image_savings"
+	IssueNoindexConflict  IssueType = "noindex_conflict"
+	IssueNofollowLink     IssueType = "nofollow_internal_link"
+	IssueEmptyAnchor      IssueType = "empty_anchor_text"
+	IssueGenericAnchor    IssueType = "generic_anchor_text"
+	IssueExactAnchor      IssueType = "exact_match_anchor"
+	IssueInvalidSchema    IssueType = "invalid_structured_data"
+	IssueMissingSchema    IssueType = "missing_structured_data"
+	IssueCanonicalChain   IssueType = "canonical_chain"
+	IssuePagination       IssueType = "pagination_error"
+	IssueDuplicateContent IssueType = "duplicate_content"
+	IssueNearDuplicate    IssueType = "near_duplicate_content"
+	IssueBrokenAnchor     IssueType = "broken_anchor"
+	IssueSoft404          IssueType = "soft_404"
+	IssueUnhelpful404     IssueType = "unhelpful_404"
+	IssueMobileParity     IssueType = "mobile_parity"
+	IssueMissingViewport  IssueType = "missing_viewport"
+
+	// Pre-launch checks (crawl --preset prelaunch)
+	IssueSiteNoindex     IssueType = "site_noindex"
+	IssueRobotsDisallow  IssueType = "robots_disallow_all"
+	IssueStagingURL      IssueType = "staging_url"
+	IssuePlaceholderText IssueType = "placeholder_text"
+)
+
+// Severity is how serious an issue is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Valid reports whether s is one of the known severities
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return true
+	}
+	return false
+}
+
+// IssueStatus is what re-fetching an issue's page found
+type IssueStatus string
+
+const (
+	IssueStatusOpen  IssueStatus = "open"  // The page still has the issue
+	IssueStatusFixed IssueStatus = "fixed" // The issue is gone
+)
+
+// Issue is an SEO issue detected on a page. The CLI, serve mode, and the API
+// all use this representation.
+type Issue struct {
+	Type           IssueType `json:"type"`
+	Severity       Severity  `json:"severity"`
+	URL            string    `json:"url"`
+	Message        string    `json:"message"`
+	Value          string    `json:"value,omitempty"`
+	Recommendation string    `json:"recommendation,omitempty"`
+	DocKey         string    `json:"doc_key,omitempty"` // Knowledge base article explaining the issue
+
+	// Set by barracuda recheck
+	Status      IssueStatus `json:"status,omitempty"`
+	RecheckedAt *time.Time  `json:"rechecked_at,omitempty"`
+}
+
+// Fingerprint identifies the same issue across crawls: its type, URL, and
+// value. Message and recommendation are left out since they depend on the
+// output language.
+func (i Issue) Fingerprint() string {
+	h := sha256.New()
+	h.Write([]byte(i.Type))
+	h.Write([]byte{0})
+	h.Write([]byte(i.URL))
+	h.Write([]byte{0})
+	h.Write([]byte(i.Value))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
@@ -0,0 +1,42 @@
+package models
+
+// MobileVariant is what a page served to a smartphone crawler, fetched
+// alongside the page itself to check that mobile-first indexing sees the
+// same content
+type MobileVariant struct {
+	StatusCode int               `json:"status_code"`
+	FinalURL   string            `json:"final_url,omitempty"` // Where redirects ended, such as an m. site, when there were any
+	Title      string            `json:"title"`
+	MetaDesc   string            `json:"meta_description"`
+	Canonical  string            `json:"canonical"`
+	Viewport   string            `json:"viewport,omitempty"`
+	H1         []string          `json:"h1"`
+	Robots     *RobotsDirectives `json:"robots,omitempty"`
+	WordCount  int               `json:"word_count"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// NewMobileVariant records the mobile-relevant fields of a page fetched and
+// parsed as a smartphone
+func NewMobileVariant(page *PageResult) *MobileVariant {
+	variant := &MobileVariant{
+		StatusCode: page.StatusCode,
+		Title:      page.Title,
+		MetaDesc:   page.MetaDesc,
+		Canonical:  page.Canonical,
+		Viewport:   page.Viewport,
+		H1:         page.H1,
+		Robots:     page.Robots,
+		WordCount:  page.WordCount,
+		Error:      page.Error,
+	}
+	if len(page.RedirectChain) > 0 {
+		variant.FinalURL = page.RedirectChain[len(page.RedirectChain)-1]
+	}
+	return variant
+}
+
+// Noindex reports whether the mobile page asks not to be indexed
+func (v *MobileVariant) Noindex() bool {
+	return v.Robots != nil && v.Robots.Noindex
+}
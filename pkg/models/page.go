@@ -1,33 +1,328 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// PageResultSchemaVersion is the current version of the PageResult format.
+// Version 1 files (written before the field existed) load with SchemaVersion 1
+// and the version 2 fields left empty.
+const PageResultSchemaVersion = 2
 
 // PageResult represents the SEO data extracted from a crawled page
 type PageResult struct {
-	URL           string    `json:"url"`
-	StatusCode    int       `json:"status_code"`
-	ResponseTime  int64     `json:"response_time_ms"` // Duration in milliseconds
-	Title         string    `json:"title"`
-	MetaDesc      string    `json:"meta_description"`
-	Canonical     string    `json:"canonical"`
-	H1            []string  `json:"h1"`
-	H2            []string  `json:"h2"`
-	H3            []string  `json:"h3"`
-	H4            []string  `json:"h4"`
-	H5            []string  `json:"h5"`
-	H6            []string  `json:"h6"`
-	InternalLinks []string  `json:"internal_links"`
-	ExternalLinks []string  `json:"external_links"`
-	Images        []Image   `json:"images,omitempty"`
-	RedirectChain []string  `json:"redirect_chain,omitempty"`
-	Error         string    `json:"error,omitempty"`
-	CrawledAt     time.Time `json:"crawled_at"`
+	SchemaVersion  int               `json:"schema_version,omitempty"`
+	URL            string            `json:"url"`
+	StatusCode     int               `json:"status_code"`
+	ResponseTime   int64             `json:"response_time_ms"` // Duration in milliseconds
+	Depth          int               `json:"depth"`            // Link distance from the start URL
+	TTFB           int64             `json:"ttfb_ms,omitempty"`
+	Title          string            `json:"title"`
+	MetaDesc       string            `json:"meta_description"`
+	MetaRobots     string            `json:"meta_robots,omitempty"`
+	Robots         *RobotsDirectives `json:"robots,omitempty"` // Parsed from MetaRobots and the X-Robots-Tag header
+	Canonical      string            `json:"canonical"`
+	RelNext        string            `json:"rel_next,omitempty"` // <link rel="next">, resolved: the next page of a paginated series
+	RelPrev        string            `json:"rel_prev,omitempty"` // <link rel="prev">, resolved
+	OGURL          string            `json:"og_url,omitempty"`   // <meta property="og:url">
+	Viewport       string            `json:"viewport,omitempty"` // <meta name="viewport"> content
+	H1             []string          `json:"h1"`
+	H2             []string          `json:"h2"`
+	H3             []string          `json:"h3"`
+	H4             []string          `json:"h4"`
+	H5             []string          `json:"h5"`
+	H6             []string          `json:"h6"`
+	InternalLinks  []string          `json:"internal_links"`
+	ExternalLinks  []string          `json:"external_links"`
+	Links          []Link            `json:"links,omitempty"`
+	FragmentLinks  []string          `json:"fragment_links,omitempty"` // Internal links with a #fragment, resolved, fragment included
+	Anchors        []string          `json:"anchors,omitempty"`        // Element ids and <a name> values a #fragment can point to
+	Images         []Image           `json:"images,omitempty"`
+	Assets         []string          `json:"assets,omitempty"` // Scripts and stylesheets the page loads
+	ThirdParty     []ThirdPartyAsset `json:"third_party_assets,omitempty"`
+	Hreflang       []Hreflang        `json:"hreflang,omitempty"`
+	StructuredData []StructuredData  `json:"structured_data,omitempty"`
+	ExpectedSchema []string          `json:"expected_schema,omitempty"` // Structured data types the page's markup calls for, such as "BreadcrumbList" for a breadcrumb trail
+	WordCount      int               `json:"word_count"`
+	Placeholder    string            `json:"placeholder_text,omitempty"`
+	PageSize       int               `json:"page_size_bytes"`        // Response body size
+	ContentHash    string            `json:"content_hash,omitempty"` // SHA-256 of the response body
+	TextHash       string            `json:"text_hash,omitempty"`    // SHA-256 of the visible text, ignoring markup, case, and punctuation
+	SimHash        string            `json:"simhash,omitempty"`      // 64-bit simhash of the visible text in hex; near-duplicate pages differ in a few bits
+	ContentType    string            `json:"content_type,omitempty"` // Content-Type response header
+	Headers        map[string]string `json:"headers,omitempty"`      // Response headers, except Set-Cookie
+	RedirectChain  []string          `json:"redirect_chain,omitempty"`
+	BodyTruncated  bool              `json:"body_truncated,omitempty"`
+	Rendered       bool              `json:"rendered,omitempty"`     // Parsed from the DOM rendered by a headless browser (crawl --render)
+	NotModified    bool              `json:"not_modified,omitempty"` // Answered 304 to a revalidation and parsed from the cached body (crawl --http-cache)
+	JSErrors       []JSError         `json:"js_errors,omitempty"`    // Seen while rendering in a headless browser
+	Error          string            `json:"error,omitempty"`
+	ErrorCode      string            `json:"error_code,omitempty"` // Kind of Error, one of the Error* codes
+	CrawledAt      time.Time         `json:"crawled_at"`
+
+	// Content dates from the page's article:published_time and
+	// article:modified_time meta tags and its sitemap <lastmod>
+	PublishedAt    *time.Time `json:"published_at,omitempty"`
+	ModifiedAt     *time.Time `json:"modified_at,omitempty"`
+	SitemapLastMod *time.Time `json:"sitemap_lastmod,omitempty"`
+	InSitemap      bool       `json:"in_sitemap,omitempty"` // Listed in the sitemap the crawl was seeded from
+
+	// Rewrites proposed by an LLM (crawl --suggest) for a title or meta
+	// description with issues
+	SuggestedTitle    string `json:"suggested_title,omitempty"`
+	SuggestedMetaDesc string `json:"suggested_meta_description,omitempty"`
+
+	// Timing breaks ResponseTime down by phase, to tell a slow server from a
+	// slow network or a heavy page
+	Timing *RequestTiming `json:"timing,omitempty"`
+
+	// Mobile is the page as served to a smartphone crawler, in crawls that
+	// compare mobile and desktop (crawl --mobile-parity)
+	Mobile *MobileVariant `json:"mobile,omitempty"`
 }
 
-// Image represents an image found on a page
-type Image struct {
-	URL string `json:"url"`
-	Alt string `json:"alt,omitempty"`
+// Error codes of PageResult.ErrorCode
+const (
+	ErrorDNS               = "dns_error"          // The host name didn't resolve
+	ErrorTimeout           = "timeout"            // The request or response took too long
+	ErrorTLS               = "tls_error"          // TLS handshake or certificate failure
+	ErrorConnectionRefused = "connection_refused" // Nothing listening on the port
+	ErrorHTTP4xx           = "http_4xx"
+	ErrorHTTP5xx           = "http_5xx"
+	ErrorParse             = "parse_error" // The HTML couldn't be parsed
+	ErrorOther             = "other"       // Any other failure, such as a reset connection or a 3xx with no redirect
+)
+
+// StatusErrorCode returns the error code of a response status that isn't 2xx
+func StatusErrorCode(status int) string {
+	switch {
+	case status >= 500 && status < 600:
+		return ErrorHTTP5xx
+	case status >= 400 && status < 500:
+		return ErrorHTTP4xx
+	default:
+		return ErrorOther
+	}
 }
 
+// FailureCode returns the error code of a page that failed or returned a 4xx
+// or 5xx, or "" for one that didn't. Results saved before error codes were
+// recorded get one from their status.
+func (p *PageResult) FailureCode() string {
+	switch {
+	case p.ErrorCode != "":
+		return p.ErrorCode
+	case p.StatusCode >= 400:
+		return StatusErrorCode(p.StatusCode)
+	case p.Error != "":
+		return ErrorOther
+	default:
+		return ""
+	}
+}
+
+// Nofollow reports whether the page asks for none of its links to be
+// followed, in a robots meta tag or an X-Robots-Tag header
+func (p *PageResult) Nofollow() bool {
+	return p.RobotsDirectives().Nofollow
+}
+
+// Noindex reports whether the page asks not to be indexed, in a robots meta
+// tag or an X-Robots-Tag header
+func (p *PageResult) Noindex() bool {
+	return p.RobotsDirectives().Noindex
+}
+
+// RobotsDirectives parses the page's robots meta tag and X-Robots-Tag header.
+// Results saved before Robots was recorded are parsed the same way.
+func (p *PageResult) RobotsDirectives() RobotsDirectives {
+	return ParseRobotsDirectives(p.MetaRobots, p.Headers["X-Robots-Tag"])
+}
 
+// RobotsDirectives are the indexing rules a page gives search engines
+type RobotsDirectives struct {
+	Noindex   bool `json:"noindex,omitempty"`
+	Nofollow  bool `json:"nofollow,omitempty"`
+	Noarchive bool `json:"noarchive,omitempty"`
+}
+
+// robotsUserAgents are the crawlers whose X-Robots-Tag rules apply to the
+// page as a whole: Google's, since audits target it, alongside the rules
+// for every crawler
+var robotsUserAgents = map[string]bool{"googlebot": true}
+
+// ParseRobotsDirectives combines the content of robots meta tags with an
+// X-Robots-Tag header. "none" means noindex and nofollow, and Bing's
+// "nocache" means noarchive. Header rules scoped to a crawler, such as
+// "bingbot: noindex", only count for Googlebot.
+func ParseRobotsDirectives(metaRobots, xRobotsTag string) RobotsDirectives {
+	var d RobotsDirectives
+	apply := func(directive string) {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "noindex":
+			d.Noindex = true
+		case "nofollow":
+			d.Nofollow = true
+		case "none":
+			d.Noindex, d.Nofollow = true, true
+		case "noarchive", "nocache":
+			d.Noarchive = true
+		}
+	}
+
+	for _, directive := range strings.Split(metaRobots, ",") {
+		apply(directive)
+	}
+
+	// A "name:" prefix scopes the directives after it, up to the next one,
+	// to a crawler; "unavailable_after: <date>" and "max-snippet: <n>" are
+	// directives with values rather than crawler names
+	applies := true
+	for _, directive := range strings.Split(xRobotsTag, ",") {
+		if name, rest, ok := strings.Cut(directive, ":"); ok && isRobotsUserAgent(name) {
+			applies = robotsUserAgents[strings.ToLower(strings.TrimSpace(name))]
+			directive = rest
+		}
+		if applies {
+			apply(directive)
+		}
+	}
+	return d
+}
+
+// isRobotsUserAgent reports whether the text before a colon in an
+// X-Robots-Tag is a crawler name rather than a directive with a value
+func isRobotsUserAgent(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "unavailable_after", "max-snippet", "max-image-preview", "max-video-preview":
+		return false
+	}
+	return !strings.ContainsAny(strings.TrimSpace(name), " \t")
+}
+
+// Indexable reports whether search engines can index the page as itself: it
+// returned 200, has no noindex, and has no canonical pointing elsewhere
+func (p *PageResult) Indexable() bool {
+	if p.Error != "" || p.StatusCode != 200 || p.Noindex() {
+		return false
+	}
+	return !p.Canonicalized()
+}
+
+// Canonicalized reports whether the page's canonical points to another URL
+func (p *PageResult) Canonicalized() bool {
+	return p.Canonical != "" && strings.TrimSuffix(p.Canonical, "/") != strings.TrimSuffix(p.URL, "/")
+}
+
+// IndexabilityCounts tallies a crawl's pages the way search engine coverage
+// reports do. Pages that failed or didn't return 200 count in none of them.
+type IndexabilityCounts struct {
+	Indexable     int `json:"indexable"`     // See PageResult.Indexable
+	Noindexed     int `json:"noindexed"`     // 200 pages asking not to be indexed
+	Canonicalized int `json:"canonicalized"` // 200 pages whose canonical points elsewhere
+	Blocked       int `json:"blocked"`       // URLs not crawled because robots.txt disallows them
+}
+
+// Add counts a crawled page
+func (c *IndexabilityCounts) Add(p *PageResult) {
+	switch {
+	case p.Error != "" || p.StatusCode != 200:
+	case p.Noindex():
+		c.Noindexed++
+	case p.Canonicalized():
+		c.Canonicalized++
+	default:
+		c.Indexable++
+	}
+}
+
+// JSError kinds
+const (
+	JSErrorConsole   = "console"   // console.error() call
+	JSErrorException = "exception" // uncaught exception
+	JSErrorNetwork   = "network"   // failed request for a script, stylesheet, or other resource
+)
+
+// JSError is a JavaScript problem captured while rendering a page
+type JSError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Source  string `json:"source,omitempty"` // script or resource URL, with line number when known
+}
+
+// Link is an anchor found on a page
+type Link struct {
+	URL      string `json:"url"`
+	Text     string `json:"text,omitempty"`   // Anchor text, or an image link's alt text
+	Rel      string `json:"rel,omitempty"`    // e.g. "nofollow", "sponsored", or "ugc"
+	Target   string `json:"target,omitempty"` // The anchor's target attribute, e.g. "_blank"
+	Internal bool   `json:"internal"`
+}
+
+// Nofollow reports whether the link's rel attribute includes nofollow
+func (l Link) Nofollow() bool {
+	return l.hasRel("nofollow")
+}
+
+// Sponsored reports whether the link's rel attribute marks it as paid, such
+// as an advertisement or affiliate link
+func (l Link) Sponsored() bool {
+	return l.hasRel("sponsored")
+}
+
+// UGC reports whether the link's rel attribute marks it as user-generated
+// content, such as a link in a comment or forum post
+func (l Link) UGC() bool {
+	return l.hasRel("ugc")
+}
+
+// Followed reports whether search engines may follow the link and credit
+// its target: its rel attribute has none of nofollow, sponsored, or ugc
+func (l Link) Followed() bool {
+	return !l.Nofollow() && !l.Sponsored() && !l.UGC()
+}
+
+// hasRel reports whether the link's rel attribute includes token
+func (l Link) hasRel(token string) bool {
+	for _, rel := range strings.Fields(l.Rel) {
+		if strings.EqualFold(rel, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hreflang is an alternate-language version of a page
+type Hreflang struct {
+	Lang string `json:"lang"`
+	URL  string `json:"url"`
+}
+
+// StructuredData is a schema.org item declared on a page
+type StructuredData struct {
+	Format string `json:"format"`          // "json-ld" or "microdata"
+	Type   string `json:"type"`            // e.g. "Article", "Product"; "" for a JSON-LD script that couldn't be read
+	Error  string `json:"error,omitempty"` // Why search engines can't use the item, e.g. "missing headline"
+}
+
+// Asset types
+const (
+	AssetScript     = "script"
+	AssetStylesheet = "stylesheet"
+)
+
+// ThirdPartyAsset is a script or stylesheet a page loads from another site
+type ThirdPartyAsset struct {
+	URL       string `json:"url"`
+	Type      string `json:"type"`                // AssetScript or AssetStylesheet
+	Integrity string `json:"integrity,omitempty"` // Subresource Integrity hashes
+}
+
+// Image represents an image found on a page
+type Image struct {
+	URL    string `json:"url"`
+	Alt    string `json:"alt,omitempty"`
+	Width  int    `json:"width,omitempty"`  // width attribute in pixels, 0 when missing or relative
+	Height int    `json:"height,omitempty"` // height attribute in pixels, 0 when missing or relative
+}
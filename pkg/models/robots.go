@@ -0,0 +1,49 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// MaxRobotsTxtSize is how much of a robots.txt a crawl keeps, the most
+// Google reads of one
+const MaxRobotsTxtSize = 500 * 1024
+
+// RobotsTxt is the robots.txt a crawl was made under, kept so later crawls
+// can tell when its rules changed
+type RobotsTxt struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`       // 0 when the request failed
+	SHA256     string `json:"sha256,omitempty"`  // Of Content; empty when there is none
+	Content    string `json:"content,omitempty"` // Body of a 200 response, up to MaxRobotsTxtSize
+}
+
+// NewRobotsTxt records a robots.txt response. Only a 200's body counts as
+// the file; any other status means the site has none.
+func NewRobotsTxt(url string, statusCode int, body []byte) *RobotsTxt {
+	robots := &RobotsTxt{URL: url, StatusCode: statusCode}
+	if statusCode != 200 {
+		return robots
+	}
+	if len(body) > MaxRobotsTxtSize {
+		body = body[:MaxRobotsTxtSize]
+	}
+	sum := sha256.Sum256(body)
+	robots.SHA256 = hex.EncodeToString(sum[:])
+	robots.Content = string(body)
+	return robots
+}
+
+// Rules returns the file's lines without comments, surrounding whitespace,
+// or blank lines, in order
+func (r *RobotsTxt) Rules() []string {
+	var rules []string
+	for _, line := range strings.Split(r.Content, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if line = strings.TrimSpace(line); line != "" {
+			rules = append(rules, line)
+		}
+	}
+	return rules
+}
@@ -0,0 +1,15 @@
+package models
+
+// RequestTiming breaks the fetch of a page down by phase, in milliseconds.
+// DNS, Connect, and TLS are those of the request for the final URL, and are
+// 0 when it reused a connection. Redirects plus the other phases up to
+// Server add up to the page's TTFB, and with Download to its full fetch time.
+type RequestTiming struct {
+	Redirects  int64 `json:"redirects_ms,omitempty"` // Requests before the final URL's, when redirected
+	DNS        int64 `json:"dns_ms"`
+	Connect    int64 `json:"connect_ms"` // TCP connection
+	TLS        int64 `json:"tls_ms"`
+	Server     int64 `json:"server_ms"`   // From the request being sent to the first byte of the response
+	Download   int64 `json:"download_ms"` // Reading the body
+	ConnReused bool  `json:"conn_reused,omitempty"`
+}
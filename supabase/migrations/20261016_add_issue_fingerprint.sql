@@ -0,0 +1,7 @@
+-- Issue fingerprints identify the same issue (type, URL, value) across crawls.
+-- Matches models.Issue.Fingerprint in the Go code.
+-- Reference: docs/SUPABASE_SCHEMA.md - Table Definitions section 6
+
+alter table public.issues add column if not exists fingerprint text;
+
+create index if not exists idx_issues_project_fingerprint on public.issues (project_id, fingerprint);
@@ -0,0 +1,8 @@
+-- Private bucket for crawl artifacts (raw exports and HTML snapshots), keyed
+-- <project_id>/<crawl_id>/<name>. Matches store.ArtifactBucket in the Go code.
+-- The API server reads and writes it with the service role key and hands out
+-- signed URLs, so no storage policies are needed.
+
+insert into storage.buckets (id, name, public)
+values ('crawl-artifacts', 'crawl-artifacts', false)
+on conflict (id) do nothing;
@@ -0,0 +1,34 @@
+-- Structured crawl logs: each URL a crawl fetched, failed, or skipped and why.
+-- Written by the API server for cloud crawls; matches store.CrawlLog in the Go code.
+-- Reference: docs/SUPABASE_SCHEMA.md - Table Definitions section 11
+
+create table if not exists public.crawl_logs (
+  id bigserial primary key,
+  crawl_id uuid not null references public.crawls (id) on delete cascade,
+  time timestamptz not null,
+  event text check (event in ('crawled', 'failed', 'skipped')) not null,
+  url text not null,
+  depth integer not null default 0,
+  reason text,
+  source text,
+  status integer,
+  error text
+);
+
+create index if not exists idx_crawl_logs_crawl on public.crawl_logs (crawl_id, id);
+create index if not exists idx_crawl_logs_crawl_event on public.crawl_logs (crawl_id, event, reason);
+
+alter table public.crawl_logs enable row level security;
+
+create policy "Project members can view crawl logs"
+  on public.crawl_logs
+  for select
+  using (
+    exists (
+      select 1
+      from public.crawls c
+      join public.project_members pm on pm.project_id = c.project_id
+      where c.id = crawl_logs.crawl_id
+        and pm.user_id = auth.uid()
+    )
+  );
@@ -0,0 +1,43 @@
+-- Domain verification: crawls triggered from the web only run against a
+-- project's domain once the API has found its token in a DNS TXT record or a
+-- meta tag on the home page. Matches store.Project in the Go code.
+-- Reference: docs/SUPABASE_SCHEMA.md - Table Definitions section 2
+
+alter table public.projects add column if not exists verification_token text;
+alter table public.projects add column if not exists verification_method text check (verification_method in ('dns', 'meta'));
+alter table public.projects add column if not exists verified_at timestamptz;
+
+-- Owners can update their projects directly, so only the service role (the
+-- API server) may change the verification columns, and changing the domain
+-- drops its verification
+create or replace function public.protect_project_verification()
+returns trigger
+language plpgsql
+as $$
+begin
+  if auth.role() is distinct from 'service_role' then
+    if tg_op = 'INSERT' then
+      new.verification_token := null;
+      new.verification_method := null;
+      new.verified_at := null;
+    else
+      new.verification_token := old.verification_token;
+      new.verification_method := old.verification_method;
+      new.verified_at := old.verified_at;
+    end if;
+  end if;
+
+  if tg_op = 'UPDATE' and lower(new.domain) is distinct from lower(old.domain) then
+    new.verification_method := null;
+    new.verified_at := null;
+  end if;
+
+  return new;
+end;
+$$;
+
+drop trigger if exists protect_project_verification_trigger on public.projects;
+create trigger protect_project_verification_trigger
+  before insert or update on public.projects
+  for each row
+  execute function public.protect_project_verification();
@@ -0,0 +1,23 @@
+-- Realtime: the dashboard follows a running crawl through postgres-changes
+-- events on crawls (progress and status) and issues (each issue as it is
+-- stored) instead of polling the API. Events are filtered by the tables' RLS
+-- select policies, so members only receive their own projects' rows.
+-- Reference: docs/SUPABASE_SCHEMA.md - Realtime Events section
+
+do $$
+begin
+  if not exists (
+    select 1 from pg_publication_tables
+    where pubname = 'supabase_realtime' and schemaname = 'public' and tablename = 'crawls'
+  ) then
+    alter publication supabase_realtime add table public.crawls;
+  end if;
+
+  if not exists (
+    select 1 from pg_publication_tables
+    where pubname = 'supabase_realtime' and schemaname = 'public' and tablename = 'issues'
+  ) then
+    alter publication supabase_realtime add table public.issues;
+  end if;
+end
+$$;
@@ -0,0 +1,31 @@
+-- Issue trends: one row per project and day with the page and issue counts of
+-- the last crawl that finished that day, so the dashboard charts health over
+-- time without counting issue rows. Written nightly by the API server
+-- (service role) from /api/internal/trends/aggregate; matches store.IssueTrend
+-- in the Go code.
+-- Reference: docs/SUPABASE_SCHEMA.md - Table Definitions section 12
+
+create table if not exists public.issue_trends (
+  project_id uuid not null references public.projects (id) on delete cascade,
+  day date not null,
+  crawl_id uuid not null references public.crawls (id) on delete cascade,
+  total_pages integer not null default 0,
+  total_issues integer not null default 0,
+  by_severity jsonb not null default '{}'::jsonb,
+  by_type jsonb not null default '{}'::jsonb,
+  primary key (project_id, day)
+);
+
+alter table public.issue_trends enable row level security;
+
+create policy "Project members can view issue trends"
+  on public.issue_trends
+  for select
+  using (
+    exists (
+      select 1
+      from public.project_members pm
+      where pm.project_id = issue_trends.project_id
+        and pm.user_id = auth.uid()
+    )
+  );
@@ -0,0 +1,6 @@
+-- robots.txt snapshots: each crawl keeps the start domain's robots.txt (URL,
+-- status, SHA-256, and content) it ran under, so diffs can flag rule changes.
+-- Matches store.Crawl in the Go code.
+-- Reference: docs/SUPABASE_SCHEMA.md - Table Definitions section 4
+
+alter table public.crawls add column if not exists robots_txt jsonb;
@@ -0,0 +1,890 @@
+// Code generated by go generate ./internal/apispec; DO NOT EDIT.
+//
+// Types and client for the Barracuda API, from the Go types in
+// internal/apispec/endpoints.go.
+
+export interface AICrawlerAccess {
+  user_agent: string;
+  allowed: boolean;
+  group?: string;
+}
+
+export interface AIVisibility {
+  robots_txt_found: boolean;
+  crawlers: AICrawlerAccess[];
+  llms_txt: LLMsTxtCheck;
+}
+
+export interface AdminUsersResponse {
+  users: (Record<string, unknown>)[];
+  count: number;
+}
+
+export interface Artifact {
+  name: string;
+  size: number;
+  content_type?: string;
+  updated_at: string;
+}
+
+export interface ArtifactURLResponse {
+  name: string;
+  url: string;
+  expires_at: string;
+}
+
+export interface ArtifactUploadResponse {
+  name: string;
+  upload_url: string;
+}
+
+export interface AuthURLResponse {
+  auth_url: string;
+  state: string;
+}
+
+export interface BillingPlan {
+  id: string;
+  price_id: string;
+  tier: string;
+  name: string;
+  description?: string;
+  unit_amount: number;
+  currency: string;
+  interval: string;
+  interval_count: number;
+}
+
+export interface BillingPlansResponse {
+  plans: BillingPlan[];
+}
+
+export interface BillingSummaryResponse {
+  profile: Record<string, unknown>;
+  subscription: Record<string, unknown>;
+  entitlements: Entitlements;
+}
+
+export interface CancelCrawlRequest {
+  reason?: string;
+}
+
+export interface CancelCrawlResponse {
+  crawl_id: string;
+  status: string;
+  message: string;
+}
+
+export interface CanonicalCluster {
+  canonical: string;
+  crawled: boolean;
+  status_code: number;
+  indexable: boolean;
+  members: CanonicalMember[];
+  indexable_duplicates: string[];
+  member_count: number;
+  duplicate_count: number;
+}
+
+export interface CanonicalClustersResponse {
+  clusters: CanonicalCluster[];
+  count: number;
+}
+
+export interface CanonicalMember {
+  url: string;
+  status_code: number;
+  same_text: boolean;
+}
+
+export interface CompleteCrawlRequest {
+  status?: string;
+  reason?: string;
+}
+
+export interface Crawl {
+  id?: string;
+  project_id: string;
+  initiated_by?: string;
+  source: string;
+  status: string;
+  started_at: string;
+  completed_at: string | null;
+  total_pages: number;
+  total_issues: number;
+  meta: Record<string, unknown>;
+  robots_txt?: RobotsTxt | null;
+}
+
+export interface CrawlEvent {
+  time: string;
+  event: string;
+  url: string;
+  depth: number;
+  reason?: string;
+  source?: string;
+  status?: number;
+  error?: string;
+}
+
+export interface CrawlFileConfig {
+  url?: string;
+  extra_urls?: string[];
+  max_depth?: number | null;
+  max_pages?: number | null;
+  workers?: number | null;
+  delay?: string;
+  max_rps?: number | null;
+  auto_throttle?: boolean | null;
+  timeout?: string;
+  max_conns_per_host?: number | null;
+  http2?: boolean | null;
+  keep_alive?: boolean | null;
+  user_agent?: string;
+  user_agent_preset?: string;
+  respect_robots?: boolean | null;
+  parse_sitemap?: boolean | null;
+  sitemap_since?: string;
+  domain_filter?: string;
+  format?: string;
+  include?: string[];
+  exclude?: string[];
+  scope_path?: string;
+  respect_nofollow?: boolean | null;
+  skip_image_check?: boolean | null;
+  save_html?: string;
+  render?: boolean | null;
+  visited_limit?: number | null;
+  visited_fp_rate?: number | null;
+  low_memory?: boolean | null;
+  strategy?: string;
+  stream?: boolean | null;
+  host_rewrite?: string[];
+  max_body_size?: string;
+  active_hours?: string;
+  http_cache?: string;
+  mobile_parity?: boolean | null;
+}
+
+export interface CrawlGraphResponse {
+  graph: Record<string, string[]>;
+  indexability: IndexabilityCounts | null;
+}
+
+export interface CrawlLog {
+  id?: number;
+  crawl_id: string;
+  time: string;
+  event: string;
+  url: string;
+  depth: number;
+  reason?: string;
+  source?: string;
+  status?: number;
+  error?: string;
+}
+
+export interface CrawlResponse {
+  id?: string;
+  project_id: string;
+  initiated_by?: string;
+  source: string;
+  status: string;
+  started_at: string;
+  completed_at: string | null;
+  total_pages: number;
+  total_issues: number;
+  meta: Record<string, unknown>;
+  robots_txt?: RobotsTxt | null;
+  page_count: number;
+  indexed_pages: number;
+  max_pages?: unknown;
+  indexability?: IndexabilityCounts | null;
+}
+
+export interface CreateArtifactUploadRequest {
+  name: string;
+}
+
+export interface CreateCheckoutSessionRequest {
+  price_id: string;
+  quantity?: number;
+  promotion_code?: string;
+}
+
+export interface CreateCheckoutSessionResponse {
+  session_id: string;
+  url: string;
+}
+
+export interface CreateCrawlRequest {
+  project_id: string;
+  pages: PageResult[];
+  source?: string;
+  metadata?: Metadata | null;
+}
+
+export interface CreateCrawlResponse {
+  crawl_id: string;
+  project_id: string;
+  total_pages: number;
+  total_issues: number;
+  status: string;
+}
+
+export interface CreatePortalSessionRequest {
+  flow?: string;
+  price_id?: string;
+}
+
+export interface CreateProjectRequest {
+  name: string;
+  domain: string;
+  settings?: Record<string, unknown>;
+}
+
+export interface DNSRecord {
+  type: string;
+  name: string;
+  value: string;
+}
+
+export interface DomainVerificationResponse {
+  domain: string;
+  required: boolean;
+  verified: boolean;
+  method?: string;
+  verified_at?: string | null;
+  token: string;
+  dns_record: DNSRecord;
+  meta_tag: string;
+}
+
+export interface EnrichJob {
+  site_url: string;
+  days: number;
+  status: string;
+  done: number;
+  total: number;
+  error?: string;
+  started_at: string;
+  finished_at?: string | null;
+  issues: EnrichedIssue[];
+}
+
+export interface EnrichedIssue {
+  issue: Issue;
+  gsc_performance?: GSCPerformance | null;
+  referring_domains?: number | null;
+  enriched_priority: number;
+  recommendation_reason: string;
+}
+
+export interface Entitlements {
+  max_pages: number;
+  max_projects: number;
+  scheduled_crawls: boolean;
+  gsc_sync: boolean;
+  render: boolean;
+  exports: boolean;
+}
+
+export interface ErrorResponse {
+  error: string;
+}
+
+export interface Freshness {
+  buckets: FreshnessBucket[];
+  unknown: number;
+}
+
+export interface FreshnessBucket {
+  bucket: string;
+  pages: number;
+}
+
+export interface GSCPerformance {
+  url: string;
+  impressions: number;
+  clicks: number;
+  ctr: number;
+  position: number;
+  top_queries?: Query[];
+  last_updated: string;
+}
+
+export interface GSCProperty {
+  url: string;
+  type: string;
+  verified: boolean;
+}
+
+export interface HealthResponse {
+  status: string;
+  time: string;
+}
+
+export interface HostLatency {
+  host: string;
+  pages: number;
+  p50_ms: number;
+  p90_ms: number;
+  p99_ms: number;
+  ttfb_p50_ms?: number;
+  ttfb_p90_ms?: number;
+  ttfb_p99_ms?: number;
+}
+
+export interface HostStats {
+  host: string;
+  pages: number;
+  errors: number;
+  avg_response_time_ms: number;
+}
+
+export interface Hreflang {
+  lang: string;
+  url: string;
+}
+
+export interface Image {
+  url: string;
+  alt?: string;
+  width?: number;
+  height?: number;
+}
+
+export interface ImageSavings {
+  images: number;
+  pages: number;
+  bytes: number;
+  webp_bytes: number;
+  avif_bytes: number;
+}
+
+export interface IndexabilityCounts {
+  indexable: number;
+  noindexed: number;
+  canonicalized: number;
+  blocked: number;
+}
+
+export interface Issue {
+  type: string;
+  severity: string;
+  url: string;
+  message: string;
+  value?: string;
+  recommendation?: string;
+  doc_key?: string;
+  status?: string;
+  rechecked_at?: string | null;
+}
+
+export interface IssueTrend {
+  project_id: string;
+  day: string;
+  crawl_id: string;
+  total_pages: number;
+  total_issues: number;
+  by_severity: Record<string, number>;
+  by_type: Record<string, number>;
+}
+
+export interface IssueTrendsResponse {
+  trends: IssueTrend[];
+  count: number;
+}
+
+export interface JSError {
+  kind: string;
+  message: string;
+  source?: string;
+}
+
+export interface LLMsTxtCheck {
+  found: boolean;
+  url: string;
+  title?: string;
+  sections: number;
+  links: number;
+  problems?: string[];
+}
+
+export interface LatencyStats {
+  pages: number;
+  p50_ms: number;
+  p90_ms: number;
+  p99_ms: number;
+  ttfb_p50_ms?: number;
+  ttfb_p90_ms?: number;
+  ttfb_p99_ms?: number;
+}
+
+export interface Link {
+  url: string;
+  text?: string;
+  rel?: string;
+  target?: string;
+  internal: boolean;
+}
+
+export interface ListArtifactsResponse {
+  artifacts: Artifact[];
+  count: number;
+}
+
+export interface ListCrawlLogsResponse {
+  logs: CrawlLog[];
+  count: number;
+}
+
+export interface ListCrawlsResponse {
+  crawls: Crawl[];
+  count: number;
+}
+
+export interface ListProjectsResponse {
+  projects: Project[];
+  count: number;
+}
+
+export interface Metadata {
+  version: string;
+  url: string;
+  status: string;
+  error?: string;
+  started_at: string;
+  completed_at?: string;
+  total_pages: number;
+  total_issues: number;
+  hosts?: HostStats[];
+  config: CrawlFileConfig;
+  robots_txt?: RobotsTxt | null;
+}
+
+export interface MobileVariant {
+  status_code: number;
+  final_url?: string;
+  title: string;
+  meta_description: string;
+  canonical: string;
+  viewport?: string;
+  h1: string[];
+  robots?: RobotsDirectives | null;
+  word_count: number;
+  error?: string;
+}
+
+export interface PagePerformance {
+  url: string;
+  response_time_ms: number;
+}
+
+export interface PageResult {
+  schema_version?: number;
+  url: string;
+  status_code: number;
+  response_time_ms: number;
+  depth: number;
+  ttfb_ms?: number;
+  title: string;
+  meta_description: string;
+  meta_robots?: string;
+  robots?: RobotsDirectives | null;
+  canonical: string;
+  rel_next?: string;
+  rel_prev?: string;
+  og_url?: string;
+  viewport?: string;
+  h1: string[];
+  h2: string[];
+  h3: string[];
+  h4: string[];
+  h5: string[];
+  h6: string[];
+  internal_links: string[];
+  external_links: string[];
+  links?: Link[];
+  fragment_links?: string[];
+  anchors?: string[];
+  images?: Image[];
+  assets?: string[];
+  third_party_assets?: ThirdPartyAsset[];
+  hreflang?: Hreflang[];
+  structured_data?: StructuredData[];
+  expected_schema?: string[];
+  word_count: number;
+  placeholder_text?: string;
+  page_size_bytes: number;
+  content_hash?: string;
+  text_hash?: string;
+  simhash?: string;
+  content_type?: string;
+  headers?: Record<string, string>;
+  redirect_chain?: string[];
+  body_truncated?: boolean;
+  rendered?: boolean;
+  not_modified?: boolean;
+  js_errors?: JSError[];
+  error?: string;
+  error_code?: string;
+  crawled_at: string;
+  published_at?: string | null;
+  modified_at?: string | null;
+  sitemap_lastmod?: string | null;
+  in_sitemap?: boolean;
+  suggested_title?: string;
+  suggested_meta_description?: string;
+  timing?: RequestTiming | null;
+  mobile?: MobileVariant | null;
+}
+
+export interface PerformanceRequest {
+  user_id: string;
+  site_url: string;
+  days: number;
+}
+
+export interface PortalSessionResponse {
+  url: string;
+}
+
+export interface Project {
+  id?: string;
+  name: string;
+  domain: string;
+  owner_id: string;
+  settings: Record<string, unknown>;
+  created_at: string;
+  updated_at: string;
+  verification_token?: string;
+  verification_method?: string;
+  verified_at?: string | null;
+}
+
+export interface Query {
+  query: string;
+  impressions: number;
+  clicks: number;
+  ctr: number;
+  position: number;
+}
+
+export interface ReassignProjectRequest {
+  owner_id: string;
+}
+
+export interface RequestTiming {
+  redirects_ms?: number;
+  dns_ms: number;
+  connect_ms: number;
+  tls_ms: number;
+  server_ms: number;
+  download_ms: number;
+  conn_reused?: boolean;
+}
+
+export interface RobotsDirectives {
+  noindex?: boolean;
+  nofollow?: boolean;
+  noarchive?: boolean;
+}
+
+export interface RobotsTxt {
+  url: string;
+  status_code: number;
+  sha256?: string;
+  content?: string;
+}
+
+export interface SkipCounts {
+  robots: number;
+  depth: number;
+  domain: number;
+  filter: number;
+  scope: number;
+  nofollow: number;
+  duplicate_links: number;
+}
+
+export interface SnapshotMatch {
+  url: string;
+  file: string;
+  status_code: number;
+  count: number;
+  snippets?: string[];
+}
+
+export interface SnapshotSearch {
+  searched: number;
+  matches: SnapshotMatch[];
+}
+
+export interface StructuredData {
+  format: string;
+  type: string;
+  error?: string;
+}
+
+export interface Summary {
+  total_pages: number;
+  total_issues: number;
+  issues_by_type: Record<string, number>;
+  issues: Issue[];
+  average_response_time_ms: number;
+  response_times: LatencyStats;
+  host_response_times?: HostLatency[];
+  pages_with_errors: number;
+  errors_by_code?: Record<string, number>;
+  pages_with_redirects: number;
+  total_internal_links: number;
+  total_external_links: number;
+  slowest_pages?: PagePerformance[];
+  pages_by_depth?: Record<string, number>;
+  freshness?: Freshness | null;
+  ai_visibility?: AIVisibility | null;
+  third_party_origins?: ThirdPartyOrigin[];
+  image_savings?: ImageSavings | null;
+  skipped?: SkipCounts | null;
+}
+
+export interface ThirdPartyAsset {
+  url: string;
+  type: string;
+  integrity?: string;
+}
+
+export interface ThirdPartyOrigin {
+  origin: string;
+  pages: number;
+  scripts: number;
+  stylesheets: number;
+  without_sri: number;
+  insecure: number;
+}
+
+export interface TriggerCrawlRequest {
+  url: string;
+  max_depth: number;
+  max_pages: number;
+  workers: number;
+  respect_robots: boolean;
+  parse_sitemap: boolean;
+  save_html?: boolean;
+  render?: boolean;
+}
+
+export interface TriggerCrawlResponse {
+  crawl_id: string;
+  status: string;
+  message: string;
+}
+
+export interface VerifyDomainRequest {
+  method: string;
+}
+
+export interface WebhookEvent {
+  id: string;
+  type: string;
+  created: string;
+  pending_webhooks: number;
+}
+
+export interface WebhookEventsResponse {
+  events: WebhookEvent[];
+  count: number;
+}
+
+export class ApiError extends Error {
+  readonly status: number;
+
+  constructor(status: number, message: string) {
+    super(message);
+    this.name = 'ApiError';
+    this.status = status;
+  }
+}
+
+export interface ClientOptions {
+  /** Origin of the API, such as the cloud API's URL; empty for the page's own origin */
+  baseURL?: string;
+  /** Extra headers for each request, such as Authorization for the cloud API */
+  headers?: () => Record<string, string> | Promise<Record<string, string>>;
+  fetch?: typeof fetch;
+}
+
+type QueryParams = Record<string, string | undefined>;
+
+export function createClient(options: ClientOptions = {}) {
+  const baseURL = options.baseURL ?? '';
+  const doFetch = options.fetch ?? fetch.bind(globalThis);
+
+  async function request<T>(method: string, path: string, query?: QueryParams, body?: unknown): Promise<T> {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value !== undefined) params.set(key, value);
+    }
+    const search = params.toString();
+    const headers: Record<string, string> = options.headers ? { ...(await options.headers()) } : {};
+    if (body !== undefined) headers['Content-Type'] = 'application/json';
+
+    const response = await doFetch(baseURL + path + (search ? '?' + search : ''), {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    if (!response.ok) {
+      let message = response.statusText;
+      try {
+        const data = (await response.json()) as ErrorResponse;
+        if (data.error) message = data.error;
+      } catch {
+        // Not a JSON error body
+      }
+      throw new ApiError(response.status, message);
+    }
+    return (await response.json()) as T;
+  }
+
+  return {
+    /** Page results of the served crawl */
+    getResults: () =>
+      request<PageResult[]>('GET', '/api/results'),
+    /** Analysis summary of the served crawl */
+    getSummary: () =>
+      request<Summary>('GET', '/api/summary'),
+    /** Metadata manifest of the served crawl; empty when it has none */
+    getMetadata: () =>
+      request<Metadata>('GET', '/api/metadata'),
+    /** Referring domains by page URL */
+    getBacklinks: () =>
+      request<Record<string, number>>('GET', '/api/backlinks'),
+    /** Link graph: linked URLs by source URL */
+    getGraph: () =>
+      request<Record<string, string[]>>('GET', '/api/graph'),
+    /** Crawled, failed, and skipped URLs of the served crawl */
+    getLogs: (query: { event?: string; reason?: string; url?: string; limit?: string } = {}) =>
+      request<CrawlEvent[]>('GET', '/api/logs', query),
+    /** Pages of the served crawl grouped by the canonical they point to */
+    getCanonicals: () =>
+      request<CanonicalCluster[]>('GET', '/api/canonicals'),
+    /** Pages of the served crawl whose saved HTML contains, or with missing=true lacks, a string */
+    searchSnapshots: (query: { q?: string; regexp?: string; ignore_case?: string; missing?: string } = {}) =>
+      request<SnapshotSearch>('GET', '/api/search', query),
+    /** Start connecting Search Console */
+    connectGSC: () =>
+      request<AuthURLResponse>('GET', '/api/gsc/connect'),
+    /** Search Console properties of the connected account */
+    getGSCProperties: (query: { user_id?: string } = {}) =>
+      request<GSCProperty[]>('GET', '/api/gsc/properties', query),
+    /** Search Console performance by page URL */
+    getGSCPerformance: (body: PerformanceRequest) =>
+      request<Record<string, GSCPerformance>>('POST', '/api/gsc/performance', undefined, body),
+    /** Start prioritizing the served crawl's issues by Search Console performance, or reuse the last result for the same property and period */
+    enrichIssues: (body: PerformanceRequest, query: { refresh?: string } = {}) =>
+      request<EnrichJob>('POST', '/api/gsc/enrich-issues', query, body),
+    /** Progress of the issue enrichment and, once done, the prioritized issues */
+    getIssueEnrichment: () =>
+      request<EnrichJob>('GET', '/api/gsc/enrich-issues'),
+    /** Server health */
+    getHealth: () =>
+      request<HealthResponse>('GET', '/health'),
+    /** Ingest a crawl run by the CLI */
+    createCrawl: (body: CreateCrawlRequest) =>
+      request<CreateCrawlResponse>('POST', '/api/v1/crawls', undefined, body),
+    /** Crawls the user can see, newest first */
+    listCrawls: (query: { project_id?: string } = {}) =>
+      request<ListCrawlsResponse>('GET', '/api/v1/crawls', query),
+    /** A crawl with its live page counts and, once finished, its indexability */
+    getCrawl: (id: string) =>
+      request<CrawlResponse>('GET', `/api/v1/crawls/${encodeURIComponent(id)}`),
+    /** Link graph of a crawl, with its indexability */
+    getCrawlGraph: (id: string) =>
+      request<CrawlGraphResponse>('GET', `/api/v1/crawls/${encodeURIComponent(id)}/graph`),
+    /** Crawled, failed, and skipped URLs of a crawl, in the order they were logged */
+    listCrawlLogs: (id: string, query: { event?: string; reason?: string; url?: string; limit?: string } = {}) =>
+      request<ListCrawlLogsResponse>('GET', `/api/v1/crawls/${encodeURIComponent(id)}/logs`, query),
+    /** Pages of a crawl grouped by the canonical they point to, with their indexable duplicates */
+    getCrawlCanonicals: (id: string) =>
+      request<CanonicalClustersResponse>('GET', `/api/v1/crawls/${encodeURIComponent(id)}/canonicals`),
+    /** Stop a crawl running on the server, keeping the pages crawled so far; it ends with status cancelled */
+    cancelCrawl: (id: string, body?: CancelCrawlRequest) =>
+      request<CancelCrawlResponse>('POST', `/api/v1/crawls/${encodeURIComponent(id)}/cancel`, undefined, body),
+    /** Raw exports and HTML snapshots stored for a crawl */
+    listCrawlArtifacts: (id: string) =>
+      request<ListArtifactsResponse>('GET', `/api/v1/crawls/${encodeURIComponent(id)}/artifacts`),
+    /** Signed URL to upload a crawl artifact to */
+    createCrawlArtifactUpload: (id: string, body: CreateArtifactUploadRequest) =>
+      request<ArtifactUploadResponse>('POST', `/api/v1/crawls/${encodeURIComponent(id)}/artifacts`, undefined, body),
+    /** Signed download URL of a crawl artifact */
+    getCrawlArtifact: (id: string, name: string) =>
+      request<ArtifactURLResponse>('GET', `/api/v1/crawls/${encodeURIComponent(id)}/artifacts/${encodeURIComponent(name)}`),
+    /** Create a project */
+    createProject: (body: CreateProjectRequest) =>
+      request<Project>('POST', '/api/v1/projects', undefined, body),
+    /** Projects the user owns or is a member of */
+    listProjects: () =>
+      request<ListProjectsResponse>('GET', '/api/v1/projects'),
+    /** A project */
+    getProject: (id: string) =>
+      request<Project>('GET', `/api/v1/projects/${encodeURIComponent(id)}`),
+    /** Crawls of a project, newest first */
+    listProjectCrawls: (id: string) =>
+      request<ListCrawlsResponse>('GET', `/api/v1/projects/${encodeURIComponent(id)}/crawls`),
+    /** Domain verification state of a project, with the DNS record and meta tag that verify it */
+    getProjectVerification: (id: string) =>
+      request<DomainVerificationResponse>('GET', `/api/v1/projects/${encodeURIComponent(id)}/verification`),
+    /** Check a project's domain for its verification token */
+    verifyProjectDomain: (id: string, body: VerifyDomainRequest) =>
+      request<DomainVerificationResponse>('POST', `/api/v1/projects/${encodeURIComponent(id)}/verification`, undefined, body),
+    /** Daily page and issue counts of a project over the last days (90 by default), oldest first */
+    getProjectTrends: (id: string, query: { days?: string } = {}) =>
+      request<IssueTrendsResponse>('GET', `/api/v1/projects/${encodeURIComponent(id)}/trends`, query),
+    /** Start a crawl of a project on the server; with domain verification required, only of its verified domain */
+    triggerCrawl: (id: string, body: TriggerCrawlRequest) =>
+      request<TriggerCrawlResponse>('POST', `/api/v1/projects/${encodeURIComponent(id)}/crawl`, undefined, body),
+    /** Start connecting Search Console to a project */
+    connectProjectGSC: (id: string) =>
+      request<AuthURLResponse>('GET', `/api/v1/projects/${encodeURIComponent(id)}/gsc/connect`),
+    /** The user's profile and subscription */
+    getBillingSummary: () =>
+      request<BillingSummaryResponse>('GET', '/api/v1/billing/summary'),
+    /** The plans for sale, with their Stripe prices */
+    getBillingPlans: () =>
+      request<BillingPlansResponse>('GET', '/api/v1/billing/plans'),
+    /** Start a Stripe checkout, with a promotion code or a field to enter one */
+    createCheckoutSession: (body: CreateCheckoutSessionRequest) =>
+      request<CreateCheckoutSessionResponse>('POST', '/api/v1/billing/checkout', undefined, body),
+    /** Open the Stripe billing portal, on its home page or one action */
+    createBillingPortalSession: (body?: CreatePortalSessionRequest) =>
+      request<PortalSessionResponse>('POST', '/api/v1/billing/portal', undefined, body),
+    /** Admin: user profiles, optionally of one tier */
+    adminListUsers: (query: { tier?: string; limit?: string; offset?: string } = {}) =>
+      request<AdminUsersResponse>('GET', '/api/v1/admin/users', query),
+    /** Admin: a user's profile, subscription, and entitlements */
+    adminGetUser: (id: string) =>
+      request<BillingSummaryResponse>('GET', `/api/v1/admin/users/${encodeURIComponent(id)}`),
+    /** Admin: copy a user's latest subscription from Stripe */
+    adminSyncSubscription: (id: string) =>
+      request<BillingSummaryResponse>('POST', `/api/v1/admin/users/${encodeURIComponent(id)}/sync-subscription`),
+    /** Admin: recent Stripe events that failed to reach a webhook, newest first */
+    adminListWebhookEvents: (query: { type?: string; limit?: string } = {}) =>
+      request<WebhookEventsResponse>('GET', '/api/v1/admin/webhook-events', query),
+    /** Admin: process a Stripe event as if its webhook had arrived */
+    adminReplayWebhookEvent: (id: string) =>
+      request<WebhookEvent>('POST', `/api/v1/admin/webhook-events/${encodeURIComponent(id)}/replay`),
+    /** Admin: make another user a project's owner */
+    adminReassignProject: (id: string, body: ReassignProjectRequest) =>
+      request<Project>('POST', `/api/v1/admin/projects/${encodeURIComponent(id)}/owner`, undefined, body),
+    /** Admin: crawls of every project, newest first */
+    adminListCrawls: (query: { status?: string; project_id?: string } = {}) =>
+      request<ListCrawlsResponse>('GET', '/api/v1/admin/crawls', query),
+    /** Admin: end a crawl left pending or running */
+    adminCompleteCrawl: (id: string, body?: CompleteCrawlRequest) =>
+      request<Crawl>('POST', `/api/v1/admin/crawls/${encodeURIComponent(id)}/complete`, undefined, body),
+  };
+}
+
+export type Client = ReturnType<typeof createClient>;
@@ -363,8 +363,74 @@ export async function fetchProjectGSCDimensions(projectId, type, params = {}) {
   return authorizedJSON(`/api/v1/projects/${projectId}/gsc/dimensions?${searchParams.toString()}`);
 }
 
+// Fetch a project's domain verification state and instructions
+export async function fetchProjectVerification(projectId) {
+  if (!projectId) return { data: null, error: new Error('projectId is required') };
+  return authorizedJSON(`/api/v1/projects/${projectId}/verification`);
+}
+
+// Ask the API to check a project's domain for its verification token, by
+// 'dns' TXT record or 'meta' tag
+export async function verifyProjectDomain(projectId, method) {
+  if (!projectId) return { data: null, error: new Error('projectId is required') };
+  return authorizedJSON(`/api/v1/projects/${projectId}/verification`, {
+    method: 'POST',
+    body: { method },
+  });
+}
+
+// Fetch a project's daily page and issue counts, oldest first, for charting
+export async function fetchProjectIssueTrends(projectId, days = 90) {
+  if (!projectId) return { data: null, error: new Error('projectId is required') };
+  return authorizedJSON(`/api/v1/projects/${projectId}/trends?days=${days}`);
+}
+
 // Fetch link graph for a crawl
 export async function fetchCrawlGraph(crawlId) {
   if (!crawlId) return { data: null, error: new Error('crawlId is required') };
   return authorizedJSON(`/api/v1/crawls/${crawlId}/graph`);
 }
+
+// Fetch a crawl's pages grouped by the canonical they point to
+export async function fetchCrawlCanonicals(crawlId) {
+  if (!crawlId) return { data: null, error: new Error('crawlId is required') };
+  return authorizedJSON(`/api/v1/crawls/${crawlId}/canonicals`);
+}
+
+// Fetch the structured crawl log (crawled, failed and skipped URLs)
+export async function fetchCrawlLogs(crawlId, filter = {}) {
+  if (!crawlId) return { data: null, error: new Error('crawlId is required') };
+
+  const searchParams = new URLSearchParams();
+  Object.entries(filter).forEach(([key, value]) => {
+    if (value !== undefined && value !== null && value !== '') {
+      searchParams.append(key, value);
+    }
+  });
+
+  return authorizedJSON(`/api/v1/crawls/${crawlId}/logs?${searchParams.toString()}`);
+}
+
+// Subscribe to a crawl's Realtime events: onCrawl receives the crawls row each
+// time its progress or status changes, and onIssue each issue as it is stored.
+// See docs/SUPABASE_SCHEMA.md (Realtime Events) for the payloads. Returns a
+// function that unsubscribes.
+export function subscribeToCrawl(crawlId, { onCrawl, onIssue, onStatus } = {}) {
+  const channel = supabase
+    .channel(`crawl:${crawlId}`)
+    .on(
+      'postgres_changes',
+      { event: 'UPDATE', schema: 'public', table: 'crawls', filter: `id=eq.${crawlId}` },
+      (payload) => onCrawl && onCrawl(payload.new)
+    )
+    .on(
+      'postgres_changes',
+      { event: 'INSERT', schema: 'public', table: 'issues', filter: `crawl_id=eq.${crawlId}` },
+      (payload) => onIssue && onIssue(payload.new)
+    )
+    .subscribe((status) => onStatus && onStatus(status));
+
+  return () => {
+    supabase.removeChannel(channel);
+  };
+}
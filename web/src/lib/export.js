@@ -0,0 +1,44 @@
+// Helpers for the dashboard's CSV and JSON downloads
+
+export const timestamp = () => {
+  const now = new Date();
+  const pad = (value) => value.toString().padStart(2, '0');
+  return `${now.getFullYear()}${pad(now.getMonth() + 1)}${pad(now.getDate())}-${pad(now.getHours())}${pad(now.getMinutes())}${pad(now.getSeconds())}`;
+};
+
+export const downloadFile = (content, fileName, mimeType) => {
+  const blob = new Blob([content], { type: mimeType });
+  const url = URL.createObjectURL(blob);
+  const link = document.createElement('a');
+  link.href = url;
+  link.download = fileName;
+  document.body.appendChild(link);
+  link.click();
+  document.body.removeChild(link);
+  URL.revokeObjectURL(url);
+};
+
+const escapeValue = (value) => {
+  if (value === null || value === undefined) return '';
+  const stringValue = String(value);
+  return /[",\n]/.test(stringValue) ? `"${stringValue.replace(/"/g, '""')}"` : stringValue;
+};
+
+export const toCsv = (headers, rows) => [headers, ...rows]
+  .map(row => row.map(escapeValue).join(','))
+  .join('\n');
+
+// Search Console columns, appended when GSC enrichment has run
+export const gscHeaders = ['gsc_impressions', 'gsc_clicks', 'gsc_ctr', 'gsc_position'];
+
+// gscValues returns the GSC column values of a page's performance, blank
+// for pages Search Console has no data for
+export const gscValues = (perf) => {
+  if (!perf) return gscHeaders.map(() => '');
+  return [
+    perf.impressions ?? 0,
+    perf.clicks ?? 0,
+    (perf.ctr ?? 0).toFixed(4),
+    (perf.position ?? 0).toFixed(1)
+  ];
+};
@@ -85,11 +85,8 @@ function extractMetrics(row = {}) {
   };
 }
 
-export function buildEnrichedIssues(issues = [], pageRows = []) {
-  if (!issues.length || !pageRows.length) {
-    return [];
-  }
-
+// Performance metrics of GSC page rows, keyed by normalized page URL
+export function buildPerformanceByUrl(pageRows = []) {
   const rowsByUrl = new Map();
   pageRows.forEach((row) => {
     const pageUrl = typeof row.dimension_value === 'string' ? row.dimension_value : '';
@@ -97,11 +94,23 @@ export function buildEnrichedIssues(issues = [], pageRows = []) {
     if (!normalized) return;
     rowsByUrl.set(normalized, extractMetrics(row));
   });
+  return rowsByUrl;
+}
+
+export function lookupPerformance(rowsByUrl, url = '') {
+  const normalized = normalizeUrlForGSC(url);
+  return normalized ? rowsByUrl?.get(normalized) ?? null : null;
+}
+
+export function buildEnrichedIssues(issues = [], pageRows = []) {
+  if (!issues.length || !pageRows.length) {
+    return [];
+  }
+
+  const rowsByUrl = buildPerformanceByUrl(pageRows);
 
   return issues.map((issue) => {
-    const issueUrl = issue?.url ?? '';
-    const normalizedIssueUrl = normalizeUrlForGSC(issueUrl);
-    const metrics = normalizedIssueUrl ? rowsByUrl.get(normalizedIssueUrl) : null;
+    const metrics = lookupPerformance(rowsByUrl, issue?.url ?? '');
 
     if (!metrics) {
       return {
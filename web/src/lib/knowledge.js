@@ -0,0 +1,10 @@
+import articles from './knowledge.gen.json';
+
+// articleFor returns the knowledge base article explaining an issue. Issues
+// stored before they carried a doc_key fall back to the article of their type.
+export function articleFor(issue) {
+  if (!issue) return null;
+  return articles[issue.doc_key || issue.type] ?? null;
+}
+
+export const impactLabel = (impact = '') => impact.charAt(0).toUpperCase() + impact.slice(1);
@@ -5,6 +5,8 @@ import { supabase } from './supabase.js';
 // Store for user subscription/profile data
 export const userProfile = writable(null);
 export const userSubscription = writable(null);
+// What the user's plan allows, from the server's entitlements table
+export const userEntitlements = writable(null);
 
 /**
  * Load user subscription data from the API
@@ -67,6 +69,7 @@ export async function loadSubscriptionData() {
             const data = await retryResponse.json();
             userProfile.set(data.profile || null);
             userSubscription.set(data.subscription || null);
+            userEntitlements.set(data.entitlements || null);
             return;
           }
         }
@@ -78,6 +81,7 @@ export async function loadSubscriptionData() {
     const data = await response.json();
     userProfile.set(data.profile || null);
     userSubscription.set(data.subscription || null);
+    userEntitlements.set(data.entitlements || null);
   } catch (error) {
     console.error('Error loading subscription data:', error);
   }